@@ -0,0 +1,135 @@
+// Command logcomponent generates the registration that tags a package's log
+// entries with a component field.
+//
+// Add a directive to any file of the package, and a go:generate line to run
+// the command:
+//
+//	//logging:component auth
+//	//go:generate go run github.com/ocrosby/go-logging/cmd/logcomponent
+//	package auth
+//
+// go generate then writes logging_component_gen.go with an init function
+// calling logging.RegisterComponent, so every entry logged from the package
+// carries component=auth without a WithField at each call site.
+//
+// Usage:
+//
+//	logcomponent [-dir .] [-o logging_component_gen.go] [-pkgpath import/path]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// directive marks the component of a package.
+const directive = "//logging:component"
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "logcomponent:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("logcomponent", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "package directory")
+	output := flags.String("o", "logging_component_gen.go", "output file name, relative to -dir")
+	pkgPath := flags.String("pkgpath", "", "import path of the package (default: from go list)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	pkgName, component, err := findComponent(*dir, *output)
+	if err != nil {
+		return err
+	}
+	if *pkgPath == "" {
+		if *pkgPath, err = importPath(*dir); err != nil {
+			return err
+		}
+	}
+
+	src, err := generate(pkgName, *pkgPath, component)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(*dir, *output), src, 0644)
+}
+
+// findComponent returns the package name and the component named by the
+// directive in the Go files of dir, skipping tests and the output file.
+func findComponent(dir, output string) (pkgName, component string, err error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", "", err
+	}
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		base := filepath.Base(file)
+		if strings.HasSuffix(base, "_test.go") || base == filepath.Base(output) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		pkgName = f.Name.Name
+
+		for _, group := range f.Comments {
+			for _, c := range group.List {
+				rest, ok := strings.CutPrefix(c.Text, directive)
+				if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+					continue
+				}
+				fields := strings.Fields(rest)
+				if len(fields) != 1 {
+					return "", "", fmt.Errorf("%s: expected %s <name>", fset.Position(c.Pos()), directive)
+				}
+				if component != "" && component != fields[0] {
+					return "", "", fmt.Errorf("%s: conflicting components %q and %q", fset.Position(c.Pos()), component, fields[0])
+				}
+				component = fields[0]
+			}
+		}
+	}
+
+	if component == "" {
+		return "", "", fmt.Errorf("no %s directive in %s", directive, dir)
+	}
+	return pkgName, component, nil
+}
+
+// importPath asks the go command for the import path of the package in dir.
+func importPath(dir string) (string, error) {
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}}", ".")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine import path: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// generate returns the formatted source registering component for pkgPath.
+func generate(pkgName, pkgPath, component string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by logcomponent; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	fmt.Fprintf(&b, "import \"github.com/ocrosby/go-logging/pkg/logging\"\n\n")
+	fmt.Fprintf(&b, "func init() {\n")
+	fmt.Fprintf(&b, "\tlogging.RegisterComponent(%s, %s)\n", strconv.Quote(pkgPath), strconv.Quote(component))
+	fmt.Fprintf(&b, "}\n")
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "doc.go", "//logging:component auth\n\n// Package auth signs users in.\npackage auth\n")
+	writeFile(t, dir, "auth.go", "package auth\n\nfunc Login() {}\n")
+	writeFile(t, dir, "auth_test.go", "//logging:component ignored\npackage auth\n")
+
+	if err := run([]string{"-dir", dir, "-pkgpath", "example.com/app/auth"}); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "logging_component_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Code generated by logcomponent; DO NOT EDIT.",
+		"package auth",
+		`logging.RegisterComponent("example.com/app/auth", "auth")`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("expected %q in:\n%s", want, src)
+		}
+	}
+
+	// Running again ignores the generated file.
+	if err := run([]string{"-dir", dir, "-pkgpath", "example.com/app/auth"}); err != nil {
+		t.Errorf("second run failed: %v", err)
+	}
+}
+
+func TestFindComponent_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"missing", map[string]string{"a.go": "package a\n"}},
+		{"no name", map[string]string{"a.go": "//logging:component\npackage a\n"}},
+		{"conflict", map[string]string{
+			"a.go": "//logging:component auth\npackage a\n",
+			"b.go": "//logging:component billing\npackage a\n",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeFile(t, dir, name, content)
+			}
+			if _, _, err := findComponent(dir, "logging_component_gen.go"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestFindComponent_IgnoresSimilarComments(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.go", "//logging:componentry x\n//logging:component auth // the sign-in flow\npackage a\n")
+	if _, _, err := findComponent(dir, "logging_component_gen.go"); err == nil {
+		t.Error("expected trailing text after the name to be rejected")
+	}
+
+	writeFile(t, dir, "a.go", "//logging:componentry x\n//logging:component auth\npackage a\n")
+	if pkg, component, err := findComponent(dir, "logging_component_gen.go"); err != nil || pkg != "a" || component != "auth" {
+		t.Errorf("got %q, %q, %v", pkg, component, err)
+	}
+}
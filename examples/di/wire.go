@@ -5,9 +5,10 @@ package main
 import (
 	"github.com/google/wire"
 	"github.com/ocrosby/go-logging/pkg/logging"
+	"github.com/ocrosby/go-logging/pkg/logging/di"
 )
 
 func InitializeLogger() logging.Logger {
-	wire.Build(logging.DefaultSet)
+	wire.Build(di.WireSet)
 	return nil
 }
@@ -13,8 +13,8 @@ import (
 // Injectors from wire.go:
 
 func InitializeLogger() logging.Logger {
-	config := logging.ProvideConfig()
-	redactorChainInterface := logging.ProvideRedactorChain(config)
-	logger := logging.ProvideLogger(config, redactorChainInterface)
+	loggerConfig := logging.ProvideLoggerConfig()
+	redactorChainInterface := logging.ProvideRedactorChainFromLoggerConfig(loggerConfig)
+	logger := logging.ProvideLoggerFromConfig(loggerConfig, redactorChainInterface)
 	return logger
 }
@@ -7,4 +7,4 @@ require (
 	go.uber.org/mock v0.6.0
 )
 
-require gopkg.in/yaml.v3 v3.0.1 // indirect
+require gopkg.in/yaml.v3 v3.0.1
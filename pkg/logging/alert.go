@@ -0,0 +1,333 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AlertProvider selects the alerting API an AlertOutput sends to.
+type AlertProvider string
+
+const (
+	// PagerDutyProvider sends PagerDuty Events API v2 trigger events.
+	PagerDutyProvider AlertProvider = "pagerduty"
+	// OpsgenieProvider creates Opsgenie alerts.
+	OpsgenieProvider AlertProvider = "opsgenie"
+)
+
+// Default endpoints of the alerting providers.
+const (
+	DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+	DefaultOpsgenieURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+// opsgenieMaxMessage is the longest alert message Opsgenie accepts.
+const opsgenieMaxMessage = 130
+
+// AlertConfig configures an AlertOutput.
+type AlertConfig struct {
+	// Provider is PagerDutyProvider or OpsgenieProvider.
+	Provider AlertProvider
+	// Key is the PagerDuty integration (routing) key or the Opsgenie API
+	// key.
+	Key string
+	// URL overrides the provider's endpoint, e.g. for Opsgenie's EU
+	// instance.
+	URL string
+	// DedupField names the field, or trace, request or correlation ID,
+	// whose value is the alert's dedup key, so repeats update one incident.
+	// Entries without it, or all entries when it is empty, use the message
+	// with numbers, IDs and quoted values replaced by placeholders.
+	DedupField string
+	// Source identifies the alerting system. Defaults to the machine's
+	// hostname.
+	Source string
+	// RateLimit is the most alerts sent per RateWindow; the rest are
+	// suppressed and counted. Defaults to 10.
+	RateLimit int
+	// RateWindow defaults to one minute.
+	RateWindow time.Duration
+	// QueueSize bounds the alerts waiting to be sent. Defaults to 100.
+	QueueSize int
+	// Retry controls retries of alerts that fail with network errors, 429
+	// or 5xx responses.
+	Retry RetryConfig
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the number of alerts that could not be sent.
+	OnError func(alerts int, err error)
+	// Keys are the field keys of entries passed to Write, as set by
+	// WithFieldKeys. Empty keys are the JSONFormatter defaults.
+	Keys FieldKeys
+}
+
+// AlertOutput pages on CRITICAL entries through PagerDuty or Opsgenie and
+// ignores the rest. Each alert carries a dedup key, from DedupField or the
+// message, so the provider folds repeats into one incident, and at most
+// RateLimit alerts are sent per RateWindow so a storm of distinct failures
+// does not page hundreds of times.
+//
+// Example:
+//
+//	alerts, err := logging.NewAlertOutput(logging.AlertConfig{
+//		Provider:   logging.PagerDutyProvider,
+//		Key:        os.Getenv("PAGERDUTY_ROUTING_KEY"),
+//		DedupField: "component",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	output := logging.NewMultiOutput(logging.NewWriterOutput(os.Stdout), alerts)
+type AlertOutput struct {
+	config AlertConfig
+	now    func() time.Time
+	worker *AsyncWorker[json.RawMessage]
+
+	mu         sync.Mutex
+	limiter    windowLimiter
+	suppressed int64
+}
+
+// NewAlertOutput creates an AlertOutput.
+func NewAlertOutput(config AlertConfig) (*AlertOutput, error) {
+	switch config.Provider {
+	case PagerDutyProvider:
+		if config.URL == "" {
+			config.URL = DefaultPagerDutyURL
+		}
+	case OpsgenieProvider:
+		if config.URL == "" {
+			config.URL = DefaultOpsgenieURL
+		}
+	default:
+		return nil, fmt.Errorf("unknown alert provider %q", config.Provider)
+	}
+	if config.Key == "" {
+		return nil, fmt.Errorf("%s alert output requires a key", config.Provider)
+	}
+	if config.Source == "" {
+		config.Source, _ = os.Hostname()
+	}
+	if config.RateLimit <= 0 {
+		config.RateLimit = 10
+	}
+	if config.RateWindow <= 0 {
+		config.RateWindow = time.Minute
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 100
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	ao := &AlertOutput{
+		config:  config,
+		now:     time.Now,
+		limiter: windowLimiter{limit: config.RateLimit, window: config.RateWindow},
+	}
+	ao.worker = NewAsyncWorker(AsyncWorkerConfig[json.RawMessage]{
+		QueueSize: config.QueueSize,
+		Processor: func(alert json.RawMessage) error {
+			if err := ao.send(alert); err != nil {
+				ao.reportError(1, err)
+			}
+			return nil
+		},
+	})
+	return ao, nil
+}
+
+// Write parses data as JSON entries, written with the field keys in Keys,
+// and writes each with WriteEntry. Data
+// that is not JSON is rejected with an error, as its level is unknown.
+func (ao *AlertOutput) Write(data []byte) error {
+	return writeParsedEntries(data, ao.config.Keys, ao.WriteEntry)
+}
+
+// WriteEntry queues an alert for a CRITICAL entry unless the rate limit has
+// been reached.
+func (ao *AlertOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	if entry.Level < CriticalLevel {
+		return nil
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = ao.now()
+	}
+	if !ao.allow() {
+		return nil
+	}
+
+	var alert interface{}
+	if ao.config.Provider == PagerDutyProvider {
+		alert = ao.pagerDutyEvent(entry)
+	} else {
+		alert = ao.opsgenieAlert(entry)
+	}
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	if ao.worker.IsClosed() {
+		return fmt.Errorf("output is closed")
+	}
+	if !ao.worker.Submit(data) {
+		ao.reportError(1, ErrQueueFull)
+		return ErrQueueFull
+	}
+	return nil
+}
+
+// allow reports whether another alert fits in the current rate window,
+// counting it as suppressed if not.
+func (ao *AlertOutput) allow() bool {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+
+	if !ao.limiter.allow(ao.now()) {
+		ao.suppressed++
+		return false
+	}
+	return true
+}
+
+// Suppressed returns the number of alerts dropped by the rate limit.
+func (ao *AlertOutput) Suppressed() int64 {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+	return ao.suppressed
+}
+
+// dedupKey returns the dedup key of entry.
+func (ao *AlertOutput) dedupKey(entry LogEntry) string {
+	if ao.config.DedupField != "" {
+		if value, ok := entryFieldString(entry, ao.config.DedupField); ok && value != "" {
+			return value
+		}
+	}
+	return messageTemplate(entry.Message)
+}
+
+// alertDetails returns the fields of entry, with its trace, request and
+// correlation IDs.
+func alertDetails(entry LogEntry) map[string]interface{} {
+	details := make(map[string]interface{}, len(entry.Fields)+3)
+	for k, v := range entry.Fields {
+		details[k] = v
+	}
+	for _, name := range []string{"trace_id", "request_id", "correlation_id"} {
+		if _, ok := details[name]; ok {
+			continue
+		}
+		if value, ok := entryFieldString(entry, name); ok {
+			details[name] = value
+		}
+	}
+	return details
+}
+
+// pagerDutyEvent is a PagerDuty Events API v2 trigger event.
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp"`
+	Component     string                 `json:"component,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+func (ao *AlertOutput) pagerDutyEvent(entry LogEntry) pagerDutyEvent {
+	component, _ := entryFieldString(entry, ComponentField)
+	return pagerDutyEvent{
+		RoutingKey:  ao.config.Key,
+		EventAction: "trigger",
+		DedupKey:    ao.dedupKey(entry),
+		Payload: pagerDutyPayload{
+			Summary:       entry.Message,
+			Source:        ao.config.Source,
+			Severity:      "critical",
+			Timestamp:     entry.Timestamp.UTC().Format(time.RFC3339Nano),
+			Component:     component,
+			CustomDetails: alertDetails(entry),
+		},
+	}
+}
+
+// opsgenieAlert is an Opsgenie create-alert request.
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+func (ao *AlertOutput) opsgenieAlert(entry LogEntry) opsgenieAlert {
+	alert := opsgenieAlert{
+		Message:  entry.Message,
+		Alias:    ao.dedupKey(entry),
+		Source:   ao.config.Source,
+		Priority: "P1",
+	}
+	if len(alert.Message) > opsgenieMaxMessage {
+		alert.Message = strings.ToValidUTF8(alert.Message[:opsgenieMaxMessage], "")
+		alert.Description = entry.Message
+	}
+	if details := alertDetails(entry); len(details) > 0 {
+		alert.Details = make(map[string]string, len(details))
+		for k, v := range details {
+			alert.Details[k] = fmt.Sprint(v)
+		}
+	}
+	return alert
+}
+
+// send posts alert to the provider.
+func (ao *AlertOutput) send(alert json.RawMessage) error {
+	err := retry(context.Background(), ao.config.Retry, func() error {
+		req, err := http.NewRequest(http.MethodPost, ao.config.URL, bytes.NewReader(alert))
+		if err != nil {
+			return permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if ao.config.Provider == OpsgenieProvider {
+			req.Header.Set("Authorization", "GenieKey "+ao.config.Key)
+		}
+
+		resp, err := ao.config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		return checkHTTPResponse(resp)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send alert to %s: %w", ao.config.Provider, err)
+	}
+	return nil
+}
+
+func (ao *AlertOutput) reportError(alerts int, err error) {
+	if ao.config.OnError != nil {
+		ao.config.OnError(alerts, err)
+	}
+}
+
+// Close sends queued alerts.
+func (ao *AlertOutput) Close() error {
+	return ao.worker.Stop()
+}
@@ -0,0 +1,209 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewAlertOutput_Validation(t *testing.T) {
+	if _, err := NewAlertOutput(AlertConfig{Provider: "pager", Key: "k"}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+	if _, err := NewAlertOutput(AlertConfig{Provider: OpsgenieProvider}); err == nil {
+		t.Error("expected error without key")
+	}
+}
+
+// alertServer records the JSON bodies and headers posted to it.
+type alertServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	bodies  []map[string]interface{}
+	headers []http.Header
+}
+
+func newAlertServer(t *testing.T) *alertServer {
+	s := &alertServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.bodies = append(s.bodies, body)
+		s.headers = append(s.headers, r.Header)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestAlertOutput_PagerDuty(t *testing.T) {
+	server := newAlertServer(t)
+	output, err := NewAlertOutput(AlertConfig{
+		Provider:   PagerDutyProvider,
+		Key:        "routing-key",
+		URL:        server.URL,
+		DedupField: "component",
+		Source:     "web-1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create alert output: %v", err)
+	}
+
+	_ = output.Write([]byte("no level\n"))
+	_ = output.WriteEntry(LogEntry{Level: ErrorLevel, Message: "not critical"}, nil)
+	_ = output.WriteEntry(LogEntry{
+		Level:   CriticalLevel,
+		Message: "database unreachable",
+		Fields:  map[string]interface{}{"component": "orders", "attempts": 3},
+	}, nil)
+	_ = output.WriteEntry(LogEntry{Level: CriticalLevel, Message: "disk 97% full"}, nil)
+	if err := output.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.bodies) != 2 {
+		t.Fatalf("expected 2 alerts, got %d", len(server.bodies))
+	}
+	event := server.bodies[0]
+	if event["routing_key"] != "routing-key" || event["event_action"] != "trigger" || event["dedup_key"] != "orders" {
+		t.Errorf("unexpected event: %v", event)
+	}
+	payload := event["payload"].(map[string]interface{})
+	if payload["summary"] != "database unreachable" || payload["severity"] != "critical" ||
+		payload["source"] != "web-1" || payload["component"] != "orders" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+	if details := payload["custom_details"].(map[string]interface{}); details["attempts"] != float64(3) {
+		t.Errorf("unexpected details: %v", details)
+	}
+	if key := server.bodies[1]["dedup_key"]; key != "disk <num>% full" {
+		t.Errorf("expected dedup key from message, got %v", key)
+	}
+}
+
+func TestAlertOutput_BehindBufferedOutput(t *testing.T) {
+	server := newAlertServer(t)
+	output, err := NewAlertOutput(AlertConfig{Provider: PagerDutyProvider, Key: "routing-key", URL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create alert output: %v", err)
+	}
+	buffered := NewBufferedOutput(output, 4096, 0)
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithCustomOutput(buffered).Build())
+
+	logger.Info("routine")
+	logger.Critical("database unreachable")
+	if err := buffered.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.bodies) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(server.bodies))
+	}
+	if summary := server.bodies[0]["payload"].(map[string]interface{})["summary"]; summary != "database unreachable" {
+		t.Errorf("unexpected summary %v", summary)
+	}
+	if err := output.Write([]byte("no level\n")); err == nil {
+		t.Error("expected an error for data that is not JSON")
+	}
+}
+
+func TestAlertOutput_BehindBufferedOutputWithFieldKeys(t *testing.T) {
+	server := newAlertServer(t)
+	keys := FieldKeys{Level: "severity", Message: "msg"}
+	output, err := NewAlertOutput(AlertConfig{Provider: PagerDutyProvider, Key: "routing-key", URL: server.URL, Keys: keys})
+	if err != nil {
+		t.Fatalf("failed to create alert output: %v", err)
+	}
+	buffered := NewBufferedOutput(output, 4096, 0)
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithFieldKeys(keys).WithCustomOutput(buffered).Build())
+
+	logger.Critical("database unreachable")
+	if err := buffered.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.bodies) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(server.bodies))
+	}
+	if summary := server.bodies[0]["payload"].(map[string]interface{})["summary"]; summary != "database unreachable" {
+		t.Errorf("unexpected summary %v", summary)
+	}
+}
+
+func TestAlertOutput_Opsgenie(t *testing.T) {
+	server := newAlertServer(t)
+	output, err := NewAlertOutput(AlertConfig{Provider: OpsgenieProvider, Key: "genie", URL: server.URL})
+	if err != nil {
+		t.Fatalf("failed to create alert output: %v", err)
+	}
+
+	long := strings.Repeat("x", 200)
+	_ = output.WriteEntry(LogEntry{
+		Level:   CriticalLevel,
+		Message: long,
+		Fields:  map[string]interface{}{"attempts": 3},
+	}, nil)
+	_ = output.Close()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.bodies) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(server.bodies))
+	}
+	if auth := server.headers[0].Get("Authorization"); auth != "GenieKey genie" {
+		t.Errorf("unexpected authorization %q", auth)
+	}
+	alert := server.bodies[0]
+	if len(alert["message"].(string)) != opsgenieMaxMessage || alert["description"] != long || alert["priority"] != "P1" {
+		t.Errorf("unexpected alert: %v", alert)
+	}
+	if details := alert["details"].(map[string]interface{}); details["attempts"] != "3" {
+		t.Errorf("unexpected details: %v", details)
+	}
+}
+
+func TestAlertOutput_RateLimit(t *testing.T) {
+	server := newAlertServer(t)
+	output, err := NewAlertOutput(AlertConfig{
+		Provider:   PagerDutyProvider,
+		Key:        "k",
+		URL:        server.URL,
+		RateLimit:  2,
+		RateWindow: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("failed to create alert output: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	output.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		_ = output.WriteEntry(LogEntry{Level: CriticalLevel, Message: "storm"}, nil)
+	}
+	now = now.Add(time.Minute)
+	_ = output.WriteEntry(LogEntry{Level: CriticalLevel, Message: "next window"}, nil)
+	_ = output.Close()
+
+	if got := output.Suppressed(); got != 3 {
+		t.Errorf("expected 3 suppressed alerts, got %d", got)
+	}
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.bodies) != 3 {
+		t.Errorf("expected 3 alerts, got %d", len(server.bodies))
+	}
+}
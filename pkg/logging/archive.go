@@ -0,0 +1,326 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultArchiveKeyPattern is the default ArchiveConfig.KeyPattern.
+const DefaultArchiveKeyPattern = "logs/{year}/{month}/{day}/{host}-{segment}.log.gz"
+
+// ArchiveConfig configures an ArchiveOutput.
+type ArchiveConfig struct {
+	// Dir holds the segment files waiting to be uploaded. It is created if
+	// needed.
+	Dir string
+	// Uploader stores the compressed segments, e.g. NewS3Uploader or
+	// NewGCSUploader.
+	Uploader ObjectUploader
+	// KeyPattern names uploaded objects. {year}, {month}, {day}, {hour} and
+	// {timestamp} come from the segment's start time in UTC, {host} is the
+	// hostname and {segment} is a unique segment ID. Defaults to
+	// DefaultArchiveKeyPattern.
+	KeyPattern string
+	// SegmentSize is the size at which a segment is sealed for upload.
+	// Defaults to 64 MiB.
+	SegmentSize int64
+	// Interval is how often the current segment is sealed and pending
+	// segments are uploaded. Defaults to 5m.
+	Interval time.Duration
+	// Retry controls retries of failed uploads.
+	Retry RetryConfig
+	// UploadTimeout bounds each segment upload. Defaults to 5m.
+	UploadTimeout time.Duration
+	// OnError is called with the path of a segment that failed to upload.
+	// The segment stays in Dir and is retried later, including after a
+	// restart.
+	OnError func(segment string, err error)
+}
+
+// ArchiveOutput spools entries to local segment files and uploads them,
+// gzip-compressed, to object storage such as S3 or GCS. It is meant for
+// cheap long-term archival alongside live outputs: segments are sealed when
+// they reach SegmentSize or every Interval, and stay on disk until uploaded,
+// so they survive outages and restarts.
+//
+// Example:
+//
+//	uploader, err := logging.NewS3Uploader(logging.S3Config{Bucket: "acme-log-archive"})
+//	...
+//	archive, err := logging.NewArchiveOutput(logging.ArchiveConfig{
+//		Dir:      "/var/spool/myapp/archive",
+//		Uploader: uploader,
+//	})
+//	output := logging.NewMultiOutput(liveOutput, archive)
+type ArchiveOutput struct {
+	config ArchiveConfig
+	host   string
+
+	mu   sync.Mutex
+	file *os.File
+	id   int64
+	size int64
+
+	uploadMu sync.Mutex
+	uploadCh chan struct{}
+	done     chan struct{}
+	wg       sync.WaitGroup
+	closed   bool
+}
+
+// NewArchiveOutput creates an ArchiveOutput. Segments left in Dir by a
+// previous run are uploaded.
+func NewArchiveOutput(config ArchiveConfig) (*ArchiveOutput, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("archive output requires a directory")
+	}
+	if config.Uploader == nil {
+		return nil, fmt.Errorf("archive output requires an uploader")
+	}
+	if config.KeyPattern == "" {
+		config.KeyPattern = DefaultArchiveKeyPattern
+	}
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = 64 << 20
+	}
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.UploadTimeout <= 0 {
+		config.UploadTimeout = 5 * time.Minute
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "unknown"
+	}
+	ao := &ArchiveOutput{
+		config:   config,
+		host:     host,
+		uploadCh: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+
+	// Seal segments a previous run was still writing.
+	active, err := filepath.Glob(filepath.Join(config.Dir, "active-*.log"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive segments: %w", err)
+	}
+	for _, path := range active {
+		sealed := filepath.Join(config.Dir, "segment-"+strings.TrimPrefix(filepath.Base(path), "active-"))
+		if err := os.Rename(path, sealed); err != nil {
+			return nil, fmt.Errorf("failed to seal archive segment: %w", err)
+		}
+	}
+
+	ao.wg.Add(1)
+	go ao.run()
+	ao.triggerUpload()
+	return ao, nil
+}
+
+// Write appends data to the current segment.
+func (ao *ArchiveOutput) Write(data []byte) error {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+
+	if ao.closed {
+		return fmt.Errorf("archive output is closed")
+	}
+	if ao.file == nil {
+		id := time.Now().UnixNano()
+		if id <= ao.id {
+			id = ao.id + 1
+		}
+		file, err := os.OpenFile(ao.segmentPath("active", id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open archive segment: %w", err)
+		}
+		ao.file, ao.id, ao.size = file, id, 0
+	}
+
+	n, err := ao.file.Write(data)
+	ao.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write archive segment: %w", err)
+	}
+	if ao.size >= ao.config.SegmentSize {
+		if err := ao.seal(); err != nil {
+			return err
+		}
+		ao.triggerUpload()
+	}
+	return nil
+}
+
+func (ao *ArchiveOutput) segmentPath(prefix string, id int64) string {
+	return filepath.Join(ao.config.Dir, fmt.Sprintf("%s-%020d.log", prefix, id))
+}
+
+// seal closes the current segment and marks it ready for upload. It must
+// be called with mu held.
+func (ao *ArchiveOutput) seal() error {
+	if ao.file == nil {
+		return nil
+	}
+	closeErr := ao.file.Close()
+	ao.file = nil
+	if err := os.Rename(ao.segmentPath("active", ao.id), ao.segmentPath("segment", ao.id)); err != nil {
+		return fmt.Errorf("failed to seal archive segment: %w", err)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close archive segment: %w", closeErr)
+	}
+	return nil
+}
+
+func (ao *ArchiveOutput) triggerUpload() {
+	select {
+	case ao.uploadCh <- struct{}{}:
+	default:
+	}
+}
+
+// run seals the current segment every Interval and uploads sealed segments.
+func (ao *ArchiveOutput) run() {
+	defer ao.wg.Done()
+	ticker := time.NewTicker(ao.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ao.done:
+			return
+		case <-ticker.C:
+			ao.mu.Lock()
+			err := ao.seal()
+			ao.mu.Unlock()
+			if err != nil {
+				ao.reportError(ao.config.Dir, err)
+			}
+			ao.uploadPending()
+		case <-ao.uploadCh:
+			ao.uploadPending()
+		}
+	}
+}
+
+// uploadPending uploads sealed segments oldest first, stopping at the first
+// failure so order is kept.
+func (ao *ArchiveOutput) uploadPending() {
+	ao.uploadMu.Lock()
+	defer ao.uploadMu.Unlock()
+
+	segments, err := filepath.Glob(filepath.Join(ao.config.Dir, "segment-*.log"))
+	if err != nil {
+		ao.reportError(ao.config.Dir, err)
+		return
+	}
+	sort.Strings(segments)
+	for _, path := range segments {
+		if err := ao.upload(path); err != nil {
+			ao.reportError(path, err)
+			return
+		}
+	}
+}
+
+// upload compresses and uploads one segment, then removes it.
+func (ao *ArchiveOutput) upload(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read archive segment: %w", err)
+	}
+	if len(data) > 0 {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write(data)
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to compress archive segment: %w", err)
+		}
+
+		id, _ := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "segment-"), ".log"), 10, 64)
+		key := ao.objectKey(id)
+		ctx, cancel := context.WithTimeout(context.Background(), ao.config.UploadTimeout)
+		err = retry(ctx, ao.config.Retry, func() error {
+			return ao.config.Uploader.Upload(ctx, key, buf.Bytes())
+		})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to upload archive segment to %s: %w", key, err)
+		}
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove uploaded archive segment: %w", err)
+	}
+	return nil
+}
+
+// objectKey renders KeyPattern for the segment with ID id.
+func (ao *ArchiveOutput) objectKey(id int64) string {
+	start := time.Unix(0, id).UTC()
+	return strings.NewReplacer(
+		"{year}", start.Format("2006"),
+		"{month}", start.Format("01"),
+		"{day}", start.Format("02"),
+		"{hour}", start.Format("15"),
+		"{timestamp}", start.Format("20060102T150405Z"),
+		"{host}", ao.host,
+		"{segment}", strconv.FormatInt(id, 10),
+	).Replace(ao.config.KeyPattern)
+}
+
+func (ao *ArchiveOutput) reportError(segment string, err error) {
+	if ao.config.OnError != nil {
+		ao.config.OnError(segment, err)
+	}
+}
+
+// Pending returns the number of sealed segments waiting to be uploaded.
+func (ao *ArchiveOutput) Pending() int {
+	segments, _ := filepath.Glob(filepath.Join(ao.config.Dir, "segment-*.log"))
+	return len(segments)
+}
+
+// Flush seals the current segment and uploads every pending segment.
+func (ao *ArchiveOutput) Flush() error {
+	ao.mu.Lock()
+	err := ao.seal()
+	ao.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	ao.uploadPending()
+	if pending := ao.Pending(); pending > 0 {
+		return fmt.Errorf("%d archive segments failed to upload", pending)
+	}
+	return nil
+}
+
+// Close seals the current segment and makes a final upload attempt,
+// returning an error if segments remain. They stay in Dir for the next run.
+func (ao *ArchiveOutput) Close() error {
+	ao.mu.Lock()
+	if ao.closed {
+		ao.mu.Unlock()
+		return nil
+	}
+	ao.closed = true
+	ao.mu.Unlock()
+
+	close(ao.done)
+	ao.wg.Wait()
+	return ao.Flush()
+}
@@ -1,6 +1,8 @@
 package logging
 
 import (
+	"context"
+	"fmt"
 	"sync"
 )
 
@@ -9,11 +11,18 @@ import (
 type AsyncWorker[T any] struct {
 	queue      chan T
 	done       chan struct{}
+	abort      chan struct{}
+	abortOnce  sync.Once
+	dropped    int
 	wg         sync.WaitGroup
 	closed     bool
 	mu         sync.Mutex
 	processor  func(T) error
 	onShutdown func() error
+
+	batchProcessor func([]T) error
+	maxBatch       int
+	batch          []T
 }
 
 // AsyncWorkerConfig configures an AsyncWorker
@@ -21,6 +30,13 @@ type AsyncWorkerConfig[T any] struct {
 	QueueSize  int
 	Processor  func(T) error
 	OnShutdown func() error // Optional cleanup on shutdown
+
+	// BatchProcessor, when set, is called instead of Processor with every
+	// queued item, up to MaxBatch, so items that accumulated while the
+	// worker was busy can be handled together. The slice is reused.
+	BatchProcessor func([]T) error
+	// MaxBatch defaults to 100.
+	MaxBatch int
 }
 
 // NewAsyncWorker creates a new async worker with the specified configuration
@@ -29,11 +45,18 @@ func NewAsyncWorker[T any](config AsyncWorkerConfig[T]) *AsyncWorker[T] {
 		panic("processor function is required")
 	}
 
+	if config.MaxBatch <= 0 {
+		config.MaxBatch = 100
+	}
+
 	worker := &AsyncWorker[T]{
-		queue:      make(chan T, config.QueueSize),
-		done:       make(chan struct{}),
-		processor:  config.Processor,
-		onShutdown: config.OnShutdown,
+		queue:          make(chan T, config.QueueSize),
+		done:           make(chan struct{}),
+		abort:          make(chan struct{}),
+		processor:      config.Processor,
+		onShutdown:     config.OnShutdown,
+		batchProcessor: config.BatchProcessor,
+		maxBatch:       config.MaxBatch,
 	}
 
 	worker.wg.Add(1)
@@ -79,9 +102,18 @@ func (w *AsyncWorker[T]) run() {
 	defer w.wg.Done()
 
 	for {
+		// Check done first so the shutdown drain, which StopWithTimeout can
+		// cut short, takes over as soon as the worker is stopped.
+		select {
+		case <-w.done:
+			w.drainAndShutdown()
+			return
+		default:
+		}
+
 		select {
 		case item := <-w.queue:
-			_ = w.processor(item)
+			w.process(item)
 		case <-w.done:
 			w.drainAndShutdown()
 			return
@@ -89,17 +121,55 @@ func (w *AsyncWorker[T]) run() {
 	}
 }
 
-// drainAndShutdown drains remaining items and calls shutdown callback
+// process handles item, together with the items queued behind it if there
+// is a BatchProcessor.
+func (w *AsyncWorker[T]) process(item T) {
+	if w.batchProcessor == nil {
+		_ = w.processor(item)
+		return
+	}
+
+	w.batch = append(w.batch[:0], item)
+collect:
+	for len(w.batch) < w.maxBatch {
+		select {
+		case next := <-w.queue:
+			w.batch = append(w.batch, next)
+		default:
+			break collect
+		}
+	}
+	_ = w.batchProcessor(w.batch)
+	clear(w.batch)
+}
+
+// drainAndShutdown drains remaining items and calls shutdown callback.
+// Once abort is closed, the rest of the queue is dropped instead.
 func (w *AsyncWorker[T]) drainAndShutdown() {
-	// Drain remaining items
+	defer func() {
+		if w.onShutdown != nil {
+			_ = w.onShutdown()
+		}
+	}()
+
 	for {
+		select {
+		case <-w.abort:
+			for {
+				select {
+				case <-w.queue:
+					w.dropped++
+				default:
+					return
+				}
+			}
+		default:
+		}
+
 		select {
 		case item := <-w.queue:
-			_ = w.processor(item)
+			w.process(item)
 		default:
-			if w.onShutdown != nil {
-				_ = w.onShutdown()
-			}
 			return
 		}
 	}
@@ -120,6 +190,49 @@ func (w *AsyncWorker[T]) Stop() error {
 	return nil
 }
 
+// StopWithTimeout shuts down the worker like Stop, but stops draining the
+// queue once ctx is done and drops what is left, so shutdown fits within a
+// deadline such as a termination grace period. It returns the number of
+// dropped items, and an error wrapping ctx.Err() if any were dropped. An
+// item being processed when ctx is done is finished first.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if dropped, err := worker.StopWithTimeout(ctx); err != nil {
+//		fmt.Fprintf(os.Stderr, "dropped %d queued entries on shutdown\n", dropped)
+//	}
+func (w *AsyncWorker[T]) StopWithTimeout(ctx context.Context) (int, error) {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.done)
+
+	stopped := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		w.abortOnce.Do(func() { close(w.abort) })
+		<-stopped
+	}
+
+	if w.dropped > 0 {
+		return w.dropped, fmt.Errorf("dropped %d queued items: %w", w.dropped, ctx.Err())
+	}
+	return 0, nil
+}
+
 // IsClosed returns whether the worker is closed
 func (w *AsyncWorker[T]) IsClosed() bool {
 	w.mu.Lock()
@@ -1,6 +1,8 @@
 package logging
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -288,3 +290,101 @@ func TestAsyncWorker_DrainOnShutdown(t *testing.T) {
 		t.Errorf("expected 5 items processed during drain, got %d", processedCount)
 	}
 }
+
+func TestAsyncWorker_StopWithTimeout(t *testing.T) {
+	gate := make(chan struct{})
+	var processed atomic.Int32
+	var shutdown atomic.Bool
+	worker := NewAsyncWorker(AsyncWorkerConfig[int]{
+		QueueSize: 10,
+		Processor: func(int) error {
+			<-gate
+			processed.Add(1)
+			return nil
+		},
+		OnShutdown: func() error {
+			shutdown.Store(true)
+			return nil
+		},
+	})
+	for i := 0; i < 10; i++ {
+		worker.Submit(i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	go func() {
+		// Hold the item in progress until the worker has been told to
+		// drop the rest.
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		close(gate)
+	}()
+
+	dropped, err := worker.StopWithTimeout(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline error, got %v", err)
+	}
+	if dropped == 0 || int(processed.Load())+dropped != 10 {
+		t.Errorf("expected the backlog split between processed and dropped, got %d processed, %d dropped", processed.Load(), dropped)
+	}
+	if !shutdown.Load() {
+		t.Error("expected OnShutdown to run")
+	}
+	if dropped, err := worker.StopWithTimeout(ctx); dropped != 0 || err != nil {
+		t.Errorf("expected a second stop to do nothing, got %d, %v", dropped, err)
+	}
+}
+
+func TestAsyncWorker_StopWithTimeout_Drains(t *testing.T) {
+	var processed atomic.Int32
+	worker := NewAsyncWorker(AsyncWorkerConfig[int]{
+		QueueSize: 100,
+		Processor: func(int) error {
+			processed.Add(1)
+			return nil
+		},
+	})
+	for i := 0; i < 100; i++ {
+		worker.Submit(i)
+	}
+
+	dropped, err := worker.StopWithTimeout(context.Background())
+	if dropped != 0 || err != nil || processed.Load() != 100 {
+		t.Errorf("expected a full drain, got %d processed, %d dropped, %v", processed.Load(), dropped, err)
+	}
+}
+
+func TestAsyncOutput_StopWithTimeout(t *testing.T) {
+	gate := make(chan struct{})
+	output := NewAsyncOutput(&blockingOutput{gate: gate}, 10)
+	for i := 0; i < 5; i++ {
+		_ = output.Write([]byte("entry\n"))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(gate)
+	}()
+	dropped, err := output.StopWithTimeout(ctx)
+	if dropped == 0 || !errors.Is(err, context.Canceled) {
+		t.Errorf("expected dropped entries and the context error, got %d, %v", dropped, err)
+	}
+	if err := output.Write([]byte("late\n")); err == nil {
+		t.Error("expected writes to fail after stopping")
+	}
+}
+
+// blockingOutput blocks each write until gate is closed.
+type blockingOutput struct {
+	gate chan struct{}
+}
+
+func (bo *blockingOutput) Write([]byte) error {
+	<-bo.gate
+	return nil
+}
+
+func (bo *blockingOutput) Close() error { return nil }
@@ -0,0 +1,208 @@
+package logging
+
+import (
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// AutoQuietEnv overrides auto-quiet mode: "off" (or "false", "0") never
+// applies the quiet settings and "on" (or "true", "1") always does,
+// whatever the container's limits.
+const AutoQuietEnv = "LOG_AUTO_QUIET"
+
+// cgroupRoot is where the container's cgroup v1 or v2 hierarchy is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupUnlimited is the smallest cgroup v1 memory limit treated as no
+// limit; the kernel reports "unlimited" as a page-aligned 2^63-1.
+const cgroupUnlimited = 1 << 62
+
+// ContainerLimits are the memory and CPU limits of the cgroup the process
+// runs in. Zero means no limit, or that none could be read.
+type ContainerLimits struct {
+	MemoryBytes int64
+	CPUs        float64
+}
+
+// DetectContainerLimits reads the process's cgroup v2, or else v1, memory
+// and CPU limits. Outside a limited container, or off Linux, it returns
+// zero limits.
+func DetectContainerLimits() ContainerLimits {
+	return readContainerLimits(os.DirFS(cgroupRoot))
+}
+
+// readContainerLimits reads the limits from a cgroup hierarchy rooted at
+// fsys.
+func readContainerLimits(fsys fs.FS) ContainerLimits {
+	var limits ContainerLimits
+
+	// cgroup v2: memory.max is "max" or bytes, cpu.max is "$quota $period"
+	// with a quota of "max" when unlimited.
+	if value, ok := readCgroupFile(fsys, "memory.max"); ok {
+		limits.MemoryBytes = parseCgroupInt(value)
+	} else if value, ok := readCgroupFile(fsys, "memory/memory.limit_in_bytes"); ok {
+		limits.MemoryBytes = parseCgroupInt(value)
+	}
+	if limits.MemoryBytes >= cgroupUnlimited {
+		limits.MemoryBytes = 0
+	}
+
+	if value, ok := readCgroupFile(fsys, "cpu.max"); ok {
+		if fields := strings.Fields(value); len(fields) == 2 {
+			limits.CPUs = cgroupCPUs(parseCgroupInt(fields[0]), parseCgroupInt(fields[1]))
+		}
+	} else if quota, ok := readCgroupFile(fsys, "cpu/cpu.cfs_quota_us"); ok {
+		period, _ := readCgroupFile(fsys, "cpu/cpu.cfs_period_us")
+		limits.CPUs = cgroupCPUs(parseCgroupInt(quota), parseCgroupInt(period))
+	}
+	return limits
+}
+
+func readCgroupFile(fsys fs.FS, name string) (string, bool) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// parseCgroupInt parses a cgroup value, returning 0 for "max", -1 and
+// anything else that is not a positive number.
+func parseCgroupInt(value string) int64 {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+func cgroupCPUs(quota, period int64) float64 {
+	if quota <= 0 || period <= 0 {
+		return 0
+	}
+	return float64(quota) / float64(period)
+}
+
+// AutoQuietConfig configures auto-quiet mode. Zero fields take their
+// defaults.
+type AutoQuietConfig struct {
+	// MaxMemory is the memory limit, in bytes, at or below which the
+	// container counts as severely constrained. Defaults to 128 MiB.
+	MaxMemory int64
+	// MaxCPUs is the CPU limit at or below which the container counts as
+	// severely constrained. Defaults to 0.25.
+	MaxCPUs float64
+	// Level is the minimum level applied when constrained; a higher
+	// configured level is kept. Defaults to WarnLevel.
+	Level Level
+	// InfoSampleRate keeps one in every InfoSampleRate INFO entries when
+	// constrained, e.g. after SetLevel(InfoLevel) during an incident.
+	// Entries with a trace ID are kept or dropped with their whole trace.
+	// Defaults to 10; 1 keeps them all.
+	InfoSampleRate int
+
+	// limits replaces DetectContainerLimits in tests.
+	limits func() ContainerLimits
+}
+
+// constrained reports whether limits are severe enough to be quiet.
+func (c AutoQuietConfig) constrained(limits ContainerLimits) bool {
+	return (limits.MemoryBytes > 0 && limits.MemoryBytes <= c.MaxMemory) ||
+		(limits.CPUs > 0 && limits.CPUs <= c.MaxCPUs)
+}
+
+func (c AutoQuietConfig) withDefaults() AutoQuietConfig {
+	if c.MaxMemory <= 0 {
+		c.MaxMemory = 128 << 20
+	}
+	if c.MaxCPUs <= 0 {
+		c.MaxCPUs = 0.25
+	}
+	if c.Level == TraceLevel {
+		c.Level = WarnLevel
+	}
+	if c.InfoSampleRate <= 0 {
+		c.InfoSampleRate = 10
+	}
+	if c.limits == nil {
+		c.limits = DetectContainerLimits
+	}
+	return c
+}
+
+// WithAutoQuiet makes loggers quieter in severely constrained containers,
+// such as tiny sidecars, so logging cannot drive them out of memory or CPU:
+// when the cgroup memory or CPU limit is at or below the configured
+// thresholds, the level is raised to WarnLevel and INFO entries are
+// sampled. LOG_AUTO_QUIET=off disables it and LOG_AUTO_QUIET=on forces it.
+func (b *CoreConfigBuilder) WithAutoQuiet(config AutoQuietConfig) *CoreConfigBuilder {
+	quiet := config.withDefaults()
+	b.config.AutoQuiet = &quiet
+	return b
+}
+
+// WithAutoQuiet makes loggers quieter in severely constrained containers.
+//
+// Example:
+//
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithAutoQuiet(logging.AutoQuietConfig{MaxMemory: 64 << 20}).
+//		Build())
+func (b *LoggerConfigBuilder) WithAutoQuiet(config AutoQuietConfig) *LoggerConfigBuilder {
+	quiet := config.withDefaults()
+	b.config.Core.AutoQuiet = &quiet
+	return b
+}
+
+// resolveAutoQuiet returns config with the quiet level and INFO sampling
+// applied if auto-quiet mode is on and the container is constrained.
+func resolveAutoQuiet(config *LoggerConfig) *LoggerConfig {
+	quiet := config.Core.AutoQuiet
+	if quiet == nil {
+		return config
+	}
+	switch strings.ToLower(os.Getenv(AutoQuietEnv)) {
+	case "off", "false", "0":
+		return config
+	case "on", "true", "1":
+	default:
+		if !quiet.constrained(quiet.limits()) {
+			return config
+		}
+	}
+
+	resolved := *config
+	core := *config.Core
+	resolved.Core = &core
+	if core.Level < quiet.Level {
+		core.Level = quiet.Level
+	}
+	if quiet.InfoSampleRate > 1 {
+		core.infoSampler = &infoSampler{rate: uint64(quiet.InfoSampleRate)}
+	}
+	return &resolved
+}
+
+// infoSampler keeps one in every rate INFO entries of a quiet logger and
+// its children.
+type infoSampler struct {
+	rate  uint64
+	count atomic.Uint64
+}
+
+// keep reports whether the next INFO entry is kept. Entries of a trace are
+// kept or dropped together, as in SamplingOutput; without a trace ID, one
+// in rate entries is kept. A nil sampler keeps every entry.
+func (s *infoSampler) keep(traceID string) bool {
+	if s == nil {
+		return true
+	}
+	if traceID != "" {
+		return traceSampled(traceID, s.rate)
+	}
+	return (s.count.Add(1)-1)%s.rate == 0
+}
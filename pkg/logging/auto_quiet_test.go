@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadContainerLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  ContainerLimits
+	}{
+		{
+			name: "cgroup v2",
+			files: fstest.MapFS{
+				"memory.max": {Data: []byte("67108864\n")},
+				"cpu.max":    {Data: []byte("25000 100000\n")},
+			},
+			want: ContainerLimits{MemoryBytes: 64 << 20, CPUs: 0.25},
+		},
+		{
+			name: "cgroup v2 unlimited",
+			files: fstest.MapFS{
+				"memory.max": {Data: []byte("max\n")},
+				"cpu.max":    {Data: []byte("max 100000\n")},
+			},
+		},
+		{
+			name: "cgroup v1",
+			files: fstest.MapFS{
+				"memory/memory.limit_in_bytes": {Data: []byte("268435456\n")},
+				"cpu/cpu.cfs_quota_us":         {Data: []byte("50000\n")},
+				"cpu/cpu.cfs_period_us":        {Data: []byte("100000\n")},
+			},
+			want: ContainerLimits{MemoryBytes: 256 << 20, CPUs: 0.5},
+		},
+		{
+			name: "cgroup v1 unlimited",
+			files: fstest.MapFS{
+				"memory/memory.limit_in_bytes": {Data: []byte("9223372036854771712\n")},
+				"cpu/cpu.cfs_quota_us":         {Data: []byte("-1\n")},
+				"cpu/cpu.cfs_period_us":        {Data: []byte("100000\n")},
+			},
+		},
+		{name: "no cgroup", files: fstest.MapFS{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readContainerLimits(tt.files); got != tt.want {
+				t.Errorf("readContainerLimits() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func newAutoQuietLogger(buf *bytes.Buffer, limits ContainerLimits) Logger {
+	quiet := AutoQuietConfig{InfoSampleRate: 3}
+	quiet.limits = func() ContainerLimits { return limits }
+	return NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(DebugLevel).
+		WithTextFormat().
+		WithWriter(buf).
+		WithAutoQuiet(quiet).
+		Build())
+}
+
+func TestAutoQuiet(t *testing.T) {
+	t.Setenv(AutoQuietEnv, "")
+
+	var buf bytes.Buffer
+	logger := newAutoQuietLogger(&buf, ContainerLimits{MemoryBytes: 32 << 20})
+	if got := logger.GetLevel(); got != WarnLevel {
+		t.Fatalf("expected WarnLevel in a constrained container, got %v", got)
+	}
+	logger.Debug("dropped")
+	logger.Warn("kept")
+	if strings.Contains(buf.String(), "dropped") || !strings.Contains(buf.String(), "kept") {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	buf.Reset()
+	logger.SetLevel(InfoLevel)
+	child := logger.WithField("component", "cache")
+	for i := 0; i < 6; i++ {
+		child.Info("info")
+	}
+	if got := strings.Count(buf.String(), "info"); got != 2 {
+		t.Errorf("expected 2 of 6 INFO entries kept, got %d", got)
+	}
+}
+
+func TestAutoQuiet_SamplesWholeTraces(t *testing.T) {
+	t.Setenv(AutoQuietEnv, "")
+
+	var buf bytes.Buffer
+	logger := newAutoQuietLogger(&buf, ContainerLimits{MemoryBytes: 32 << 20})
+	logger.SetLevel(InfoLevel)
+	for i := 0; i < 12; i++ {
+		traceID := fmt.Sprintf("trace-%d", i)
+		ctx := WithTraceID(context.Background(), traceID)
+		for j := 0; j < 3; j++ {
+			logger.InfoContext(ctx, "step")
+		}
+		want := 0
+		if traceSampled(traceID, 3) {
+			want = 3
+		}
+		if got := strings.Count(buf.String(), "step"); got != want {
+			t.Errorf("trace %s: expected %d of 3 entries kept, got %d", traceID, want, got)
+		}
+		buf.Reset()
+	}
+}
+
+func TestAutoQuiet_Unconstrained(t *testing.T) {
+	t.Setenv(AutoQuietEnv, "")
+
+	var buf bytes.Buffer
+	logger := newAutoQuietLogger(&buf, ContainerLimits{MemoryBytes: 1 << 30, CPUs: 2})
+	if got := logger.GetLevel(); got != DebugLevel {
+		t.Errorf("expected the configured level, got %v", got)
+	}
+	for i := 0; i < 3; i++ {
+		logger.Info("info")
+	}
+	if got := strings.Count(buf.String(), "info"); got != 3 {
+		t.Errorf("expected every INFO entry, got %d", got)
+	}
+}
+
+func TestAutoQuiet_EnvOverride(t *testing.T) {
+	var buf bytes.Buffer
+
+	t.Setenv(AutoQuietEnv, "off")
+	if got := newAutoQuietLogger(&buf, ContainerLimits{CPUs: 0.1}).GetLevel(); got != DebugLevel {
+		t.Errorf("LOG_AUTO_QUIET=off: expected DebugLevel, got %v", got)
+	}
+
+	t.Setenv(AutoQuietEnv, "on")
+	if got := newAutoQuietLogger(&buf, ContainerLimits{}).GetLevel(); got != WarnLevel {
+		t.Errorf("LOG_AUTO_QUIET=on: expected WarnLevel, got %v", got)
+	}
+}
@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// parityBackends builds one logger per backend writing to buf, all sharing
+// the same static field so output can be compared across backends.
+func parityBackends(buf *bytes.Buffer) map[string]Logger {
+	build := func(configure func(*LoggerConfigBuilder)) Logger {
+		builder := NewLoggerConfig().
+			WithLevel(InfoLevel).
+			WithWriter(buf)
+		configure(builder)
+		config := builder.Build()
+		config.Core.StaticFields["service"] = "parity-service"
+		return NewUnifiedLogger(config, nil)
+	}
+
+	return map[string]Logger{
+		"text": build(func(b *LoggerConfigBuilder) { b.WithTextFormat() }),
+		"json": build(func(b *LoggerConfigBuilder) { b.WithJSONFormat() }),
+		"slog": build(func(b *LoggerConfigBuilder) { b.WithJSONFormat().UseSlog(true) }),
+	}
+}
+
+func parityContext() context.Context {
+	ctx := WithTraceID(context.Background(), "parity-trace")
+	ctx = WithRequestID(ctx, "parity-request")
+	return WithCorrelationID(ctx, "parity-correlation")
+}
+
+func assertAppearsOnce(t *testing.T, backend, output string, values ...string) {
+	t.Helper()
+	for _, value := range values {
+		if n := strings.Count(output, value); n != 1 {
+			t.Errorf("%s: expected %q exactly once, found %d times in: %s", backend, value, n, output)
+		}
+	}
+}
+
+func TestBackendParity_FieldsAndContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	for name, logger := range parityBackends(buf) {
+		buf.Reset()
+
+		logger.
+			WithField("user_id", "parity-user").
+			WithFields(map[string]interface{}{"component": "parity-component"}).
+			InfoContext(parityContext(), "parity message")
+
+		assertAppearsOnce(t, name, buf.String(),
+			"parity message",
+			"parity-service",
+			"parity-user",
+			"parity-component",
+			"parity-trace",
+			"parity-request",
+			"parity-correlation",
+		)
+	}
+}
+
+func TestBackendParity_FluentContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	for name, logger := range parityBackends(buf) {
+		buf.Reset()
+
+		logger.Fluent().Info().
+			Ctx(parityContext()).
+			Str("user_id", "parity-user").
+			Msg("parity message")
+
+		assertAppearsOnce(t, name, buf.String(),
+			"parity-user",
+			"parity-trace",
+			"parity-request",
+			"parity-correlation",
+		)
+	}
+}
+
+func TestBackendParity_InstanceFieldOverridesStatic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	for name, logger := range parityBackends(buf) {
+		buf.Reset()
+
+		logger.WithField("service", "parity-override").Info("parity message")
+
+		output := buf.String()
+		if strings.Contains(output, "parity-service") {
+			t.Errorf("%s: expected static field to be overridden, got: %s", name, output)
+		}
+		assertAppearsOnce(t, name, output, "parity-override")
+	}
+}
@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is reported for entries dropped because a batching output's
+// queue was full.
+var ErrQueueFull = errors.New("output queue is full")
+
+// batcherConfig configures a batcher.
+type batcherConfig[T any] struct {
+	// Size is the number of items that triggers a flush. Defaults to 100.
+	Size int
+	// Linger is how long the first item of a partial batch waits before the
+	// batch is flushed anyway. Zero flushes partial batches only on Stop.
+	Linger time.Duration
+	// QueueSize is the capacity of the AsyncWorker queue. Defaults to 1000.
+	QueueSize int
+	// Flush delivers a batch. Calls are never concurrent.
+	Flush func(batch []T) error
+	// OnError is called with batches whose Flush failed.
+	OnError func(batch []T, err error)
+}
+
+// batcher groups items submitted through an AsyncWorker into batches,
+// flushing when a batch is full or has lingered long enough. Outputs that
+// ship entries to remote stores share it. Batches are built and delivered
+// only on the worker goroutine, so they reach Flush in submission order.
+type batcher[T any] struct {
+	config  batcherConfig[T]
+	worker  *AsyncWorker[batchOp[T]]
+	pending []T // owned by the worker goroutine
+	mu      sync.Mutex
+	timer   *time.Timer
+}
+
+// batchOp is an item to add to the pending batch or, when flush is set, a
+// request to deliver it.
+type batchOp[T any] struct {
+	item  T
+	flush bool
+	// done, if set, receives the result of the flush.
+	done chan error
+}
+
+func newBatcher[T any](config batcherConfig[T]) *batcher[T] {
+	if config.Size <= 0 {
+		config.Size = 100
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+
+	b := &batcher[T]{config: config}
+	b.worker = NewAsyncWorker(AsyncWorkerConfig[batchOp[T]]{
+		QueueSize: config.QueueSize,
+		Processor: b.process,
+	})
+	return b
+}
+
+// Submit queues item without blocking. When the queue is full the item is
+// dropped, passed to OnError and ErrQueueFull is returned.
+func (b *batcher[T]) Submit(item T) error {
+	if b.worker.IsClosed() {
+		return fmt.Errorf("output is closed")
+	}
+	if b.worker.Submit(batchOp[T]{item: item}) {
+		return nil
+	}
+	if b.config.OnError != nil {
+		b.config.OnError([]T{item}, ErrQueueFull)
+	}
+	return ErrQueueFull
+}
+
+// process runs on the worker goroutine.
+func (b *batcher[T]) process(op batchOp[T]) error {
+	if op.flush {
+		err := b.deliver(b.take())
+		if op.done != nil {
+			op.done <- err
+		}
+		return err
+	}
+
+	b.pending = append(b.pending, op.item)
+	if len(b.pending) < b.config.Size {
+		b.mu.Lock()
+		if b.timer == nil && b.config.Linger > 0 {
+			b.timer = time.AfterFunc(b.config.Linger, b.lingered)
+		}
+		b.mu.Unlock()
+		return nil
+	}
+	return b.deliver(b.take())
+}
+
+// lingered queues a flush of the partial batch. While the queue is full it
+// retries after another Linger; the queued items may fill the batch first.
+func (b *batcher[T]) lingered() {
+	if b.worker.Submit(batchOp[T]{flush: true}) || b.worker.IsClosed() {
+		return
+	}
+	b.mu.Lock()
+	b.timer = time.AfterFunc(b.config.Linger, b.lingered)
+	b.mu.Unlock()
+}
+
+// take removes the pending batch and stops the linger timer.
+func (b *batcher[T]) take() []T {
+	batch := b.pending
+	b.pending = nil
+	b.mu.Lock()
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.mu.Unlock()
+	return batch
+}
+
+// Flush waits until every item submitted before it has been delivered,
+// including those still queued, and returns the error delivering the last
+// partial batch.
+func (b *batcher[T]) Flush() error {
+	op := batchOp[T]{flush: true, done: make(chan error, 1)}
+	if !b.worker.SubmitBlocking(op) {
+		return nil // Stop delivers what is pending.
+	}
+	select {
+	case err := <-op.done:
+		return err
+	case <-b.worker.done:
+		// Stopping; the flush is processed by the drain, or dropped by
+		// StopWithTimeout.
+		return nil
+	}
+}
+
+func (b *batcher[T]) deliver(batch []T) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := b.config.Flush(batch)
+	if err != nil && b.config.OnError != nil {
+		b.config.OnError(batch, err)
+	}
+	return err
+}
+
+// Stop drains the queue and delivers everything still pending.
+func (b *batcher[T]) Stop() error {
+	if err := b.worker.Stop(); err != nil {
+		return err
+	}
+	// The worker has exited, so the pending batch is ours.
+	return b.deliver(b.take())
+}
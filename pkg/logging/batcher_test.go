@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatcher_OrderAndFlush(t *testing.T) {
+	var mu sync.Mutex
+	var delivered []int
+	b := newBatcher(batcherConfig[int]{
+		Size:   3,
+		Linger: time.Millisecond,
+		Flush: func(batch []int) error {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			delivered = append(delivered, batch...)
+			return nil
+		},
+	})
+	defer b.Stop()
+
+	const items = 200
+	for i := 0; i < items; i++ {
+		if err := b.Submit(i); err != nil {
+			t.Fatalf("submit %d: %v", i, err)
+		}
+		if i%7 == 0 {
+			// Let some partial batches linger, so timer flushes
+			// interleave with full ones.
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if err := b.Flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delivered) != items {
+		t.Fatalf("expected Flush to deliver all %d items, got %d", items, len(delivered))
+	}
+	for i, item := range delivered {
+		if item != i {
+			t.Fatalf("item %d delivered at position %d: %v", item, i, delivered)
+		}
+	}
+}
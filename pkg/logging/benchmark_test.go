@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func BenchmarkStandardLogger_Info(b *testing.B) {
@@ -338,3 +341,141 @@ func BenchmarkParallelLogging_SlogLogger(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkStandardLogger_TextWithoutCaller(b *testing.B) {
+	var buf bytes.Buffer
+	config := NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithWriter(&buf).
+		WithTextFormat().
+		Build()
+	config.Formatter.IncludeFile = false
+	logger := NewUnifiedLogger(config, NewRedactorChain()).
+		WithField("service", "test")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("benchmark message")
+	}
+}
+
+func BenchmarkTextFormatter_Format(b *testing.B) {
+	formatter := NewTextFormatter(nil)
+	entry := LogEntry{
+		Timestamp: time.Now(),
+		Level:     InfoLevel,
+		Message:   "benchmark message",
+		Fields:    map[string]interface{}{"service": "test", "version": "1.0.0"},
+		Context:   WithTraceID(context.Background(), "trace-123"),
+		File:      "/path/to/file.go",
+		Line:      42,
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = formatter.Format(entry)
+	}
+}
+
+func BenchmarkFileOutput_SyncPolicy(b *testing.B) {
+	line := []byte(`{"level":"INFO","message":"benchmark message"}` + "\n")
+	for _, policy := range []struct {
+		name string
+		sync FileSyncPolicy
+	}{
+		{"Always", SyncAlways},
+		{"Interval", SyncInterval},
+		{"Never", SyncNever},
+	} {
+		b.Run(policy.name, func(b *testing.B) {
+			output, err := NewFileOutputWithConfig(FileOutputConfig{
+				Filename: filepath.Join(b.TempDir(), "bench.log"),
+				Sync:     policy.sync,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer output.Close()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = output.Write(line)
+			}
+		})
+	}
+}
+
+// countingFile counts the writes, and so the syscalls, made to a file.
+type countingFile struct {
+	file   *os.File
+	writes int
+}
+
+func (cf *countingFile) Write(p []byte) (int, error) {
+	cf.writes++
+	return cf.file.Write(p)
+}
+
+// opaqueOutput hides the output it wraps from AsyncOutput, so every entry
+// is written separately.
+type opaqueOutput struct {
+	Output
+}
+
+func benchmarkAsyncOutput(b *testing.B, wrap func(Output) Output) {
+	file, err := os.Create(filepath.Join(b.TempDir(), "bench.log"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer file.Close()
+	counter := &countingFile{file: file}
+	output := NewAsyncOutput(wrap(NewWriterOutput(counter)), 10000)
+	entry := []byte(`{"level":"INFO","message":"request handled","status":200}` + "\n")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = output.Write(entry)
+		}
+	})
+	_ = output.Stop()
+	b.StopTimer()
+	b.ReportMetric(float64(counter.writes)/float64(b.N), "writes/op")
+}
+
+func BenchmarkAsyncOutput_Coalesced(b *testing.B) {
+	benchmarkAsyncOutput(b, func(o Output) Output { return o })
+}
+
+func BenchmarkAsyncOutput_PerEntry(b *testing.B) {
+	benchmarkAsyncOutput(b, func(o Output) Output { return opaqueOutput{o} })
+}
+
+func BenchmarkJSONFormatter_Format(b *testing.B) {
+	formatter := NewJSONFormatter(nil)
+	entry := LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 0, time.UTC),
+		Level:     InfoLevel,
+		Message:   "request completed",
+		Fields: map[string]interface{}{
+			"service":     "api",
+			"method":      "GET",
+			"path":        "/users/42",
+			"status":      200,
+			"duration_ms": 12.5,
+			"cached":      true,
+		},
+		Context: WithTraceID(context.Background(), "trace-123"),
+		File:    "/src/app/handler.go",
+		Line:    42,
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := formatter.Format(entry); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrBrowserConsoleUnsupported is returned by NewBrowserConsoleOutput
+// outside js/wasm.
+var ErrBrowserConsoleUnsupported = errors.New("the browser console is only available under js/wasm")
+
+// BrowserConsoleOutput writes formatted entries to the JavaScript console
+// of a Go program compiled to WebAssembly (GOOS=js), using the method
+// matching the entry's level: console.debug for TRACE and DEBUG,
+// console.info for INFO, console.warn for WARN and console.error for ERROR
+// and CRITICAL. Browser developer tools then filter and color entries by
+// level, and front-end code uses the same Logger and configuration as the
+// backend. Entries written with plain Write use console.info.
+//
+// Example:
+//
+//	console, err := logging.NewBrowserConsoleOutput()
+//	if err != nil {
+//		return err
+//	}
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithCustomOutput(console).
+//		Build())
+type BrowserConsoleOutput struct {
+	log func(method, line string)
+}
+
+// NewBrowserConsoleOutput creates a BrowserConsoleOutput, or returns
+// ErrBrowserConsoleUnsupported when not running under js/wasm.
+func NewBrowserConsoleOutput() (*BrowserConsoleOutput, error) {
+	log, err := browserConsole()
+	if err != nil {
+		return nil, err
+	}
+	return &BrowserConsoleOutput{log: log}, nil
+}
+
+// consoleMethod returns the console method entries at level are logged
+// with.
+func consoleMethod(level Level) string {
+	switch {
+	case level <= DebugLevel:
+		return "debug"
+	case level == InfoLevel:
+		return "info"
+	case level == WarnLevel:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// Write logs data with console.info.
+func (bo *BrowserConsoleOutput) Write(data []byte) error {
+	bo.log("info", string(bytes.TrimRight(data, "\r\n")))
+	return nil
+}
+
+// WriteEntry logs data with the console method for the entry's level.
+func (bo *BrowserConsoleOutput) WriteEntry(entry LogEntry, data []byte) error {
+	line := string(bytes.TrimRight(data, "\r\n"))
+	if line == "" {
+		line = entry.Message
+	}
+	bo.log(consoleMethod(entry.Level), line)
+	return nil
+}
+
+// Close does nothing; the console needs no cleanup.
+func (bo *BrowserConsoleOutput) Close() error {
+	return nil
+}
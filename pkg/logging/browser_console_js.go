@@ -0,0 +1,17 @@
+//go:build js && wasm
+
+package logging
+
+import "syscall/js"
+
+// browserConsole returns a function calling a method of the global console
+// object.
+func browserConsole() (func(method, line string), error) {
+	console := js.Global().Get("console")
+	if console.IsUndefined() || console.IsNull() {
+		return nil, ErrBrowserConsoleUnsupported
+	}
+	return func(method, line string) {
+		console.Call(method, line)
+	}, nil
+}
@@ -0,0 +1,8 @@
+//go:build !(js && wasm)
+
+package logging
+
+// browserConsole is unsupported outside js/wasm.
+func browserConsole() (func(method, line string), error) {
+	return nil, ErrBrowserConsoleUnsupported
+}
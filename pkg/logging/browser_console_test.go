@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestBrowserConsoleOutput_Methods(t *testing.T) {
+	var calls [][2]string
+	output := &BrowserConsoleOutput{log: func(method, line string) {
+		calls = append(calls, [2]string{method, line})
+	}}
+
+	for _, level := range []Level{TraceLevel, DebugLevel, InfoLevel, WarnLevel, ErrorLevel, CriticalLevel} {
+		_ = output.WriteEntry(LogEntry{Level: level, Message: level.String()}, []byte(level.String()+" formatted\n"))
+	}
+	_ = output.WriteEntry(LogEntry{Level: WarnLevel, Message: "message only"}, nil)
+	_ = output.Write([]byte("raw\n"))
+
+	want := [][2]string{
+		{"debug", "TRACE formatted"},
+		{"debug", "DEBUG formatted"},
+		{"info", "INFO formatted"},
+		{"warn", "WARN formatted"},
+		{"error", "ERROR formatted"},
+		{"error", "CRITICAL formatted"},
+		{"warn", "message only"},
+		{"info", "raw"},
+	}
+	if len(calls) != len(want) {
+		t.Fatalf("expected %d calls, got %v", len(want), calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %v, want %v", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestNewBrowserConsoleOutput_Unsupported(t *testing.T) {
+	if runtime.GOOS == "js" {
+		t.Skip("console is available under js")
+	}
+	if _, err := NewBrowserConsoleOutput(); !errors.Is(err, ErrBrowserConsoleUnsupported) {
+		t.Errorf("expected ErrBrowserConsoleUnsupported, got %v", err)
+	}
+}
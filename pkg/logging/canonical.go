@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CanonicalLogLineMessage is the message of canonical log lines.
+const CanonicalLogLineMessage = "canonical-log-line"
+
+const canonicalEntryKey contextKey = "canonical_entry"
+
+// CanonicalEntry accumulates the fields recorded while serving a request
+// and writes them as one canonical log line when it completes, a "wide
+// event" that answers most questions about the request without joining
+// several entries. Code handling the request records fields through the
+// context; every method is safe for concurrent use and does nothing on a
+// nil entry, so callers need not check whether one was started.
+//
+// Example:
+//
+//	ctx, canonical := logging.NewCanonicalEntry(ctx)
+//	defer canonical.Emit(logger)
+//	...
+//	logging.CanonicalEntryFrom(ctx).Set("user_id", user.ID).Add("db_queries", 1)
+//	// INFO canonical-log-line {db_queries=1 duration_ms=12 user_id=42}
+type CanonicalEntry struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	start   time.Time
+	fields  map[string]interface{}
+	emitted bool
+}
+
+// NewCanonicalEntry starts a canonical log line and returns ctx carrying
+// it. Its duration_ms field is measured from now, and the trace, request
+// and correlation IDs of ctx are written with it.
+func NewCanonicalEntry(ctx context.Context) (context.Context, *CanonicalEntry) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	entry := &CanonicalEntry{start: time.Now(), fields: make(map[string]interface{})}
+	ctx = context.WithValue(ctx, canonicalEntryKey, entry)
+	entry.ctx = ctx
+	return ctx, entry
+}
+
+// CanonicalEntryFrom returns the canonical entry started by
+// NewCanonicalEntry or the tracing middleware that ctx carries, or nil.
+func CanonicalEntryFrom(ctx context.Context) *CanonicalEntry {
+	if ctx == nil {
+		return nil
+	}
+	entry, _ := ctx.Value(canonicalEntryKey).(*CanonicalEntry)
+	return entry
+}
+
+// Set records a field, replacing any earlier value of key.
+func (e *CanonicalEntry) Set(key string, value interface{}) *CanonicalEntry {
+	if e == nil {
+		return e
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fields[key] = value
+	return e
+}
+
+// SetFields records several fields, replacing earlier values of their
+// keys.
+func (e *CanonicalEntry) SetFields(fields map[string]interface{}) *CanonicalEntry {
+	if e == nil {
+		return e
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for k, v := range fields {
+		e.fields[k] = v
+	}
+	return e
+}
+
+// Add adds n to the counter key, such as a count of database queries or
+// cache misses. A key holding something other than a counter is replaced.
+func (e *CanonicalEntry) Add(key string, n int64) *CanonicalEntry {
+	if e == nil {
+		return e
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	count, _ := e.fields[key].(int64)
+	e.fields[key] = count + n
+	return e
+}
+
+// Fields returns a copy of the recorded fields.
+func (e *CanonicalEntry) Fields() map[string]interface{} {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return copyFields(e.fields, 0)
+}
+
+// Emit logs the canonical line at INFO with the recorded fields and a
+// duration_ms field. Only the first call logs; it reports whether it did.
+func (e *CanonicalEntry) Emit(logger Logger) bool {
+	fields, ok := e.complete()
+	if !ok {
+		return false
+	}
+	fluentAt(logger, InfoLevel).
+		Ctx(e.ctx).
+		Fields(fields).
+		Int64("duration_ms", time.Since(e.start).Milliseconds()).
+		Msg(CanonicalLogLineMessage)
+	return true
+}
+
+// complete marks the entry emitted and returns its fields, unless it was
+// emitted already.
+func (e *CanonicalEntry) complete() (map[string]interface{}, bool) {
+	if e == nil {
+		return nil, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.emitted {
+		return nil, false
+	}
+	e.emitted = true
+	return copyFields(e.fields, 0), true
+}
+
+// CanonicalLogMiddleware logs one canonical line per request in place of
+// TracingMiddleware's start and completion entries: the fields handlers
+// record through CanonicalEntryFrom(r.Context()) together with the method,
+// path, status, bytes and duration of the request.
+//
+// Example:
+//
+//	handler := logging.CanonicalLogMiddleware(logger)(mux)
+//	// in a handler:
+//	logging.CanonicalEntryFrom(r.Context()).Set("user_id", user.ID)
+func CanonicalLogMiddleware(logger Logger) func(http.Handler) http.Handler {
+	config := DefaultTracingMiddlewareConfig()
+	config.DisableStart = true
+	config.CanonicalLogLine = true
+	return TracingMiddlewareWithConfig(logger, config)
+}
@@ -0,0 +1,148 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newCanonicalTestLogger(buf *bytes.Buffer) Logger {
+	return NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithWriter(buf).
+		WithJSONFormat().
+		Build())
+}
+
+func decodeJSONLines(t *testing.T, output string) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to decode %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestCanonicalEntry_Emit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newCanonicalTestLogger(buf)
+
+	ctx, canonical := NewCanonicalEntry(WithRequestID(context.Background(), "req-1"))
+	if CanonicalEntryFrom(ctx) != canonical {
+		t.Fatal("expected the context to carry the entry")
+	}
+	CanonicalEntryFrom(ctx).Set("user_id", 42).Add("db_queries", 1)
+	CanonicalEntryFrom(ctx).SetFields(map[string]interface{}{"plan": "pro"}).Add("db_queries", 2)
+
+	if !canonical.Emit(logger) {
+		t.Fatal("expected the first Emit to log")
+	}
+	if canonical.Emit(logger) {
+		t.Error("expected a second Emit not to log")
+	}
+
+	entries := decodeJSONLines(t, buf.String())
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %d: %s", len(entries), buf.String())
+	}
+	entry := entries[0]
+	if entry["message"] != CanonicalLogLineMessage || entry["level"] != "INFO" {
+		t.Errorf("unexpected message or level: %v", entry)
+	}
+	if entry["user_id"] != float64(42) || entry["plan"] != "pro" || entry["db_queries"] != float64(3) {
+		t.Errorf("expected the recorded fields, got %v", entry)
+	}
+	if _, ok := entry["duration_ms"]; !ok {
+		t.Errorf("expected duration_ms, got %v", entry)
+	}
+	if entry["request_id"] != "req-1" {
+		t.Errorf("expected request_id from the context, got %v", entry)
+	}
+}
+
+func TestCanonicalEntry_Nil(t *testing.T) {
+	var canonical *CanonicalEntry
+	canonical.Set("a", 1).SetFields(map[string]interface{}{"b": 2}).Add("c", 1)
+	if canonical.Fields() != nil {
+		t.Error("expected no fields from a nil entry")
+	}
+	if canonical.Emit(NewNop()) {
+		t.Error("expected a nil entry not to log")
+	}
+	if CanonicalEntryFrom(context.Background()) != nil {
+		t.Error("expected no entry in a plain context")
+	}
+}
+
+func TestCanonicalEntry_Concurrent(t *testing.T) {
+	_, canonical := NewCanonicalEntry(context.Background())
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			canonical.Add("calls", 1)
+		}()
+	}
+	wg.Wait()
+	if got := canonical.Fields()["calls"]; got != int64(50) {
+		t.Errorf("expected 50 calls, got %v", got)
+	}
+}
+
+func TestCanonicalLogMiddleware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newCanonicalTestLogger(buf)
+
+	handler := CanonicalLogMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		CanonicalEntryFrom(r.Context()).Set("user_id", "u-7").Set("status", "overridden")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/orders", nil))
+
+	entries := decodeJSONLines(t, buf.String())
+	if len(entries) != 1 {
+		t.Fatalf("expected one canonical line per request, got %d: %s", len(entries), buf.String())
+	}
+	entry := entries[0]
+	if entry["message"] != CanonicalLogLineMessage {
+		t.Errorf("expected message %q, got %v", CanonicalLogLineMessage, entry["message"])
+	}
+	if entry["user_id"] != "u-7" || entry["method"] != "POST" || entry["path"] != "/orders" {
+		t.Errorf("expected handler and request fields, got %v", entry)
+	}
+	if entry["status"] != float64(http.StatusCreated) {
+		t.Errorf("expected the response status to win, got %v", entry["status"])
+	}
+	if _, ok := entry["trace_id"]; !ok {
+		t.Errorf("expected trace_id, got %v", entry)
+	}
+}
+
+func TestTracingMiddlewareWithConfig_WithoutCanonicalLogLine(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := newCanonicalTestLogger(buf)
+
+	handler := TracingMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if CanonicalEntryFrom(r.Context()) != nil {
+			t.Error("expected no canonical entry by default")
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if strings.Contains(buf.String(), CanonicalLogLineMessage) {
+		t.Errorf("expected no canonical line, got %s", buf.String())
+	}
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCEFFormatter_Format(t *testing.T) {
+	formatter := NewCEFFormatter(NewFormatterConfig().AddRedactPattern(`card=\d+`).Build(), CEFConfig{
+		Vendor:           "Acme|Corp",
+		Product:          "payments",
+		Version:          "2.3",
+		SignatureIDField: "event_id",
+		Extensions:       map[string]string{"user": "suser", "client_ip": "src"},
+	})
+	ctx := WithRequestID(context.Background(), "req-1")
+	data, err := formatter.Format(LogEntry{
+		Timestamp: time.UnixMilli(1700000000123),
+		Level:     ErrorLevel,
+		Message:   "login failed card=4111\nstack trace",
+		Context:   ctx,
+		Fields: map[string]interface{}{
+			"event_id":  "auth-401",
+			"user":      "alice",
+			"client_ip": "10.0.0.7",
+			"query":     `a=b\c`,
+			"error":     errors.New("bad password"),
+			"":          "dropped",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	want := `CEF:0|Acme\|Corp|payments|2.3|auth-401|login failed [REDACTED]|7|` +
+		`error=bad password msg=login failed [REDACTED]\nstack trace query=a\=b\\c request_id=req-1 rt=1700000000123 src=10.0.0.7 suser=alice` + "\n"
+	if string(data) != want {
+		t.Errorf("unexpected event\n got: %q\nwant: %q", data, want)
+	}
+}
+
+func TestCEFFormatter_Defaults(t *testing.T) {
+	data, err := NewCEFFormatter(nil, CEFConfig{}).Format(LogEntry{Level: WarnLevel, Message: ""})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	if want := "CEF:0|ocrosby|go-logging|1|WARN|-|5|\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+}
+
+func TestCEFSeverity(t *testing.T) {
+	want := map[Level]int{TraceLevel: 0, DebugLevel: 1, InfoLevel: 3, WarnLevel: 5, ErrorLevel: 7, CriticalLevel: 10}
+	for level, severity := range want {
+		if got := cefSeverity(level); got != severity {
+			t.Errorf("cefSeverity(%v) = %d, want %d", level, got, severity)
+		}
+	}
+}
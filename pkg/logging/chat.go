@@ -0,0 +1,308 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// ChatPlatform selects the incoming webhook format of a ChatOutput.
+type ChatPlatform string
+
+const (
+	// SlackPlatform posts to Slack incoming webhooks.
+	SlackPlatform ChatPlatform = "slack"
+	// TeamsPlatform posts MessageCards to Microsoft Teams incoming webhooks.
+	TeamsPlatform ChatPlatform = "teams"
+)
+
+// DefaultChatTemplate renders the level and message.
+const DefaultChatTemplate = "*{{.Level}}*: {{.Message}}"
+
+// ChatConfig configures a ChatOutput.
+type ChatConfig struct {
+	// Platform is SlackPlatform or TeamsPlatform.
+	Platform ChatPlatform
+	// WebhookURL is the channel's incoming webhook.
+	WebhookURL string
+	// MinLevel is the lowest level posted, e.g. WarnLevel.
+	MinLevel Level
+	// Filter, if set, must accept an entry for it to be posted.
+	Filter EntryFilter
+	// Template is a text/template rendering the message text, with .Level,
+	// .Message, .Time, .Fields (a map) and .Field "name". Defaults to
+	// DefaultChatTemplate.
+	Template string
+	// Fields lists fields, or trace, request or correlation IDs, shown
+	// below the message.
+	Fields []string
+	// MaxMessages is the most messages posted per Interval; the rest are
+	// counted and reported in the next message posted. Defaults to 5.
+	MaxMessages int
+	// Interval defaults to one minute.
+	Interval time.Duration
+	// QueueSize bounds the messages waiting to be posted. Defaults to 100.
+	QueueSize int
+	// Retry controls retries of posts that fail with network errors, 429
+	// or 5xx responses.
+	Retry RetryConfig
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the number of messages that could not be
+	// posted.
+	OnError func(messages int, err error)
+	// Keys are the field keys of entries passed to Write, as set by
+	// WithFieldKeys. Empty keys are the JSONFormatter defaults.
+	Keys FieldKeys
+}
+
+// ChatOutput posts selected entries to a Slack or Microsoft Teams channel
+// through an incoming webhook. Entries must reach MinLevel and pass Filter;
+// each is rendered through Template, with the listed Fields shown below it.
+// At most MaxMessages are posted per Interval so a burst of errors does not
+// flood the channel, and the next message posted says how many were
+// skipped.
+//
+// Example:
+//
+//	chat, err := logging.NewChatOutput(logging.ChatConfig{
+//		Platform:   logging.SlackPlatform,
+//		WebhookURL: os.Getenv("SLACK_WEBHOOK_URL"),
+//		MinLevel:   logging.ErrorLevel,
+//		Filter:     logging.FieldFilter("component", "billing"),
+//		Template:   `:rotating_light: *{{.Level}}* in {{.Field "component"}}: {{.Message}}`,
+//		Fields:     []string{"order_id", "trace_id"},
+//	})
+type ChatOutput struct {
+	config   ChatConfig
+	template *template.Template
+	now      func() time.Time
+	worker   *AsyncWorker[json.RawMessage]
+
+	mu         sync.Mutex
+	limiter    windowLimiter
+	suppressed int
+}
+
+// NewChatOutput creates a ChatOutput.
+func NewChatOutput(config ChatConfig) (*ChatOutput, error) {
+	if config.Platform != SlackPlatform && config.Platform != TeamsPlatform {
+		return nil, fmt.Errorf("unknown chat platform %q", config.Platform)
+	}
+	if config.WebhookURL == "" {
+		return nil, fmt.Errorf("%s output requires a webhook URL", config.Platform)
+	}
+	if config.Template == "" {
+		config.Template = DefaultChatTemplate
+	}
+	tmpl, err := template.New("chat").Parse(config.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chat template: %w", err)
+	}
+	if config.MaxMessages <= 0 {
+		config.MaxMessages = 5
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 100
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	co := &ChatOutput{
+		config:   config,
+		template: tmpl,
+		now:      time.Now,
+		limiter:  windowLimiter{limit: config.MaxMessages, window: config.Interval},
+	}
+	co.worker = NewAsyncWorker(AsyncWorkerConfig[json.RawMessage]{
+		QueueSize: config.QueueSize,
+		Processor: func(message json.RawMessage) error {
+			if err := co.send(message); err != nil {
+				co.reportError(1, err)
+			}
+			return nil
+		},
+	})
+	return co, nil
+}
+
+// chatEntry is the data a chat template is executed with.
+type chatEntry struct {
+	Level   Level
+	Message string
+	Time    time.Time
+	Fields  map[string]interface{}
+	entry   LogEntry
+}
+
+// Field returns the value of a field, or trace, request or correlation ID,
+// or "" if the entry has none.
+func (e chatEntry) Field(name string) string {
+	value, _ := entryFieldString(e.entry, name)
+	return value
+}
+
+// chatField is a field shown below a chat message.
+type chatField struct {
+	name  string
+	value string
+}
+
+// Write parses data as JSON entries, written with the field keys in Keys,
+// and writes each with WriteEntry. Data
+// that is not JSON is rejected with an error, as its level is unknown.
+func (co *ChatOutput) Write(data []byte) error {
+	return writeParsedEntries(data, co.config.Keys, co.WriteEntry)
+}
+
+// WriteEntry queues a message for entry if it is selected and the channel
+// has not been posted to too often.
+func (co *ChatOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	if entry.Level < co.config.MinLevel || (co.config.Filter != nil && !co.config.Filter(entry)) {
+		return nil
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = co.now()
+	}
+
+	co.mu.Lock()
+	if !co.limiter.allow(co.now()) {
+		co.suppressed++
+		co.mu.Unlock()
+		return nil
+	}
+	suppressed := co.suppressed
+	co.suppressed = 0
+	co.mu.Unlock()
+
+	var text strings.Builder
+	err := co.template.Execute(&text, chatEntry{
+		Level:   entry.Level,
+		Message: entry.Message,
+		Time:    entry.Timestamp,
+		Fields:  entry.Fields,
+		entry:   entry,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render chat message: %w", err)
+	}
+	if suppressed > 0 {
+		fmt.Fprintf(&text, "\n(%d earlier messages were not posted)", suppressed)
+	}
+
+	var fields []chatField
+	for _, name := range co.config.Fields {
+		if value, ok := entryFieldString(entry, name); ok {
+			fields = append(fields, chatField{name: name, value: value})
+		}
+	}
+
+	var payload interface{}
+	if co.config.Platform == SlackPlatform {
+		payload = slackMessage(entry.Level, text.String(), fields)
+	} else {
+		payload = teamsMessage(entry.Level, entry.Message, text.String(), fields)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode chat message: %w", err)
+	}
+	if co.worker.IsClosed() {
+		return fmt.Errorf("output is closed")
+	}
+	if !co.worker.Submit(data) {
+		co.reportError(1, ErrQueueFull)
+		return ErrQueueFull
+	}
+	return nil
+}
+
+// chatColor returns the hex color of messages at level.
+func chatColor(level Level) string {
+	switch {
+	case level >= ErrorLevel:
+		return "#d93025"
+	case level == WarnLevel:
+		return "#f9ab00"
+	default:
+		return "#1a73e8"
+	}
+}
+
+// slackMessage returns a Slack incoming webhook payload with fields in a
+// colored attachment.
+func slackMessage(level Level, text string, fields []chatField) map[string]interface{} {
+	message := map[string]interface{}{"text": text}
+	if len(fields) > 0 {
+		attachmentFields := make([]map[string]interface{}, len(fields))
+		for i, f := range fields {
+			attachmentFields[i] = map[string]interface{}{"title": f.name, "value": f.value, "short": true}
+		}
+		message["attachments"] = []map[string]interface{}{{
+			"color":  chatColor(level),
+			"fields": attachmentFields,
+		}}
+	}
+	return message
+}
+
+// teamsMessage returns a Microsoft Teams MessageCard with fields as facts.
+func teamsMessage(level Level, summary, text string, fields []chatField) map[string]interface{} {
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    summary,
+		"themeColor": strings.TrimPrefix(chatColor(level), "#"),
+		"text":       text,
+	}
+	if len(fields) > 0 {
+		facts := make([]map[string]string, len(fields))
+		for i, f := range fields {
+			facts[i] = map[string]string{"name": f.name, "value": f.value}
+		}
+		card["sections"] = []map[string]interface{}{{"facts": facts}}
+	}
+	return card
+}
+
+// send posts message to the webhook.
+func (co *ChatOutput) send(message json.RawMessage) error {
+	err := retry(context.Background(), co.config.Retry, func() error {
+		req, err := http.NewRequest(http.MethodPost, co.config.WebhookURL, bytes.NewReader(message))
+		if err != nil {
+			return permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := co.config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		return checkHTTPResponse(resp)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %w", co.config.Platform, err)
+	}
+	return nil
+}
+
+func (co *ChatOutput) reportError(messages int, err error) {
+	if co.config.OnError != nil {
+		co.config.OnError(messages, err)
+	}
+}
+
+// Close posts queued messages.
+func (co *ChatOutput) Close() error {
+	return co.worker.Stop()
+}
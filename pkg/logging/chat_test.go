@@ -0,0 +1,196 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewChatOutput_Validation(t *testing.T) {
+	if _, err := NewChatOutput(ChatConfig{Platform: "irc", WebhookURL: "http://x"}); err == nil {
+		t.Error("expected error for unknown platform")
+	}
+	if _, err := NewChatOutput(ChatConfig{Platform: SlackPlatform}); err == nil {
+		t.Error("expected error without webhook URL")
+	}
+	if _, err := NewChatOutput(ChatConfig{Platform: SlackPlatform, WebhookURL: "http://x", Template: "{{.Message"}); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
+
+// chatServer records the JSON messages posted to it.
+type chatServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	messages []map[string]interface{}
+}
+
+func newChatServer(t *testing.T) *chatServer {
+	s := &chatServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.messages = append(s.messages, message)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestChatOutput_Slack(t *testing.T) {
+	server := newChatServer(t)
+	output, err := NewChatOutput(ChatConfig{
+		Platform:   SlackPlatform,
+		WebhookURL: server.URL,
+		MinLevel:   ErrorLevel,
+		Filter:     FieldFilter("component", "billing"),
+		Template:   `{{.Level}} in {{.Field "component"}}: {{.Message}} ({{index .Fields "order_id"}})`,
+		Fields:     []string{"order_id", "missing"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create chat output: %v", err)
+	}
+
+	_ = output.WriteEntry(LogEntry{Level: WarnLevel, Message: "too low", Fields: map[string]interface{}{"component": "billing"}}, nil)
+	_ = output.WriteEntry(LogEntry{Level: ErrorLevel, Message: "filtered", Fields: map[string]interface{}{"component": "auth"}}, nil)
+	_ = output.WriteEntry(LogEntry{
+		Level:   ErrorLevel,
+		Message: "charge failed",
+		Fields:  map[string]interface{}{"component": "billing", "order_id": "o-1"},
+	}, nil)
+	_ = output.Close()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(server.messages))
+	}
+	message := server.messages[0]
+	if message["text"] != "ERROR in billing: charge failed (o-1)" {
+		t.Errorf("unexpected text %q", message["text"])
+	}
+	attachment := message["attachments"].([]interface{})[0].(map[string]interface{})
+	fields := attachment["fields"].([]interface{})
+	if len(fields) != 1 || fields[0].(map[string]interface{})["value"] != "o-1" {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+}
+
+func TestChatOutput_Write(t *testing.T) {
+	server := newChatServer(t)
+	output, err := NewChatOutput(ChatConfig{Platform: SlackPlatform, WebhookURL: server.URL, MinLevel: ErrorLevel})
+	if err != nil {
+		t.Fatalf("failed to create chat output: %v", err)
+	}
+
+	lines := `{"level":"INFO","message":"routine"}` + "\n" + `{"level":"ERROR","message":"charge failed"}` + "\n"
+	if err := output.Write([]byte(lines)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := output.Write([]byte("plain text\n")); err == nil {
+		t.Error("expected an error for data that is not JSON")
+	}
+	_ = output.Close()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.messages) != 1 {
+		t.Fatalf("expected 1 message, got %v", server.messages)
+	}
+	if text, _ := server.messages[0]["text"].(string); !strings.Contains(text, "charge failed") {
+		t.Errorf("unexpected text %q", text)
+	}
+}
+
+func TestChatOutput_WriteWithFieldKeys(t *testing.T) {
+	server := newChatServer(t)
+	output, err := NewChatOutput(ChatConfig{
+		Platform:   SlackPlatform,
+		WebhookURL: server.URL,
+		MinLevel:   ErrorLevel,
+		Keys:       FieldKeys{Level: "severity", Message: "msg"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create chat output: %v", err)
+	}
+
+	if err := output.Write([]byte(`{"severity":"ERROR","msg":"charge failed"}` + "\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	_ = output.Close()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.messages) != 1 {
+		t.Fatalf("expected 1 message, got %v", server.messages)
+	}
+	if text, _ := server.messages[0]["text"].(string); !strings.Contains(text, "charge failed") {
+		t.Errorf("unexpected text %q", text)
+	}
+}
+
+func TestChatOutput_Teams(t *testing.T) {
+	server := newChatServer(t)
+	output, err := NewChatOutput(ChatConfig{
+		Platform:   TeamsPlatform,
+		WebhookURL: server.URL,
+		Fields:     []string{"host"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create chat output: %v", err)
+	}
+	_ = output.WriteEntry(LogEntry{Level: WarnLevel, Message: "disk filling", Fields: map[string]interface{}{"host": "db-1"}}, nil)
+	_ = output.Close()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(server.messages))
+	}
+	card := server.messages[0]
+	if card["@type"] != "MessageCard" || card["text"] != "*WARN*: disk filling" || card["summary"] != "disk filling" {
+		t.Errorf("unexpected card: %v", card)
+	}
+	facts := card["sections"].([]interface{})[0].(map[string]interface{})["facts"].([]interface{})
+	if fact := facts[0].(map[string]interface{}); fact["name"] != "host" || fact["value"] != "db-1" {
+		t.Errorf("unexpected facts: %v", facts)
+	}
+}
+
+func TestChatOutput_Throttle(t *testing.T) {
+	server := newChatServer(t)
+	output, err := NewChatOutput(ChatConfig{
+		Platform:    SlackPlatform,
+		WebhookURL:  server.URL,
+		MaxMessages: 2,
+		Interval:    time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("failed to create chat output: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	output.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		_ = output.WriteEntry(LogEntry{Level: ErrorLevel, Message: "flood"}, nil)
+	}
+	now = now.Add(time.Minute)
+	_ = output.WriteEntry(LogEntry{Level: ErrorLevel, Message: "later"}, nil)
+	_ = output.Close()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(server.messages))
+	}
+	if text := server.messages[2]["text"].(string); !strings.Contains(text, "3 earlier messages were not posted") {
+		t.Errorf("expected suppressed count in %q", text)
+	}
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is reported for batches dropped without an attempt because
+// the destination failed repeatedly and its circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerConfig controls when a network output stops calling a
+// failing destination. After FailureThreshold consecutive failed deliveries
+// the circuit opens and deliveries fail fast with ErrCircuitOpen; after
+// Cooldown one delivery is let through, and its result closes or reopens
+// the circuit.
+type CircuitBreakerConfig struct {
+	// FailureThreshold defaults to 5; negative disables the breaker.
+	FailureThreshold int
+	// Cooldown defaults to 30s.
+	Cooldown time.Duration
+}
+
+// circuitBreaker implements CircuitBreakerConfig.
+type circuitBreaker struct {
+	config   CircuitBreakerConfig
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	if config.FailureThreshold == 0 {
+		config.FailureThreshold = 5
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = 30 * time.Second
+	}
+	return &circuitBreaker{config: config}
+}
+
+// allow reports whether a delivery may be attempted.
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.config.FailureThreshold < 0 || cb.failures < cb.config.FailureThreshold {
+		return true
+	}
+	if cb.probing || time.Since(cb.openedAt) < cb.config.Cooldown {
+		return false
+	}
+	cb.probing = true
+	return true
+}
+
+// record updates the breaker with the result of an attempted delivery.
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.probing = false
+	if err == nil {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.config.FailureThreshold > 0 && cb.failures >= cb.config.FailureThreshold {
+		cb.openedAt = time.Now()
+	}
+}
+
+// open reports whether the circuit is currently open.
+func (cb *circuitBreaker) open() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.config.FailureThreshold > 0 && cb.failures >= cb.config.FailureThreshold
+}
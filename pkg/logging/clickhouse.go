@@ -0,0 +1,283 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClickHouseColumn maps an entry field to a typed table column.
+type ClickHouseColumn struct {
+	// Name is the column name.
+	Name string
+	// Field is the entry field, or context ID such as "trace_id", that
+	// fills the column. Defaults to Name.
+	Field string
+	// Type is the ClickHouse column type: String, LowCardinality(String),
+	// Int64, UInt64, Float64 or Bool. Values are converted to it before
+	// insertion. Defaults to String.
+	Type string
+}
+
+// ClickHouseConfig configures a ClickHouseOutput.
+type ClickHouseConfig struct {
+	// Endpoint is the HTTP interface URL, e.g. "http://localhost:8123".
+	Endpoint string
+	Database string
+	// Table defaults to "logs".
+	Table    string
+	User     string
+	Password string
+	// Columns are typed columns filled from entry fields. Fields not mapped
+	// to a column are stored as a JSON object in the fields column.
+	Columns []ClickHouseColumn
+	// BatchSize is the number of rows per INSERT. Defaults to 1000.
+	BatchSize int
+	// Linger is how long a partial batch waits for more rows. Defaults to 1s.
+	Linger time.Duration
+	// QueueSize bounds the rows waiting to be inserted. Defaults to 10000.
+	QueueSize int
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the JSONEachRow lines of batches that could not
+	// be inserted, including rows dropped because the queue was full.
+	OnError func(rows [][]byte, err error)
+}
+
+// ClickHouseOutput inserts log entries into a ClickHouse table in batches
+// using the HTTP interface and the JSONEachRow format. The table must have
+// the columns returned by Schema:
+//
+//	CREATE TABLE logs (
+//	    timestamp DateTime64(3, 'UTC'),
+//	    level LowCardinality(String),
+//	    message String,
+//	    fields String,
+//	    -- one column per ClickHouseColumn, e.g.
+//	    trace_id String,
+//	    status Int64
+//	) ENGINE = MergeTree
+//	ORDER BY timestamp
+//
+// Example:
+//
+//	output, err := logging.NewClickHouseOutput(logging.ClickHouseConfig{
+//		Endpoint: "http://clickhouse:8123",
+//		Database: "observability",
+//		Columns: []logging.ClickHouseColumn{
+//			{Name: "trace_id"},
+//			{Name: "service", Type: "LowCardinality(String)"},
+//			{Name: "status", Type: "Int64"},
+//		},
+//	})
+type ClickHouseOutput struct {
+	config  ClickHouseConfig
+	query   string
+	mapped  map[string]bool
+	batcher *batcher[[]byte]
+}
+
+// NewClickHouseOutput creates a ClickHouseOutput.
+func NewClickHouseOutput(config ClickHouseConfig) (*ClickHouseOutput, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("clickhouse output requires an endpoint")
+	}
+	if config.Table == "" {
+		config.Table = "logs"
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 1000
+	}
+	if config.Linger == 0 {
+		config.Linger = time.Second
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 10000
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	columns := []string{"timestamp", "level", "message", "fields"}
+	mapped := make(map[string]bool, len(config.Columns))
+	config.Columns = append([]ClickHouseColumn(nil), config.Columns...)
+	for i := range config.Columns {
+		column := &config.Columns[i]
+		if column.Name == "" {
+			return nil, fmt.Errorf("clickhouse column %d has no name", i)
+		}
+		if column.Field == "" {
+			column.Field = column.Name
+		}
+		if column.Type == "" {
+			column.Type = "String"
+		}
+		columns = append(columns, column.Name)
+		mapped[column.Field] = true
+	}
+
+	table := config.Table
+	if config.Database != "" {
+		table = config.Database + "." + table
+	}
+
+	co := &ClickHouseOutput{
+		config: config,
+		query:  fmt.Sprintf("INSERT INTO %s (%s) FORMAT JSONEachRow", table, strings.Join(columns, ", ")),
+		mapped: mapped,
+	}
+	co.batcher = newBatcher(batcherConfig[[]byte]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     co.insert,
+		OnError:   config.OnError,
+	})
+	return co, nil
+}
+
+// Schema returns a CREATE TABLE statement matching the output's columns.
+func (co *ClickHouseOutput) Schema() string {
+	table := co.config.Table
+	if co.config.Database != "" {
+		table = co.config.Database + "." + table
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE IF NOT EXISTS %s (\n", table)
+	b.WriteString("    timestamp DateTime64(3, 'UTC'),\n")
+	b.WriteString("    level LowCardinality(String),\n")
+	b.WriteString("    message String,\n")
+	b.WriteString("    fields String")
+	for _, column := range co.config.Columns {
+		fmt.Fprintf(&b, ",\n    %s %s", column.Name, column.Type)
+	}
+	b.WriteString("\n) ENGINE = MergeTree\nORDER BY timestamp\n")
+	return b.String()
+}
+
+// Write queues data as the message of an INFO row.
+func (co *ClickHouseOutput) Write(data []byte) error {
+	return co.WriteEntry(LogEntry{
+		Timestamp: time.Now(),
+		Level:     InfoLevel,
+		Message:   string(bytes.TrimRight(data, "\r\n")),
+	}, data)
+}
+
+// WriteEntry queues entry as a row. The formatted data is not used because
+// ClickHouse stores the entry's parts in columns.
+func (co *ClickHouseOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	row, err := co.encodeRow(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode clickhouse row: %w", err)
+	}
+	return co.batcher.Submit(row)
+}
+
+func (co *ClickHouseOutput) encodeRow(entry LogEntry) ([]byte, error) {
+	extra := make(map[string]interface{}, len(entry.Fields))
+	for k, v := range entry.Fields {
+		if !co.mapped[k] {
+			extra[k] = v
+		}
+	}
+	fields, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+
+	row := map[string]interface{}{
+		"timestamp": entry.Timestamp.UTC().Format("2006-01-02 15:04:05.000"),
+		"level":     entry.Level.String(),
+		"message":   entry.Message,
+		"fields":    string(fields),
+	}
+	for _, column := range co.config.Columns {
+		if value, ok := entryFieldValue(entry, column.Field); ok {
+			row[column.Name] = clickHouseValue(value, column.Type)
+		}
+	}
+
+	return json.Marshal(row)
+}
+
+// entryFieldValue returns field name of entry, falling back to context IDs.
+func entryFieldValue(entry LogEntry, name string) (interface{}, bool) {
+	if v, ok := entry.Fields[name]; ok {
+		return v, true
+	}
+	return entryFieldString(entry, name)
+}
+
+// clickHouseValue converts value to the JSON representation of columnType.
+// Values that can't be converted are sent as strings so ClickHouse reports
+// the mismatch.
+func clickHouseValue(value interface{}, columnType string) interface{} {
+	s := fmt.Sprint(value)
+	switch {
+	case strings.Contains(columnType, "Int"):
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return v
+		case float64:
+			return int64(v)
+		}
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case strings.Contains(columnType, "Float"):
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case strings.Contains(columnType, "Bool"):
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return s
+}
+
+func (co *ClickHouseOutput) insert(rows [][]byte) error {
+	body := bytes.Join(rows, []byte{'\n'})
+
+	endpoint := strings.TrimRight(co.config.Endpoint, "/") + "/?query=" + url.QueryEscape(co.query)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create clickhouse request: %w", err)
+	}
+	if co.config.User != "" {
+		req.Header.Set("X-ClickHouse-User", co.config.User)
+		req.Header.Set("X-ClickHouse-Key", co.config.Password)
+	}
+
+	resp, err := co.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to insert into clickhouse: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to insert into clickhouse: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Flush inserts the pending partial batch.
+func (co *ClickHouseOutput) Flush() error {
+	return co.batcher.Flush()
+}
+
+// Close inserts queued rows.
+func (co *ClickHouseOutput) Close() error {
+	return co.batcher.Stop()
+}
@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewClickHouseOutput_Validation(t *testing.T) {
+	if _, err := NewClickHouseOutput(ClickHouseConfig{}); err == nil {
+		t.Error("expected error without endpoint")
+	}
+	if _, err := NewClickHouseOutput(ClickHouseConfig{Endpoint: "http://localhost", Columns: []ClickHouseColumn{{}}}); err == nil {
+		t.Error("expected error for unnamed column")
+	}
+}
+
+func TestClickHouseOutput_Insert(t *testing.T) {
+	var mu sync.Mutex
+	var query, user string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		query = r.URL.Query().Get("query")
+		user = r.Header.Get("X-ClickHouse-User")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	output, err := NewClickHouseOutput(ClickHouseConfig{
+		Endpoint: server.URL,
+		Database: "obs",
+		User:     "writer",
+		Columns: []ClickHouseColumn{
+			{Name: "trace_id"},
+			{Name: "status", Type: "Int64"},
+			{Name: "cached", Field: "cache_hit", Type: "Bool"},
+		},
+		Linger: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("failed to create clickhouse output: %v", err)
+	}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(output).Build())
+	ctx := WithTraceID(context.Background(), "trace-1")
+	logger.WithFields(map[string]interface{}{
+		"status":    "200",
+		"cache_hit": true,
+		"path":      "/orders",
+	}).InfoContext(ctx, "request done")
+	logger.Warn("second")
+
+	if err := output.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if query != "INSERT INTO obs.logs (timestamp, level, message, fields, trace_id, status, cached) FORMAT JSONEachRow" {
+		t.Errorf("unexpected query: %q", query)
+	}
+	if user != "writer" {
+		t.Errorf("expected user header, got %q", user)
+	}
+
+	lines := strings.Split(string(body), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 rows, got %q", body)
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &row); err != nil {
+		t.Fatalf("invalid row %q: %v", lines[0], err)
+	}
+	if row["message"] != "request done" || row["level"] != "INFO" || row["trace_id"] != "trace-1" {
+		t.Errorf("unexpected row: %v", row)
+	}
+	if row["status"] != float64(200) || row["cached"] != true {
+		t.Errorf("expected typed columns, got status=%#v cached=%#v", row["status"], row["cached"])
+	}
+	if row["fields"] != `{"path":"/orders"}` {
+		t.Errorf("expected unmapped fields only, got %v", row["fields"])
+	}
+}
+
+func TestClickHouseOutput_OnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Code: 60. Table obs.logs does not exist", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var failed int
+	var failure error
+	output, err := NewClickHouseOutput(ClickHouseConfig{
+		Endpoint: server.URL,
+		OnError: func(rows [][]byte, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed += len(rows)
+			failure = err
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create clickhouse output: %v", err)
+	}
+
+	_ = output.Write([]byte("lost\n"))
+	_ = output.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if failed != 1 || failure == nil || !strings.Contains(failure.Error(), "does not exist") {
+		t.Errorf("expected failure callback with server message, got %d rows, %v", failed, failure)
+	}
+}
+
+func TestClickHouseOutput_Schema(t *testing.T) {
+	output, err := NewClickHouseOutput(ClickHouseConfig{
+		Endpoint: "http://localhost:8123",
+		Columns:  []ClickHouseColumn{{Name: "status", Type: "Int64"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to create clickhouse output: %v", err)
+	}
+	defer output.Close()
+
+	schema := output.Schema()
+	for _, want := range []string{"CREATE TABLE IF NOT EXISTS logs (", "timestamp DateTime64(3, 'UTC')", "status Int64", "ORDER BY timestamp"} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("expected %q in schema:\n%s", want, schema)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"context"
+	"time"
+)
+
+// Clock supplies the timestamps of log entries. Configure one with
+// CoreConfigBuilder.WithClock to freeze time in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+//
+// Example:
+//
+//	fixed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+//	config := logging.NewLoggerConfig().
+//		WithClock(logging.ClockFunc(func() time.Time { return fixed })).
+//		Build()
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the default Clock, backed by time.Now.
+var SystemClock Clock = ClockFunc(time.Now)
+
+const entryTimeKey contextKey = "entry_time"
+
+// WithEntryTime returns a context that makes entries logged with it use t as
+// their timestamp instead of the configured Clock. Replay tools use it to
+// preserve original timestamps when re-emitting entries.
+func WithEntryTime(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, entryTimeKey, t)
+}
+
+// GetEntryTime retrieves a timestamp set with WithEntryTime.
+func GetEntryTime(ctx context.Context) (time.Time, bool) {
+	if ctx == nil {
+		return time.Time{}, false
+	}
+	t, ok := ctx.Value(entryTimeKey).(time.Time)
+	return t, ok
+}
+
+// Replay re-emits entry through logger, preserving its timestamp, level,
+// message, fields and context.
+//
+// Example:
+//
+//	for entry, err := range logging.Query(dir, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		logging.Replay(logger, entry)
+//	}
+func Replay(logger Logger, entry LogEntry) {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !entry.Timestamp.IsZero() {
+		ctx = WithEntryTime(ctx, entry.Timestamp)
+	}
+	if len(entry.Fields) > 0 {
+		logger = logger.WithFields(entry.Fields)
+	}
+	logger.LogContext(ctx, entry.Level, "%s", entry.Message)
+}
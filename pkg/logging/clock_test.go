@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+var frozenTime = time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+
+func frozenClock() Clock {
+	return ClockFunc(func() time.Time { return frozenTime })
+}
+
+func TestClock_FreezesJSONTimestamps(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithWriter(buf).
+		WithClock(frozenClock()).
+		Build())
+
+	logger.Info("first")
+	logger.Info("second")
+
+	entries := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	for _, line := range entries {
+		entry, ok := ParseJSONEntry([]byte(line))
+		if !ok || !entry.Timestamp.Equal(frozenTime) {
+			t.Errorf("expected frozen timestamp, got: %s", line)
+		}
+	}
+}
+
+func TestClock_FreezesSlogTimestamps(t *testing.T) {
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().
+		WithJSONFormat().
+		WithWriter(buf).
+		WithClock(frozenClock()).
+		UseSlog(true).
+		Build()
+	logger := NewWithLoggerConfig(config)
+
+	logger.Info("slog entry")
+
+	if !strings.Contains(buf.String(), `"time":"2024-03-15T09:30:00Z"`) {
+		t.Errorf("expected frozen slog timestamp, got: %s", buf.String())
+	}
+}
+
+func TestWithEntryTime_OverridesClock(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithWriter(buf).
+		WithClock(frozenClock()).
+		Build())
+
+	original := frozenTime.Add(-24 * time.Hour)
+	logger.InfoContext(WithEntryTime(context.Background(), original), "replayed")
+
+	entry, ok := ParseJSONEntry(bytes.TrimSpace(buf.Bytes()))
+	if !ok || !entry.Timestamp.Equal(original) {
+		t.Errorf("expected original timestamp, got: %s", buf.String())
+	}
+}
+
+func TestReplay_PreservesEntry(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(TraceLevel).
+		WithJSONFormat().
+		WithWriter(buf).
+		Build())
+
+	original := LogEntry{
+		Timestamp: frozenTime,
+		Level:     WarnLevel,
+		Message:   "disk 95% full",
+		Fields:    map[string]interface{}{"host": "db-1"},
+	}
+	Replay(logger, original)
+
+	entry, ok := ParseJSONEntry(bytes.TrimSpace(buf.Bytes()))
+	if !ok {
+		t.Fatalf("expected JSON entry, got: %s", buf.String())
+	}
+	if !entry.Timestamp.Equal(frozenTime) || entry.Level != WarnLevel || entry.Message != original.Message {
+		t.Errorf("unexpected replayed entry: %+v", entry)
+	}
+	if entry.Fields["host"] != "db-1" {
+		t.Errorf("expected host field, got %v", entry.Fields)
+	}
+}
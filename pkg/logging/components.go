@@ -0,0 +1,94 @@
+package logging
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ComponentField is the field set on entries logged from a package
+// registered with RegisterComponent.
+const ComponentField = "component"
+
+// components maps package import paths to component names.
+var components struct {
+	mu        sync.RWMutex
+	byPackage map[string]string
+	// byPC caches the component of each call site; "" means none.
+	byPC sync.Map
+}
+
+// componentsRegistered is set once RegisterComponent has been called, so
+// call sites are only resolved when there is something to find.
+var componentsRegistered atomic.Bool
+
+// RegisterComponent tags every entry logged from code in the package
+// pkgPath with a component field, unless the entry already has one. It is
+// usually called from an init function written by the logcomponent
+// generator for packages carrying a "//logging:component <name>"
+// directive, rather than by hand.
+//
+// Example:
+//
+//	//logging:component auth
+//	//go:generate go run github.com/ocrosby/go-logging/cmd/logcomponent
+//	package auth
+func RegisterComponent(pkgPath, component string) {
+	if pkgPath == "" || component == "" {
+		reportInvalidConfig("RegisterComponent", "ignoring empty package path or component", nil)
+		return
+	}
+
+	components.mu.Lock()
+	if components.byPackage == nil {
+		components.byPackage = make(map[string]string)
+	}
+	components.byPackage[pkgPath] = component
+	components.mu.Unlock()
+
+	components.byPC.Clear()
+	componentsRegistered.Store(true)
+}
+
+// componentAt returns the component registered for the package of the
+// function containing pc.
+func componentAt(pc uintptr) (string, bool) {
+	if cached, ok := components.byPC.Load(pc); ok {
+		component := cached.(string)
+		return component, component != ""
+	}
+
+	var component string
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function != "" {
+		components.mu.RLock()
+		component = components.byPackage[packageOfFunction(frame.Function)]
+		components.mu.RUnlock()
+	}
+	components.byPC.Store(pc, component)
+	return component, component != ""
+}
+
+// callerComponent returns the component of the user's call site, found by
+// callerPC from skip frames above callerComponent's caller.
+func callerComponent(skip int) (string, bool) {
+	if !componentsRegistered.Load() {
+		return "", false
+	}
+	pc, ok := callerPC(skip + 1)
+	if !ok {
+		return "", false
+	}
+	return componentAt(pc)
+}
+
+// packageOfFunction returns the import path of a fully qualified function
+// name such as "example.com/app/auth.(*Service).Login.func1".
+func packageOfFunction(name string) string {
+	slash := strings.LastIndexByte(name, '/')
+	if dot := strings.IndexByte(name[slash+1:], '.'); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// registerTestComponent registers component for this package until the
+// test ends.
+func registerTestComponent(t *testing.T, component string) {
+	t.Helper()
+	RegisterComponent("github.com/ocrosby/go-logging/pkg/logging", component)
+	t.Cleanup(func() {
+		components.mu.Lock()
+		components.byPackage = nil
+		components.mu.Unlock()
+		components.byPC.Clear()
+		componentsRegistered.Store(false)
+	})
+}
+
+func TestRegisterComponent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+
+	logger.Info("before registration")
+	registerTestComponent(t, "auth")
+	logger.Info("after registration")
+	logger.WithField(ComponentField, "billing").Info("explicit component")
+	func() {
+		logger.Info("from a closure")
+	}()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), buf.String())
+	}
+	if strings.Contains(lines[0], `"component"`) {
+		t.Errorf("expected no component before registration, got %s", lines[0])
+	}
+	for _, i := range []int{1, 3} {
+		if !strings.Contains(lines[i], `"component":"auth"`) {
+			t.Errorf("expected the registered component, got %s", lines[i])
+		}
+	}
+	if !strings.Contains(lines[2], `"component":"billing"`) || strings.Contains(lines[2], `"auth"`) {
+		t.Errorf("expected an explicit component to win, got %s", lines[2])
+	}
+}
+
+func TestRegisterComponent_Slog(t *testing.T) {
+	registerTestComponent(t, "auth")
+
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build()
+	config.UseSlog = true
+	logger := NewWithLoggerConfig(config)
+
+	logger.Info("slog entry")
+	logger.WithField(ComponentField, "billing").Info("explicit component")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"component":"auth"`) || strings.Count(lines[1], `"component"`) != 1 {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestRegisterComponent_Fluent(t *testing.T) {
+	registerTestComponent(t, "auth")
+
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+	logger.Fluent().Info().Str("user", "alice").Msg("fluent entry")
+
+	if !strings.Contains(buf.String(), `"component":"auth"`) {
+		t.Errorf("expected the registered component, got %s", buf.String())
+	}
+	// This package's own frames share its component, so check that the
+	// component was resolved from the call site rather than fluent.go.
+	components.byPC.Range(func(key, _ any) bool {
+		frame, _ := runtime.CallersFrames([]uintptr{key.(uintptr)}).Next()
+		if !strings.HasSuffix(frame.File, "components_test.go") {
+			t.Errorf("component resolved from %s:%d", frame.File, frame.Line)
+		}
+		return true
+	})
+}
+
+func TestRegisterComponent_OtherPackages(t *testing.T) {
+	diagnostics := captureDiagnostics(t)
+	RegisterComponent("", "auth")
+	if got := diagnostics(); len(got) != 1 || got[0].Source != "RegisterComponent" {
+		t.Errorf("expected an empty package path to be reported, got %v", got)
+	}
+	registerTestComponent(t, "auth")
+
+	if got := packageOfFunction("example.com/app/auth.(*Service).Login.func1"); got != "example.com/app/auth" {
+		t.Errorf("unexpected package %q", got)
+	}
+	if got := packageOfFunction("main.main"); got != "main" {
+		t.Errorf("unexpected package %q", got)
+	}
+	RegisterComponent("example.com/app/auth", "auth")
+	if _, ok := componentAt(0); ok {
+		t.Error("expected no component for an unknown pc")
+	}
+}
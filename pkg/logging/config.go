@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"regexp"
@@ -12,6 +13,27 @@ const (
 	CommonLogFormat OutputFormat = iota
 	TextFormat
 	JSONFormat
+	// AutoFormat writes colored ConsoleFormatter output when the logger
+	// writes to a terminal and JSON when its output is piped or redirected.
+	AutoFormat
+	// GELFFormat writes GELF 1.1 JSON with GELFFormatter, for Graylog
+	// inputs reached through any output, such as HTTPOutput.
+	GELFFormat
+	// CombinedLogFormat writes the Apache Combined Log Format, the Common
+	// Log Format with the referer and user agent.
+	CombinedLogFormat
+	// TemplateFormat renders entries through FormatterConfig.Template with
+	// TemplateFormatter.
+	TemplateFormat
+	// PrettyFormat writes multi-line development output with
+	// PrettyFormatter, colored when the logger writes to a terminal.
+	PrettyFormat
+	// StackdriverFormat writes Google Cloud structured logging JSON with
+	// StackdriverFormatter, for Cloud Run and GKE stdout logs.
+	StackdriverFormat
+	// RegisteredFormat writes entries with the formatter registered by
+	// RegisterFormatter as FormatterConfig.FormatName.
+	RegisteredFormat
 )
 
 // Config provides backward compatibility with the old configuration system.
@@ -112,9 +134,12 @@ func (b *ConfigBuilder) UseShortFile(useShort bool) *ConfigBuilder {
 }
 
 func (b *ConfigBuilder) AddRedactPattern(pattern string) *ConfigBuilder {
-	if re, err := regexp.Compile(pattern); err == nil {
-		b.builder.config.Formatter.RedactPatterns = append(b.builder.config.Formatter.RedactPatterns, re)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		reportInvalidConfig("AddRedactPattern", fmt.Sprintf("ignoring invalid pattern %q", pattern), err)
+		return b
 	}
+	b.builder.config.Formatter.RedactPatterns = append(b.builder.config.Formatter.RedactPatterns, re)
 	return b
 }
 
@@ -0,0 +1,232 @@
+package logging
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigAuditMessage is the message of configuration audit entries.
+const ConfigAuditMessage = "logging configuration changed"
+
+// ConfigChange is one setting that differs between two configurations.
+// Old is nil for added settings and New is nil for removed ones.
+type ConfigChange struct {
+	Setting string      `json:"setting"`
+	Old     interface{} `json:"old,omitempty"`
+	New     interface{} `json:"new,omitempty"`
+}
+
+// String renders the change as "setting: old → new".
+func (c ConfigChange) String() string {
+	return fmt.Sprintf("%s: %v → %v", c.Setting, displayConfigValue(c.Old), displayConfigValue(c.New))
+}
+
+func displayConfigValue(v interface{}) interface{} {
+	if v == nil {
+		return "(none)"
+	}
+	return v
+}
+
+// ConfigChangeSource describes what triggered a configuration change.
+type ConfigChangeSource struct {
+	// Kind is the trigger, e.g. "file", "http" or "api".
+	Kind string
+	// Actor identifies who made the change, e.g. an HTTP caller.
+	Actor string
+	// Detail adds context, e.g. the file path or request path.
+	Detail string
+}
+
+// ConfigChangeFromFile describes a change loaded from path.
+func ConfigChangeFromFile(path string) ConfigChangeSource {
+	return ConfigChangeSource{Kind: "file", Detail: path}
+}
+
+// ConfigChangeFromRequest describes a change made through an HTTP request.
+// The actor is the basic auth user if present, otherwise the remote address.
+func ConfigChangeFromRequest(r *http.Request) ConfigChangeSource {
+	actor := r.RemoteAddr
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		actor = user
+	}
+	return ConfigChangeSource{Kind: "http", Actor: actor, Detail: r.Method + " " + r.URL.Path}
+}
+
+// DiffYAMLConfig returns the settings that differ between old and new,
+// sorted by setting. Static fields and redaction patterns are compared per
+// entry so additions and removals are reported individually.
+func DiffYAMLConfig(old, new *YAMLConfig) []ConfigChange {
+	if old == nil {
+		old = &YAMLConfig{}
+	}
+	if new == nil {
+		new = &YAMLConfig{}
+	}
+
+	var changes []ConfigChange
+	diff := func(setting string, o, n interface{}, zero interface{}) {
+		if reflect.DeepEqual(o, n) {
+			return
+		}
+		if reflect.DeepEqual(o, zero) {
+			o = nil
+		}
+		if reflect.DeepEqual(n, zero) {
+			n = nil
+		}
+		changes = append(changes, ConfigChange{Setting: setting, Old: o, New: n})
+	}
+
+	diff("level", old.Level, new.Level, "")
+	diff("format", old.Format, new.Format, "")
+	diff("template", old.Template, new.Template, "")
+	oldKeys, newKeys := yamlFieldKeys(old.FieldKeys), yamlFieldKeys(new.FieldKeys)
+	diff("field_keys.timestamp", oldKeys.Timestamp, newKeys.Timestamp, "")
+	diff("field_keys.level", oldKeys.Level, newKeys.Level, "")
+	diff("field_keys.message", oldKeys.Message, newKeys.Message, "")
+	diff("time_format", old.TimeFormat, new.TimeFormat, "")
+	diff("time_zone", old.TimeZone, new.TimeZone, "")
+	diff("time_precision", old.TimePrecision, new.TimePrecision, "")
+	diff("field_order", old.FieldOrder, new.FieldOrder, "")
+	for _, level := range unionThemeLevels(old.Theme, new.Theme) {
+		diff("theme."+level, old.Theme[level], new.Theme[level], YAMLLevelTheme{})
+	}
+	diff("priority_fields", strings.Join(old.PriorityFields, ","), strings.Join(new.PriorityFields, ","), "")
+	diff("group_style", old.GroupStyle, new.GroupStyle, "")
+	diff("include_file", old.IncludeFile, new.IncludeFile, nil)
+	diff("include_time", old.IncludeTime, new.IncludeTime, nil)
+	diff("use_short_file", old.UseShortFile, new.UseShortFile, nil)
+	diff("collapse_multiline", old.CollapseMultiline, new.CollapseMultiline, nil)
+	diff("use_slog", old.UseSlog, new.UseSlog, nil)
+	diff("utc", old.UTC, new.UTC, nil)
+	diff("preset", old.Preset, new.Preset, "")
+	diff("schema_version", old.SchemaVersion, new.SchemaVersion, "")
+	diff("output.type", old.Output.Type, new.Output.Type, "")
+	diff("output.target", old.Output.Target, new.Output.Target, "")
+	diff("output.dead_letter", old.Output.DeadLetter, new.Output.DeadLetter, "")
+
+	for _, key := range unionKeys(old.StaticFields, new.StaticFields) {
+		diff("static_fields."+key, old.StaticFields[key], new.StaticFields[key], nil)
+	}
+
+	oldPatterns, newPatterns := stringSet(old.RedactList), stringSet(new.RedactList)
+	for _, p := range old.RedactList {
+		if !newPatterns[p] {
+			diff("redact_patterns", p, nil, nil)
+		}
+	}
+	for _, p := range new.RedactList {
+		if !oldPatterns[p] {
+			diff("redact_patterns", nil, p, nil)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Setting < changes[j].Setting })
+	return changes
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// unionThemeLevels returns the sorted levels of either theme.
+func unionThemeLevels(a, b map[string]YAMLLevelTheme) []string {
+	levels := make([]string, 0, len(a)+len(b))
+	for level := range a {
+		levels = append(levels, level)
+	}
+	for level := range b {
+		if _, ok := a[level]; !ok {
+			levels = append(levels, level)
+		}
+	}
+	sort.Strings(levels)
+	return levels
+}
+
+// yamlFieldKeys returns keys, or no keys when it is nil.
+func yamlFieldKeys(keys *YAMLFieldKeys) YAMLFieldKeys {
+	if keys == nil {
+		return YAMLFieldKeys{}
+	}
+	return *keys
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// AuditConfigChange logs an INFO entry describing changes and their source,
+// with fields audit, config_changes, change_source, change_actor and
+// change_detail. Nothing is logged when changes is empty.
+func AuditConfigChange(logger Logger, source ConfigChangeSource, changes []ConfigChange) {
+	auditConfigChange(logger, InfoLevel, source, changes)
+}
+
+func auditConfigChange(logger Logger, level Level, source ConfigChangeSource, changes []ConfigChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fields := map[string]interface{}{
+		"audit":          true,
+		"config_changes": changes,
+		"change_source":  source.Kind,
+	}
+	if source.Actor != "" {
+		fields["change_actor"] = source.Actor
+	}
+	if source.Detail != "" {
+		fields["change_detail"] = source.Detail
+	}
+	logger.WithFields(fields).Log(level, ConfigAuditMessage)
+}
+
+// SetLevelWithAudit changes logger's level and audits the change. The entry
+// is logged while the less severe of the two levels is active, at INFO or
+// that level if higher, so neither level filters it out.
+//
+// Example:
+//
+//	logging.SetLevelWithAudit(logger, logging.DebugLevel, logging.ConfigChangeFromRequest(r))
+func SetLevelWithAudit(logger Logger, level Level, source ConfigChangeSource) {
+	old := logger.GetLevel()
+	if old == level {
+		return
+	}
+	setLevelWithAudit(logger, level, source, []ConfigChange{{Setting: "level", Old: old.String(), New: level.String()}})
+}
+
+// setLevelWithAudit changes logger's level and audits changes, at a level
+// at which neither the old nor the new level filters the entry out.
+func setLevelWithAudit(logger Logger, level Level, source ConfigChangeSource, changes []ConfigChange) {
+	old := logger.GetLevel()
+	auditLevel := InfoLevel
+	if lower := min(old, level); lower > auditLevel {
+		auditLevel = lower
+	}
+	if level > old {
+		auditConfigChange(logger, auditLevel, source, changes)
+		logger.SetLevel(level)
+		return
+	}
+	logger.SetLevel(level)
+	auditConfigChange(logger, auditLevel, source, changes)
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiffYAMLConfig(t *testing.T) {
+	old := &YAMLConfig{
+		Level:        "info",
+		Format:       "json",
+		StaticFields: map[string]interface{}{"service": "api", "region": "us"},
+		RedactList:   []string{"password"},
+		Output:       YAMLOutputConfig{Type: "stdout"},
+	}
+	new := &YAMLConfig{
+		Level:        "debug",
+		Format:       "json",
+		StaticFields: map[string]interface{}{"service": "api", "env": "prod"},
+		RedactList:   []string{"token"},
+		Output:       YAMLOutputConfig{Type: "file", Target: "/var/log/app.log"},
+	}
+
+	var got []string
+	for _, c := range DiffYAMLConfig(old, new) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		"level: info → debug",
+		"output.target: (none) → /var/log/app.log",
+		"output.type: stdout → file",
+		"redact_patterns: password → (none)",
+		"redact_patterns: (none) → token",
+		"static_fields.env: (none) → prod",
+		"static_fields.region: us → (none)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected diff:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if changes := DiffYAMLConfig(old, old); len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestAuditConfigChange(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+
+	req := httptest.NewRequest("PUT", "/admin/logging", nil)
+	req.SetBasicAuth("ops", "secret")
+	AuditConfigChange(logger, ConfigChangeFromRequest(req), []ConfigChange{{Setting: "format", Old: "text", New: "json"}})
+
+	entry, ok := ParseJSONEntry(bytes.TrimSpace(buf.Bytes()))
+	if !ok || entry.Message != ConfigAuditMessage {
+		t.Fatalf("expected an audit entry, got %s", buf.String())
+	}
+	if entry.Fields["change_source"] != "http" || entry.Fields["change_actor"] != "ops" || entry.Fields["change_detail"] != "PUT /admin/logging" {
+		t.Errorf("unexpected source fields: %v", entry.Fields)
+	}
+	changes, _ := entry.Fields["config_changes"].([]interface{})
+	if len(changes) != 1 {
+		t.Fatalf("expected one change, got %v", entry.Fields["config_changes"])
+	}
+	if change, _ := changes[0].(map[string]interface{}); change["setting"] != "format" || change["new"] != "json" {
+		t.Errorf("unexpected change %v", changes[0])
+	}
+
+	buf.Reset()
+	AuditConfigChange(logger, ConfigChangeFromFile("app.yaml"), nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing logged without changes, got %s", buf.String())
+	}
+}
+
+func TestSetLevelWithAudit(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+	source := ConfigChangeSource{Kind: "api", Actor: "admin"}
+
+	SetLevelWithAudit(logger, ErrorLevel, source)
+	if logger.GetLevel() != ErrorLevel {
+		t.Fatalf("expected level ERROR, got %s", logger.GetLevel())
+	}
+	if !strings.Contains(buf.String(), `"new":"ERROR"`) {
+		t.Errorf("expected the raise to be audited, got %s", buf.String())
+	}
+
+	buf.Reset()
+	SetLevelWithAudit(logger, WarnLevel, source)
+	if !strings.Contains(buf.String(), `"new":"WARN"`) || !strings.Contains(buf.String(), `"level":"WARN"`) {
+		t.Errorf("expected the change to be audited at WARN, got %s", buf.String())
+	}
+
+	buf.Reset()
+	SetLevelWithAudit(logger, WarnLevel, source)
+	if buf.Len() != 0 {
+		t.Errorf("expected no audit for an unchanged level, got %s", buf.String())
+	}
+}
@@ -1,21 +1,63 @@
 package logging
 
 import (
+	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"regexp"
+	"time"
 )
 
 const (
-	jsonFormatString = "json"
-	textFormatString = "text"
+	jsonFormatString        = "json"
+	textFormatString        = "text"
+	autoFormatString        = "auto"
+	gelfFormatString        = "gelf"
+	templateFormatString    = "template"
+	prettyFormatString      = "pretty"
+	stackdriverFormatString = "stackdriver"
 )
 
 // CoreConfig contains the core logging configuration.
 type CoreConfig struct {
 	Level        Level
 	StaticFields map[string]interface{}
+
+	// Clock supplies entry timestamps; nil means SystemClock.
+	Clock Clock
+
+	// UTC converts every entry timestamp to UTC, so text, console and
+	// common log output use the same time zone as JSON.
+	UTC bool
+
+	// SchemaVersion, when set, is added to every entry as
+	// log_schema_version.
+	SchemaVersion string
+	// FieldMigrations rename fields on every entry.
+	FieldMigrations []FieldMigration
+
+	// FieldEncryptor, when set, encrypts its fields on every entry.
+	FieldEncryptor *FieldEncryptor
+
+	// NoiseDemoter, when set, demotes INFO messages repeated at a very high
+	// rate to DEBUG.
+	NoiseDemoter *NoiseDemoter
+
+	// RecentErrors is how many ERROR and CRITICAL entries loggers keep in
+	// memory for RecentErrorsLogger; zero keeps none.
+	RecentErrors int
+
+	// Origin adds an origin field with the binary's module, version and
+	// VCS revision and the emitting package to every entry.
+	Origin bool
+
+	// AutoQuiet, when set, raises the level and samples INFO entries in
+	// severely constrained containers.
+	AutoQuiet *AutoQuietConfig
+
+	// infoSampler samples INFO entries once auto-quiet mode applies.
+	infoSampler *infoSampler
 }
 
 // FormatterConfig contains formatting-related configuration.
@@ -25,11 +67,66 @@ type FormatterConfig struct {
 	IncludeTime    bool
 	UseShortFile   bool
 	RedactPatterns []*regexp.Regexp
+
+	// Template is the text/template TemplateFormat renders entries with.
+	Template string
+
+	// FormatName is the name of the formatter RegisteredFormat uses.
+	FormatName string
+
+	// Keys rename the timestamp, level and message keys of JSON output.
+	Keys FieldKeys
+
+	// TimeFormat is the Go time layout of timestamps; empty keeps each
+	// formatter's default. TimeZone converts timestamps before formatting;
+	// nil keeps UTC for JSON and the entry's own zone elsewhere.
+	TimeFormat string
+	TimeZone   *time.Location
+
+	// TimePrecision adds sub-second digits to the default JSON and text
+	// timestamp layouts.
+	TimePrecision TimePrecision
+
+	// FieldOrder is the order fields are written in, after PriorityFields.
+	FieldOrder     FieldOrder
+	PriorityFields []string
+
+	// GroupStyle is how JSON output writes the fields of groups.
+	GroupStyle GroupStyle
+
+	// CollapseMultiline writes field values that span several lines on
+	// the entry's line in text and console output, with their line breaks
+	// escaped, instead of on indented lines beneath it.
+	CollapseMultiline bool
+
+	// JSONEncoder encodes JSON field values the JSON formatter does not
+	// append itself; nil uses StandardJSONEncoder.
+	JSONEncoder JSONEncoder
+
+	// LevelNames replace the displayed names of levels, and LevelColors
+	// the ANSI escapes console output colors them with.
+	LevelNames  map[Level]string
+	LevelColors map[Level]string
+
+	// Custom, when set, replaces the built-in formatter selected by Format.
+	Custom Formatter
 }
 
 // OutputConfig contains output-related configuration.
 type OutputConfig struct {
 	Writer io.Writer
+
+	// Custom, when set, receives formatted entries instead of Writer.
+	// Use it to plug in MultiOutput, AsyncOutput, FileOutput and friends.
+	Custom Output
+
+	// LevelWriters send entries at or above a level to another writer
+	// instead of Writer. They are ignored when Custom is set.
+	LevelWriters []LevelWriter
+
+	// OnWriteError is called with entries that could not be formatted or
+	// written. Without it, such errors are ignored.
+	OnWriteError WriteErrorHandler
 }
 
 // LoggerConfig combines all configuration types.
@@ -62,9 +159,12 @@ func (b *CoreConfigBuilder) WithLevel(level Level) *CoreConfigBuilder {
 }
 
 func (b *CoreConfigBuilder) WithLevelString(level string) *CoreConfigBuilder {
-	if l, ok := ParseLevel(level); ok {
-		b.config.Level = l
+	l, ok := ParseLevel(level)
+	if !ok {
+		reportInvalidConfig("WithLevelString", fmt.Sprintf("ignoring unknown level %q", level), nil)
+		return b
 	}
+	b.config.Level = l
 	return b
 }
 
@@ -80,6 +180,36 @@ func (b *CoreConfigBuilder) WithStaticFields(fields map[string]interface{}) *Cor
 	return b
 }
 
+// WithClock sets the Clock used to timestamp entries.
+func (b *CoreConfigBuilder) WithClock(clock Clock) *CoreConfigBuilder {
+	b.config.Clock = clock
+	return b
+}
+
+// WithSchemaVersion adds version to every entry as log_schema_version.
+// WithUTC converts every entry timestamp to UTC.
+func (b *CoreConfigBuilder) WithUTC() *CoreConfigBuilder {
+	b.config.UTC = true
+	return b
+}
+
+func (b *CoreConfigBuilder) WithSchemaVersion(version string) *CoreConfigBuilder {
+	b.config.SchemaVersion = version
+	return b
+}
+
+// WithFieldMigration renames a field on every entry.
+func (b *CoreConfigBuilder) WithFieldMigration(migration FieldMigration) *CoreConfigBuilder {
+	b.config.FieldMigrations = append(b.config.FieldMigrations, migration)
+	return b
+}
+
+// WithFieldEncryption encrypts the encryptor's fields on every entry.
+func (b *CoreConfigBuilder) WithFieldEncryption(encryptor *FieldEncryptor) *CoreConfigBuilder {
+	b.config.FieldEncryptor = encryptor
+	return b
+}
+
 func (b *CoreConfigBuilder) Build() *CoreConfig {
 	return b.config
 }
@@ -122,6 +252,18 @@ func (b *FormatterConfigBuilder) WithCommonLogFormat() *FormatterConfigBuilder {
 	return b
 }
 
+// WithCombinedLogFormat formats entries in the Apache Combined Log Format.
+func (b *FormatterConfigBuilder) WithCombinedLogFormat() *FormatterConfigBuilder {
+	b.config.Format = CombinedLogFormat
+	return b
+}
+
+// WithGELFFormat formats entries as GELF 1.1 JSON.
+func (b *FormatterConfigBuilder) WithGELFFormat() *FormatterConfigBuilder {
+	b.config.Format = GELFFormat
+	return b
+}
+
 func (b *FormatterConfigBuilder) IncludeFile(include bool) *FormatterConfigBuilder {
 	b.config.IncludeFile = include
 	return b
@@ -138,9 +280,12 @@ func (b *FormatterConfigBuilder) UseShortFile(useShort bool) *FormatterConfigBui
 }
 
 func (b *FormatterConfigBuilder) AddRedactPattern(pattern string) *FormatterConfigBuilder {
-	if re, err := regexp.Compile(pattern); err == nil {
-		b.config.RedactPatterns = append(b.config.RedactPatterns, re)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		reportInvalidConfig("AddRedactPattern", fmt.Sprintf("ignoring invalid pattern %q", pattern), err)
+		return b
 	}
+	b.config.RedactPatterns = append(b.config.RedactPatterns, re)
 	return b
 }
 
@@ -149,6 +294,12 @@ func (b *FormatterConfigBuilder) AddRedactRegex(re *regexp.Regexp) *FormatterCon
 	return b
 }
 
+// WithCustom sets a Formatter that replaces the built-in formatter for Format.
+func (b *FormatterConfigBuilder) WithCustom(formatter Formatter) *FormatterConfigBuilder {
+	b.config.Custom = formatter
+	return b
+}
+
 func (b *FormatterConfigBuilder) Build() *FormatterConfig {
 	return b.config
 }
@@ -172,6 +323,12 @@ func (b *OutputConfigBuilder) WithWriter(w io.Writer) *OutputConfigBuilder {
 	return b
 }
 
+// WithCustom sets an Output that receives formatted entries instead of the writer.
+func (b *OutputConfigBuilder) WithCustom(output Output) *OutputConfigBuilder {
+	b.config.Custom = output
+	return b
+}
+
 func (b *OutputConfigBuilder) Build() *OutputConfig {
 	return b.config
 }
@@ -223,11 +380,30 @@ func (b *LoggerConfigBuilder) FromEnvironment() *LoggerConfigBuilder {
 	if level := os.Getenv("LOG_LEVEL"); level != "" {
 		if l, ok := ParseLevel(level); ok {
 			b.config.Core.Level = l
+		} else {
+			reportInvalidEnv("LOG_LEVEL", level)
 		}
 	}
-	if format := os.Getenv("LOG_FORMAT"); format == jsonFormatString {
+	switch format := os.Getenv("LOG_FORMAT"); format {
+	case jsonFormatString:
 		b.config.Formatter.Format = JSONFormat
+	case autoFormatString:
+		b.config.Formatter.Format = AutoFormat
+	case gelfFormatString:
+		b.config.Formatter.Format = GELFFormat
+	case prettyFormatString:
+		b.config.Formatter.Format = PrettyFormat
+	case stackdriverFormatString:
+		b.config.Formatter.Format = StackdriverFormat
+	case textFormatString, "":
+	default:
+		if _, ok := registeredFormatter(format); ok {
+			b.WithRegisteredFormat(format)
+		} else {
+			reportInvalidEnv("LOG_FORMAT", format)
+		}
 	}
+	b.config.Core.UTC = getEnvBool("LOG_UTC", b.config.Core.UTC)
 	return b
 }
 
@@ -242,9 +418,12 @@ func (b *LoggerConfigBuilder) WithLevel(level Level) *LoggerConfigBuilder {
 }
 
 func (b *LoggerConfigBuilder) WithLevelString(level string) *LoggerConfigBuilder {
-	if l, ok := ParseLevel(level); ok {
-		b.config.Core.Level = l
+	l, ok := ParseLevel(level)
+	if !ok {
+		reportInvalidConfig("WithLevelString", fmt.Sprintf("ignoring unknown level %q", level), nil)
+		return b
 	}
+	b.config.Core.Level = l
 	return b
 }
 
@@ -263,6 +442,13 @@ func (b *LoggerConfigBuilder) WithJSONFormat() *LoggerConfigBuilder {
 	return b
 }
 
+// WithAutoFormat chooses colored console output when the output is a
+// terminal and JSON otherwise.
+func (b *LoggerConfigBuilder) WithAutoFormat() *LoggerConfigBuilder {
+	b.config.Formatter.Format = AutoFormat
+	return b
+}
+
 func (b *LoggerConfigBuilder) WithTextFormat() *LoggerConfigBuilder {
 	b.config.Formatter.Format = TextFormat
 	return b
@@ -272,3 +458,60 @@ func (b *LoggerConfigBuilder) WithCommonLogFormat() *LoggerConfigBuilder {
 	b.config.Formatter.Format = CommonLogFormat
 	return b
 }
+
+// WithCombinedLogFormat formats entries in the Apache Combined Log Format.
+func (b *LoggerConfigBuilder) WithCombinedLogFormat() *LoggerConfigBuilder {
+	b.config.Formatter.Format = CombinedLogFormat
+	return b
+}
+
+// WithGELFFormat formats entries as GELF 1.1 JSON with the machine's
+// hostname as host, for Graylog inputs reached through any output. Use
+// WithCustomFormatter(NewGELFFormatter(config, host)) to set another host.
+func (b *LoggerConfigBuilder) WithGELFFormat() *LoggerConfigBuilder {
+	b.config.Formatter.Format = GELFFormat
+	return b
+}
+
+// WithCustomFormatter sets a Formatter that replaces the built-in formatter.
+func (b *LoggerConfigBuilder) WithCustomFormatter(formatter Formatter) *LoggerConfigBuilder {
+	b.config.Formatter.Custom = formatter
+	return b
+}
+
+// WithClock sets the Clock used to timestamp entries.
+func (b *LoggerConfigBuilder) WithClock(clock Clock) *LoggerConfigBuilder {
+	b.config.Core.Clock = clock
+	return b
+}
+
+// WithUTC converts every entry timestamp to UTC, so all formatters agree
+// with JSON output.
+func (b *LoggerConfigBuilder) WithUTC() *LoggerConfigBuilder {
+	b.config.Core.UTC = true
+	return b
+}
+
+// WithCustomOutput sets an Output that receives formatted entries instead of the writer.
+func (b *LoggerConfigBuilder) WithCustomOutput(output Output) *LoggerConfigBuilder {
+	b.config.Output.Custom = output
+	return b
+}
+
+// WithSchemaVersion adds version to every entry as log_schema_version.
+func (b *LoggerConfigBuilder) WithSchemaVersion(version string) *LoggerConfigBuilder {
+	b.config.Core.SchemaVersion = version
+	return b
+}
+
+// WithFieldMigration renames a field on every entry.
+func (b *LoggerConfigBuilder) WithFieldMigration(migration FieldMigration) *LoggerConfigBuilder {
+	b.config.Core.FieldMigrations = append(b.config.Core.FieldMigrations, migration)
+	return b
+}
+
+// WithFieldEncryption encrypts the encryptor's fields on every entry.
+func (b *LoggerConfigBuilder) WithFieldEncryption(encryptor *FieldEncryptor) *LoggerConfigBuilder {
+	b.config.Core.FieldEncryptor = encryptor
+	return b
+}
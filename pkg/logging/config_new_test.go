@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -342,3 +343,23 @@ func TestLoggerConfigBuilder_WithTextFormat(t *testing.T) {
 		t.Error("expected text format")
 	}
 }
+
+func TestAutoFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithAutoFormat().WithWriter(buf).Build())
+	logger.Info("piped")
+	if !strings.HasPrefix(buf.String(), "{") {
+		t.Errorf("expected JSON when not writing to a terminal, got %q", buf.String())
+	}
+
+	// /dev/null is a character device, like a terminal.
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("cannot open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	ul := NewWithLoggerConfig(NewLoggerConfig().WithAutoFormat().WithWriter(devNull).Build()).(*unifiedLogger)
+	if _, ok := ul.formatter.(*ConsoleFormatter); !ok {
+		t.Errorf("expected ConsoleFormatter for a terminal, got %T", ul.formatter)
+	}
+}
@@ -1,22 +1,29 @@
 package logging
 
 import (
+	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Constants for YAML configuration
 const (
-	stdoutString = "stdout"
-	stderrString = "stderr"
-	fileString   = "file"
-	infoString   = "info"
+	stdoutString   = "stdout"
+	stderrString   = "stderr"
+	fileString     = "file"
+	journaldString = "journald"
+	consoleString  = "console"
+	infoString     = "info"
 )
 
 // YAMLConfig represents the complete YAML configuration structure.
@@ -24,13 +31,34 @@ type YAMLConfig struct {
 	// Core logging configuration
 	Level        string                 `yaml:"level"`
 	StaticFields map[string]interface{} `yaml:"static_fields,omitempty"`
+	UTC          bool                   `yaml:"utc,omitempty"`
+
+	// Schema versioning
+	SchemaVersion   string               `yaml:"schema_version,omitempty"`
+	FieldMigrations []YAMLFieldMigration `yaml:"field_migrations,omitempty"`
+
+	// Field encryption
+	EncryptFields *YAMLFieldEncryption `yaml:"encrypt_fields,omitempty"`
 
 	// Formatting configuration
-	Format       string   `yaml:"format"`
-	IncludeFile  bool     `yaml:"include_file"`
-	IncludeTime  bool     `yaml:"include_time"`
-	UseShortFile bool     `yaml:"use_short_file"`
-	RedactList   []string `yaml:"redact_patterns,omitempty"`
+	Format         string                    `yaml:"format"`
+	Template       string                    `yaml:"template,omitempty"` // text/template for format "template"
+	FieldKeys      *YAMLFieldKeys            `yaml:"field_keys,omitempty"`
+	TimeFormat     string                    `yaml:"time_format,omitempty"`     // Go time layout
+	TimeZone       string                    `yaml:"time_zone,omitempty"`       // "UTC", "Local" or an IANA zone
+	TimePrecision  string                    `yaml:"time_precision,omitempty"`  // "second", "millisecond", "microsecond" or "nanosecond"
+	FieldOrder     string                    `yaml:"field_order,omitempty"`     // "alphabetical" or "insertion"
+	PriorityFields []string                  `yaml:"priority_fields,omitempty"` // keys written first
+	GroupStyle     string                    `yaml:"group_style,omitempty"`     // "nested" or "dotted"
+	Theme          map[string]YAMLLevelTheme `yaml:"theme,omitempty"`           // level display names and console colors, keyed by level
+	IncludeFile    bool                      `yaml:"include_file"`
+	IncludeTime    bool                      `yaml:"include_time"`
+	UseShortFile   bool                      `yaml:"use_short_file"`
+	RedactList     []string                  `yaml:"redact_patterns,omitempty"`
+
+	// CollapseMultiline writes multi-line values, such as stack traces, on
+	// the entry's line in text and console output.
+	CollapseMultiline bool `yaml:"collapse_multiline,omitempty"`
 
 	// Output configuration
 	Output YAMLOutputConfig `yaml:"output"`
@@ -45,8 +73,41 @@ type YAMLConfig struct {
 
 // YAMLOutputConfig represents output configuration in YAML.
 type YAMLOutputConfig struct {
-	Type   string `yaml:"type"`             // "stdout", "stderr", "file"
-	Target string `yaml:"target,omitempty"` // file path for type "file"
+	Type   string `yaml:"type"`             // "stdout", "stderr", "file", "journald", "console"
+	Target string `yaml:"target,omitempty"` // file path for type "file", optional socket path for "journald"
+	// DeadLetter is a file where entries that could not be written are
+	// appended for replay.
+	DeadLetter string `yaml:"dead_letter,omitempty"`
+}
+
+// YAMLFieldKeys represents FieldKeys in YAML.
+type YAMLFieldKeys struct {
+	Timestamp string `yaml:"timestamp,omitempty"`
+	Level     string `yaml:"level,omitempty"`
+	Message   string `yaml:"message,omitempty"`
+}
+
+// YAMLLevelTheme is the display name and console color of a level in a
+// YAML theme.
+type YAMLLevelTheme struct {
+	Name  string `yaml:"name,omitempty"`
+	Color string `yaml:"color,omitempty"` // color name, e.g. "bold-red", or ANSI escape
+}
+
+// YAMLFieldMigration represents a FieldMigration in YAML.
+type YAMLFieldMigration struct {
+	Old     string `yaml:"old"`
+	New     string `yaml:"new"`
+	KeepOld bool   `yaml:"keep_old,omitempty"`
+}
+
+// YAMLFieldEncryption configures a FieldEncryptor in YAML. The key is read
+// base64-encoded from the environment variable named by KeyEnv, so it never
+// appears in the file.
+type YAMLFieldEncryption struct {
+	Fields []string `yaml:"fields"`
+	KeyEnv string   `yaml:"key_env"`
+	KeyID  string   `yaml:"key_id,omitempty"`
 }
 
 // YAMLSlogConfig represents slog-specific configuration in YAML.
@@ -86,13 +147,52 @@ func LoadFromYAML(filename string) (Logger, error) {
 // LoadFromYAMLData loads configuration from YAML data bytes.
 func LoadFromYAMLData(data []byte) (Logger, error) {
 	var yamlConfig YAMLConfig
-	if err := yaml.Unmarshal(data, &yamlConfig); err != nil {
+	if err := unmarshalYAMLConfig(data, &yamlConfig); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML configuration: %w", err)
 	}
 
 	return buildLoggerFromYAML(&yamlConfig)
 }
 
+// yamlBoolKeys are the YAMLConfig keys holding booleans.
+var yamlBoolKeys = map[string]bool{
+	"include_file":       true,
+	"include_time":       true,
+	"use_short_file":     true,
+	"collapse_multiline": true,
+	"use_slog":           true,
+	"utc":                true,
+}
+
+// unmarshalYAMLConfig decodes data into yamlConfig. In strict mode it
+// rejects unknown keys and booleans written as anything but true or false.
+func unmarshalYAMLConfig(data []byte, yamlConfig *YAMLConfig) error {
+	if !IsStrict() {
+		return yaml.Unmarshal(data, yamlConfig)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if len(root.Content) > 0 && root.Content[0].Kind == yaml.MappingNode {
+		mapping := root.Content[0].Content
+		for i := 0; i+1 < len(mapping); i += 2 {
+			key, value := mapping[i], mapping[i+1]
+			if yamlBoolKeys[key.Value] && value.Tag != "!!bool" {
+				return fmt.Errorf("line %d: %s must be true or false, got %q", value.Line, key.Value, value.Value)
+			}
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(yamlConfig); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
 // LoadFromYAMLString loads configuration from a YAML string.
 func LoadFromYAMLString(yamlStr string) (Logger, error) {
 	return LoadFromYAMLData([]byte(yamlStr))
@@ -148,15 +248,88 @@ func configureCoreFromYAML(builder *LoggerConfigBuilder, yamlConfig *YAMLConfig)
 	}
 
 	// Set static fields
-	if len(yamlConfig.StaticFields) > 0 {
-		for k, v := range yamlConfig.StaticFields {
-			builder.config.Core.StaticFields[k] = v
+	fields, err := expandStaticFields(yamlConfig.StaticFields)
+	if err != nil {
+		return err
+	}
+	for k, v := range fields {
+		builder.config.Core.StaticFields[k] = v
+	}
+
+	builder.config.Core.UTC = yamlConfig.UTC
+	builder.WithSchemaVersion(yamlConfig.SchemaVersion)
+	for _, m := range yamlConfig.FieldMigrations {
+		if m.Old == "" || m.New == "" {
+			return fmt.Errorf("field migration needs old and new names")
 		}
+		builder.WithFieldMigration(FieldMigration{Old: m.Old, New: m.New, KeepOld: m.KeepOld})
+	}
+
+	if enc := yamlConfig.EncryptFields; enc != nil {
+		key, err := base64.StdEncoding.DecodeString(os.Getenv(enc.KeyEnv))
+		if err != nil || len(key) == 0 {
+			return fmt.Errorf("encrypt_fields: %s must hold a base64-encoded key", enc.KeyEnv)
+		}
+		encryptor, err := NewFieldEncryptor(key, enc.KeyID, enc.Fields...)
+		if err != nil {
+			return fmt.Errorf("encrypt_fields: %w", err)
+		}
+		builder.WithFieldEncryption(encryptor)
 	}
 
 	return nil
 }
 
+// staticFieldFuncs are the functions available to static field templates.
+var staticFieldFuncs = template.FuncMap{
+	"env": os.Getenv,
+	"now": func() string { return time.Now().UTC().Format(time.RFC3339) },
+	"hostname": func() (string, error) {
+		return os.Hostname()
+	},
+	"default": func(fallback, value string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// expandStaticFields returns fields with templated values evaluated.
+func expandStaticFields(fields map[string]interface{}) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	expanded := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		value, err := expandStaticField(v)
+		if err != nil {
+			return nil, fmt.Errorf("static field %s: %w", k, err)
+		}
+		expanded[k] = value
+	}
+	return expanded, nil
+}
+
+// expandStaticField evaluates a static field value holding a template, such
+// as "{{ env "HOSTNAME" }}" or "{{ now }}", once at load time. Other values
+// are returned unchanged.
+func expandStaticField(value interface{}) (interface{}, error) {
+	text, ok := value.(string)
+	if !ok || !strings.Contains(text, "{{") {
+		return value, nil
+	}
+	tmpl, err := template.New("static_field").Funcs(staticFieldFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	var expanded strings.Builder
+	if err := tmpl.Execute(&expanded, nil); err != nil {
+		return nil, fmt.Errorf("failed to evaluate template: %w", err)
+	}
+	return expanded.String(), nil
+}
+
 // configureFormatterFromYAML configures formatter settings from YAML.
 func configureFormatterFromYAML(builder *LoggerConfigBuilder, yamlConfig *YAMLConfig) error {
 	// Set format
@@ -165,14 +338,71 @@ func configureFormatterFromYAML(builder *LoggerConfigBuilder, yamlConfig *YAMLCo
 		builder.WithJSONFormat()
 	case textFormatString, "":
 		builder.WithTextFormat()
+	case autoFormatString:
+		builder.WithAutoFormat()
+	case gelfFormatString:
+		builder.WithGELFFormat()
+	case prettyFormatString:
+		builder.WithPrettyFormat()
+	case stackdriverFormatString:
+		builder.WithStackdriverFormat()
+	case templateFormatString:
+		if _, err := NewTemplateFormatter(nil, yamlConfig.Template); err != nil {
+			return err
+		}
+		builder.config.Formatter.Format = TemplateFormat
+		builder.config.Formatter.Template = yamlConfig.Template
 	default:
-		return fmt.Errorf("invalid format: %s (must be 'json' or 'text')", yamlConfig.Format)
+		if _, ok := registeredFormatter(yamlConfig.Format); !ok {
+			return fmt.Errorf("invalid format: %s (must be 'json', 'text', 'auto', 'gelf', 'pretty', 'stackdriver', 'template' or a registered formatter)", yamlConfig.Format)
+		}
+		builder.WithRegisteredFormat(yamlConfig.Format)
+	}
+	if yamlConfig.Template != "" && builder.config.Formatter.Format != TemplateFormat {
+		return fmt.Errorf("template requires format: template")
 	}
 
 	// Set formatter options
 	builder.config.Formatter.IncludeFile = yamlConfig.IncludeFile
 	builder.config.Formatter.IncludeTime = yamlConfig.IncludeTime
 	builder.config.Formatter.UseShortFile = yamlConfig.UseShortFile
+	builder.CollapseMultiline(yamlConfig.CollapseMultiline)
+	if yamlConfig.FieldKeys != nil {
+		builder.WithFieldKeys(FieldKeys(*yamlConfig.FieldKeys))
+	}
+	builder.WithTimeFormat(yamlConfig.TimeFormat)
+	if yamlConfig.TimeZone != "" {
+		loc, err := parseTimeZone(yamlConfig.TimeZone)
+		if err != nil {
+			return fmt.Errorf("invalid time_zone: %w", err)
+		}
+		builder.WithTimeZone(loc)
+	}
+	if yamlConfig.TimePrecision != "" {
+		precision, ok := ParseTimePrecision(yamlConfig.TimePrecision)
+		if !ok {
+			return fmt.Errorf("invalid time_precision: %s (must be 'second', 'millisecond', 'microsecond' or 'nanosecond')", yamlConfig.TimePrecision)
+		}
+		builder.WithTimePrecision(precision)
+	}
+	order := AlphabeticalFieldOrder
+	if yamlConfig.FieldOrder != "" {
+		var ok bool
+		if order, ok = ParseFieldOrder(yamlConfig.FieldOrder); !ok {
+			return fmt.Errorf("invalid field_order: %s (must be 'alphabetical' or 'insertion')", yamlConfig.FieldOrder)
+		}
+	}
+	builder.WithFieldOrder(order, yamlConfig.PriorityFields...)
+	if yamlConfig.GroupStyle != "" {
+		style, ok := ParseGroupStyle(yamlConfig.GroupStyle)
+		if !ok {
+			return fmt.Errorf("invalid group_style: %s (must be 'nested' or 'dotted')", yamlConfig.GroupStyle)
+		}
+		builder.WithGroupStyle(style)
+	}
+	if err := configureThemeFromYAML(builder, yamlConfig.Theme); err != nil {
+		return err
+	}
 
 	// Add redact patterns
 	for _, pattern := range yamlConfig.RedactList {
@@ -201,8 +431,28 @@ func configureOutputFromYAML(builder *LoggerConfigBuilder, yamlConfig *YAMLConfi
 			return err
 		}
 		builder.WithWriter(writer)
+	case journaldString:
+		output, err := NewJournaldOutput(yamlConfig.Output.Target)
+		if err != nil {
+			return err
+		}
+		builder.WithCustomOutput(output)
+	case consoleString:
+		output, err := NewBrowserConsoleOutput()
+		if err != nil {
+			return err
+		}
+		builder.WithCustomOutput(output)
 	default:
-		return fmt.Errorf("invalid output type: %s (must be '%s', '%s', or '%s')", yamlConfig.Output.Type, stdoutString, stderrString, fileString)
+		return fmt.Errorf("invalid output type: %s (must be '%s', '%s', '%s', '%s', or '%s')", yamlConfig.Output.Type, stdoutString, stderrString, fileString, journaldString, consoleString)
+	}
+
+	if yamlConfig.Output.DeadLetter != "" {
+		dlq, err := NewDeadLetterFile(yamlConfig.Output.DeadLetter)
+		if err != nil {
+			return err
+		}
+		builder.WithWriteErrorHandler(dlq.Handle)
 	}
 
 	return nil
@@ -280,7 +530,7 @@ func applyDevelopmentPreset(yamlConfig *YAMLConfig) {
 		yamlConfig.Level = "debug"
 	}
 	if yamlConfig.Format == "" {
-		yamlConfig.Format = textFormatString
+		yamlConfig.Format = prettyFormatString
 	}
 	if yamlConfig.Output.Type == "" {
 		yamlConfig.Output.Type = stdoutString
@@ -373,6 +623,12 @@ func NewFromYAMLEnv(envVar string) Logger {
 	logger, err := LoadFromYAML(filename)
 	if err != nil {
 		// Fall back to simple logger if YAML loading fails
+		reportDiagnostic(Diagnostic{
+			Kind:    DiagnosticFallback,
+			Source:  envVar,
+			Message: "using default logger",
+			Err:     err,
+		})
 		return NewSimple()
 	}
 
@@ -389,14 +645,55 @@ func SaveToYAML(config *LoggerConfig, filename string) error {
 		IncludeTime:  config.Formatter.IncludeTime,
 		UseShortFile: config.Formatter.UseShortFile,
 		UseSlog:      config.UseSlog,
+		UTC:          config.Core.UTC,
+
+		CollapseMultiline: config.Formatter.CollapseMultiline,
+
+		SchemaVersion: config.Core.SchemaVersion,
+	}
+	for _, m := range config.Core.FieldMigrations {
+		yamlConfig.FieldMigrations = append(yamlConfig.FieldMigrations, YAMLFieldMigration(m))
 	}
 
 	// Set format
-	if config.Formatter.Format == JSONFormat {
+	switch config.Formatter.Format {
+	case JSONFormat:
 		yamlConfig.Format = jsonFormatString
-	} else {
+	case AutoFormat:
+		yamlConfig.Format = autoFormatString
+	case GELFFormat:
+		yamlConfig.Format = gelfFormatString
+	case PrettyFormat:
+		yamlConfig.Format = prettyFormatString
+	case StackdriverFormat:
+		yamlConfig.Format = stackdriverFormatString
+	case RegisteredFormat:
+		yamlConfig.Format = config.Formatter.FormatName
+	case TemplateFormat:
+		yamlConfig.Format = templateFormatString
+		yamlConfig.Template = config.Formatter.Template
+	default:
 		yamlConfig.Format = textFormatString
 	}
+	if keys := config.Formatter.Keys; keys != (FieldKeys{}) {
+		yamlKeys := YAMLFieldKeys(keys)
+		yamlConfig.FieldKeys = &yamlKeys
+	}
+	yamlConfig.TimeFormat = config.Formatter.TimeFormat
+	if config.Formatter.TimeZone != nil {
+		yamlConfig.TimeZone = config.Formatter.TimeZone.String()
+	}
+	if config.Formatter.TimePrecision != SecondPrecision {
+		yamlConfig.TimePrecision = config.Formatter.TimePrecision.String()
+	}
+	if config.Formatter.FieldOrder != AlphabeticalFieldOrder {
+		yamlConfig.FieldOrder = config.Formatter.FieldOrder.String()
+	}
+	yamlConfig.PriorityFields = config.Formatter.PriorityFields
+	if config.Formatter.GroupStyle != NestedGroups {
+		yamlConfig.GroupStyle = config.Formatter.GroupStyle.String()
+	}
+	yamlConfig.Theme = themeToYAML(config.Formatter)
 
 	// Set output (simplified - only supports stdout/stderr detection)
 	yamlConfig.Output.Type = stdoutString // Default assumption
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadFromYAMLString(t *testing.T) {
@@ -452,3 +453,73 @@ output:
 	logger.Info("Login attempt with password=secret123 and token=abc456")
 	logger.Error("API call failed with apikey=xyz789")
 }
+
+func TestYAMLJournaldOutput(t *testing.T) {
+	path, received := listenJournal(t)
+
+	logger, err := LoadFromYAMLString(`
+level: info
+output:
+  type: journald
+  target: ` + path)
+	if err != nil {
+		t.Fatalf("LoadFromYAMLString() error = %v", err)
+	}
+
+	logger.Error("from yaml")
+
+	if got := receiveJournal(t, received); !strings.Contains(got, "MESSAGE=from yaml\nPRIORITY=3\n") {
+		t.Errorf("unexpected journal entry %q", got)
+	}
+}
+
+func TestExpandStaticField(t *testing.T) {
+	t.Setenv("POD_NAME", "web-7")
+	t.Setenv("EMPTY_VAR", "")
+
+	tests := []struct {
+		value interface{}
+		want  interface{}
+	}{
+		{`{{ env "POD_NAME" }}`, "web-7"},
+		{`pod-{{ env "POD_NAME" }}`, "pod-web-7"},
+		{`{{ env "EMPTY_VAR" | default "local" }}`, "local"},
+		{"plain", "plain"},
+		{42, 42},
+	}
+	for _, tt := range tests {
+		got, err := expandStaticField(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("expandStaticField(%v) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	started, err := expandStaticField("{{ now }}")
+	if err != nil {
+		t.Fatalf("expandStaticField(now) error = %v", err)
+	}
+	if _, err := time.Parse(time.RFC3339, started.(string)); err != nil {
+		t.Errorf("expected RFC 3339 timestamp, got %v", started)
+	}
+}
+
+func TestLoadFromYAMLString_InvalidStaticFieldTemplate(t *testing.T) {
+	_, err := LoadFromYAMLString(`
+level: info
+static_fields:
+  pod: "{{ env }}"
+`)
+	if err == nil || !strings.Contains(err.Error(), "static field pod") {
+		t.Errorf("expected static field error, got %v", err)
+	}
+}
+
+func TestLoadFromYAMLString_AutoFormat(t *testing.T) {
+	logger, err := LoadFromYAMLString("format: auto\n")
+	if err != nil {
+		t.Fatalf("LoadFromYAMLString() error = %v", err)
+	}
+	if got := logger.(*unifiedLogger).config.Formatter.Format; got != JSONFormat && got != TextFormat {
+		t.Errorf("expected auto format to be resolved, got %v", got)
+	}
+}
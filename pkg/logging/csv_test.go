@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSVFormatter_Format(t *testing.T) {
+	formatter, err := NewCSVFormatter(nil, CSVConfig{
+		Columns: []string{"timestamp", "level", "message", "user_id", "request_id", "missing"},
+		Header:  true,
+	})
+	if err != nil {
+		t.Fatalf("NewCSVFormatter returned error: %v", err)
+	}
+	entry := LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 0, time.UTC),
+		Level:     InfoLevel,
+		Message:   `order "42" placed, paid`,
+		Context:   WithRequestID(context.Background(), "req-1"),
+		Fields:    map[string]interface{}{"user_id": 7, "ignored": "x"},
+	}
+
+	first, err := formatter.Format(entry)
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	want := "timestamp,level,message,user_id,request_id,missing\n" +
+		`2025-11-24T10:30:00Z,INFO,"order ""42"" placed, paid",7,req-1,` + "\n"
+	if string(first) != want {
+		t.Errorf("unexpected output\n got: %q\nwant: %q", first, want)
+	}
+
+	second, _ := formatter.Format(entry)
+	records, err := csv.NewReader(strings.NewReader(string(second))).ReadAll()
+	if err != nil || len(records) != 1 || records[0][2] != `order "42" placed, paid` {
+		t.Errorf("expected one row that reads back, got %q (%v)", records, err)
+	}
+
+	formatter.Reset()
+	third, _ := formatter.Format(entry)
+	if !strings.HasPrefix(string(third), "timestamp,") {
+		t.Errorf("expected the header after Reset, got %q", third)
+	}
+}
+
+func TestCSVFormatter_Defaults(t *testing.T) {
+	formatter, err := NewCSVFormatter(nil, CSVConfig{Comma: ';'})
+	if err != nil {
+		t.Fatalf("NewCSVFormatter returned error: %v", err)
+	}
+	data, _ := formatter.Format(LogEntry{Level: WarnLevel, Message: "a;b"})
+	if want := ";WARN;\"a;b\"\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+
+	if _, err := NewCSVFormatter(nil, CSVConfig{Comma: '"'}); err == nil {
+		t.Error("expected an error for a quote delimiter")
+	}
+}
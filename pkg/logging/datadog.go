@@ -0,0 +1,255 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// datadogMaxBatch is the most entries the logs intake accepts per request.
+const datadogMaxBatch = 1000
+
+// DatadogConfig configures a DatadogOutput.
+type DatadogConfig struct {
+	// APIKey is sent as the DD-API-KEY header.
+	APIKey string
+	// Site is the Datadog site, e.g. "datadoghq.eu". Defaults to
+	// "datadoghq.com".
+	Site string
+	// URL overrides the intake URL derived from Site.
+	URL string
+	// Service is the default service; a "service" field overrides it.
+	Service string
+	// Source is sent as ddsource. Defaults to "go".
+	Source string
+	// Hostname defaults to the machine's hostname.
+	Hostname string
+	// Tags are sent as ddtags, e.g. []string{"env:prod", "team:payments"}.
+	Tags []string
+	// Compress gzips request bodies.
+	Compress bool
+	// BatchSize is the number of entries per request. Defaults to and is
+	// capped at 1000, the intake's limit.
+	BatchSize int
+	// Linger is how long a partial batch waits for more entries. Defaults
+	// to 1s.
+	Linger time.Duration
+	// QueueSize bounds the entries waiting to be sent. Defaults to 10000.
+	QueueSize int
+	// Retry controls retries of requests that fail with network errors, 429
+	// or 5xx responses.
+	Retry RetryConfig
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the number of entries that could not be sent.
+	OnError func(entries int, err error)
+}
+
+// DatadogOutput batches entries to the Datadog logs intake. Level becomes
+// status, fields become attributes, and the context's trace ID together
+// with a span_id field become dd.trace_id and dd.span_id so entries link to
+// their APM traces. W3C and OpenTelemetry hex trace IDs are converted to
+// Datadog's 64-bit decimal form.
+//
+// Example:
+//
+//	output, err := logging.NewDatadogOutput(logging.DatadogConfig{
+//		APIKey:  os.Getenv("DD_API_KEY"),
+//		Service: "checkout",
+//		Tags:    []string{"env:prod"},
+//	})
+type DatadogOutput struct {
+	config  DatadogConfig
+	tags    string
+	batcher *batcher[json.RawMessage]
+}
+
+// NewDatadogOutput creates a DatadogOutput.
+func NewDatadogOutput(config DatadogConfig) (*DatadogOutput, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("datadog output requires an API key")
+	}
+	if config.Site == "" {
+		config.Site = "datadoghq.com"
+	}
+	if config.URL == "" {
+		config.URL = "https://http-intake.logs." + config.Site + "/api/v2/logs"
+	}
+	if config.Source == "" {
+		config.Source = "go"
+	}
+	if config.Hostname == "" {
+		config.Hostname, _ = os.Hostname()
+	}
+	if config.BatchSize <= 0 || config.BatchSize > datadogMaxBatch {
+		config.BatchSize = datadogMaxBatch
+	}
+	if config.Linger == 0 {
+		config.Linger = time.Second
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 10000
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	do := &DatadogOutput{config: config, tags: strings.Join(config.Tags, ",")}
+	do.batcher = newBatcher(batcherConfig[json.RawMessage]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     do.send,
+		OnError: func(batch []json.RawMessage, err error) {
+			do.reportError(len(batch), err)
+		},
+	})
+	return do, nil
+}
+
+// datadogStatus maps a level to a Datadog log status.
+func datadogStatus(level Level) string {
+	switch level {
+	case CriticalLevel:
+		return "critical"
+	case ErrorLevel:
+		return "error"
+	case WarnLevel:
+		return "warn"
+	case InfoLevel:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+// datadogID converts a trace or span ID to Datadog's decimal form. 128-bit
+// hex IDs and 64-bit hex IDs that aren't valid decimals keep their lower 64
+// bits; decimal IDs pass through.
+func datadogID(id string) string {
+	if _, err := strconv.ParseUint(id, 10, 64); err == nil && len(id) != 32 {
+		return id
+	}
+	if len(id) == 16 || len(id) == 32 {
+		if n, err := strconv.ParseUint(id[len(id)-16:], 16, 64); err == nil {
+			return strconv.FormatUint(n, 10)
+		}
+	}
+	return id
+}
+
+// Write queues data as the message of an INFO entry.
+func (do *DatadogOutput) Write(data []byte) error {
+	return do.WriteEntry(LogEntry{
+		Timestamp: time.Now(),
+		Level:     InfoLevel,
+		Message:   string(bytes.TrimRight(data, "\r\n")),
+	}, data)
+}
+
+// WriteEntry queues entry with its status, service and trace correlation.
+func (do *DatadogOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	log := make(map[string]interface{}, len(entry.Fields)+10)
+	for k, v := range entry.Fields {
+		log[k] = v
+	}
+	for _, name := range []string{"request_id", "correlation_id"} {
+		if _, ok := log[name]; ok {
+			continue
+		}
+		if value, ok := entryFieldString(entry, name); ok {
+			log[name] = value
+		}
+	}
+	if traceID, ok := entryFieldString(entry, "trace_id"); ok && traceID != "" {
+		log["dd.trace_id"] = datadogID(traceID)
+		delete(log, "trace_id")
+	}
+	if spanID, ok := entryFieldString(entry, "span_id"); ok && spanID != "" {
+		log["dd.span_id"] = datadogID(spanID)
+		delete(log, "span_id")
+	}
+	if _, ok := log["service"]; !ok && do.config.Service != "" {
+		log["service"] = do.config.Service
+	}
+	log["message"] = entry.Message
+	log["status"] = datadogStatus(entry.Level)
+	log["ddsource"] = do.config.Source
+	log["hostname"] = do.config.Hostname
+	log["timestamp"] = entry.Timestamp.UnixMilli()
+	if do.tags != "" {
+		log["ddtags"] = do.tags
+	}
+
+	data, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("failed to encode datadog log: %w", err)
+	}
+	return do.batcher.Submit(data)
+}
+
+// send posts logs as one JSON array.
+func (do *DatadogOutput) send(logs []json.RawMessage) error {
+	body, err := json.Marshal(logs)
+	if err != nil {
+		return fmt.Errorf("failed to encode datadog request: %w", err)
+	}
+	if do.config.Compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write(body)
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to compress datadog request: %w", err)
+		}
+		body = buf.Bytes()
+	}
+
+	err = retry(context.Background(), do.config.Retry, func() error {
+		req, err := http.NewRequest(http.MethodPost, do.config.URL, bytes.NewReader(body))
+		if err != nil {
+			return permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("DD-API-KEY", do.config.APIKey)
+		if do.config.Compress {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+
+		resp, err := do.config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		return checkHTTPResponse(resp)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send logs to datadog: %w", err)
+	}
+	return nil
+}
+
+func (do *DatadogOutput) reportError(entries int, err error) {
+	if do.config.OnError != nil {
+		do.config.OnError(entries, err)
+	}
+}
+
+// Flush sends the pending partial batch.
+func (do *DatadogOutput) Flush() error {
+	return do.batcher.Flush()
+}
+
+// Close sends queued entries.
+func (do *DatadogOutput) Close() error {
+	return do.batcher.Stop()
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewDatadogOutput_Validation(t *testing.T) {
+	if _, err := NewDatadogOutput(DatadogConfig{}); err == nil {
+		t.Error("expected error without API key")
+	}
+}
+
+func TestDatadogID(t *testing.T) {
+	tests := map[string]string{
+		"1234567890":                       "1234567890",
+		"00000000000000000000000000000010": "16",
+		"4bf92f3577b34da6a3ce929d0e0e4736": "11803532876627986230",
+		"00f067aa0ba902b7":                 "67667974448284343",
+		"not-an-id":                        "not-an-id",
+	}
+	for in, want := range tests {
+		if got := datadogID(in); got != want {
+			t.Errorf("datadogID(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDatadogOutput_Send(t *testing.T) {
+	var mu sync.Mutex
+	var logs []map[string]interface{}
+	var apiKey, encoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("expected gzip body: %v", err)
+			return
+		}
+		var batch []map[string]interface{}
+		if err := json.NewDecoder(zr).Decode(&batch); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, batch...)
+		apiKey, encoding = r.Header.Get("DD-API-KEY"), r.Header.Get("Content-Encoding")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	output, err := NewDatadogOutput(DatadogConfig{
+		APIKey:   "key",
+		URL:      server.URL,
+		Service:  "checkout",
+		Hostname: "web-1",
+		Tags:     []string{"env:prod", "team:payments"},
+		Compress: true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create datadog output: %v", err)
+	}
+
+	ctx := WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	_ = output.WriteEntry(LogEntry{
+		Timestamp: time.UnixMilli(1700000000123),
+		Level:     ErrorLevel,
+		Message:   "payment declined",
+		Fields:    map[string]interface{}{"span_id": "00f067aa0ba902b7", "order": "o-1"},
+		Context:   ctx,
+	}, nil)
+	_ = output.WriteEntry(LogEntry{
+		Level:   WarnLevel,
+		Message: "from worker",
+		Fields:  map[string]interface{}{"service": "worker"},
+	}, nil)
+	if err := output.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if apiKey != "key" || encoding != "gzip" {
+		t.Errorf("unexpected headers: key %q, encoding %q", apiKey, encoding)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(logs))
+	}
+	want := map[string]interface{}{
+		"message":     "payment declined",
+		"status":      "error",
+		"service":     "checkout",
+		"ddsource":    "go",
+		"hostname":    "web-1",
+		"ddtags":      "env:prod,team:payments",
+		"dd.trace_id": "11803532876627986230",
+		"dd.span_id":  "67667974448284343",
+		"order":       "o-1",
+		"timestamp":   float64(1700000000123),
+	}
+	for k, v := range want {
+		if logs[0][k] != v {
+			t.Errorf("%s = %v, want %v", k, logs[0][k], v)
+		}
+	}
+	if _, ok := logs[0]["trace_id"]; ok {
+		t.Error("expected trace_id to be replaced by dd.trace_id")
+	}
+	if logs[1]["service"] != "worker" || logs[1]["status"] != "warn" {
+		t.Errorf("expected the service field to override the default: %v", logs[1])
+	}
+}
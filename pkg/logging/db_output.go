@@ -0,0 +1,264 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dbColumns are the columns DBOutput inserts, in order.
+var dbColumns = []string{"timestamp", "level", "message", "fields", "trace_id"}
+
+// dbIdentifier matches the table names DBOutput accepts.
+var dbIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// DBConfig configures a DBOutput.
+type DBConfig struct {
+	// Dialect selects placeholders and column types: "sqlite" (the default),
+	// "postgres" or "mysql".
+	Dialect string
+	// Table defaults to "logs".
+	Table string
+	// BatchSize is the number of rows per INSERT. Defaults to 100.
+	BatchSize int
+	// Linger is how long a partial batch waits for more rows. Defaults to 1s.
+	Linger time.Duration
+	// QueueSize bounds the rows waiting to be inserted. Defaults to 10000.
+	QueueSize int
+	// Retry controls retries of failed inserts.
+	Retry RetryConfig
+	// OnError is called with the number of entries that could not be
+	// inserted.
+	OnError func(entries int, err error)
+}
+
+// dbRow is a queued row.
+type dbRow struct {
+	timestamp time.Time
+	level     string
+	message   string
+	fields    string
+	traceID   interface{}
+}
+
+// DBOutput inserts entries into a SQL table through database/sql, in
+// batched multi-row INSERTs, so embedded tools and small services keep
+// queryable logs in SQLite or an existing Postgres or MySQL database. Fields
+// are stored as a JSON object, and the trace ID from the fields or context
+// gets its own column. Bring your own driver; call Migrate once to create
+// the table.
+//
+// Example:
+//
+//	db, _ := sql.Open("sqlite", "app-logs.db")
+//	output, err := logging.NewDBOutput(db, logging.DBConfig{})
+//	if err == nil {
+//		err = output.Migrate(ctx)
+//	}
+//	// SELECT message FROM logs WHERE level = 'ERROR' AND trace_id = ?
+type DBOutput struct {
+	db      *sql.DB
+	config  DBConfig
+	batcher *batcher[dbRow]
+}
+
+// NewDBOutput creates a DBOutput writing to db.
+func NewDBOutput(db *sql.DB, config DBConfig) (*DBOutput, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db output requires a database")
+	}
+	if config.Dialect == "" {
+		config.Dialect = "sqlite"
+	}
+	switch config.Dialect {
+	case "sqlite", "postgres", "mysql":
+	default:
+		return nil, fmt.Errorf("unsupported db dialect %q", config.Dialect)
+	}
+	if config.Table == "" {
+		config.Table = "logs"
+	}
+	if !dbIdentifier.MatchString(config.Table) {
+		return nil, fmt.Errorf("invalid db table name %q", config.Table)
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Linger == 0 {
+		config.Linger = time.Second
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 10000
+	}
+
+	dbo := &DBOutput{db: db, config: config}
+	dbo.batcher = newBatcher(batcherConfig[dbRow]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     dbo.insert,
+		OnError: func(rows []dbRow, err error) {
+			dbo.reportError(len(rows), err)
+		},
+	})
+	return dbo, nil
+}
+
+// Schema returns the statements Migrate runs for the output's dialect.
+func (dbo *DBOutput) Schema() []string {
+	table := dbo.config.Table
+	index := strings.ReplaceAll(table, ".", "_")
+
+	var create string
+	switch dbo.config.Dialect {
+	case "postgres":
+		create = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    id BIGSERIAL PRIMARY KEY,
+    timestamp TIMESTAMPTZ NOT NULL,
+    level TEXT NOT NULL,
+    message TEXT NOT NULL,
+    fields JSONB,
+    trace_id TEXT
+)`, table)
+	case "mysql":
+		create = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    timestamp DATETIME(6) NOT NULL,
+    level VARCHAR(16) NOT NULL,
+    message TEXT NOT NULL,
+    fields JSON,
+    trace_id VARCHAR(64),
+    INDEX %s_timestamp_idx (timestamp),
+    INDEX %s_trace_id_idx (trace_id)
+)`, table, index, index)
+		return []string{create}
+	default:
+		create = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    timestamp TIMESTAMP NOT NULL,
+    level TEXT NOT NULL,
+    message TEXT NOT NULL,
+    fields TEXT,
+    trace_id TEXT
+)`, table)
+	}
+	return []string{
+		create,
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_timestamp_idx ON %s (timestamp)", index, table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_trace_id_idx ON %s (trace_id)", index, table),
+	}
+}
+
+// Migrate creates the table and its indexes if they don't exist. It is
+// safe to call on every start.
+func (dbo *DBOutput) Migrate(ctx context.Context) error {
+	for _, stmt := range dbo.Schema() {
+		if _, err := dbo.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to migrate log table %s: %w", dbo.config.Table, err)
+		}
+	}
+	return nil
+}
+
+// Write queues data as the message of an INFO row.
+func (dbo *DBOutput) Write(data []byte) error {
+	return dbo.WriteEntry(LogEntry{
+		Timestamp: time.Now(),
+		Level:     InfoLevel,
+		Message:   string(bytes.TrimRight(data, "\r\n")),
+	}, data)
+}
+
+// WriteEntry queues entry as a row. The formatted data is not used.
+func (dbo *DBOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	fields := copyFields(entry.Fields, 2)
+	delete(fields, "trace_id")
+	for _, name := range []string{"request_id", "correlation_id"} {
+		if _, ok := fields[name]; ok {
+			continue
+		}
+		if value, ok := entryFieldString(entry, name); ok {
+			fields[name] = value
+		}
+	}
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode log fields: %w", err)
+	}
+
+	row := dbRow{
+		timestamp: entry.Timestamp.UTC(),
+		level:     entry.Level.String(),
+		message:   entry.Message,
+		fields:    string(encoded),
+	}
+	if traceID, ok := entryFieldString(entry, "trace_id"); ok && traceID != "" {
+		row.traceID = traceID
+	}
+	return dbo.batcher.Submit(row)
+}
+
+// insert writes rows with one multi-row INSERT.
+func (dbo *DBOutput) insert(rows []dbRow) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES ", dbo.config.Table, strings.Join(dbColumns, ", "))
+	args := make([]interface{}, 0, len(rows)*len(dbColumns))
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for j := range dbColumns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(dbo.placeholder(len(args) + j + 1))
+		}
+		b.WriteByte(')')
+		args = append(args, row.timestamp, row.level, row.message, row.fields, row.traceID)
+	}
+	query := b.String()
+
+	err := retry(context.Background(), dbo.config.Retry, func() error {
+		_, err := dbo.db.ExecContext(context.Background(), query, args...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert logs into %s: %w", dbo.config.Table, err)
+	}
+	return nil
+}
+
+// placeholder returns the n-th bind parameter for the dialect.
+func (dbo *DBOutput) placeholder(n int) string {
+	if dbo.config.Dialect == "postgres" {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+func (dbo *DBOutput) reportError(entries int, err error) {
+	if dbo.config.OnError != nil {
+		dbo.config.OnError(entries, err)
+	}
+}
+
+// Flush inserts the pending partial batch.
+func (dbo *DBOutput) Flush() error {
+	return dbo.batcher.Flush()
+}
+
+// Close inserts queued entries. The database is not closed.
+func (dbo *DBOutput) Close() error {
+	return dbo.batcher.Stop()
+}
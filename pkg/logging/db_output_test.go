@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeDB records the statements executed through the "logging-fake" driver.
+type fakeDB struct {
+	mu    sync.Mutex
+	execs []fakeExec
+	err   error
+}
+
+type fakeExec struct {
+	query string
+	args  []driver.NamedValue
+}
+
+var fakeDBs sync.Map
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	db, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, errors.New("unknown fake database")
+	}
+	return &fakeConn{db: db.(*fakeDB)}, nil
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if c.db.err != nil {
+		return nil, c.db.err
+	}
+	c.db.execs = append(c.db.execs, fakeExec{query: query, args: args})
+	return driver.RowsAffected(1), nil
+}
+
+func init() {
+	sql.Register("logging-fake", fakeDriver{})
+}
+
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDB) {
+	t.Helper()
+	fake := &fakeDB{}
+	fakeDBs.Store(t.Name(), fake)
+	db, err := sql.Open("logging-fake", t.Name())
+	if err != nil {
+		t.Fatalf("failed to open fake database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fake
+}
+
+func TestNewDBOutput_Validation(t *testing.T) {
+	db, _ := openFakeDB(t)
+	if _, err := NewDBOutput(nil, DBConfig{}); err == nil {
+		t.Error("expected error without a database")
+	}
+	if _, err := NewDBOutput(db, DBConfig{Dialect: "oracle"}); err == nil {
+		t.Error("expected error for an unsupported dialect")
+	}
+	if _, err := NewDBOutput(db, DBConfig{Table: "logs; DROP TABLE users"}); err == nil {
+		t.Error("expected error for an invalid table name")
+	}
+}
+
+func TestDBOutput_Migrate(t *testing.T) {
+	db, fake := openFakeDB(t)
+	output, _ := NewDBOutput(db, DBConfig{Dialect: "postgres", Table: "app.logs"})
+	defer output.Close()
+
+	if err := output.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate returned error: %v", err)
+	}
+	if len(fake.execs) != 3 {
+		t.Fatalf("expected 3 statements, got %d", len(fake.execs))
+	}
+	if !strings.Contains(fake.execs[0].query, "CREATE TABLE IF NOT EXISTS app.logs") || !strings.Contains(fake.execs[0].query, "JSONB") {
+		t.Errorf("unexpected create statement %q", fake.execs[0].query)
+	}
+	if !strings.Contains(fake.execs[1].query, "app_logs_timestamp_idx ON app.logs") {
+		t.Errorf("unexpected index statement %q", fake.execs[1].query)
+	}
+
+	fake.err = errors.New("permission denied")
+	if err := output.Migrate(context.Background()); err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("expected the migration error, got %v", err)
+	}
+}
+
+func TestDBOutput_Insert(t *testing.T) {
+	db, fake := openFakeDB(t)
+	output, _ := NewDBOutput(db, DBConfig{Dialect: "postgres"})
+
+	ctx := WithRequestID(WithTraceID(context.Background(), "trace-1"), "req-1")
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	_ = output.WriteEntry(LogEntry{Timestamp: ts, Level: ErrorLevel, Message: "failed", Fields: map[string]interface{}{"order": "o-1"}, Context: ctx}, nil)
+	_ = output.WriteEntry(LogEntry{Timestamp: ts, Level: InfoLevel, Message: "ok"}, nil)
+	if err := output.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if len(fake.execs) != 1 {
+		t.Fatalf("expected one batched insert, got %d", len(fake.execs))
+	}
+	exec := fake.execs[0]
+	want := "INSERT INTO logs (timestamp, level, message, fields, trace_id) VALUES ($1, $2, $3, $4, $5), ($6, $7, $8, $9, $10)"
+	if exec.query != want {
+		t.Errorf("query = %q, want %q", exec.query, want)
+	}
+	if len(exec.args) != 10 {
+		t.Fatalf("expected 10 args, got %d", len(exec.args))
+	}
+	if exec.args[0].Value != ts || exec.args[1].Value != "ERROR" || exec.args[2].Value != "failed" || exec.args[4].Value != "trace-1" {
+		t.Errorf("unexpected first row %v", exec.args[:5])
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(exec.args[3].Value.(string)), &fields); err != nil || fields["order"] != "o-1" || fields["request_id"] != "req-1" {
+		t.Errorf("unexpected fields %v", exec.args[3].Value)
+	}
+	if exec.args[9].Value != nil {
+		t.Errorf("expected a NULL trace ID, got %v", exec.args[9].Value)
+	}
+}
+
+func TestDBOutput_InsertError(t *testing.T) {
+	db, fake := openFakeDB(t)
+	fake.err = errors.New("disk full")
+
+	var failed int
+	output, _ := NewDBOutput(db, DBConfig{
+		Retry:   RetryConfig{MaxRetries: -1},
+		OnError: func(entries int, err error) { failed += entries },
+	})
+	_ = output.Write([]byte("line\n"))
+	_ = output.Close()
+
+	if failed != 1 {
+		t.Errorf("expected 1 failed entry, got %d", failed)
+	}
+}
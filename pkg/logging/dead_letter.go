@@ -0,0 +1,237 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WriteErrorHandler is called with a formatted entry that could not be
+// written and the error. entry is nil when the entry could not be formatted,
+// or when a custom slog handler failed.
+type WriteErrorHandler func(entry []byte, err error)
+
+// WithWriteErrorHandler sets the function called when an entry cannot be
+// formatted or written. Without one, such errors are ignored.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithWriteErrorHandler(func(entry []byte, err error) {
+//			writeErrors.Inc()
+//		}).
+//		Build()
+func (b *LoggerConfigBuilder) WithWriteErrorHandler(handler WriteErrorHandler) *LoggerConfigBuilder {
+	b.config.Output.OnWriteError = handler
+	return b
+}
+
+// WithDeadLetterFile appends entries that cannot be written to path, in
+// addition to any WithWriteErrorHandler, so they can be replayed later with
+// ReplayDeadLetters. A file that cannot be opened is reported as a
+// diagnostic.
+func (b *LoggerConfigBuilder) WithDeadLetterFile(path string) *LoggerConfigBuilder {
+	dlq, err := NewDeadLetterFile(path)
+	if err != nil {
+		reportInvalidConfig("WithDeadLetterFile", fmt.Sprintf("ignoring dead-letter file %q", path), err)
+		return b
+	}
+	b.config.Output.OnWriteError = chainWriteErrorHandlers(b.config.Output.OnWriteError, dlq.Handle)
+	return b
+}
+
+// WithWriteErrorHandler sets the function called when an entry cannot be
+// formatted or written.
+func (b *OutputConfigBuilder) WithWriteErrorHandler(handler WriteErrorHandler) *OutputConfigBuilder {
+	b.config.OnWriteError = handler
+	return b
+}
+
+// chainWriteErrorHandlers returns a handler calling first, if set, then
+// second.
+func chainWriteErrorHandlers(first, second WriteErrorHandler) WriteErrorHandler {
+	if first == nil {
+		return second
+	}
+	return func(entry []byte, err error) {
+		first(entry, err)
+		second(entry, err)
+	}
+}
+
+// writeErrorWriter reports failed writes to a WriteErrorHandler. It wraps
+// the writer of the default slog handlers, which write one entry per call.
+type writeErrorWriter struct {
+	w       io.Writer
+	onError WriteErrorHandler
+}
+
+func (w *writeErrorWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if err != nil {
+		w.onError(append([]byte(nil), p...), err)
+	}
+	return n, err
+}
+
+// DeadLetterFile appends entries that could not be written to a file, one
+// per line, for replay once the output recovers. Use its Handle method as a
+// WriteErrorHandler, or WithDeadLetterFile.
+//
+// Example:
+//
+//	dlq, err := logging.NewDeadLetterFile("/var/lib/myapp/logs.dead")
+//	...
+//	config := logging.NewLoggerConfig().
+//		WithCustomOutput(httpOutput).
+//		WithWriteErrorHandler(dlq.Handle).
+//		Build()
+//
+//	// Later, e.g. at startup:
+//	replayed, err := dlq.Replay(httpOutput)
+type DeadLetterFile struct {
+	path string
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewDeadLetterFile opens path for appending, creating it and its directory
+// if needed.
+func NewDeadLetterFile(path string) (*DeadLetterFile, error) {
+	path, err := expandHomePath(path)
+	if err != nil {
+		return nil, err
+	}
+	d := &DeadLetterFile{path: path}
+	if err := d.open(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *DeadLetterFile) open() error {
+	if err := createLogDirectory(d.path); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(d.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter file: %w", err)
+	}
+	d.file = file
+	return nil
+}
+
+// Path returns the file's path.
+func (d *DeadLetterFile) Path() string {
+	return d.path
+}
+
+// Handle appends entry to the file. Entries without formatted data are
+// skipped since there is nothing to replay.
+func (d *DeadLetterFile) Handle(entry []byte, _ error) {
+	if len(entry) == 0 {
+		return
+	}
+	line := entry
+	if line[len(line)-1] != '\n' {
+		line = append(line[:len(line):len(line)], '\n')
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.file != nil {
+		_, _ = d.file.Write(line)
+	}
+}
+
+// Replay writes the dead-lettered entries to output in order and removes
+// them from the file. It stops at the first failure, keeping that entry and
+// the ones after it.
+func (d *DeadLetterFile) Replay(output Output) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file != nil {
+		if err := d.file.Close(); err != nil {
+			return 0, fmt.Errorf("failed to close dead-letter file: %w", err)
+		}
+		d.file = nil
+	}
+	replayed, err := ReplayDeadLetters(d.path, output)
+	if openErr := d.open(); openErr != nil && err == nil {
+		err = openErr
+	}
+	return replayed, err
+}
+
+// Close closes the file.
+func (d *DeadLetterFile) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.file == nil {
+		return nil
+	}
+	err := d.file.Close()
+	d.file = nil
+	return err
+}
+
+// ReplayDeadLetters writes the entries in the dead-letter file at path to
+// output in order and returns how many were written. Written entries are
+// removed from the file; on failure the remaining ones are kept for the
+// next attempt. A missing file replays nothing. The file must not be in use
+// by a DeadLetterFile; call its Replay method instead.
+func ReplayDeadLetters(path string, output Output) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read dead-letter file: %w", err)
+	}
+
+	replayed, offset := 0, 0
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if err := output.Write(line); err != nil {
+			if rewriteErr := rewriteFile(path, data[offset:]); rewriteErr != nil {
+				return replayed, rewriteErr
+			}
+			return replayed, fmt.Errorf("failed to replay dead-lettered entry: %w", err)
+		}
+		replayed++
+		offset += len(line)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return replayed, fmt.Errorf("failed to remove dead-letter file: %w", err)
+	}
+	return replayed, nil
+}
+
+// rewriteFile atomically replaces the contents of path with data.
+func rewriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to rewrite dead-letter file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to rewrite dead-letter file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to rewrite dead-letter file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to rewrite dead-letter file: %w", err)
+	}
+	return nil
+}
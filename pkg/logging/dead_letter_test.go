@@ -0,0 +1,166 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingOutput fails every write while failing is set.
+type failingOutput struct {
+	failing bool
+	written [][]byte
+}
+
+func (fo *failingOutput) Write(data []byte) error {
+	if fo.failing {
+		return errors.New("output unavailable")
+	}
+	fo.written = append(fo.written, append([]byte(nil), data...))
+	return nil
+}
+
+func (fo *failingOutput) Close() error { return nil }
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWithWriteErrorHandler(t *testing.T) {
+	var entries [][]byte
+	var errs []error
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithCustomOutput(&failingOutput{failing: true}).
+		WithWriteErrorHandler(func(entry []byte, err error) {
+			entries = append(entries, entry)
+			errs = append(errs, err)
+		}).
+		Build())
+
+	logger.Info("lost message")
+
+	if len(entries) != 1 || !strings.Contains(string(entries[0]), `"message":"lost message"`) {
+		t.Fatalf("expected the formatted entry to be reported, got %q", entries)
+	}
+	if errs[0] == nil || errs[0].Error() != "output unavailable" {
+		t.Errorf("unexpected error %v", errs[0])
+	}
+}
+
+func TestWithWriteErrorHandler_Slog(t *testing.T) {
+	var reported []byte
+	config := NewLoggerConfig().
+		WithJSONFormat().
+		WithWriter(failingWriter{}).
+		WithWriteErrorHandler(func(entry []byte, err error) { reported = entry }).
+		Build()
+	config.UseSlog = true
+
+	NewWithLoggerConfig(config).Error("slog entry")
+
+	if !strings.Contains(string(reported), `"msg":"slog entry"`) {
+		t.Errorf("expected the slog entry to be reported, got %q", reported)
+	}
+}
+
+func TestDeadLetterFile_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool", "logs.dead")
+	output := &failingOutput{failing: true}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithCustomOutput(output).
+		WithDeadLetterFile(path).
+		Build())
+
+	logger.Info("first")
+	logger.Warn("second")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected a dead-letter file: %v", err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != 2 {
+		t.Fatalf("expected 2 dead-lettered entries, got %d: %s", lines, data)
+	}
+
+	// Still failing: nothing is lost.
+	if replayed, err := ReplayDeadLetters(path, output); err == nil || replayed != 0 {
+		t.Errorf("expected replay to fail, got %d, %v", replayed, err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, data) {
+		t.Errorf("expected the entries to be kept, got %s", after)
+	}
+
+	output.failing = false
+	replayed, err := ReplayDeadLetters(path, output)
+	if err != nil || replayed != 2 {
+		t.Fatalf("expected 2 entries replayed, got %d, %v", replayed, err)
+	}
+	if !strings.Contains(string(output.written[0]), `"message":"first"`) || !strings.Contains(string(output.written[1]), `"message":"second"`) {
+		t.Errorf("expected entries replayed in order, got %q", output.written)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the dead-letter file to be removed after a full replay")
+	}
+}
+
+// flakyOutput accepts the given number of writes and then fails.
+type flakyOutput struct {
+	remaining int
+	written   int
+}
+
+func (fo *flakyOutput) Write(data []byte) error {
+	if fo.remaining == 0 {
+		return errors.New("output unavailable")
+	}
+	fo.remaining--
+	fo.written++
+	return nil
+}
+
+func (fo *flakyOutput) Close() error { return nil }
+
+func TestDeadLetterFile_PartialReplay(t *testing.T) {
+	dlq, err := NewDeadLetterFile(filepath.Join(t.TempDir(), "logs.dead"))
+	if err != nil {
+		t.Fatalf("failed to create dead-letter file: %v", err)
+	}
+	defer dlq.Close()
+
+	dlq.Handle([]byte("one"), nil)
+	dlq.Handle([]byte("two\n"), nil)
+	dlq.Handle([]byte("three\n"), nil)
+	dlq.Handle(nil, errors.New("format failed"))
+
+	replayed, err := dlq.Replay(&flakyOutput{remaining: 1})
+	if err == nil || replayed != 1 {
+		t.Fatalf("expected 1 entry replayed before the failure, got %d, %v", replayed, err)
+	}
+	if data, _ := os.ReadFile(dlq.Path()); string(data) != "two\nthree\n" {
+		t.Errorf("expected the unreplayed entries to remain, got %q", data)
+	}
+
+	dlq.Handle([]byte("four\n"), nil)
+	output := &flakyOutput{remaining: 10}
+	if replayed, err := dlq.Replay(output); err != nil || replayed != 3 {
+		t.Errorf("expected the remaining 3 entries replayed, got %d, %v", replayed, err)
+	}
+}
+
+func TestYAMLConfig_DeadLetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.dead")
+	logger, err := LoadFromYAMLString("level: info\nformat: json\noutput:\n  type: stdout\n  dead_letter: " + path + "\n")
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if ul, ok := logger.(*unifiedLogger); !ok || ul.config.Output.OnWriteError == nil {
+		t.Error("expected dead_letter to install a write error handler")
+	}
+}
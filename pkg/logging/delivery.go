@@ -0,0 +1,682 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSpoolFull is returned by AtLeastOnceOutput when accepting an entry
+// would grow the spool beyond MaxSize.
+var ErrSpoolFull = errors.New("delivery spool is full")
+
+// DeliveryRecord is a spooled entry awaiting acknowledgement.
+type DeliveryRecord struct {
+	// Key is an idempotency key, unique per record and unchanged when the
+	// record is redelivered, so receivers can drop duplicates.
+	Key string
+	// Data is the formatted entry.
+	Data []byte
+}
+
+// DeliverySink delivers records synchronously. Returning nil acknowledges
+// every record in the batch; any other error causes redelivery unless it
+// was wrapped with RejectDelivery.
+type DeliverySink interface {
+	Deliver(ctx context.Context, records []DeliveryRecord) error
+}
+
+// DeliverySinkFunc adapts a function to DeliverySink.
+type DeliverySinkFunc func(ctx context.Context, records []DeliveryRecord) error
+
+// Deliver calls f.
+func (f DeliverySinkFunc) Deliver(ctx context.Context, records []DeliveryRecord) error {
+	return f(ctx, records)
+}
+
+// RejectDelivery marks err as a permanent rejection, such as a 4xx
+// response: the batch is reported to OnError and removed from the spool
+// instead of being redelivered forever.
+func RejectDelivery(err error) error {
+	return permanent(err)
+}
+
+// AtLeastOnceConfig configures an AtLeastOnceOutput.
+type AtLeastOnceConfig struct {
+	// BatchSize is the most records passed to one Deliver call. Defaults
+	// to 100.
+	BatchSize int
+	// Linger is how long a partial batch waits for more records. Defaults
+	// to 1s.
+	Linger time.Duration
+	// SegmentSize is the size at which a new spool file is started.
+	// Defaults to 4MiB.
+	SegmentSize int64
+	// MaxSize bounds the spool on disk. Writes beyond it fail with
+	// ErrSpoolFull. Defaults to 1GiB.
+	MaxSize int64
+	// Sync fsyncs every write and acknowledgement, so spooled entries
+	// survive power loss and not just process crashes.
+	Sync bool
+	// Retry controls retries of a failed batch before OnError is called.
+	// Unacknowledged batches are retried again after MaxBackoff.
+	Retry RetryConfig
+	// DrainTimeout bounds how long Close keeps delivering. Entries still
+	// spooled are delivered after the next restart. Defaults to 5s.
+	DrainTimeout time.Duration
+	// OnError is called when a batch fails after its retries or is
+	// rejected.
+	OnError func(records []DeliveryRecord, err error)
+}
+
+// spoolSegment is a spool file holding records from sequence first on.
+type spoolSegment struct {
+	first uint64
+	path  string
+	size  int64
+}
+
+// spoolCursor is the position of a record within the spool.
+type spoolCursor struct {
+	seq     uint64
+	segment uint64
+	offset  int64
+}
+
+// AtLeastOnceOutput spools entries to disk and removes them only after a
+// DeliverySink acknowledges them, so entries survive sink outages and
+// process restarts. Every record carries an idempotency key, because a
+// crash between delivery and acknowledgement redelivers the batch.
+//
+// Example:
+//
+//	sink := logging.NewEnvelopeSink(logging.EnvelopeSinkConfig{
+//		URL: "http://log-relay:8080/ingest",
+//	})
+//	output, err := logging.NewAtLeastOnceOutput("/var/spool/myapp", sink, logging.AtLeastOnceConfig{})
+//	if err != nil {
+//		return err
+//	}
+//	defer output.Close()
+type AtLeastOnceOutput struct {
+	dir    string
+	id     string
+	sink   DeliverySink
+	config AtLeastOnceConfig
+
+	mu       sync.Mutex
+	segments []spoolSegment
+	file     *os.File
+	size     int64
+	nextSeq  uint64
+	acked    uint64
+	closed   bool
+	cursor   spoolCursor
+	notify   chan struct{}
+	closing  chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	finished chan struct{}
+}
+
+// NewAtLeastOnceOutput opens or creates the spool in dir and starts
+// delivering any entries left from a previous run.
+func NewAtLeastOnceOutput(dir string, sink DeliverySink, config AtLeastOnceConfig) (*AtLeastOnceOutput, error) {
+	if sink == nil {
+		return nil, fmt.Errorf("delivery sink is required")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Linger <= 0 {
+		config.Linger = time.Second
+	}
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = 4 << 20
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = 1 << 30
+	}
+	if config.DrainTimeout <= 0 {
+		config.DrainTimeout = 5 * time.Second
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	ao := &AtLeastOnceOutput{
+		dir:      dir,
+		sink:     sink,
+		config:   config,
+		notify:   make(chan struct{}, 1),
+		closing:  make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	if err := ao.open(); err != nil {
+		return nil, err
+	}
+	ao.ctx, ao.cancel = context.WithCancel(context.Background())
+	go ao.run()
+	return ao, nil
+}
+
+// open loads the spool ID, acknowledged sequence and segments, truncating
+// a record left incomplete by a crash.
+func (ao *AtLeastOnceOutput) open() error {
+	id, err := os.ReadFile(filepath.Join(ao.dir, "id"))
+	switch {
+	case err == nil:
+		ao.id = strings.TrimSpace(string(id))
+	case errors.Is(err, os.ErrNotExist):
+		ao.id = strconv.FormatUint(rand.Uint64(), 36)
+		if err := ao.writeFile("id", ao.id); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("failed to read spool ID: %w", err)
+	}
+
+	if acked, err := os.ReadFile(filepath.Join(ao.dir, "acked")); err == nil {
+		ao.acked, err = strconv.ParseUint(strings.TrimSpace(string(acked)), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse spool acknowledgement: %w", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read spool acknowledgement: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(ao.dir, "segment-*.spool"))
+	if err != nil {
+		return fmt.Errorf("failed to list spool segments: %w", err)
+	}
+	for _, path := range paths {
+		var first uint64
+		if _, err := fmt.Sscanf(filepath.Base(path), "segment-%d.spool", &first); err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat spool segment: %w", err)
+		}
+		ao.segments = append(ao.segments, spoolSegment{first: first, path: path, size: info.Size()})
+		ao.size += info.Size()
+	}
+	sort.Slice(ao.segments, func(i, j int) bool { return ao.segments[i].first < ao.segments[j].first })
+
+	ao.nextSeq = ao.acked + 1
+	if n := len(ao.segments); n > 0 {
+		last := &ao.segments[n-1]
+		count, valid, err := scanSpoolSegment(last.path)
+		if err != nil {
+			return err
+		}
+		if valid < last.size {
+			if err := os.Truncate(last.path, valid); err != nil {
+				return fmt.Errorf("failed to truncate spool segment: %w", err)
+			}
+			ao.size -= last.size - valid
+			last.size = valid
+		}
+		ao.nextSeq = max(ao.nextSeq, last.first+count)
+		if ao.file, err = os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0o644); err != nil {
+			return fmt.Errorf("failed to open spool segment: %w", err)
+		}
+	}
+	ao.removeAcked()
+
+	ao.cursor = spoolCursor{seq: ao.acked + 1}
+	if len(ao.segments) > 0 && ao.segments[0].first > ao.cursor.seq {
+		ao.cursor.seq = ao.segments[0].first
+	}
+	return ao.seekCursor()
+}
+
+// seekCursor positions the cursor at the record with its sequence number.
+func (ao *AtLeastOnceOutput) seekCursor() error {
+	seg, ok := ao.segmentFor(ao.cursor.seq)
+	if !ok {
+		ao.cursor.segment, ao.cursor.offset = ao.cursor.seq, 0
+		return nil
+	}
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return fmt.Errorf("failed to open spool segment: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	offset := int64(0)
+	for seq := seg.first; seq < ao.cursor.seq; seq++ {
+		_, n, err := readSpoolRecord(r)
+		if err != nil {
+			break
+		}
+		offset += n
+	}
+	ao.cursor.segment, ao.cursor.offset = seg.first, offset
+	return nil
+}
+
+// segmentFor returns the segment holding seq.
+func (ao *AtLeastOnceOutput) segmentFor(seq uint64) (spoolSegment, bool) {
+	for i := len(ao.segments) - 1; i >= 0; i-- {
+		if ao.segments[i].first <= seq {
+			return ao.segments[i], true
+		}
+	}
+	return spoolSegment{}, false
+}
+
+// Write spools data with a new idempotency key.
+func (ao *AtLeastOnceOutput) Write(data []byte) error {
+	var header [binary.MaxVarintLen64 + 4]byte
+	n := binary.PutUvarint(header[:], uint64(len(data)))
+	binary.BigEndian.PutUint32(header[n:], crc32.Checksum(data, envelopeCRC))
+	record := append(header[:n+4:n+4], data...)
+
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+
+	if ao.closed {
+		return fmt.Errorf("at-least-once output is closed")
+	}
+	if ao.size+int64(len(record)) > ao.config.MaxSize {
+		return ErrSpoolFull
+	}
+	if ao.file == nil || ao.segments[len(ao.segments)-1].size >= ao.config.SegmentSize {
+		if err := ao.rotate(); err != nil {
+			return err
+		}
+	}
+	if _, err := ao.file.Write(record); err != nil {
+		ao.truncateTail()
+		return fmt.Errorf("failed to write spool segment: %w", err)
+	}
+	if ao.config.Sync {
+		if err := ao.file.Sync(); err != nil {
+			ao.truncateTail()
+			return fmt.Errorf("failed to sync spool segment: %w", err)
+		}
+	}
+	ao.segments[len(ao.segments)-1].size += int64(len(record))
+	ao.size += int64(len(record))
+	ao.nextSeq++
+
+	select {
+	case ao.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// truncateTail cuts the current segment back to its recorded size,
+// removing a record that was only partly written. It must be called with
+// mu held.
+func (ao *AtLeastOnceOutput) truncateTail() {
+	_ = ao.file.Truncate(ao.segments[len(ao.segments)-1].size)
+}
+
+// rotate starts a new segment at the next sequence number.
+func (ao *AtLeastOnceOutput) rotate() error {
+	if ao.file != nil {
+		if err := ao.file.Close(); err != nil {
+			return fmt.Errorf("failed to close spool segment: %w", err)
+		}
+	}
+	path := filepath.Join(ao.dir, fmt.Sprintf("segment-%020d.spool", ao.nextSeq))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		ao.file = nil
+		return fmt.Errorf("failed to create spool segment: %w", err)
+	}
+	ao.file = f
+	ao.segments = append(ao.segments, spoolSegment{first: ao.nextSeq, path: path})
+	return nil
+}
+
+// Pending returns the number of entries not yet acknowledged.
+func (ao *AtLeastOnceOutput) Pending() int {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+	return int(ao.nextSeq - 1 - ao.acked)
+}
+
+// run delivers batches until Close finishes draining.
+func (ao *AtLeastOnceOutput) run() {
+	defer close(ao.finished)
+
+	closing := ao.closing
+	for {
+		if ao.Pending() == 0 {
+			if closing == nil {
+				return
+			}
+			select {
+			case <-ao.notify:
+			case <-closing:
+				closing = nil
+				continue
+			}
+		}
+		if closing != nil && ao.Pending() < ao.config.BatchSize {
+			timer := time.NewTimer(ao.config.Linger)
+			select {
+			case <-timer.C:
+			case <-closing:
+				closing = nil
+			}
+			timer.Stop()
+		}
+
+		if !ao.deliverBatch() {
+			select {
+			case <-time.After(ao.config.Retry.withDefaults().MaxBackoff):
+			case <-closing:
+				closing = nil
+			case <-ao.ctx.Done():
+			}
+		}
+		if ao.ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// deliverBatch delivers the records at the cursor and acknowledges them.
+// It returns false if the batch must be redelivered later.
+func (ao *AtLeastOnceOutput) deliverBatch() bool {
+	records, next, err := ao.readBatch()
+	if err != nil {
+		ao.reportError(nil, err)
+		return false
+	}
+	if len(records) == 0 {
+		return ao.ack(next) == nil
+	}
+
+	var rejected bool
+	err = retry(ao.ctx, ao.config.Retry, func() error {
+		err := ao.sink.Deliver(ao.ctx, records)
+		var perm *permanentError
+		rejected = errors.As(err, &perm)
+		return err
+	})
+	if err != nil {
+		ao.reportError(records, fmt.Errorf("failed to deliver spooled entries: %w", err))
+		if !rejected {
+			return false
+		}
+	}
+
+	if err := ao.ack(next); err != nil {
+		ao.reportError(nil, err)
+		return false
+	}
+	return true
+}
+
+// readBatch reads up to BatchSize records at the cursor and returns them
+// with the cursor that follows them.
+func (ao *AtLeastOnceOutput) readBatch() ([]DeliveryRecord, spoolCursor, error) {
+	ao.mu.Lock()
+	cursor := ao.cursor
+	end := ao.nextSeq
+	segments := append([]spoolSegment(nil), ao.segments...)
+	ao.mu.Unlock()
+
+	var records []DeliveryRecord
+	for i, seg := range segments {
+		if cursor.seq >= end || len(records) >= ao.config.BatchSize {
+			break
+		}
+		segEnd := end
+		if i+1 < len(segments) {
+			segEnd = segments[i+1].first
+		}
+		if seg.first < cursor.segment {
+			continue
+		}
+		if seg.first > cursor.segment {
+			cursor = spoolCursor{seq: seg.first, segment: seg.first}
+		}
+		var err error
+		if records, cursor, err = ao.readSegment(seg, cursor, segEnd, records); err != nil {
+			return nil, cursor, err
+		}
+	}
+	return records, cursor, nil
+}
+
+// readSegment appends records from seg, starting at cursor, to records. end
+// is the sequence number following the segment's last record. A corrupt
+// record skips the rest of the segment, which is reported as lost, and
+// moves the cursor to end so later keys stay in step with their records.
+func (ao *AtLeastOnceOutput) readSegment(seg spoolSegment, cursor spoolCursor, end uint64, records []DeliveryRecord) ([]DeliveryRecord, spoolCursor, error) {
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return records, cursor, fmt.Errorf("failed to open spool segment: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(io.NewSectionReader(f, cursor.offset, seg.size-cursor.offset))
+	for cursor.seq < end && len(records) < ao.config.BatchSize && cursor.offset < seg.size {
+		data, n, err := readSpoolRecord(r)
+		if err != nil {
+			err = fmt.Errorf("failed to read spool segment %s: %w", seg.path, err)
+			ao.reportError(nil, err)
+			notifyDiagnostic(Diagnostic{
+				Kind:    DiagnosticDataLoss,
+				Source:  "AtLeastOnceOutput",
+				Message: fmt.Sprintf("skipping %d corrupt spooled entries", end-cursor.seq),
+				Err:     err,
+			})
+			cursor.seq = end
+			cursor.offset = seg.size
+			break
+		}
+		records = append(records, DeliveryRecord{Key: ao.key(cursor.seq), Data: data})
+		cursor.seq++
+		cursor.offset += n
+	}
+	return records, cursor, nil
+}
+
+// key returns the idempotency key of the record with sequence number seq.
+func (ao *AtLeastOnceOutput) key(seq uint64) string {
+	return ao.id + "-" + strconv.FormatUint(seq, 10)
+}
+
+// ack records that every entry before next was delivered and removes
+// fully acknowledged segments.
+func (ao *AtLeastOnceOutput) ack(next spoolCursor) error {
+	if err := ao.writeFile("acked", strconv.FormatUint(next.seq-1, 10)); err != nil {
+		return err
+	}
+
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+	ao.acked = next.seq - 1
+	ao.cursor = next
+	ao.removeAcked()
+	return nil
+}
+
+// removeAcked deletes segments whose records are all acknowledged, except
+// the one being written. It must be called with mu held.
+func (ao *AtLeastOnceOutput) removeAcked() {
+	for len(ao.segments) > 1 && ao.segments[1].first <= ao.acked+1 {
+		if err := os.Remove(ao.segments[0].path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		ao.size -= ao.segments[0].size
+		ao.segments = ao.segments[1:]
+	}
+}
+
+// writeFile atomically replaces name in the spool directory.
+func (ao *AtLeastOnceOutput) writeFile(name, content string) error {
+	path := filepath.Join(ao.dir, name)
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to write spool %s: %w", name, err)
+	}
+	_, err = f.WriteString(content + "\n")
+	if err == nil && ao.config.Sync {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write spool %s: %w", name, err)
+	}
+	return nil
+}
+
+func (ao *AtLeastOnceOutput) reportError(records []DeliveryRecord, err error) {
+	if ao.config.OnError != nil {
+		ao.config.OnError(records, err)
+	}
+}
+
+// Close stops accepting entries and delivers what it can within
+// DrainTimeout. Undelivered entries stay in the spool.
+func (ao *AtLeastOnceOutput) Close() error {
+	ao.mu.Lock()
+	if ao.closed {
+		ao.mu.Unlock()
+		return nil
+	}
+	ao.closed = true
+	ao.mu.Unlock()
+
+	close(ao.closing)
+	timer := time.AfterFunc(ao.config.DrainTimeout, ao.cancel)
+	<-ao.finished
+	timer.Stop()
+	ao.cancel()
+
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+	if ao.file != nil {
+		err := ao.file.Close()
+		ao.file = nil
+		return err
+	}
+	return nil
+}
+
+// readSpoolRecord reads one length-prefixed, checksummed record and
+// returns it with its encoded size.
+func readSpoolRecord(r *bufio.Reader) ([]byte, int64, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	var sum [4]byte
+	if _, err := io.ReadFull(r, sum[:]); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+	if crc32.Checksum(data, envelopeCRC) != binary.BigEndian.Uint32(sum[:]) {
+		return nil, 0, fmt.Errorf("spool record checksum mismatch")
+	}
+	var prefix [binary.MaxVarintLen64]byte
+	return data, int64(binary.PutUvarint(prefix[:], length)) + 4 + int64(length), nil
+}
+
+// scanSpoolSegment counts the intact records in a segment and returns the
+// size they occupy.
+func scanSpoolSegment(path string) (uint64, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open spool segment: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var count uint64
+	var size int64
+	for {
+		_, n, err := readSpoolRecord(r)
+		if err != nil {
+			return count, size, nil
+		}
+		count++
+		size += n
+	}
+}
+
+// EnvelopeSinkConfig configures a sink created by NewEnvelopeSink.
+type EnvelopeSinkConfig struct {
+	// URL is the ingest endpoint, typically served by NewIngestHandler.
+	URL string
+	// Compress gzips envelope payloads.
+	Compress bool
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+}
+
+// NewEnvelopeSink returns a DeliverySink that POSTs each batch as a keyed
+// envelope and treats a 2xx response as the acknowledgement. Every record
+// carries its idempotency key, so NewIngestHandler drops records it already
+// wrote however a redelivered batch is split. 4xx responses other than 429
+// reject the batch.
+func NewEnvelopeSink(config EnvelopeSinkConfig) DeliverySink {
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return DeliverySinkFunc(func(ctx context.Context, records []DeliveryRecord) error {
+		var body bytes.Buffer
+		if err := WriteKeyedEnvelope(&body, records, config.Compress); err != nil {
+			return RejectDelivery(err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, &body)
+		if err != nil {
+			return RejectDelivery(err)
+		}
+		req.Header.Set("Content-Type", EnvelopeContentType)
+
+		resp, err := config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		return checkHTTPResponse(resp)
+	})
+}
+
+// NewKafkaSink returns a DeliverySink that produces each record to topic
+// with its idempotency key as the message key. A nil error from Produce is
+// the acknowledgement, so the producer should wait for broker acks.
+func NewKafkaSink(producer KafkaProducer, topic string) DeliverySink {
+	return DeliverySinkFunc(func(ctx context.Context, records []DeliveryRecord) error {
+		batch := KafkaBatch{Topic: topic, Messages: make([]KafkaMessage, len(records))}
+		now := time.Now()
+		for i, record := range records {
+			batch.Messages[i] = KafkaMessage{Key: []byte(record.Key), Value: record.Data, Time: now}
+		}
+		return producer.Produce(ctx, batch)
+	})
+}
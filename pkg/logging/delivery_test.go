@@ -0,0 +1,348 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink collects delivered records and fails while err is set.
+type recordingSink struct {
+	mu      sync.Mutex
+	records []DeliveryRecord
+	err     error
+}
+
+func (s *recordingSink) Deliver(_ context.Context, records []DeliveryRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *recordingSink) snapshot() []DeliveryRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]DeliveryRecord(nil), s.records...)
+}
+
+func waitForPending(t *testing.T, output *AtLeastOnceOutput, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for output.Pending() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d pending entries, got %d", want, output.Pending())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func fastDelivery() AtLeastOnceConfig {
+	return AtLeastOnceConfig{
+		BatchSize:    3,
+		Linger:       time.Millisecond,
+		SegmentSize:  64,
+		Retry:        RetryConfig{MaxRetries: -1, MaxBackoff: 10 * time.Millisecond},
+		DrainTimeout: 100 * time.Millisecond,
+	}
+}
+
+func TestNewAtLeastOnceOutput_Validation(t *testing.T) {
+	if _, err := NewAtLeastOnceOutput(t.TempDir(), nil, AtLeastOnceConfig{}); err == nil {
+		t.Error("expected error without sink")
+	}
+}
+
+func TestAtLeastOnceOutput_DeliversAndAcknowledges(t *testing.T) {
+	dir := t.TempDir()
+	sink := &recordingSink{}
+	output, err := NewAtLeastOnceOutput(dir, sink, fastDelivery())
+	if err != nil {
+		t.Fatalf("NewAtLeastOnceOutput returned error: %v", err)
+	}
+	defer output.Close()
+
+	for i := 0; i < 10; i++ {
+		if err := output.Write([]byte(fmt.Sprintf("entry number %d\n", i))); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	waitForPending(t, output, 0)
+
+	records := sink.snapshot()
+	if len(records) != 10 {
+		t.Fatalf("expected 10 records, got %d", len(records))
+	}
+	keys := make(map[string]bool)
+	for i, record := range records {
+		if string(record.Data) != fmt.Sprintf("entry number %d\n", i) {
+			t.Errorf("record %d = %q", i, record.Data)
+		}
+		if keys[record.Key] {
+			t.Errorf("duplicate key %s", record.Key)
+		}
+		keys[record.Key] = true
+	}
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "segment-*.spool"))
+	if len(segments) != 1 {
+		t.Errorf("expected acknowledged segments to be removed, found %d", len(segments))
+	}
+}
+
+func TestAtLeastOnceOutput_RedeliversAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	sink := &recordingSink{err: errors.New("sink down")}
+	var failures int
+	var mu sync.Mutex
+	config := fastDelivery()
+	config.OnError = func([]DeliveryRecord, error) {
+		mu.Lock()
+		failures++
+		mu.Unlock()
+	}
+
+	output, err := NewAtLeastOnceOutput(dir, sink, config)
+	if err != nil {
+		t.Fatalf("NewAtLeastOnceOutput returned error: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		_ = output.Write([]byte(fmt.Sprintf("e%d", i)))
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if output.Pending() != 5 {
+		t.Fatalf("expected 5 undelivered entries, got %d", output.Pending())
+	}
+	mu.Lock()
+	if failures == 0 {
+		t.Error("expected OnError for failed deliveries")
+	}
+	mu.Unlock()
+
+	// A torn write at the tail must not block the remaining entries.
+	segments, _ := filepath.Glob(filepath.Join(dir, "segment-*.spool"))
+	f, err := os.OpenFile(segments[len(segments)-1], os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("failed to open segment: %v", err)
+	}
+	_, _ = f.Write([]byte{0x20, 1, 2})
+	_ = f.Close()
+
+	sink.err = nil
+	config.OnError = nil
+	restarted, err := NewAtLeastOnceOutput(dir, sink, config)
+	if err != nil {
+		t.Fatalf("NewAtLeastOnceOutput returned error on restart: %v", err)
+	}
+	defer restarted.Close()
+	waitForPending(t, restarted, 0)
+
+	records := sink.snapshot()
+	if len(records) != 5 {
+		t.Fatalf("expected 5 redelivered records, got %d", len(records))
+	}
+	if string(records[4].Data) != "e4" {
+		t.Errorf("unexpected last record %q", records[4].Data)
+	}
+
+	_ = restarted.Write([]byte("e5"))
+	waitForPending(t, restarted, 0)
+	records = sink.snapshot()
+	if len(records) != 6 || records[5].Key == records[4].Key {
+		t.Errorf("expected a new key for a new entry, got %v", records)
+	}
+}
+
+func TestAtLeastOnceOutput_SkipsCorruptRecords(t *testing.T) {
+	dir := t.TempDir()
+	config := fastDelivery()
+	output, err := NewAtLeastOnceOutput(dir, &recordingSink{err: errors.New("sink down")}, config)
+	if err != nil {
+		t.Fatalf("NewAtLeastOnceOutput returned error: %v", err)
+	}
+	// 13-byte records: five fill a 64-byte segment.
+	for i := 0; i < 12; i++ {
+		_ = output.Write([]byte(fmt.Sprintf("entry-%02d", i)))
+	}
+	_ = output.Close()
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "segment-*.spool"))
+	if len(segments) != 3 {
+		t.Fatalf("expected 3 segments, got %d", len(segments))
+	}
+	corrupt := func(segment string, offset int) {
+		data, _ := os.ReadFile(segment)
+		data[offset] ^= 0xff
+		_ = os.WriteFile(segment, data, 0o644)
+	}
+	corrupt(segments[0], 2*13+6) // inside the third record
+
+	var lost []Diagnostic
+	var mu sync.Mutex
+	defer SetDiagnosticsHandler(SetDiagnosticsHandler(func(d Diagnostic) {
+		mu.Lock()
+		lost = append(lost, d)
+		mu.Unlock()
+	}))
+
+	sink := &recordingSink{}
+	gate := make(chan struct{})
+	gated := DeliverySinkFunc(func(ctx context.Context, records []DeliveryRecord) error {
+		<-gate
+		return sink.Deliver(ctx, records)
+	})
+	restarted, err := NewAtLeastOnceOutput(dir, gated, config)
+	if err != nil {
+		t.Fatalf("NewAtLeastOnceOutput returned error on restart: %v", err)
+	}
+	defer restarted.Close()
+	// The segment being written to is only checked on open, so corrupt it
+	// afterwards, while the first batch waits.
+	corrupt(segments[2], 6)
+	close(gate)
+	waitForPending(t, restarted, 0)
+
+	_ = restarted.Write([]byte("entry-12"))
+	waitForPending(t, restarted, 0)
+
+	records := sink.snapshot()
+	if len(records) != 8 {
+		t.Fatalf("expected 8 records around the corrupt ones, got %d", len(records))
+	}
+	if string(records[1].Data) != "entry-01" || string(records[2].Data) != "entry-05" {
+		t.Errorf("unexpected records %q and %q", records[1].Data, records[2].Data)
+	}
+	if !strings.HasSuffix(records[2].Key, "-6") || !strings.HasSuffix(records[7].Key, "-13") {
+		t.Errorf("expected keys in step with sequence numbers, got %s and %s", records[2].Key, records[7].Key)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(lost) != 2 || lost[0].Kind != DiagnosticDataLoss {
+		t.Errorf("expected one data loss diagnostic, got %v", lost)
+	}
+}
+
+func TestAtLeastOnceOutput_Rejected(t *testing.T) {
+	rejected := make(chan int, 10)
+	config := fastDelivery()
+	config.OnError = func(records []DeliveryRecord, err error) {
+		rejected <- len(records)
+	}
+	sink := DeliverySinkFunc(func(context.Context, []DeliveryRecord) error {
+		return RejectDelivery(errors.New("400 Bad Request"))
+	})
+
+	output, err := NewAtLeastOnceOutput(t.TempDir(), sink, config)
+	if err != nil {
+		t.Fatalf("NewAtLeastOnceOutput returned error: %v", err)
+	}
+	defer output.Close()
+
+	_ = output.Write([]byte("bad"))
+	waitForPending(t, output, 0)
+	if n := <-rejected; n != 1 {
+		t.Errorf("expected 1 rejected record, got %d", n)
+	}
+}
+
+func TestAtLeastOnceOutput_SpoolFull(t *testing.T) {
+	config := fastDelivery()
+	config.MaxSize = 20
+	output, err := NewAtLeastOnceOutput(t.TempDir(), &recordingSink{err: errors.New("down")}, config)
+	if err != nil {
+		t.Fatalf("NewAtLeastOnceOutput returned error: %v", err)
+	}
+	defer output.Close()
+
+	if err := output.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := output.Write([]byte("0123456789")); !errors.Is(err, ErrSpoolFull) {
+		t.Errorf("expected ErrSpoolFull, got %v", err)
+	}
+}
+
+func TestEnvelopeSink(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	var received [][]byte
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		env, err := ReadEnvelope(r.Body)
+		if err != nil {
+			t.Errorf("invalid envelope: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		keys = env.Keys
+		received = env.Records
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewEnvelopeSink(EnvelopeSinkConfig{URL: server.URL, Compress: true})
+	records := []DeliveryRecord{{Key: "s-1", Data: []byte("a")}, {Key: "s-2", Data: []byte("b")}}
+	if err := sink.Deliver(context.Background(), records); err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+	mu.Lock()
+	if len(keys) != 2 || keys[1] != "s-2" || len(received) != 2 || string(received[1]) != "b" {
+		t.Errorf("unexpected delivery: keys %q, records %q", keys, received)
+	}
+	status = http.StatusBadRequest
+	mu.Unlock()
+
+	err := sink.Deliver(context.Background(), records)
+	var perm *permanentError
+	if !errors.As(err, &perm) {
+		t.Errorf("expected a 400 response to reject the batch, got %v", err)
+	}
+}
+
+func TestEnvelopeSink_IngestDropsRedeliveredRecords(t *testing.T) {
+	out := &bytes.Buffer{}
+	server := httptest.NewServer(NewIngestHandler(NewWriterOutput(out)))
+	defer server.Close()
+
+	sink := NewEnvelopeSink(EnvelopeSinkConfig{URL: server.URL})
+	first := []DeliveryRecord{{Key: "s-1", Data: []byte("a\n")}, {Key: "s-2", Data: []byte("b\n")}}
+	// A redelivery after a restart may cut the batch differently.
+	second := []DeliveryRecord{{Key: "s-2", Data: []byte("b\n")}, {Key: "s-3", Data: []byte("c\n")}}
+	for _, batch := range [][]DeliveryRecord{first, second, first} {
+		if err := sink.Deliver(context.Background(), batch); err != nil {
+			t.Fatalf("Deliver returned error: %v", err)
+		}
+	}
+	if out.String() != "a\nb\nc\n" {
+		t.Errorf("expected each record once, got %q", out.String())
+	}
+}
+
+func TestKafkaSink(t *testing.T) {
+	producer := &fakeKafkaProducer{}
+	sink := NewKafkaSink(producer, "logs")
+	if err := sink.Deliver(context.Background(), []DeliveryRecord{{Key: "s-7", Data: []byte("x")}}); err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+	batches := producer.snapshot()
+	if len(batches) != 1 || batches[0].Topic != "logs" {
+		t.Fatalf("unexpected batches: %v", batches)
+	}
+	if msg := batches[0].Messages[0]; string(msg.Key) != "s-7" || string(msg.Value) != "x" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
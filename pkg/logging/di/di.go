@@ -0,0 +1,83 @@
+// Package di provides dependency injection wiring for the logging package.
+//
+// It connects configuration, the redactor chain, the logger and the HTTP
+// tracing middleware so that adopting a DI framework is a single import.
+// Wire users reference the provider sets directly; Uber fx and dig accept
+// the same constructors through Constructors, without this package
+// depending on either framework.
+//
+// Wire:
+//
+//	func InitializeLogger() logging.Logger {
+//		wire.Build(di.WireSet)
+//		return nil
+//	}
+//
+// fx:
+//
+//	app := fx.New(fx.Provide(di.Constructors()...))
+//
+// dig:
+//
+//	c := dig.New()
+//	for _, constructor := range di.Constructors() {
+//		if err := c.Provide(constructor); err != nil {
+//			return err
+//		}
+//	}
+package di
+
+import (
+	"net/http"
+
+	"github.com/google/wire"
+	"github.com/ocrosby/go-logging/pkg/logging"
+)
+
+// HTTPMiddleware is the logging middleware provided for HTTP servers. It is a
+// distinct type so injectors can tell it apart from other middleware.
+type HTTPMiddleware func(http.Handler) http.Handler
+
+// ProvideHTTPMiddleware returns the tracing middleware for logger.
+func ProvideHTTPMiddleware(logger logging.Logger) HTTPMiddleware {
+	return HTTPMiddleware(logging.TracingMiddleware(logger))
+}
+
+// WireSet provides *logging.LoggerConfig, logging.RedactorChainInterface,
+// logging.Logger and HTTPMiddleware, with configuration read from the
+// environment.
+var WireSet = wire.NewSet(
+	logging.ProvideLoggerConfig,
+	logging.ProvideRedactorChainFromLoggerConfig,
+	logging.ProvideLoggerFromConfig,
+	ProvideHTTPMiddleware,
+)
+
+// LoggerSet provides the redactor chain, logger and middleware from a
+// *logging.LoggerConfig supplied by the application.
+var LoggerSet = wire.NewSet(
+	logging.ProvideRedactorChainFromLoggerConfig,
+	logging.ProvideLoggerFromConfig,
+	ProvideHTTPMiddleware,
+)
+
+// Constructors returns the constructors behind WireSet for use with fx.Provide
+// or dig.Container.Provide.
+func Constructors() []interface{} {
+	return []interface{}{
+		logging.ProvideLoggerConfig,
+		logging.ProvideRedactorChainFromLoggerConfig,
+		logging.ProvideLoggerFromConfig,
+		ProvideHTTPMiddleware,
+	}
+}
+
+// LoggerConstructors returns the constructors behind LoggerSet, for
+// applications that provide their own *logging.LoggerConfig.
+func LoggerConstructors() []interface{} {
+	return []interface{}{
+		logging.ProvideRedactorChainFromLoggerConfig,
+		logging.ProvideLoggerFromConfig,
+		ProvideHTTPMiddleware,
+	}
+}
@@ -0,0 +1,62 @@
+package di_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ocrosby/go-logging/pkg/logging"
+	"github.com/ocrosby/go-logging/pkg/logging/di"
+)
+
+// assertResolvable checks that every constructor's parameters are produced
+// by a constructor earlier in the list, as fx and dig would require.
+func assertResolvable(t *testing.T, constructors []interface{}, provided ...reflect.Type) {
+	t.Helper()
+
+	available := make(map[reflect.Type]bool)
+	for _, typ := range provided {
+		available[typ] = true
+	}
+
+	for _, c := range constructors {
+		fn := reflect.TypeOf(c)
+		if fn.Kind() != reflect.Func {
+			t.Fatalf("constructor %v is not a function", fn)
+		}
+		for i := 0; i < fn.NumIn(); i++ {
+			if !available[fn.In(i)] {
+				t.Errorf("%v: no provider for parameter %v", fn, fn.In(i))
+			}
+		}
+		for i := 0; i < fn.NumOut(); i++ {
+			available[fn.Out(i)] = true
+		}
+	}
+}
+
+func TestConstructors_Resolvable(t *testing.T) {
+	assertResolvable(t, di.Constructors())
+	assertResolvable(t, di.LoggerConstructors(), reflect.TypeOf(&logging.LoggerConfig{}))
+}
+
+func TestProvideHTTPMiddleware(t *testing.T) {
+	config := logging.NewLoggerConfig().WithWriter(io.Discard).Build()
+	logger := logging.ProvideLoggerFromConfig(config, logging.ProvideRedactorChainFromLoggerConfig(config))
+	middleware := di.ProvideHTTPMiddleware(logger)
+
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := logging.GetTraceID(r.Context()); !ok {
+			t.Error("expected trace ID in request context")
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Header().Get(logging.HeaderTraceID) == "" {
+		t.Error("expected trace ID response header")
+	}
+}
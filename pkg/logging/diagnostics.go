@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// DiagnosticKind classifies a problem the library reports about its own use.
+type DiagnosticKind string
+
+const (
+	// DiagnosticInvalidConfig reports configuration input that was ignored,
+	// such as an invalid redact pattern or level name.
+	DiagnosticInvalidConfig DiagnosticKind = "invalid_config"
+	// DiagnosticInvalidEnv reports an environment variable with an unknown value.
+	DiagnosticInvalidEnv DiagnosticKind = "invalid_env"
+	// DiagnosticFallback reports that the library fell back to a default
+	// after an error, such as a YAML file that could not be loaded.
+	DiagnosticFallback DiagnosticKind = "fallback"
+	// DiagnosticDeprecated reports use of a deprecated API. Each API is
+	// reported once per process.
+	DiagnosticDeprecated DiagnosticKind = "deprecated"
+	// DiagnosticDataLoss reports entries the library had to discard, such
+	// as corrupt records in an AtLeastOnceOutput spool.
+	DiagnosticDataLoss DiagnosticKind = "data_loss"
+)
+
+// Diagnostic is a warning about how the library is being used.
+type Diagnostic struct {
+	Kind DiagnosticKind
+	// Source names the API or setting involved, e.g. "AddRedactPattern".
+	Source  string
+	Message string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// String formats the diagnostic as a single line.
+func (d Diagnostic) String() string {
+	s := fmt.Sprintf("go-logging: %s: %s: %s", d.Kind, d.Source, d.Message)
+	if d.Err != nil {
+		s += ": " + d.Err.Error()
+	}
+	return s
+}
+
+// DiagnosticsHandler receives library diagnostics. It must be safe for
+// concurrent use.
+type DiagnosticsHandler func(Diagnostic)
+
+// WriterDiagnosticsHandler returns a handler that writes each diagnostic as a
+// line to w.
+func WriterDiagnosticsHandler(w io.Writer) DiagnosticsHandler {
+	var mu sync.Mutex
+	return func(d Diagnostic) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintln(w, d.String())
+	}
+}
+
+// ConfigError is the panic value raised in strict mode when configuration
+// input would otherwise be ignored.
+type ConfigError struct {
+	Diagnostic Diagnostic
+}
+
+// Error returns the diagnostic as a single line.
+func (e *ConfigError) Error() string {
+	return e.Diagnostic.String()
+}
+
+// Unwrap returns the underlying error of the diagnostic, if any.
+func (e *ConfigError) Unwrap() error {
+	return e.Diagnostic.Err
+}
+
+var (
+	diagnosticsHandler atomic.Pointer[DiagnosticsHandler]
+	reportedDeprecated sync.Map
+	strictMode         atomic.Bool
+)
+
+func init() {
+	SetDiagnosticsHandler(WriterDiagnosticsHandler(os.Stderr))
+	SetStrict(getEnvBool("LOG_STRICT", false))
+}
+
+// SetStrict enables or disables strict mode and returns the previous
+// setting. In strict mode, configuration the library would otherwise ignore
+// fails immediately: builders such as AddRedactPattern and WithLevelString
+// panic with a *ConfigError, unknown LOG_* environment values panic when
+// read, NewFromYAMLEnv panics instead of falling back, and YAML loaders
+// reject unknown keys and non-literal booleans like "yes" or "on". Strict
+// mode is meant for development and tests; it can also be enabled with
+// LOG_STRICT=true.
+//
+// Example:
+//
+//	func TestMain(m *testing.M) {
+//		logging.SetStrict(true)
+//		os.Exit(m.Run())
+//	}
+func SetStrict(strict bool) bool {
+	return strictMode.Swap(strict)
+}
+
+// IsStrict reports whether strict mode is enabled.
+func IsStrict() bool {
+	return strictMode.Load()
+}
+
+// SetDiagnosticsHandler sets where the library reports its own warnings and
+// returns the previous handler. The default writes to standard error; pass
+// nil to discard diagnostics.
+//
+// Example:
+//
+//	logging.SetDiagnosticsHandler(func(d logging.Diagnostic) {
+//		appLogger.Warn("%s", d)
+//	})
+func SetDiagnosticsHandler(handler DiagnosticsHandler) DiagnosticsHandler {
+	if handler == nil {
+		handler = func(Diagnostic) {}
+	}
+	previous := diagnosticsHandler.Swap(&handler)
+	if previous == nil {
+		return nil
+	}
+	return *previous
+}
+
+// reportDiagnostic sends d to the current handler. In strict mode, anything
+// but a deprecation notice then panics with a *ConfigError.
+func reportDiagnostic(d Diagnostic) {
+	notifyDiagnostic(d)
+	if d.Kind != DiagnosticDeprecated && IsStrict() {
+		panic(&ConfigError{Diagnostic: d})
+	}
+}
+
+// notifyDiagnostic sends d to the current handler without the strict-mode
+// panic, for paths such as signal handling that must carry on regardless.
+func notifyDiagnostic(d Diagnostic) {
+	if handler := diagnosticsHandler.Load(); handler != nil {
+		(*handler)(d)
+	}
+}
+
+// reportInvalidConfig reports configuration input that was ignored.
+func reportInvalidConfig(source, message string, err error) {
+	reportDiagnostic(Diagnostic{Kind: DiagnosticInvalidConfig, Source: source, Message: message, Err: err})
+}
+
+// reportInvalidEnv reports an environment variable with an unknown value.
+func reportInvalidEnv(name, value string) {
+	reportDiagnostic(Diagnostic{
+		Kind:    DiagnosticInvalidEnv,
+		Source:  name,
+		Message: fmt.Sprintf("ignoring unknown value %q", value),
+	})
+}
+
+// reportDeprecated reports the first use of a deprecated API.
+func reportDeprecated(api, replacement string) {
+	if _, loaded := reportedDeprecated.LoadOrStore(api, true); loaded {
+		return
+	}
+	reportDiagnostic(Diagnostic{
+		Kind:    DiagnosticDeprecated,
+		Source:  api,
+		Message: "deprecated, use " + replacement,
+	})
+}
@@ -0,0 +1,172 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// captureDiagnostics installs a recording handler for the duration of t.
+func captureDiagnostics(t *testing.T) func() []Diagnostic {
+	t.Helper()
+	var mu sync.Mutex
+	var got []Diagnostic
+	previous := SetDiagnosticsHandler(func(d Diagnostic) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, d)
+	})
+	t.Cleanup(func() { SetDiagnosticsHandler(previous) })
+
+	return func() []Diagnostic {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Diagnostic(nil), got...)
+	}
+}
+
+func TestDiagnostics_InvalidConfig(t *testing.T) {
+	diagnostics := captureDiagnostics(t)
+
+	NewFormatterConfig().AddRedactPattern("(")
+	NewLoggerConfig().WithLevelString("verbose")
+
+	got := diagnostics()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %d: %v", len(got), got)
+	}
+	if got[0].Kind != DiagnosticInvalidConfig || got[0].Source != "AddRedactPattern" || got[0].Err == nil {
+		t.Errorf("unexpected diagnostic: %+v", got[0])
+	}
+	if got[1].Source != "WithLevelString" || !strings.Contains(got[1].Message, "verbose") {
+		t.Errorf("unexpected diagnostic: %+v", got[1])
+	}
+}
+
+func TestDiagnostics_InvalidEnv(t *testing.T) {
+	diagnostics := captureDiagnostics(t)
+	t.Setenv("LOG_LEVEL", "loud")
+	t.Setenv("LOG_FORMAT", "xml")
+
+	NewLoggerConfig().FromEnvironment().Build()
+
+	got := diagnostics()
+	if len(got) != 2 || got[0].Kind != DiagnosticInvalidEnv || got[0].Source != "LOG_LEVEL" || got[1].Source != "LOG_FORMAT" {
+		t.Errorf("unexpected diagnostics: %v", got)
+	}
+}
+
+func TestDiagnostics_DeprecatedReportedOnce(t *testing.T) {
+	diagnostics := captureDiagnostics(t)
+	reportedDeprecated.Delete("NewStandardLogger")
+
+	config := NewConfig().WithOutput(&bytes.Buffer{}).Build()
+	NewStandardLogger(config, nil)
+	NewStandardLogger(config, nil)
+
+	got := diagnostics()
+	if len(got) != 1 || got[0].Kind != DiagnosticDeprecated {
+		t.Errorf("expected one deprecation diagnostic, got %v", got)
+	}
+}
+
+func TestSetDiagnosticsHandler_NilDiscards(t *testing.T) {
+	captureDiagnostics(t)
+	SetDiagnosticsHandler(nil)
+
+	// Must not panic.
+	NewFormatterConfig().AddRedactPattern("(")
+}
+
+func TestWriterDiagnosticsHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	WriterDiagnosticsHandler(buf)(Diagnostic{Kind: DiagnosticFallback, Source: "LOG_CONFIG", Message: "using default logger"})
+
+	if got := buf.String(); got != "go-logging: fallback: LOG_CONFIG: using default logger\n" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+// enableStrict turns on strict mode for the duration of t.
+func enableStrict(t *testing.T) {
+	t.Helper()
+	captureDiagnostics(t)
+	previous := SetStrict(true)
+	t.Cleanup(func() { SetStrict(previous) })
+}
+
+// recoverConfigError runs fn and returns the *ConfigError it panicked with.
+func recoverConfigError(fn func()) (configErr *ConfigError) {
+	defer func() {
+		if r := recover(); r != nil {
+			configErr, _ = r.(*ConfigError)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func TestStrict_PanicsOnIgnoredConfig(t *testing.T) {
+	enableStrict(t)
+
+	tests := map[string]func(){
+		"AddRedactPattern": func() { NewConfig().AddRedactPattern("(") },
+		"WithLevelString":  func() { NewCoreConfig().WithLevelString("verbose") },
+		"LOG_FORMAT": func() {
+			t.Setenv("LOG_FORMAT", "xml")
+			NewLoggerConfig().FromEnvironment()
+		},
+	}
+	for source, fn := range tests {
+		configErr := recoverConfigError(fn)
+		if configErr == nil {
+			t.Errorf("%s: expected panic with *ConfigError", source)
+			continue
+		}
+		if configErr.Diagnostic.Source != source {
+			t.Errorf("expected source %s, got %+v", source, configErr.Diagnostic)
+		}
+	}
+}
+
+func TestStrict_DeprecationDoesNotPanic(t *testing.T) {
+	enableStrict(t)
+	reportedDeprecated.Delete("NewStandardLogger")
+
+	if configErr := recoverConfigError(func() {
+		NewStandardLogger(NewConfig().WithOutput(&bytes.Buffer{}).Build(), nil)
+	}); configErr != nil {
+		t.Errorf("unexpected panic: %v", configErr)
+	}
+}
+
+func TestStrict_YAML(t *testing.T) {
+	valid := "level: info\ninclude_time: true\n"
+	invalid := map[string]string{
+		"yes boolean": "level: info\ninclude_time: yes\n",
+		"on boolean":  "use_slog: on\n",
+		"yes utc":     "utc: yes\n",
+		"unknown key": "level: info\ninclude_tme: true\n",
+	}
+
+	for name, yamlStr := range invalid {
+		if _, err := LoadFromYAMLString(yamlStr); err != nil {
+			t.Errorf("%s: expected lenient load outside strict mode, got %v", name, err)
+		}
+	}
+
+	enableStrict(t)
+
+	if _, err := LoadFromYAMLString(valid); err != nil {
+		t.Errorf("unexpected error for valid YAML: %v", err)
+	}
+	if _, err := LoadFromYAMLString(""); err != nil {
+		t.Errorf("unexpected error for empty YAML: %v", err)
+	}
+	for name, yamlStr := range invalid {
+		if _, err := LoadFromYAMLString(yamlStr); err == nil {
+			t.Errorf("%s: expected error in strict mode", name)
+		}
+	}
+}
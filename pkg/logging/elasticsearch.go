@@ -0,0 +1,319 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ElasticsearchConfig configures an ElasticsearchOutput.
+type ElasticsearchConfig struct {
+	// URL is the cluster URL, e.g. "http://localhost:9200".
+	URL string
+	// Index is the index name template. Placeholders in braces are replaced
+	// per entry: date patterns use yyyy, MM, dd and HH (e.g. {yyyy.MM.dd}, in
+	// UTC), anything else names a field (e.g. {service}). Missing fields
+	// render as "unknown". Defaults to "logs-{yyyy.MM.dd}".
+	Index string
+	// Username and Password enable basic auth; APIKey enables API key auth.
+	Username string
+	Password string
+	APIKey   string
+	// BatchSize is the number of documents per bulk request. Defaults to 500.
+	BatchSize int
+	// Linger is how long a partial batch waits for more documents. Defaults
+	// to 1s.
+	Linger time.Duration
+	// QueueSize bounds the documents waiting to be indexed. Defaults to 10000.
+	QueueSize int
+	// Retry controls retries of failed requests and of documents rejected
+	// with 429 or 5xx.
+	Retry RetryConfig
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the number of documents that could not be
+	// indexed.
+	OnError func(documents int, err error)
+}
+
+// esDocument is a queued bulk action and its source, without newlines.
+type esDocument struct {
+	action []byte
+	source []byte
+}
+
+// ElasticsearchOutput indexes entries through the _bulk API. Documents that
+// the cluster rejects with 429 or 5xx are retried with backoff; other
+// rejections are reported through OnError. One bulk request is in flight at
+// a time, so bursts queue up instead of multiplying load on the cluster.
+//
+// Example:
+//
+//	output, err := logging.NewElasticsearchOutput(logging.ElasticsearchConfig{
+//		URL:    "https://es.example.com:9200",
+//		Index:  "logs-{service}-{yyyy.MM.dd}",
+//		APIKey: os.Getenv("ES_API_KEY"),
+//	})
+type ElasticsearchOutput struct {
+	config  ElasticsearchConfig
+	index   []indexPart
+	batcher *batcher[esDocument]
+}
+
+// indexPart is a literal, date or field piece of an index template.
+type indexPart struct {
+	literal    string
+	dateLayout string
+	field      string
+}
+
+var indexPlaceholder = regexp.MustCompile(`\{([^}]+)\}`)
+
+// parseIndexTemplate splits template into literal, date and field parts.
+func parseIndexTemplate(template string) []indexPart {
+	var parts []indexPart
+	last := 0
+	for _, m := range indexPlaceholder.FindAllStringSubmatchIndex(template, -1) {
+		if m[0] > last {
+			parts = append(parts, indexPart{literal: template[last:m[0]]})
+		}
+		token := template[m[2]:m[3]]
+		if strings.ContainsAny(token, "yMdH") && strings.Trim(token, "yMdH.-_") == "" {
+			layout := strings.NewReplacer("yyyy", "2006", "MM", "01", "dd", "02", "HH", "15").Replace(token)
+			parts = append(parts, indexPart{dateLayout: layout})
+		} else {
+			parts = append(parts, indexPart{field: token})
+		}
+		last = m[1]
+	}
+	if last < len(template) {
+		parts = append(parts, indexPart{literal: template[last:]})
+	}
+	return parts
+}
+
+// NewElasticsearchOutput creates an ElasticsearchOutput.
+func NewElasticsearchOutput(config ElasticsearchConfig) (*ElasticsearchOutput, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("elasticsearch output requires a URL")
+	}
+	if config.Index == "" {
+		config.Index = "logs-{yyyy.MM.dd}"
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 500
+	}
+	if config.Linger == 0 {
+		config.Linger = time.Second
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 10000
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	eo := &ElasticsearchOutput{
+		config: config,
+		index:  parseIndexTemplate(config.Index),
+	}
+	eo.batcher = newBatcher(batcherConfig[esDocument]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     eo.bulk,
+		OnError: func(batch []esDocument, err error) {
+			// bulk reports its own failures; only queue drops arrive here.
+			if errors.Is(err, ErrQueueFull) {
+				eo.reportError(len(batch), err)
+			}
+		},
+	})
+	return eo, nil
+}
+
+// IndexFor returns the index entry is written to.
+func (eo *ElasticsearchOutput) IndexFor(entry LogEntry) string {
+	var b strings.Builder
+	for _, part := range eo.index {
+		switch {
+		case part.dateLayout != "":
+			b.WriteString(entry.Timestamp.UTC().Format(part.dateLayout))
+		case part.field != "":
+			value, ok := entryFieldString(entry, part.field)
+			if !ok || value == "" {
+				value = "unknown"
+			}
+			b.WriteString(strings.ToLower(value))
+		default:
+			b.WriteString(part.literal)
+		}
+	}
+	return b.String()
+}
+
+// Write queues data as the message of an INFO document.
+func (eo *ElasticsearchOutput) Write(data []byte) error {
+	return eo.WriteEntry(LogEntry{
+		Timestamp: time.Now(),
+		Level:     InfoLevel,
+		Message:   string(bytes.TrimRight(data, "\r\n")),
+	}, data)
+}
+
+// WriteEntry queues entry as a document with @timestamp, level, message,
+// its fields and context IDs.
+func (eo *ElasticsearchOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	doc := make(map[string]interface{}, len(entry.Fields)+6)
+	for k, v := range entry.Fields {
+		doc[k] = v
+	}
+	for _, name := range []string{"trace_id", "request_id", "correlation_id"} {
+		if _, ok := doc[name]; ok {
+			continue
+		}
+		if value, ok := entryFieldString(entry, name); ok {
+			doc[name] = value
+		}
+	}
+	doc["@timestamp"] = entry.Timestamp.UTC().Format(time.RFC3339Nano)
+	doc["level"] = entry.Level.String()
+	doc["message"] = entry.Message
+
+	source, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode elasticsearch document: %w", err)
+	}
+	action, err := json.Marshal(map[string]map[string]string{"create": {"_index": eo.IndexFor(entry)}})
+	if err != nil {
+		return fmt.Errorf("failed to encode elasticsearch action: %w", err)
+	}
+
+	return eo.batcher.Submit(esDocument{action: action, source: source})
+}
+
+// esBulkResponse is the part of a _bulk response used to find failures.
+type esBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// bulk indexes docs, retrying the request and any documents rejected with
+// retryable statuses.
+func (eo *ElasticsearchOutput) bulk(docs []esDocument) error {
+	pending := docs
+	err := retry(context.Background(), eo.config.Retry, func() error {
+		retryable, err := eo.send(pending)
+		if err != nil {
+			return err
+		}
+		if len(retryable) == 0 {
+			return nil
+		}
+		pending = retryable
+		return fmt.Errorf("%d documents rejected by elasticsearch", len(retryable))
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to index into elasticsearch: %w", err)
+		eo.reportError(len(pending), err)
+		return err
+	}
+	return nil
+}
+
+// send performs one bulk request. It returns the documents to retry, and
+// reports documents rejected permanently through OnError.
+func (eo *ElasticsearchOutput) send(docs []esDocument) ([]esDocument, error) {
+	var body bytes.Buffer
+	for _, doc := range docs {
+		body.Write(doc.action)
+		body.WriteByte('\n')
+		body.Write(doc.source)
+		body.WriteByte('\n')
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(eo.config.URL, "/")+"/_bulk", &body)
+	if err != nil {
+		return nil, permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if eo.config.APIKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+eo.config.APIKey)
+	} else if eo.config.Username != "" {
+		req.SetBasicAuth(eo.config.Username, eo.config.Password)
+	}
+
+	resp, err := eo.config.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, checkHTTPResponse(resp)
+	}
+	defer resp.Body.Close()
+
+	var result esBulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, permanent(fmt.Errorf("failed to decode bulk response: %w", err))
+	}
+	if !result.Errors {
+		return nil, nil
+	}
+
+	var retryable []esDocument
+	var rejected int
+	var firstReason string
+	for i, item := range result.Items {
+		if i >= len(docs) {
+			break
+		}
+		for _, r := range item {
+			switch {
+			case r.Status < 300:
+			case r.Status == http.StatusTooManyRequests || r.Status >= 500:
+				retryable = append(retryable, docs[i])
+			default:
+				rejected++
+				if firstReason == "" && r.Error != nil {
+					firstReason = r.Error.Type + ": " + r.Error.Reason
+				}
+			}
+		}
+	}
+	if rejected > 0 {
+		eo.reportError(rejected, fmt.Errorf("elasticsearch rejected %d documents: %s", rejected, firstReason))
+	}
+	return retryable, nil
+}
+
+func (eo *ElasticsearchOutput) reportError(documents int, err error) {
+	if eo.config.OnError != nil {
+		eo.config.OnError(documents, err)
+	}
+}
+
+// Flush indexes the pending partial batch.
+func (eo *ElasticsearchOutput) Flush() error {
+	return eo.batcher.Flush()
+}
+
+// Close indexes queued documents.
+func (eo *ElasticsearchOutput) Close() error {
+	return eo.batcher.Stop()
+}
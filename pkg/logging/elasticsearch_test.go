@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestElasticsearchOutput_IndexFor(t *testing.T) {
+	output, err := NewElasticsearchOutput(ElasticsearchConfig{
+		URL:   "http://localhost:9200",
+		Index: "logs-{service}-{yyyy.MM.dd}",
+	})
+	if err != nil {
+		t.Fatalf("failed to create elasticsearch output: %v", err)
+	}
+	defer output.Close()
+
+	timestamp := time.Date(2024, 3, 5, 23, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	tests := []struct {
+		entry LogEntry
+		want  string
+	}{
+		{LogEntry{Timestamp: timestamp, Fields: map[string]interface{}{"service": "Orders"}}, "logs-orders-2024.03.06"},
+		{LogEntry{Timestamp: timestamp}, "logs-unknown-2024.03.06"},
+	}
+	for _, tt := range tests {
+		if got := output.IndexFor(tt.entry); got != tt.want {
+			t.Errorf("IndexFor() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// bulkServer answers _bulk requests, rejecting documents whose message is in
+// reject with the given status.
+func bulkServer(t *testing.T, reject map[string]int, requests *atomic.Int32, indexed *[]string, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+
+		var items []string
+		errors := false
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var action map[string]map[string]string
+			_ = json.Unmarshal(scanner.Bytes(), &action)
+			scanner.Scan()
+			var doc map[string]interface{}
+			_ = json.Unmarshal(scanner.Bytes(), &doc)
+
+			message := fmt.Sprint(doc["message"])
+			status := 201
+			if s, ok := reject[message]; ok {
+				status = s
+				errors = true
+				if status == http.StatusTooManyRequests {
+					delete(reject, message)
+				}
+			} else {
+				mu.Lock()
+				*indexed = append(*indexed, action["create"]["_index"]+":"+message)
+				mu.Unlock()
+			}
+			items = append(items, fmt.Sprintf(`{"create":{"status":%d,"error":{"type":"mapper_parsing_exception","reason":"bad %s"}}}`, status, message))
+		}
+		fmt.Fprintf(w, `{"errors":%t,"items":[%s]}`, errors, strings.Join(items, ","))
+	}))
+}
+
+func TestElasticsearchOutput_PartialFailures(t *testing.T) {
+	var mu sync.Mutex
+	var indexed []string
+	var requests atomic.Int32
+	server := bulkServer(t, map[string]int{"busy": http.StatusTooManyRequests, "bad": http.StatusBadRequest}, &requests, &indexed, &mu)
+	defer server.Close()
+
+	var failed atomic.Int32
+	output, err := NewElasticsearchOutput(ElasticsearchConfig{
+		URL:     server.URL,
+		Index:   "logs-{service}",
+		Linger:  time.Hour,
+		Retry:   RetryConfig{InitialBackoff: time.Millisecond},
+		OnError: func(documents int, err error) { failed.Add(int32(documents)) },
+	})
+	if err != nil {
+		t.Fatalf("failed to create elasticsearch output: %v", err)
+	}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(output).Build()).WithField("service", "api")
+	logger.Info("ok")
+	logger.Info("busy")
+	logger.Info("bad")
+	_ = output.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(indexed, ",") != "logs-api:ok,logs-api:busy" {
+		t.Errorf("unexpected indexed documents: %v", indexed)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("expected the 429 document to be retried in a second request, got %d requests", requests.Load())
+	}
+	if failed.Load() != 1 {
+		t.Errorf("expected 1 permanently rejected document, got %d", failed.Load())
+	}
+}
+
+func TestElasticsearchOutput_RetriesExhausted(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var failed atomic.Int32
+	output, err := NewElasticsearchOutput(ElasticsearchConfig{
+		URL:     server.URL,
+		Retry:   RetryConfig{MaxRetries: 2, InitialBackoff: time.Millisecond},
+		OnError: func(documents int, err error) { failed.Add(int32(documents)) },
+	})
+	if err != nil {
+		t.Fatalf("failed to create elasticsearch output: %v", err)
+	}
+	_ = output.Write([]byte("a"))
+	_ = output.Write([]byte("b"))
+	if err := output.Close(); err == nil {
+		t.Error("expected close to report the failed bulk request")
+	}
+
+	if requests.Load() != 3 || failed.Load() != 2 {
+		t.Errorf("expected 3 attempts and 2 failed documents, got %d and %d", requests.Load(), failed.Load())
+	}
+}
@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// BaggageKey is the context key for baggage, key-value pairs that travel
+// with a request across service and process boundaries.
+const BaggageKey contextKey = "baggage"
+
+// Environment variables InjectEnv sets for subprocesses.
+const (
+	TraceIDEnv       = "LOG_TRACE_ID"
+	RequestIDEnv     = "LOG_REQUEST_ID"
+	CorrelationIDEnv = "LOG_CORRELATION_ID"
+	// BaggageEnv holds baggage as comma-separated key=value pairs with
+	// URL-escaped keys and values, as in the W3C baggage header.
+	BaggageEnv = "LOG_BAGGAGE"
+)
+
+// WithBaggage returns a new context with key set to value in its baggage.
+//
+// Example:
+//
+//	ctx = logging.WithBaggage(ctx, "tenant", "acme")
+func WithBaggage(ctx context.Context, key, value string) context.Context {
+	current := GetBaggage(ctx)
+	baggage := make(map[string]string, len(current)+1)
+	for k, v := range current {
+		baggage[k] = v
+	}
+	baggage[key] = value
+	return context.WithValue(ctx, BaggageKey, baggage)
+}
+
+// GetBaggage retrieves the baggage from the context. The returned map must
+// not be modified.
+func GetBaggage(ctx context.Context) map[string]string {
+	baggage, _ := ctx.Value(BaggageKey).(map[string]string)
+	return baggage
+}
+
+// InjectEnv returns environment variables carrying ctx's trace, request and
+// correlation IDs and baggage, for passing to a subprocess so its logs
+// correlate with the caller's. The subprocess restores them with ExtractEnv.
+//
+// Example:
+//
+//	cmd := exec.CommandContext(ctx, "migrate", "up")
+//	cmd.Env = append(os.Environ(), logging.InjectEnv(ctx)...)
+func InjectEnv(ctx context.Context) []string {
+	var env []string
+	if id, ok := GetTraceID(ctx); ok && id != "" {
+		env = append(env, TraceIDEnv+"="+id)
+	}
+	if id, ok := GetRequestID(ctx); ok && id != "" {
+		env = append(env, RequestIDEnv+"="+id)
+	}
+	if id, ok := GetCorrelationID(ctx); ok && id != "" {
+		env = append(env, CorrelationIDEnv+"="+id)
+	}
+	if baggage := GetBaggage(ctx); len(baggage) > 0 {
+		env = append(env, BaggageEnv+"="+encodeBaggage(baggage))
+	}
+	return env
+}
+
+// ExtractEnv returns ctx with the IDs and baggage InjectEnv put in env,
+// typically os.Environ(). Baggage is merged into ctx's existing baggage.
+//
+// Example:
+//
+//	ctx := logging.ExtractEnv(context.Background(), os.Environ())
+//	logger.InfoContext(ctx, "migration started")
+func ExtractEnv(ctx context.Context, env []string) context.Context {
+	for _, kv := range env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || value == "" {
+			continue
+		}
+		switch key {
+		case TraceIDEnv:
+			ctx = WithTraceID(ctx, value)
+		case RequestIDEnv:
+			ctx = WithRequestID(ctx, value)
+		case CorrelationIDEnv:
+			ctx = WithCorrelationID(ctx, value)
+		case BaggageEnv:
+			for _, member := range strings.Split(value, ",") {
+				k, v, ok := strings.Cut(strings.TrimSpace(member), "=")
+				if !ok {
+					continue
+				}
+				k, errK := url.QueryUnescape(k)
+				v, errV := url.QueryUnescape(v)
+				if errK != nil || errV != nil || k == "" {
+					continue
+				}
+				ctx = WithBaggage(ctx, k, v)
+			}
+		}
+	}
+	return ctx
+}
+
+// encodeBaggage renders baggage with sorted keys.
+func encodeBaggage(baggage map[string]string) string {
+	keys := make([]string, 0, len(baggage))
+	for k := range baggage {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	members := make([]string, len(keys))
+	for i, k := range keys {
+		members[i] = url.QueryEscape(k) + "=" + url.QueryEscape(baggage[k])
+	}
+	return strings.Join(members, ",")
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestInjectExtractEnv(t *testing.T) {
+	ctx := WithTraceID(context.Background(), "trace-1")
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithCorrelationID(ctx, "corr-1")
+	ctx = WithBaggage(ctx, "tenant", "acme")
+	ctx = WithBaggage(ctx, "note", "a,b=c d")
+
+	env := InjectEnv(ctx)
+	want := []string{
+		"LOG_TRACE_ID=trace-1",
+		"LOG_REQUEST_ID=req-1",
+		"LOG_CORRELATION_ID=corr-1",
+		"LOG_BAGGAGE=note=a%2Cb%3Dc+d,tenant=acme",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("InjectEnv() = %v, want %v", env, want)
+	}
+
+	child := ExtractEnv(context.Background(), append([]string{"PATH=/bin", "LOG_TRACE_ID="}, env...))
+	if id, _ := GetTraceID(child); id != "trace-1" {
+		t.Errorf("trace ID = %q", id)
+	}
+	if id, _ := GetRequestID(child); id != "req-1" {
+		t.Errorf("request ID = %q", id)
+	}
+	if id, _ := GetCorrelationID(child); id != "corr-1" {
+		t.Errorf("correlation ID = %q", id)
+	}
+	if baggage := GetBaggage(child); !reflect.DeepEqual(baggage, GetBaggage(ctx)) {
+		t.Errorf("baggage = %v, want %v", baggage, GetBaggage(ctx))
+	}
+
+	if env := InjectEnv(context.Background()); len(env) != 0 {
+		t.Errorf("expected no variables for an empty context, got %v", env)
+	}
+}
+
+func TestWithBaggage_DoesNotModifyParent(t *testing.T) {
+	parent := WithBaggage(context.Background(), "a", "1")
+	_ = WithBaggage(parent, "b", "2")
+	if baggage := GetBaggage(parent); len(baggage) != 1 {
+		t.Errorf("expected the parent's baggage to be unchanged, got %v", baggage)
+	}
+}
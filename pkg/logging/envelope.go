@@ -0,0 +1,512 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EnvelopeVersion is the current batch envelope format version. Version 2
+// added per-record idempotency keys.
+const EnvelopeVersion = 2
+
+// EnvelopeContentType is the media type of envelope request bodies.
+const EnvelopeContentType = "application/vnd.go-logging.envelope"
+
+// envelopeMagic starts every envelope.
+var envelopeMagic = [4]byte{'G', 'L', 'E', 'V'}
+
+// envelopeFlagGzip marks a gzip-compressed payload.
+const envelopeFlagGzip = 1 << 0
+
+// envelopeFlagKeys marks records prefixed with an idempotency key.
+const envelopeFlagKeys = 1 << 1
+
+// envelopeHeaderSize is the size of the fixed envelope header.
+const envelopeHeaderSize = 20
+
+// maxEnvelopePayload bounds the payload accepted by ReadEnvelope.
+const maxEnvelopePayload = 64 << 20
+
+// maxEnvelopeRecords bounds the records of one envelope.
+const maxEnvelopeRecords = 1 << 20
+
+// ErrEnvelopeChecksum is returned when an envelope's payload does not match
+// its checksum.
+var ErrEnvelopeChecksum = errors.New("envelope checksum mismatch")
+
+var envelopeCRC = crc32.MakeTable(crc32.Castagnoli)
+
+// Envelope is a batch of log records shipped between services. Records are
+// opaque, typically one formatted entry each, and survive the trip byte for
+// byte.
+//
+// On the wire an envelope is a 20-byte header followed by the payload:
+//
+//	magic      [4]byte  "GLEV"
+//	version    uint16   EnvelopeVersion
+//	flags      uint16   bit 0: payload is gzip-compressed
+//	                    bit 1: records carry idempotency keys
+//	count      uint32   number of records
+//	length     uint32   payload length in bytes
+//	checksum   uint32   CRC-32C of the payload as sent
+//	payload    records, each a uvarint length followed by its bytes, and
+//	           preceded by its key encoded the same way when bit 1 is set
+//
+// Integers are big-endian. Envelopes are self-delimiting, so several can be
+// written back to back on one stream.
+type Envelope struct {
+	Version    uint16
+	Compressed bool
+	Records    [][]byte
+	// Keys holds each record's idempotency key, or is nil when the
+	// envelope carries none.
+	Keys []string
+}
+
+// WriteEnvelope encodes records as one envelope on w, gzip-compressing the
+// payload when compress is true.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	if err := logging.WriteEnvelope(&buf, lines, true); err != nil {
+//		return err
+//	}
+//	http.Post(url, logging.EnvelopeContentType, &buf)
+func WriteEnvelope(w io.Writer, records [][]byte, compress bool) error {
+	return writeEnvelope(w, nil, records, compress)
+}
+
+// WriteKeyedEnvelope encodes records as one envelope on w together with
+// their idempotency keys, so NewIngestHandler can drop records it has
+// already accepted.
+func WriteKeyedEnvelope(w io.Writer, records []DeliveryRecord, compress bool) error {
+	keys := make([]string, len(records))
+	data := make([][]byte, len(records))
+	for i, record := range records {
+		keys[i], data[i] = record.Key, record.Data
+	}
+	return writeEnvelope(w, keys, data, compress)
+}
+
+// writeEnvelope encodes an envelope, with keys when keys is not nil.
+func writeEnvelope(w io.Writer, keys []string, records [][]byte, compress bool) error {
+	var payload bytes.Buffer
+	var sink io.Writer = &payload
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(&payload)
+		sink = zw
+	}
+
+	var lenBuf [binary.MaxVarintLen64]byte
+	for i, record := range records {
+		if keys != nil {
+			n := binary.PutUvarint(lenBuf[:], uint64(len(keys[i])))
+			if _, err := sink.Write(lenBuf[:n]); err != nil {
+				return fmt.Errorf("failed to encode envelope: %w", err)
+			}
+			if _, err := io.WriteString(sink, keys[i]); err != nil {
+				return fmt.Errorf("failed to encode envelope: %w", err)
+			}
+		}
+		n := binary.PutUvarint(lenBuf[:], uint64(len(record)))
+		if _, err := sink.Write(lenBuf[:n]); err != nil {
+			return fmt.Errorf("failed to encode envelope: %w", err)
+		}
+		if _, err := sink.Write(record); err != nil {
+			return fmt.Errorf("failed to encode envelope: %w", err)
+		}
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("failed to compress envelope: %w", err)
+		}
+	}
+
+	var flags uint16
+	if compress {
+		flags |= envelopeFlagGzip
+	}
+	if keys != nil {
+		flags |= envelopeFlagKeys
+	}
+
+	var header [envelopeHeaderSize]byte
+	copy(header[0:4], envelopeMagic[:])
+	binary.BigEndian.PutUint16(header[4:6], EnvelopeVersion)
+	binary.BigEndian.PutUint16(header[6:8], flags)
+	binary.BigEndian.PutUint32(header[8:12], uint32(len(records)))
+	binary.BigEndian.PutUint32(header[12:16], uint32(payload.Len()))
+	binary.BigEndian.PutUint32(header[16:20], crc32.Checksum(payload.Bytes(), envelopeCRC))
+
+	if _, err := w.Write(header[:]); err != nil {
+		return fmt.Errorf("failed to write envelope: %w", err)
+	}
+	if _, err := w.Write(payload.Bytes()); err != nil {
+		return fmt.Errorf("failed to write envelope: %w", err)
+	}
+	return nil
+}
+
+// ReadEnvelope decodes the next envelope from r. It returns io.EOF when r is
+// exhausted before an envelope starts, ErrEnvelopeChecksum for corrupted
+// payloads, and an error for versions newer than EnvelopeVersion.
+func ReadEnvelope(r io.Reader) (*Envelope, error) {
+	var header [envelopeHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read envelope header: %w", err)
+	}
+	if !bytes.Equal(header[0:4], envelopeMagic[:]) {
+		return nil, fmt.Errorf("not an envelope")
+	}
+
+	version := binary.BigEndian.Uint16(header[4:6])
+	if version == 0 || version > EnvelopeVersion {
+		return nil, fmt.Errorf("unsupported envelope version %d", version)
+	}
+	flags := binary.BigEndian.Uint16(header[6:8])
+	if flags&envelopeFlagKeys != 0 && version < 2 {
+		return nil, fmt.Errorf("envelope version %d cannot carry keys", version)
+	}
+	count := binary.BigEndian.Uint32(header[8:12])
+	length := binary.BigEndian.Uint32(header[12:16])
+	checksum := binary.BigEndian.Uint32(header[16:20])
+
+	if length > maxEnvelopePayload {
+		return nil, fmt.Errorf("envelope payload of %d bytes exceeds limit", length)
+	}
+	// Every record takes at least one byte for its length, before
+	// compression or after it, so a larger count cannot be genuine.
+	if count > maxEnvelopeRecords || (flags&envelopeFlagGzip == 0 && count > length) {
+		return nil, fmt.Errorf("envelope record count %d exceeds payload", count)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, fmt.Errorf("failed to read envelope payload: %w", err)
+	}
+	if crc32.Checksum(payload, envelopeCRC) != checksum {
+		return nil, ErrEnvelopeChecksum
+	}
+
+	env := &Envelope{Version: version, Compressed: flags&envelopeFlagGzip != 0}
+
+	var records io.Reader = bytes.NewReader(payload)
+	if env.Compressed {
+		zr, err := gzip.NewReader(records)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress envelope: %w", err)
+		}
+		defer zr.Close()
+		records = io.LimitReader(zr, maxEnvelopePayload)
+	}
+
+	br := bufio.NewReader(records)
+	for i := uint32(0); i < count; i++ {
+		if flags&envelopeFlagKeys != 0 {
+			key, err := readEnvelopeRecord(br, i)
+			if err != nil {
+				return nil, err
+			}
+			env.Keys = append(env.Keys, string(key))
+		}
+		record, err := readEnvelopeRecord(br, i)
+		if err != nil {
+			return nil, err
+		}
+		env.Records = append(env.Records, record)
+	}
+	return env, nil
+}
+
+// readEnvelopeRecord reads one length-prefixed value of record i.
+func readEnvelopeRecord(br *bufio.Reader, i uint32) ([]byte, error) {
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode envelope record %d: %w", i, err)
+	}
+	if n > maxEnvelopePayload {
+		return nil, fmt.Errorf("envelope record %d of %d bytes exceeds limit", i, n)
+	}
+	record := make([]byte, n)
+	if _, err := io.ReadFull(br, record); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope record %d: %w", i, err)
+	}
+	return record, nil
+}
+
+// maxIngestBody bounds the request body accepted by NewIngestHandler.
+const maxIngestBody = 4 * maxEnvelopePayload
+
+// maxIngestDecoded and maxIngestRecords bound the decompressed records of
+// one request to NewIngestHandler, which holds them all before writing any.
+// Tests lower them.
+var (
+	maxIngestDecoded = 4 * maxEnvelopePayload
+	maxIngestRecords = 4 * maxEnvelopeRecords
+)
+
+// ingestKeyCapacity is how many idempotency keys NewIngestHandler
+// remembers.
+const ingestKeyCapacity = 100000
+
+// idempotencyKeys remembers the most recent keys, forgetting the oldest
+// once capacity is reached.
+type idempotencyKeys struct {
+	mu    sync.Mutex
+	seen  map[string]struct{}
+	order []string
+	next  int
+}
+
+func newIdempotencyKeys(capacity int) *idempotencyKeys {
+	return &idempotencyKeys{seen: make(map[string]struct{}, capacity), order: make([]string, 0, capacity)}
+}
+
+// contains reports whether key was added and not yet forgotten.
+func (k *idempotencyKeys) contains(key string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	_, ok := k.seen[key]
+	return ok
+}
+
+// add remembers key.
+func (k *idempotencyKeys) add(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.seen[key]; ok {
+		return
+	}
+	if len(k.order) < cap(k.order) {
+		k.order = append(k.order, key)
+	} else {
+		delete(k.seen, k.order[k.next])
+		k.order[k.next] = key
+		k.next = (k.next + 1) % len(k.order)
+	}
+	k.seen[key] = struct{}{}
+}
+
+// NewIngestHandler returns an HTTP handler that accepts POSTed envelopes
+// and writes every record to output, so a service can relay or store logs
+// shipped by others. The body may hold several envelopes. The response is
+// 200 with {"accepted": n} once all records are written; corrupted or
+// unsupported envelopes are rejected with 400 before any of the request's
+// records are written, and bodies over 256MiB, or decompressing to more
+// than 256MiB or about four million records, with 413.
+//
+// A request repeating the Idempotency-Key of a recently accepted request
+// is acknowledged with {"accepted": 0} without writing its records again.
+// Likewise, records of keyed envelopes whose keys were recently written are
+// skipped, so redelivered batches are written once even when their
+// boundaries change. Skipped records are not counted as accepted.
+//
+// Example:
+//
+//	store, _ := logging.NewLocalStore("/var/lib/app/logs", logging.LocalStoreConfig{})
+//	http.Handle("/ingest", logging.NewIngestHandler(store))
+func NewIngestHandler(output Output) http.Handler {
+	keys := newIdempotencyKeys(ingestKeyCapacity)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		key := r.Header.Get("Idempotency-Key")
+		if key != "" && keys.contains(key) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]int{"accepted": 0})
+			return
+		}
+
+		body := http.MaxBytesReader(w, r.Body, maxIngestBody)
+		var records [][]byte
+		var recordKeys []string
+		decoded := 0
+		for {
+			env, err := ReadEnvelope(body)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				status := http.StatusBadRequest
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					status = http.StatusRequestEntityTooLarge
+				}
+				http.Error(w, err.Error(), status)
+				return
+			}
+			for i, record := range env.Records {
+				decoded += len(record)
+				if env.Keys != nil {
+					decoded += len(env.Keys[i])
+				}
+			}
+			if decoded > maxIngestDecoded || len(records)+len(env.Records) > maxIngestRecords {
+				http.Error(w, "decoded request exceeds limit", http.StatusRequestEntityTooLarge)
+				return
+			}
+			for i, record := range env.Records {
+				recordKey := ""
+				if env.Keys != nil {
+					recordKey = env.Keys[i]
+				}
+				records = append(records, record)
+				recordKeys = append(recordKeys, recordKey)
+			}
+		}
+
+		accepted := 0
+		written := make(map[string]bool)
+		for i, record := range records {
+			recordKey := recordKeys[i]
+			if recordKey != "" && (written[recordKey] || keys.contains(recordKey)) {
+				continue
+			}
+			if err := output.Write(record); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"accepted": accepted, "error": err.Error()})
+				return
+			}
+			if recordKey != "" {
+				written[recordKey] = true
+				keys.add(recordKey)
+			}
+			accepted++
+		}
+		if key != "" {
+			keys.add(key)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]int{"accepted": accepted})
+	})
+}
+
+// EnvelopeConfig configures an EnvelopeOutput.
+type EnvelopeConfig struct {
+	// URL is the ingest endpoint, typically served by NewIngestHandler.
+	URL string
+	// Compress gzips envelope payloads.
+	Compress bool
+	// BatchSize is the number of records per envelope. Defaults to 500.
+	BatchSize int
+	// Linger is how long a partial batch waits for more records. Defaults
+	// to 1s.
+	Linger time.Duration
+	// QueueSize bounds the records waiting to be shipped. Defaults to 10000.
+	QueueSize int
+	// Retry controls retries of failed requests.
+	Retry RetryConfig
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the number of records that could not be
+	// shipped.
+	OnError func(records int, err error)
+}
+
+// EnvelopeOutput ships formatted entries in envelopes to an ingest endpoint,
+// preserving every record byte for byte.
+//
+// Example:
+//
+//	output, err := logging.NewEnvelopeOutput(logging.EnvelopeConfig{
+//		URL:      "http://log-relay:8080/ingest",
+//		Compress: true,
+//	})
+type EnvelopeOutput struct {
+	config  EnvelopeConfig
+	batcher *batcher[[]byte]
+}
+
+// NewEnvelopeOutput creates an EnvelopeOutput.
+func NewEnvelopeOutput(config EnvelopeConfig) (*EnvelopeOutput, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("envelope output requires a URL")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 500
+	}
+	if config.Linger == 0 {
+		config.Linger = time.Second
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 10000
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	eo := &EnvelopeOutput{config: config}
+	eo.batcher = newBatcher(batcherConfig[[]byte]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     eo.ship,
+		OnError: func(batch [][]byte, err error) {
+			if eo.config.OnError != nil {
+				eo.config.OnError(len(batch), err)
+			}
+		},
+	})
+	return eo, nil
+}
+
+// Write queues a copy of data as one record.
+func (eo *EnvelopeOutput) Write(data []byte) error {
+	return eo.batcher.Submit(append([]byte(nil), data...))
+}
+
+func (eo *EnvelopeOutput) ship(records [][]byte) error {
+	var body bytes.Buffer
+	if err := WriteEnvelope(&body, records, eo.config.Compress); err != nil {
+		return err
+	}
+
+	err := retry(context.Background(), eo.config.Retry, func() error {
+		req, err := http.NewRequest(http.MethodPost, eo.config.URL, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return permanent(err)
+		}
+		req.Header.Set("Content-Type", EnvelopeContentType)
+
+		resp, err := eo.config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		return checkHTTPResponse(resp)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ship envelope: %w", err)
+	}
+	return nil
+}
+
+// Flush ships the pending partial batch.
+func (eo *EnvelopeOutput) Flush() error {
+	return eo.batcher.Flush()
+}
+
+// Close ships queued records.
+func (eo *EnvelopeOutput) Close() error {
+	return eo.batcher.Stop()
+}
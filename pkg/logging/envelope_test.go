@@ -0,0 +1,232 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEnvelope_RoundTrip(t *testing.T) {
+	records := [][]byte{
+		[]byte(`{"level":"INFO","message":"one"}` + "\n"),
+		{},
+		[]byte("binary \x00\xff record"),
+	}
+
+	for _, compress := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := WriteEnvelope(&buf, records, compress); err != nil {
+			t.Fatalf("WriteEnvelope failed: %v", err)
+		}
+		if err := WriteEnvelope(&buf, records[:1], compress); err != nil {
+			t.Fatalf("WriteEnvelope failed: %v", err)
+		}
+
+		env, err := ReadEnvelope(&buf)
+		if err != nil {
+			t.Fatalf("ReadEnvelope failed: %v", err)
+		}
+		if env.Version != EnvelopeVersion || env.Compressed != compress || len(env.Records) != len(records) {
+			t.Fatalf("unexpected envelope: %+v", env)
+		}
+		for i := range records {
+			if !bytes.Equal(env.Records[i], records[i]) {
+				t.Errorf("record %d: got %q, want %q", i, env.Records[i], records[i])
+			}
+		}
+
+		if second, err := ReadEnvelope(&buf); err != nil || len(second.Records) != 1 {
+			t.Errorf("expected second envelope on the same stream, got %v, %v", second, err)
+		}
+		if _, err := ReadEnvelope(&buf); !errors.Is(err, io.EOF) {
+			t.Errorf("expected io.EOF after last envelope, got %v", err)
+		}
+	}
+}
+
+func TestEnvelope_Keys(t *testing.T) {
+	var buf bytes.Buffer
+	records := []DeliveryRecord{{Key: "k-1", Data: []byte("one")}, {Key: "k-2", Data: nil}}
+	if err := WriteKeyedEnvelope(&buf, records, true); err != nil {
+		t.Fatalf("WriteKeyedEnvelope failed: %v", err)
+	}
+	env, err := ReadEnvelope(&buf)
+	if err != nil {
+		t.Fatalf("ReadEnvelope failed: %v", err)
+	}
+	if len(env.Keys) != 2 || env.Keys[0] != "k-1" || env.Keys[1] != "k-2" || string(env.Records[0]) != "one" || len(env.Records[1]) != 0 {
+		t.Errorf("unexpected keyed envelope: %+v", env)
+	}
+
+	buf.Reset()
+	_ = WriteEnvelope(&buf, [][]byte{[]byte("one")}, false)
+	if env, err := ReadEnvelope(&buf); err != nil || env.Keys != nil {
+		t.Errorf("expected no keys in an unkeyed envelope, got %v, %v", env, err)
+	}
+
+	buf.Reset()
+	_ = WriteKeyedEnvelope(&buf, records, false)
+	v1 := buf.Bytes()
+	binary.BigEndian.PutUint16(v1[4:6], 1)
+	if _, err := ReadEnvelope(bytes.NewReader(v1)); err == nil {
+		t.Error("expected a version 1 envelope with keys to be rejected")
+	}
+}
+
+func TestEnvelope_Corruption(t *testing.T) {
+	var buf bytes.Buffer
+	_ = WriteEnvelope(&buf, [][]byte{[]byte("record")}, false)
+	data := buf.Bytes()
+
+	corrupted := append([]byte(nil), data...)
+	corrupted[len(corrupted)-1] ^= 0xff
+	if _, err := ReadEnvelope(bytes.NewReader(corrupted)); !errors.Is(err, ErrEnvelopeChecksum) {
+		t.Errorf("expected checksum error, got %v", err)
+	}
+
+	future := append([]byte(nil), data...)
+	binary.BigEndian.PutUint16(future[4:6], EnvelopeVersion+1)
+	if _, err := ReadEnvelope(bytes.NewReader(future)); err == nil || !strings.Contains(err.Error(), "unsupported envelope version") {
+		t.Errorf("expected version error, got %v", err)
+	}
+
+	if _, err := ReadEnvelope(bytes.NewReader(data[:10])); err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("expected truncation error, got %v", err)
+	}
+	if _, err := ReadEnvelope(strings.NewReader("not an envelope at all")); err == nil {
+		t.Error("expected error for bad magic")
+	}
+
+	var empty bytes.Buffer
+	_ = WriteEnvelope(&empty, nil, false)
+	inflated := empty.Bytes()
+	binary.BigEndian.PutUint32(inflated[8:12], 0xFFFFFFFF)
+	if _, err := ReadEnvelope(bytes.NewReader(inflated)); err == nil || !strings.Contains(err.Error(), "record count") {
+		t.Errorf("expected record count error, got %v", err)
+	}
+}
+
+func TestIngestHandler(t *testing.T) {
+	out := &bytes.Buffer{}
+	server := httptest.NewServer(NewIngestHandler(NewWriterOutput(out)))
+	defer server.Close()
+
+	var body bytes.Buffer
+	_ = WriteEnvelope(&body, [][]byte{[]byte("a\n"), []byte("b\n")}, true)
+	_ = WriteEnvelope(&body, [][]byte{[]byte("c\n")}, false)
+
+	resp, err := http.Post(server.URL, EnvelopeContentType, &body)
+	if err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(respBody)) != `{"accepted":3}` {
+		t.Errorf("unexpected response %d %s", resp.StatusCode, respBody)
+	}
+	if out.String() != "a\nb\nc\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	resp, err = http.Post(server.URL, EnvelopeContentType, strings.NewReader("garbage"))
+	if err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for garbage, got %d", resp.StatusCode)
+	}
+}
+
+func TestIngestHandler_IdempotencyKey(t *testing.T) {
+	out := &bytes.Buffer{}
+	server := httptest.NewServer(NewIngestHandler(NewWriterOutput(out)))
+	defer server.Close()
+
+	var body bytes.Buffer
+	_ = WriteEnvelope(&body, [][]byte{[]byte("a\n")}, false)
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body.Bytes()))
+		req.Header.Set("Idempotency-Key", "batch-1")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("post failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("unexpected status %d", resp.StatusCode)
+		}
+	}
+	if out.String() != "a\n" {
+		t.Errorf("expected the retried request to be dropped, got %q", out.String())
+	}
+}
+
+func TestIngestHandler_DecodedLimit(t *testing.T) {
+	defer func(size, records int) { maxIngestDecoded, maxIngestRecords = size, records }(maxIngestDecoded, maxIngestRecords)
+	maxIngestDecoded = 1 << 10
+
+	out := &bytes.Buffer{}
+	server := httptest.NewServer(NewIngestHandler(NewWriterOutput(out)))
+	defer server.Close()
+
+	// Each envelope compresses well and stays under the per-envelope
+	// limit; together they decompress past the request limit.
+	var body bytes.Buffer
+	for i := 0; i < 4; i++ {
+		_ = WriteEnvelope(&body, [][]byte{bytes.Repeat([]byte("a"), 512)}, true)
+	}
+	resp, err := http.Post(server.URL, EnvelopeContentType, &body)
+	if err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge || out.Len() != 0 {
+		t.Errorf("expected 413 with nothing written, got %d and %d bytes", resp.StatusCode, out.Len())
+	}
+
+	maxIngestDecoded, maxIngestRecords = 1<<20, 2
+	body.Reset()
+	_ = WriteEnvelope(&body, [][]byte{{}, {}, {}}, true)
+	resp, err = http.Post(server.URL, EnvelopeContentType, &body)
+	if err != nil {
+		t.Fatalf("post failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for too many records, got %d", resp.StatusCode)
+	}
+}
+
+func TestEnvelopeOutput_ShipsToIngestHandler(t *testing.T) {
+	out := &bytes.Buffer{}
+	server := httptest.NewServer(NewIngestHandler(NewWriterOutput(out)))
+	defer server.Close()
+
+	output, err := NewEnvelopeOutput(EnvelopeConfig{URL: server.URL, Compress: true, BatchSize: 2})
+	if err != nil {
+		t.Fatalf("failed to create envelope output: %v", err)
+	}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithCustomOutput(output).Build())
+	logger.Info("first")
+	logger.Info("second")
+	logger.Info("third")
+	if err := output.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 relayed lines, got %q", out.String())
+	}
+	if entry, ok := ParseJSONEntry([]byte(lines[2])); !ok || entry.Message != "third" {
+		t.Errorf("unexpected relayed entry %q", lines[2])
+	}
+}
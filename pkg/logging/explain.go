@@ -0,0 +1,213 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ExplainOutcome is what a pipeline stage would do with an entry.
+type ExplainOutcome string
+
+const (
+	// ExplainPass means the stage passes the entry on.
+	ExplainPass ExplainOutcome = "pass"
+	// ExplainDrop means the stage discards the entry.
+	ExplainDrop ExplainOutcome = "drop"
+	// ExplainMaybe means the stage keeps only some entries, e.g. sampling.
+	ExplainMaybe ExplainOutcome = "maybe"
+	// ExplainRoute means the stage sends the entry to the nested stages.
+	ExplainRoute ExplainOutcome = "route"
+)
+
+// ExplainStep is one stage's decision about an entry. Depth is the nesting
+// below routing stages.
+type ExplainStep struct {
+	Stage   string
+	Outcome ExplainOutcome
+	Detail  string
+	Depth   int
+}
+
+// Explanation lists the decisions of every stage an entry would pass
+// through, in order.
+type Explanation struct {
+	Level Level
+	Steps []ExplainStep
+}
+
+// Dropped reports whether the entry is discarded before reaching any
+// output.
+func (e Explanation) Dropped() bool {
+	for _, step := range e.Steps {
+		if step.Depth == 0 && step.Outcome == ExplainDrop {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders one step per line, indented by depth.
+func (e Explanation) String() string {
+	var b strings.Builder
+	for _, step := range e.Steps {
+		fmt.Fprintf(&b, "%s%s: %s", strings.Repeat("  ", step.Depth), step.Stage, step.Outcome)
+		if step.Detail != "" {
+			fmt.Fprintf(&b, " (%s)", step.Detail)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// OutputExplainer is implemented by outputs that filter, sample or route
+// entries so Explain can report their decisions. ExplainEntry must not
+// write entry or change the output's state.
+type OutputExplainer interface {
+	ExplainEntry(entry LogEntry) []ExplainStep
+}
+
+// ExplainOutput returns output's decisions about entry. Outputs that don't
+// implement OutputExplainer are reported as writing every entry. Wrapping
+// outputs use it to explain the outputs they wrap.
+func ExplainOutput(output Output, entry LogEntry) []ExplainStep {
+	if explainer, ok := output.(OutputExplainer); ok {
+		return explainer.ExplainEntry(entry)
+	}
+	return []ExplainStep{{Stage: fmt.Sprintf("%T", output), Outcome: ExplainPass, Detail: "writes every entry"}}
+}
+
+// nestSteps returns steps one level deeper.
+func nestSteps(steps []ExplainStep) []ExplainStep {
+	for i := range steps {
+		steps[i].Depth++
+	}
+	return steps
+}
+
+// loggerExplainer is implemented by loggers that can explain their pipeline.
+type loggerExplainer interface {
+	explain(ctx context.Context, level Level, fields map[string]interface{}) Explanation
+}
+
+// Explain reports which stages of the default logger's pipeline would drop,
+// sample or route an entry with level and fields, without logging it. Use
+// it to diagnose why a log line doesn't show up.
+//
+// Example:
+//
+//	fmt.Print(logging.Explain(ctx, logging.DebugLevel, map[string]interface{}{"component": "cache"}))
+func Explain(ctx context.Context, level Level, fields map[string]interface{}) Explanation {
+	return ExplainLogger(GetDefaultLogger(), ctx, level, fields)
+}
+
+// ExplainLogger is Explain for a specific logger.
+func ExplainLogger(logger Logger, ctx context.Context, level Level, fields map[string]interface{}) Explanation {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if explainer, ok := logger.(loggerExplainer); ok {
+		return explainer.explain(ctx, level, fields)
+	}
+
+	e := Explanation{Level: level}
+	if logger.IsLevelEnabled(level) {
+		e.Steps = append(e.Steps, ExplainStep{Stage: "level", Outcome: ExplainPass, Detail: fmt.Sprintf("%T accepts %s", logger, level)})
+	} else {
+		e.Steps = append(e.Steps, ExplainStep{Stage: "level", Outcome: ExplainDrop, Detail: fmt.Sprintf("%T rejects %s", logger, level)})
+	}
+	return e
+}
+
+// explain walks the logger's level check, slog handler or output chain.
+func (ul *unifiedLogger) explain(ctx context.Context, level Level, fields map[string]interface{}) Explanation {
+	e := Explanation{Level: level}
+
+	minimum := ul.level.Load()
+	if level < minimum {
+		e.Steps = append(e.Steps, ExplainStep{Stage: "level", Outcome: ExplainDrop, Detail: fmt.Sprintf("%s is below the minimum %s", level, minimum)})
+		return e
+	}
+	e.Steps = append(e.Steps, ExplainStep{Stage: "level", Outcome: ExplainPass, Detail: fmt.Sprintf("%s meets the minimum %s", level, minimum)})
+
+	if ul.config.UseSlog {
+		handler := ul.slogLogger.Handler()
+		if !handler.Enabled(ctx, ul.levelToSlog(level)) {
+			e.Steps = append(e.Steps, ExplainStep{Stage: "slog handler", Outcome: ExplainDrop, Detail: fmt.Sprintf("%T is not enabled for %s", handler, level)})
+			return e
+		}
+		e.Steps = append(e.Steps, ExplainStep{Stage: "slog handler", Outcome: ExplainPass, Detail: fmt.Sprintf("%T handles the entry", handler)})
+		return e
+	}
+
+	child := ul.WithFields(fields).(*unifiedLogger)
+	entry := LogEntry{
+		Timestamp: ul.now(ctx),
+		Level:     level,
+		Fields:    child.buildCommonLogFields(),
+		Context:   ctx,
+	}
+	e.Steps = append(e.Steps, ExplainOutput(ul.output, entry)...)
+	return e
+}
+
+// ExplainEntry reports the entry being routed to every output.
+func (mo *MultiOutput) ExplainEntry(entry LogEntry) []ExplainStep {
+	mo.mu.RLock()
+	defer mo.mu.RUnlock()
+
+	steps := []ExplainStep{{Stage: "MultiOutput", Outcome: ExplainRoute, Detail: fmt.Sprintf("to %d outputs", len(mo.outputs))}}
+	for _, output := range mo.outputs {
+		steps = append(steps, nestSteps(ExplainOutput(output, entry))...)
+	}
+	return steps
+}
+
+// ExplainEntry reports the sampling rate, or whether the entry's trace is
+// sampled.
+func (so *SamplingOutput) ExplainEntry(entry LogEntry) []ExplainStep {
+	step := ExplainStep{Stage: "SamplingOutput", Outcome: ExplainMaybe, Detail: fmt.Sprintf("keeps 1 in %d entries", so.rate)}
+	if traceID, ok := entryFieldString(entry, "trace_id"); ok && traceID != "" {
+		step.Outcome = ExplainPass
+		step.Detail = fmt.Sprintf("trace %s is kept (1 in %d traces)", traceID, so.rate)
+		if !traceSampled(traceID, so.rate) {
+			step.Outcome = ExplainDrop
+			step.Detail = fmt.Sprintf("trace %s is dropped (1 in %d traces kept)", traceID, so.rate)
+			return []ExplainStep{step}
+		}
+	} else if so.rate == 1 {
+		step.Outcome = ExplainPass
+	}
+	return append([]ExplainStep{step}, nestSteps(ExplainOutput(so.output, entry))...)
+}
+
+// ExplainEntry reports the queue; full queues fall back to synchronous
+// writes, so nothing is dropped.
+func (ao *AsyncOutput) ExplainEntry(entry LogEntry) []ExplainStep {
+	step := ExplainStep{Stage: "AsyncOutput", Outcome: ExplainPass,
+		Detail: fmt.Sprintf("queue %d/%d", ao.worker.QueueSize(), ao.worker.QueueCapacity())}
+	if ao.worker.IsClosed() {
+		step.Outcome, step.Detail = ExplainDrop, "output is closed"
+		return []ExplainStep{step}
+	}
+	return append([]ExplainStep{step}, nestSteps(ExplainOutput(ao.output, entry))...)
+}
+
+// ExplainEntry reports the entry being routed to the primary and a tagged
+// copy to the candidate, which is dropped while its queue is full.
+func (so *ShadowOutput) ExplainEntry(entry LogEntry) []ExplainStep {
+	steps := []ExplainStep{{Stage: "ShadowOutput", Outcome: ExplainRoute, Detail: "to primary and candidate"}}
+	steps = append(steps, nestSteps(ExplainOutput(so.config.Primary, entry))...)
+
+	candidate := ExplainStep{Stage: "shadow candidate queue", Outcome: ExplainPass, Depth: 1,
+		Detail: fmt.Sprintf("queue %d/%d", so.worker.QueueSize(), so.worker.QueueCapacity())}
+	if so.worker.QueueSize() >= so.worker.QueueCapacity() {
+		candidate.Outcome = ExplainDrop
+		candidate.Detail += ", full"
+	}
+	steps = append(steps, candidate)
+	if candidate.Outcome == ExplainPass {
+		steps = append(steps, nestSteps(nestSteps(ExplainOutput(so.config.Candidate, entry)))...)
+	}
+	return steps
+}
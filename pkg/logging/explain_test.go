@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExplainLogger_Level(t *testing.T) {
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithLevel(WarnLevel).WithWriter(&bytes.Buffer{}).Build())
+
+	e := ExplainLogger(logger, context.Background(), InfoLevel, nil)
+	if !e.Dropped() || e.Steps[0].Stage != "level" {
+		t.Errorf("expected the level check to drop INFO:\n%s", e)
+	}
+	if !strings.Contains(e.String(), "INFO is below the minimum WARN") {
+		t.Errorf("unexpected explanation:\n%s", e)
+	}
+
+	if e := ExplainLogger(logger, context.Background(), ErrorLevel, nil); e.Dropped() {
+		t.Errorf("expected ERROR to pass:\n%s", e)
+	}
+}
+
+func TestExplainLogger_Outputs(t *testing.T) {
+	sampled := NewSamplingOutput(NewWriterOutput(&bytes.Buffer{}), 10)
+	async := NewAsyncOutput(NewWriterOutput(&bytes.Buffer{}), 4)
+	defer async.Stop()
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithCustomOutput(NewMultiOutput(sampled, async)).
+		Build())
+
+	e := ExplainLogger(logger, context.Background(), InfoLevel, map[string]interface{}{"component": "cache"})
+	if e.Dropped() {
+		t.Fatalf("expected the entry to reach the outputs:\n%s", e)
+	}
+
+	var got []string
+	for _, step := range e.Steps {
+		got = append(got, strings.Repeat(">", step.Depth)+step.Stage+":"+string(step.Outcome))
+	}
+	want := []string{
+		"level:pass",
+		"MultiOutput:route",
+		">SamplingOutput:maybe",
+		">>*logging.WriterOutput:pass",
+		">AsyncOutput:pass",
+		">>*logging.WriterOutput:pass",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("unexpected steps %v, want %v", got, want)
+	}
+}
+
+func TestExplainLogger_Slog(t *testing.T) {
+	logger := NewWithLoggerConfig(NewLoggerConfig().UseSlog(true).WithWriter(&bytes.Buffer{}).Build())
+	e := ExplainLogger(logger, context.Background(), InfoLevel, nil)
+	if e.Dropped() || len(e.Steps) != 2 || e.Steps[1].Stage != "slog handler" {
+		t.Errorf("unexpected explanation:\n%s", e)
+	}
+}
+
+func TestExplain_DefaultLogger(t *testing.T) {
+	previous := GetDefaultLogger()
+	defer SetDefaultLogger(previous)
+
+	SetDefaultLogger(NewWithLoggerConfig(NewLoggerConfig().WithLevel(ErrorLevel).WithWriter(&bytes.Buffer{}).Build()))
+	if e := Explain(context.Background(), DebugLevel, nil); !e.Dropped() {
+		t.Errorf("expected the default logger to drop DEBUG:\n%s", e)
+	}
+}
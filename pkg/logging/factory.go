@@ -87,6 +87,16 @@ func NewTextLogger(level Level) Logger {
 	return ProvideLogger(config, redactorChain)
 }
 
+// NewAuto creates an INFO logger writing to stdout in the form that suits
+// its destination: colored ConsoleFormatter output when stdout is a terminal
+// and JSON when it is piped or redirected, e.g. to a log collector.
+func NewAuto() Logger {
+	return NewWithLoggerConfig(NewLoggerConfig().
+		WithAutoFormat().
+		WithWriter(os.Stdout).
+		Build())
+}
+
 func NewWithHandler(handler slog.Handler) Logger {
 	config := NewConfig().
 		WithHandler(handler).
@@ -184,11 +194,13 @@ func MustGetEnv(key string) string {
 // NewStandardLogger creates a standard logger with the specified config and redactor chain.
 // Deprecated: Use New() or NewWithLoggerConfig() for new code.
 func NewStandardLogger(config *Config, redactorChain RedactorChainInterface) Logger {
+	reportDeprecated("NewStandardLogger", "New or NewWithLoggerConfig")
 	return ProvideLogger(config, redactorChain)
 }
 
 // NewSlogLogger creates a slog-based logger with the specified config and redactor chain.
 // Deprecated: Use New() or NewWithLoggerConfig() for new code.
 func NewSlogLogger(config *Config, redactorChain RedactorChainInterface) Logger {
+	reportDeprecated("NewSlogLogger", "New or NewWithLoggerConfig")
 	return ProvideLogger(config, redactorChain)
 }
@@ -53,6 +53,16 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewAuto(t *testing.T) {
+	logger := NewAuto()
+	if logger == nil {
+		t.Fatal("expected logger to be created")
+	}
+	if format := logger.(*unifiedLogger).config.Formatter.Format; format == AutoFormat {
+		t.Error("expected the format to be resolved")
+	}
+}
+
 func TestNewWithLoggerConfig(t *testing.T) {
 	buf := &bytes.Buffer{}
 	config := NewLoggerConfig().
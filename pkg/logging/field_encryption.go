@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix starts every value produced by FieldEncryptor.
+const encryptedPrefix = "enc:v1:"
+
+// FieldEncryptor encrypts selected fields with AES-GCM so values such as an
+// email address stay recoverable by tooling that holds the key, unlike
+// redaction which destroys them. Encrypted values look like
+// "enc:v1:<key id>:<base64>"; the field name is authenticated, so a value
+// copied to another field fails to decrypt. Strings are encrypted as-is and
+// other values as JSON. Messages are not encrypted.
+//
+// Example:
+//
+//	enc, err := logging.NewFieldEncryptor(key, "2024-06", "email", "phone")
+//	if err != nil {
+//		return err
+//	}
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithFieldEncryption(enc).
+//		Build())
+//
+//	// In support tooling
+//	email, err := enc.Decrypt("email", entry.Fields["email"].(string))
+type FieldEncryptor struct {
+	aead   cipher.AEAD
+	keyID  string
+	fields map[string]bool
+}
+
+// NewFieldEncryptor creates a FieldEncryptor for fields. key must be 16, 24
+// or 32 bytes. keyID is stored with each value to tell which key decrypts
+// it after rotation; it must not contain ':'.
+func NewFieldEncryptor(key []byte, keyID string, fields ...string) (*FieldEncryptor, error) {
+	if strings.Contains(keyID, ":") {
+		return nil, fmt.Errorf("invalid key ID %q: must not contain ':'", keyID)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create field cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create field cipher: %w", err)
+	}
+
+	fe := &FieldEncryptor{aead: aead, keyID: keyID, fields: make(map[string]bool, len(fields))}
+	for _, field := range fields {
+		fe.fields[field] = true
+	}
+	return fe, nil
+}
+
+// Encrypt encrypts plaintext as the value of field.
+func (fe *FieldEncryptor) Encrypt(field, plaintext string) (string, error) {
+	nonce := make([]byte, fe.aead.NonceSize(), fe.aead.NonceSize()+len(plaintext)+fe.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := fe.aead.Seal(nonce, nonce, []byte(plaintext), []byte(field))
+	return encryptedPrefix + fe.keyID + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the plaintext of value, which must have been encrypted
+// for field with this encryptor's key.
+func (fe *FieldEncryptor) Decrypt(field, value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return "", fmt.Errorf("value of %s is not encrypted", field)
+	}
+	keyID, encoded, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", fmt.Errorf("malformed encrypted value of %s", field)
+	}
+	if keyID != fe.keyID {
+		return "", fmt.Errorf("value of %s was encrypted with key %q, not %q", field, keyID, fe.keyID)
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < fe.aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted value of %s", field)
+	}
+	nonce, ciphertext := sealed[:fe.aead.NonceSize()], sealed[fe.aead.NonceSize():]
+	plaintext, err := fe.aead.Open(nil, nonce, ciphertext, []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", field, err)
+	}
+	return string(plaintext), nil
+}
+
+// DecryptFields returns a copy of fields with every value this encryptor
+// can decrypt replaced by its plaintext. Use it on entries read back with
+// ParseJSONEntry or Query.
+func (fe *FieldEncryptor) DecryptFields(fields map[string]interface{}) map[string]interface{} {
+	decrypted := copyFields(fields, 0)
+	for k, v := range fields {
+		if s, ok := v.(string); ok && IsEncryptedValue(s) {
+			if plaintext, err := fe.Decrypt(k, s); err == nil {
+				decrypted[k] = plaintext
+			}
+		}
+	}
+	return decrypted
+}
+
+// IsEncryptedValue reports whether s was produced by a FieldEncryptor.
+func IsEncryptedValue(s string) bool {
+	return strings.HasPrefix(s, encryptedPrefix)
+}
+
+// apply returns fields with the configured fields encrypted. fields is
+// treated as read-only. A value that cannot be encrypted is replaced rather
+// than logged in the clear.
+func (fe *FieldEncryptor) apply(fields map[string]interface{}) map[string]interface{} {
+	var encrypted map[string]interface{}
+	for k, v := range fields {
+		if !fe.fields[k] {
+			continue
+		}
+		if encrypted == nil {
+			encrypted = copyFields(fields, 0)
+		}
+		encrypted[k] = fe.encryptValue(k, v)
+	}
+	if encrypted == nil {
+		return fields
+	}
+	return encrypted
+}
+
+func (fe *FieldEncryptor) encryptValue(field string, value interface{}) string {
+	plaintext, ok := value.(string)
+	if !ok {
+		data, err := json.Marshal(value)
+		if err != nil {
+			data = []byte(fmt.Sprint(value))
+		}
+		plaintext = string(data)
+	}
+	ciphertext, err := fe.Encrypt(field, plaintext)
+	if err != nil {
+		return "<ENCRYPTION_FAILED>"
+	}
+	return ciphertext
+}
@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+var testFieldKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestNewFieldEncryptor_Validation(t *testing.T) {
+	if _, err := NewFieldEncryptor([]byte("short"), "k1", "email"); err == nil {
+		t.Error("expected error for an invalid key size")
+	}
+	if _, err := NewFieldEncryptor(testFieldKey, "k:1", "email"); err == nil {
+		t.Error("expected error for a key ID containing ':'")
+	}
+}
+
+func TestFieldEncryptor_RoundTrip(t *testing.T) {
+	enc, err := NewFieldEncryptor(testFieldKey, "k1", "email")
+	if err != nil {
+		t.Fatalf("NewFieldEncryptor returned error: %v", err)
+	}
+
+	value, err := enc.Encrypt("email", "ada@example.com")
+	if err != nil {
+		t.Fatalf("Encrypt returned error: %v", err)
+	}
+	if !strings.HasPrefix(value, "enc:v1:k1:") || strings.Contains(value, "ada") {
+		t.Errorf("unexpected encrypted value %q", value)
+	}
+	if !IsEncryptedValue(value) {
+		t.Error("expected IsEncryptedValue to recognize the value")
+	}
+
+	other, _ := enc.Encrypt("email", "ada@example.com")
+	if other == value {
+		t.Error("expected a fresh nonce for each encryption")
+	}
+
+	plaintext, err := enc.Decrypt("email", value)
+	if err != nil || plaintext != "ada@example.com" {
+		t.Errorf("Decrypt() = %q, %v", plaintext, err)
+	}
+	if _, err := enc.Decrypt("phone", value); err == nil {
+		t.Error("expected a value moved to another field to fail")
+	}
+
+	rotated, _ := NewFieldEncryptor(testFieldKey, "k2", "email")
+	if _, err := rotated.Decrypt("email", value); err == nil {
+		t.Error("expected a key ID mismatch to fail")
+	}
+	if _, err := enc.Decrypt("email", "plain"); err == nil {
+		t.Error("expected an unencrypted value to fail")
+	}
+}
+
+func TestLogger_FieldEncryption(t *testing.T) {
+	enc, err := NewFieldEncryptor(testFieldKey, "k1", "email", "account")
+	if err != nil {
+		t.Fatalf("NewFieldEncryptor returned error: %v", err)
+	}
+
+	for _, useSlog := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		logger := NewWithLoggerConfig(NewLoggerConfig().
+			WithJSONFormat().
+			WithWriter(buf).
+			UseSlog(useSlog).
+			WithFieldEncryption(enc).
+			Build())
+
+		logger.WithFields(map[string]interface{}{
+			"email":   "ada@example.com",
+			"account": 42,
+			"plan":    "pro",
+		}).Info("signup")
+
+		if strings.Contains(buf.String(), "ada@example.com") {
+			t.Fatalf("slog=%v: plaintext leaked: %s", useSlog, buf.String())
+		}
+		entry, ok := ParseJSONEntry(bytes.TrimSpace(buf.Bytes()))
+		if !ok {
+			t.Fatalf("slog=%v: expected JSON entry, got %s", useSlog, buf.String())
+		}
+		fields := enc.DecryptFields(entry.Fields)
+		if fields["email"] != "ada@example.com" || fields["account"] != "42" || fields["plan"] != "pro" {
+			t.Errorf("slog=%v: unexpected decrypted fields %v", useSlog, fields)
+		}
+	}
+}
+
+func TestYAMLFieldEncryption(t *testing.T) {
+	t.Setenv("TEST_LOG_FIELD_KEY", base64.StdEncoding.EncodeToString(testFieldKey))
+
+	var yamlConfig YAMLConfig
+	if err := unmarshalYAMLConfig([]byte(`
+encrypt_fields:
+  fields: [email]
+  key_env: TEST_LOG_FIELD_KEY
+  key_id: k1
+`), &yamlConfig); err != nil {
+		t.Fatalf("unmarshalYAMLConfig() error = %v", err)
+	}
+	builder := NewLoggerConfig()
+	if err := configureCoreFromYAML(builder, &yamlConfig); err != nil {
+		t.Fatalf("configureCoreFromYAML() error = %v", err)
+	}
+	if builder.Build().Core.FieldEncryptor == nil {
+		t.Error("expected a field encryptor")
+	}
+
+	if _, err := LoadFromYAMLString("encrypt_fields:\n  fields: [email]\n  key_env: TEST_LOG_MISSING_KEY\n"); err == nil {
+		t.Error("expected error when the key variable is unset")
+	}
+}
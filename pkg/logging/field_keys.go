@@ -0,0 +1,78 @@
+package logging
+
+import "log/slog"
+
+// FieldKeys rename the timestamp, level and message keys of JSON output,
+// for downstream systems that require specific names. Empty keys keep the
+// defaults: "timestamp", "level" and "message" for JSONFormatter, and
+// "time", "level" and "msg" for the slog JSON handler.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithFieldKeys(logging.FieldKeys{Timestamp: "@timestamp", Level: "severity", Message: "msg"}).
+//		Build()
+//	// {"@timestamp":"2025-11-24T10:30:00Z","msg":"started","severity":"INFO"}
+type FieldKeys struct {
+	Timestamp string
+	Level     string
+	Message   string
+}
+
+// orDefaults returns k with empty keys replaced by timestamp, level and
+// message.
+func (k FieldKeys) orDefaults(timestamp, level, message string) FieldKeys {
+	if k.Timestamp == "" {
+		k.Timestamp = timestamp
+	}
+	if k.Level == "" {
+		k.Level = level
+	}
+	if k.Message == "" {
+		k.Message = message
+	}
+	return k
+}
+
+// jsonKeys returns the keys JSONFormatter writes.
+func (k FieldKeys) jsonKeys() FieldKeys {
+	return k.orDefaults("timestamp", "level", "message")
+}
+
+// replaceSlogKeys returns a slog.HandlerOptions.ReplaceAttr function that
+// renames the handler's built-in keys, or nil when no key is renamed.
+func (k FieldKeys) replaceSlogKeys() func([]string, slog.Attr) slog.Attr {
+	if k == (FieldKeys{}) {
+		return nil
+	}
+	keys := k.orDefaults(slog.TimeKey, slog.LevelKey, slog.MessageKey)
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+		switch a.Key {
+		case slog.TimeKey:
+			a.Key = keys.Timestamp
+		case slog.LevelKey:
+			a.Key = keys.Level
+		case slog.MessageKey:
+			a.Key = keys.Message
+		}
+		return a
+	}
+}
+
+// WithFieldKeys renames the timestamp, level and message keys of JSON
+// output.
+func (b *FormatterConfigBuilder) WithFieldKeys(keys FieldKeys) *FormatterConfigBuilder {
+	b.config.Keys = keys
+	return b
+}
+
+// WithFieldKeys renames the timestamp, level and message keys written by
+// JSONFormatter and, with UseSlog, by the slog JSON handler.
+func (b *LoggerConfigBuilder) WithFieldKeys(keys FieldKeys) *LoggerConfigBuilder {
+	b.config.Formatter.Keys = keys
+	return b
+}
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFieldKeys = FieldKeys{Timestamp: "@timestamp", Level: "severity", Message: "msg"}
+
+func TestFieldKeys_JSONFormatter(t *testing.T) {
+	formatter := NewJSONFormatter(NewFormatterConfig().IncludeFile(false).WithFieldKeys(testFieldKeys).Build())
+	data, err := formatter.Format(LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 0, time.UTC),
+		Level:     WarnLevel,
+		Message:   "disk almost full",
+		Fields:    map[string]interface{}{"disk": "sda1"},
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	want := `{"@timestamp":"2025-11-24T10:30:00Z","disk":"sda1","msg":"disk almost full","severity":"WARN"}` + "\n"
+	if string(data) != want {
+		t.Errorf("unexpected output\n got: %s\nwant: %s", data, want)
+	}
+
+	data, _ = NewJSONFormatter(NewFormatterConfig().IncludeFile(false).WithFieldKeys(FieldKeys{Level: "severity"}).Build()).
+		Format(LogEntry{Level: InfoLevel, Message: "hi"})
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil || got["severity"] != "INFO" || got["message"] != "hi" {
+		t.Errorf("expected only the level key renamed, got %s", data)
+	}
+}
+
+func TestFieldKeys_Slog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithWriter(buf).
+		WithJSONFormat().
+		WithFieldKeys(testFieldKeys).
+		UseSlog(true).
+		Build())
+	logger.Info("started")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.Bytes())
+	}
+	if got["msg"] != "started" || got["severity"] != "INFO" || got["@timestamp"] == nil || got["time"] != nil || got["level"] != nil {
+		t.Errorf("expected renamed keys, got %s", buf.Bytes())
+	}
+}
+
+func TestFieldKeys_YAMLAndSchema(t *testing.T) {
+	logger, err := LoadFromYAMLString(`
+format: json
+include_time: true
+field_keys:
+  timestamp: "@timestamp"
+  level: severity
+  message: msg
+output:
+  type: stdout
+`)
+	if err != nil {
+		t.Fatalf("LoadFromYAMLString returned error: %v", err)
+	}
+	schema := SchemaDescriptorLogger(logger)
+	var names []string
+	for _, f := range schema.Fields {
+		names = append(names, f.Name)
+	}
+	if len(names) < 3 || names[0] != "@timestamp" || names[1] != "severity" || names[2] != "msg" {
+		t.Errorf("expected the schema to use the renamed keys, got %v", names)
+	}
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	if err := SaveToYAML(NewLoggerConfig().WithJSONFormat().WithFieldKeys(testFieldKeys).Build(), path); err != nil {
+		t.Fatalf("SaveToYAML returned error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "field_keys:") || !strings.Contains(string(data), "level: severity") {
+		t.Errorf("expected the field keys to be saved, got\n%s", data)
+	}
+}
@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldOrder is the order formatters write an entry's fields in.
+type FieldOrder int
+
+const (
+	// AlphabeticalFieldOrder writes fields sorted by key, the default.
+	AlphabeticalFieldOrder FieldOrder = iota
+	// InsertionFieldOrder writes fields in the order they were added:
+	// static fields, then those added by WithField and WithFields, then
+	// the fields of the call. JSON output starts with the timestamp, level,
+	// message and file.
+	InsertionFieldOrder
+)
+
+// fieldOrderNames are the names of each FieldOrder in configuration.
+var fieldOrderNames = map[FieldOrder]string{
+	AlphabeticalFieldOrder: "alphabetical",
+	InsertionFieldOrder:    "insertion",
+}
+
+// String returns the name of o, such as "insertion".
+func (o FieldOrder) String() string {
+	if name, ok := fieldOrderNames[o]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseFieldOrder returns the order named by name: "alphabetical" or
+// "insertion".
+func ParseFieldOrder(name string) (FieldOrder, bool) {
+	for order, orderName := range fieldOrderNames {
+		if strings.EqualFold(name, orderName) {
+			return order, true
+		}
+	}
+	return AlphabeticalFieldOrder, false
+}
+
+// orderFieldKeys returns the keys of fields in output order: the
+// PriorityFields that are set, then inserted with InsertionFieldOrder, then
+// the remaining keys sorted.
+func (c *FormatterConfig) orderFieldKeys(fields map[string]interface{}, inserted []string) []string {
+	keys := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	add := func(k string) {
+		if _, ok := fields[k]; ok && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range c.PriorityFields {
+		add(k)
+	}
+	if c.FieldOrder == InsertionFieldOrder {
+		for _, k := range inserted {
+			add(k)
+		}
+	}
+
+	ordered := len(keys)
+	for k := range fields {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys[ordered:])
+	return keys
+}
+
+// ordersFields reports whether fields are written in an order other than
+// encoding/json's sorted map keys.
+func (c *FormatterConfig) ordersFields() bool {
+	return c.FieldOrder != AlphabeticalFieldOrder || len(c.PriorityFields) > 0
+}
+
+// insertionOrder returns the keys of the logger's fields in the order they
+// were added, followed by the sorted keys of extra.
+func (ul *unifiedLogger) insertionOrder(extra map[string]interface{}) []string {
+	order := make([]string, 0, len(ul.config.Core.StaticFields)+len(ul.fieldOrder)+len(extra))
+	order = append(order, sortedKeys(ul.config.Core.StaticFields)...)
+	order = append(order, ul.fieldOrder...)
+	return append(order, sortedKeys(extra)...)
+}
+
+// appendFieldOrder returns order followed by the keys of fields missing
+// from existing, sorted.
+func appendFieldOrder(order []string, existing, fields map[string]interface{}) []string {
+	var added []string
+	for k := range fields {
+		if _, ok := existing[k]; !ok {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	return append(append(make([]string, 0, len(order)+len(added)), order...), added...)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WithFieldOrder sets the order fields are written in, after the priority
+// keys, which come first in the order given.
+func (b *FormatterConfigBuilder) WithFieldOrder(order FieldOrder, priority ...string) *FormatterConfigBuilder {
+	b.config.FieldOrder = order
+	b.config.PriorityFields = priority
+	return b
+}
+
+// WithFieldOrder sets the order JSON, text, console and pretty output and
+// the slog handlers write fields in, after the priority keys, which come
+// first in the order given. Fields are sorted by default.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithFieldOrder(logging.InsertionFieldOrder, "request_id").
+//		Build()
+//	logger := logging.NewWithLoggerConfig(config).WithField("user", "alice").WithField("action", "login")
+//	logger.Info("signed in")
+//	// {"timestamp":"...","level":"INFO","message":"signed in","user":"alice","action":"login"}
+func (b *LoggerConfigBuilder) WithFieldOrder(order FieldOrder, priority ...string) *LoggerConfigBuilder {
+	b.config.Formatter.FieldOrder = order
+	b.config.Formatter.PriorityFields = priority
+	return b
+}
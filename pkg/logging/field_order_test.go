@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func fieldOrderTestLogger(buf *bytes.Buffer, configure func(*LoggerConfigBuilder) *LoggerConfigBuilder) Logger {
+	builder := NewLoggerConfig().
+		WithWriter(buf).
+		WithCore(NewCoreConfig().WithStaticField("service", "api").Build()).
+		WithFormatter(NewFormatterConfig().IncludeTime(false).IncludeFile(false).Build())
+	return NewWithLoggerConfig(configure(builder).Build()).
+		WithField("zone", "eu").
+		WithField("user", "alice").
+		WithFields(map[string]interface{}{"b": 2, "a": 1})
+}
+
+func TestFieldOrder_JSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*LoggerConfigBuilder) *LoggerConfigBuilder
+		want      string
+	}{
+		{
+			name:      "alphabetical",
+			configure: func(b *LoggerConfigBuilder) *LoggerConfigBuilder { return b.WithJSONFormat() },
+			want:      `{"a":1,"b":2,"level":"INFO","message":"hi","service":"api","user":"alice","zone":"eu"}`,
+		},
+		{
+			name: "insertion",
+			configure: func(b *LoggerConfigBuilder) *LoggerConfigBuilder {
+				return b.WithJSONFormat().WithFieldOrder(InsertionFieldOrder)
+			},
+			want: `{"level":"INFO","message":"hi","service":"api","zone":"eu","user":"alice","a":1,"b":2}`,
+		},
+		{
+			name: "priority",
+			configure: func(b *LoggerConfigBuilder) *LoggerConfigBuilder {
+				return b.WithJSONFormat().WithFieldOrder(AlphabeticalFieldOrder, "message", "user")
+			},
+			want: `{"message":"hi","user":"alice","a":1,"b":2,"level":"INFO","service":"api","zone":"eu"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 5; i++ {
+				buf := &bytes.Buffer{}
+				fieldOrderTestLogger(buf, tt.configure).Info("hi")
+				if got := strings.TrimSpace(buf.String()); got != tt.want {
+					t.Fatalf("unexpected output\n got: %s\nwant: %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFieldOrder_Text(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		buf := &bytes.Buffer{}
+		fieldOrderTestLogger(buf, func(b *LoggerConfigBuilder) *LoggerConfigBuilder { return b.WithTextFormat() }).Info("hi")
+		if want := "[INFO] hi {a=1 b=2 service=api user=alice zone=eu}\n"; buf.String() != want {
+			t.Fatalf("got %q, want %q", buf.String(), want)
+		}
+
+		buf.Reset()
+		fieldOrderTestLogger(buf, func(b *LoggerConfigBuilder) *LoggerConfigBuilder {
+			return b.WithTextFormat().WithFieldOrder(InsertionFieldOrder, "user")
+		}).Info("hi")
+		if want := "[INFO] hi {user=alice service=api zone=eu a=1 b=2}\n"; buf.String() != want {
+			t.Fatalf("got %q, want %q", buf.String(), want)
+		}
+	}
+}
+
+func TestFieldOrder_Slog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fieldOrderTestLogger(buf, func(b *LoggerConfigBuilder) *LoggerConfigBuilder {
+		return b.WithJSONFormat().UseSlog(true).WithFieldOrder(InsertionFieldOrder)
+	}).Info("hi")
+	if want := `"service":"api","zone":"eu","user":"alice","a":1,"b":2}`; !strings.HasSuffix(strings.TrimSpace(buf.String()), want) {
+		t.Errorf("expected fields in insertion order, got %s", buf.String())
+	}
+}
+
+func TestFieldOrder_YAML(t *testing.T) {
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "json", FieldOrder: "insertion", PriorityFields: []string{"trace_id"}}); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	config := builder.Build()
+	if config.Formatter.FieldOrder != InsertionFieldOrder || len(config.Formatter.PriorityFields) != 1 {
+		t.Errorf("unexpected formatter config %+v", config.Formatter)
+	}
+
+	err := configureFormatterFromYAML(NewLoggerConfig(), &YAMLConfig{Format: "json", FieldOrder: "random"})
+	if err == nil || !strings.Contains(err.Error(), "field_order") {
+		t.Errorf("expected a field_order error, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FlagDefiner is the part of a flag set RegisterFlagsOn needs. Both
+// *flag.FlagSet and the *pflag.FlagSet used by cobra implement it.
+type FlagDefiner interface {
+	StringVar(p *string, name string, value string, usage string)
+	BoolVar(p *bool, name string, value bool, usage string)
+}
+
+// LogFlags holds the values of the logging flags once the flag set is
+// parsed.
+type LogFlags struct {
+	Level  string
+	Format string
+	File   string
+	Caller bool
+}
+
+// RegisterFlags defines --log-level, --log-format, --log-file and
+// --log-caller on fs, so every command line tool gets the same logging
+// switches. Build the logger from the returned LogFlags after parsing.
+//
+// Example:
+//
+//	logFlags := logging.RegisterFlags(flag.CommandLine)
+//	flag.Parse()
+//	logger, err := logFlags.Logger()
+func RegisterFlags(fs *flag.FlagSet) *LogFlags {
+	return RegisterFlagsOn(fs)
+}
+
+// RegisterFlagsOn is RegisterFlags for any FlagDefiner, such as a cobra
+// command's flags.
+//
+// Example:
+//
+//	var logFlags = logging.RegisterFlagsOn(rootCmd.PersistentFlags())
+//
+//	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+//		logger, err := logFlags.Logger()
+//		...
+//	}
+func RegisterFlagsOn(fs FlagDefiner) *LogFlags {
+	f := &LogFlags{}
+	fs.StringVar(&f.Level, "log-level", "info", "minimum log level: trace, debug, info, warn, error or critical")
+	fs.StringVar(&f.Format, "log-format", textFormatString, "log format: text, json, auto, gelf, pretty, stackdriver or a registered formatter")
+	fs.StringVar(&f.File, "log-file", "", "append logs to this file instead of standard error")
+	fs.BoolVar(&f.Caller, "log-caller", false, "include the calling file and line in log entries")
+	return f
+}
+
+// Config returns a LoggerConfig built from the flag values.
+func (f *LogFlags) Config() (*LoggerConfig, error) {
+	builder := NewLoggerConfig()
+
+	level, ok := ParseLevel(f.Level)
+	if !ok {
+		return nil, fmt.Errorf("invalid --log-level %q", f.Level)
+	}
+	builder.WithLevel(level)
+
+	switch strings.ToLower(f.Format) {
+	case textFormatString, "":
+		builder.WithTextFormat()
+	case jsonFormatString:
+		builder.WithJSONFormat()
+	case autoFormatString:
+		builder.WithAutoFormat()
+	case gelfFormatString:
+		builder.WithGELFFormat()
+	case prettyFormatString:
+		builder.WithPrettyFormat()
+	case stackdriverFormatString:
+		builder.WithStackdriverFormat()
+	default:
+		if _, ok := registeredFormatter(f.Format); !ok {
+			return nil, fmt.Errorf("invalid --log-format %q (must be 'text', 'json', 'auto', 'gelf', 'pretty', 'stackdriver' or a registered formatter)", f.Format)
+		}
+		builder.WithRegisteredFormat(f.Format)
+	}
+
+	if f.File == "" {
+		builder.WithWriter(os.Stderr)
+	} else {
+		writer, err := createFileWriter(f.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open --log-file: %w", err)
+		}
+		builder.WithWriter(writer)
+	}
+
+	config := builder.Build()
+	config.Formatter.IncludeFile = f.Caller
+	config.Formatter.UseShortFile = f.Caller
+	return config, nil
+}
+
+// Logger returns a Logger configured from the flag values.
+func (f *LogFlags) Logger() (Logger, error) {
+	config, err := f.Config()
+	if err != nil {
+		return nil, err
+	}
+	return NewWithLoggerConfig(config), nil
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("tool", flag.ContinueOnError)
+	logFlags := RegisterFlags(fs)
+
+	file := filepath.Join(t.TempDir(), "tool.log")
+	if err := fs.Parse([]string{"--log-level=debug", "--log-format=json", "--log-file=" + file, "--log-caller"}); err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	config, err := logFlags.Config()
+	if err != nil {
+		t.Fatalf("Config returned error: %v", err)
+	}
+	if config.Core.Level != DebugLevel || config.Formatter.Format != JSONFormat || !config.Formatter.IncludeFile {
+		t.Errorf("unexpected config: level %s, format %v, include file %v", config.Core.Level, config.Formatter.Format, config.Formatter.IncludeFile)
+	}
+
+	logger, _ := logFlags.Logger()
+	logger.Debug("hello")
+	data, _ := os.ReadFile(file)
+	if !strings.Contains(string(data), `"message":"hello"`) || !strings.Contains(string(data), "flags_test.go") {
+		t.Errorf("expected a JSON entry with the caller in the log file, got %q", data)
+	}
+}
+
+func TestRegisterFlags_Defaults(t *testing.T) {
+	fs := flag.NewFlagSet("tool", flag.ContinueOnError)
+	logFlags := RegisterFlags(fs)
+	_ = fs.Parse(nil)
+
+	config, err := logFlags.Config()
+	if err != nil {
+		t.Fatalf("Config returned error: %v", err)
+	}
+	if config.Core.Level != InfoLevel || config.Formatter.Format != TextFormat || config.Output.Writer != os.Stderr {
+		t.Errorf("unexpected defaults: level %s, format %v", config.Core.Level, config.Formatter.Format)
+	}
+}
+
+func TestLogFlags_Invalid(t *testing.T) {
+	for _, args := range [][]string{{"--log-level=loud"}, {"--log-format=xml"}} {
+		fs := flag.NewFlagSet("tool", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		logFlags := RegisterFlags(fs)
+		_ = fs.Parse(args)
+		if _, err := logFlags.Logger(); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
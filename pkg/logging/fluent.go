@@ -2,6 +2,12 @@ package logging
 
 import "context"
 
+// NewFluentLogger returns a FluentLogger that writes entries through logger.
+// Custom Logger implementations can use it to implement Fluent.
+func NewFluentLogger(logger Logger) FluentLogger {
+	return &fluentLoggerWrapper{logger: logger}
+}
+
 type fluentLoggerWrapper struct {
 	logger Logger
 }
@@ -40,7 +46,8 @@ func (w *fluentLoggerWrapper) Critical() *FluentEntry {
 
 // FluentEntry represents a fluent logging entry that can be configured
 // with structured fields before being output. Methods can be chained
-// until Msg() or Msgf() is called to output the log entry.
+// until Msg() or Msgf() is called to output the log entry. A nil
+// *FluentEntry, as returned by the logger from NewNop, discards everything.
 //
 // Example:
 //
@@ -59,12 +66,18 @@ type FluentEntry struct {
 
 // Field adds a key-value pair to the log entry and returns the entry for chaining.
 func (e *FluentEntry) Field(key string, value interface{}) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	e.fields[key] = value
 	return e
 }
 
 // Fields adds multiple key-value pairs to the log entry and returns the entry for chaining.
 func (e *FluentEntry) Fields(fields map[string]interface{}) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	for k, v := range fields {
 		e.fields[k] = v
 	}
@@ -73,24 +86,36 @@ func (e *FluentEntry) Fields(fields map[string]interface{}) *FluentEntry {
 
 // Str adds a string field to the log entry and returns the entry for chaining.
 func (e *FluentEntry) Str(key, value string) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	e.fields[key] = value
 	return e
 }
 
 // Int adds an integer field to the log entry and returns the entry for chaining.
 func (e *FluentEntry) Int(key string, value int) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	e.fields[key] = value
 	return e
 }
 
 // Int64 adds a 64-bit integer field to the log entry and returns the entry for chaining.
 func (e *FluentEntry) Int64(key string, value int64) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	e.fields[key] = value
 	return e
 }
 
 // Bool adds a boolean field to the log entry and returns the entry for chaining.
 func (e *FluentEntry) Bool(key string, value bool) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	e.fields[key] = value
 	return e
 }
@@ -98,6 +123,9 @@ func (e *FluentEntry) Bool(key string, value bool) *FluentEntry {
 // Err adds an error field to the log entry and returns the entry for chaining.
 // If err is nil, no field is added.
 func (e *FluentEntry) Err(err error) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	if err != nil {
 		e.fields["error"] = err.Error()
 	}
@@ -107,6 +135,9 @@ func (e *FluentEntry) Err(err error) *FluentEntry {
 // TraceID adds a trace identifier to the log entry and returns the entry for chaining.
 // The trace ID will appear as "trace_id" in the output.
 func (e *FluentEntry) TraceID(id string) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	e.traceID = id
 	e.fields["trace_id"] = id
 	return e
@@ -120,6 +151,9 @@ func (e *FluentEntry) TraceID(id string) *FluentEntry {
 //	ctx := logging.WithTraceID(context.Background(), "trace-123")
 //	logger.Fluent().Info().Ctx(ctx).Msg("Processing request")
 func (e *FluentEntry) Ctx(ctx context.Context) *FluentEntry {
+	if e == nil {
+		return e
+	}
 	e.ctx = ctx
 
 	if traceID, ok := GetTraceID(ctx); ok {
@@ -138,6 +172,9 @@ func (e *FluentEntry) Ctx(ctx context.Context) *FluentEntry {
 // Msg outputs the log entry with the specified message.
 // This is the terminal method that actually writes the log.
 func (e *FluentEntry) Msg(msg string) {
+	if e == nil {
+		return
+	}
 	logger := e.logger.WithFields(e.fields)
 	e.dispatch(logger, msg, nil)
 }
@@ -151,6 +188,9 @@ func (e *FluentEntry) Msg(msg string) {
 //		Str("user", username).
 //		Msgf("User %s logged in at %s", username, time.Now())
 func (e *FluentEntry) Msgf(format string, args ...interface{}) {
+	if e == nil {
+		return
+	}
 	logger := e.logger.WithFields(e.fields)
 	e.dispatch(logger, format, args)
 }
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FormatterFactory creates a formatter with the logger's formatter
+// configuration.
+type FormatterFactory func(config *FormatterConfig) Formatter
+
+var (
+	formatterFactoriesMu sync.RWMutex
+	formatterFactories   = make(map[string]FormatterFactory)
+)
+
+// builtinFormatNames are the format names RegisterFormatter cannot take.
+var builtinFormatNames = []string{
+	jsonFormatString, textFormatString, autoFormatString, gelfFormatString,
+	templateFormatString, prettyFormatString, stackdriverFormatString,
+}
+
+// RegisterFormatter makes a formatter selectable by name, so YAML
+// configuration (format: NAME), LOG_FORMAT and --log-format can use it like
+// a built-in format. Names are case-insensitive and cannot be those of
+// built-in formats. Register formatters before loading configuration,
+// typically from an init function.
+//
+// Example:
+//
+//	func init() {
+//		_ = logging.RegisterFormatter("mycorp-audit", func(config *logging.FormatterConfig) logging.Formatter {
+//			return audit.NewFormatter(config)
+//		})
+//	}
+func RegisterFormatter(name string, factory func(*FormatterConfig) Formatter) error {
+	if factory == nil {
+		return fmt.Errorf("formatter factory cannot be nil")
+	}
+	name = strings.ToLower(name)
+	if name == "" {
+		return fmt.Errorf("formatter name cannot be empty")
+	}
+	if containsString(builtinFormatNames, name) {
+		return fmt.Errorf("formatter name %q is a built-in format", name)
+	}
+
+	formatterFactoriesMu.Lock()
+	defer formatterFactoriesMu.Unlock()
+
+	if _, exists := formatterFactories[name]; exists {
+		return fmt.Errorf("formatter with name %q already registered", name)
+	}
+	formatterFactories[name] = factory
+	return nil
+}
+
+// UnregisterFormatter removes a registered formatter, reporting whether it
+// was registered.
+func UnregisterFormatter(name string) bool {
+	name = strings.ToLower(name)
+
+	formatterFactoriesMu.Lock()
+	defer formatterFactoriesMu.Unlock()
+
+	if _, exists := formatterFactories[name]; !exists {
+		return false
+	}
+	delete(formatterFactories, name)
+	return true
+}
+
+// ListFormatters returns the names of the registered formatters, sorted.
+func ListFormatters() []string {
+	formatterFactoriesMu.RLock()
+	defer formatterFactoriesMu.RUnlock()
+
+	names := make([]string, 0, len(formatterFactories))
+	for name := range formatterFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registeredFormatter returns the factory registered as name.
+func registeredFormatter(name string) (FormatterFactory, bool) {
+	formatterFactoriesMu.RLock()
+	defer formatterFactoriesMu.RUnlock()
+	factory, ok := formatterFactories[strings.ToLower(name)]
+	return factory, ok
+}
+
+// newRegisteredFormatter creates the registered formatter config names,
+// falling back to the text format when none is registered.
+func newRegisteredFormatter(config *FormatterConfig) Formatter {
+	factory, ok := registeredFormatter(config.FormatName)
+	if !ok {
+		reportInvalidConfig("RegisteredFormat", "using the text format", fmt.Errorf("no formatter registered with name %q", config.FormatName))
+		return NewTextFormatter(config)
+	}
+	return factory(config)
+}
+
+// WithRegisteredFormat formats entries with the formatter registered as
+// name.
+func (b *FormatterConfigBuilder) WithRegisteredFormat(name string) *FormatterConfigBuilder {
+	b.config.Format = RegisteredFormat
+	b.config.FormatName = strings.ToLower(name)
+	return b
+}
+
+// WithRegisteredFormat formats entries with the formatter registered as
+// name by RegisterFormatter. The factory is called when the logger is
+// created; if nothing is registered as name, the logger reports it and
+// uses the text format.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithRegisteredFormat("mycorp-audit").
+//		Build()
+func (b *LoggerConfigBuilder) WithRegisteredFormat(name string) *LoggerConfigBuilder {
+	b.config.Formatter.Format = RegisteredFormat
+	b.config.Formatter.FormatName = strings.ToLower(name)
+	return b
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type auditTestFormatter struct {
+	config *FormatterConfig
+}
+
+func (f *auditTestFormatter) Format(entry LogEntry) ([]byte, error) {
+	return []byte("AUDIT " + f.config.levelName(entry.Level) + " " + entry.Message + "\n"), nil
+}
+
+func registerAuditTestFormatter(t *testing.T) {
+	t.Helper()
+	err := RegisterFormatter("MyCorp-Audit", func(config *FormatterConfig) Formatter {
+		return &auditTestFormatter{config: config}
+	})
+	if err != nil {
+		t.Fatalf("RegisterFormatter returned error: %v", err)
+	}
+	t.Cleanup(func() { UnregisterFormatter("mycorp-audit") })
+}
+
+func TestRegisterFormatter_YAML(t *testing.T) {
+	registerAuditTestFormatter(t)
+
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "mycorp-audit"}); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	config := builder.WithWriter(buf).WithLevelNames(map[Level]string{WarnLevel: "WARNING"}).Build()
+	NewWithLoggerConfig(config).Warn("disk almost full")
+	if want := "AUDIT WARNING disk almost full\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	path := filepath.Join(t.TempDir(), "logging.yaml")
+	if err := SaveToYAML(config, path); err != nil {
+		t.Fatalf("SaveToYAML returned error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "format: mycorp-audit") {
+		t.Errorf("expected the registered name to be saved, got\n%s", data)
+	}
+	if got := ListFormatters(); len(got) != 1 || got[0] != "mycorp-audit" {
+		t.Errorf("unexpected registered formatters %v", got)
+	}
+}
+
+func TestRegisterFormatter_Errors(t *testing.T) {
+	registerAuditTestFormatter(t)
+
+	factory := func(config *FormatterConfig) Formatter { return NewJSONFormatter(config) }
+	tests := []struct {
+		name     string
+		register string
+		factory  func(*FormatterConfig) Formatter
+		want     string
+	}{
+		{name: "nil factory", register: "x", want: "nil"},
+		{name: "empty name", register: "", factory: factory, want: "empty"},
+		{name: "built-in name", register: "JSON", factory: factory, want: "built-in"},
+		{name: "duplicate", register: "mycorp-audit", factory: factory, want: "already registered"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterFormatter(tt.register, tt.factory)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := configureFormatterFromYAML(NewLoggerConfig(), &YAMLConfig{Format: "unknown-audit"}); err == nil {
+		t.Error("expected an error for an unregistered format")
+	}
+}
+
+func TestRegisteredFormat_Unregistered(t *testing.T) {
+	diagnostics := captureDiagnostics(t)
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().WithWriter(buf).
+		WithFormatter(NewFormatterConfig().IncludeTime(false).IncludeFile(false).WithRegisteredFormat("missing").Build()).Build()
+	NewWithLoggerConfig(config).Info("hello")
+	if want := "[INFO] hello\n"; buf.String() != want {
+		t.Errorf("expected the text format fallback, got %q", buf.String())
+	}
+	if got := diagnostics(); len(got) != 1 || got[0].Source != "RegisteredFormat" {
+		t.Errorf("expected a RegisteredFormat diagnostic, got %v", got)
+	}
+}
@@ -1,11 +1,11 @@
 package logging
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
 	"runtime"
 	"strings"
-	"time"
 
 	"github.com/ocrosby/go-logging/pkg/logging/internal"
 )
@@ -23,40 +23,72 @@ func NewJSONFormatter(config *FormatterConfig) *JSONFormatter {
 	return &JSONFormatter{config: config}
 }
 
-// Format formats a log entry as JSON bytes.
+// Format formats a log entry as a newline-terminated JSON object. The entry
+// is encoded into pooled scratch space, and the built-in fields are
+// appended straight from the entry, so the only allocation of an entry
+// with simple field values is the returned slice.
 func (f *JSONFormatter) Format(entry LogEntry) ([]byte, error) {
-	data := make(map[string]interface{})
-
-	f.addBaseFields(entry, data)
-	f.addUserFields(entry, data)
-	f.addFileInfo(entry, data)
-	f.addContextFields(entry, data)
+	state := getJSONEncodeState()
+	defer putJSONEncodeState(state)
+
+	f.addBaseFields(state)
+	f.addUserFields(entry, state)
+	f.addFileInfo(entry, state)
+	f.addContextFields(entry, state)
+
+	var keys []string
+	if f.config.ordersFields() {
+		jsonKeys := f.config.Keys.jsonKeys()
+		order := entry.fieldOrder
+		if f.config.GroupStyle == DottedGroups {
+			_, order = flattenGroups(entry.Fields, order)
+		}
+		inserted := append([]string{jsonKeys.Timestamp, jsonKeys.Level, jsonKeys.Message, "file"}, order...)
+		keys = f.config.orderFieldKeys(state.data, inserted)
+	} else {
+		state.keys = sortedKeysInto(state.keys, state.data)
+		keys = state.keys
+	}
 
-	return json.Marshal(data)
+	buf, err := f.appendObject(state.buf, entry, state, keys)
+	if err != nil {
+		return nil, err
+	}
+	state.buf = buf
+	out := make([]byte, len(buf)+1)
+	copy(out, buf)
+	out[len(buf)] = '\n'
+	return out, nil
 }
 
-func (f *JSONFormatter) addBaseFields(entry LogEntry, data map[string]interface{}) {
+func (f *JSONFormatter) addBaseFields(state *jsonEncodeState) {
+	keys := f.config.Keys.jsonKeys()
 	if f.config.IncludeTime {
-		data["timestamp"] = entry.Timestamp.UTC().Format(time.RFC3339)
+		state.data[keys.Timestamp] = jsonTimestampField
 	}
-	data["level"] = entry.Level.String()
-	data["message"] = f.applyRedaction(entry.Message)
+	state.data[keys.Level] = jsonLevelField
+	state.data[keys.Message] = jsonMessageField
 }
 
-func (f *JSONFormatter) addUserFields(entry LogEntry, data map[string]interface{}) {
-	for k, v := range entry.Fields {
-		data[k] = v
+func (f *JSONFormatter) addUserFields(entry LogEntry, state *jsonEncodeState) {
+	fields := entry.Fields
+	if f.config.GroupStyle == DottedGroups {
+		fields, _ = flattenGroups(fields, nil)
+	}
+	for k, v := range fields {
+		state.data[k] = v
 	}
 }
 
-func (f *JSONFormatter) addFileInfo(entry LogEntry, data map[string]interface{}) {
+func (f *JSONFormatter) addFileInfo(entry LogEntry, state *jsonEncodeState) {
 	if !f.config.IncludeFile {
 		return
 	}
 
 	file, line := f.getFileInfo(entry)
 	if file != "" {
-		data["file"] = f.formatFilename(file, line)
+		state.file, state.line = file, line
+		state.data["file"] = jsonFileField
 	}
 }
 
@@ -72,17 +104,35 @@ func (f *JSONFormatter) getFileInfo(entry LogEntry) (string, int) {
 	return "", 0
 }
 
-func (f *JSONFormatter) addContextFields(entry LogEntry, data map[string]interface{}) {
-	contextFields := internal.ExtractContextFields(entry.Context)
-	contextFields.AddToMap(data)
+func (f *JSONFormatter) addContextFields(entry LogEntry, state *jsonEncodeState) {
+	state.context = contextFieldsFrom(entry.Context)
+	if state.context.TraceID != "" {
+		state.data["trace_id"] = jsonTraceIDField
+	}
+	if state.context.RequestID != "" {
+		state.data["request_id"] = jsonRequestIDField
+	}
+	if state.context.CorrelationID != "" {
+		state.data["correlation_id"] = jsonCorrelationIDField
+	}
 }
 
 func (f *JSONFormatter) applyRedaction(message string) string {
 	return internal.ApplyRedactionPatterns(message, f.config.RedactPatterns)
 }
 
-func (f *JSONFormatter) formatFilename(file string, line int) string {
-	return internal.FormatFilename(file, line, f.config.UseShortFile)
+// contextFieldsFrom extracts the identifiers stored by WithTraceID,
+// WithRequestID and WithCorrelationID. A nil context yields no fields.
+func contextFieldsFrom(ctx context.Context) internal.ContextFields {
+	if ctx == nil {
+		return internal.ContextFields{}
+	}
+
+	var fields internal.ContextFields
+	fields.TraceID, _ = GetTraceID(ctx)
+	fields.RequestID, _ = GetRequestID(ctx)
+	fields.CorrelationID, _ = GetCorrelationID(ctx)
+	return fields
 }
 
 // TextFormatter formats log entries as human-readable text.
@@ -99,39 +149,46 @@ func NewTextFormatter(config *FormatterConfig) *TextFormatter {
 }
 
 // Format formats a log entry as text bytes.
+// Format: timestamp [LEVEL] file:line message {key=value ...} [trace_id=...]
 func (f *TextFormatter) Format(entry LogEntry) ([]byte, error) {
-	var parts []string
+	buf := make([]byte, 0, 128)
 
-	f.addTimestamp(&parts, entry)
-	f.addLevel(&parts, entry)
-	f.addFileInfoText(&parts, entry)
-	f.addMessage(&parts, entry)
-	f.addFieldsText(&parts, entry)
-	f.addContextText(&parts, entry)
+	buf = f.appendTimestamp(buf, entry)
+	buf = f.appendLevel(buf, entry)
+	buf = f.appendFileInfo(buf, entry)
+	buf = f.appendMessage(buf, entry)
+	buf, multiline := f.appendFields(buf, entry)
+	buf = f.appendContext(buf, entry)
+	buf = appendMultilineFields(buf, multiline)
 
-	result := strings.Join(parts, " ") + "\n"
-	return []byte(result), nil
+	return append(buf, '\n'), nil
 }
 
-func (f *TextFormatter) addTimestamp(parts *[]string, entry LogEntry) {
-	if f.config.IncludeTime {
-		*parts = append(*parts, entry.Timestamp.Format("2006/01/02 15:04:05"))
+func (f *TextFormatter) appendTimestamp(buf []byte, entry LogEntry) []byte {
+	if !f.config.IncludeTime {
+		return buf
 	}
+	buf = f.config.appendTime(buf, entry.Timestamp, f.config.TimePrecision.layout("2006/01/02 15:04:05"), nil)
+	return append(buf, ' ')
 }
 
-func (f *TextFormatter) addLevel(parts *[]string, entry LogEntry) {
-	*parts = append(*parts, fmt.Sprintf("[%s]", strings.ToUpper(entry.Level.String())))
+func (f *TextFormatter) appendLevel(buf []byte, entry LogEntry) []byte {
+	buf = append(buf, '[')
+	buf = append(buf, f.config.levelName(entry.Level)...)
+	return append(buf, "] "...)
 }
 
-func (f *TextFormatter) addFileInfoText(parts *[]string, entry LogEntry) {
+func (f *TextFormatter) appendFileInfo(buf []byte, entry LogEntry) []byte {
 	if !f.config.IncludeFile {
-		return
+		return buf
 	}
 
 	file, line := f.getFileInfoText(entry)
-	if file != "" {
-		*parts = append(*parts, f.formatFilename(file, line))
+	if file == "" {
+		return buf
 	}
+	buf = internal.AppendFilename(buf, file, line, f.config.UseShortFile)
+	return append(buf, ' ')
 }
 
 func (f *TextFormatter) getFileInfoText(entry LogEntry) (string, int) {
@@ -146,38 +203,59 @@ func (f *TextFormatter) getFileInfoText(entry LogEntry) (string, int) {
 	return "", 0
 }
 
-func (f *TextFormatter) addMessage(parts *[]string, entry LogEntry) {
-	message := f.applyRedaction(entry.Message)
-	*parts = append(*parts, message)
+func (f *TextFormatter) appendMessage(buf []byte, entry LogEntry) []byte {
+	return append(buf, f.applyRedaction(entry.Message)...)
 }
 
-func (f *TextFormatter) addFieldsText(parts *[]string, entry LogEntry) {
+// appendFields appends the fields that fit on the entry's line and returns
+// those that span several lines, unless CollapseMultiline is set.
+func (f *TextFormatter) appendFields(buf []byte, entry LogEntry) ([]byte, []multilineField) {
 	if len(entry.Fields) == 0 {
-		return
+		return buf, nil
 	}
 
-	var fieldParts []string
-	for k, v := range entry.Fields {
-		fieldParts = append(fieldParts, fmt.Sprintf("%s=%v", k, v))
+	fields, order := flattenGroups(entry.Fields, entry.fieldOrder)
+	var multiline []multilineField
+	written := 0
+	for _, k := range f.config.orderFieldKeys(fields, order) {
+		text, isMultiline := multilineText(fields[k])
+		if isMultiline && !f.config.CollapseMultiline {
+			multiline = append(multiline, multilineField{key: k, text: text})
+			continue
+		}
+		if written == 0 {
+			buf = append(buf, " {"...)
+		} else {
+			buf = append(buf, ' ')
+		}
+		written++
+		buf = append(buf, k...)
+		buf = append(buf, '=')
+		if isMultiline {
+			buf = append(buf, collapseNewlines.Replace(text)...)
+		} else {
+			buf = fmt.Append(buf, fields[k])
+		}
 	}
-	*parts = append(*parts, fmt.Sprintf("{%s}", strings.Join(fieldParts, " ")))
+	if written > 0 {
+		buf = append(buf, '}')
+	}
+	return buf, multiline
 }
 
-func (f *TextFormatter) addContextText(parts *[]string, entry LogEntry) {
-	contextFields := internal.ExtractContextFields(entry.Context)
+func (f *TextFormatter) appendContext(buf []byte, entry LogEntry) []byte {
+	contextFields := contextFieldsFrom(entry.Context).Omit(entry.Fields)
 	if contextText := contextFields.FormatForText(); contextText != "" {
-		*parts = append(*parts, contextText)
+		buf = append(buf, ' ')
+		buf = append(buf, contextText...)
 	}
+	return buf
 }
 
 func (f *TextFormatter) applyRedaction(message string) string {
 	return internal.ApplyRedactionPatterns(message, f.config.RedactPatterns)
 }
 
-func (f *TextFormatter) formatFilename(file string, line int) string {
-	return internal.FormatFilename(file, line, f.config.UseShortFile)
-}
-
 // ConsoleFormatter provides colored output for console/terminal usage.
 type ConsoleFormatter struct {
 	config      *FormatterConfig
@@ -192,19 +270,32 @@ func NewConsoleFormatter(config *FormatterConfig, useColors bool) *ConsoleFormat
 	}
 
 	return &ConsoleFormatter{
-		config:    config,
-		useColors: useColors,
-		levelColors: map[Level]string{
-			TraceLevel:    "\033[36m", // Cyan
-			DebugLevel:    "\033[37m", // White
-			InfoLevel:     "\033[32m", // Green
-			WarnLevel:     "\033[33m", // Yellow
-			ErrorLevel:    "\033[31m", // Red
-			CriticalLevel: "\033[35m", // Magenta
-		},
+		config:      config,
+		useColors:   useColors,
+		levelColors: config.levelColors(),
 	}
 }
 
+// NewConsoleFormatterFor creates a console formatter for output to w,
+// colored when ColorEnabled(w) reports so.
+//
+// Example:
+//
+//	formatter := logging.NewConsoleFormatterFor(nil, os.Stderr)
+func NewConsoleFormatterFor(config *FormatterConfig, w io.Writer) *ConsoleFormatter {
+	return NewConsoleFormatter(config, ColorEnabled(w))
+}
+
+// consoleLevelColors are the ANSI colors of each level in terminal output.
+var consoleLevelColors = map[Level]string{
+	TraceLevel:    "\033[36m", // Cyan
+	DebugLevel:    "\033[37m", // White
+	InfoLevel:     "\033[32m", // Green
+	WarnLevel:     "\033[33m", // Yellow
+	ErrorLevel:    "\033[31m", // Red
+	CriticalLevel: "\033[35m", // Magenta
+}
+
 // Format formats a log entry with optional colors for console output.
 func (f *ConsoleFormatter) Format(entry LogEntry) ([]byte, error) {
 	var parts []string
@@ -212,10 +303,10 @@ func (f *ConsoleFormatter) Format(entry LogEntry) ([]byte, error) {
 	f.addTimestampConsole(&parts, entry)
 	f.addLevelConsole(&parts, entry)
 	f.addMessageConsole(&parts, entry)
-	f.addFieldsConsole(&parts, entry)
+	multiline := f.addFieldsConsole(&parts, entry)
 
-	result := strings.Join(parts, " ") + "\n"
-	return []byte(result), nil
+	result := append([]byte(strings.Join(parts, " ")), f.colorizeMultiline(appendMultilineFields(nil, multiline))...)
+	return append(result, '\n'), nil
 }
 
 func (f *ConsoleFormatter) addTimestampConsole(parts *[]string, entry LogEntry) {
@@ -223,7 +314,7 @@ func (f *ConsoleFormatter) addTimestampConsole(parts *[]string, entry LogEntry)
 		return
 	}
 
-	timestamp := entry.Timestamp.Format("15:04:05")
+	timestamp := f.config.formatTime(entry.Timestamp, "15:04:05", nil)
 	if f.useColors {
 		timestamp = "\033[90m" + timestamp + "\033[0m" // Dark gray
 	}
@@ -231,9 +322,9 @@ func (f *ConsoleFormatter) addTimestampConsole(parts *[]string, entry LogEntry)
 }
 
 func (f *ConsoleFormatter) addLevelConsole(parts *[]string, entry LogEntry) {
-	levelStr := strings.ToUpper(entry.Level.String())
+	levelStr := f.config.levelName(entry.Level)
 	if f.useColors {
-		if color, exists := f.levelColors[entry.Level]; exists {
+		if color := f.levelColors[entry.Level]; color != "" {
 			levelStr = color + levelStr + "\033[0m"
 		}
 	}
@@ -245,20 +336,44 @@ func (f *ConsoleFormatter) addMessageConsole(parts *[]string, entry LogEntry) {
 	*parts = append(*parts, message)
 }
 
-func (f *ConsoleFormatter) addFieldsConsole(parts *[]string, entry LogEntry) {
+// addFieldsConsole adds the fields that fit on the entry's line and returns
+// those that span several lines, unless CollapseMultiline is set.
+func (f *ConsoleFormatter) addFieldsConsole(parts *[]string, entry LogEntry) []multilineField {
 	if len(entry.Fields) == 0 {
-		return
+		return nil
 	}
 
+	fields, order := flattenGroups(entry.Fields, entry.fieldOrder)
 	var fieldParts []string
-	for k, v := range entry.Fields {
-		fieldStr := fmt.Sprintf("%s=%v", k, v)
+	var multiline []multilineField
+	for _, k := range f.config.orderFieldKeys(fields, order) {
+		text, isMultiline := multilineText(fields[k])
+		if isMultiline && !f.config.CollapseMultiline {
+			multiline = append(multiline, multilineField{key: k, text: text})
+			continue
+		}
+		fieldStr := fmt.Sprintf("%s=%v", k, fields[k])
+		if isMultiline {
+			fieldStr = k + "=" + collapseNewlines.Replace(text)
+		}
 		if f.useColors {
 			fieldStr = "\033[90m" + fieldStr + "\033[0m" // Dark gray
 		}
 		fieldParts = append(fieldParts, fieldStr)
 	}
-	*parts = append(*parts, strings.Join(fieldParts, " "))
+	if len(fieldParts) > 0 {
+		*parts = append(*parts, strings.Join(fieldParts, " "))
+	}
+	return multiline
+}
+
+// colorizeMultiline colors continuation lines red, like the error and
+// stack trace values they usually hold.
+func (f *ConsoleFormatter) colorizeMultiline(lines []byte) []byte {
+	if !f.useColors || len(lines) == 0 {
+		return lines
+	}
+	return append(append([]byte("\033[31m"), lines...), "\033[0m"...)
 }
 
 func (f *ConsoleFormatter) applyRedaction(message string) string {
@@ -284,7 +399,7 @@ func (f *CommonLogFormatter) Format(entry LogEntry) ([]byte, error) {
 	host := f.getField(entry, "host", "-")
 	ident := f.getField(entry, "ident", "-")
 	authuser := f.getField(entry, "authuser", "-")
-	timestamp := entry.Timestamp.Format("02/Jan/2006:15:04:05 -0700")
+	timestamp := f.config.formatTime(entry.Timestamp, "02/Jan/2006:15:04:05 -0700", nil)
 	requestLine := f.getField(entry, "request", entry.Message)
 	status := f.getField(entry, "status", "-")
 	bytes := f.getField(entry, "bytes", "-")
@@ -301,3 +416,32 @@ func (f *CommonLogFormatter) getField(entry LogEntry, key string, defaultValue s
 	}
 	return defaultValue
 }
+
+// CombinedLogFormatter formats log entries in the Apache Combined Log
+// Format: the Common Log Format followed by the quoted "referer" and
+// "user_agent" fields, with quotes and backslashes in them escaped.
+// Format: host ident authuser [timestamp] "request-line" status bytes "referer" "user-agent"
+type CombinedLogFormatter struct {
+	common *CommonLogFormatter
+}
+
+// NewCombinedLogFormatter creates a new Combined Log Format formatter.
+func NewCombinedLogFormatter(config *FormatterConfig) *CombinedLogFormatter {
+	return &CombinedLogFormatter{common: NewCommonLogFormatter(config)}
+}
+
+// Format formats a log entry according to Combined Log Format.
+func (f *CombinedLogFormatter) Format(entry LogEntry) ([]byte, error) {
+	line, err := f.common.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	referer := combinedLogEscaper.Replace(f.common.getField(entry, "referer", "-"))
+	userAgent := combinedLogEscaper.Replace(f.common.getField(entry, "user_agent", "-"))
+	line = append(line[:len(line)-1], fmt.Sprintf(" \"%s\" \"%s\"\n", referer, userAgent)...)
+	return line, nil
+}
+
+// combinedLogEscaper escapes the quoted fields of a Combined Log Format
+// line, which clients control.
+var combinedLogEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
@@ -564,3 +564,39 @@ func TestCommonLogFormatter_Format_PartialFields(t *testing.T) {
 		t.Error("expected byte count in output")
 	}
 }
+
+func TestCombinedLogFormatter_Format(t *testing.T) {
+	formatter := NewCombinedLogFormatter(nil)
+
+	entry := LogEntry{
+		Level:     InfoLevel,
+		Message:   "GET /index.html HTTP/1.1",
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 0, time.UTC),
+		Fields: map[string]interface{}{
+			"host":       "10.0.0.1",
+			"authuser":   "frank",
+			"request":    "GET /index.html HTTP/1.1",
+			"status":     200,
+			"bytes":      2326,
+			"referer":    "https://example.com/start",
+			"user_agent": `Mozilla/5.0 "quoted" \ agent`,
+		},
+	}
+
+	data, err := formatter.Format(entry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `10.0.0.1 - frank [24/Nov/2025:10:30:00 +0000] "GET /index.html HTTP/1.1" 200 2326 "https://example.com/start" "Mozilla/5.0 \"quoted\" \\ agent"` + "\n"
+	if string(data) != want {
+		t.Errorf("unexpected line\n got: %q\nwant: %q", data, want)
+	}
+
+	data, err = formatter.Format(LogEntry{Message: "GET / HTTP/1.1", Timestamp: entry.Timestamp})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(string(data), ` - - "-" "-"`+"\n") {
+		t.Errorf("expected dashes for missing fields, got %q", data)
+	}
+}
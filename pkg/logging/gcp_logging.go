@@ -0,0 +1,310 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultGCPLoggingEndpoint is the Cloud Logging API method entries are
+// written to.
+const DefaultGCPLoggingEndpoint = "https://logging.googleapis.com/v2/entries:write"
+
+// GCPResource is the monitored resource entries are attributed to, e.g.
+// {Type: "k8s_container", Labels: {"cluster_name": ...}}.
+type GCPResource struct {
+	Type   string            `json:"type"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// GCPLoggingConfig configures a GCPLoggingOutput.
+type GCPLoggingConfig struct {
+	// ProjectID is the Google Cloud project that owns the log and traces.
+	ProjectID string
+	// LogID names the log, e.g. "api". Defaults to "app".
+	LogID string
+	// Resource defaults to {Type: "global"}.
+	Resource *GCPResource
+	// Labels are added to every entry.
+	Labels map[string]string
+	// TokenSource returns an OAuth2 access token for each request. Defaults
+	// to the service account token from the GCE metadata server, available
+	// on Compute Engine, GKE, Cloud Run and Cloud Functions.
+	TokenSource func(ctx context.Context) (string, error)
+	// Endpoint defaults to DefaultGCPLoggingEndpoint.
+	Endpoint string
+	// BatchSize is the number of entries per write. Defaults to 500.
+	BatchSize int
+	// Linger is how long a partial batch waits for more entries. Defaults
+	// to 1s.
+	Linger time.Duration
+	// QueueSize bounds the entries waiting to be written. Defaults to 10000.
+	QueueSize int
+	// Retry controls retries of writes that fail with network errors, 429
+	// or 5xx responses.
+	Retry RetryConfig
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the number of entries that could not be
+	// written.
+	OnError func(entries int, err error)
+}
+
+// GCPLoggingOutput writes entries through the Cloud Logging API. Level
+// becomes severity, the message and fields form jsonPayload, and the trace
+// ID from the context (or a trace_id field) becomes the entry's trace — the
+// API counterpart of logging.googleapis.com/trace — so logs appear next to
+// their Cloud Trace spans.
+//
+// Example:
+//
+//	output, err := logging.NewGCPLoggingOutput(logging.GCPLoggingConfig{
+//		ProjectID: "my-project",
+//		LogID:     "api",
+//	})
+type GCPLoggingOutput struct {
+	config  GCPLoggingConfig
+	logName string
+	batcher *batcher[json.RawMessage]
+}
+
+// NewGCPLoggingOutput creates a GCPLoggingOutput.
+func NewGCPLoggingOutput(config GCPLoggingConfig) (*GCPLoggingOutput, error) {
+	if config.ProjectID == "" {
+		return nil, fmt.Errorf("cloud logging output requires a project ID")
+	}
+	if config.LogID == "" {
+		config.LogID = "app"
+	}
+	if config.Resource == nil {
+		config.Resource = &GCPResource{Type: "global"}
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = DefaultGCPLoggingEndpoint
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 500
+	}
+	if config.Linger == 0 {
+		config.Linger = time.Second
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 10000
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if config.TokenSource == nil {
+		config.TokenSource = (&metadataTokenSource{client: config.Client}).Token
+	}
+
+	gco := &GCPLoggingOutput{
+		config:  config,
+		logName: "projects/" + config.ProjectID + "/logs/" + config.LogID,
+	}
+	gco.batcher = newBatcher(batcherConfig[json.RawMessage]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     gco.write,
+		OnError: func(batch []json.RawMessage, err error) {
+			gco.reportError(len(batch), err)
+		},
+	})
+	return gco, nil
+}
+
+// gcpSeverity maps a level to a Cloud Logging severity.
+func gcpSeverity(level Level) string {
+	switch level {
+	case CriticalLevel:
+		return "CRITICAL"
+	case ErrorLevel:
+		return "ERROR"
+	case WarnLevel:
+		return "WARNING"
+	case InfoLevel:
+		return "INFO"
+	case DebugLevel, TraceLevel:
+		return "DEBUG"
+	default:
+		return "DEFAULT"
+	}
+}
+
+// gcpEntry is a LogEntry in the Cloud Logging API's JSON form.
+type gcpEntry struct {
+	Timestamp      string                 `json:"timestamp"`
+	Severity       string                 `json:"severity"`
+	JSONPayload    map[string]interface{} `json:"jsonPayload"`
+	Trace          string                 `json:"trace,omitempty"`
+	SourceLocation *gcpSourceLocation     `json:"sourceLocation,omitempty"`
+}
+
+type gcpSourceLocation struct {
+	File string `json:"file"`
+	Line string `json:"line"`
+}
+
+// Write queues data as the message of an INFO entry.
+func (gco *GCPLoggingOutput) Write(data []byte) error {
+	return gco.WriteEntry(LogEntry{
+		Timestamp: time.Now(),
+		Level:     InfoLevel,
+		Message:   string(bytes.TrimRight(data, "\r\n")),
+	}, data)
+}
+
+// WriteEntry queues entry with its severity, trace and source location.
+func (gco *GCPLoggingOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	payload := make(map[string]interface{}, len(entry.Fields)+4)
+	for k, v := range entry.Fields {
+		payload[k] = v
+	}
+	for _, name := range []string{"request_id", "correlation_id"} {
+		if _, ok := payload[name]; ok {
+			continue
+		}
+		if value, ok := entryFieldString(entry, name); ok {
+			payload[name] = value
+		}
+	}
+	payload["message"] = entry.Message
+
+	e := gcpEntry{
+		Timestamp:   entry.Timestamp.UTC().Format(time.RFC3339Nano),
+		Severity:    gcpSeverity(entry.Level),
+		JSONPayload: payload,
+	}
+	if traceID, ok := entryFieldString(entry, "trace_id"); ok && traceID != "" {
+		e.Trace = "projects/" + gco.config.ProjectID + "/traces/" + traceID
+		delete(payload, "trace_id")
+	}
+	if entry.File != "" {
+		e.SourceLocation = &gcpSourceLocation{File: entry.File, Line: strconv.Itoa(entry.Line)}
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode cloud logging entry: %w", err)
+	}
+	return gco.batcher.Submit(data)
+}
+
+// write sends entries in one entries.write call. partialSuccess lets valid
+// entries through when others are rejected.
+func (gco *GCPLoggingOutput) write(entries []json.RawMessage) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"logName":        gco.logName,
+		"resource":       gco.config.Resource,
+		"labels":         gco.config.Labels,
+		"entries":        entries,
+		"partialSuccess": true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode cloud logging request: %w", err)
+	}
+
+	err = retry(context.Background(), gco.config.Retry, func() error {
+		token, err := gco.config.TokenSource(context.Background())
+		if err != nil {
+			return fmt.Errorf("failed to get access token: %w", err)
+		}
+		req, err := http.NewRequest(http.MethodPost, gco.config.Endpoint, bytes.NewReader(body))
+		if err != nil {
+			return permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := gco.config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		return checkHTTPResponse(resp)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write to cloud logging: %w", err)
+	}
+	return nil
+}
+
+func (gco *GCPLoggingOutput) reportError(entries int, err error) {
+	if gco.config.OnError != nil {
+		gco.config.OnError(entries, err)
+	}
+}
+
+// Flush writes the pending partial batch.
+func (gco *GCPLoggingOutput) Flush() error {
+	return gco.batcher.Flush()
+}
+
+// Close writes queued entries.
+func (gco *GCPLoggingOutput) Close() error {
+	return gco.batcher.Stop()
+}
+
+// metadataTokenSource fetches and caches the default service account's
+// access token from the GCE metadata server.
+type metadataTokenSource struct {
+	client  *http.Client
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token returns a cached token, refreshing it a minute before it expires.
+func (ts *metadataTokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token != "" && time.Now().Before(ts.expires) {
+		return ts.token, nil
+	}
+
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = "metadata.google.internal"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		"http://"+host+"/computeMetadata/v1/instance/service-accounts/default/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := ts.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned %s", resp.Status)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode metadata token: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", errors.New("metadata server returned an empty token")
+	}
+	ts.token = result.AccessToken
+	ts.expires = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return ts.token, nil
+}
@@ -0,0 +1,156 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type gcpWriteRequest struct {
+	LogName        string            `json:"logName"`
+	Resource       GCPResource       `json:"resource"`
+	Labels         map[string]string `json:"labels"`
+	PartialSuccess bool              `json:"partialSuccess"`
+	Entries        []struct {
+		Timestamp      string                 `json:"timestamp"`
+		Severity       string                 `json:"severity"`
+		JSONPayload    map[string]interface{} `json:"jsonPayload"`
+		Trace          string                 `json:"trace"`
+		SourceLocation *gcpSourceLocation     `json:"sourceLocation"`
+	} `json:"entries"`
+}
+
+func TestNewGCPLoggingOutput_Validation(t *testing.T) {
+	if _, err := NewGCPLoggingOutput(GCPLoggingConfig{}); err == nil {
+		t.Error("expected error without project ID")
+	}
+}
+
+func TestGCPLoggingOutput_Write(t *testing.T) {
+	var mu sync.Mutex
+	var requests []gcpWriteRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req gcpWriteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, req)
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	output, err := NewGCPLoggingOutput(GCPLoggingConfig{
+		ProjectID:   "proj",
+		LogID:       "api",
+		Labels:      map[string]string{"env": "prod"},
+		Endpoint:    server.URL,
+		TokenSource: func(context.Context) (string, error) { return "tok", nil },
+	})
+	if err != nil {
+		t.Fatalf("failed to create cloud logging output: %v", err)
+	}
+
+	ctx := WithTraceID(context.Background(), "abc123")
+	ctx = WithRequestID(ctx, "req-1")
+	if err := output.WriteEntry(LogEntry{
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:     WarnLevel,
+		Message:   "slow query",
+		Fields:    map[string]interface{}{"table": "orders"},
+		Context:   ctx,
+		File:      "db.go",
+		Line:      42,
+	}, nil); err != nil {
+		t.Fatalf("WriteEntry returned error: %v", err)
+	}
+	_ = output.Write([]byte("plain\n"))
+	if err := output.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 || auth != "Bearer tok" {
+		t.Fatalf("expected 1 authorized request, got %d with %q", len(requests), auth)
+	}
+	req := requests[0]
+	if req.LogName != "projects/proj/logs/api" || req.Resource.Type != "global" || req.Labels["env"] != "prod" || !req.PartialSuccess {
+		t.Errorf("unexpected request: %+v", req)
+	}
+	if len(req.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(req.Entries))
+	}
+	e := req.Entries[0]
+	if e.Severity != "WARNING" || e.Timestamp != "2024-01-02T03:04:05Z" {
+		t.Errorf("unexpected severity or timestamp: %s %s", e.Severity, e.Timestamp)
+	}
+	if e.Trace != "projects/proj/traces/abc123" {
+		t.Errorf("unexpected trace %q", e.Trace)
+	}
+	if e.JSONPayload["message"] != "slow query" || e.JSONPayload["table"] != "orders" || e.JSONPayload["request_id"] != "req-1" {
+		t.Errorf("unexpected payload: %v", e.JSONPayload)
+	}
+	if _, ok := e.JSONPayload["trace_id"]; ok {
+		t.Error("expected trace_id to move to the trace field")
+	}
+	if e.SourceLocation == nil || e.SourceLocation.File != "db.go" || e.SourceLocation.Line != "42" {
+		t.Errorf("unexpected source location: %+v", e.SourceLocation)
+	}
+	if second := req.Entries[1]; second.Severity != "INFO" || second.JSONPayload["message"] != "plain" || second.Trace != "" {
+		t.Errorf("unexpected second entry: %+v", second)
+	}
+}
+
+func TestGCPLoggingOutput_TokenError(t *testing.T) {
+	var failed atomic.Int32
+	output, err := NewGCPLoggingOutput(GCPLoggingConfig{
+		ProjectID:   "proj",
+		Endpoint:    "http://127.0.0.1:1",
+		Retry:       RetryConfig{MaxRetries: -1},
+		TokenSource: func(context.Context) (string, error) { return "", errors.New("no credentials") },
+		OnError:     func(entries int, err error) { failed.Add(int32(entries)) },
+	})
+	if err != nil {
+		t.Fatalf("failed to create cloud logging output: %v", err)
+	}
+	_ = output.Write([]byte("lost"))
+	_ = output.Close()
+
+	if failed.Load() != 1 {
+		t.Errorf("expected 1 failed entry, got %d", failed.Load())
+	}
+}
+
+func TestMetadataTokenSource(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			t.Error("expected Metadata-Flavor header")
+		}
+		w.Write([]byte(`{"access_token":"ya29.x","expires_in":3600}`))
+	}))
+	defer server.Close()
+	t.Setenv("GCE_METADATA_HOST", server.Listener.Addr().String())
+
+	ts := &metadataTokenSource{client: server.Client()}
+	for i := 0; i < 2; i++ {
+		token, err := ts.Token(context.Background())
+		if err != nil || token != "ya29.x" {
+			t.Fatalf("Token() = %q, %v", token, err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected the token to be cached, got %d requests", calls.Load())
+	}
+}
@@ -0,0 +1,345 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ocrosby/go-logging/pkg/logging/internal"
+)
+
+const (
+	// gelfChunkHeaderSize is the size of the magic bytes, message ID,
+	// sequence number and sequence count that prefix each UDP chunk.
+	gelfChunkHeaderSize = 12
+	// gelfMaxChunks is the most chunks a GELF message may be split into.
+	gelfMaxChunks = 128
+	// DefaultGELFChunkSize is the UDP datagram size used when a payload must
+	// be chunked. It fits a typical WAN MTU.
+	DefaultGELFChunkSize = 1420
+)
+
+// gelfChunkMagic identifies a chunked GELF datagram.
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+// GELFFormatter formats log entries as GELF 1.1 JSON payloads for Graylog.
+// The first line of the message becomes short_message and a multi-line
+// message is also sent whole as full_message. Fields, context IDs and the
+// caller's file and line are sent as additional fields with a leading
+// underscore; names outside [A-Za-z0-9_.-] have those characters replaced
+// and a field named "id", which GELF reserves, is sent as "__id".
+type GELFFormatter struct {
+	config *FormatterConfig
+	host   string
+}
+
+// NewGELFFormatter creates a GELF formatter. An empty host defaults to the
+// machine's hostname.
+func NewGELFFormatter(config *FormatterConfig, host string) *GELFFormatter {
+	if config == nil {
+		config = NewFormatterConfig().Build()
+	}
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	return &GELFFormatter{config: config, host: host}
+}
+
+// Format formats a log entry as a newline-terminated GELF payload.
+func (f *GELFFormatter) Format(entry LogEntry) ([]byte, error) {
+	payload, err := f.payload(entry)
+	if err != nil {
+		return nil, err
+	}
+	return append(payload, '\n'), nil
+}
+
+// payload encodes entry as GELF JSON without a terminator.
+func (f *GELFFormatter) payload(entry LogEntry) ([]byte, error) {
+	data := make(map[string]interface{}, len(entry.Fields)+8)
+
+	message := internal.ApplyRedactionPatterns(entry.Message, f.config.RedactPatterns)
+	short, _, multiline := strings.Cut(strings.TrimRight(message, "\r\n"), "\n")
+	short = strings.TrimRight(short, "\r")
+	if short == "" {
+		short = "-"
+	}
+
+	data["version"] = "1.1"
+	data["host"] = f.host
+	data["short_message"] = short
+	if multiline {
+		data["full_message"] = message
+	}
+	data["level"] = syslogSeverity(entry.Level)
+	if !entry.Timestamp.IsZero() {
+		ms := entry.Timestamp.UnixMilli()
+		data["timestamp"] = json.Number(fmt.Sprintf("%d.%03d", ms/1000, ms%1000))
+	}
+
+	for k, v := range entry.Fields {
+		data[gelfFieldName(k)] = gelfValue(v)
+	}
+	if f.config.IncludeFile && entry.File != "" {
+		file := entry.File
+		if f.config.UseShortFile {
+			file = filepath.Base(file)
+		}
+		data["_file"] = file
+		data["_line"] = entry.Line
+	}
+	contextFields := make(map[string]interface{})
+	contextFieldsFrom(entry.Context).AddToMap(contextFields)
+	for k, v := range contextFields {
+		data[gelfFieldName(k)] = v
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GELF payload: %w", err)
+	}
+	return payload, nil
+}
+
+// gelfFieldName returns the additional field name for a log field.
+func gelfFieldName(name string) string {
+	b := []byte("_" + name)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-') {
+			b[i] = '_'
+		}
+	}
+	if string(b) == "_id" {
+		return "__id"
+	}
+	return string(b)
+}
+
+// gelfValue converts v to a string or number, the only value types GELF
+// accepts for additional fields.
+func gelfValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case error:
+		return v.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// GELFConfig configures a GELFOutput.
+type GELFConfig struct {
+	// Network is "udp" (the default) or "tcp".
+	Network string
+	// Address is the Graylog input, e.g. "graylog:12201".
+	Address string
+	// TLSConfig enables TLS on TCP connections.
+	TLSConfig *tls.Config
+	// Host is the GELF host field. Defaults to the machine's hostname.
+	Host string
+	// Compress gzips UDP payloads. TCP payloads are never compressed, as
+	// GELF TCP inputs do not support it.
+	Compress bool
+	// ChunkSize is the largest UDP datagram sent; bigger payloads are split
+	// into chunks. Defaults to DefaultGELFChunkSize.
+	ChunkSize int
+	// Formatter controls redaction and file info. Defaults to
+	// NewFormatterConfig().Build().
+	Formatter *FormatterConfig
+}
+
+// GELFOutput sends entries to Graylog as GELF 1.1 messages, chunking large
+// UDP payloads and null-terminating messages on TCP. It encodes entries
+// itself, so the logger's formatter only affects plain Write calls, which
+// are sent as an INFO message with the data as short_message.
+//
+// Example:
+//
+//	gelf, err := logging.NewGELFOutput(logging.GELFConfig{
+//		Network:  "udp",
+//		Address:  "graylog:12201",
+//		Compress: true,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer gelf.Close()
+//	logger := logging.NewWithLoggerConfig(
+//		logging.NewLoggerConfig().WithCustomOutput(gelf).Build(),
+//	)
+type GELFOutput struct {
+	config    GELFConfig
+	formatter *GELFFormatter
+	conn      net.Conn
+	mu        sync.Mutex
+}
+
+// NewGELFOutput connects to the configured Graylog input.
+func NewGELFOutput(config GELFConfig) (*GELFOutput, error) {
+	if config.Network == "" {
+		config.Network = "udp"
+	}
+	if config.Network != "udp" && config.Network != "tcp" {
+		return nil, fmt.Errorf("unsupported GELF network: %q", config.Network)
+	}
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = DefaultGELFChunkSize
+	}
+	if config.ChunkSize <= gelfChunkHeaderSize {
+		return nil, fmt.Errorf("GELF chunk size too small: %d", config.ChunkSize)
+	}
+
+	gl := &GELFOutput{
+		config:    config,
+		formatter: NewGELFFormatter(config.Formatter, config.Host),
+	}
+	if err := gl.connect(); err != nil {
+		return nil, err
+	}
+	return gl, nil
+}
+
+// connect dials the endpoint. It must be called with mu held or before the
+// output is shared.
+func (gl *GELFOutput) connect() error {
+	var conn net.Conn
+	var err error
+	if gl.config.TLSConfig != nil && gl.config.Network == "tcp" {
+		conn, err = tls.Dial("tcp", gl.config.Address, gl.config.TLSConfig)
+	} else {
+		conn, err = net.Dial(gl.config.Network, gl.config.Address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to GELF input: %w", err)
+	}
+	gl.conn = conn
+	return nil
+}
+
+// Write sends data as an INFO message.
+func (gl *GELFOutput) Write(data []byte) error {
+	return gl.WriteEntry(LogEntry{
+		Timestamp: time.Now(),
+		Level:     InfoLevel,
+		Message:   string(bytes.TrimRight(data, "\r\n")),
+	}, data)
+}
+
+// WriteEntry encodes entry as GELF and sends it; data is ignored.
+func (gl *GELFOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	payload, err := gl.formatter.payload(entry)
+	if err != nil {
+		return err
+	}
+
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
+
+	if gl.conn == nil {
+		return fmt.Errorf("GELF output is closed")
+	}
+
+	if gl.config.Network == "udp" {
+		err = gl.writeUDP(payload)
+	} else {
+		err = gl.writeTCP(payload)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write to GELF input: %w", err)
+	}
+	return nil
+}
+
+// writeTCP writes a null-terminated payload, reconnecting once on failure.
+func (gl *GELFOutput) writeTCP(payload []byte) error {
+	msg := append(payload, 0)
+	_, err := gl.conn.Write(msg)
+	if err != nil {
+		_ = gl.conn.Close()
+		if err = gl.connect(); err == nil {
+			_, err = gl.conn.Write(msg)
+		}
+	}
+	return err
+}
+
+// writeUDP sends payload in one datagram, or in chunks when it exceeds
+// ChunkSize.
+func (gl *GELFOutput) writeUDP(payload []byte) error {
+	if gl.config.Compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(payload); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		payload = buf.Bytes()
+	}
+
+	if len(payload) <= gl.config.ChunkSize {
+		_, err := gl.conn.Write(payload)
+		return err
+	}
+
+	chunks, err := gelfChunks(payload, gl.config.ChunkSize)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if _, err := gl.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gelfChunks splits payload into chunked GELF datagrams of at most size
+// bytes each.
+func gelfChunks(payload []byte, size int) ([][]byte, error) {
+	dataSize := size - gelfChunkHeaderSize
+	count := (len(payload) + dataSize - 1) / dataSize
+	if count > gelfMaxChunks {
+		return nil, fmt.Errorf("GELF payload of %d bytes needs %d chunks, more than %d", len(payload), count, gelfMaxChunks)
+	}
+
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], rand.Uint64())
+
+	chunks := make([][]byte, 0, count)
+	for seq := 0; seq < count; seq++ {
+		end := min((seq+1)*dataSize, len(payload))
+		chunk := make([]byte, 0, gelfChunkHeaderSize+end-seq*dataSize)
+		chunk = append(chunk, gelfChunkMagic...)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(seq), byte(count))
+		chunk = append(chunk, payload[seq*dataSize:end]...)
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// Close closes the connection.
+func (gl *GELFOutput) Close() error {
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
+
+	if gl.conn != nil {
+		err := gl.conn.Close()
+		gl.conn = nil
+		return err
+	}
+	return nil
+}
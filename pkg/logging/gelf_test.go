@@ -0,0 +1,287 @@
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGELFFormatter_Format(t *testing.T) {
+	formatter := NewGELFFormatter(NewFormatterConfig().Build(), "web-1")
+	ctx := WithTraceID(context.Background(), "trace-1")
+
+	data, err := formatter.Format(LogEntry{
+		Timestamp: time.Unix(1700000000, 250*int64(time.Millisecond)),
+		Level:     ErrorLevel,
+		Message:   "query failed\nstack line 1\nstack line 2",
+		Fields: map[string]interface{}{
+			"user id": 42,
+			"id":      "abc",
+			"err":     errors.New("timeout"),
+			"ok":      false,
+		},
+		Context: ctx,
+		File:    "/src/app/db.go",
+		Line:    17,
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		t.Error("expected newline-terminated payload")
+	}
+	if !bytes.Contains(data, []byte(`"timestamp":1700000000.250`)) {
+		t.Errorf("expected timestamp in seconds with milliseconds, got %s", data)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	want := map[string]interface{}{
+		"version":       "1.1",
+		"host":          "web-1",
+		"short_message": "query failed",
+		"full_message":  "query failed\nstack line 1\nstack line 2",
+		"level":         float64(3),
+		"_user_id":      float64(42),
+		"__id":          "abc",
+		"_err":          "timeout",
+		"_ok":           "false",
+		"_file":         "db.go",
+		"_line":         float64(17),
+		"_trace_id":     "trace-1",
+	}
+	for k, v := range want {
+		if payload[k] != v {
+			t.Errorf("%s = %v, want %v", k, payload[k], v)
+		}
+	}
+	if _, ok := payload["_id"]; ok {
+		t.Error("expected reserved _id field to be renamed")
+	}
+}
+
+func TestGELFFormatter_SingleLine(t *testing.T) {
+	data, err := NewGELFFormatter(nil, "h").Format(LogEntry{Level: InfoLevel, Message: "hello"})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if payload["short_message"] != "hello" || payload["level"] != float64(6) {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+	if _, ok := payload["full_message"]; ok {
+		t.Error("expected no full_message for a single-line message")
+	}
+}
+
+func TestNewGELFOutput_Validation(t *testing.T) {
+	if _, err := NewGELFOutput(GELFConfig{Network: "unix", Address: "x"}); err == nil {
+		t.Error("expected error for unsupported network")
+	}
+	if _, err := NewGELFOutput(GELFConfig{Address: "127.0.0.1:1", ChunkSize: 12}); err == nil {
+		t.Error("expected error for chunk size without room for data")
+	}
+}
+
+func TestGELFOutput_UDPChunking(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	output, err := NewGELFOutput(GELFConfig{Address: conn.LocalAddr().String(), Host: "h", ChunkSize: 100, Compress: true})
+	if err != nil {
+		t.Fatalf("NewGELFOutput returned error: %v", err)
+	}
+	defer output.Close()
+
+	// Random-looking text so the gzipped payload still needs several chunks.
+	var message strings.Builder
+	for i := 0; i < 200; i++ {
+		message.WriteString(time.Duration(i * 7919).String())
+	}
+	if err := output.WriteEntry(LogEntry{Level: WarnLevel, Message: message.String()}, nil); err != nil {
+		t.Fatalf("WriteEntry returned error: %v", err)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var chunks [][]byte
+	var id []byte
+	count := -1
+	for count < 0 || len(chunks) < count {
+		buf := make([]byte, 200)
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("failed to read chunk: %v", err)
+		}
+		chunk := buf[:n]
+		if n > 100 || !bytes.Equal(chunk[:2], gelfChunkMagic) {
+			t.Fatalf("invalid chunk of %d bytes", n)
+		}
+		if id == nil {
+			id, count = chunk[2:10], int(chunk[11])
+			chunks = make([][]byte, 0, count)
+		}
+		if !bytes.Equal(chunk[2:10], id) || int(chunk[10]) != len(chunks) {
+			t.Fatalf("unexpected chunk header %v", chunk[:12])
+		}
+		chunks = append(chunks, chunk[12:])
+	}
+	if count < 2 {
+		t.Fatalf("expected several chunks, got %d", count)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(bytes.Join(chunks, nil)))
+	if err != nil {
+		t.Fatalf("reassembled payload is not gzip: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("failed to decompress: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if payload["short_message"] != message.String() || payload["level"] != float64(4) {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+}
+
+func TestGELFChunks_TooLarge(t *testing.T) {
+	if _, err := gelfChunks(make([]byte, 129*10), 22); err == nil {
+		t.Error("expected error for more than 128 chunks")
+	}
+}
+
+func TestGELFOutput_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	output, err := NewGELFOutput(GELFConfig{Network: "tcp", Address: listener.Addr().String(), Host: "h"})
+	if err != nil {
+		t.Fatalf("NewGELFOutput returned error: %v", err)
+	}
+	if err := output.Write([]byte("plain line\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if err := output.WriteEntry(LogEntry{Level: DebugLevel, Message: "second"}, nil); err != nil {
+		t.Fatalf("WriteEntry returned error: %v", err)
+	}
+	_ = output.Close()
+
+	data := <-received
+	messages := bytes.Split(bytes.TrimSuffix(data, []byte{0}), []byte{0})
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 null-terminated messages, got %q", data)
+	}
+	var first, second map[string]interface{}
+	if err := json.Unmarshal(messages[0], &first); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal(messages[1], &second); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if first["short_message"] != "plain line" || first["level"] != float64(6) {
+		t.Errorf("unexpected first message: %v", first)
+	}
+	if second["short_message"] != "second" || second["level"] != float64(7) {
+		t.Errorf("unexpected second message: %v", second)
+	}
+
+	if err := output.Write([]byte("x")); err == nil {
+		t.Error("expected error writing after Close")
+	}
+}
+
+func TestGELFFormat_NetOutput(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	output, err := NewNetOutput(NetConfig{Network: "udp", Address: conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("failed to create net output: %v", err)
+	}
+	defer output.Close()
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithGELFFormat().
+		WithCustomOutput(output).
+		Build())
+	logger.WithField("user", "alice").Warn("disk almost full")
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 65535)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("failed to read datagram: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf[:n], &got); err != nil {
+		t.Fatalf("datagram is not JSON: %v\n%s", err, buf[:n])
+	}
+	if got["version"] != "1.1" || got["short_message"] != "disk almost full" || got["level"] != float64(4) || got["_user"] != "alice" {
+		t.Errorf("unexpected payload %v", got)
+	}
+	if host, _ := got["host"].(string); host == "" {
+		t.Error("expected the hostname as host")
+	}
+}
+
+func TestGELFFormat_YAMLAndSchema(t *testing.T) {
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "gelf", IncludeFile: true}); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	config := builder.Build()
+	if config.Formatter.Format != GELFFormat {
+		t.Fatalf("expected GELFFormat, got %v", config.Formatter.Format)
+	}
+
+	buf := &bytes.Buffer{}
+	config.Output.Writer = buf
+	logger := NewWithLoggerConfig(config).WithField("service", "api")
+	logger.InfoContext(WithTraceID(context.Background(), "trace-1"), "first line\nsecond line")
+
+	schema := SchemaDescriptorLogger(logger)
+	if schema.Format != "gelf" {
+		t.Errorf("expected gelf schema, got %q", schema.Format)
+	}
+	for _, name := range []string{"short_message", "_service", "_trace_id", "_file"} {
+		if _, ok := schema.Field(name); !ok {
+			t.Errorf("expected schema field %q", name)
+		}
+	}
+	if err := schema.Validate(buf.Bytes()); err != nil {
+		t.Errorf("expected GELF output to validate: %v\n%s", err, buf.Bytes())
+	}
+}
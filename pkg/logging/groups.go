@@ -0,0 +1,213 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// GroupLogger is implemented by loggers that can qualify fields with a
+// group, like slog.Logger.WithGroup. Loggers built with NewWithLoggerConfig
+// implement it.
+type GroupLogger interface {
+	Logger
+
+	// WithGroup returns a child whose fields added by WithField and
+	// WithFields are placed in the group name, nested within the groups of
+	// the logger. An empty name returns the logger itself.
+	WithGroup(name string) Logger
+}
+
+// GroupStyle is how JSON output writes the fields of a group.
+type GroupStyle int
+
+const (
+	// NestedGroups writes a group as a JSON object, the default:
+	// "http":{"method":"GET","status":200}.
+	NestedGroups GroupStyle = iota
+	// DottedGroups writes each field of a group as a top-level key
+	// qualified by the group: "http.method":"GET","http.status":200.
+	DottedGroups
+)
+
+// groupStyleNames are the names of each GroupStyle in configuration.
+var groupStyleNames = map[GroupStyle]string{
+	NestedGroups: "nested",
+	DottedGroups: "dotted",
+}
+
+// String returns the name of s, such as "dotted".
+func (s GroupStyle) String() string {
+	if name, ok := groupStyleNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseGroupStyle returns the style named by name: "nested" or "dotted".
+func ParseGroupStyle(name string) (GroupStyle, bool) {
+	for style, styleName := range groupStyleNames {
+		if strings.EqualFold(name, styleName) {
+			return style, true
+		}
+	}
+	return NestedGroups, false
+}
+
+// fieldGroup holds the fields of a group. It is stored as the value of the
+// group's key, so formatters that know nothing of groups write it as a map.
+type fieldGroup map[string]interface{}
+
+// nestFields returns fields placed in the groups of path, outermost first.
+func nestFields(path []string, fields map[string]interface{}) map[string]interface{} {
+	nested := fields
+	for i := len(path) - 1; i >= 0; i-- {
+		group := make(fieldGroup, len(nested))
+		for k, v := range nested {
+			group[k] = v
+		}
+		nested = map[string]interface{}{path[i]: group}
+	}
+	return nested
+}
+
+// mergeGroupField sets key to value in fields, merging value into the
+// existing group when both are groups. Existing groups are copied rather
+// than modified, since loggers share them with their parents.
+func mergeGroupField(fields map[string]interface{}, key string, value interface{}) {
+	group, isGroup := value.(fieldGroup)
+	existing, hasGroup := fields[key].(fieldGroup)
+	if !isGroup || !hasGroup {
+		fields[key] = value
+		return
+	}
+	merged := make(fieldGroup, len(existing)+len(group))
+	for k, v := range existing {
+		merged[k] = v
+	}
+	for k, v := range group {
+		mergeGroupField(merged, k, v)
+	}
+	fields[key] = merged
+}
+
+// hasGroups reports whether any field holds a group.
+func hasGroups(fields map[string]interface{}) bool {
+	for _, v := range fields {
+		if _, ok := v.(fieldGroup); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenGroups returns fields with the fields of each group moved to
+// top-level keys qualified by the group, such as "http.method", and order
+// with each group's key replaced by its qualified keys, sorted. Fields
+// without groups are returned as they are.
+func flattenGroups(fields map[string]interface{}, order []string) (map[string]interface{}, []string) {
+	if !hasGroups(fields) {
+		return fields, order
+	}
+	flat := make(map[string]interface{}, len(fields))
+	expanded := make(map[string][]string)
+	for k, v := range fields {
+		group, ok := v.(fieldGroup)
+		if !ok {
+			flat[k] = v
+			continue
+		}
+		expanded[k] = flattenGroup(flat, k+".", group)
+	}
+	if len(order) == 0 {
+		return flat, order
+	}
+	flatOrder := make([]string, 0, len(order))
+	for _, k := range order {
+		if keys, ok := expanded[k]; ok {
+			flatOrder = append(flatOrder, keys...)
+		} else {
+			flatOrder = append(flatOrder, k)
+		}
+	}
+	return flat, flatOrder
+}
+
+// flattenGroup adds the fields of group to flat under prefix and returns
+// their keys, sorted.
+func flattenGroup(flat map[string]interface{}, prefix string, group fieldGroup) []string {
+	var keys []string
+	for _, k := range sortedKeys(group) {
+		if inner, ok := group[k].(fieldGroup); ok {
+			keys = append(keys, flattenGroup(flat, prefix+k+".", inner)...)
+			continue
+		}
+		flat[prefix+k] = group[k]
+		keys = append(keys, prefix+k)
+	}
+	return keys
+}
+
+// slogAttr returns the slog attribute of a field, with groups as
+// slog.Group values.
+func slogAttr(key string, value interface{}) slog.Attr {
+	group, ok := value.(fieldGroup)
+	if !ok {
+		return slog.Any(key, value)
+	}
+	attrs := make([]slog.Attr, 0, len(group))
+	for _, k := range sortedKeys(group) {
+		attrs = append(attrs, slogAttr(k, group[k]))
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+// WithGroup returns a child logger whose fields added by WithField and
+// WithFields are placed in the group name. JSON output writes a group as a
+// nested object or as dotted keys, depending on the GroupStyle; text and
+// console output use dotted keys.
+//
+// Example:
+//
+//	httpLogger := logger.(logging.GroupLogger).WithGroup("http")
+//	httpLogger.WithFields(map[string]interface{}{"method": "GET", "status": 200}).Info("request")
+//	// {"http":{"method":"GET","status":200},"level":"INFO","message":"request",...}
+func (ul *unifiedLogger) WithGroup(name string) Logger {
+	if name == "" {
+		return ul
+	}
+	group := make([]string, 0, len(ul.group)+1)
+	group = append(append(group, ul.group...), name)
+	return &unifiedLogger{
+		config:        ul.config,
+		fields:        ul.fields,
+		fieldOrder:    ul.fieldOrder,
+		group:         group,
+		level:         ul.level,
+		formatter:     ul.formatter,
+		output:        ul.output,
+		slogLogger:    ul.slogLogger,
+		redactorChain: ul.redactorChain,
+		recentErrors:  ul.recentErrors,
+	}
+}
+
+// WithGroupStyle sets how JSON output writes the fields of groups.
+func (b *FormatterConfigBuilder) WithGroupStyle(style GroupStyle) *FormatterConfigBuilder {
+	b.config.GroupStyle = style
+	return b
+}
+
+// WithGroupStyle sets how JSON output writes the fields of groups added
+// with GroupLogger.WithGroup: as nested objects, the default, or as dotted
+// keys for backends that index flat fields.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithGroupStyle(logging.DottedGroups).
+//		Build()
+func (b *LoggerConfigBuilder) WithGroupStyle(style GroupStyle) *LoggerConfigBuilder {
+	b.config.Formatter.GroupStyle = style
+	return b
+}
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func groupTestLogger(buf *bytes.Buffer, configure func(*LoggerConfigBuilder) *LoggerConfigBuilder) Logger {
+	builder := NewLoggerConfig().
+		WithWriter(buf).
+		WithFormatter(NewFormatterConfig().IncludeTime(false).IncludeFile(false).Build())
+	logger := NewWithLoggerConfig(configure(builder).Build()).WithField("service", "api")
+	http := logger.(GroupLogger).WithGroup("http").WithField("method", "GET")
+	return http.(GroupLogger).WithGroup("response").WithField("status", 200)
+}
+
+func TestWithGroup_JSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(*LoggerConfigBuilder) *LoggerConfigBuilder
+		want      string
+	}{
+		{
+			name:      "nested",
+			configure: func(b *LoggerConfigBuilder) *LoggerConfigBuilder { return b.WithJSONFormat() },
+			want:      `{"http":{"method":"GET","response":{"status":200}},"level":"INFO","message":"done","service":"api"}`,
+		},
+		{
+			name: "dotted",
+			configure: func(b *LoggerConfigBuilder) *LoggerConfigBuilder {
+				return b.WithJSONFormat().WithGroupStyle(DottedGroups)
+			},
+			want: `{"http.method":"GET","http.response.status":200,"level":"INFO","message":"done","service":"api"}`,
+		},
+		{
+			name: "dotted in insertion order",
+			configure: func(b *LoggerConfigBuilder) *LoggerConfigBuilder {
+				return b.WithJSONFormat().WithGroupStyle(DottedGroups).WithFieldOrder(InsertionFieldOrder)
+			},
+			want: `{"level":"INFO","message":"done","service":"api","http.method":"GET","http.response.status":200}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			groupTestLogger(buf, tt.configure).Info("done")
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("unexpected output\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithGroup_Text(t *testing.T) {
+	buf := &bytes.Buffer{}
+	groupTestLogger(buf, func(b *LoggerConfigBuilder) *LoggerConfigBuilder { return b.WithTextFormat() }).Info("done")
+	if want := "[INFO] done {http.method=GET http.response.status=200 service=api}\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWithGroup_Slog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	groupTestLogger(buf, func(b *LoggerConfigBuilder) *LoggerConfigBuilder { return b.WithJSONFormat().UseSlog(true) }).Info("done")
+	if want := `"http":{"method":"GET","response":{"status":200}}`; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected nested group %s, got %s", want, buf.String())
+	}
+}
+
+func TestWithGroup_DoesNotModifyParent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	parent := NewWithLoggerConfig(NewLoggerConfig().WithWriter(buf).
+		WithFormatter(NewFormatterConfig().IncludeTime(false).IncludeFile(false).Build()).WithJSONFormat().Build())
+	http := parent.(GroupLogger).WithGroup("http").WithField("method", "GET")
+	http.WithField("status", 500)
+	if http.(GroupLogger).WithGroup("") != http {
+		t.Error("expected an empty group to return the logger itself")
+	}
+
+	http.Info("request")
+	if want := `{"http":{"method":"GET"},"level":"INFO","message":"request"}`; strings.TrimSpace(buf.String()) != want {
+		t.Errorf("got %s, want %s", buf.String(), want)
+	}
+}
+
+func TestGroupStyle_YAML(t *testing.T) {
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "json", GroupStyle: "dotted"}); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	if style := builder.Build().Formatter.GroupStyle; style != DottedGroups {
+		t.Errorf("expected dotted groups, got %v", style)
+	}
+
+	err := configureFormatterFromYAML(NewLoggerConfig(), &YAMLConfig{Format: "json", GroupStyle: "flat"})
+	if err == nil || !strings.Contains(err.Error(), "group_style") {
+		t.Errorf("expected a group_style error, got %v", err)
+	}
+}
@@ -232,6 +232,13 @@ func (h *AsyncHandler) Close() {
 	_ = h.worker.Stop()
 }
 
+// StopWithTimeout shuts down the handler, handling queued records until
+// ctx is done and dropping the rest. It returns the number of dropped
+// records; see AsyncWorker.StopWithTimeout.
+func (h *AsyncHandler) StopWithTimeout(ctx context.Context) (int, error) {
+	return h.worker.StopWithTimeout(ctx)
+}
+
 func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return NewAsyncHandler(h.handler.WithAttrs(attrs), h.worker.QueueCapacity())
 }
@@ -98,9 +98,18 @@ func LevelFilterMiddleware(minLevel slog.Level) HandlerMiddleware {
 	})
 }
 
+// SamplingMiddleware keeps one in every rate records. Records whose context
+// carries a trace ID are kept or dropped with the rest of their trace, as
+// in SamplingOutput.
 func SamplingMiddleware(rate int) HandlerMiddleware {
 	counter := 0
 	return handlerMiddlewareFunc(func(ctx context.Context, record slog.Record, next HandlerFunc) error {
+		if traceID, ok := GetTraceID(ctx); ok && traceID != "" && rate > 0 {
+			if !traceSampled(traceID, uint64(rate)) {
+				return nil
+			}
+			return next(ctx, record)
+		}
 		counter++
 		if counter%rate != 0 {
 			return nil
@@ -3,6 +3,7 @@ package logging
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"log/slog"
 	"regexp"
 	"strings"
@@ -144,6 +145,26 @@ func TestSamplingMiddleware(t *testing.T) {
 	}
 }
 
+func TestSamplingMiddleware_Trace(t *testing.T) {
+	var buf bytes.Buffer
+	mh := NewMiddlewareHandler(slog.NewTextHandler(&buf, nil), SamplingMiddleware(3))
+
+	for i := 0; i < 12; i++ {
+		traceID := fmt.Sprintf("trace-%d", i)
+		ctx := WithTraceID(context.Background(), traceID)
+		for j := 0; j < 2; j++ {
+			_ = mh.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, traceID+";", 0))
+		}
+		want := 0
+		if traceSampled(traceID, 3) {
+			want = 2
+		}
+		if got := strings.Count(buf.String(), traceID+";"); got != want {
+			t.Errorf("expected %d records of %s, got %d", want, traceID, got)
+		}
+	}
+}
+
 func TestStaticFieldsMiddleware(t *testing.T) {
 	var buf bytes.Buffer
 	handler := slog.NewJSONHandler(&buf, nil)
@@ -0,0 +1,227 @@
+package logging
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// histogramRelativeError bounds the error of reported percentiles.
+const histogramRelativeError = 0.01
+
+// histogramGamma is the ratio between consecutive bucket boundaries.
+var histogramGamma = (1 + histogramRelativeError) / (1 - histogramRelativeError)
+
+// durationHistogram accumulates durations in logarithmic buckets so that any
+// percentile can be estimated within histogramRelativeError using memory
+// proportional to the range of values rather than their count.
+type durationHistogram struct {
+	buckets map[int]uint64
+	count   uint64
+	sum     time.Duration
+	min     time.Duration
+	max     time.Duration
+}
+
+func newDurationHistogram() *durationHistogram {
+	return &durationHistogram{buckets: make(map[int]uint64)}
+}
+
+func (h *durationHistogram) observe(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+	h.buckets[histogramBucket(d)]++
+}
+
+// quantile estimates the q-th quantile (0 <= q <= 1).
+func (h *durationHistogram) quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	indexes := make([]int, 0, len(h.buckets))
+	for i := range h.buckets {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	rank := uint64(math.Ceil(q * float64(h.count)))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var seen uint64
+	for _, i := range indexes {
+		seen += h.buckets[i]
+		if seen >= rank {
+			return clampDuration(histogramValue(i), h.min, h.max)
+		}
+	}
+	return h.max
+}
+
+// histogramBucket returns the bucket index for d. Durations under one
+// nanosecond share bucket zero.
+func histogramBucket(d time.Duration) int {
+	if d <= 1 {
+		return 0
+	}
+	return int(math.Ceil(math.Log(float64(d)) / math.Log(histogramGamma)))
+}
+
+// histogramValue returns the representative duration of bucket i.
+func histogramValue(i int) time.Duration {
+	if i == 0 {
+		return 0
+	}
+	return time.Duration(2 * math.Pow(histogramGamma, float64(i)) / (histogramGamma + 1))
+}
+
+func clampDuration(d, lo, hi time.Duration) time.Duration {
+	if d < lo {
+		return lo
+	}
+	if d > hi {
+		return hi
+	}
+	return d
+}
+
+// DurationAggregator summarizes repeated duration measurements. Instead of
+// logging every occurrence, it accumulates a histogram per key and logs one
+// summary entry per key each interval with count, min, max, avg, p50, p95
+// and p99 in milliseconds.
+//
+// Example:
+//
+//	agg := logging.NewDurationAggregator(logger, time.Minute)
+//	defer agg.Stop()
+//
+//	start := time.Now()
+//	handle(req)
+//	agg.Observe("handle_request", time.Since(start))
+type DurationAggregator struct {
+	logger     Logger
+	level      Level
+	interval   time.Duration
+	histograms map[string]*durationHistogram
+	flushTimer *time.Timer
+	mu         sync.Mutex
+	stopped    bool
+}
+
+// NewDurationAggregator creates an aggregator that logs summaries at INFO
+// level through logger every interval. A zero interval disables periodic
+// summaries; call Flush to emit them.
+func NewDurationAggregator(logger Logger, interval time.Duration) *DurationAggregator {
+	a := &DurationAggregator{
+		logger:     logger,
+		level:      InfoLevel,
+		interval:   interval,
+		histograms: make(map[string]*durationHistogram),
+	}
+
+	if interval > 0 {
+		a.flushTimer = time.AfterFunc(interval, a.periodicFlush)
+	}
+
+	return a
+}
+
+// SetLevel changes the level of summary entries.
+func (a *DurationAggregator) SetLevel(level Level) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.level = level
+}
+
+// Observe records one duration for key.
+func (a *DurationAggregator) Observe(key string, d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stopped {
+		return
+	}
+
+	h, ok := a.histograms[key]
+	if !ok {
+		h = newDurationHistogram()
+		a.histograms[key] = h
+	}
+	h.observe(d)
+}
+
+// Flush logs a summary for every key observed since the last flush and
+// resets the histograms. Keys are summarized in name order.
+func (a *DurationAggregator) Flush() {
+	a.mu.Lock()
+	histograms := a.histograms
+	level := a.level
+	a.histograms = make(map[string]*durationHistogram)
+	a.mu.Unlock()
+
+	keys := make([]string, 0, len(histograms))
+	for k := range histograms {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		h := histograms[key]
+		a.logger.WithFields(map[string]interface{}{
+			"metric": key,
+			"count":  h.count,
+			"min_ms": durationMillis(h.min),
+			"max_ms": durationMillis(h.max),
+			"avg_ms": durationMillis(h.sum / time.Duration(h.count)),
+			"p50_ms": durationMillis(h.quantile(0.50)),
+			"p95_ms": durationMillis(h.quantile(0.95)),
+			"p99_ms": durationMillis(h.quantile(0.99)),
+		}).Log(level, "%s duration summary", key)
+	}
+}
+
+// periodicFlush is called by the timer to emit summaries.
+func (a *DurationAggregator) periodicFlush() {
+	a.Flush()
+
+	a.mu.Lock()
+	if !a.stopped {
+		a.flushTimer = time.AfterFunc(a.interval, a.periodicFlush)
+	}
+	a.mu.Unlock()
+}
+
+// Stop ends periodic summaries and flushes pending observations. Observations
+// after Stop are ignored.
+func (a *DurationAggregator) Stop() error {
+	a.mu.Lock()
+	if a.stopped {
+		a.mu.Unlock()
+		return nil
+	}
+	a.stopped = true
+	if a.flushTimer != nil {
+		a.flushTimer.Stop()
+	}
+	a.mu.Unlock()
+
+	a.Flush()
+	return nil
+}
+
+// durationMillis converts d to fractional milliseconds.
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDurationHistogram_Quantiles(t *testing.T) {
+	h := newDurationHistogram()
+	for i := 1; i <= 1000; i++ {
+		h.observe(time.Duration(i) * time.Millisecond)
+	}
+
+	cases := map[float64]time.Duration{
+		0.50: 500 * time.Millisecond,
+		0.95: 950 * time.Millisecond,
+		0.99: 990 * time.Millisecond,
+	}
+	for q, want := range cases {
+		got := h.quantile(q)
+		if diff := math.Abs(float64(got-want)) / float64(want); diff > histogramRelativeError {
+			t.Errorf("quantile(%v) = %v, want %v within %v", q, got, want, histogramRelativeError)
+		}
+	}
+	if h.quantile(1) != time.Second || h.quantile(0) != time.Millisecond {
+		t.Errorf("expected extremes to be exact, got %v and %v", h.quantile(0), h.quantile(1))
+	}
+}
+
+func TestDurationAggregator_Flush(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+
+	agg := NewDurationAggregator(logger, 0)
+	for i := 0; i < 100; i++ {
+		agg.Observe("db_query", 10*time.Millisecond)
+	}
+	agg.Observe("cache_get", time.Millisecond)
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected no entries before flush, got: %s", buf.String())
+	}
+
+	agg.Flush()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one summary per key, got %d: %s", len(lines), buf.String())
+	}
+
+	entry, _ := ParseJSONEntry([]byte(lines[1]))
+	if entry.Fields["metric"] != "db_query" || entry.Fields["count"] != float64(100) {
+		t.Errorf("unexpected summary fields: %v", entry.Fields)
+	}
+	if entry.Fields["p99_ms"] != float64(10) {
+		t.Errorf("expected p99_ms=10, got %v", entry.Fields["p99_ms"])
+	}
+
+	buf.Reset()
+	agg.Flush()
+	if buf.Len() != 0 {
+		t.Errorf("expected histograms to reset after flush, got: %s", buf.String())
+	}
+}
+
+func TestDurationAggregator_Stop(t *testing.T) {
+	buf := &bytes.Buffer{}
+	output := NewWriterOutput(buf)
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(output).Build())
+
+	agg := NewDurationAggregator(logger, time.Hour)
+	StartTimer(context.Background()).Record(agg, "handler")
+
+	if err := agg.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "handler duration summary") {
+		t.Errorf("expected summary on stop, got: %s", buf.String())
+	}
+
+	buf.Reset()
+	agg.Observe("handler", time.Millisecond)
+	agg.Flush()
+	if buf.Len() != 0 {
+		t.Errorf("expected observations after stop to be ignored, got: %s", buf.String())
+	}
+}
+
+func TestDurationAggregator_Periodic(t *testing.T) {
+	output := &countingOutput{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(output).Build())
+
+	agg := NewDurationAggregator(logger, 10*time.Millisecond)
+	defer agg.Stop()
+	agg.Observe("tick", time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for output.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if output.count() != 1 {
+		t.Errorf("expected one periodic summary, got %d", output.count())
+	}
+}
@@ -0,0 +1,172 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPOutputConfig configures an HTTPOutput.
+type HTTPOutputConfig struct {
+	// URL receives the requests.
+	URL string
+	// Method defaults to POST.
+	Method string
+	// Headers are set on every request, e.g. an Authorization header.
+	Headers map[string]string
+	// ContentType defaults to "application/x-ndjson".
+	ContentType string
+	// BatchSize is the number of entries per request, sent as
+	// newline-delimited JSON or text depending on the formatter. 1 sends each
+	// entry in its own request. Defaults to 100.
+	BatchSize int
+	// Linger is how long a partial batch waits for more entries. Defaults
+	// to 1s.
+	Linger time.Duration
+	// QueueSize bounds the entries waiting to be sent. Defaults to 10000.
+	QueueSize int
+	// Timeout bounds each request when Client is nil. Defaults to 10s.
+	Timeout time.Duration
+	// Retry controls retries of requests that fail with network errors, 429
+	// or 5xx responses.
+	Retry RetryConfig
+	// CircuitBreaker stops requests to a destination that keeps failing.
+	CircuitBreaker CircuitBreakerConfig
+	// Client defaults to an http.Client with Timeout.
+	Client *http.Client
+	// OnError is called with the number of entries that could not be sent.
+	OnError func(entries int, err error)
+}
+
+// HTTPOutput ships formatted entries to any HTTP collector or webhook, in
+// batches of newline-delimited entries or one request per entry. Failed
+// requests are retried with backoff, and a circuit breaker stops calling a
+// destination that keeps failing until it has had time to recover.
+//
+// Example:
+//
+//	output, err := logging.NewHTTPOutput(logging.HTTPOutputConfig{
+//		URL:     "https://collector.example.com/ingest",
+//		Headers: map[string]string{"Authorization": "Bearer " + token},
+//	})
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithCustomOutput(output).
+//		Build())
+type HTTPOutput struct {
+	config  HTTPOutputConfig
+	breaker *circuitBreaker
+	batcher *batcher[[]byte]
+}
+
+// NewHTTPOutput creates an HTTPOutput.
+func NewHTTPOutput(config HTTPOutputConfig) (*HTTPOutput, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("http output requires a URL")
+	}
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/x-ndjson"
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Linger == 0 {
+		config.Linger = time.Second
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 10000
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: config.Timeout}
+	}
+
+	ho := &HTTPOutput{config: config, breaker: newCircuitBreaker(config.CircuitBreaker)}
+	ho.batcher = newBatcher(batcherConfig[[]byte]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     ho.send,
+		OnError: func(batch [][]byte, err error) {
+			ho.reportError(len(batch), err)
+		},
+	})
+	return ho, nil
+}
+
+// Write queues a copy of data.
+func (ho *HTTPOutput) Write(data []byte) error {
+	return ho.batcher.Submit(append([]byte(nil), data...))
+}
+
+// send posts entries as one request, each entry on its own line.
+func (ho *HTTPOutput) send(entries [][]byte) error {
+	if !ho.breaker.allow() {
+		return ErrCircuitOpen
+	}
+
+	var body []byte
+	if len(entries) == 1 {
+		body = entries[0]
+	} else {
+		var buf bytes.Buffer
+		for _, entry := range entries {
+			buf.Write(entry)
+			if len(entry) == 0 || entry[len(entry)-1] != '\n' {
+				buf.WriteByte('\n')
+			}
+		}
+		body = buf.Bytes()
+	}
+
+	err := retry(context.Background(), ho.config.Retry, func() error {
+		req, err := http.NewRequest(ho.config.Method, ho.config.URL, bytes.NewReader(body))
+		if err != nil {
+			return permanent(err)
+		}
+		req.Header.Set("Content-Type", ho.config.ContentType)
+		for k, v := range ho.config.Headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := ho.config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		return checkHTTPResponse(resp)
+	})
+	ho.breaker.record(err)
+	if err != nil {
+		return fmt.Errorf("failed to send logs to %s: %w", ho.config.URL, err)
+	}
+	return nil
+}
+
+func (ho *HTTPOutput) reportError(entries int, err error) {
+	if ho.config.OnError != nil {
+		ho.config.OnError(entries, err)
+	}
+}
+
+// CircuitOpen reports whether requests are currently being skipped because
+// the destination kept failing.
+func (ho *HTTPOutput) CircuitOpen() bool {
+	return ho.breaker.open()
+}
+
+// Flush sends the pending partial batch.
+func (ho *HTTPOutput) Flush() error {
+	return ho.batcher.Flush()
+}
+
+// Close sends queued entries.
+func (ho *HTTPOutput) Close() error {
+	return ho.batcher.Stop()
+}
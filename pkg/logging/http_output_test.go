@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPOutput_Validation(t *testing.T) {
+	if _, err := NewHTTPOutput(HTTPOutputConfig{}); err == nil {
+		t.Error("expected error without a URL")
+	}
+}
+
+func TestHTTPOutput_Batches(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	var auth, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, string(body))
+		auth, contentType = r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	output, err := NewHTTPOutput(HTTPOutputConfig{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer t"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create http output: %v", err)
+	}
+	_ = output.Write([]byte(`{"msg":"a"}` + "\n"))
+	_ = output.Write([]byte(`{"msg":"b"}`))
+	if err := output.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 || bodies[0] != "{\"msg\":\"a\"}\n{\"msg\":\"b\"}\n" {
+		t.Errorf("expected one NDJSON batch, got %q", bodies)
+	}
+	if auth != "Bearer t" || contentType != "application/x-ndjson" {
+		t.Errorf("unexpected headers: auth %q, content type %q", auth, contentType)
+	}
+}
+
+func TestHTTPOutput_SingleEntries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	output, _ := NewHTTPOutput(HTTPOutputConfig{URL: server.URL, BatchSize: 1})
+	for i := 0; i < 3; i++ {
+		_ = output.Write([]byte("line\n"))
+	}
+	_ = output.Close()
+
+	if got := requests.Load(); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestHTTPOutput_CircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var errs []error
+	output, _ := NewHTTPOutput(HTTPOutputConfig{
+		URL:            server.URL,
+		BatchSize:      1,
+		Retry:          RetryConfig{MaxRetries: -1},
+		CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 2, Cooldown: time.Hour},
+		OnError: func(entries int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	for i := 0; i < 4; i++ {
+		_ = output.Write([]byte("line\n"))
+	}
+	_ = output.Close()
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("expected requests to stop after 2 failures, got %d", got)
+	}
+	if !output.CircuitOpen() {
+		t.Error("expected the circuit to be open")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 4 || !errors.Is(errs[3], ErrCircuitOpen) {
+		t.Errorf("expected 4 errors ending with ErrCircuitOpen, got %v", errs)
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, Cooldown: time.Millisecond})
+	cb.record(errors.New("down"))
+	if cb.allow() {
+		t.Fatal("expected the circuit to be open")
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	if !cb.allow() {
+		t.Fatal("expected a probe after the cooldown")
+	}
+	if cb.allow() {
+		t.Error("expected only one probe at a time")
+	}
+	cb.record(nil)
+	if !cb.allow() || cb.open() {
+		t.Error("expected a successful probe to close the circuit")
+	}
+
+	disabled := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: -1})
+	for i := 0; i < 10; i++ {
+		disabled.record(errors.New("down"))
+	}
+	if !disabled.allow() {
+		t.Error("expected a disabled breaker to always allow")
+	}
+}
@@ -1,9 +1,9 @@
 package internal
 
 import (
-	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -25,6 +25,19 @@ func FormatFilename(file string, line int, useShort bool) string {
 	return fmt.Sprintf("%s:%d", file, line)
 }
 
+// AppendFilename appends the formatted filename and line number to buf.
+// It produces the same text as FormatFilename without intermediate allocations.
+func AppendFilename(buf []byte, file string, line int, useShort bool) []byte {
+	if useShort {
+		if idx := strings.LastIndex(file, "/"); idx >= 0 {
+			file = file[idx+1:]
+		}
+	}
+	buf = append(buf, file...)
+	buf = append(buf, ':')
+	return strconv.AppendInt(buf, int64(line), 10)
+}
+
 // ApplyRedactionPatterns applies redaction patterns to a message
 func ApplyRedactionPatterns(message string, patterns []*regexp.Regexp) string {
 	result := message
@@ -34,53 +47,6 @@ func ApplyRedactionPatterns(message string, patterns []*regexp.Regexp) string {
 	return result
 }
 
-// ExtractContextFields extracts common context fields used throughout logging
-func ExtractContextFields(ctx context.Context) ContextFields {
-	if ctx == nil {
-		return ContextFields{}
-	}
-
-	fields := ContextFields{}
-
-	fields.TraceID = extractTraceID(ctx)
-	fields.RequestID = extractRequestID(ctx)
-	fields.CorrelationID = extractCorrelationID(ctx)
-
-	return fields
-}
-
-func extractTraceID(ctx context.Context) string {
-	if val := ctx.Value(contextKey("trace_id")); val != nil {
-		if traceID, ok := val.(string); ok {
-			return traceID
-		}
-	}
-	return ""
-}
-
-func extractRequestID(ctx context.Context) string {
-	if val := ctx.Value(contextKey("request_id")); val != nil {
-		if requestID, ok := val.(string); ok {
-			return requestID
-		}
-	}
-	return ""
-}
-
-func extractCorrelationID(ctx context.Context) string {
-	if val := ctx.Value(contextKey("correlation_id")); val != nil {
-		if correlationID, ok := val.(string); ok {
-			return correlationID
-		}
-	}
-	return ""
-}
-
-// Context keys (duplicated to avoid circular import)
-type contextKey string
-
-// Remove unused constants - only keep the contextKey type
-
 // AddContextFieldsToMap adds context fields to a map for JSON/structured output
 func (cf ContextFields) AddToMap(data map[string]interface{}) {
 	if cf.TraceID != "" {
@@ -113,6 +79,21 @@ func (cf ContextFields) FormatForText() string {
 	return ""
 }
 
+// Omit returns a copy without the identifiers whose keys are already present
+// in fields, so they are not rendered twice.
+func (cf ContextFields) Omit(fields map[string]interface{}) ContextFields {
+	if _, ok := fields["trace_id"]; ok {
+		cf.TraceID = ""
+	}
+	if _, ok := fields["request_id"]; ok {
+		cf.RequestID = ""
+	}
+	if _, ok := fields["correlation_id"]; ok {
+		cf.CorrelationID = ""
+	}
+	return cf
+}
+
 // HasAnyFields returns true if any context fields are present
 func (cf ContextFields) HasAnyFields() bool {
 	return cf.TraceID != "" || cf.RequestID != "" || cf.CorrelationID != ""
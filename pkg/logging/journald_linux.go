@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// isMessageTooLong reports whether err means a datagram exceeded the socket
+// buffer.
+func isMessageTooLong(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE)
+}
+
+// sendJournalFD passes data to journald through an unlinked temporary file,
+// which the native protocol accepts for entries too large for a datagram.
+func sendJournalFD(conn *net.UnixConn, data []byte) error {
+	file, err := os.CreateTemp("/dev/shm", "journal-")
+	if err != nil {
+		file, err = os.CreateTemp("", "journal-")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create journal payload file: %w", err)
+	}
+	defer file.Close()
+
+	if err := os.Remove(file.Name()); err != nil {
+		return fmt.Errorf("failed to unlink journal payload file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write journal payload file: %w", err)
+	}
+
+	_, _, err = conn.WriteMsgUnix(nil, syscall.UnixRights(int(file.Fd())), nil)
+	return err
+}
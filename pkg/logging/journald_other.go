@@ -0,0 +1,18 @@
+//go:build !linux
+
+package logging
+
+import (
+	"errors"
+	"net"
+)
+
+// isMessageTooLong always reports false; journald only runs on Linux.
+func isMessageTooLong(error) bool {
+	return false
+}
+
+// sendJournalFD is unsupported outside Linux.
+func sendJournalFD(*net.UnixConn, []byte) error {
+	return errors.New("journald is only available on Linux")
+}
@@ -0,0 +1,318 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ocrosby/go-logging/pkg/logging/internal"
+)
+
+// JSONEncoder encodes the field values of JSON output that are not strings,
+// numbers, booleans, times or nil, which JSONFormatter appends itself.
+type JSONEncoder interface {
+	// AppendJSON appends the JSON encoding of v to buf.
+	AppendJSON(buf []byte, v interface{}) ([]byte, error)
+}
+
+// JSONMarshalFunc adapts a function compatible with json.Marshal, such as
+// jsoniter's or sonic's, to JSONEncoder.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithJSONEncoder(logging.JSONMarshalFunc(jsoniter.ConfigFastest.Marshal)).
+//		Build()
+type JSONMarshalFunc func(v interface{}) ([]byte, error)
+
+// AppendJSON appends the encoding of v returned by f to buf.
+func (f JSONMarshalFunc) AppendJSON(buf []byte, v interface{}) ([]byte, error) {
+	data, err := f(v)
+	if err != nil {
+		return buf, err
+	}
+	return append(buf, data...), nil
+}
+
+// StandardJSONEncoder encodes values with encoding/json, the default.
+var StandardJSONEncoder JSONEncoder = JSONMarshalFunc(json.Marshal)
+
+// jsonEncodeState is the reusable scratch space of a JSON entry.
+type jsonEncodeState struct {
+	data    map[string]interface{}
+	keys    []string
+	buf     []byte
+	file    string
+	line    int
+	context internal.ContextFields
+}
+
+// jsonEntryField stands in the fields of a JSON entry for a value
+// JSONFormatter appends from the entry itself, which spares boxing it.
+type jsonEntryField uint8
+
+const (
+	jsonTimestampField jsonEntryField = iota
+	jsonLevelField
+	jsonMessageField
+	jsonFileField
+	jsonTraceIDField
+	jsonRequestIDField
+	jsonCorrelationIDField
+)
+
+// maxPooledJSONBuffer is the largest buffer returned to jsonEncodeStates, so
+// one huge entry does not pin its memory.
+const maxPooledJSONBuffer = 64 << 10
+
+var jsonEncodeStates = sync.Pool{
+	New: func() interface{} {
+		return &jsonEncodeState{
+			data: make(map[string]interface{}, 16),
+			buf:  make([]byte, 0, 512),
+		}
+	},
+}
+
+func getJSONEncodeState() *jsonEncodeState {
+	return jsonEncodeStates.Get().(*jsonEncodeState)
+}
+
+func putJSONEncodeState(s *jsonEncodeState) {
+	if cap(s.buf) > maxPooledJSONBuffer {
+		return
+	}
+	clear(s.data)
+	clear(s.keys)
+	s.keys = s.keys[:0]
+	s.buf = s.buf[:0]
+	s.file, s.line, s.context = "", 0, internal.ContextFields{}
+	jsonEncodeStates.Put(s)
+}
+
+// sortedKeysInto returns the keys of data sorted, reusing keys.
+func sortedKeysInto(keys []string, data map[string]interface{}) []string {
+	keys = keys[:0]
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendObject appends the fields of state as a JSON object with its keys
+// in order.
+func (f *JSONFormatter) appendObject(buf []byte, entry LogEntry, state *jsonEncodeState, keys []string) ([]byte, error) {
+	buf = append(buf, '{')
+	for i, k := range keys {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendJSONString(buf, k)
+		buf = append(buf, ':')
+		if field, ok := state.data[k].(jsonEntryField); ok {
+			buf = f.appendEntryField(buf, field, entry, state)
+			continue
+		}
+		var err error
+		if buf, err = appendJSONValue(buf, state.data[k], f.config.JSONEncoder); err != nil {
+			return nil, fmt.Errorf("failed to marshal field %s: %w", k, err)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// appendEntryField appends the value field stands for.
+func (f *JSONFormatter) appendEntryField(buf []byte, field jsonEntryField, entry LogEntry, state *jsonEncodeState) []byte {
+	switch field {
+	case jsonTimestampField:
+		return appendJSONText(buf, func(buf []byte) []byte {
+			return f.config.appendTime(buf, entry.Timestamp, f.config.TimePrecision.layout(time.RFC3339), time.UTC)
+		})
+	case jsonLevelField:
+		return appendJSONString(buf, f.config.levelName(entry.Level))
+	case jsonMessageField:
+		return appendJSONString(buf, f.applyRedaction(entry.Message))
+	case jsonFileField:
+		return appendJSONText(buf, func(buf []byte) []byte {
+			return internal.AppendFilename(buf, state.file, state.line, f.config.UseShortFile)
+		})
+	case jsonTraceIDField:
+		return appendJSONString(buf, state.context.TraceID)
+	case jsonRequestIDField:
+		return appendJSONString(buf, state.context.RequestID)
+	default:
+		return appendJSONString(buf, state.context.CorrelationID)
+	}
+}
+
+// appendJSONText appends the text appendText appends as a JSON string. The
+// text is appended in place and only escaped when it needs to be.
+func appendJSONText(buf []byte, appendText func([]byte) []byte) []byte {
+	buf = append(buf, '"')
+	start := len(buf)
+	buf = appendText(buf)
+	for _, b := range buf[start:] {
+		if b < 0x20 || b >= utf8.RuneSelf || b == '"' || b == '\\' || b == '<' || b == '>' || b == '&' {
+			text := string(buf[start:])
+			return appendJSONString(buf[:start-1], text)
+		}
+	}
+	return append(buf, '"')
+}
+
+// appendJSONValue appends the JSON encoding of v to buf, as encoding/json
+// would, without reflection for common types.
+func appendJSONValue(buf []byte, v interface{}, enc JSONEncoder) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(buf, "null"...), nil
+	case string:
+		return appendJSONString(buf, v), nil
+	case bool:
+		return strconv.AppendBool(buf, v), nil
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(buf, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(buf, v, 10), nil
+	case uint:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(buf, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(buf, v, 10), nil
+	case float32:
+		if !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0) {
+			return appendJSONFloat(buf, float64(v), 32), nil
+		}
+	case float64:
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			return appendJSONFloat(buf, v, 64), nil
+		}
+	case time.Time:
+		if year := v.Year(); year >= 0 && year <= 9999 {
+			buf = append(buf, '"')
+			buf = v.AppendFormat(buf, time.RFC3339Nano)
+			return append(buf, '"'), nil
+		}
+	}
+	if enc == nil {
+		enc = StandardJSONEncoder
+	}
+	return enc.AppendJSON(buf, v)
+}
+
+// appendJSONFloat appends f the way encoding/json does: in exponent form
+// outside [1e-6, 1e21), with a minimal exponent.
+func appendJSONFloat(buf []byte, f float64, bits int) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, bits)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		n := len(buf)
+		if n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf
+}
+
+const jsonHex = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string, escaped as encoding/json
+// escapes it, HTML characters included.
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				buf = append(buf, '\\', b)
+			case '\b':
+				buf = append(buf, '\\', 'b')
+			case '\f':
+				buf = append(buf, '\\', 'f')
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', jsonHex[b>>4], jsonHex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are valid JSON but end lines in JavaScript.
+		if r == '\u2028' || r == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', jsonHex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
+
+// WithJSONEncoder sets the encoder of JSON field values.
+func (b *FormatterConfigBuilder) WithJSONEncoder(enc JSONEncoder) *FormatterConfigBuilder {
+	b.config.JSONEncoder = enc
+	return b
+}
+
+// WithJSONEncoder sets the encoder of the JSON format's field values other
+// than strings, numbers, booleans, times and nil, such as maps and structs.
+// The default is StandardJSONEncoder; JSONMarshalFunc adapts faster
+// encoders such as jsoniter or sonic.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithJSONEncoder(logging.JSONMarshalFunc(sonic.Marshal)).
+//		Build()
+func (b *LoggerConfigBuilder) WithJSONEncoder(enc JSONEncoder) *LoggerConfigBuilder {
+	b.config.Formatter.JSONEncoder = enc
+	return b
+}
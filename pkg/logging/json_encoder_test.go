@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendJSONValue_MatchesEncodingJSON(t *testing.T) {
+	values := []interface{}{
+		nil,
+		"plain",
+		"quotes \" and \\ backslashes",
+		"control \b\f\n\r\t\x00\x1f",
+		"<html> & entities",
+		"unicode é 日本 \u2028\u2029",
+		"invalid \xff utf-8",
+		true,
+		-42,
+		int8(-8),
+		int16(16),
+		int32(-32),
+		int64(math.MinInt64),
+		uint(7),
+		uint8(255),
+		uint16(16),
+		uint32(32),
+		uint64(math.MaxUint64),
+		0.0,
+		12.5,
+		-1e-7,
+		1e21,
+		123456789.125,
+		float32(3.14),
+		float32(1e-7),
+		time.Date(2025, 11, 24, 10, 30, 0, 500, time.FixedZone("CET", 3600)),
+		map[string]interface{}{"b": 1, "a": []int{1, 2}},
+		struct{ Name string }{"x"},
+		errors.New("boom"),
+		time.Second,
+	}
+	for _, v := range values {
+		want, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("json.Marshal(%#v) returned error: %v", v, err)
+		}
+		got, err := appendJSONValue(nil, v, nil)
+		if err != nil {
+			t.Fatalf("appendJSONValue(%#v) returned error: %v", v, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("appendJSONValue(%#v) = %s, want %s", v, got, want)
+		}
+	}
+
+	if _, err := appendJSONValue(nil, math.NaN(), nil); err == nil {
+		t.Error("expected an error for NaN")
+	}
+}
+
+func TestJSONFormatter_JSONEncoder(t *testing.T) {
+	var encoded []interface{}
+	encoder := JSONMarshalFunc(func(v interface{}) ([]byte, error) {
+		encoded = append(encoded, v)
+		return []byte(`"custom"`), nil
+	})
+	formatter := NewJSONFormatter(NewFormatterConfig().IncludeTime(false).IncludeFile(false).WithJSONEncoder(encoder).Build())
+
+	data, err := formatter.Format(LogEntry{
+		Level:   InfoLevel,
+		Message: "hi",
+		Fields:  map[string]interface{}{"user": map[string]string{"id": "1"}, "count": 3},
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	if want := `{"count":3,"level":"INFO","message":"hi","user":"custom"}` + "\n"; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+	if len(encoded) != 1 {
+		t.Errorf("expected only the map to be passed to the encoder, got %v", encoded)
+	}
+
+	failing := JSONMarshalFunc(func(interface{}) ([]byte, error) { return nil, errors.New("unsupported") })
+	formatter = NewJSONFormatter(NewFormatterConfig().WithJSONEncoder(failing).Build())
+	if _, err := formatter.Format(LogEntry{Fields: map[string]interface{}{"ch": make(chan int)}}); err == nil || !strings.Contains(err.Error(), "ch") {
+		t.Errorf("expected an error naming the field, got %v", err)
+	}
+}
+
+func TestJSONFormatter_EscapesBuiltInFields(t *testing.T) {
+	formatter := NewJSONFormatter(NewFormatterConfig().
+		UseShortFile(false).
+		WithTimeFormat(`"2006"`).
+		Build())
+	data, err := formatter.Format(LogEntry{
+		Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Level:     InfoLevel,
+		Message:   "hi",
+		File:      `C:\src\main.go`,
+		Line:      7,
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, data)
+	}
+	if got["timestamp"] != `"2025"` || got["file"] != `C:\src\main.go:7` {
+		t.Errorf("unexpected entry %s", data)
+	}
+}
@@ -0,0 +1,458 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// jsonlIndexSuffix is appended to a JSONL filename to name its side index.
+const jsonlIndexSuffix = ".idx"
+
+// defaultJSONLBlockSize is the number of entries covered by one index block.
+const defaultJSONLBlockSize = 256
+
+// JSONLIndexBlock describes a contiguous run of entries in a JSONL file.
+// The side index is itself a JSONL file with one block per line.
+type JSONLIndexBlock struct {
+	Offset int64     `json:"offset"`
+	Length int64     `json:"length"`
+	Count  int       `json:"count"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	// Untimed counts entries with no parseable timestamp and no earlier
+	// entry to take one from, so they are outside Start and End.
+	Untimed int `json:"untimed,omitempty"`
+}
+
+// overlaps reports whether the block may contain entries within [since, until].
+// A zero since or until is treated as unbounded. Blocks with untimed
+// entries always may.
+func (b JSONLIndexBlock) overlaps(since, until time.Time) bool {
+	if b.Untimed > 0 {
+		return true
+	}
+	if !since.IsZero() && b.End.Before(since) {
+		return false
+	}
+	if !until.IsZero() && b.Start.After(until) {
+		return false
+	}
+	return true
+}
+
+// JSONLConfig configures a JSONLWriter or JSONLReader.
+type JSONLConfig struct {
+	// BlockSize is the number of entries per index block. Defaults to 256.
+	BlockSize int
+	// Keys are the field keys the entries were written with, as set by
+	// WithFieldKeys. Empty keys are the JSONFormatter defaults.
+	Keys FieldKeys
+}
+
+// JSONLWriter appends JSON lines to a file and maintains a side index
+// (<filename>.idx) recording the offset and time range of every block of
+// entries. It implements the Output interface.
+//
+// Example:
+//
+//	w, err := logging.NewJSONLWriter("/var/log/app.jsonl", 0)
+//	if err != nil {
+//		return err
+//	}
+//	defer w.Close()
+type JSONLWriter struct {
+	filename     string
+	file         *os.File
+	index        *os.File
+	blockSize    int
+	timestampKey string
+	offset       int64
+	block        JSONLIndexBlock
+	last         time.Time // timestamp of the last entry that had one
+	mu           sync.Mutex
+}
+
+// NewJSONLWriter opens (or creates) filename for appending. blockSize is the
+// number of entries per index block; zero or negative selects the default.
+func NewJSONLWriter(filename string, blockSize int) (*JSONLWriter, error) {
+	return NewJSONLWriterWithConfig(filename, JSONLConfig{BlockSize: blockSize})
+}
+
+// NewJSONLWriterWithConfig opens (or creates) filename for appending.
+// Entries left unindexed by a writer that was not closed cleanly are
+// indexed before the first write.
+func NewJSONLWriterWithConfig(filename string, config JSONLConfig) (*JSONLWriter, error) {
+	blockSize := config.BlockSize
+	if blockSize <= 0 {
+		blockSize = defaultJSONLBlockSize
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	index, err := os.OpenFile(filename+jsonlIndexSuffix, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to open index file: %w", err)
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		_ = index.Close()
+		return nil, fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	w := &JSONLWriter{
+		filename:     filename,
+		file:         file,
+		index:        index,
+		blockSize:    blockSize,
+		timestampKey: config.Keys.jsonKeys().Timestamp,
+		offset:       stat.Size(),
+		block:        JSONLIndexBlock{Offset: stat.Size()},
+	}
+	if err := w.indexTail(); err != nil {
+		_ = file.Close()
+		_ = index.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// indexTail drops a torn trailing index line and indexes the entries after
+// the last indexed block, terminating a torn last entry.
+func (w *JSONLWriter) indexTail() error {
+	data, err := os.ReadFile(w.filename + jsonlIndexSuffix)
+	if err != nil {
+		return fmt.Errorf("failed to read index file: %w", err)
+	}
+	if i := bytes.LastIndexByte(data, '\n'); i+1 < len(data) {
+		if err := w.index.Truncate(int64(i + 1)); err != nil {
+			return fmt.Errorf("failed to repair index file: %w", err)
+		}
+		data = data[:i+1]
+	}
+
+	var indexedEnd int64
+	for _, block := range parseJSONLIndex(data) {
+		if block.Offset+block.Length > indexedEnd {
+			indexedEnd = block.Offset + block.Length
+			w.last = block.End
+		}
+	}
+	if indexedEnd >= w.offset {
+		return nil
+	}
+
+	file, err := os.Open(w.filename)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	end := w.offset
+	w.offset = indexedEnd
+	w.block = JSONLIndexBlock{Offset: indexedEnd}
+	r := bufio.NewReader(io.NewSectionReader(file, indexedEnd, end-indexedEnd))
+	for {
+		line, readErr := r.ReadBytes('\n')
+		if len(line) == 0 {
+			break
+		}
+		if line[len(line)-1] != '\n' {
+			if _, err := w.file.Write([]byte{'\n'}); err != nil {
+				return fmt.Errorf("failed to write to log file: %w", err)
+			}
+			line = append(line, '\n')
+		}
+		w.offset += int64(len(line))
+		if len(bytes.TrimSpace(line)) > 0 {
+			if err := w.indexLine(line); err != nil {
+				return err
+			}
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// Write appends one or more newline-terminated JSON entries to the file and
+// updates the index. Entries without a parseable timestamp are indexed at
+// the timestamp of the entry before them.
+func (w *JSONLWriter) Write(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return fmt.Errorf("jsonl writer is closed")
+	}
+
+	for _, line := range splitJSONLines(data) {
+		if err := w.writeLine(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *JSONLWriter) writeLine(line []byte) error {
+	n, err := w.file.Write(line)
+	w.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write to log file: %w", err)
+	}
+	return w.indexLine(line)
+}
+
+// indexLine adds the line ending at the current offset to the current
+// block.
+func (w *JSONLWriter) indexLine(line []byte) error {
+	if ts, ok := jsonlTimestamp(line, w.timestampKey); ok {
+		w.last = ts
+	}
+	switch {
+	case w.last.IsZero():
+		w.block.Untimed++
+	case w.block.Start.IsZero() || w.last.Before(w.block.Start):
+		w.block.Start = w.last
+	}
+	if w.last.After(w.block.End) {
+		w.block.End = w.last
+	}
+	w.block.Count++
+	w.block.Length = w.offset - w.block.Offset
+
+	if w.block.Count >= w.blockSize {
+		return w.flushBlock()
+	}
+	return nil
+}
+
+// flushBlock appends the current block to the side index and starts a new one.
+func (w *JSONLWriter) flushBlock() error {
+	if w.block.Count == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(w.block)
+	if err != nil {
+		return fmt.Errorf("failed to encode index block: %w", err)
+	}
+	if _, err := w.index.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write index block: %w", err)
+	}
+
+	w.block = JSONLIndexBlock{Offset: w.offset}
+	return nil
+}
+
+// Close writes any partial index block and closes both files.
+func (w *JSONLWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	flushErr := w.flushBlock()
+	fileErr := w.file.Close()
+	indexErr := w.index.Close()
+	w.file = nil
+	w.index = nil
+
+	return errors.Join(flushErr, fileErr, indexErr)
+}
+
+// JSONLReader reads entries from a JSONL file written by JSONLWriter, using the
+// side index to skip blocks outside a requested time range. Entries written
+// after the last indexed block (for example when the writer was not closed
+// cleanly) are scanned and filtered individually.
+type JSONLReader struct {
+	filename     string
+	timestampKey string
+	blocks       []JSONLIndexBlock
+}
+
+// OpenJSONLReader loads the side index for filename. A missing index is not an
+// error; the whole file is then scanned.
+func OpenJSONLReader(filename string) (*JSONLReader, error) {
+	return OpenJSONLReaderWithConfig(filename, JSONLConfig{})
+}
+
+// OpenJSONLReaderWithConfig loads the side index for filename, reading
+// timestamps from the key set in config.
+func OpenJSONLReaderWithConfig(filename string, config JSONLConfig) (*JSONLReader, error) {
+	if _, err := os.Stat(filename); err != nil {
+		return nil, fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	blocks, err := readJSONLIndex(filename + jsonlIndexSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONLReader{filename: filename, timestampKey: config.Keys.jsonKeys().Timestamp, blocks: blocks}, nil
+}
+
+// Blocks returns the index blocks loaded for the file.
+func (r *JSONLReader) Blocks() []JSONLIndexBlock {
+	return r.blocks
+}
+
+// ReadRange calls fn for every entry whose timestamp falls within
+// [since, until]. A zero since or until is treated as unbounded. The line
+// passed to fn excludes the trailing newline and must not be retained.
+// Parts of the file not covered by any block are scanned in full.
+func (r *JSONLReader) ReadRange(since, until time.Time, fn func(line []byte) error) error {
+	file, err := os.Open(r.filename)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	blocks := append([]JSONLIndexBlock(nil), r.blocks...)
+	sort.SliceStable(blocks, func(i, j int) bool { return blocks[i].Offset < blocks[j].Offset })
+
+	var covered int64
+	for _, block := range blocks {
+		if block.Offset > covered {
+			gap := io.NewSectionReader(file, covered, block.Offset-covered)
+			if err := scanJSONLRange(gap, since, until, r.timestampKey, fn); err != nil {
+				return err
+			}
+		}
+		covered = max(covered, block.Offset+block.Length)
+		if !block.overlaps(since, until) {
+			continue
+		}
+		section := io.NewSectionReader(file, block.Offset, block.Length)
+		if err := scanJSONLRange(section, since, until, r.timestampKey, fn); err != nil {
+			return err
+		}
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	if stat.Size() <= covered {
+		return nil
+	}
+
+	tail := io.NewSectionReader(file, covered, stat.Size()-covered)
+	return scanJSONLRange(tail, since, until, r.timestampKey, fn)
+}
+
+// inTimeRange reports whether ts falls within [since, until], treating a zero
+// bound as unbounded.
+func inTimeRange(ts, since, until time.Time) bool {
+	if !since.IsZero() && ts.Before(since) {
+		return false
+	}
+	return until.IsZero() || !ts.After(until)
+}
+
+// scanJSONLRange filters each line of r by timestamp and passes matches to fn.
+// Lines without a parseable timestamp are always passed through.
+func scanJSONLRange(r io.Reader, since, until time.Time, timestampKey string, fn func([]byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		if ts, ok := jsonlTimestamp(line, timestampKey); ok && !inTimeRange(ts, since, until) {
+			continue
+		}
+
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// readJSONLIndex parses a side index file, returning nil if it does not exist.
+// A truncated trailing block is ignored.
+func readJSONLIndex(filename string) ([]JSONLIndexBlock, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index file: %w", err)
+	}
+	return parseJSONLIndex(data), nil
+}
+
+// parseJSONLIndex parses the blocks of a side index, skipping lines that
+// are not valid blocks.
+func parseJSONLIndex(data []byte) []JSONLIndexBlock {
+	var blocks []JSONLIndexBlock
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		var block JSONLIndexBlock
+		if err := json.Unmarshal(line, &block); err != nil {
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// splitJSONLines splits data into newline-terminated lines, adding a trailing
+// newline to the final line if it is missing.
+func splitJSONLines(data []byte) [][]byte {
+	var lines [][]byte
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			line := make([]byte, len(data)+1)
+			copy(line, data)
+			line[len(data)] = '\n'
+			return append(lines, line)
+		}
+		if i > 0 {
+			lines = append(lines, data[:i+1])
+		}
+		data = data[i+1:]
+	}
+	return lines
+}
+
+// jsonlTimestamp extracts the field named key from a JSON entry. It returns
+// false when the field is absent or unparseable.
+func jsonlTimestamp(line []byte, key string) (time.Time, bool) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(line, &probe); err != nil {
+		return time.Time{}, false
+	}
+	var value string
+	if err := json.Unmarshal(probe[key], &value); err != nil || value == "" {
+		return time.Time{}, false
+	}
+	ts, err := parseTimestamp(value)
+	return ts, err == nil
+}
@@ -0,0 +1,302 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeJSONLEntries(t *testing.T, w *JSONLWriter, base time.Time, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		ts := base.Add(time.Duration(i) * time.Minute).Format(time.RFC3339)
+		line := fmt.Sprintf(`{"timestamp":%q,"level":"INFO","message":"entry %d"}`+"\n", ts, i)
+		if err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+}
+
+func TestJSONLWriter_WritesIndexBlocks(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.jsonl")
+	w, err := NewJSONLWriter(filename, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	writeJSONLEntries(t, w, base, 10)
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	r, err := OpenJSONLReader(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blocks := r.Blocks()
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 index blocks, got %d", len(blocks))
+	}
+	if blocks[0].Count != 4 || blocks[2].Count != 2 {
+		t.Errorf("unexpected block counts: %+v", blocks)
+	}
+	if !blocks[1].Start.Equal(base.Add(4*time.Minute)) || !blocks[1].End.Equal(base.Add(7*time.Minute)) {
+		t.Errorf("unexpected time range for block 1: %v - %v", blocks[1].Start, blocks[1].End)
+	}
+	if blocks[1].Offset != blocks[0].Offset+blocks[0].Length {
+		t.Error("expected blocks to be contiguous")
+	}
+}
+
+func TestJSONLReader_ReadRange(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.jsonl")
+	w, err := NewJSONLWriter(filename, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	writeJSONLEntries(t, w, base, 10)
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	r, err := OpenJSONLReader(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	err = r.ReadRange(base.Add(4*time.Minute), base.Add(6*time.Minute), func(line []byte) error {
+		got = append(got, string(line))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries in range, got %d: %v", len(got), got)
+	}
+}
+
+func TestJSONLReader_ScansUnindexedTail(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.jsonl")
+	w, err := NewJSONLWriter(filename, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	writeJSONLEntries(t, w, base, 5)
+
+	// Reader opened before Close sees no index blocks and must scan the file.
+	r, err := OpenJSONLReader(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	count := 0
+	err = r.ReadRange(time.Time{}, time.Time{}, func(line []byte) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("expected 5 entries, got %d", count)
+	}
+
+	_ = w.Close()
+}
+
+func TestJSONLWriter_ResumesExistingFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.jsonl")
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		w, err := NewJSONLWriter(filename, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		writeJSONLEntries(t, w, base.Add(time.Duration(i)*time.Hour), 3)
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected close error: %v", err)
+		}
+	}
+
+	r, err := OpenJSONLReader(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	count := 0
+	err = r.ReadRange(base.Add(time.Hour), time.Time{}, func(line []byte) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 entries from second session, got %d", count)
+	}
+}
+
+func TestJSONLWriter_IndexesTailAfterUncleanClose(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.jsonl")
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	crashed, err := NewJSONLWriter(filename, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The third entry is written but never indexed, and the index ends torn.
+	writeJSONLEntries(t, crashed, base, 3)
+	index, _ := os.OpenFile(filename+jsonlIndexSuffix, os.O_WRONLY|os.O_APPEND, 0o644)
+	_, _ = index.WriteString(`{"offset":`)
+	_ = index.Close()
+
+	w, err := NewJSONLWriter(filename, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	writeJSONLEntries(t, w, base.Add(time.Hour), 4)
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	_ = crashed.file.Close()
+	_ = crashed.index.Close()
+
+	r, err := OpenJSONLReader(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	err = r.ReadRange(base, base.Add(30*time.Minute), func(line []byte) error {
+		got = append(got, string(line))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("expected the 3 entries of the first session, got %d: %v", len(got), got)
+	}
+}
+
+func TestJSONLWriter_CustomTimestampKey(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.jsonl")
+	config := JSONLConfig{BlockSize: 1, Keys: FieldKeys{Timestamp: "@timestamp"}}
+	w, err := NewJSONLWriterWithConfig(filename, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		ts := base.Add(time.Duration(i) * time.Hour).Format(time.RFC3339)
+		_ = w.Write([]byte(fmt.Sprintf(`{"@timestamp":%q,"message":"entry %d"}`+"\n", ts, i)))
+	}
+	_ = w.Close()
+
+	r, err := OpenJSONLReaderWithConfig(filename, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blocks := r.Blocks(); len(blocks) != 3 || !blocks[1].Start.Equal(base.Add(time.Hour)) {
+		t.Fatalf("expected blocks indexed by @timestamp, got %+v", blocks)
+	}
+	count := 0
+	_ = r.ReadRange(base.Add(time.Hour), base.Add(time.Hour), func([]byte) error {
+		count++
+		return nil
+	})
+	if count != 1 {
+		t.Errorf("expected 1 entry in range, got %d", count)
+	}
+}
+
+func TestJSONLWriter_IndexesUntimedEntries(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.jsonl")
+	w, err := NewJSONLWriter(filename, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration, message string) string {
+		return fmt.Sprintf(`{"timestamp":%q,"message":%q}`, base.Add(d).Format(time.RFC3339), message)
+	}
+	lines := []string{
+		`{"message":"boot"}`,
+		at(0, "start"),
+		at(time.Hour, "retry"),
+		`{"timestamp":"yesterday","message":"retry detail"}`,
+		at(2*time.Hour, "done"),
+		at(3*time.Hour, "idle"),
+	}
+	for _, line := range lines {
+		if err := w.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	r, err := OpenJSONLReader(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if blocks := r.Blocks(); len(blocks) != 3 || blocks[0].Untimed != 1 || !blocks[1].End.Equal(base.Add(time.Hour)) {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+
+	// An entry without a timestamp is found with the one before it, and
+	// the first, which has none to take, with every range.
+	for _, tt := range []struct {
+		since, until time.Duration
+		want         []string
+	}{
+		{30 * time.Minute, 90 * time.Minute, []string{lines[0], lines[2], lines[3]}},
+		{2 * time.Hour, 3 * time.Hour, []string{lines[0], lines[4], lines[5]}},
+	} {
+		var got []string
+		err := r.ReadRange(base.Add(tt.since), base.Add(tt.until), func(line []byte) error {
+			got = append(got, string(line))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("ReadRange(%v, %v) = %v, want %v", tt.since, tt.until, got, tt.want)
+		}
+	}
+}
+
+func TestJSONLWriter_WriteAfterClose(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.jsonl")
+	w, err := NewJSONLWriter(filename, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = w.Close()
+
+	if err := w.Write([]byte("{}\n")); err == nil {
+		t.Error("expected error writing to closed writer")
+	}
+}
+
+func TestOpenJSONLReader_MissingFile(t *testing.T) {
+	_, err := OpenJSONLReader(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
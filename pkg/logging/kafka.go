@@ -0,0 +1,174 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// KafkaCompression names a Kafka record batch compression codec.
+type KafkaCompression string
+
+// Kafka compression codecs.
+const (
+	KafkaCompressionNone   KafkaCompression = "none"
+	KafkaCompressionGzip   KafkaCompression = "gzip"
+	KafkaCompressionSnappy KafkaCompression = "snappy"
+	KafkaCompressionLZ4    KafkaCompression = "lz4"
+	KafkaCompressionZstd   KafkaCompression = "zstd"
+)
+
+// KafkaMessage is one log entry to publish.
+type KafkaMessage struct {
+	Key   []byte
+	Value []byte
+	Time  time.Time
+}
+
+// KafkaBatch is a group of messages for one topic.
+type KafkaBatch struct {
+	Topic       string
+	Compression KafkaCompression
+	Messages    []KafkaMessage
+}
+
+// KafkaProducer publishes batches to Kafka. It keeps this package free of a
+// Kafka client dependency; adapting a client takes a few lines, e.g. for
+// github.com/segmentio/kafka-go:
+//
+//	type kafkaGoProducer struct{ w *kafka.Writer }
+//
+//	func (p kafkaGoProducer) Produce(ctx context.Context, batch logging.KafkaBatch) error {
+//		msgs := make([]kafka.Message, len(batch.Messages))
+//		for i, m := range batch.Messages {
+//			msgs[i] = kafka.Message{Topic: batch.Topic, Key: m.Key, Value: m.Value, Time: m.Time}
+//		}
+//		return p.w.WriteMessages(ctx, msgs...)
+//	}
+//
+// Producers should apply batch.Compression where the client allows it.
+type KafkaProducer interface {
+	Produce(ctx context.Context, batch KafkaBatch) error
+}
+
+// KafkaConfig configures a KafkaOutput.
+type KafkaConfig struct {
+	Producer KafkaProducer
+	Topic    string
+	// KeyField names the field used as the partition key, e.g. "trace_id",
+	// so related entries land on the same partition. Context IDs are used
+	// when the entry has no such field. Empty sends unkeyed messages.
+	KeyField    string
+	Compression KafkaCompression
+	// BatchSize is the number of messages per batch. Defaults to 100.
+	BatchSize int
+	// Linger is how long a partial batch waits for more messages. Defaults
+	// to 100ms.
+	Linger time.Duration
+	// QueueSize bounds the messages waiting to be batched. Defaults to 1000.
+	QueueSize int
+	// Timeout bounds each Produce call. Defaults to 10s.
+	Timeout time.Duration
+	// OnError is called with messages that could not be delivered, including
+	// those dropped because the queue was full (ErrQueueFull).
+	OnError func(messages []KafkaMessage, err error)
+}
+
+// KafkaOutput publishes formatted log entries to a Kafka topic in batches.
+// Writes are queued on an AsyncWorker and never block on the broker.
+//
+// Example:
+//
+//	output, err := logging.NewKafkaOutput(logging.KafkaConfig{
+//		Producer:    kafkaGoProducer{w: writer},
+//		Topic:       "logs",
+//		KeyField:    "trace_id",
+//		Compression: logging.KafkaCompressionZstd,
+//		OnError: func(msgs []logging.KafkaMessage, err error) {
+//			fmt.Fprintf(os.Stderr, "dropped %d log messages: %v\n", len(msgs), err)
+//		},
+//	})
+type KafkaOutput struct {
+	config  KafkaConfig
+	batcher *batcher[KafkaMessage]
+}
+
+// NewKafkaOutput creates a KafkaOutput.
+func NewKafkaOutput(config KafkaConfig) (*KafkaOutput, error) {
+	if config.Producer == nil {
+		return nil, fmt.Errorf("kafka output requires a producer")
+	}
+	if config.Topic == "" {
+		return nil, fmt.Errorf("kafka output requires a topic")
+	}
+	if config.Compression == "" {
+		config.Compression = KafkaCompressionNone
+	}
+	if config.Linger == 0 {
+		config.Linger = 100 * time.Millisecond
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	ko := &KafkaOutput{config: config}
+	ko.batcher = newBatcher(batcherConfig[KafkaMessage]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     ko.produce,
+		OnError:   config.OnError,
+	})
+	return ko, nil
+}
+
+func (ko *KafkaOutput) produce(messages []KafkaMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ko.config.Timeout)
+	defer cancel()
+
+	return ko.config.Producer.Produce(ctx, KafkaBatch{
+		Topic:       ko.config.Topic,
+		Compression: ko.config.Compression,
+		Messages:    messages,
+	})
+}
+
+// Write queues data as an unkeyed message.
+func (ko *KafkaOutput) Write(data []byte) error {
+	return ko.batcher.Submit(KafkaMessage{Value: kafkaValue(data), Time: time.Now()})
+}
+
+// WriteEntry queues data keyed by the configured key field of entry.
+func (ko *KafkaOutput) WriteEntry(entry LogEntry, data []byte) error {
+	msg := KafkaMessage{Value: kafkaValue(data), Time: entry.Timestamp}
+	if ko.config.KeyField != "" {
+		if key, ok := entryFieldString(entry, ko.config.KeyField); ok {
+			msg.Key = []byte(key)
+		}
+	}
+	return ko.batcher.Submit(msg)
+}
+
+// kafkaValue copies data without its trailing newline.
+func kafkaValue(data []byte) []byte {
+	return append([]byte(nil), bytes.TrimRight(data, "\r\n")...)
+}
+
+// Flush publishes the pending partial batch.
+func (ko *KafkaOutput) Flush() error {
+	return ko.batcher.Flush()
+}
+
+// Close publishes queued messages and closes the producer if it implements
+// io.Closer.
+func (ko *KafkaOutput) Close() error {
+	err := ko.batcher.Stop()
+	if closer, ok := ko.config.Producer.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeKafkaProducer struct {
+	mu      sync.Mutex
+	batches []KafkaBatch
+	err     error
+	closed  bool
+}
+
+func (p *fakeKafkaProducer) Produce(_ context.Context, batch KafkaBatch) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.batches = append(p.batches, batch)
+	return nil
+}
+
+func (p *fakeKafkaProducer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func (p *fakeKafkaProducer) snapshot() []KafkaBatch {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]KafkaBatch(nil), p.batches...)
+}
+
+func TestNewKafkaOutput_Validation(t *testing.T) {
+	if _, err := NewKafkaOutput(KafkaConfig{Topic: "logs"}); err == nil {
+		t.Error("expected error without producer")
+	}
+	if _, err := NewKafkaOutput(KafkaConfig{Producer: &fakeKafkaProducer{}}); err == nil {
+		t.Error("expected error without topic")
+	}
+}
+
+func TestKafkaOutput_BatchesAndKeys(t *testing.T) {
+	producer := &fakeKafkaProducer{}
+	output, err := NewKafkaOutput(KafkaConfig{
+		Producer:    producer,
+		Topic:       "logs",
+		KeyField:    "trace_id",
+		Compression: KafkaCompressionGzip,
+		BatchSize:   2,
+		Linger:      time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("failed to create kafka output: %v", err)
+	}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithCustomOutput(output).Build())
+	logger.WithField("trace_id", "field-trace").Info("first")
+	logger.InfoContext(WithTraceID(context.Background(), "ctx-trace"), "second")
+	logger.Info("third")
+
+	if err := output.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	batches := producer.snapshot()
+	if len(batches) != 2 || len(batches[0].Messages) != 2 || len(batches[1].Messages) != 1 {
+		t.Fatalf("expected batches of 2 and 1, got %+v", batches)
+	}
+	if batches[0].Topic != "logs" || batches[0].Compression != KafkaCompressionGzip {
+		t.Errorf("unexpected batch metadata: %+v", batches[0])
+	}
+
+	keys := []string{
+		string(batches[0].Messages[0].Key),
+		string(batches[0].Messages[1].Key),
+		string(batches[1].Messages[0].Key),
+	}
+	if keys[0] != "field-trace" || keys[1] != "ctx-trace" || keys[2] != "" {
+		t.Errorf("unexpected keys: %q", keys)
+	}
+	if entry, ok := ParseJSONEntry(batches[1].Messages[0].Value); !ok || entry.Message != "third" {
+		t.Errorf("expected JSON value without newline, got %q", batches[1].Messages[0].Value)
+	}
+	if !producer.closed {
+		t.Error("expected producer to be closed")
+	}
+}
+
+func TestKafkaOutput_Linger(t *testing.T) {
+	producer := &fakeKafkaProducer{}
+	output, err := NewKafkaOutput(KafkaConfig{Producer: producer, Topic: "logs", Linger: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("failed to create kafka output: %v", err)
+	}
+	defer output.Close()
+
+	_ = output.Write([]byte("lingering\n"))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(producer.snapshot()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if batches := producer.snapshot(); len(batches) != 1 || string(batches[0].Messages[0].Value) != "lingering" {
+		t.Errorf("expected partial batch after linger, got %+v", batches)
+	}
+}
+
+func TestKafkaOutput_OnError(t *testing.T) {
+	producer := &fakeKafkaProducer{err: errors.New("broker down")}
+
+	var mu sync.Mutex
+	var failed int
+	output, err := NewKafkaOutput(KafkaConfig{
+		Producer: producer,
+		Topic:    "logs",
+		OnError: func(messages []KafkaMessage, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed += len(messages)
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create kafka output: %v", err)
+	}
+
+	_ = output.Write([]byte("a"))
+	_ = output.Write([]byte("b"))
+	if err := output.Close(); err == nil {
+		t.Error("expected close to report the failed flush")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if failed != 2 {
+		t.Errorf("expected 2 failed messages, got %d", failed)
+	}
+}
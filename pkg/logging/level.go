@@ -1,6 +1,9 @@
 package logging
 
-import "strings"
+import (
+	"strings"
+	"sync/atomic"
+)
 
 // Level represents the severity level of a log message.
 // Lower numeric values indicate more verbose logging.
@@ -62,3 +65,25 @@ func ParseLevel(level string) (Level, bool) {
 	l, ok := nameLevels[strings.ToUpper(level)]
 	return l, ok
 }
+
+// levelVar is a Level that can be shared between a logger and the children
+// that inherit its threshold, and changed safely while they log.
+type levelVar struct {
+	v atomic.Int64
+}
+
+func newLevelVar(level Level) *levelVar {
+	lv := &levelVar{}
+	lv.Store(level)
+	return lv
+}
+
+// Load returns the current level.
+func (lv *levelVar) Load() Level {
+	return Level(lv.v.Load())
+}
+
+// Store sets the level.
+func (lv *levelVar) Store(level Level) {
+	lv.v.Store(int64(level))
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+)
+
+// LevelWriter sends entries at or above Level to Writer instead of the
+// default writer. See LoggerConfigBuilder.WriterForLevel.
+type LevelWriter struct {
+	Level  Level
+	Writer io.Writer
+}
+
+// WriterForLevel sends entries at or above level to w instead of the
+// default writer. See LoggerConfigBuilder.WriterForLevel.
+func (b *OutputConfigBuilder) WriterForLevel(level Level, w io.Writer) *OutputConfigBuilder {
+	b.config.LevelWriters = append(b.config.LevelWriters, LevelWriter{Level: level, Writer: w})
+	return b
+}
+
+// WriterForLevel sends entries at or above level to w instead of the
+// default writer. When several levels are configured, an entry goes to the
+// writer with the highest level it meets.
+//
+// Example:
+//
+//	// Errors to stderr, everything else to stdout
+//	config := logging.NewLoggerConfig().
+//		WithWriter(os.Stdout).
+//		WriterForLevel(logging.ErrorLevel, os.Stderr).
+//		Build()
+func (b *LoggerConfigBuilder) WriterForLevel(level Level, w io.Writer) *LoggerConfigBuilder {
+	b.config.Output.LevelWriters = append(b.config.Output.LevelWriters, LevelWriter{Level: level, Writer: w})
+	return b
+}
+
+// sortedLevelWriters returns writers ordered from the highest level down,
+// so the first one an entry meets is the one it goes to.
+func sortedLevelWriters(writers []LevelWriter) []LevelWriter {
+	sorted := append([]LevelWriter(nil), writers...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Level > sorted[j].Level })
+	return sorted
+}
+
+// levelWriterOutput writes each entry to the writer for its level.
+type levelWriterOutput struct {
+	fallback Output
+	levels   []Level
+	outputs  []Output
+}
+
+func newLevelWriterOutput(fallback io.Writer, writers []LevelWriter) *levelWriterOutput {
+	lo := &levelWriterOutput{fallback: NewWriterOutput(fallback)}
+	for _, lw := range sortedLevelWriters(writers) {
+		lo.levels = append(lo.levels, lw.Level)
+		lo.outputs = append(lo.outputs, NewWriterOutput(lw.Writer))
+	}
+	return lo
+}
+
+func (lo *levelWriterOutput) outputFor(level Level) Output {
+	for i, threshold := range lo.levels {
+		if level >= threshold {
+			return lo.outputs[i]
+		}
+	}
+	return lo.fallback
+}
+
+// Write writes data, which carries no level, to the default writer.
+func (lo *levelWriterOutput) Write(data []byte) error {
+	return lo.fallback.Write(data)
+}
+
+// WriteEntry writes data to the writer for the entry's level.
+func (lo *levelWriterOutput) WriteEntry(entry LogEntry, data []byte) error {
+	return lo.outputFor(entry.Level).Write(data)
+}
+
+// ExplainEntry reports which writer receives entry.
+func (lo *levelWriterOutput) ExplainEntry(entry LogEntry) []ExplainStep {
+	for _, threshold := range lo.levels {
+		if entry.Level >= threshold {
+			return []ExplainStep{{Stage: "level writers", Outcome: ExplainPass, Detail: fmt.Sprintf("to the writer for %s and above", threshold)}}
+		}
+	}
+	return []ExplainStep{{Stage: "level writers", Outcome: ExplainPass, Detail: "to the default writer"}}
+}
+
+// Close closes the default writer and each level's writer.
+func (lo *levelWriterOutput) Close() error {
+	err := lo.fallback.Close()
+	for _, output := range lo.outputs {
+		if closeErr := output.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// levelWriterHandler is the slog backend's equivalent of levelWriterOutput:
+// it hands each record to the handler for its level.
+type levelWriterHandler struct {
+	fallback slog.Handler
+	levels   []slog.Level
+	handlers []slog.Handler
+}
+
+func (h *levelWriterHandler) handlerFor(level slog.Level) slog.Handler {
+	for i, threshold := range h.levels {
+		if level >= threshold {
+			return h.handlers[i]
+		}
+	}
+	return h.fallback
+}
+
+func (h *levelWriterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handlerFor(level).Enabled(ctx, level)
+}
+
+func (h *levelWriterHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handlerFor(record.Level).Handle(ctx, record)
+}
+
+func (h *levelWriterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) })
+}
+
+func (h *levelWriterHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) })
+}
+
+func (h *levelWriterHandler) with(apply func(slog.Handler) slog.Handler) slog.Handler {
+	c := &levelWriterHandler{fallback: apply(h.fallback), levels: h.levels}
+	for _, handler := range h.handlers {
+		c.handlers = append(c.handlers, apply(handler))
+	}
+	return c
+}
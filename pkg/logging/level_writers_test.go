@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriterForLevel(t *testing.T) {
+	stdout, stderr, critical := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(DebugLevel).
+		WithTextFormat().
+		WithWriter(stdout).
+		WriterForLevel(CriticalLevel, critical).
+		WriterForLevel(ErrorLevel, stderr).
+		Build())
+
+	logger.Debug("debug message")
+	logger.Warn("warn message")
+	logger.Error("error message")
+	logger.Critical("critical message")
+
+	if !strings.Contains(stdout.String(), "debug message") || !strings.Contains(stdout.String(), "warn message") {
+		t.Errorf("expected DEBUG and WARN on the default writer, got: %s", stdout.String())
+	}
+	if strings.Contains(stdout.String(), "error message") {
+		t.Errorf("expected ERROR not to reach the default writer, got: %s", stdout.String())
+	}
+	if stderr.String() == "" || !strings.Contains(stderr.String(), "error message") || strings.Contains(stderr.String(), "critical message") {
+		t.Errorf("expected only ERROR on the error writer, got: %s", stderr.String())
+	}
+	if !strings.Contains(critical.String(), "critical message") {
+		t.Errorf("expected CRITICAL on its own writer, got: %s", critical.String())
+	}
+}
+
+func TestWriterForLevel_Slog(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	config := NewLoggerConfig().
+		WithJSONFormat().
+		WithWriter(stdout).
+		WriterForLevel(ErrorLevel, stderr).
+		Build()
+	config.UseSlog = true
+	logger := NewWithLoggerConfig(config).WithField("service", "api")
+
+	logger.Info("info message")
+	logger.Error("error message")
+
+	if !strings.Contains(stdout.String(), "info message") || strings.Contains(stdout.String(), "error message") {
+		t.Errorf("unexpected default writer output: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "error message") || !strings.Contains(stderr.String(), `"service":"api"`) {
+		t.Errorf("expected ERROR with fields on the error writer, got: %s", stderr.String())
+	}
+}
+
+func TestWriterForLevel_Explain(t *testing.T) {
+	output := newLevelWriterOutput(&bytes.Buffer{}, []LevelWriter{{Level: ErrorLevel, Writer: &bytes.Buffer{}}})
+
+	if steps := ExplainOutput(output, LogEntry{Level: InfoLevel}); steps[0].Detail != "to the default writer" {
+		t.Errorf("unexpected explanation for INFO: %+v", steps)
+	}
+	if steps := ExplainOutput(output, LogEntry{Level: ErrorLevel}); !strings.Contains(steps[0].Detail, "ERROR") {
+		t.Errorf("unexpected explanation for ERROR: %+v", steps)
+	}
+}
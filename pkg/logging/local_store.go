@@ -0,0 +1,250 @@
+package logging
+
+import (
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	localStorePrefix = "segment-"
+	localStoreSuffix = ".jsonl"
+)
+
+// LocalStoreConfig configures a LocalStore.
+type LocalStoreConfig struct {
+	// MaxSize bounds the total size of the store in bytes. The oldest
+	// segments are deleted when it is exceeded. Defaults to 50 MiB.
+	MaxSize int64
+	// SegmentSize is the size at which a new segment is started, and so the
+	// granularity of pruning. Defaults to MaxSize / 10.
+	SegmentSize int64
+}
+
+// LocalStore keeps a searchable, size-bounded history of log entries in a
+// directory, for desktop and CLI applications that show recent logs in their
+// own UI. Entries are stored as indexed JSONL segments (see JSONLWriter) and
+// need no database. LocalStore implements Output and EntryOutput; entries are
+// always stored as JSON regardless of the logger's formatter.
+//
+// Example:
+//
+//	store, err := logging.NewLocalStore(filepath.Join(configDir, "logs"), logging.LocalStoreConfig{
+//		MaxSize: 20 << 20,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer store.Close()
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().WithCustomOutput(store).Build())
+//
+//	// Later, in the UI:
+//	recent, err := store.Tail(100, logging.Filter{Level: logging.WarnLevel})
+type LocalStore struct {
+	dir       string
+	config    LocalStoreConfig
+	formatter Formatter
+	segment   *JSONLWriter
+	size      int64
+	mu        sync.Mutex
+}
+
+// NewLocalStore opens or creates a store in dir.
+func NewLocalStore(dir string, config LocalStoreConfig) (*LocalStore, error) {
+	if config.MaxSize <= 0 {
+		config.MaxSize = 50 << 20
+	}
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = config.MaxSize / 10
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	ls := &LocalStore{
+		dir:       dir,
+		config:    config,
+		formatter: NewJSONFormatter(NewFormatterConfig().WithJSONFormat().IncludeTime(true).IncludeFile(true).Build()),
+	}
+
+	segments, err := ls.segments()
+	if err != nil {
+		return nil, err
+	}
+	if n := len(segments); n > 0 {
+		if info, statErr := os.Stat(segments[n-1]); statErr == nil && info.Size() < config.SegmentSize {
+			ls.segment, err = NewJSONLWriter(segments[n-1], 0)
+			ls.size = info.Size()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ls.segment == nil {
+		if err := ls.rotate(); err != nil {
+			return nil, err
+		}
+	}
+	return ls, nil
+}
+
+// segments lists segment files oldest first.
+func (ls *LocalStore) segments() ([]string, error) {
+	entries, err := os.ReadDir(ls.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store directory: %w", err)
+	}
+
+	var segments []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && strings.HasPrefix(name, localStorePrefix) && strings.HasSuffix(name, localStoreSuffix) {
+			segments = append(segments, filepath.Join(ls.dir, name))
+		}
+	}
+	sort.Strings(segments)
+	return segments, nil
+}
+
+// rotate starts a new segment and prunes old ones. It must be called with
+// mu held.
+func (ls *LocalStore) rotate() error {
+	if ls.segment != nil {
+		if err := ls.segment.Close(); err != nil {
+			return err
+		}
+	}
+
+	// Names sort chronologically; bump the timestamp on coarse clocks.
+	var path string
+	for ns := time.Now().UnixNano(); ; ns++ {
+		path = filepath.Join(ls.dir, fmt.Sprintf("%s%020d%s", localStorePrefix, ns, localStoreSuffix))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+	}
+	segment, err := NewJSONLWriter(path, 0)
+	if err != nil {
+		return err
+	}
+	ls.segment = segment
+	ls.size = 0
+
+	return ls.prune()
+}
+
+// prune deletes the oldest segments until the store fits in MaxSize. The
+// current segment is never deleted.
+func (ls *LocalStore) prune() error {
+	segments, err := ls.segments()
+	if err != nil {
+		return err
+	}
+
+	sizes := make([]int64, len(segments))
+	var total int64
+	for i, segment := range segments {
+		for _, file := range []string{segment, segment + jsonlIndexSuffix} {
+			if info, err := os.Stat(file); err == nil {
+				sizes[i] += info.Size()
+			}
+		}
+		total += sizes[i]
+	}
+
+	for i := 0; i < len(segments)-1 && total > ls.config.MaxSize; i++ {
+		if err := os.Remove(segments[i]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to prune log segment: %w", err)
+		}
+		_ = os.Remove(segments[i] + jsonlIndexSuffix)
+		total -= sizes[i]
+	}
+	return nil
+}
+
+// Write stores newline-delimited JSON entries.
+func (ls *LocalStore) Write(data []byte) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.segment == nil {
+		return fmt.Errorf("local store is closed")
+	}
+	if err := ls.segment.Write(data); err != nil {
+		return err
+	}
+
+	ls.size += int64(len(data))
+	if ls.size >= ls.config.SegmentSize {
+		return ls.rotate()
+	}
+	return nil
+}
+
+// WriteEntry stores entry as JSON.
+func (ls *LocalStore) WriteEntry(entry LogEntry, _ []byte) error {
+	data, err := ls.formatter.Format(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+	return ls.Write(data)
+}
+
+// Query returns an iterator over stored entries matching filter, oldest
+// first. Segments pruned while iterating are reported as errors.
+func (ls *LocalStore) Query(filter Filter) iter.Seq2[LogEntry, error] {
+	return func(yield func(LogEntry, error) bool) {
+		segments, err := ls.segments()
+		if err != nil {
+			yield(LogEntry{}, err)
+			return
+		}
+		for _, segment := range segments {
+			if !queryFile(segment, filter, JSONLConfig{}, yield) {
+				return
+			}
+		}
+	}
+}
+
+// Tail returns up to n of the most recent entries matching filter, oldest
+// first.
+func (ls *LocalStore) Tail(n int, filter Filter) ([]LogEntry, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	ring := make([]LogEntry, 0, n)
+	start := 0
+	for entry, err := range ls.Query(filter) {
+		if err != nil {
+			return nil, err
+		}
+		if len(ring) < n {
+			ring = append(ring, entry)
+			continue
+		}
+		ring[start] = entry
+		start = (start + 1) % n
+	}
+	return append(ring[start:], ring[:start]...), nil
+}
+
+// Close closes the current segment.
+func (ls *LocalStore) Close() error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.segment == nil {
+		return nil
+	}
+	err := ls.segment.Close()
+	ls.segment = nil
+	return err
+}
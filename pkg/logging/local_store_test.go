@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestLocalStore_QueryAndTail(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), LocalStoreConfig{})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	defer store.Close()
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithTextFormat().WithCustomOutput(store).Build())
+	for i := 0; i < 5; i++ {
+		logger.WithField("n", i).Info("info %d", i)
+	}
+	logger.Error("failure")
+
+	var errorsFound int
+	for entry, err := range store.Query(Filter{Level: ErrorLevel}) {
+		if err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if entry.Message != "failure" {
+			t.Errorf("unexpected entry %q", entry.Message)
+		}
+		errorsFound++
+	}
+	if errorsFound != 1 {
+		t.Errorf("expected 1 error entry, got %d", errorsFound)
+	}
+
+	tail, err := store.Tail(3, Filter{})
+	if err != nil {
+		t.Fatalf("tail failed: %v", err)
+	}
+	var got []string
+	for _, entry := range tail {
+		got = append(got, entry.Message)
+	}
+	if fmt.Sprint(got) != "[info 3 info 4 failure]" {
+		t.Errorf("unexpected tail: %v", got)
+	}
+}
+
+func TestLocalStore_Prunes(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir, LocalStoreConfig{MaxSize: 4096, SegmentSize: 1024})
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(store).Build())
+	for i := 0; i < 200; i++ {
+		logger.Info("entry %03d with some padding to fill segments", i)
+	}
+	_ = store.Close()
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	var total int64
+	for _, f := range files {
+		info, _ := f.Info()
+		total += info.Size()
+	}
+	// The current segment may grow past SegmentSize by one entry.
+	if total > 4096+1024 {
+		t.Errorf("expected store to be pruned near 4096 bytes, got %d", total)
+	}
+
+	reopened, err := NewLocalStore(dir, LocalStoreConfig{MaxSize: 4096, SegmentSize: 1024})
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer reopened.Close()
+
+	tail, err := reopened.Tail(1, Filter{})
+	if err != nil {
+		t.Fatalf("tail failed: %v", err)
+	}
+	if len(tail) != 1 || tail[0].Message != "entry 199 with some padding to fill segments" {
+		t.Errorf("expected newest entry to survive pruning, got %v", tail)
+	}
+	if first, _ := reopened.Tail(1000, Filter{}); len(first) == 0 || first[0].Message == "entry 000 with some padding to fill segments" {
+		t.Errorf("expected oldest entries to be pruned")
+	}
+}
@@ -22,6 +22,10 @@ type Logger interface {
 	WithField(key string, value interface{}) Logger
 	WithFields(fields map[string]interface{}) Logger
 
+	// WithLevel returns a child with its own minimum level, independent of
+	// later SetLevel calls on the parent
+	WithLevel(level Level) Logger
+
 	// Level checking
 	IsLevelEnabled(level Level) bool
 
@@ -60,6 +64,41 @@ type ConfigurableLogger interface {
 	GetLevel() Level
 }
 
+// InfoLogger is the minimal interface for code that only reports progress.
+// Libraries should accept the narrowest interface they need so callers can
+// pass any Logger and tests can supply small fakes.
+//
+// Example:
+//
+//	func NewCache(log logging.InfoLogger) *Cache { ... }
+type InfoLogger interface {
+	Info(msg string, args ...interface{})
+}
+
+// ErrorLogger is the minimal interface for code that only reports failures.
+type ErrorLogger interface {
+	Error(msg string, args ...interface{})
+}
+
+// ContextAwareLogger is the subset of Logger that logs with a context.Context,
+// so trace, request and correlation IDs are carried into each entry.
+type ContextAwareLogger interface {
+	LogContext(ctx context.Context, level Level, msg string, args ...interface{})
+	TraceContext(ctx context.Context, msg string, args ...interface{})
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+	CriticalContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// Every Logger satisfies the narrow interfaces.
+var (
+	_ InfoLogger         = Logger(nil)
+	_ ErrorLogger        = Logger(nil)
+	_ ContextAwareLogger = Logger(nil)
+)
+
 // LogEntry represents a structured log entry with all its metadata.
 type LogEntry struct {
 	Timestamp time.Time
@@ -69,6 +108,10 @@ type LogEntry struct {
 	Context   context.Context
 	File      string
 	Line      int
+
+	// fieldOrder lists the keys of Fields in the order they were added,
+	// when the formatter writes them in InsertionFieldOrder.
+	fieldOrder []string
 }
 
 // Formatter defines how log entries are converted to output format.
@@ -86,6 +129,16 @@ type Output interface {
 	Close() error
 }
 
+// EntryOutput is implemented by outputs that need the structured entry as
+// well as its formatted bytes, for example to map the level onto a protocol
+// field. Loggers call WriteEntry instead of Write when it is available.
+type EntryOutput interface {
+	Output
+
+	// WriteEntry outputs data, the formatted form of entry.
+	WriteEntry(entry LogEntry, data []byte) error
+}
+
 // BufferedOutputInterface extends Output with buffering capabilities.
 type BufferedOutputInterface interface {
 	Output
@@ -0,0 +1,7 @@
+// Package loggingmock provides test doubles for the logging package.
+//
+// It contains gomock mocks for Logger, Formatter, Output and the other
+// interfaces in logger.go, a mock HandlerMiddleware for hooking into slog
+// records, and Spy, a recording logger with assertion helpers. The mocks are
+// regenerated with `task generate-mocks`.
+package loggingmock
@@ -0,0 +1,300 @@
+package loggingmock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ocrosby/go-logging/pkg/logging"
+)
+
+// Entry is a log entry captured by a Spy. Fields include trace, request and
+// correlation IDs found in Context.
+type Entry struct {
+	Level   logging.Level
+	Message string
+	Fields  map[string]interface{}
+	Context context.Context
+}
+
+// spyRecorder holds the entries shared by a Spy and its children.
+type spyRecorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Spy is a logging.Logger that records every entry instead of writing it.
+// Loggers derived with WithField or WithFields record into the same Spy, so
+// assertions see entries from the whole tree. The assertion helpers accept
+// testing.TB and report failures with Errorf, which also works with testify's
+// assert.TestingT wrappers.
+//
+// Example:
+//
+//	spy := loggingmock.NewSpy()
+//	service := NewService(spy)
+//	service.Run()
+//	spy.AssertLogged(t, logging.ErrorLevel, "connection refused")
+type Spy struct {
+	recorder *spyRecorder
+	level    *logging.Level
+	levelMu  *sync.RWMutex
+	fields   map[string]interface{}
+}
+
+var _ logging.ConfigurableLogger = (*Spy)(nil)
+
+// NewSpy creates a Spy that records entries at every level.
+func NewSpy() *Spy {
+	level := logging.TraceLevel
+	return &Spy{
+		recorder: &spyRecorder{},
+		level:    &level,
+		levelMu:  &sync.RWMutex{},
+		fields:   map[string]interface{}{},
+	}
+}
+
+// Entries returns a copy of the recorded entries in the order they were logged.
+func (s *Spy) Entries() []Entry {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	return append([]Entry(nil), s.recorder.entries...)
+}
+
+// EntriesAt returns the recorded entries at level.
+func (s *Spy) EntriesAt(level logging.Level) []Entry {
+	var matched []Entry
+	for _, e := range s.Entries() {
+		if e.Level == level {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Reset discards all recorded entries.
+func (s *Spy) Reset() {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.recorder.entries = nil
+}
+
+// Find returns the first entry at level whose message contains substr.
+func (s *Spy) Find(level logging.Level, substr string) (Entry, bool) {
+	for _, e := range s.EntriesAt(level) {
+		if strings.Contains(e.Message, substr) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// AssertLogged fails t unless an entry at level contains substr.
+func (s *Spy) AssertLogged(t testing.TB, level logging.Level, substr string) {
+	t.Helper()
+	if _, ok := s.Find(level, substr); !ok {
+		t.Errorf("expected %s entry containing %q, got: %s", level, substr, s.describe())
+	}
+}
+
+// AssertNotLogged fails t if an entry at level contains substr.
+func (s *Spy) AssertNotLogged(t testing.TB, level logging.Level, substr string) {
+	t.Helper()
+	if e, ok := s.Find(level, substr); ok {
+		t.Errorf("unexpected %s entry: %q", level, e.Message)
+	}
+}
+
+// AssertCount fails t unless exactly n entries were logged at level.
+func (s *Spy) AssertCount(t testing.TB, level logging.Level, n int) {
+	t.Helper()
+	if got := len(s.EntriesAt(level)); got != n {
+		t.Errorf("expected %d %s entries, got %d: %s", n, level, got, s.describe())
+	}
+}
+
+// AssertField fails t unless an entry at level containing substr has the
+// field key equal to value. Values are compared by their string form.
+func (s *Spy) AssertField(t testing.TB, level logging.Level, substr, key string, value interface{}) {
+	t.Helper()
+	e, ok := s.Find(level, substr)
+	if !ok {
+		t.Errorf("expected %s entry containing %q, got: %s", level, substr, s.describe())
+		return
+	}
+	got, ok := e.Fields[key]
+	if !ok || fmt.Sprint(got) != fmt.Sprint(value) {
+		t.Errorf("expected field %s=%v on %q, got fields %v", key, value, e.Message, e.Fields)
+	}
+}
+
+// describe renders the recorded entries for failure messages.
+func (s *Spy) describe() string {
+	entries := s.Entries()
+	if len(entries) == 0 {
+		return "no entries"
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = fmt.Sprintf("[%s] %s %v", e.Level, e.Message, e.Fields)
+	}
+	return strings.Join(lines, "; ")
+}
+
+func (s *Spy) record(ctx context.Context, level logging.Level, msg string, args ...interface{}) {
+	if !s.IsLevelEnabled(level) {
+		return
+	}
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+
+	fields := make(map[string]interface{}, len(s.fields))
+	for k, v := range s.fields {
+		fields[k] = v
+	}
+	addContextFields(ctx, fields)
+
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.recorder.entries = append(s.recorder.entries, Entry{
+		Level:   level,
+		Message: msg,
+		Fields:  fields,
+		Context: ctx,
+	})
+}
+
+// addContextFields copies trace, request and correlation IDs from ctx into
+// fields, as the loggers in the logging package do.
+func addContextFields(ctx context.Context, fields map[string]interface{}) {
+	if id, ok := logging.GetTraceID(ctx); ok {
+		fields["trace_id"] = id
+	}
+	if id, ok := logging.GetRequestID(ctx); ok {
+		fields["request_id"] = id
+	}
+	if id, ok := logging.GetCorrelationID(ctx); ok {
+		fields["correlation_id"] = id
+	}
+}
+
+// Log records an entry at level.
+func (s *Spy) Log(level logging.Level, msg string, args ...interface{}) {
+	s.record(context.Background(), level, msg, args...)
+}
+
+// LogContext records an entry at level with ctx.
+func (s *Spy) LogContext(ctx context.Context, level logging.Level, msg string, args ...interface{}) {
+	s.record(ctx, level, msg, args...)
+}
+
+// WithField returns a child Spy with an additional field.
+func (s *Spy) WithField(key string, value interface{}) logging.Logger {
+	return s.WithFields(map[string]interface{}{key: value})
+}
+
+// WithFields returns a child Spy with additional fields.
+func (s *Spy) WithFields(fields map[string]interface{}) logging.Logger {
+	merged := make(map[string]interface{}, len(s.fields)+len(fields))
+	for k, v := range s.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Spy{recorder: s.recorder, level: s.level, levelMu: s.levelMu, fields: merged}
+}
+
+// WithLevel returns a child Spy with its own minimum level. It records into
+// the same Spy but ignores later SetLevel calls on the parent.
+func (s *Spy) WithLevel(level logging.Level) logging.Logger {
+	return &Spy{recorder: s.recorder, level: &level, levelMu: &sync.RWMutex{}, fields: s.fields}
+}
+
+// IsLevelEnabled reports whether entries at level are recorded.
+func (s *Spy) IsLevelEnabled(level logging.Level) bool {
+	return level >= s.GetLevel()
+}
+
+// Trace records a TRACE entry.
+func (s *Spy) Trace(msg string, args ...interface{}) {
+	s.record(context.Background(), logging.TraceLevel, msg, args...)
+}
+
+// Debug records a DEBUG entry.
+func (s *Spy) Debug(msg string, args ...interface{}) {
+	s.record(context.Background(), logging.DebugLevel, msg, args...)
+}
+
+// Info records an INFO entry.
+func (s *Spy) Info(msg string, args ...interface{}) {
+	s.record(context.Background(), logging.InfoLevel, msg, args...)
+}
+
+// Warn records a WARN entry.
+func (s *Spy) Warn(msg string, args ...interface{}) {
+	s.record(context.Background(), logging.WarnLevel, msg, args...)
+}
+
+// Error records an ERROR entry.
+func (s *Spy) Error(msg string, args ...interface{}) {
+	s.record(context.Background(), logging.ErrorLevel, msg, args...)
+}
+
+// Critical records a CRITICAL entry.
+func (s *Spy) Critical(msg string, args ...interface{}) {
+	s.record(context.Background(), logging.CriticalLevel, msg, args...)
+}
+
+// TraceContext records a TRACE entry with ctx.
+func (s *Spy) TraceContext(ctx context.Context, msg string, args ...interface{}) {
+	s.record(ctx, logging.TraceLevel, msg, args...)
+}
+
+// DebugContext records a DEBUG entry with ctx.
+func (s *Spy) DebugContext(ctx context.Context, msg string, args ...interface{}) {
+	s.record(ctx, logging.DebugLevel, msg, args...)
+}
+
+// InfoContext records an INFO entry with ctx.
+func (s *Spy) InfoContext(ctx context.Context, msg string, args ...interface{}) {
+	s.record(ctx, logging.InfoLevel, msg, args...)
+}
+
+// WarnContext records a WARN entry with ctx.
+func (s *Spy) WarnContext(ctx context.Context, msg string, args ...interface{}) {
+	s.record(ctx, logging.WarnLevel, msg, args...)
+}
+
+// ErrorContext records an ERROR entry with ctx.
+func (s *Spy) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
+	s.record(ctx, logging.ErrorLevel, msg, args...)
+}
+
+// CriticalContext records a CRITICAL entry with ctx.
+func (s *Spy) CriticalContext(ctx context.Context, msg string, args ...interface{}) {
+	s.record(ctx, logging.CriticalLevel, msg, args...)
+}
+
+// Fluent returns a fluent interface that records through the Spy.
+func (s *Spy) Fluent() logging.FluentLogger {
+	return logging.NewFluentLogger(s)
+}
+
+// SetLevel changes the minimum recorded level for the Spy and its children.
+func (s *Spy) SetLevel(level logging.Level) {
+	s.levelMu.Lock()
+	defer s.levelMu.Unlock()
+	*s.level = level
+}
+
+// GetLevel returns the minimum recorded level.
+func (s *Spy) GetLevel() logging.Level {
+	s.levelMu.RLock()
+	defer s.levelMu.RUnlock()
+	return *s.level
+}
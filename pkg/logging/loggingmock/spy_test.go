@@ -0,0 +1,94 @@
+package loggingmock_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/ocrosby/go-logging/pkg/logging"
+	"github.com/ocrosby/go-logging/pkg/logging/loggingmock"
+)
+
+// failRecorder captures assertion failures so the helpers can be tested.
+type failRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (f *failRecorder) Helper() {}
+
+func (f *failRecorder) Errorf(format string, args ...interface{}) {
+	f.failures = append(f.failures, fmt.Sprintf(format, args...))
+}
+
+func TestSpy_RecordsEntriesAndFields(t *testing.T) {
+	spy := loggingmock.NewSpy()
+
+	child := spy.WithField("user_id", 42)
+	child.Info("user %s logged in", "alice")
+	spy.ErrorContext(context.Background(), "connection refused")
+	spy.Fluent().Warn().Str("op", "retry").Msg("retrying")
+
+	spy.AssertLogged(t, logging.InfoLevel, "alice logged in")
+	spy.AssertField(t, logging.InfoLevel, "logged in", "user_id", 42)
+	spy.AssertField(t, logging.WarnLevel, "retrying", "op", "retry")
+	spy.AssertCount(t, logging.ErrorLevel, 1)
+	spy.AssertNotLogged(t, logging.DebugLevel, "alice")
+
+	if len(spy.Entries()) != 3 {
+		t.Errorf("expected 3 entries, got %d", len(spy.Entries()))
+	}
+
+	spy.Reset()
+	spy.AssertCount(t, logging.InfoLevel, 0)
+}
+
+func TestSpy_LevelFiltering(t *testing.T) {
+	spy := loggingmock.NewSpy()
+	child := spy.WithField("k", "v")
+	spy.SetLevel(logging.WarnLevel)
+
+	child.Info("dropped")
+	child.Warn("kept")
+
+	spy.AssertCount(t, logging.InfoLevel, 0)
+	spy.AssertCount(t, logging.WarnLevel, 1)
+}
+
+func TestSpy_AssertionsReportFailures(t *testing.T) {
+	spy := loggingmock.NewSpy()
+	spy.Info("hello")
+
+	rec := &failRecorder{TB: t}
+	spy.AssertLogged(rec, logging.ErrorLevel, "hello")
+	spy.AssertNotLogged(rec, logging.InfoLevel, "hello")
+	spy.AssertCount(rec, logging.InfoLevel, 2)
+	spy.AssertField(rec, logging.InfoLevel, "hello", "missing", 1)
+
+	if len(rec.failures) != 4 {
+		t.Errorf("expected 4 failures, got %d: %v", len(rec.failures), rec.failures)
+	}
+}
+
+func TestMockOutputAndFormatter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	formatter := loggingmock.NewMockFormatter(ctrl)
+	output := loggingmock.NewMockOutput(ctrl)
+
+	formatter.EXPECT().
+		Format(gomock.Any()).
+		DoAndReturn(func(entry logging.LogEntry) ([]byte, error) {
+			return []byte(entry.Message), nil
+		})
+	output.EXPECT().Write([]byte("formatted")).Return(nil)
+
+	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+		WithCustomFormatter(formatter).
+		WithCustomOutput(output).
+		Build())
+
+	logger.Info("formatted")
+}
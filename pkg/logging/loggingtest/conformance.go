@@ -0,0 +1,262 @@
+// Package loggingtest provides a conformance suite for implementations of
+// logging.Logger, and helpers for testing log output.
+//
+// Teams that implement Logger over a custom backend run the suite from their
+// own tests to verify it behaves like the loggers in this module:
+//
+//	func TestMyLogger(t *testing.T) {
+//		loggingtest.RunLoggerConformance(t, func(t *testing.T) loggingtest.Subject {
+//			buf := &bytes.Buffer{}
+//			return loggingtest.Subject{
+//				Logger:  mylogger.New(buf),
+//				Entries: loggingtest.JSONEntries(buf),
+//			}
+//		})
+//	}
+package loggingtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ocrosby/go-logging/pkg/logging"
+)
+
+// Subject is a Logger under test together with a way to observe its output.
+type Subject struct {
+	// Logger is a fresh logger with no static fields, enabled at every level.
+	Logger logging.Logger
+
+	// Entries returns the entries written so far, in order. Implementations
+	// must report fields and context IDs (trace_id, request_id,
+	// correlation_id) in LogEntry.Fields.
+	Entries func() []logging.LogEntry
+}
+
+// Factory creates a new Subject for each conformance check.
+type Factory func(t *testing.T) Subject
+
+// RunLoggerConformance runs the conformance checks as subtests of t. Each
+// check obtains a fresh Subject from factory.
+func RunLoggerConformance(t *testing.T, factory Factory) {
+	t.Helper()
+
+	checks := []struct {
+		name string
+		fn   func(t *testing.T, s Subject)
+	}{
+		{"LevelFiltering", checkLevelFiltering},
+		{"MessageFormatting", checkMessageFormatting},
+		{"WithFieldsImmutability", checkWithFieldsImmutability},
+		{"LevelOverride", checkLevelOverride},
+		{"ContextExtraction", checkContextExtraction},
+		{"Fluent", checkFluent},
+		{"Concurrency", checkConcurrency},
+	}
+
+	for _, c := range checks {
+		t.Run(c.name, func(t *testing.T) {
+			c.fn(t, factory(t))
+		})
+	}
+}
+
+// JSONEntries returns an Entries function that parses newline-delimited JSON
+// written to buf, using the key names of the JSON formatter.
+func JSONEntries(buf *bytes.Buffer) func() []logging.LogEntry {
+	return func() []logging.LogEntry {
+		var entries []logging.LogEntry
+		for _, line := range bytes.Split(buf.Bytes(), []byte{'\n'}) {
+			if entry, ok := logging.ParseJSONEntry(line); ok {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	}
+}
+
+func checkLevelFiltering(t *testing.T, s Subject) {
+	s.Logger.SetLevel(logging.WarnLevel)
+
+	if got := s.Logger.GetLevel(); got != logging.WarnLevel {
+		t.Errorf("GetLevel() = %s after SetLevel(WARN)", got)
+	}
+	if s.Logger.IsLevelEnabled(logging.InfoLevel) {
+		t.Error("IsLevelEnabled(INFO) = true with level WARN")
+	}
+	if !s.Logger.IsLevelEnabled(logging.ErrorLevel) {
+		t.Error("IsLevelEnabled(ERROR) = false with level WARN")
+	}
+
+	s.Logger.Debug("debug entry")
+	s.Logger.Info("info entry")
+	s.Logger.Warn("warn entry")
+	s.Logger.Log(logging.ErrorLevel, "error entry")
+	s.Logger.InfoContext(context.Background(), "info context entry")
+
+	entries := s.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries at WARN and above, got %d: %v", len(entries), messages(entries))
+	}
+	if entries[0].Level != logging.WarnLevel || entries[1].Level != logging.ErrorLevel {
+		t.Errorf("unexpected levels: %s, %s", entries[0].Level, entries[1].Level)
+	}
+}
+
+func checkMessageFormatting(t *testing.T, s Subject) {
+	s.Logger.Info("hello %s #%d", "world", 1)
+
+	entries := s.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Message != "hello world #1" {
+		t.Errorf("expected formatted message, got %q", entries[0].Message)
+	}
+}
+
+func checkWithFieldsImmutability(t *testing.T, s Subject) {
+	parent := s.Logger
+	child := parent.WithField("a", 1)
+	grandchild := child.WithFields(map[string]interface{}{"b": 2})
+	sibling := parent.WithField("a", 3)
+
+	parent.Info("parent")
+	child.Info("child")
+	grandchild.Info("grandchild")
+	sibling.Info("sibling")
+
+	entries := byMessage(s.Entries())
+
+	assertFields(t, entries["parent"], map[string]interface{}{}, "a", "b")
+	assertFields(t, entries["child"], map[string]interface{}{"a": 1}, "b")
+	assertFields(t, entries["grandchild"], map[string]interface{}{"a": 1, "b": 2})
+	assertFields(t, entries["sibling"], map[string]interface{}{"a": 3}, "b")
+}
+
+func checkLevelOverride(t *testing.T, s Subject) {
+	inherited := s.Logger.WithField("k", "v")
+	pinned := s.Logger.WithLevel(logging.WarnLevel)
+	pinnedChild := pinned.WithField("k", "v")
+
+	s.Logger.SetLevel(logging.DebugLevel)
+
+	inherited.Debug("inherited")
+	pinned.Info("pinned info")
+	pinnedChild.Info("pinned child info")
+	pinnedChild.Warn("pinned child warn")
+
+	entries := byMessage(s.Entries())
+	if entries["inherited"] == nil {
+		t.Error("expected child without override to follow the parent's SetLevel")
+	}
+	if entries["pinned info"] != nil || entries["pinned child info"] != nil {
+		t.Error("expected WithLevel override to suppress INFO")
+	}
+	if entries["pinned child warn"] == nil {
+		t.Error("expected descendants of an override to log at its level")
+	}
+	if got := pinned.GetLevel(); got != logging.WarnLevel {
+		t.Errorf("GetLevel() on override = %s, want WARN", got)
+	}
+}
+
+func checkContextExtraction(t *testing.T, s Subject) {
+	ctx := logging.WithTraceID(context.Background(), "trace-123")
+	ctx = logging.WithRequestID(ctx, "req-456")
+	ctx = logging.WithCorrelationID(ctx, "corr-789")
+
+	s.Logger.InfoContext(ctx, "with context")
+	s.Logger.LogContext(ctx, logging.WarnLevel, "log context")
+
+	entries := s.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		assertFields(t, &entry, map[string]interface{}{
+			"trace_id":       "trace-123",
+			"request_id":     "req-456",
+			"correlation_id": "corr-789",
+		})
+	}
+}
+
+func checkFluent(t *testing.T, s Subject) {
+	s.Logger.Fluent().Error().Str("op", "query").Int("attempt", 2).Msg("fluent entry")
+
+	entries := byMessage(s.Entries())
+	entry := entries["fluent entry"]
+	if entry == nil {
+		t.Fatal("expected fluent entry to be written")
+	}
+	if entry.Level != logging.ErrorLevel {
+		t.Errorf("expected ERROR level, got %s", entry.Level)
+	}
+	assertFields(t, entry, map[string]interface{}{"op": "query", "attempt": 2})
+}
+
+func checkConcurrency(t *testing.T, s Subject) {
+	const goroutines, perGoroutine = 20, 50
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			logger := s.Logger.WithField("goroutine", g)
+			for i := 0; i < perGoroutine; i++ {
+				logger.Info("entry %d", i)
+				if i%10 == 0 {
+					s.Logger.SetLevel(logging.TraceLevel)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if got := len(s.Entries()); got != goroutines*perGoroutine {
+		t.Errorf("expected %d entries, got %d", goroutines*perGoroutine, got)
+	}
+}
+
+// assertFields checks that entry has each wanted field and none of absent.
+// Values are compared by their string form so JSON-decoded numbers match.
+func assertFields(t *testing.T, entry *logging.LogEntry, want map[string]interface{}, absent ...string) {
+	t.Helper()
+	if entry == nil {
+		t.Error("expected entry to be written")
+		return
+	}
+	for k, v := range want {
+		got, ok := entry.Fields[k]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(v) {
+			t.Errorf("%q: expected field %s=%v, got fields %v", entry.Message, k, v, entry.Fields)
+		}
+	}
+	for _, k := range absent {
+		if _, ok := entry.Fields[k]; ok {
+			t.Errorf("%q: unexpected field %s in %v", entry.Message, k, entry.Fields)
+		}
+	}
+}
+
+func byMessage(entries []logging.LogEntry) map[string]*logging.LogEntry {
+	m := make(map[string]*logging.LogEntry, len(entries))
+	for i := range entries {
+		m[entries[i].Message] = &entries[i]
+	}
+	return m
+}
+
+func messages(entries []logging.LogEntry) string {
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Message
+	}
+	return strings.Join(msgs, ", ")
+}
@@ -0,0 +1,38 @@
+package loggingtest_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ocrosby/go-logging/pkg/logging"
+	"github.com/ocrosby/go-logging/pkg/logging/loggingmock"
+	"github.com/ocrosby/go-logging/pkg/logging/loggingtest"
+)
+
+func TestUnifiedLoggerConformance(t *testing.T) {
+	loggingtest.RunLoggerConformance(t, func(t *testing.T) loggingtest.Subject {
+		buf := &bytes.Buffer{}
+		logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+			WithLevel(logging.TraceLevel).
+			WithJSONFormat().
+			WithWriter(buf).
+			Build())
+		return loggingtest.Subject{Logger: logger, Entries: loggingtest.JSONEntries(buf)}
+	})
+}
+
+func TestSpyConformance(t *testing.T) {
+	loggingtest.RunLoggerConformance(t, func(t *testing.T) loggingtest.Subject {
+		spy := loggingmock.NewSpy()
+		return loggingtest.Subject{
+			Logger: spy,
+			Entries: func() []logging.LogEntry {
+				var entries []logging.LogEntry
+				for _, e := range spy.Entries() {
+					entries = append(entries, logging.LogEntry{Level: e.Level, Message: e.Message, Fields: e.Fields})
+				}
+				return entries
+			},
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package loggingtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ocrosby/go-logging/pkg/logging"
+)
+
+// Context returns t's context carrying t's name, so entries logged with it
+// through a logging.TestFormatter are prefixed with the test they came from.
+//
+// Example:
+//
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithCustomFormatter(logging.NewTestFormatter(nil, false)).
+//		Build())
+//	logger.InfoContext(loggingtest.Context(t), "seeded %d rows", n)
+func Context(t testing.TB) context.Context {
+	return logging.WithTestName(t.Context(), t.Name())
+}
@@ -0,0 +1,23 @@
+package loggingtest_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ocrosby/go-logging/pkg/logging"
+	"github.com/ocrosby/go-logging/pkg/logging/loggingtest"
+)
+
+func TestContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+		WithCustomFormatter(logging.NewTestFormatter(nil, false)).
+		WithWriter(buf).
+		Build())
+
+	logger.InfoContext(loggingtest.Context(t), "seeded")
+	if !strings.HasPrefix(buf.String(), "[TestContext] INFO") {
+		t.Errorf("expected the test name prefix, got %q", buf.String())
+	}
+}
@@ -0,0 +1,150 @@
+package loggingtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+)
+
+// ValidateJSONSchema checks a JSON entry against a JSON Schema document,
+// such as one returned by logging.JSONSchema or read back from a published
+// file. It supports the keywords those documents use: type, properties,
+// required, additionalProperties, enum, pattern and the date-time format.
+// All problems are reported together.
+func ValidateJSONSchema(schema map[string]interface{}, entry []byte) error {
+	// Round-trip the document so schemas built in Go and read from JSON
+	// are handled alike.
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %w", err)
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to decode schema: %w", err)
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(entry))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("entry is not JSON: %w", err)
+	}
+	return errors.Join(validateValue("entry", document, value)...)
+}
+
+// AssertMatchesJSONSchema fails t for each newline-delimited JSON entry in
+// output that does not match schema.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithWriter(&buf).
+//		Build())
+//	logger.Info("order placed")
+//	loggingtest.AssertMatchesJSONSchema(t, logging.JSONSchemaLogger(logger), buf.Bytes())
+func AssertMatchesJSONSchema(t testing.TB, schema map[string]interface{}, output []byte) {
+	t.Helper()
+	for i, line := range bytes.Split(output, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := ValidateJSONSchema(schema, line); err != nil {
+			t.Errorf("entry %d does not match the schema: %v\n%s", i+1, err, line)
+		}
+	}
+}
+
+// validateValue checks value, found at path, against schema.
+func validateValue(path string, schema map[string]interface{}, value interface{}) []error {
+	var problems []error
+	if want, ok := schema["type"].(string); ok {
+		if got := jsonType(value); got != want && !(want == "number" && got == "integer") {
+			return []error{fmt.Errorf("%s is %s, want %s", path, got, want)}
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, value) {
+		problems = append(problems, fmt.Errorf("%s is %v, want one of %v", path, value, enum))
+	}
+	if s, ok := value.(string); ok {
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("%s has an invalid pattern %q: %w", path, pattern, err))
+			} else if !re.MatchString(s) {
+				problems = append(problems, fmt.Errorf("%s is %q, want a match for %q", path, s, pattern))
+			}
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				problems = append(problems, fmt.Errorf("%s is not an RFC 3339 date-time: %q", path, s))
+			}
+		}
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return problems
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := object[fmt.Sprint(name)]; !ok {
+				problems = append(problems, fmt.Errorf("%s is missing required property %q", path, name))
+			}
+		}
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			if schema["additionalProperties"] == false {
+				problems = append(problems, fmt.Errorf("%s has unexpected property %q", path, name))
+			}
+			continue
+		}
+		problems = append(problems, validateValue(path+"."+name, property, object[name])...)
+	}
+	return problems
+}
+
+// jsonType returns the JSON Schema type of a decoded value.
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) || fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
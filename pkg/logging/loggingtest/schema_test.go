@@ -0,0 +1,66 @@
+package loggingtest_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ocrosby/go-logging/pkg/logging"
+	"github.com/ocrosby/go-logging/pkg/logging/loggingtest"
+)
+
+func TestAssertMatchesJSONSchema(t *testing.T) {
+	encryptor, err := logging.NewFieldEncryptor(bytes.Repeat([]byte{1}, 32), "k1", "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+		WithJSONFormat().
+		WithSchemaVersion("3").
+		WithFieldEncryption(encryptor).
+		WithWriter(buf).
+		Build()).
+		WithField("service", "checkout")
+
+	logger.InfoContext(logging.NewContextWithTrace(), "order placed")
+	logger.WithField("email", "ada@example.com").Warn("payment retried")
+	logger.Fluent().Error().Int("attempt", 3).Msg("payment failed")
+
+	loggingtest.AssertMatchesJSONSchema(t, logging.JSONSchemaLogger(logger), buf.Bytes())
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+		WithJSONFormat().
+		Build()).
+		WithField("replicas", 3)
+	schema := logging.JSONSchemaLogger(logger)
+
+	tests := []struct {
+		name  string
+		entry string
+		want  string
+	}{
+		{"valid", `{"timestamp":"2024-05-01T12:00:00Z","level":"INFO","message":"ok","replicas":3,"extra":true}`, ""},
+		{"missing message", `{"timestamp":"2024-05-01T12:00:00Z","level":"INFO","replicas":3}`, `missing required property "message"`},
+		{"unknown level", `{"timestamp":"2024-05-01T12:00:00Z","level":"LOUD","message":"ok","replicas":3}`, "want one of"},
+		{"wrong type", `{"timestamp":"2024-05-01T12:00:00Z","level":"INFO","message":"ok","replicas":"3"}`, "entry.replicas is string, want number"},
+		{"bad timestamp", `{"timestamp":"yesterday","level":"INFO","message":"ok","replicas":3}`, "not an RFC 3339 date-time"},
+		{"not JSON", `INFO ok`, "entry is not JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loggingtest.ValidateJSONSchema(schema, []byte(tt.entry))
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LokiConfig configures a LokiOutput.
+type LokiConfig struct {
+	// URL is the push endpoint, e.g. "http://loki:3100/loki/api/v1/push".
+	URL string
+	// Labels lists the fields that become stream labels, typically static
+	// fields such as "service" and "environment". Every other field goes into
+	// the log line. Keep labels low-cardinality.
+	Labels []string
+	// StaticLabels are added to every stream, e.g. {"job": "api"}.
+	StaticLabels map[string]string
+	// TenantID sets the X-Scope-OrgID header for multi-tenant Loki.
+	TenantID string
+	// Username and Password enable basic auth.
+	Username string
+	Password string
+	// BatchSize is the number of entries per push. Defaults to 500.
+	BatchSize int
+	// Linger is how long a partial batch waits for more entries. Defaults to 1s.
+	Linger time.Duration
+	// QueueSize bounds the entries waiting to be batched. Defaults to 10000.
+	QueueSize int
+	// MaxInFlight bounds concurrent pushes. Defaults to 2.
+	MaxInFlight int
+	// Retry controls retries of pushes that fail with network errors, 429
+	// or 5xx responses.
+	Retry RetryConfig
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the number of entries that could not be pushed.
+	OnError func(entries int, err error)
+}
+
+// lokiEntry is a queued log line with its stream labels.
+type lokiEntry struct {
+	labels    map[string]string
+	timestamp time.Time
+	line      string
+}
+
+// LokiOutput ships entries to Grafana Loki's push API in batches. The level
+// and configured fields become stream labels; the message and remaining
+// fields form a JSON log line, queryable with LogQL's json parser.
+//
+// Example:
+//
+//	output, err := logging.NewLokiOutput(logging.LokiConfig{
+//		URL:    "http://loki:3100/loki/api/v1/push",
+//		Labels: []string{"service", "environment"},
+//	})
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithCustomOutput(output).
+//		Build()).
+//		WithFields(map[string]interface{}{"service": "orders", "environment": "prod"})
+type LokiOutput struct {
+	config   LokiConfig
+	labels   map[string]bool
+	batcher  *batcher[lokiEntry]
+	inFlight chan struct{}
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// NewLokiOutput creates a LokiOutput.
+func NewLokiOutput(config LokiConfig) (*LokiOutput, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("loki output requires a push URL")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 500
+	}
+	if config.Linger == 0 {
+		config.Linger = time.Second
+	}
+	if config.QueueSize == 0 {
+		config.QueueSize = 10000
+	}
+	if config.MaxInFlight <= 0 {
+		config.MaxInFlight = 2
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	labels := make(map[string]bool, len(config.Labels))
+	for _, name := range config.Labels {
+		labels[name] = true
+	}
+
+	lo := &LokiOutput{
+		config:   config,
+		labels:   labels,
+		inFlight: make(chan struct{}, config.MaxInFlight),
+	}
+	lo.ctx, lo.cancel = context.WithCancel(context.Background())
+	lo.batcher = newBatcher(batcherConfig[lokiEntry]{
+		Size:      config.BatchSize,
+		Linger:    config.Linger,
+		QueueSize: config.QueueSize,
+		Flush:     lo.dispatch,
+		OnError: func(batch []lokiEntry, err error) {
+			lo.reportError(len(batch), err)
+		},
+	})
+	return lo, nil
+}
+
+// Write queues data as an INFO line.
+func (lo *LokiOutput) Write(data []byte) error {
+	return lo.batcher.Submit(lokiEntry{
+		labels:    lo.streamLabels(LogEntry{Level: InfoLevel}),
+		timestamp: time.Now(),
+		line:      string(bytes.TrimRight(data, "\r\n")),
+	})
+}
+
+// WriteEntry queues entry, splitting its fields into labels and line.
+func (lo *LokiOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	line := map[string]interface{}{"message": entry.Message}
+	for k, v := range entry.Fields {
+		if !lo.labels[k] {
+			line[k] = v
+		}
+	}
+	for _, name := range []string{"trace_id", "request_id", "correlation_id"} {
+		if _, ok := line[name]; ok || lo.labels[name] {
+			continue
+		}
+		if value, ok := entryFieldString(entry, name); ok {
+			line[name] = value
+		}
+	}
+
+	encoded, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Errorf("failed to encode loki line: %w", err)
+	}
+
+	timestamp := entry.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	return lo.batcher.Submit(lokiEntry{
+		labels:    lo.streamLabels(entry),
+		timestamp: timestamp,
+		line:      string(encoded),
+	})
+}
+
+var lokiLabelInvalid = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// streamLabels returns the labels of the stream entry belongs to.
+func (lo *LokiOutput) streamLabels(entry LogEntry) map[string]string {
+	labels := make(map[string]string, len(lo.config.StaticLabels)+len(lo.config.Labels)+1)
+	for k, v := range lo.config.StaticLabels {
+		labels[lokiLabelName(k)] = v
+	}
+	for _, name := range lo.config.Labels {
+		if value, ok := entryFieldString(entry, name); ok {
+			labels[lokiLabelName(name)] = value
+		}
+	}
+	labels["level"] = strings.ToLower(entry.Level.String())
+	return labels
+}
+
+// lokiLabelName makes name a valid Prometheus label name.
+func lokiLabelName(name string) string {
+	name = lokiLabelInvalid.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// dispatch pushes batch in the background, blocking while MaxInFlight
+// pushes are already running.
+func (lo *LokiOutput) dispatch(batch []lokiEntry) error {
+	body, err := encodeLokiPush(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode loki push: %w", err)
+	}
+
+	lo.inFlight <- struct{}{}
+	lo.wg.Add(1)
+	go func() {
+		defer func() {
+			<-lo.inFlight
+			lo.wg.Done()
+		}()
+		if err := retry(lo.ctx, lo.config.Retry, func() error { return lo.push(body) }); err != nil {
+			lo.reportError(len(batch), fmt.Errorf("failed to push to loki: %w", err))
+		}
+	}()
+	return nil
+}
+
+func (lo *LokiOutput) push(body []byte) error {
+	req, err := http.NewRequestWithContext(lo.ctx, http.MethodPost, lo.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if lo.config.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", lo.config.TenantID)
+	}
+	if lo.config.Username != "" {
+		req.SetBasicAuth(lo.config.Username, lo.config.Password)
+	}
+
+	resp, err := lo.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	return checkHTTPResponse(resp)
+}
+
+func (lo *LokiOutput) reportError(entries int, err error) {
+	if lo.config.OnError != nil {
+		lo.config.OnError(entries, err)
+	}
+}
+
+// lokiStream is one stream of a push request.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// encodeLokiPush groups batch into streams by label set, in a stable order.
+func encodeLokiPush(batch []lokiEntry) ([]byte, error) {
+	streams := make(map[string]*lokiStream)
+	var keys []string
+	for _, e := range batch {
+		key := lokiStreamKey(e.labels)
+		stream, ok := streams[key]
+		if !ok {
+			stream = &lokiStream{Stream: e.labels}
+			streams[key] = stream
+			keys = append(keys, key)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(e.timestamp.UnixNano(), 10), e.line})
+	}
+
+	push := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{Streams: make([]*lokiStream, 0, len(keys))}
+	for _, key := range keys {
+		push.Streams = append(push.Streams, streams[key])
+	}
+	return json.Marshal(push)
+}
+
+// lokiStreamKey renders labels in LogQL selector form, e.g. {a="1",b="2"}.
+func lokiStreamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", name, labels[name])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// Flush starts a push of the pending partial batch.
+func (lo *LokiOutput) Flush() error {
+	return lo.batcher.Flush()
+}
+
+// Close pushes queued entries and waits for in-flight pushes to finish.
+func (lo *LokiOutput) Close() error {
+	err := lo.batcher.Stop()
+	lo.wg.Wait()
+	lo.cancel()
+	return err
+}
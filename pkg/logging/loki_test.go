@@ -0,0 +1,182 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type lokiPushRequest struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+func TestNewLokiOutput_Validation(t *testing.T) {
+	if _, err := NewLokiOutput(LokiConfig{}); err == nil {
+		t.Error("expected error without URL")
+	}
+}
+
+func TestLokiOutput_Push(t *testing.T) {
+	var mu sync.Mutex
+	var pushes []lokiPushRequest
+	var tenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var push lokiPushRequest
+		if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+			t.Errorf("invalid push body: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		pushes = append(pushes, push)
+		tenant = r.Header.Get("X-Scope-OrgID")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	output, err := NewLokiOutput(LokiConfig{
+		URL:          server.URL,
+		Labels:       []string{"service", "environment"},
+		StaticLabels: map[string]string{"job": "api"},
+		TenantID:     "team-a",
+		Linger:       time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("failed to create loki output: %v", err)
+	}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(output).Build()).
+		WithFields(map[string]interface{}{"service": "orders", "environment": "prod"})
+	ctx := WithTraceID(context.Background(), "trace-1")
+	logger.WithField("order_id", 7).InfoContext(ctx, "created")
+	logger.Info("listed")
+	logger.Error("failed")
+
+	if err := output.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(pushes) != 1 || len(pushes[0].Streams) != 2 {
+		t.Fatalf("expected one push with info and error streams, got %+v", pushes)
+	}
+	if tenant != "team-a" {
+		t.Errorf("expected tenant header, got %q", tenant)
+	}
+
+	info := pushes[0].Streams[0]
+	want := map[string]string{"service": "orders", "environment": "prod", "job": "api", "level": "info"}
+	for k, v := range want {
+		if info.Stream[k] != v {
+			t.Errorf("expected label %s=%s, got %v", k, v, info.Stream)
+		}
+	}
+	if len(info.Values) != 2 {
+		t.Fatalf("expected 2 info lines, got %v", info.Values)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal([]byte(info.Values[0][1]), &line); err != nil {
+		t.Fatalf("invalid line %q: %v", info.Values[0][1], err)
+	}
+	if line["message"] != "created" || line["order_id"] != float64(7) || line["trace_id"] != "trace-1" {
+		t.Errorf("unexpected line: %v", line)
+	}
+	if _, ok := line["service"]; ok {
+		t.Errorf("label fields should not be repeated in the line: %v", line)
+	}
+	if pushes[0].Streams[1].Stream["level"] != "error" {
+		t.Errorf("expected error stream, got %v", pushes[0].Streams[1].Stream)
+	}
+}
+
+func TestLokiOutput_RetriesAndReportsFailures(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			http.Error(w, "overloaded", http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	output, err := NewLokiOutput(LokiConfig{
+		URL:   server.URL,
+		Retry: RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("failed to create loki output: %v", err)
+	}
+	_ = output.Write([]byte("retried\n"))
+	_ = output.Close()
+
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestLokiOutput_PermanentFailure(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "entry too far behind", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var failed atomic.Int32
+	output, err := NewLokiOutput(LokiConfig{
+		URL:     server.URL,
+		Retry:   RetryConfig{InitialBackoff: time.Millisecond},
+		OnError: func(entries int, err error) { failed.Add(int32(entries)) },
+	})
+	if err != nil {
+		t.Fatalf("failed to create loki output: %v", err)
+	}
+	_ = output.Write([]byte("rejected\n"))
+	_ = output.Close()
+
+	if attempts.Load() != 1 {
+		t.Errorf("expected no retries for 400, got %d attempts", attempts.Load())
+	}
+	if failed.Load() != 1 {
+		t.Errorf("expected failure callback for 1 entry, got %d", failed.Load())
+	}
+}
+
+func TestLokiOutput_MaxInFlight(t *testing.T) {
+	var current, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := current.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		current.Add(-1)
+	}))
+	defer server.Close()
+
+	output, err := NewLokiOutput(LokiConfig{URL: server.URL, BatchSize: 1, MaxInFlight: 2})
+	if err != nil {
+		t.Fatalf("failed to create loki output: %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		_ = output.Write([]byte("entry"))
+	}
+	_ = output.Close()
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("expected at most 2 concurrent pushes, got %d", got)
+	}
+}
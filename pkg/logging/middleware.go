@@ -1,7 +1,12 @@
 package logging
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -11,24 +16,281 @@ const (
 	HeaderCorrelationID = "X-Correlation-ID"
 )
 
+// sensitiveResponseHeaders are recorded as "<REDACTED>" whatever their
+// value.
+var sensitiveResponseHeaders = map[string]bool{
+	"Set-Cookie":          true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Www-Authenticate":    true,
+}
+
+// responseWriter records the status, size and timing of a response. wrap
+// passes http.Flusher, http.Hijacker and io.ReaderFrom through to the
+// wrapped writer when it has them, and Unwrap gives http.ResponseController
+// access to the rest, so SSE, websockets and sendfile keep working behind
+// the middleware.
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
 	written    int64
+	// capture lists the response headers to record when they are sent.
+	capture     []string
+	redactors   []Redactor
+	headers     map[string]string
+	wroteHeader bool
+	// start is when the request began; firstByte is how long after start the
+	// response header was sent.
+	start     time.Time
+	firstByte time.Duration
+	flushes   int
+	hijacked  bool
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
 	rw.statusCode = code
+	rw.recordHeaders()
 	rw.ResponseWriter.WriteHeader(code)
 }
 
 func (rw *responseWriter) Write(b []byte) (int, error) {
+	rw.recordHeaders()
 	n, err := rw.ResponseWriter.Write(b)
 	rw.written += int64(n)
 	return n, err
 }
 
+// flush sends buffered data to the client, if the wrapped writer supports
+// it.
+func (rw *responseWriter) flush() {
+	rw.recordHeaders()
+	rw.flushes++
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// hijack takes over the connection, if the wrapped writer supports it.
+func (rw *responseWriter) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer %T does not support hijacking", rw.ResponseWriter)
+	}
+	conn, buf, err := hijacker.Hijack()
+	if err == nil {
+		rw.recordHeaders()
+		rw.hijacked = true
+		rw.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, buf, err
+}
+
+// readFrom copies from r with the wrapped writer's ReadFrom, which lets
+// net/http use sendfile for files.
+func (rw *responseWriter) readFrom(r io.Reader) (int64, error) {
+	rw.recordHeaders()
+	var n int64
+	var err error
+	if readerFrom, ok := rw.ResponseWriter.(io.ReaderFrom); ok {
+		n, err = readerFrom.ReadFrom(r)
+	} else {
+		n, err = io.Copy(rw.ResponseWriter, r)
+	}
+	rw.written += n
+	return n, err
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+type responseFlusher struct{ rw *responseWriter }
+
+func (f responseFlusher) Flush() { f.rw.flush() }
+
+type responseHijacker struct{ rw *responseWriter }
+
+func (h responseHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) { return h.rw.hijack() }
+
+type responseReaderFrom struct{ rw *responseWriter }
+
+func (r responseReaderFrom) ReadFrom(src io.Reader) (int64, error) { return r.rw.readFrom(src) }
+
+// wrap returns rw as an http.ResponseWriter implementing http.Flusher,
+// http.Hijacker and io.ReaderFrom only where the wrapped writer does, so
+// handlers checking for them find what the connection supports.
+func (rw *responseWriter) wrap() http.ResponseWriter {
+	_, canFlush := rw.ResponseWriter.(http.Flusher)
+	_, canHijack := rw.ResponseWriter.(http.Hijacker)
+	_, canReadFrom := rw.ResponseWriter.(io.ReaderFrom)
+	f, h, r := responseFlusher{rw}, responseHijacker{rw}, responseReaderFrom{rw}
+
+	switch {
+	case canFlush && canHijack && canReadFrom:
+		return struct {
+			*responseWriter
+			responseFlusher
+			responseHijacker
+			responseReaderFrom
+		}{rw, f, h, r}
+	case canFlush && canHijack:
+		return struct {
+			*responseWriter
+			responseFlusher
+			responseHijacker
+		}{rw, f, h}
+	case canFlush && canReadFrom:
+		return struct {
+			*responseWriter
+			responseFlusher
+			responseReaderFrom
+		}{rw, f, r}
+	case canHijack && canReadFrom:
+		return struct {
+			*responseWriter
+			responseHijacker
+			responseReaderFrom
+		}{rw, h, r}
+	case canFlush:
+		return struct {
+			*responseWriter
+			responseFlusher
+		}{rw, f}
+	case canHijack:
+		return struct {
+			*responseWriter
+			responseHijacker
+		}{rw, h}
+	case canReadFrom:
+		return struct {
+			*responseWriter
+			responseReaderFrom
+		}{rw, r}
+	default:
+		return rw
+	}
+}
+
+// streamed reports whether the response was flushed while being written or
+// the connection hijacked, so its duration is not just the time to respond.
+func (rw *responseWriter) streamed() bool {
+	return rw.flushes > 0 || rw.hijacked
+}
+
+// recordHeaders records the captured headers as they are when the response
+// header is written. Changes made later are trailers, or have no effect.
+func (rw *responseWriter) recordHeaders() {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	if !rw.start.IsZero() {
+		rw.firstByte = time.Since(rw.start)
+	}
+	if len(rw.capture) > 0 {
+		rw.headers = headerValues(rw.ResponseWriter.Header(), rw.capture, "", rw.redactors)
+	}
+}
+
+// headerValues returns the values of the named headers present in header,
+// looked up with prefix, keyed by canonical name. Sensitive headers are
+// masked and the rest passed through redactors, as in RedactedURL.
+func headerValues(header http.Header, names []string, prefix string, redactors []Redactor) map[string]string {
+	var values map[string]string
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(name)
+		v, ok := header[prefix+name]
+		if !ok {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]string, len(names))
+		}
+		if sensitiveResponseHeaders[name] {
+			values[name] = "<REDACTED>"
+			continue
+		}
+		values[name] = RedactedURL(strings.Join(v, ", "), redactors...)
+	}
+	return values
+}
+
+// TracingMiddlewareConfig configures the entries TracingMiddlewareWithConfig
+// logs for each request.
+type TracingMiddlewareConfig struct {
+	// StartLevel is the level of the "Request started" entry. Defaults to
+	// InfoLevel.
+	StartLevel Level
+	// CompletionLevel is the level of the "Request completed" entry.
+	// Defaults to InfoLevel.
+	CompletionLevel Level
+	// DisableStart omits the "Request started" entry, leaving a single
+	// completion entry per request.
+	DisableStart bool
+	// ResponseHeaders lists response headers, e.g. "Content-Type" or
+	// "Cache-Control", recorded in a response_headers field of the
+	// completion entry.
+	ResponseHeaders []string
+	// Trailers lists HTTP trailers recorded in a response_trailers field of
+	// the completion entry.
+	Trailers []string
+	// Redactors are applied to recorded header and trailer values. Without
+	// any, API keys are redacted as in RedactedURL. Set-Cookie,
+	// Authorization, Proxy-Authorization and WWW-Authenticate values are
+	// always replaced with "<REDACTED>".
+	Redactors []Redactor
+	// AccessLog, if set, receives one access-log line per request when it
+	// completes, alongside the completion entry.
+	AccessLog Output
+	// AccessLogFormat selects the AccessLog lines: CommonLogFormat, the
+	// default, or CombinedLogFormat, which adds the referer and user agent.
+	// JSONFormat and TextFormat write the same fields as structured entries.
+	AccessLogFormat OutputFormat
+	// CanonicalLogLine starts a CanonicalEntry for each request, which
+	// handlers reach with CanonicalEntryFrom(r.Context()), and writes its
+	// fields with the completion entry, logged as a canonical log line.
+	CanonicalLogLine bool
+}
+
+// DefaultTracingMiddlewareConfig returns the configuration TracingMiddleware
+// uses: start and completion entries, both at INFO.
+func DefaultTracingMiddlewareConfig() TracingMiddlewareConfig {
+	return TracingMiddlewareConfig{StartLevel: InfoLevel, CompletionLevel: InfoLevel}
+}
+
+func (c TracingMiddlewareConfig) withDefaults() TracingMiddlewareConfig {
+	if c.StartLevel == TraceLevel {
+		c.StartLevel = InfoLevel
+	}
+	if c.CompletionLevel == TraceLevel {
+		c.CompletionLevel = InfoLevel
+	}
+	return c
+}
+
 func TracingMiddleware(logger Logger) func(http.Handler) http.Handler {
+	return TracingMiddlewareWithConfig(logger, DefaultTracingMiddlewareConfig())
+}
+
+// TracingMiddlewareWithConfig is TracingMiddleware with the levels of its
+// entries, and whether the start entry is logged, set by config.
+//
+// Example:
+//
+//	config := logging.DefaultTracingMiddlewareConfig()
+//	config.DisableStart = true
+//	config.AccessLog = accessLogFile
+//	config.AccessLogFormat = logging.CombinedLogFormat
+//	handler := logging.TracingMiddlewareWithConfig(logger, config)(mux)
+func TracingMiddlewareWithConfig(logger Logger, config TracingMiddlewareConfig) func(http.Handler) http.Handler {
+	config = config.withDefaults()
+	var accessLog Formatter
+	if config.AccessLog != nil {
+		accessLog = newFormatterFromConfig(&LoggerConfig{
+			Formatter: NewFormatterConfig().WithFormat(config.AccessLogFormat).IncludeFile(false).Build(),
+		})
+	}
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -51,37 +313,147 @@ func TracingMiddleware(logger Logger) func(http.Handler) http.Handler {
 				ctx = WithCorrelationID(ctx, correlationID)
 			}
 
+			var canonical *CanonicalEntry
+			if config.CanonicalLogLine {
+				ctx, canonical = NewCanonicalEntry(ctx)
+			}
+
 			w.Header().Set(HeaderTraceID, traceID)
 
 			rw := &responseWriter{
 				ResponseWriter: w,
 				statusCode:     http.StatusOK,
+				capture:        config.ResponseHeaders,
+				redactors:      config.Redactors,
+				start:          start,
 			}
 
-			logger.Fluent().Info().
-				Ctx(ctx).
-				Str("method", r.Method).
-				Str("path", RedactedURL(r.URL.String())).
-				Str("remote_addr", r.RemoteAddr).
-				Str("user_agent", r.UserAgent()).
-				Msg("Request started")
+			if !config.DisableStart {
+				fluentAt(logger, config.StartLevel).
+					Ctx(ctx).
+					Str("method", r.Method).
+					Str("path", RedactedURL(r.URL.String())).
+					Str("remote_addr", r.RemoteAddr).
+					Str("user_agent", r.UserAgent()).
+					Msg("Request started")
+			}
 
-			next.ServeHTTP(rw, r.WithContext(ctx))
+			next.ServeHTTP(rw.wrap(), r.WithContext(ctx))
+			rw.recordHeaders()
 
 			duration := time.Since(start)
 
-			logger.Fluent().Info().
-				Ctx(ctx).
+			message := "Request completed"
+			entry := fluentAt(logger, config.CompletionLevel).Ctx(ctx)
+			if fields, ok := canonical.complete(); ok {
+				message = CanonicalLogLineMessage
+				entry = entry.Fields(fields)
+			}
+			entry = entry.
 				Str("method", r.Method).
 				Str("path", RedactedURL(r.URL.String())).
 				Int("status", rw.statusCode).
 				Int64("bytes", rw.written).
-				Int64("duration_ms", duration.Milliseconds()).
-				Msg("Request completed")
+				Int64("duration_ms", duration.Milliseconds())
+			if rw.streamed() {
+				entry = entry.
+					Int64("ttfb_ms", rw.firstByte.Milliseconds()).
+					Int("flushes", rw.flushes)
+			}
+			if rw.hijacked {
+				entry = entry.Bool("hijacked", true)
+			}
+			if rw.headers != nil {
+				entry.Field("response_headers", rw.headers)
+			}
+			if trailers := trailerValues(w.Header(), config.Trailers, config.Redactors); trailers != nil {
+				entry.Field("response_trailers", trailers)
+			}
+			entry.Msg(message)
+
+			if accessLog != nil {
+				if err := writeAccessLog(config.AccessLog, accessLog, r.WithContext(ctx), rw); err != nil {
+					fluentAt(logger, ErrorLevel).Ctx(ctx).Err(err).Msg("Failed to write access log")
+				}
+			}
 		})
 	}
 }
 
+// writeAccessLog writes the access-log line of a completed request, with
+// the fields CommonLogFormatter and CombinedLogFormatter read.
+func writeAccessLog(output Output, formatter Formatter, r *http.Request, rw *responseWriter) error {
+	host := r.RemoteAddr
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	request := fmt.Sprintf("%s %s %s", r.Method, RedactedURL(r.URL.RequestURI()), r.Proto)
+	fields := map[string]interface{}{
+		"host":    host,
+		"request": request,
+		"status":  rw.statusCode,
+		"bytes":   rw.written,
+	}
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		fields["authuser"] = user
+	}
+	if referer := r.Referer(); referer != "" {
+		fields["referer"] = RedactedURL(referer)
+	}
+	if userAgent := r.UserAgent(); userAgent != "" {
+		fields["user_agent"] = userAgent
+	}
+
+	entry := LogEntry{
+		Timestamp: rw.start,
+		Level:     InfoLevel,
+		Message:   request,
+		Fields:    fields,
+		Context:   r.Context(),
+	}
+	data, err := formatter.Format(entry)
+	if err != nil {
+		return fmt.Errorf("failed to format access log entry: %w", err)
+	}
+	return writeEntry(output, entry, data)
+}
+
+// trailerValues returns the named trailers set in header after the response
+// was written, whether declared in advance with the Trailer header or set
+// with http.TrailerPrefix.
+func trailerValues(header http.Header, names []string, redactors []Redactor) map[string]string {
+	if len(names) == 0 {
+		return nil
+	}
+	values := headerValues(header, names, "", redactors)
+	for name, value := range headerValues(header, names, http.TrailerPrefix, redactors) {
+		if values == nil {
+			values = make(map[string]string, len(names))
+		}
+		values[name] = value
+	}
+	return values
+}
+
+// fluentAt starts a fluent entry at level.
+func fluentAt(logger Logger, level Level) *FluentEntry {
+	fluent := logger.Fluent()
+	switch level {
+	case TraceLevel:
+		return fluent.Trace()
+	case DebugLevel:
+		return fluent.Debug()
+	case WarnLevel:
+		return fluent.Warn()
+	case ErrorLevel:
+		return fluent.Error()
+	case CriticalLevel:
+		return fluent.Critical()
+	default:
+		return fluent.Info()
+	}
+}
+
 func RequestLogger(logger Logger, headers ...string) func(http.Handler) http.Handler {
 	if len(headers) == 0 {
 		headers = []string{"User-Agent"}
@@ -1,11 +1,17 @@
 package logging
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestTracingMiddleware(t *testing.T) {
@@ -127,6 +133,175 @@ func TestTracingMiddleware_WithRequestAndCorrelationIDs(t *testing.T) {
 	}
 }
 
+func TestTracingMiddlewareWithConfig_Levels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().
+		WithLevel(TraceLevel).
+		WithWriter(buf).
+		WithJSONFormat().
+		Build()
+	logger := NewWithLoggerConfig(config)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	middleware := TracingMiddlewareWithConfig(logger, TracingMiddlewareConfig{
+		StartLevel:      DebugLevel,
+		CompletionLevel: WarnLevel,
+	})
+	middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "Request started") || !strings.Contains(lines[0], `"level":"DEBUG"`) {
+		t.Errorf("expected start entry at DEBUG, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "Request completed") || !strings.Contains(lines[1], `"level":"WARN"`) {
+		t.Errorf("expected completion entry at WARN, got: %s", lines[1])
+	}
+}
+
+func TestTracingMiddlewareWithConfig_DisableStart(t *testing.T) {
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithWriter(buf).
+		WithTextFormat().
+		Build()
+	logger := NewWithLoggerConfig(config)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tracingConfig := DefaultTracingMiddlewareConfig()
+	tracingConfig.DisableStart = true
+	middleware := TracingMiddlewareWithConfig(logger, tracingConfig)
+	middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	output := buf.String()
+	if strings.Contains(output, "Request started") {
+		t.Errorf("expected no start entry, got: %s", output)
+	}
+	if strings.Count(output, "Request completed") != 1 {
+		t.Errorf("expected one completion entry, got: %s", output)
+	}
+}
+
+func TestTracingMiddlewareWithConfig_DefaultLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithWriter(buf).
+		WithJSONFormat().
+		Build()
+	logger := NewWithLoggerConfig(config)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	middleware := TracingMiddlewareWithConfig(logger, TracingMiddlewareConfig{DisableStart: true})
+	middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	output := buf.String()
+	if strings.Count(output, "Request completed") != 1 || !strings.Contains(output, `"level":"INFO"`) {
+		t.Errorf("expected one completion entry at INFO, got: %s", output)
+	}
+}
+
+func TestTracingMiddlewareWithConfig_AccessLog(t *testing.T) {
+	logs := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithWriter(logs).WithTextFormat().Build())
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	})
+	for _, tt := range []struct {
+		format OutputFormat
+		suffix string
+	}{
+		{CommonLogFormat, `"POST /orders?apiKey=abcdefg...<REDACTED> HTTP/1.1" 201 7` + "\n"},
+		{CombinedLogFormat, `"POST /orders?apiKey=abcdefg...<REDACTED> HTTP/1.1" 201 7 "https://shop.example.com/cart" "curl/8.0"` + "\n"},
+	} {
+		access := &bytes.Buffer{}
+		tracingConfig := DefaultTracingMiddlewareConfig()
+		tracingConfig.AccessLog = NewWriterOutput(access)
+		tracingConfig.AccessLogFormat = tt.format
+
+		req := httptest.NewRequest("POST", "/orders?apiKey=abcdefg12345", nil)
+		req.RemoteAddr = "203.0.113.9:51234"
+		req.SetBasicAuth("alice", "password")
+		req.Header.Set("Referer", "https://shop.example.com/cart")
+		req.Header.Set("User-Agent", "curl/8.0")
+		TracingMiddlewareWithConfig(logger, tracingConfig)(handler).ServeHTTP(httptest.NewRecorder(), req)
+
+		line := access.String()
+		if !strings.HasPrefix(line, "203.0.113.9 - alice [") || !strings.HasSuffix(line, tt.suffix) {
+			t.Errorf("format %v: unexpected access log line %q", tt.format, line)
+		}
+	}
+	if !strings.Contains(logs.String(), "Request completed") {
+		t.Errorf("expected the completion entry as well, got: %s", logs.String())
+	}
+}
+
+func TestTracingMiddlewareWithConfig_ResponseHeadersAndTrailers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithWriter(buf).
+		WithJSONFormat().
+		Build()
+	logger := NewWithLoggerConfig(config)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Upstream", "https://cdn.example.com/a?apiKey=abcdefghijkl")
+		w.Header().Set("Trailer", "X-Checksum")
+		_, _ = w.Write([]byte("body"))
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("X-Checksum", "abc123")
+		w.Header().Set(http.TrailerPrefix+"X-Late", "done")
+	})
+	tracingConfig := DefaultTracingMiddlewareConfig()
+	tracingConfig.DisableStart = true
+	tracingConfig.ResponseHeaders = []string{"content-type", "Cache-Control", "Set-Cookie", "X-Upstream", "X-Missing"}
+	tracingConfig.Trailers = []string{"X-Checksum", "X-Late"}
+	middleware := TracingMiddlewareWithConfig(logger, tracingConfig)
+	middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+
+	var entry struct {
+		ResponseHeaders  map[string]string `json:"response_headers"`
+		ResponseTrailers map[string]string `json:"response_trailers"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid entry %q: %v", buf.String(), err)
+	}
+	want := map[string]string{
+		"Content-Type":  "text/plain",
+		"Cache-Control": "max-age=60",
+		"Set-Cookie":    "<REDACTED>",
+	}
+	for name, value := range want {
+		if entry.ResponseHeaders[name] != value {
+			t.Errorf("expected %s %q, got %q", name, value, entry.ResponseHeaders[name])
+		}
+	}
+	if upstream := entry.ResponseHeaders["X-Upstream"]; strings.Contains(upstream, "abcdefghijkl") {
+		t.Errorf("expected API key redacted, got %q", upstream)
+	}
+	if _, ok := entry.ResponseHeaders["X-Missing"]; ok {
+		t.Error("expected missing header to be omitted")
+	}
+	if entry.ResponseTrailers["X-Checksum"] != "abc123" || entry.ResponseTrailers["X-Late"] != "done" {
+		t.Errorf("unexpected trailers: %v", entry.ResponseTrailers)
+	}
+}
+
 func TestRequestLogger(t *testing.T) {
 	buf := &bytes.Buffer{}
 	config := NewLoggerConfig().
@@ -228,6 +403,196 @@ func TestResponseWriter_Write(t *testing.T) {
 	}
 }
 
+func TestResponseWriter_Flush(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: recorder, statusCode: http.StatusOK}
+
+	flusher, ok := rw.wrap().(http.Flusher)
+	if !ok {
+		t.Fatal("expected the wrapper to implement http.Flusher")
+	}
+	_, _ = rw.Write([]byte("event: ping\n\n"))
+	flusher.Flush()
+
+	if !recorder.Flushed {
+		t.Error("expected flush to reach the wrapped writer")
+	}
+	if rw.flushes != 1 || !rw.streamed() {
+		t.Errorf("expected one recorded flush, got %d", rw.flushes)
+	}
+}
+
+func TestResponseWriter_ReadFrom(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	rw := &responseWriter{ResponseWriter: recorder, statusCode: http.StatusOK}
+
+	n, err := rw.readFrom(strings.NewReader("file contents"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 13 || rw.written != 13 || recorder.Body.String() != "file contents" {
+		t.Errorf("unexpected copy: n=%d written=%d body=%q", n, rw.written, recorder.Body.String())
+	}
+}
+
+func TestResponseWriter_HijackUnsupported(t *testing.T) {
+	rw := &responseWriter{ResponseWriter: httptest.NewRecorder()}
+	if _, ok := rw.wrap().(http.Hijacker); ok {
+		t.Error("expected no http.Hijacker for a writer without one")
+	}
+	if _, _, err := http.NewResponseController(rw.wrap()).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("expected http.ErrNotSupported, got %v", err)
+	}
+	if _, _, err := rw.hijack(); err == nil {
+		t.Error("expected error hijacking a writer without Hijacker")
+	}
+	if rw.hijacked {
+		t.Error("expected failed hijack not to be recorded")
+	}
+}
+
+// plainResponseWriter implements only http.ResponseWriter.
+type plainResponseWriter struct{ http.ResponseWriter }
+
+// hijackResponseWriter adds http.Hijacker.
+type hijackResponseWriter struct{ http.ResponseWriter }
+
+func (hijackResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("not connected")
+}
+
+// readerFromResponseWriter adds io.ReaderFrom.
+type readerFromResponseWriter struct{ http.ResponseWriter }
+
+func (w readerFromResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(w.ResponseWriter, r)
+}
+
+// fullResponseWriter adds both to the recorder's http.Flusher.
+type fullResponseWriter struct{ *httptest.ResponseRecorder }
+
+func (fullResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errors.New("not connected")
+}
+
+func (w fullResponseWriter) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(w.ResponseRecorder, r)
+}
+
+func TestResponseWriter_Interfaces(t *testing.T) {
+	tests := []struct {
+		name                        string
+		writer                      http.ResponseWriter
+		flusher, hijacker, readFrom bool
+	}{
+		{"plain", plainResponseWriter{httptest.NewRecorder()}, false, false, false},
+		{"flusher", httptest.NewRecorder(), true, false, false},
+		{"hijacker", hijackResponseWriter{plainResponseWriter{httptest.NewRecorder()}}, false, true, false},
+		{"reader from", readerFromResponseWriter{plainResponseWriter{httptest.NewRecorder()}}, false, false, true},
+		{"all", fullResponseWriter{httptest.NewRecorder()}, true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := (&responseWriter{ResponseWriter: tt.writer}).wrap()
+			if _, ok := wrapped.(http.Flusher); ok != tt.flusher {
+				t.Errorf("http.Flusher = %v, want %v", ok, tt.flusher)
+			}
+			if _, ok := wrapped.(http.Hijacker); ok != tt.hijacker {
+				t.Errorf("http.Hijacker = %v, want %v", ok, tt.hijacker)
+			}
+			if _, ok := wrapped.(io.ReaderFrom); ok != tt.readFrom {
+				t.Errorf("io.ReaderFrom = %v, want %v", ok, tt.readFrom)
+			}
+		})
+	}
+}
+
+func TestTracingMiddleware_Streaming(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithWriter(buf).
+		WithJSONFormat().
+		Build())
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		controller := http.NewResponseController(w)
+		for i := 0; i < 3; i++ {
+			_, _ = w.Write([]byte("data: tick\n\n"))
+			if err := controller.Flush(); err != nil {
+				t.Errorf("flush failed: %v", err)
+			}
+		}
+	})
+	tracingConfig := DefaultTracingMiddlewareConfig()
+	tracingConfig.DisableStart = true
+	TracingMiddlewareWithConfig(logger, tracingConfig)(handler).
+		ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/events", nil))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid entry %q: %v", buf.String(), err)
+	}
+	if entry["flushes"] != float64(3) {
+		t.Errorf("expected 3 flushes, got %v", entry["flushes"])
+	}
+	if _, ok := entry["ttfb_ms"]; !ok {
+		t.Errorf("expected ttfb_ms on streamed response, got %v", entry)
+	}
+}
+
+// entryChan is a writer sending each write to a channel, for entries logged
+// on server goroutines.
+type entryChan chan []byte
+
+func (c entryChan) Write(p []byte) (int, error) {
+	c <- append([]byte(nil), p...)
+	return len(p), nil
+}
+
+func TestTracingMiddleware_Hijack(t *testing.T) {
+	entries := make(entryChan, 1)
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithWriter(entries).
+		WithJSONFormat().
+		Build())
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			t.Errorf("hijack failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: close\r\n\r\n")
+		_ = rw.Flush()
+	})
+	tracingConfig := DefaultTracingMiddlewareConfig()
+	tracingConfig.DisableStart = true
+	server := httptest.NewServer(TracingMiddlewareWithConfig(logger, tracingConfig)(handler))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	var entry map[string]interface{}
+	select {
+	case data := <-entries:
+		if err := json.Unmarshal(data, &entry); err != nil {
+			t.Fatalf("invalid entry %q: %v", data, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for completion entry")
+	}
+	if entry["hijacked"] != true || entry["status"] != float64(http.StatusSwitchingProtocols) {
+		t.Errorf("expected hijacked 101 entry, got %v", entry)
+	}
+}
+
 func TestMiddlewareConstants(t *testing.T) {
 	// Test that middleware header constants are defined
 	if HeaderTraceID == "" {
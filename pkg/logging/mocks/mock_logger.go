@@ -361,6 +361,20 @@ func (mr *MockLoggerMockRecorder) WithFields(fields any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithFields", reflect.TypeOf((*MockLogger)(nil).WithFields), fields)
 }
 
+// WithLevel mocks base method.
+func (m *MockLogger) WithLevel(level logging.Level) logging.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithLevel", level)
+	ret0, _ := ret[0].(logging.Logger)
+	return ret0
+}
+
+// WithLevel indicates an expected call of WithLevel.
+func (mr *MockLoggerMockRecorder) WithLevel(level any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLevel", reflect.TypeOf((*MockLogger)(nil).WithLevel), level)
+}
+
 // MockConfigurableLogger is a mock of ConfigurableLogger interface.
 type MockConfigurableLogger struct {
 	ctrl     *gomock.Controller
@@ -705,6 +719,245 @@ func (mr *MockConfigurableLoggerMockRecorder) WithFields(fields any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithFields", reflect.TypeOf((*MockConfigurableLogger)(nil).WithFields), fields)
 }
 
+// WithLevel mocks base method.
+func (m *MockConfigurableLogger) WithLevel(level logging.Level) logging.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithLevel", level)
+	ret0, _ := ret[0].(logging.Logger)
+	return ret0
+}
+
+// WithLevel indicates an expected call of WithLevel.
+func (mr *MockConfigurableLoggerMockRecorder) WithLevel(level any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLevel", reflect.TypeOf((*MockConfigurableLogger)(nil).WithLevel), level)
+}
+
+// MockInfoLogger is a mock of InfoLogger interface.
+type MockInfoLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockInfoLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockInfoLoggerMockRecorder is the mock recorder for MockInfoLogger.
+type MockInfoLoggerMockRecorder struct {
+	mock *MockInfoLogger
+}
+
+// NewMockInfoLogger creates a new mock instance.
+func NewMockInfoLogger(ctrl *gomock.Controller) *MockInfoLogger {
+	mock := &MockInfoLogger{ctrl: ctrl}
+	mock.recorder = &MockInfoLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInfoLogger) EXPECT() *MockInfoLoggerMockRecorder {
+	return m.recorder
+}
+
+// Info mocks base method.
+func (m *MockInfoLogger) Info(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Info", varargs...)
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockInfoLoggerMockRecorder) Info(msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockInfoLogger)(nil).Info), varargs...)
+}
+
+// MockErrorLogger is a mock of ErrorLogger interface.
+type MockErrorLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockErrorLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockErrorLoggerMockRecorder is the mock recorder for MockErrorLogger.
+type MockErrorLoggerMockRecorder struct {
+	mock *MockErrorLogger
+}
+
+// NewMockErrorLogger creates a new mock instance.
+func NewMockErrorLogger(ctrl *gomock.Controller) *MockErrorLogger {
+	mock := &MockErrorLogger{ctrl: ctrl}
+	mock.recorder = &MockErrorLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockErrorLogger) EXPECT() *MockErrorLoggerMockRecorder {
+	return m.recorder
+}
+
+// Error mocks base method.
+func (m *MockErrorLogger) Error(msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Error", varargs...)
+}
+
+// Error indicates an expected call of Error.
+func (mr *MockErrorLoggerMockRecorder) Error(msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockErrorLogger)(nil).Error), varargs...)
+}
+
+// MockContextAwareLogger is a mock of ContextAwareLogger interface.
+type MockContextAwareLogger struct {
+	ctrl     *gomock.Controller
+	recorder *MockContextAwareLoggerMockRecorder
+	isgomock struct{}
+}
+
+// MockContextAwareLoggerMockRecorder is the mock recorder for MockContextAwareLogger.
+type MockContextAwareLoggerMockRecorder struct {
+	mock *MockContextAwareLogger
+}
+
+// NewMockContextAwareLogger creates a new mock instance.
+func NewMockContextAwareLogger(ctrl *gomock.Controller) *MockContextAwareLogger {
+	mock := &MockContextAwareLogger{ctrl: ctrl}
+	mock.recorder = &MockContextAwareLoggerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContextAwareLogger) EXPECT() *MockContextAwareLoggerMockRecorder {
+	return m.recorder
+}
+
+// CriticalContext mocks base method.
+func (m *MockContextAwareLogger) CriticalContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "CriticalContext", varargs...)
+}
+
+// CriticalContext indicates an expected call of CriticalContext.
+func (mr *MockContextAwareLoggerMockRecorder) CriticalContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CriticalContext", reflect.TypeOf((*MockContextAwareLogger)(nil).CriticalContext), varargs...)
+}
+
+// DebugContext mocks base method.
+func (m *MockContextAwareLogger) DebugContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "DebugContext", varargs...)
+}
+
+// DebugContext indicates an expected call of DebugContext.
+func (mr *MockContextAwareLoggerMockRecorder) DebugContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugContext", reflect.TypeOf((*MockContextAwareLogger)(nil).DebugContext), varargs...)
+}
+
+// ErrorContext mocks base method.
+func (m *MockContextAwareLogger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "ErrorContext", varargs...)
+}
+
+// ErrorContext indicates an expected call of ErrorContext.
+func (mr *MockContextAwareLoggerMockRecorder) ErrorContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ErrorContext", reflect.TypeOf((*MockContextAwareLogger)(nil).ErrorContext), varargs...)
+}
+
+// InfoContext mocks base method.
+func (m *MockContextAwareLogger) InfoContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "InfoContext", varargs...)
+}
+
+// InfoContext indicates an expected call of InfoContext.
+func (mr *MockContextAwareLoggerMockRecorder) InfoContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InfoContext", reflect.TypeOf((*MockContextAwareLogger)(nil).InfoContext), varargs...)
+}
+
+// LogContext mocks base method.
+func (m *MockContextAwareLogger) LogContext(ctx context.Context, level logging.Level, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, level, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "LogContext", varargs...)
+}
+
+// LogContext indicates an expected call of LogContext.
+func (mr *MockContextAwareLoggerMockRecorder) LogContext(ctx, level, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, level, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogContext", reflect.TypeOf((*MockContextAwareLogger)(nil).LogContext), varargs...)
+}
+
+// TraceContext mocks base method.
+func (m *MockContextAwareLogger) TraceContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "TraceContext", varargs...)
+}
+
+// TraceContext indicates an expected call of TraceContext.
+func (mr *MockContextAwareLoggerMockRecorder) TraceContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceContext", reflect.TypeOf((*MockContextAwareLogger)(nil).TraceContext), varargs...)
+}
+
+// WarnContext mocks base method.
+func (m *MockContextAwareLogger) WarnContext(ctx context.Context, msg string, args ...any) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, msg}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "WarnContext", varargs...)
+}
+
+// WarnContext indicates an expected call of WarnContext.
+func (mr *MockContextAwareLoggerMockRecorder) WarnContext(ctx, msg any, args ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, msg}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WarnContext", reflect.TypeOf((*MockContextAwareLogger)(nil).WarnContext), varargs...)
+}
+
 // MockFormatter is a mock of Formatter interface.
 type MockFormatter struct {
 	ctrl     *gomock.Controller
@@ -796,6 +1049,72 @@ func (mr *MockOutputMockRecorder) Write(data any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockOutput)(nil).Write), data)
 }
 
+// MockEntryOutput is a mock of EntryOutput interface.
+type MockEntryOutput struct {
+	ctrl     *gomock.Controller
+	recorder *MockEntryOutputMockRecorder
+	isgomock struct{}
+}
+
+// MockEntryOutputMockRecorder is the mock recorder for MockEntryOutput.
+type MockEntryOutputMockRecorder struct {
+	mock *MockEntryOutput
+}
+
+// NewMockEntryOutput creates a new mock instance.
+func NewMockEntryOutput(ctrl *gomock.Controller) *MockEntryOutput {
+	mock := &MockEntryOutput{ctrl: ctrl}
+	mock.recorder = &MockEntryOutputMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEntryOutput) EXPECT() *MockEntryOutputMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockEntryOutput) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockEntryOutputMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockEntryOutput)(nil).Close))
+}
+
+// Write mocks base method.
+func (m *MockEntryOutput) Write(data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Write", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Write indicates an expected call of Write.
+func (mr *MockEntryOutputMockRecorder) Write(data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Write", reflect.TypeOf((*MockEntryOutput)(nil).Write), data)
+}
+
+// WriteEntry mocks base method.
+func (m *MockEntryOutput) WriteEntry(entry logging.LogEntry, data []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteEntry", entry, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteEntry indicates an expected call of WriteEntry.
+func (mr *MockEntryOutputMockRecorder) WriteEntry(entry, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteEntry", reflect.TypeOf((*MockEntryOutput)(nil).WriteEntry), entry, data)
+}
+
 // MockBufferedOutputInterface is a mock of BufferedOutputInterface interface.
 type MockBufferedOutputInterface struct {
 	ctrl     *gomock.Controller
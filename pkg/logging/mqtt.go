@@ -0,0 +1,515 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// errMQTTOutputDisconnected is reported for buffered entries discarded when
+// an MQTTOutput closes before reconnecting.
+var errMQTTOutputDisconnected = errors.New("mqtt output is disconnected")
+
+// MQTT 3.1.1 control packet types, shifted into the fixed header.
+const (
+	mqttConnect    byte = 1 << 4
+	mqttConnack    byte = 2 << 4
+	mqttPublish    byte = 3 << 4
+	mqttPuback     byte = 4 << 4
+	mqttPubrec     byte = 5 << 4
+	mqttPubrel     byte = 6<<4 | 0x02
+	mqttPubcomp    byte = 7 << 4
+	mqttPingreq    byte = 12 << 4
+	mqttPingresp   byte = 13 << 4
+	mqttDisconnect byte = 14 << 4
+)
+
+// MQTTWill is the last will and testament the broker publishes if the
+// output's connection is lost without a clean disconnect, e.g. "offline" on
+// a device status topic.
+type MQTTWill struct {
+	Topic   string
+	Payload []byte
+	QoS     byte
+	Retain  bool
+}
+
+// MQTTConfig configures an MQTTOutput.
+type MQTTConfig struct {
+	// Address is the broker, e.g. "broker:1883" or "broker:8883" with
+	// TLSConfig.
+	Address string
+	// TLSConfig enables TLS.
+	TLSConfig *tls.Config
+	// ClientID identifies the connection. Defaults to
+	// "go-logging-<hostname>-<pid>".
+	ClientID string
+	// Username and Password authenticate with the broker, if set.
+	Username string
+	Password string
+	// Topic receives every entry, e.g. "devices/sensor-7/logs".
+	Topic string
+	// QoS is the MQTT quality of service: 0 (at most once), 1 (at least
+	// once) or 2 (exactly once).
+	QoS byte
+	// Retain asks the broker to keep the last entry for new subscribers.
+	Retain bool
+	// Will, if set, is registered with the broker on connect.
+	Will *MQTTWill
+	// KeepAlive is the interval of pings on an idle connection. Defaults to
+	// 30s.
+	KeepAlive time.Duration
+	// DialTimeout bounds each connection attempt. Defaults to 5s.
+	DialTimeout time.Duration
+	// AckTimeout bounds the wait for the broker to acknowledge a connect,
+	// ping or QoS 1 or 2 publish. Defaults to 10s.
+	AckTimeout time.Duration
+	// BufferSize is the number of entries kept while offline or waiting to
+	// be published; the oldest are dropped beyond it. Defaults to 1000.
+	BufferSize int
+	// ReconnectDelay is the first wait between connection attempts; it
+	// doubles up to MaxReconnectDelay. Defaults to 500ms and 30s.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	// OnError is called with the number of entries that were dropped.
+	OnError func(entries int, err error)
+}
+
+// MQTTOutput publishes formatted entries to an MQTT broker topic, for edge
+// and IoT devices that forward logs over MQTT rather than HTTP. It speaks
+// MQTT 3.1.1 over TCP or TLS itself, without a client dependency. Entries
+// are buffered and published in order by a background connection that
+// reconnects with backoff, so they survive the device going offline; with
+// QoS 1 or 2 an entry leaves the buffer only once the broker acknowledges
+// it.
+//
+// Example:
+//
+//	output, err := logging.NewMQTTOutput(logging.MQTTConfig{
+//		Address: "broker.local:1883",
+//		Topic:   "devices/sensor-7/logs",
+//		QoS:     1,
+//		Will:    &logging.MQTTWill{Topic: "devices/sensor-7/status", Payload: []byte("offline"), Retain: true},
+//	})
+type MQTTOutput struct {
+	config MQTTConfig
+
+	mu        sync.Mutex
+	buffer    [][]byte
+	connected bool
+	closed    bool
+	packetID  uint16
+
+	wake    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewMQTTOutput creates an MQTTOutput and starts connecting to the broker.
+// The broker being unreachable is not an error; entries are buffered until
+// it is.
+func NewMQTTOutput(config MQTTConfig) (*MQTTOutput, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("mqtt output requires a broker address")
+	}
+	if config.Topic == "" {
+		return nil, fmt.Errorf("mqtt output requires a topic")
+	}
+	if config.QoS > 2 {
+		return nil, fmt.Errorf("invalid mqtt QoS %d", config.QoS)
+	}
+	if config.Will != nil && (config.Will.Topic == "" || config.Will.QoS > 2) {
+		return nil, fmt.Errorf("mqtt will requires a topic and a QoS of 0, 1 or 2")
+	}
+	if config.ClientID == "" {
+		hostname, _ := os.Hostname()
+		config.ClientID = "go-logging-" + hostname + "-" + strconv.Itoa(os.Getpid())
+	}
+	if config.KeepAlive <= 0 {
+		config.KeepAlive = 30 * time.Second
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+	if config.AckTimeout <= 0 {
+		config.AckTimeout = 10 * time.Second
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1000
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = 500 * time.Millisecond
+	}
+	if config.MaxReconnectDelay <= 0 {
+		config.MaxReconnectDelay = 30 * time.Second
+	}
+
+	mo := &MQTTOutput{
+		config:  config,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go mo.run()
+	return mo, nil
+}
+
+// Write queues data, without a trailing newline, for publishing.
+func (mo *MQTTOutput) Write(data []byte) error {
+	payload := append([]byte(nil), bytes.TrimRight(data, "\r\n")...)
+
+	mo.mu.Lock()
+	if mo.closed {
+		mo.mu.Unlock()
+		return fmt.Errorf("mqtt output is closed")
+	}
+	mo.buffer = append(mo.buffer, payload)
+	dropped := len(mo.buffer) - mo.config.BufferSize
+	if dropped > 0 {
+		mo.buffer = append(mo.buffer[:0], mo.buffer[dropped:]...)
+	}
+	mo.mu.Unlock()
+
+	select {
+	case mo.wake <- struct{}{}:
+	default:
+	}
+	if dropped > 0 {
+		mo.reportError(dropped, ErrQueueFull)
+	}
+	return nil
+}
+
+// run connects with backoff and publishes until the output closes.
+func (mo *MQTTOutput) run() {
+	defer close(mo.stopped)
+	delay := mo.config.ReconnectDelay
+
+	for {
+		conn, err := mo.connect()
+		if err == nil {
+			delay = mo.config.ReconnectDelay
+			err = mo.session(conn)
+			_ = conn.Close()
+			mo.setConnected(false)
+			if err == nil {
+				return
+			}
+		}
+
+		select {
+		case <-mo.done:
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > mo.config.MaxReconnectDelay {
+			delay = mo.config.MaxReconnectDelay
+		}
+	}
+}
+
+// connect dials the broker and completes the MQTT handshake.
+func (mo *MQTTOutput) connect() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: mo.config.DialTimeout}
+	var conn net.Conn
+	var err error
+	if mo.config.TLSConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", mo.config.Address, mo.config.TLSConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", mo.config.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(mo.config.AckTimeout))
+	if _, err := conn.Write(mo.connectPacket()); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	packetType, body, err := readMQTTPacket(bufio.NewReader(conn))
+	if err == nil && (packetType != mqttConnack || len(body) != 2) {
+		err = fmt.Errorf("unexpected mqtt packet %#x instead of CONNACK", packetType)
+	}
+	if err == nil && body[1] != 0 {
+		err = fmt.Errorf("mqtt broker refused connection with code %d", body[1])
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	mo.setConnected(true)
+	return conn, nil
+}
+
+// connectPacket returns the CONNECT packet, with a clean session.
+func (mo *MQTTOutput) connectPacket() []byte {
+	flags := byte(0x02)
+	var payload []byte
+	payload = appendMQTTString(payload, []byte(mo.config.ClientID))
+	if will := mo.config.Will; will != nil {
+		flags |= 0x04 | will.QoS<<3
+		if will.Retain {
+			flags |= 0x20
+		}
+		payload = appendMQTTString(payload, []byte(will.Topic))
+		payload = appendMQTTString(payload, will.Payload)
+	}
+	if mo.config.Username != "" {
+		flags |= 0x80
+		payload = appendMQTTString(payload, []byte(mo.config.Username))
+	}
+	if mo.config.Password != "" {
+		flags |= 0x40
+		payload = appendMQTTString(payload, []byte(mo.config.Password))
+	}
+
+	body := appendMQTTString(nil, []byte("MQTT"))
+	body = append(body, 4, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(mo.config.KeepAlive/time.Second))
+	return mqttPacket(mqttConnect, append(body, payload...))
+}
+
+// session publishes buffered entries over conn, pinging while idle. It
+// returns nil after a clean disconnect once the output closes and the
+// buffer is empty.
+func (mo *MQTTOutput) session(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	idle := time.NewTimer(mo.config.KeepAlive)
+	defer idle.Stop()
+
+	for {
+		if payload, ok := mo.next(); ok {
+			if err := mo.publish(conn, r, payload); err != nil {
+				return err
+			}
+			mo.pop()
+			idle.Reset(mo.config.KeepAlive)
+			continue
+		}
+
+		select {
+		case <-mo.wake:
+		case <-mo.done:
+			_ = conn.SetWriteDeadline(time.Now().Add(mo.config.AckTimeout))
+			_, _ = conn.Write([]byte{mqttDisconnect, 0})
+			return nil
+		case <-idle.C:
+			if err := mo.ping(conn, r); err != nil {
+				return err
+			}
+			idle.Reset(mo.config.KeepAlive)
+		}
+	}
+}
+
+// publish sends payload and waits for the acknowledgements its QoS needs.
+func (mo *MQTTOutput) publish(conn net.Conn, r *bufio.Reader, payload []byte) error {
+	header := mqttPublish | mo.config.QoS<<1
+	if mo.config.Retain {
+		header |= 0x01
+	}
+	body := appendMQTTString(nil, []byte(mo.config.Topic))
+	var id uint16
+	if mo.config.QoS > 0 {
+		id = mo.nextPacketID()
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	body = append(body, payload...)
+
+	_ = conn.SetDeadline(time.Now().Add(mo.config.AckTimeout))
+	defer conn.SetDeadline(time.Time{})
+	if _, err := conn.Write(mqttPacket(header, body)); err != nil {
+		return err
+	}
+
+	switch mo.config.QoS {
+	case 1:
+		return awaitMQTTAck(r, mqttPuback, id)
+	case 2:
+		if err := awaitMQTTAck(r, mqttPubrec, id); err != nil {
+			return err
+		}
+		if _, err := conn.Write(mqttPacket(mqttPubrel, binary.BigEndian.AppendUint16(nil, id))); err != nil {
+			return err
+		}
+		return awaitMQTTAck(r, mqttPubcomp, id)
+	}
+	return nil
+}
+
+// ping sends PINGREQ and waits for PINGRESP.
+func (mo *MQTTOutput) ping(conn net.Conn, r *bufio.Reader) error {
+	_ = conn.SetDeadline(time.Now().Add(mo.config.AckTimeout))
+	defer conn.SetDeadline(time.Time{})
+	if _, err := conn.Write([]byte{mqttPingreq, 0}); err != nil {
+		return err
+	}
+	for {
+		packetType, _, err := readMQTTPacket(r)
+		if err != nil {
+			return err
+		}
+		if packetType == mqttPingresp {
+			return nil
+		}
+	}
+}
+
+// awaitMQTTAck reads packets until the acknowledgement of type want for
+// packet id arrives, skipping others such as PINGRESP.
+func awaitMQTTAck(r *bufio.Reader, want byte, id uint16) error {
+	for {
+		packetType, body, err := readMQTTPacket(r)
+		if err != nil {
+			return err
+		}
+		if packetType&0xf0 == want&0xf0 && len(body) >= 2 && binary.BigEndian.Uint16(body) == id {
+			return nil
+		}
+	}
+}
+
+func (mo *MQTTOutput) nextPacketID() uint16 {
+	mo.packetID++
+	if mo.packetID == 0 {
+		mo.packetID = 1
+	}
+	return mo.packetID
+}
+
+// next returns the oldest buffered entry without removing it.
+func (mo *MQTTOutput) next() ([]byte, bool) {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	if len(mo.buffer) == 0 {
+		return nil, false
+	}
+	return mo.buffer[0], true
+}
+
+// pop removes the oldest buffered entry once it has been published.
+func (mo *MQTTOutput) pop() {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	if len(mo.buffer) > 0 {
+		mo.buffer[0] = nil
+		mo.buffer = mo.buffer[1:]
+	}
+}
+
+func (mo *MQTTOutput) setConnected(connected bool) {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	mo.connected = connected
+}
+
+// mqttPacket returns a packet with header and body, encoding the remaining
+// length.
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// appendMQTTString appends s with its two-byte length prefix.
+func appendMQTTString(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// readMQTTPacket reads one packet, returning its fixed header byte and
+// body.
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, fmt.Errorf("malformed mqtt remaining length")
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func (mo *MQTTOutput) reportError(entries int, err error) {
+	if mo.config.OnError != nil {
+		mo.config.OnError(entries, err)
+	}
+}
+
+// Connected reports whether the output currently has a broker connection.
+func (mo *MQTTOutput) Connected() bool {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	return mo.connected
+}
+
+// Buffered returns the number of entries waiting to be published.
+func (mo *MQTTOutput) Buffered() int {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+	return len(mo.buffer)
+}
+
+// Close publishes the buffered entries if connected, then disconnects
+// cleanly, so the broker does not publish the will. Entries that could not
+// be published are dropped and reported to OnError.
+func (mo *MQTTOutput) Close() error {
+	mo.mu.Lock()
+	if mo.closed {
+		mo.mu.Unlock()
+		return nil
+	}
+	mo.closed = true
+	close(mo.done)
+	mo.mu.Unlock()
+
+	<-mo.stopped
+
+	mo.mu.Lock()
+	dropped := len(mo.buffer)
+	mo.buffer = nil
+	mo.mu.Unlock()
+	if dropped > 0 {
+		mo.reportError(dropped, errMQTTOutputDisconnected)
+	}
+	return nil
+}
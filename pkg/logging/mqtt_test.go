@@ -0,0 +1,258 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mqttBroker is a minimal MQTT broker recording the CONNECT and PUBLISH
+// packets it receives. It refuses the first refuse connections.
+type mqttBroker struct {
+	listener     net.Listener
+	refuse       int
+	disconnected chan struct{}
+
+	mu        sync.Mutex
+	connects  [][]byte
+	published []mqttPublished
+	packets   []byte
+}
+
+type mqttPublished struct {
+	header  byte
+	topic   string
+	payload string
+}
+
+func newMQTTBroker(t *testing.T, refuse int) *mqttBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	b := &mqttBroker{listener: listener, refuse: refuse, disconnected: make(chan struct{}, 1)}
+	t.Cleanup(func() { _ = listener.Close() })
+	go b.serve()
+	return b
+}
+
+func (b *mqttBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *mqttBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		header, body, err := readMQTTPacket(r)
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		b.packets = append(b.packets, header)
+		b.mu.Unlock()
+
+		switch header & 0xf0 {
+		case mqttConnect:
+			b.mu.Lock()
+			b.connects = append(b.connects, body)
+			code := byte(0)
+			if b.refuse > 0 {
+				b.refuse--
+				code = 3
+			}
+			b.mu.Unlock()
+			_, _ = conn.Write(mqttPacket(mqttConnack, []byte{0, code}))
+			if code != 0 {
+				return
+			}
+		case mqttPublish:
+			topicLength := int(binary.BigEndian.Uint16(body))
+			topic := string(body[2 : 2+topicLength])
+			rest := body[2+topicLength:]
+			qos := header >> 1 & 0x03
+			if qos > 0 {
+				id := rest[:2]
+				rest = rest[2:]
+				if qos == 1 {
+					_, _ = conn.Write(mqttPacket(mqttPuback, id))
+				} else {
+					_, _ = conn.Write(mqttPacket(mqttPubrec, id))
+				}
+			}
+			b.mu.Lock()
+			b.published = append(b.published, mqttPublished{header: header, topic: topic, payload: string(rest)})
+			b.mu.Unlock()
+		case mqttPubrel & 0xf0:
+			_, _ = conn.Write(mqttPacket(mqttPubcomp, body))
+		case mqttPingreq:
+			_, _ = conn.Write([]byte{mqttPingresp, 0})
+		case mqttDisconnect:
+			b.disconnected <- struct{}{}
+			return
+		}
+	}
+}
+
+// waitDisconnect waits for a client to disconnect cleanly.
+func (b *mqttBroker) waitDisconnect(t *testing.T) {
+	t.Helper()
+	select {
+	case <-b.disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for disconnect")
+	}
+}
+
+func (b *mqttBroker) snapshot() ([][]byte, []mqttPublished, []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([][]byte(nil), b.connects...), append([]mqttPublished(nil), b.published...), append([]byte(nil), b.packets...)
+}
+
+func TestNewMQTTOutput_Validation(t *testing.T) {
+	tests := []MQTTConfig{
+		{Topic: "logs"},
+		{Address: "broker:1883"},
+		{Address: "broker:1883", Topic: "logs", QoS: 3},
+		{Address: "broker:1883", Topic: "logs", Will: &MQTTWill{}},
+	}
+	for _, config := range tests {
+		if _, err := NewMQTTOutput(config); err == nil {
+			t.Errorf("expected error for %+v", config)
+		}
+	}
+}
+
+func TestMQTTPacket_RemainingLength(t *testing.T) {
+	packet := mqttPacket(mqttPublish, make([]byte, 321))
+	if packet[1] != 0xc1 || packet[2] != 0x02 {
+		t.Errorf("unexpected remaining length encoding % x", packet[1:3])
+	}
+	header, body, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(packet)))
+	if err != nil || header != mqttPublish || len(body) != 321 {
+		t.Errorf("round trip failed: header=%#x len=%d err=%v", header, len(body), err)
+	}
+}
+
+func TestMQTTOutput_PublishQoS(t *testing.T) {
+	for _, qos := range []byte{0, 1, 2} {
+		broker := newMQTTBroker(t, 0)
+		output, err := NewMQTTOutput(MQTTConfig{
+			Address:  broker.listener.Addr().String(),
+			ClientID: "sensor-7",
+			Username: "device",
+			Password: "secret",
+			Topic:    "devices/sensor-7/logs",
+			QoS:      qos,
+			Retain:   true,
+			Will:     &MQTTWill{Topic: "devices/sensor-7/status", Payload: []byte("offline"), QoS: 1, Retain: true},
+		})
+		if err != nil {
+			t.Fatalf("failed to create mqtt output: %v", err)
+		}
+		_ = output.Write([]byte("first\n"))
+		_ = output.Write([]byte("second\n"))
+		if err := output.Close(); err != nil {
+			t.Fatalf("close failed: %v", err)
+		}
+		broker.waitDisconnect(t)
+
+		connects, published, packets := broker.snapshot()
+		if len(connects) != 1 {
+			t.Fatalf("QoS %d: expected 1 connect, got %d", qos, len(connects))
+		}
+		connect := connects[0]
+		if flags := connect[7]; flags != 0x80|0x40|0x20|1<<3|0x04|0x02 {
+			t.Errorf("QoS %d: unexpected connect flags %#x", qos, flags)
+		}
+		if len(published) != 2 || published[0].payload != "first" || published[1].payload != "second" {
+			t.Fatalf("QoS %d: unexpected publishes %+v", qos, published)
+		}
+		if published[0].topic != "devices/sensor-7/logs" || published[0].header != mqttPublish|qos<<1|0x01 {
+			t.Errorf("QoS %d: unexpected publish %+v", qos, published[0])
+		}
+		if last := packets[len(packets)-1]; last != mqttDisconnect {
+			t.Errorf("QoS %d: expected clean disconnect, got %#x", qos, last)
+		}
+	}
+}
+
+func TestMQTTOutput_OfflineBuffering(t *testing.T) {
+	broker := newMQTTBroker(t, 2)
+	output, err := NewMQTTOutput(MQTTConfig{
+		Address:        broker.listener.Addr().String(),
+		Topic:          "logs",
+		QoS:            1,
+		ReconnectDelay: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("failed to create mqtt output: %v", err)
+	}
+	for _, entry := range []string{"a", "b", "c"} {
+		_ = output.Write([]byte(entry))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for output.Buffered() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !output.Connected() {
+		t.Error("expected output to reconnect")
+	}
+	_ = output.Close()
+	broker.waitDisconnect(t)
+
+	connects, published, _ := broker.snapshot()
+	if len(connects) != 3 {
+		t.Errorf("expected 3 connection attempts, got %d", len(connects))
+	}
+	if len(published) != 3 || published[0].payload != "a" || published[2].payload != "c" {
+		t.Errorf("unexpected publishes %+v", published)
+	}
+}
+
+func TestMQTTOutput_BufferOverflow(t *testing.T) {
+	var mu sync.Mutex
+	var dropped int
+	output, err := NewMQTTOutput(MQTTConfig{
+		Address:        "127.0.0.1:1",
+		Topic:          "logs",
+		BufferSize:     2,
+		ReconnectDelay: time.Hour,
+		OnError: func(entries int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			dropped += entries
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create mqtt output: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		_ = output.Write([]byte("entry"))
+	}
+	if got := output.Buffered(); got != 2 {
+		t.Errorf("expected 2 buffered entries, got %d", got)
+	}
+	_ = output.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if dropped != 5 {
+		t.Errorf("expected 5 dropped entries, got %d", dropped)
+	}
+	if err := output.Write([]byte("late")); err == nil {
+		t.Error("expected error writing to closed output")
+	}
+}
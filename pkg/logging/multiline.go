@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// multilineIndent indents the continuation lines of text and console
+// output.
+const multilineIndent = "    "
+
+// multilineField is a field whose value spans several lines, written
+// beneath the entry's line.
+type multilineField struct {
+	key  string
+	text string
+}
+
+// multilineText returns the text of a field value and whether it spans
+// several lines, as an error with a stack trace printed by %+v or a stack
+// field from runtime/debug.Stack does. Other values report false.
+func multilineText(v interface{}) (string, bool) {
+	var text string
+	switch value := v.(type) {
+	case error:
+		text = fmt.Sprintf("%+v", value)
+	case string:
+		text = value
+	case fmt.Stringer:
+		text = value.String()
+	default:
+		return "", false
+	}
+	text = strings.TrimRight(text, "\n")
+	return text, strings.Contains(text, "\n")
+}
+
+// collapseNewlines replaces the line breaks of text with \n, keeping a
+// multi-line value on the entry's line.
+var collapseNewlines = strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// appendMultilineFields appends each field on continuation lines: the key
+// indented on its own line, then each line of the value indented beneath
+// it.
+func appendMultilineFields(buf []byte, fields []multilineField) []byte {
+	for _, field := range fields {
+		buf = append(buf, '\n')
+		buf = append(buf, multilineIndent...)
+		buf = append(buf, field.key...)
+		buf = append(buf, ':')
+		for _, line := range strings.Split(field.text, "\n") {
+			buf = append(buf, '\n')
+			buf = append(buf, multilineIndent+multilineIndent...)
+			buf = append(buf, strings.TrimRight(line, "\r")...)
+		}
+	}
+	return buf
+}
+
+// CollapseMultiline writes multi-line field values on the entry's line.
+func (b *FormatterConfigBuilder) CollapseMultiline(collapse bool) *FormatterConfigBuilder {
+	b.config.CollapseMultiline = collapse
+	return b
+}
+
+// CollapseMultiline sets whether text and console output write field
+// values that span several lines, such as errors with stack traces and
+// stack fields, on the entry's line with their line breaks escaped as \n.
+// By default they are written on indented lines beneath the entry, which
+// reads well in a terminal; collapse them in production so that every
+// entry stays one line for line-based log collectors.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithTextFormat().
+//		CollapseMultiline(true).
+//		Build()
+func (b *LoggerConfigBuilder) CollapseMultiline(collapse bool) *LoggerConfigBuilder {
+	b.config.Formatter.CollapseMultiline = collapse
+	return b
+}
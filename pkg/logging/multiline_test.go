@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// stackError prints a stack trace with %+v, like github.com/pkg/errors.
+type stackError struct{ msg string }
+
+func (e stackError) Error() string { return e.msg }
+
+func (e stackError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		_, _ = io.WriteString(s, e.msg+"\nmain.charge\n\t/src/main.go:42\n")
+		return
+	}
+	_, _ = io.WriteString(s, e.msg)
+}
+
+func TestTextFormatter_MultilineFields(t *testing.T) {
+	entry := LogEntry{
+		Level:   ErrorLevel,
+		Message: "payment failed",
+		Fields: map[string]interface{}{
+			"error":    stackError{msg: "card declined"},
+			"order_id": 42,
+			"cause":    errors.New("timeout"),
+		},
+	}
+	config := NewFormatterConfig().IncludeTime(false).IncludeFile(false)
+
+	data, _ := NewTextFormatter(config.Build()).Format(entry)
+	want := "[ERROR] payment failed {cause=timeout order_id=42}\n" +
+		"    error:\n" +
+		"        card declined\n" +
+		"        main.charge\n" +
+		"        \t/src/main.go:42\n"
+	if string(data) != want {
+		t.Errorf("unexpected output\n got: %q\nwant: %q", data, want)
+	}
+
+	data, _ = NewTextFormatter(config.CollapseMultiline(true).Build()).Format(entry)
+	want = `[ERROR] payment failed {cause=timeout error=card declined\nmain.charge\n` + "\t/src/main.go:42 order_id=42}\n"
+	if string(data) != want {
+		t.Errorf("unexpected collapsed output\n got: %q\nwant: %q", data, want)
+	}
+}
+
+func TestConsoleFormatter_MultilineFields(t *testing.T) {
+	entry := LogEntry{
+		Level:   ErrorLevel,
+		Message: "panic recovered",
+		Fields:  map[string]interface{}{"stack": "goroutine 1 [running]:\nmain.main()\n"},
+	}
+	config := NewFormatterConfig().IncludeTime(false)
+
+	data, _ := NewConsoleFormatter(config.Build(), false).Format(entry)
+	if want := "[ERROR] panic recovered\n    stack:\n        goroutine 1 [running]:\n        main.main()\n"; string(data) != want {
+		t.Errorf("unexpected output\n got: %q\nwant: %q", data, want)
+	}
+
+	data, _ = NewConsoleFormatter(config.CollapseMultiline(true).Build(), false).Format(entry)
+	if want := `[ERROR] panic recovered stack=goroutine 1 [running]:\nmain.main()` + "\n"; string(data) != want {
+		t.Errorf("unexpected collapsed output\n got: %q\nwant: %q", data, want)
+	}
+}
+
+func TestCollapseMultiline_YAML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "text", CollapseMultiline: true}); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	NewWithLoggerConfig(builder.WithWriter(buf).Build()).WithField("error", stackError{msg: "boom"}).Error("failed")
+	if got := buf.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, `boom\nmain.charge`) {
+		t.Errorf("expected one collapsed line, got %q", got)
+	}
+}
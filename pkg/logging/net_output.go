@@ -0,0 +1,267 @@
+package logging
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// errNetOutputDisconnected is reported for buffered entries discarded when
+// a NetOutput closes before reconnecting.
+var errNetOutputDisconnected = errors.New("network output is disconnected")
+
+// NetConfig configures a NetOutput.
+type NetConfig struct {
+	// Network is "tcp", "udp", "unix" or "unixgram". Defaults to "tcp".
+	Network string
+	// Address is the endpoint, e.g. "collector:5170" or "/run/collector.sock".
+	Address string
+	// TLSConfig enables TLS on TCP connections.
+	TLSConfig *tls.Config
+	// DialTimeout bounds each connection attempt. Defaults to 5s.
+	DialTimeout time.Duration
+	// WriteTimeout is the deadline for each write. Defaults to 5s.
+	WriteTimeout time.Duration
+	// BufferSize is the number of entries kept while disconnected; the
+	// oldest are dropped beyond it. Defaults to 1000.
+	BufferSize int
+	// ReconnectDelay is the first wait between connection attempts; it
+	// doubles up to MaxReconnectDelay. Defaults to 500ms and 30s.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	// OnError is called with the number of entries that were dropped.
+	OnError func(entries int, err error)
+}
+
+// NetOutput writes newline-delimited formatted entries to a TCP, UDP or
+// Unix socket endpoint, such as a log collector's raw input. When the
+// connection fails, entries are kept in a bounded buffer while the output
+// reconnects in the background with backoff, then sent in order. The
+// endpoint being down when the output is created is not an error.
+//
+// Example:
+//
+//	output, err := logging.NewNetOutput(logging.NetConfig{
+//		Network: "tcp",
+//		Address: "vector:9000",
+//	})
+type NetOutput struct {
+	config NetConfig
+
+	mu           sync.Mutex
+	conn         net.Conn
+	buffer       [][]byte
+	reconnecting bool
+	closed       bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewNetOutput creates a NetOutput and connects to the endpoint, retrying in
+// the background if the first attempt fails.
+func NewNetOutput(config NetConfig) (*NetOutput, error) {
+	if config.Address == "" {
+		return nil, fmt.Errorf("net output requires an address")
+	}
+	if config.Network == "" {
+		config.Network = "tcp"
+	}
+	switch config.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("unsupported network %q", config.Network)
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = 5 * time.Second
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 1000
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = 500 * time.Millisecond
+	}
+	if config.MaxReconnectDelay <= 0 {
+		config.MaxReconnectDelay = 30 * time.Second
+	}
+
+	no := &NetOutput{config: config, done: make(chan struct{})}
+	conn, err := no.dial()
+	no.mu.Lock()
+	defer no.mu.Unlock()
+	if err != nil {
+		no.startReconnect()
+	} else {
+		no.conn = conn
+	}
+	return no, nil
+}
+
+func (no *NetOutput) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: no.config.DialTimeout}
+	if no.config.TLSConfig != nil && no.config.Network == "tcp" {
+		return tls.DialWithDialer(dialer, "tcp", no.config.Address, no.config.TLSConfig)
+	}
+	return dialer.Dial(no.config.Network, no.config.Address)
+}
+
+// Write sends data, adding a trailing newline if it has none. While
+// disconnected, data is buffered instead.
+func (no *NetOutput) Write(data []byte) error {
+	line := make([]byte, len(data), len(data)+1)
+	copy(line, data)
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+
+	no.mu.Lock()
+	if no.closed {
+		no.mu.Unlock()
+		return fmt.Errorf("net output is closed")
+	}
+	if no.conn != nil {
+		err := no.writeLine(no.conn, line)
+		if err == nil {
+			no.mu.Unlock()
+			return nil
+		}
+		_ = no.conn.Close()
+		no.conn = nil
+	}
+	dropped := no.bufferLine(line)
+	no.startReconnect()
+	no.mu.Unlock()
+
+	if dropped > 0 {
+		no.reportError(dropped, ErrQueueFull)
+	}
+	return nil
+}
+
+func (no *NetOutput) writeLine(conn net.Conn, line []byte) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(no.config.WriteTimeout))
+	_, err := conn.Write(line)
+	return err
+}
+
+// bufferLine keeps line for sending after reconnecting and returns the
+// number of old entries dropped to make room. It must be called with mu
+// held.
+func (no *NetOutput) bufferLine(line []byte) int {
+	no.buffer = append(no.buffer, line)
+	dropped := len(no.buffer) - no.config.BufferSize
+	if dropped <= 0 {
+		return 0
+	}
+	no.buffer = append(no.buffer[:0], no.buffer[dropped:]...)
+	return dropped
+}
+
+// startReconnect starts the reconnect loop unless it is running. It must be
+// called with mu held.
+func (no *NetOutput) startReconnect() {
+	if no.reconnecting || no.closed {
+		return
+	}
+	no.reconnecting = true
+	no.wg.Add(1)
+	go no.reconnect()
+}
+
+// reconnect dials with backoff until it connects and sends the buffer, or
+// the output closes.
+func (no *NetOutput) reconnect() {
+	defer no.wg.Done()
+	delay := no.config.ReconnectDelay
+
+	for {
+		select {
+		case <-no.done:
+			return
+		case <-time.After(delay):
+		}
+
+		if conn, err := no.dial(); err == nil && no.resume(conn) {
+			return
+		}
+		delay *= 2
+		if delay > no.config.MaxReconnectDelay {
+			delay = no.config.MaxReconnectDelay
+		}
+	}
+}
+
+// resume sends the buffered entries over conn and makes it the output's
+// connection. It reports false, keeping the unsent entries, if a write
+// fails.
+func (no *NetOutput) resume(conn net.Conn) bool {
+	no.mu.Lock()
+	defer no.mu.Unlock()
+
+	for len(no.buffer) > 0 {
+		if err := no.writeLine(conn, no.buffer[0]); err != nil {
+			_ = conn.Close()
+			return false
+		}
+		no.buffer[0] = nil
+		no.buffer = no.buffer[1:]
+	}
+	no.buffer = nil
+	no.conn = conn
+	no.reconnecting = false
+	return true
+}
+
+func (no *NetOutput) reportError(entries int, err error) {
+	if no.config.OnError != nil {
+		no.config.OnError(entries, err)
+	}
+}
+
+// Connected reports whether the output currently has a connection.
+func (no *NetOutput) Connected() bool {
+	no.mu.Lock()
+	defer no.mu.Unlock()
+	return no.conn != nil
+}
+
+// Buffered returns the number of entries waiting for a connection.
+func (no *NetOutput) Buffered() int {
+	no.mu.Lock()
+	defer no.mu.Unlock()
+	return len(no.buffer)
+}
+
+// Close stops reconnecting and closes the connection. Entries still
+// buffered are dropped and reported to OnError.
+func (no *NetOutput) Close() error {
+	no.mu.Lock()
+	if no.closed {
+		no.mu.Unlock()
+		return nil
+	}
+	no.closed = true
+	close(no.done)
+	no.mu.Unlock()
+
+	no.wg.Wait()
+
+	no.mu.Lock()
+	defer no.mu.Unlock()
+	if dropped := len(no.buffer); dropped > 0 {
+		no.buffer = nil
+		no.reportError(dropped, errNetOutputDisconnected)
+	}
+	if no.conn == nil {
+		return nil
+	}
+	err := no.conn.Close()
+	no.conn = nil
+	return err
+}
@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewNetOutput_Validation(t *testing.T) {
+	if _, err := NewNetOutput(NetConfig{}); err == nil {
+		t.Error("expected error without an address")
+	}
+	if _, err := NewNetOutput(NetConfig{Network: "ipx", Address: "x"}); err == nil {
+		t.Error("expected error for an unsupported network")
+	}
+}
+
+// acceptLines reads newline-delimited lines from the first connection to ln.
+func acceptLines(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+	lines := make(chan string, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+func receiveLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a line")
+		return ""
+	}
+}
+
+func TestNetOutput_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	output, err := NewNetOutput(NetConfig{Address: ln.Addr().String()})
+	if err != nil {
+		t.Fatalf("failed to create net output: %v", err)
+	}
+	defer output.Close()
+
+	_ = output.Write([]byte(`{"message":"one"}` + "\n"))
+	_ = output.Write([]byte(`{"message":"two"}`))
+
+	if got := receiveLine(t, lines); got != `{"message":"one"}` {
+		t.Errorf("first line = %q", got)
+	}
+	if got := receiveLine(t, lines); got != `{"message":"two"}` {
+		t.Errorf("expected a newline to be added, got %q", got)
+	}
+}
+
+func TestNetOutput_BuffersUntilConnected(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "collector.sock")
+
+	var mu sync.Mutex
+	dropped := 0
+	output, err := NewNetOutput(NetConfig{
+		Network:        "unix",
+		Address:        socket,
+		BufferSize:     2,
+		ReconnectDelay: 10 * time.Millisecond,
+		OnError: func(entries int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			dropped += entries
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected an unreachable endpoint not to fail creation: %v", err)
+	}
+	defer output.Close()
+
+	for _, line := range []string{"one", "two", "three"} {
+		if err := output.Write([]byte(line)); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if output.Connected() || output.Buffered() != 2 {
+		t.Errorf("expected 2 buffered entries while disconnected, got %d", output.Buffered())
+	}
+	mu.Lock()
+	if dropped != 1 {
+		t.Errorf("expected the oldest entry to be dropped, got %d dropped", dropped)
+	}
+	mu.Unlock()
+
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+	lines := acceptLines(t, ln)
+
+	if got := receiveLine(t, lines); got != "two" {
+		t.Errorf("first buffered line = %q", got)
+	}
+	if got := receiveLine(t, lines); got != "three" {
+		t.Errorf("second buffered line = %q", got)
+	}
+	if !output.Connected() {
+		t.Error("expected the output to be connected")
+	}
+}
+
+func TestNetOutput_CloseDropsBuffer(t *testing.T) {
+	var dropped int
+	output, _ := NewNetOutput(NetConfig{
+		Network: "unix",
+		Address: filepath.Join(t.TempDir(), "missing.sock"),
+		OnError: func(entries int, err error) { dropped += entries },
+	})
+	_ = output.Write([]byte("lost"))
+	if err := output.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if dropped != 1 {
+		t.Errorf("expected the buffered entry to be reported, got %d", dropped)
+	}
+	if err := output.Write([]byte("late")); err == nil {
+		t.Error("expected an error writing after Close")
+	}
+}
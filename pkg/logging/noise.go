@@ -0,0 +1,160 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// NoiseConfig configures a NoiseDemoter.
+type NoiseConfig struct {
+	// Threshold is how many identical INFO messages are logged at INFO
+	// within Window; the rest are demoted to DEBUG. Defaults to 100.
+	Threshold int
+	// Window is the period over which messages are counted. A message stays
+	// demoted until a whole window passes with at most Threshold copies.
+	// Defaults to one second.
+	Window time.Duration
+	// NoticeInterval is how often an INFO notice reports that a message is
+	// being demoted. Defaults to one minute.
+	NoticeInterval time.Duration
+	// Allow lists regular expressions for messages that are never demoted.
+	Allow []string
+	// MaxTracked bounds the number of distinct messages counted at once;
+	// further messages are not demoted. Defaults to 1000.
+	MaxTracked int
+}
+
+// NoiseDemoter demotes INFO messages repeated at a very high rate to DEBUG,
+// so a hot loop logging the same line does not drown out the rest of the
+// INFO stream. It counts identical messages over a fixed window; no learning
+// or sampling is involved. While a message is demoted, an INFO notice with
+// noise_message and noise_demoted fields is logged once per NoticeInterval.
+//
+// Example:
+//
+//	demoter, err := logging.NewNoiseDemoter(logging.NoiseConfig{
+//		Threshold: 50,
+//		Allow:     []string{`^request completed`},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	config := logging.NewLoggerConfig().WithNoiseDemotion(demoter).Build()
+type NoiseDemoter struct {
+	config   NoiseConfig
+	allow    []*regexp.Regexp
+	mu       sync.Mutex
+	messages map[string]*noiseState
+}
+
+// noiseState counts one message.
+type noiseState struct {
+	windowStart time.Time
+	count       int
+	noisy       bool
+	demoted     int
+	lastNotice  time.Time
+}
+
+// noiseNoticeMessage is the message of the notice logged for demoted entries.
+const noiseNoticeMessage = "demoting repeated INFO message to DEBUG"
+
+// NewNoiseDemoter creates a NoiseDemoter. It returns an error if an Allow
+// pattern is not a valid regular expression.
+func NewNoiseDemoter(config NoiseConfig) (*NoiseDemoter, error) {
+	if config.Threshold <= 0 {
+		config.Threshold = 100
+	}
+	if config.Window <= 0 {
+		config.Window = time.Second
+	}
+	if config.NoticeInterval <= 0 {
+		config.NoticeInterval = time.Minute
+	}
+	if config.MaxTracked <= 0 {
+		config.MaxTracked = 1000
+	}
+
+	nd := &NoiseDemoter{config: config, messages: make(map[string]*noiseState)}
+	for _, pattern := range config.Allow {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow pattern %q: %w", pattern, err)
+		}
+		nd.allow = append(nd.allow, re)
+	}
+	return nd, nil
+}
+
+// observe counts message at now and reports whether it should be demoted.
+// notice holds the fields of a notice to log first, or nil.
+func (nd *NoiseDemoter) observe(message string, now time.Time) (demote bool, notice map[string]interface{}) {
+	for _, re := range nd.allow {
+		if re.MatchString(message) {
+			return false, nil
+		}
+	}
+
+	nd.mu.Lock()
+	defer nd.mu.Unlock()
+
+	state, ok := nd.messages[message]
+	if !ok {
+		if len(nd.messages) >= nd.config.MaxTracked && !nd.prune(now) {
+			return false, nil
+		}
+		state = &noiseState{windowStart: now}
+		nd.messages[message] = state
+	}
+
+	if elapsed := now.Sub(state.windowStart); elapsed >= nd.config.Window {
+		if state.count <= nd.config.Threshold || elapsed >= 2*nd.config.Window {
+			state.noisy = false
+		}
+		state.windowStart = now
+		state.count = 0
+	}
+	state.count++
+	if state.count > nd.config.Threshold {
+		state.noisy = true
+	}
+	if !state.noisy {
+		return false, nil
+	}
+
+	state.demoted++
+	if state.lastNotice.IsZero() || now.Sub(state.lastNotice) >= nd.config.NoticeInterval {
+		notice = map[string]interface{}{"noise_message": message, "noise_demoted": state.demoted}
+		state.demoted = 0
+		state.lastNotice = now
+	}
+	return true, notice
+}
+
+// prune forgets messages that would no longer be demoted and reports
+// whether there is room for another.
+func (nd *NoiseDemoter) prune(now time.Time) bool {
+	for message, state := range nd.messages {
+		elapsed := now.Sub(state.windowStart)
+		if elapsed >= 2*nd.config.Window || (!state.noisy && elapsed >= nd.config.Window) {
+			delete(nd.messages, message)
+		}
+	}
+	return len(nd.messages) < nd.config.MaxTracked
+}
+
+// WithNoiseDemotion demotes INFO messages repeated at a very high rate to
+// DEBUG.
+func (b *CoreConfigBuilder) WithNoiseDemotion(demoter *NoiseDemoter) *CoreConfigBuilder {
+	b.config.NoiseDemoter = demoter
+	return b
+}
+
+// WithNoiseDemotion demotes INFO messages repeated at a very high rate to
+// DEBUG.
+func (b *LoggerConfigBuilder) WithNoiseDemotion(demoter *NoiseDemoter) *LoggerConfigBuilder {
+	b.config.Core.NoiseDemoter = demoter
+	return b
+}
@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNoiseDemoter_DemotesRepeatedInfo(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	demoter, err := NewNoiseDemoter(NoiseConfig{Threshold: 3, Window: time.Second, NoticeInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create demoter: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithWriter(buf).
+		WithClock(ClockFunc(func() time.Time { return now })).
+		WithNoiseDemotion(demoter).
+		Build())
+
+	for i := 0; i < 10; i++ {
+		logger.Info("polling queue")
+	}
+	logger.Info("order placed")
+	logger.Warn("polling queue")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected 3 entries, a notice and 2 others, got %d:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[3], noiseNoticeMessage) || !strings.Contains(lines[3], `"noise_message":"polling queue"`) || !strings.Contains(lines[3], `"noise_demoted":1`) {
+		t.Errorf("unexpected notice %s", lines[3])
+	}
+	if !strings.Contains(lines[4], "order placed") || !strings.Contains(lines[5], `"level":"WARN"`) {
+		t.Errorf("expected other messages and levels to pass, got %s", lines[4:])
+	}
+
+	// Still noisy in the next window; the notice is not repeated yet.
+	buf.Reset()
+	now = now.Add(time.Second)
+	logger.Info("polling queue")
+	if buf.Len() != 0 {
+		t.Errorf("expected the message to stay demoted, got %s", buf.String())
+	}
+
+	// A quiet window restores it.
+	now = now.Add(3 * time.Second)
+	logger.Info("polling queue")
+	if !strings.Contains(buf.String(), `"level":"INFO"`) {
+		t.Errorf("expected the message at INFO again, got %q", buf.String())
+	}
+}
+
+func TestNoiseDemoter_DemotedEntriesAtDebug(t *testing.T) {
+	demoter, err := NewNoiseDemoter(NoiseConfig{Threshold: 1})
+	if err != nil {
+		t.Fatalf("failed to create demoter: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(DebugLevel).
+		WithJSONFormat().
+		WithWriter(buf).
+		WithNoiseDemotion(demoter).
+		Build())
+
+	logger.Info("heartbeat")
+	logger.Info("heartbeat")
+	logger.Info("heartbeat")
+
+	if got := strings.Count(buf.String(), `"level":"DEBUG"`); got != 2 {
+		t.Errorf("expected 2 demoted entries, got %d:\n%s", got, buf.String())
+	}
+	if got := strings.Count(buf.String(), noiseNoticeMessage); got != 1 {
+		t.Errorf("expected 1 notice, got %d", got)
+	}
+}
+
+func TestNoiseDemoter_Allow(t *testing.T) {
+	demoter, err := NewNoiseDemoter(NoiseConfig{Threshold: 1, Allow: []string{`^request completed`}})
+	if err != nil {
+		t.Fatalf("failed to create demoter: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if demote, _ := demoter.observe("request completed", time.Now()); demote {
+			t.Fatal("expected an allowed message never to be demoted")
+		}
+	}
+
+	if _, err := NewNoiseDemoter(NoiseConfig{Allow: []string{"("}}); err == nil {
+		t.Error("expected an error for an invalid allow pattern")
+	}
+}
+
+func TestNoiseDemoter_MaxTracked(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	demoter, err := NewNoiseDemoter(NoiseConfig{Threshold: 1, MaxTracked: 2})
+	if err != nil {
+		t.Fatalf("failed to create demoter: %v", err)
+	}
+
+	demoter.observe("a", now)
+	demoter.observe("b", now)
+	for i := 0; i < 3; i++ {
+		if demote, _ := demoter.observe("c", now); demote {
+			t.Fatal("expected untracked messages not to be demoted")
+		}
+	}
+
+	// Once the tracked messages go quiet, there is room again.
+	now = now.Add(2 * time.Second)
+	demoter.observe("c", now)
+	if demote, _ := demoter.observe("c", now); !demote {
+		t.Error("expected the message to be tracked after pruning")
+	}
+}
+
+func TestNoiseDemoter_Slog(t *testing.T) {
+	demoter, err := NewNoiseDemoter(NoiseConfig{Threshold: 1})
+	if err != nil {
+		t.Fatalf("failed to create demoter: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().WithJSONFormat().WithWriter(buf).WithNoiseDemotion(demoter).Build()
+	config.UseSlog = true
+	logger := NewWithLoggerConfig(config)
+
+	logger.Info("tick")
+	logger.Info("tick")
+
+	if !strings.Contains(buf.String(), `"noise_message":"tick"`) || strings.Count(buf.String(), `"msg":"tick"`) != 1 {
+		t.Errorf("expected one entry and a notice, got:\n%s", buf.String())
+	}
+}
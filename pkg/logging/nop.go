@@ -0,0 +1,76 @@
+package logging
+
+import "context"
+
+// NewNop returns a Logger that discards everything. Every method is a no-op
+// that allocates nothing, WithField and friends return the same logger, and
+// Fluent entries are nil *FluentEntry values whose methods do nothing, so
+// libraries can default a Logger dependency to it and tests and benchmarks
+// pay nothing for logging.
+//
+// Example:
+//
+//	func NewClient(logger logging.Logger) *Client {
+//		if logger == nil {
+//			logger = logging.NewNop()
+//		}
+//		return &Client{logger: logger}
+//	}
+func NewNop() Logger {
+	return nopLogger{}
+}
+
+// nopLogger is the Logger returned by NewNop.
+type nopLogger struct{}
+
+var _ Logger = nopLogger{}
+
+func (nopLogger) Log(Level, string, ...interface{})                         {}
+func (nopLogger) LogContext(context.Context, Level, string, ...interface{}) {}
+func (n nopLogger) WithField(string, interface{}) Logger                    { return n }
+func (n nopLogger) WithFields(map[string]interface{}) Logger                { return n }
+func (n nopLogger) WithLevel(Level) Logger                                  { return n }
+func (nopLogger) IsLevelEnabled(Level) bool                                 { return false }
+func (nopLogger) Trace(string, ...interface{})                              {}
+func (nopLogger) Debug(string, ...interface{})                              {}
+func (nopLogger) Info(string, ...interface{})                               {}
+func (nopLogger) Warn(string, ...interface{})                               {}
+func (nopLogger) Error(string, ...interface{})                              {}
+func (nopLogger) Critical(string, ...interface{})                           {}
+func (nopLogger) TraceContext(context.Context, string, ...interface{})      {}
+func (nopLogger) DebugContext(context.Context, string, ...interface{})      {}
+func (nopLogger) InfoContext(context.Context, string, ...interface{})       {}
+func (nopLogger) WarnContext(context.Context, string, ...interface{})       {}
+func (nopLogger) ErrorContext(context.Context, string, ...interface{})      {}
+func (nopLogger) CriticalContext(context.Context, string, ...interface{})   {}
+func (nopLogger) Fluent() FluentLogger                                      { return nopFluentLogger{} }
+func (nopLogger) SetLevel(Level)                                            {}
+
+// GetLevel returns CriticalLevel, although no level is enabled.
+func (nopLogger) GetLevel() Level { return CriticalLevel }
+
+// nopFluentLogger returns nil entries, which discard everything.
+type nopFluentLogger struct{}
+
+func (nopFluentLogger) Trace() *FluentEntry    { return nil }
+func (nopFluentLogger) Debug() *FluentEntry    { return nil }
+func (nopFluentLogger) Info() *FluentEntry     { return nil }
+func (nopFluentLogger) Warn() *FluentEntry     { return nil }
+func (nopFluentLogger) Error() *FluentEntry    { return nil }
+func (nopFluentLogger) Critical() *FluentEntry { return nil }
+
+// DiscardOutput is an Output that discards everything written to it, the
+// Output counterpart of io.Discard.
+//
+// Example:
+//
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithCustomOutput(logging.DiscardOutput).
+//		Build())
+var DiscardOutput Output = discardOutput{}
+
+type discardOutput struct{}
+
+func (discardOutput) Write([]byte) error                { return nil }
+func (discardOutput) WriteEntry(LogEntry, []byte) error { return nil }
+func (discardOutput) Close() error                      { return nil }
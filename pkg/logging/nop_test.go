@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNewNop(t *testing.T) {
+	logger := NewNop()
+	if logger.IsLevelEnabled(CriticalLevel) {
+		t.Error("expected no level to be enabled")
+	}
+	if logger.WithField("k", "v") != logger || logger.WithLevel(TraceLevel) != logger {
+		t.Error("expected children to be the nop logger")
+	}
+	// Must not panic.
+	logger.Fluent().Error().Str("k", "v").Err(errors.New("boom")).Ctx(context.Background()).Origin().Msgf("failed %d", 1)
+}
+
+func TestNewNop_ZeroAllocations(t *testing.T) {
+	logger := NewNop()
+	ctx := context.Background()
+	fields := map[string]interface{}{"k": "v"}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		logger.Info("message")
+		logger.ErrorContext(ctx, "message")
+		logger.WithFields(fields).WithField("k", "v").Debug("message")
+		logger.Fluent().Info().Str("k", "v").Int("n", 1).Bool("ok", true).Msg("message")
+	})
+	if allocs != 0 {
+		t.Errorf("expected zero allocations, got %v", allocs)
+	}
+}
+
+func TestDiscardOutput(t *testing.T) {
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(DiscardOutput).Build())
+	logger.Info("discarded")
+	if err := DiscardOutput.Write([]byte("data")); err != nil {
+		t.Errorf("Write() error = %v", err)
+	}
+	if err := DiscardOutput.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func BenchmarkNop(b *testing.B) {
+	logger := NewNop()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger.WithField("request_id", "abc").Info("handled request")
+	}
+}
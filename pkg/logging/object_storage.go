@@ -0,0 +1,277 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ObjectUploader stores an object under key, e.g. in S3 or GCS.
+type ObjectUploader interface {
+	Upload(ctx context.Context, key string, data []byte) error
+}
+
+// ObjectUploaderFunc adapts a function to ObjectUploader.
+type ObjectUploaderFunc func(ctx context.Context, key string, data []byte) error
+
+// Upload calls f.
+func (f ObjectUploaderFunc) Upload(ctx context.Context, key string, data []byte) error {
+	return f(ctx, key, data)
+}
+
+// S3Config configures an S3 uploader.
+type S3Config struct {
+	Bucket string
+	// Region defaults to AWS_REGION, then "us-east-1".
+	Region string
+	// Endpoint overrides the AWS endpoint for S3-compatible stores such as
+	// MinIO, e.g. "http://minio:9000". Objects are then addressed by path.
+	Endpoint string
+	// AccessKeyID, SecretAccessKey and SessionToken default to the standard
+	// AWS_* environment variables.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// ContentType defaults to "application/gzip".
+	ContentType string
+	// Client defaults to an http.Client with a 5 minute timeout.
+	Client *http.Client
+}
+
+// s3Uploader uploads objects with PUT requests signed with AWS Signature
+// Version 4.
+type s3Uploader struct {
+	config S3Config
+}
+
+// NewS3Uploader returns an ObjectUploader for an S3 bucket or an
+// S3-compatible store.
+func NewS3Uploader(config S3Config) (ObjectUploader, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 uploader requires a bucket")
+	}
+	if config.Region == "" {
+		config.Region = os.Getenv("AWS_REGION")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.AccessKeyID == "" {
+		config.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		config.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		config.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 uploader requires credentials")
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/gzip"
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	return &s3Uploader{config: config}, nil
+}
+
+// objectURL returns the URL of key, virtual-hosted on AWS and path-style
+// on custom endpoints.
+func (u *s3Uploader) objectURL(key string) string {
+	path := "/" + awsURIEscape(key, false)
+	if u.config.Endpoint != "" {
+		return strings.TrimRight(u.config.Endpoint, "/") + "/" + awsURIEscape(u.config.Bucket, true) + path
+	}
+	return "https://" + u.config.Bucket + ".s3." + u.config.Region + ".amazonaws.com" + path
+}
+
+// Upload PUTs data under key.
+func (u *s3Uploader) Upload(ctx context.Context, key string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return permanent(err)
+	}
+	req.Header.Set("Content-Type", u.config.ContentType)
+	if u.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", u.config.SessionToken)
+	}
+	signAWSv4(req, data, u.config.AccessKeyID, u.config.SecretAccessKey, u.config.Region, "s3", time.Now())
+
+	resp, err := u.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	return checkHTTPResponse(resp)
+}
+
+// signAWSv4 signs req with AWS Signature Version 4, covering the host,
+// Content-Type, Date and X-Amz-* headers.
+func signAWSv4(req *http.Request, payload []byte, accessKey, secretKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" || lower == "date" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		vs := append([]string(nil), values[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			pairs = append(pairs, awsURIEscape(k, true)+"="+awsURIEscape(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEscape percent-encodes everything but unreserved characters, and
+// '/' unless encodeSlash is set, as Signature Version 4 requires.
+func awsURIEscape(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// GCSConfig configures a Google Cloud Storage uploader.
+type GCSConfig struct {
+	Bucket string
+	// TokenSource returns an OAuth2 access token for each upload. Defaults
+	// to the metadata server's default service account token.
+	TokenSource func(ctx context.Context) (string, error)
+	// Endpoint overrides "https://storage.googleapis.com".
+	Endpoint string
+	// ContentType defaults to "application/gzip".
+	ContentType string
+	// Client defaults to an http.Client with a 5 minute timeout.
+	Client *http.Client
+}
+
+// gcsUploader uploads objects with the JSON API's simple upload.
+type gcsUploader struct {
+	config GCSConfig
+}
+
+// NewGCSUploader returns an ObjectUploader for a GCS bucket.
+func NewGCSUploader(config GCSConfig) (ObjectUploader, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("gcs uploader requires a bucket")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://storage.googleapis.com"
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/gzip"
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	if config.TokenSource == nil {
+		config.TokenSource = (&metadataTokenSource{client: config.Client}).Token
+	}
+	return &gcsUploader{config: config}, nil
+}
+
+// Upload stores data under key.
+func (u *gcsUploader) Upload(ctx context.Context, key string, data []byte) error {
+	token, err := u.config.TokenSource(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		strings.TrimRight(u.config.Endpoint, "/"), url.PathEscape(u.config.Bucket), url.QueryEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return permanent(err)
+	}
+	req.Header.Set("Content-Type", u.config.ContentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := u.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	return checkHTTPResponse(resp)
+}
@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// OriginField is the field holding an entry's Origin.
+const OriginField = "origin"
+
+// Origin identifies the code that emitted an entry, so lines from a fleet
+// of binaries can be attributed to an exact version. The build details come
+// from debug.ReadBuildInfo and are empty for binaries built without module
+// or VCS information, e.g. by go run or with -buildvcs=false.
+type Origin struct {
+	// Module is the main module's path.
+	Module string `json:"module,omitempty"`
+	// Version is the main module's version, "(devel)" for local builds.
+	Version string `json:"version,omitempty"`
+	// Revision is the VCS commit the binary was built from.
+	Revision string `json:"vcs.revision,omitempty"`
+	// Time is the commit time of Revision.
+	Time string `json:"vcs.time,omitempty"`
+	// Modified is set when the working tree had uncommitted changes.
+	Modified bool `json:"vcs.modified,omitempty"`
+	// Package is the import path of the package that logged the entry.
+	Package string `json:"package,omitempty"`
+}
+
+// LogValue groups the origin's non-empty attributes for slog handlers.
+func (o Origin) LogValue() slog.Value {
+	var attrs []slog.Attr
+	for _, attr := range []slog.Attr{
+		slog.String("module", o.Module),
+		slog.String("version", o.Version),
+		slog.String("vcs.revision", o.Revision),
+		slog.String("vcs.time", o.Time),
+		slog.String("package", o.Package),
+	} {
+		if attr.Value.String() != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+	if o.Modified {
+		attrs = append(attrs, slog.Bool("vcs.modified", true))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// buildOrigin reads the build information once.
+var buildOrigin = sync.OnceValue(func() Origin {
+	var origin Origin
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return origin
+	}
+	origin.Module = info.Main.Path
+	origin.Version = info.Main.Version
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			origin.Revision = setting.Value
+		case "vcs.time":
+			origin.Time = setting.Value
+		case "vcs.modified":
+			origin.Modified = setting.Value == "true"
+		}
+	}
+	return origin
+})
+
+// BuildOrigin returns the Origin of the running binary, without a package.
+//
+// Example:
+//
+//	logger = logger.WithField(logging.OriginField, logging.BuildOrigin())
+func BuildOrigin() Origin {
+	return buildOrigin()
+}
+
+// origins caches the Origin of each call site.
+var origins sync.Map
+
+// originAt returns the Origin of the code at pc.
+func originAt(pc uintptr) Origin {
+	if cached, ok := origins.Load(pc); ok {
+		return cached.(Origin)
+	}
+	origin := buildOrigin()
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function != "" {
+		origin.Package = packageOfFunction(frame.Function)
+	}
+	origins.Store(pc, origin)
+	return origin
+}
+
+// callerOrigin returns the Origin of the user's call site, found by
+// callerPC from skip frames above callerOrigin's caller.
+func callerOrigin(skip int) Origin {
+	pc, ok := callerPC(skip + 1)
+	if !ok {
+		return buildOrigin()
+	}
+	return originAt(pc)
+}
+
+// WithOrigin adds an origin field to every entry; see Origin.
+func (b *CoreConfigBuilder) WithOrigin() *CoreConfigBuilder {
+	b.config.Origin = true
+	return b
+}
+
+// WithOrigin adds an origin field to every entry; see Origin.
+//
+// Example:
+//
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithOrigin().
+//		Build())
+//	// {"message":"started","origin":{"module":"example.com/api","vcs.revision":"4f2a...","package":"example.com/api/server"},...}
+func (b *LoggerConfigBuilder) WithOrigin() *LoggerConfigBuilder {
+	b.config.Core.Origin = true
+	return b
+}
+
+// Origin adds an origin field for the calling package to this entry.
+func (e *FluentEntry) Origin() *FluentEntry {
+	if e == nil {
+		return e
+	}
+	e.fields[OriginField] = callerOrigin(1)
+	return e
+}
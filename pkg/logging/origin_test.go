@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+const thisPackage = "github.com/ocrosby/go-logging/pkg/logging"
+
+func TestWithOrigin(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithOrigin().WithWriter(buf).Build())
+	logger.Info("started")
+
+	var entry struct {
+		Origin Origin `json:"origin"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	want := BuildOrigin()
+	want.Package = thisPackage
+	if entry.Origin != want {
+		t.Errorf("origin = %+v, want %+v", entry.Origin, want)
+	}
+}
+
+func TestWithOrigin_Slog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().UseSlog(true).WithTextFormat().WithOrigin().WithWriter(buf).Build())
+	logger.Info("started")
+
+	if !strings.Contains(buf.String(), "origin.package="+thisPackage) {
+		t.Errorf("expected origin group, got %q", buf.String())
+	}
+}
+
+func TestFluentEntry_Origin(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+	logger.Fluent().Info().Origin().Msg("tagged")
+	logger.Info("untagged")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], `"package":"`+thisPackage+`"`) {
+		t.Errorf("expected origin on fluent entry, got %s", lines[0])
+	}
+	if strings.Contains(lines[1], `"`+OriginField+`":`) {
+		t.Errorf("expected no origin without WithOrigin, got %s", lines[1])
+	}
+}
+
+func TestWithOrigin_Fluent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithOrigin().WithWriter(buf).Build())
+	logger.Fluent().Info().Str("user", "alice").Msg("started")
+
+	var entry struct {
+		File   string `json:"file"`
+		Origin Origin `json:"origin"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if !strings.Contains(entry.File, "origin_test.go:") {
+		t.Errorf("expected the fluent call site, got file %q", entry.File)
+	}
+	if entry.Origin.Package != thisPackage {
+		t.Errorf("origin package = %q, want %q", entry.Origin.Package, thisPackage)
+	}
+}
+
+func TestOrigin_LogValue(t *testing.T) {
+	value := Origin{Module: "example.com/api", Modified: true}.LogValue()
+	if value.Kind() != slog.KindGroup {
+		t.Fatalf("expected group, got %v", value.Kind())
+	}
+	attrs := value.Group()
+	if len(attrs) != 2 || attrs[0].Key != "module" || attrs[1].Key != "vcs.modified" {
+		t.Errorf("expected module and vcs.modified only, got %v", attrs)
+	}
+}
@@ -0,0 +1,370 @@
+package logging
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ocrosby/go-logging/pkg/logging/internal"
+)
+
+// OTLPEncoding selects how an OTLPFormatter serializes LogRecords.
+type OTLPEncoding int
+
+const (
+	// OTLPEncodingJSON writes each LogRecord as a line of OTLP/JSON.
+	OTLPEncodingJSON OTLPEncoding = iota
+	// OTLPEncodingProtobuf writes each LogRecord as a protobuf message
+	// prefixed with its length as a varint, so a stream of them can be
+	// split again.
+	OTLPEncodingProtobuf
+)
+
+// OTLPConfig configures an OTLPFormatter.
+type OTLPConfig struct {
+	// Encoding defaults to OTLPEncodingJSON.
+	Encoding OTLPEncoding
+}
+
+// OTLPFormatter formats log entries as OpenTelemetry LogRecords
+// (opentelemetry.proto.logs.v1.LogRecord), in OTLP/JSON or protobuf. The
+// level maps to the severity number and text, the message becomes the
+// body, and fields become attributes, keeping strings, numbers, booleans,
+// slices and maps typed. A trace ID from WithTraceID and a span_id field
+// become the record's traceId and spanId when they are 32 and 16 hex
+// digits (dashes in UUIDs are ignored); otherwise they stay attributes.
+//
+// Example:
+//
+//	formatter := logging.NewOTLPFormatter(nil, logging.OTLPConfig{})
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithCustomFormatter(formatter).
+//		Build())
+//	// {"timeUnixNano":"1700000000000000000","severityNumber":9,"severityText":"INFO","body":{"stringValue":"order placed"},...}
+type OTLPFormatter struct {
+	config *FormatterConfig
+	otlp   OTLPConfig
+}
+
+// NewOTLPFormatter creates an OTLP LogRecord formatter.
+func NewOTLPFormatter(config *FormatterConfig, otlp OTLPConfig) *OTLPFormatter {
+	if config == nil {
+		config = NewFormatterConfig().Build()
+	}
+	return &OTLPFormatter{config: config, otlp: otlp}
+}
+
+// otlpRecord is a LogRecord before encoding.
+type otlpRecord struct {
+	timeUnixNano   uint64
+	severityNumber int
+	severityText   string
+	body           string
+	attributes     []otlpKeyValue
+	traceID        []byte
+	spanID         []byte
+}
+
+type otlpKeyValue struct {
+	key   string
+	value interface{}
+}
+
+// Format formats a log entry as an OTLP LogRecord.
+func (f *OTLPFormatter) Format(entry LogEntry) ([]byte, error) {
+	record := f.record(entry)
+	if f.otlp.Encoding == OTLPEncodingProtobuf {
+		message := record.appendProto(nil)
+		return append(binary.AppendUvarint(nil, uint64(len(message))), message...), nil
+	}
+	data, err := json.Marshal(record.jsonValue())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OTLP log record: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func (f *OTLPFormatter) record(entry LogEntry) otlpRecord {
+	record := otlpRecord{
+		severityNumber: otlpSeverity(entry.Level),
+		severityText:   entry.Level.String(),
+		body:           internal.ApplyRedactionPatterns(entry.Message, f.config.RedactPatterns),
+	}
+	if !entry.Timestamp.IsZero() {
+		record.timeUnixNano = uint64(entry.Timestamp.UnixNano())
+	}
+
+	attributes := make(map[string]interface{}, len(entry.Fields)+4)
+	for k, v := range entry.Fields {
+		attributes[k] = v
+	}
+	contextFields := make(map[string]interface{})
+	contextFieldsFrom(entry.Context).AddToMap(contextFields)
+	for k, v := range contextFields {
+		attributes[k] = v
+	}
+	if traceID, ok := otlpID(attributes["trace_id"], 16); ok {
+		record.traceID = traceID
+		delete(attributes, "trace_id")
+	}
+	if spanID, ok := otlpID(attributes["span_id"], 8); ok {
+		record.spanID = spanID
+		delete(attributes, "span_id")
+	}
+	if f.config.IncludeFile && entry.File != "" {
+		file := entry.File
+		if f.config.UseShortFile {
+			file = filepath.Base(file)
+		}
+		attributes["code.filepath"] = file
+		attributes["code.lineno"] = entry.Line
+	}
+
+	keys := make([]string, 0, len(attributes))
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		record.attributes = append(record.attributes, otlpKeyValue{key: k, value: otlpValue(attributes[k])})
+	}
+	return record
+}
+
+// otlpSeverity maps a level to the OpenTelemetry severity number.
+func otlpSeverity(level Level) int {
+	switch {
+	case level >= CriticalLevel:
+		return 21 // FATAL
+	case level >= ErrorLevel:
+		return 17 // ERROR
+	case level >= WarnLevel:
+		return 13 // WARN
+	case level >= InfoLevel:
+		return 9 // INFO
+	case level >= DebugLevel:
+		return 5 // DEBUG
+	default:
+		return 1 // TRACE
+	}
+}
+
+// otlpID decodes a trace or span ID of size bytes written in hex.
+func otlpID(v interface{}, size int) ([]byte, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, false
+	}
+	id, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(id) != size {
+		return nil, false
+	}
+	return id, true
+}
+
+// otlpValue normalizes v to a string, bool, int64, float64, []byte,
+// []interface{} or []otlpKeyValue, the kinds of an OTLP AnyValue.
+func otlpValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string, bool, int64, float64, []byte:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case error:
+		return v.Error()
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := rv.Uint(); n <= math.MaxInt64 {
+			return int64(n)
+		}
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, rv.Len())
+		for i := range values {
+			values[i] = otlpValue(rv.Index(i).Interface())
+		}
+		return values
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		values := make([]otlpKeyValue, len(keys))
+		for i, k := range keys {
+			values[i] = otlpKeyValue{key: k.String(), value: otlpValue(rv.MapIndex(k).Interface())}
+		}
+		return values
+	}
+	return fmt.Sprint(v)
+}
+
+// jsonValue returns the record in the OTLP/JSON mapping: camelCase names,
+// 64-bit integers as strings and IDs in hex.
+func (r otlpRecord) jsonValue() map[string]interface{} {
+	record := map[string]interface{}{
+		"severityNumber": r.severityNumber,
+		"severityText":   r.severityText,
+		"body":           otlpJSONAnyValue(r.body),
+	}
+	if r.timeUnixNano != 0 {
+		record["timeUnixNano"] = strconv.FormatUint(r.timeUnixNano, 10)
+		record["observedTimeUnixNano"] = strconv.FormatUint(r.timeUnixNano, 10)
+	}
+	if len(r.attributes) > 0 {
+		record["attributes"] = otlpJSONKeyValues(r.attributes)
+	}
+	if r.traceID != nil {
+		record["traceId"] = hex.EncodeToString(r.traceID)
+	}
+	if r.spanID != nil {
+		record["spanId"] = hex.EncodeToString(r.spanID)
+	}
+	return record
+}
+
+func otlpJSONKeyValues(kvs []otlpKeyValue) []interface{} {
+	values := make([]interface{}, len(kvs))
+	for i, kv := range kvs {
+		values[i] = map[string]interface{}{"key": kv.key, "value": otlpJSONAnyValue(kv.value)}
+	}
+	return values
+}
+
+func otlpJSONAnyValue(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int64:
+		return map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+	case float64:
+		return map[string]interface{}{"doubleValue": v}
+	case []byte:
+		return map[string]interface{}{"bytesValue": v}
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = otlpJSONAnyValue(value)
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	case []otlpKeyValue:
+		return map[string]interface{}{"kvlistValue": map[string]interface{}{"values": otlpJSONKeyValues(v)}}
+	}
+	return map[string]interface{}{}
+}
+
+// Protobuf wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+)
+
+func appendProtoTag(b []byte, field, wireType int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wireType))
+}
+
+func appendProtoBytes(b []byte, field int, data []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// appendProto encodes the record as a LogRecord message.
+func (r otlpRecord) appendProto(b []byte) []byte {
+	if r.timeUnixNano != 0 {
+		b = appendProtoTag(b, 1, protoFixed64)
+		b = binary.LittleEndian.AppendUint64(b, r.timeUnixNano)
+	}
+	b = appendProtoTag(b, 2, protoVarint)
+	b = binary.AppendUvarint(b, uint64(r.severityNumber))
+	b = appendProtoBytes(b, 3, []byte(r.severityText))
+	b = appendProtoBytes(b, 5, appendOTLPAnyValue(nil, r.body))
+	for _, kv := range r.attributes {
+		b = appendProtoBytes(b, 6, appendOTLPKeyValue(nil, kv))
+	}
+	if r.traceID != nil {
+		b = appendProtoBytes(b, 9, r.traceID)
+	}
+	if r.spanID != nil {
+		b = appendProtoBytes(b, 10, r.spanID)
+	}
+	if r.timeUnixNano != 0 {
+		b = appendProtoTag(b, 11, protoFixed64)
+		b = binary.LittleEndian.AppendUint64(b, r.timeUnixNano)
+	}
+	return b
+}
+
+// appendOTLPKeyValue encodes a KeyValue message.
+func appendOTLPKeyValue(b []byte, kv otlpKeyValue) []byte {
+	b = appendProtoBytes(b, 1, []byte(kv.key))
+	return appendProtoBytes(b, 2, appendOTLPAnyValue(nil, kv.value))
+}
+
+// appendOTLPAnyValue encodes an AnyValue message. A nil value is an empty
+// AnyValue.
+func appendOTLPAnyValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendProtoBytes(b, 1, []byte(v))
+	case bool:
+		b = appendProtoTag(b, 2, protoVarint)
+		if v {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case int64:
+		b = appendProtoTag(b, 3, protoVarint)
+		return binary.AppendUvarint(b, uint64(v))
+	case float64:
+		b = appendProtoTag(b, 4, protoFixed64)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	case []interface{}:
+		var values []byte
+		for _, value := range v {
+			values = appendProtoBytes(values, 1, appendOTLPAnyValue(nil, value))
+		}
+		return appendProtoBytes(b, 5, values)
+	case []otlpKeyValue:
+		var values []byte
+		for _, kv := range v {
+			values = appendProtoBytes(values, 1, appendOTLPKeyValue(nil, kv))
+		}
+		return appendProtoBytes(b, 6, values)
+	case []byte:
+		return appendProtoBytes(b, 7, v)
+	}
+	return b
+}
@@ -0,0 +1,170 @@
+package logging
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func otlpTestEntry() LogEntry {
+	return LogEntry{
+		Timestamp: time.Unix(1700000000, 5),
+		Level:     WarnLevel,
+		Message:   "disk almost full",
+		Context:   WithTraceID(context.Background(), "4bf92f35-77b3-4da6-a3ce-929d0e0e4736"),
+		Fields: map[string]interface{}{
+			"span_id": "00f067aa0ba902b7",
+			"percent": 91.5,
+			"disk":    "sda1",
+			"retries": 3,
+			"healthy": false,
+			"tags":    []string{"a", "b"},
+			"meta":    map[string]interface{}{"zone": "eu-1"},
+			"error":   errors.New("no space"),
+		},
+	}
+}
+
+func TestOTLPFormatter_JSON(t *testing.T) {
+	data, err := NewOTLPFormatter(NewFormatterConfig().IncludeFile(false).Build(), OTLPConfig{}).Format(otlpTestEntry())
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, data)
+	}
+	want := map[string]interface{}{
+		"timeUnixNano":         "1700000000000000005",
+		"observedTimeUnixNano": "1700000000000000005",
+		"severityNumber":       float64(13),
+		"severityText":         "WARN",
+		"body":                 map[string]interface{}{"stringValue": "disk almost full"},
+		"traceId":              "4bf92f3577b34da6a3ce929d0e0e4736",
+		"spanId":               "00f067aa0ba902b7",
+		"attributes": []interface{}{
+			otlpTestAttribute("disk", map[string]interface{}{"stringValue": "sda1"}),
+			otlpTestAttribute("error", map[string]interface{}{"stringValue": "no space"}),
+			otlpTestAttribute("healthy", map[string]interface{}{"boolValue": false}),
+			otlpTestAttribute("meta", map[string]interface{}{"kvlistValue": map[string]interface{}{"values": []interface{}{
+				otlpTestAttribute("zone", map[string]interface{}{"stringValue": "eu-1"}),
+			}}}),
+			otlpTestAttribute("percent", map[string]interface{}{"doubleValue": 91.5}),
+			otlpTestAttribute("retries", map[string]interface{}{"intValue": "3"}),
+			otlpTestAttribute("tags", map[string]interface{}{"arrayValue": map[string]interface{}{"values": []interface{}{
+				map[string]interface{}{"stringValue": "a"},
+				map[string]interface{}{"stringValue": "b"},
+			}}}),
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected record\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func otlpTestAttribute(key string, value map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"key": key, "value": value}
+}
+
+func TestOTLPFormatter_NonHexTraceIDStaysAttribute(t *testing.T) {
+	entry := LogEntry{Level: InfoLevel, Message: "hi", Context: WithTraceID(context.Background(), "trace-123")}
+	data, _ := NewOTLPFormatter(nil, OTLPConfig{}).Format(entry)
+
+	var got struct {
+		TraceID    string `json:"traceId"`
+		Attributes []struct {
+			Key string `json:"key"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if got.TraceID != "" || len(got.Attributes) != 1 || got.Attributes[0].Key != "trace_id" {
+		t.Errorf("expected trace_id as an attribute, got %s", data)
+	}
+}
+
+// protoField is a decoded protobuf field: a varint or fixed64 value, or
+// the bytes of a length-delimited one.
+type protoField struct {
+	number int
+	value  uint64
+	bytes  []byte
+}
+
+func decodeProto(t *testing.T, b []byte) []protoField {
+	t.Helper()
+	var fields []protoField
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		b = b[n:]
+		field := protoField{number: int(tag >> 3)}
+		switch tag & 7 {
+		case protoVarint:
+			field.value, n = binary.Uvarint(b)
+			b = b[n:]
+		case protoFixed64:
+			field.value = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case protoBytes:
+			length, n := binary.Uvarint(b)
+			field.bytes = b[n : n+int(length)]
+			b = b[n+int(length):]
+		default:
+			t.Fatalf("unexpected wire type in tag %#x", tag)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func TestOTLPFormatter_Protobuf(t *testing.T) {
+	entry := otlpTestEntry()
+	entry.Fields = map[string]interface{}{"span_id": "00f067aa0ba902b7", "percent": 91.5, "retries": 3}
+	data, err := NewOTLPFormatter(NewFormatterConfig().IncludeFile(false).Build(), OTLPConfig{Encoding: OTLPEncodingProtobuf}).Format(entry)
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	length, n := binary.Uvarint(data)
+	if int(length) != len(data)-n {
+		t.Fatalf("length prefix %d does not match message of %d bytes", length, len(data)-n)
+	}
+	fields := decodeProto(t, data[n:])
+
+	var numbers []int
+	for _, f := range fields {
+		numbers = append(numbers, f.number)
+	}
+	if want := []int{1, 2, 3, 5, 6, 6, 9, 10, 11}; !reflect.DeepEqual(numbers, want) {
+		t.Fatalf("field numbers = %v, want %v", numbers, want)
+	}
+	if fields[0].value != 1700000000000000005 || fields[1].value != 13 || string(fields[2].bytes) != "WARN" {
+		t.Errorf("unexpected time, severity or text: %+v", fields[:3])
+	}
+	if body := decodeProto(t, fields[3].bytes); len(body) != 1 || body[0].number != 1 || string(body[0].bytes) != "disk almost full" {
+		t.Errorf("unexpected body %+v", body)
+	}
+
+	percent := decodeProto(t, fields[4].bytes)
+	if string(percent[0].bytes) != "percent" {
+		t.Fatalf("expected the percent attribute first, got %q", percent[0].bytes)
+	}
+	if value := decodeProto(t, percent[1].bytes); value[0].number != 4 || math.Float64frombits(value[0].value) != 91.5 {
+		t.Errorf("unexpected double value %+v", value)
+	}
+	retries := decodeProto(t, fields[5].bytes)
+	if value := decodeProto(t, retries[1].bytes); string(retries[0].bytes) != "retries" || value[0].number != 3 || value[0].value != 3 {
+		t.Errorf("unexpected int attribute %+v", retries)
+	}
+
+	if len(fields[6].bytes) != 16 || len(fields[7].bytes) != 8 {
+		t.Errorf("expected 16-byte trace and 8-byte span IDs, got %x and %x", fields[6].bytes, fields[7].bytes)
+	}
+}
@@ -0,0 +1,57 @@
+package logging
+
+import "sync/atomic"
+
+// OutputStats is a snapshot of an output's counters, for monitoring the
+// logging pipeline itself. WriterOutput, FileOutput, BufferedOutput and
+// AsyncOutput report them through Stats.
+//
+// Example:
+//
+//	stats := output.Stats()
+//	metrics.Gauge("log_queue_depth", float64(stats.QueueDepth))
+//	metrics.Counter("log_write_errors", float64(stats.Errors))
+type OutputStats struct {
+	// Entries is the number of entries written to the destination.
+	Entries int64
+	// Bytes is the number of bytes written to the destination.
+	Bytes int64
+	// Errors is the number of writes that failed.
+	Errors int64
+	// QueueDepth is the number of entries accepted but not yet written.
+	QueueDepth int
+	// Dropped is the number of accepted entries discarded without being
+	// written, e.g. when a buffer fails to flush or a shutdown deadline
+	// passes.
+	Dropped int64
+}
+
+// outputCounters accumulates the counters of OutputStats.
+type outputCounters struct {
+	entries atomic.Int64
+	bytes   atomic.Int64
+	errors  atomic.Int64
+	dropped atomic.Int64
+}
+
+// record counts entries totalling size bytes as written, or a failed write
+// if err is not nil.
+func (c *outputCounters) record(entries, size int, err error) {
+	if err != nil {
+		c.errors.Add(1)
+		return
+	}
+	c.entries.Add(int64(entries))
+	c.bytes.Add(int64(size))
+}
+
+// snapshot returns the counters with queueDepth.
+func (c *outputCounters) snapshot(queueDepth int) OutputStats {
+	return OutputStats{
+		Entries:    c.entries.Load(),
+		Bytes:      c.bytes.Load(),
+		Errors:     c.errors.Load(),
+		QueueDepth: queueDepth,
+		Dropped:    c.dropped.Load(),
+	}
+}
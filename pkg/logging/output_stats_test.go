@@ -0,0 +1,115 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriterOutput_Stats(t *testing.T) {
+	output := NewWriterOutput(&bytes.Buffer{})
+	_ = output.Write([]byte("one\n"))
+	_ = output.Write([]byte("two\n"))
+
+	failing := NewWriterOutput(failingWriter{})
+	_ = failing.Write([]byte("lost\n"))
+
+	if got, want := output.Stats(), (OutputStats{Entries: 2, Bytes: 8}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if got := failing.Stats(); got.Errors != 1 || got.Entries != 0 {
+		t.Errorf("expected one error, got %+v", got)
+	}
+}
+
+func TestFileOutput_Stats(t *testing.T) {
+	output, err := NewFileOutputWithConfig(FileOutputConfig{
+		Filename:     filepath.Join(t.TempDir(), "app.log"),
+		Sync:         SyncInterval,
+		SyncInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("failed to create file output: %v", err)
+	}
+	_ = output.Write([]byte("one\n"))
+	_ = output.Write([]byte("two\n"))
+
+	if got, want := output.Stats(), (OutputStats{QueueDepth: 2}); got != want {
+		t.Errorf("before flush: Stats() = %+v, want %+v", got, want)
+	}
+	if err := output.Flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if got, want := output.Stats(), (OutputStats{Entries: 2, Bytes: 8}); got != want {
+		t.Errorf("after flush: Stats() = %+v, want %+v", got, want)
+	}
+
+	_ = output.Close()
+	_ = output.Write([]byte("late\n"))
+	if got := output.Stats(); got.Errors != 1 {
+		t.Errorf("expected write after close to count as an error, got %+v", got)
+	}
+}
+
+func TestBufferedOutput_Stats(t *testing.T) {
+	sink := &failingOutput{}
+	output := NewBufferedOutput(sink, 10, 0)
+	_ = output.Write([]byte("abcd\n"))
+	_ = output.Write([]byte("efgh\n"))
+
+	if got, want := output.Stats(), (OutputStats{QueueDepth: 2}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	// The buffer is full, so the next entry flushes it first, and an entry
+	// larger than the buffer is written directly.
+	_ = output.Write([]byte("ijkl\n"))
+	_ = output.Write([]byte("a much longer entry\n"))
+	if got, want := output.Stats(), (OutputStats{Entries: 4, Bytes: 35}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+	if len(sink.written) != 3 || string(sink.written[0]) != "abcd\nefgh\n" {
+		t.Errorf("expected whole entries per write, got %q", sink.written)
+	}
+
+	sink.failing = true
+	_ = output.Write([]byte("lost\n"))
+	if err := output.Flush(); err == nil {
+		t.Fatal("expected flush error")
+	}
+	if got := output.Stats(); got.Errors != 1 || got.Dropped != 1 || got.QueueDepth != 0 {
+		t.Errorf("expected one error and one dropped entry, got %+v", got)
+	}
+}
+
+func TestAsyncOutput_Stats(t *testing.T) {
+	release := make(chan struct{})
+	sink := &blockingOutput{gate: release}
+	output := NewAsyncOutput(sink, 10)
+
+	for i := 0; i < 4; i++ {
+		_ = output.Write([]byte("entry\n"))
+	}
+	// One write is blocked in the sink; the rest are queued.
+	deadline := time.Now().Add(5 * time.Second)
+	for output.Stats().QueueDepth != 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := output.Stats().QueueDepth; got != 3 {
+		t.Fatalf("expected 3 queued writes, got %d", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	_, _ = output.StopWithTimeout(ctx)
+
+	if got, want := output.Stats(), (OutputStats{Entries: 1, Bytes: 6, Dropped: 3}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
@@ -2,18 +2,95 @@ package logging
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// writeEntry writes data to output, passing entry along if output is an
+// EntryOutput.
+func writeEntry(output Output, entry LogEntry, data []byte) error {
+	if eo, ok := output.(EntryOutput); ok {
+		return eo.WriteEntry(entry, data)
+	}
+	return output.Write(data)
+}
+
+// writeParsedEntries parses each line of data as a JSON entry and passes it
+// to write, for outputs that select entries by level but are reached
+// through Write, e.g. behind a BufferedOutput. Lines that are not JSON
+// entries are reported in the returned error. keys are the field keys the
+// entries were written with.
+func writeParsedEntries(data []byte, keys FieldKeys, write func(LogEntry, []byte) error) error {
+	var errs []error
+	for _, line := range splitJSONLines(data) {
+		entry, ok := ParseJSONEntryWithKeys(line, keys)
+		if !ok {
+			errs = append(errs, fmt.Errorf("cannot parse entry %q: use a JSON formatter", bytes.TrimSpace(line)))
+			continue
+		}
+		if err := write(entry, line); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// batchWriter is implemented by outputs whose writes form a byte stream,
+// such as writers and files, so consecutive entries can be passed to them
+// in one write. entries is the number of entries coalesced into data.
+type batchWriter interface {
+	writeBatch(data []byte, entries int) error
+}
+
+// writeBatch writes data, holding entries entries, to output.
+func writeBatch(output Output, data []byte, entries int) error {
+	if bw, ok := output.(batchWriter); ok {
+		return bw.writeBatch(data, entries)
+	}
+	return output.Write(data)
+}
+
+// entryFieldString returns the string form of field name on entry, looking
+// at the entry's fields first and then at the IDs carried by its context.
+func entryFieldString(entry LogEntry, name string) (string, bool) {
+	if v, ok := entry.Fields[name]; ok {
+		return fmt.Sprint(v), true
+	}
+
+	contextFields := contextFieldsFrom(entry.Context)
+	var value string
+	switch name {
+	case "trace_id":
+		value = contextFields.TraceID
+	case "request_id":
+		value = contextFields.RequestID
+	case "correlation_id":
+		value = contextFields.CorrelationID
+	}
+	return value, value != ""
+}
+
 // WriterOutput wraps an io.Writer to implement the Output interface.
 type WriterOutput struct {
 	writer io.Writer
 	mu     sync.Mutex
+	stats  outputCounters
 }
 
 // NewWriterOutput creates a new WriterOutput.
@@ -23,12 +100,22 @@ func NewWriterOutput(w io.Writer) *WriterOutput {
 
 // Write writes data to the underlying writer.
 func (o *WriterOutput) Write(data []byte) error {
+	return o.writeBatch(data, 1)
+}
+
+func (o *WriterOutput) writeBatch(data []byte, entries int) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 	_, err := o.writer.Write(data)
+	o.stats.record(entries, len(data), err)
 	return err
 }
 
+// Stats returns the output's counters.
+func (o *WriterOutput) Stats() OutputStats {
+	return o.stats.snapshot(0)
+}
+
 // Close closes the output if the underlying writer implements io.Closer.
 func (o *WriterOutput) Close() error {
 	if closer, ok := o.writer.(io.Closer); ok {
@@ -37,85 +124,262 @@ func (o *WriterOutput) Close() error {
 	return nil
 }
 
+// FileSyncPolicy controls when a FileOutput forces entries to stable
+// storage with fsync.
+type FileSyncPolicy int
+
+const (
+	// SyncAlways writes and syncs every entry before Write returns. It is the
+	// default, the most durable and the slowest.
+	SyncAlways FileSyncPolicy = iota
+	// SyncInterval buffers entries in memory and writes and syncs them in
+	// batches every SyncInterval, and writes them early when the buffer
+	// fills. A crash can lose up to one interval of entries.
+	SyncInterval
+	// SyncNever writes every entry but leaves syncing to the operating
+	// system. Entries survive a process crash but not a power loss.
+	SyncNever
+)
+
+// FileOutputConfig configures a FileOutput.
+type FileOutputConfig struct {
+	// Filename is the file to append to. Its directory is created if needed.
+	Filename string
+	// Sync is the fsync policy. Defaults to SyncAlways.
+	Sync FileSyncPolicy
+	// SyncInterval is how often buffered entries are written and synced with
+	// SyncInterval. Defaults to one second.
+	SyncInterval time.Duration
+	// BufferSize is the size of the in-memory buffer used with
+	// SyncInterval. Defaults to 64 KiB.
+	BufferSize int
+}
+
 // FileOutput writes log entries to a file.
+//
+// Example:
+//
+//	output, err := logging.NewFileOutputWithConfig(logging.FileOutputConfig{
+//		Filename:     "/var/log/myapp/app.log",
+//		Sync:         logging.SyncInterval,
+//		SyncInterval: 500 * time.Millisecond,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer output.Close() // writes and syncs what is still buffered
 type FileOutput struct {
 	filename string
 	file     *os.File
 	mu       sync.Mutex
+	config   FileOutputConfig
+	buffer   *bufio.Writer
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	stats    outputCounters
+	// pending and pendingBytes count the entries in buffer.
+	pending      int
+	pendingBytes int
 }
 
-// NewFileOutput creates a new FileOutput that writes to the specified file.
+// NewFileOutput creates a new FileOutput that writes to the specified file
+// and syncs every entry.
 func NewFileOutput(filename string) (*FileOutput, error) {
+	return NewFileOutputWithConfig(FileOutputConfig{Filename: filename})
+}
+
+// NewFileOutputWithConfig creates a FileOutput with the given sync policy.
+func NewFileOutputWithConfig(config FileOutputConfig) (*FileOutput, error) {
+	if config.SyncInterval <= 0 {
+		config.SyncInterval = time.Second
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = 64 << 10
+	}
+
 	// Create directory if it doesn't exist
-	dir := filepath.Dir(filename)
+	dir := filepath.Dir(config.Filename)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
-	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	file, err := os.OpenFile(config.Filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open log file: %w", err)
 	}
 
-	return &FileOutput{
-		filename: filename,
+	o := &FileOutput{
+		filename: config.Filename,
 		file:     file,
-	}, nil
+		config:   config,
+	}
+	if config.Sync == SyncInterval {
+		o.buffer = bufio.NewWriterSize(file, config.BufferSize)
+		o.stop = make(chan struct{})
+		o.done = make(chan struct{})
+		go o.syncLoop()
+	}
+	return o, nil
 }
 
-// Write writes data to the file.
+// Write writes data to the file, or to the buffer with SyncInterval.
 func (o *FileOutput) Write(data []byte) error {
+	return o.writeBatch(data, 1)
+}
+
+func (o *FileOutput) writeBatch(data []byte, entries int) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
 	if o.file == nil {
+		o.stats.errors.Add(1)
 		return fmt.Errorf("file output is closed")
 	}
 
+	if o.buffer != nil {
+		if _, err := o.buffer.Write(data); err != nil {
+			o.dropPendingLocked(err)
+			return fmt.Errorf("failed to write to log file: %w", err)
+		}
+		o.pending += entries
+		o.pendingBytes += len(data)
+		return nil
+	}
+
 	_, err := o.file.Write(data)
 	if err != nil {
+		o.stats.record(0, 0, err)
 		return fmt.Errorf("failed to write to log file: %w", err)
 	}
 
-	// Sync to ensure data is written to disk
-	return o.file.Sync()
+	if o.config.Sync != SyncNever {
+		// Sync to ensure data is written to disk
+		err = o.file.Sync()
+	}
+	o.stats.record(entries, len(data), err)
+	return err
 }
 
-// Close closes the file.
-func (o *FileOutput) Close() error {
+// Stats returns the output's counters. QueueDepth is the number of entries
+// buffered with SyncInterval.
+func (o *FileOutput) Stats() OutputStats {
 	o.mu.Lock()
 	defer o.mu.Unlock()
+	return o.stats.snapshot(o.pending)
+}
 
-	if o.file != nil {
-		err := o.file.Close()
-		o.file = nil
-		return err
+// dropPendingLocked counts a failed flush, after which the buffered entries
+// are lost. o.mu must be held.
+func (o *FileOutput) dropPendingLocked(err error) {
+	o.stats.record(0, 0, err)
+	o.stats.dropped.Add(int64(o.pending))
+	o.pending, o.pendingBytes = 0, 0
+}
+
+// Flush writes any buffered entries and syncs the file, whatever the
+// policy.
+func (o *FileOutput) Flush() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.file == nil {
+		return fmt.Errorf("file output is closed")
+	}
+	return o.flushLocked()
+}
+
+// flushLocked writes the buffer and syncs the file. o.mu must be held.
+func (o *FileOutput) flushLocked() error {
+	if o.buffer != nil {
+		if err := o.buffer.Flush(); err != nil {
+			o.dropPendingLocked(err)
+			return fmt.Errorf("failed to write to log file: %w", err)
+		}
+		o.stats.record(o.pending, o.pendingBytes, nil)
+		o.pending, o.pendingBytes = 0, 0
+	}
+	if err := o.file.Sync(); err != nil {
+		o.stats.record(0, 0, err)
+		return fmt.Errorf("failed to sync log file: %w", err)
 	}
 	return nil
 }
 
+// syncLoop flushes the buffer every SyncInterval until Close.
+func (o *FileOutput) syncLoop() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(o.config.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			o.mu.Lock()
+			if o.file != nil {
+				_ = o.flushLocked()
+			}
+			o.mu.Unlock()
+		case <-o.stop:
+			return
+		}
+	}
+}
+
+// Close writes and syncs any buffered entries and closes the file.
+func (o *FileOutput) Close() error {
+	if o.stop != nil {
+		o.stopOnce.Do(func() { close(o.stop) })
+		<-o.done
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.file == nil {
+		return nil
+	}
+
+	var err error
+	if o.buffer != nil {
+		err = o.flushLocked()
+	}
+	if closeErr := o.file.Close(); err == nil {
+		err = closeErr
+	}
+	o.file = nil
+	return err
+}
+
 // BufferedOutput buffers writes and flushes them periodically or when full.
+// Each flush passes every buffered entry to the underlying output in a
+// single Write.
 type BufferedOutput struct {
 	output        Output
-	buffer        *bufio.Writer
+	buffer        []byte
 	bufferSize    int
 	flushTimer    *time.Timer
 	flushInterval time.Duration
 	mu            sync.Mutex
 	closed        bool
+	stats         outputCounters
+	// pending and pendingBytes count the entries in buffer.
+	pending      int
+	pendingBytes int
 }
 
 // NewBufferedOutput creates a new BufferedOutput with the specified buffer size and flush interval.
 func NewBufferedOutput(output Output, bufferSize int, flushInterval time.Duration) *BufferedOutput {
+	if bufferSize <= 0 {
+		bufferSize = 4096
+	}
 	bo := &BufferedOutput{
 		output:        output,
+		buffer:        make([]byte, 0, bufferSize),
 		bufferSize:    bufferSize,
 		flushInterval: flushInterval,
 	}
 
-	// Create a buffer that writes to our output
-	bo.buffer = bufio.NewWriterSize(&outputWriter{output: output}, bufferSize)
-
 	// Start periodic flush timer
 	if flushInterval > 0 {
 		bo.flushTimer = time.AfterFunc(flushInterval, bo.periodicFlush)
@@ -124,30 +388,68 @@ func NewBufferedOutput(output Output, bufferSize int, flushInterval time.Duratio
 	return bo
 }
 
-// outputWriter is a helper to make Output compatible with io.Writer
-type outputWriter struct {
-	output Output
+// Write writes data to the buffer.
+func (bo *BufferedOutput) Write(data []byte) error {
+	return bo.writeBatch(data, 1)
 }
 
-func (ow *outputWriter) Write(p []byte) (n int, err error) {
-	err = ow.output.Write(p)
-	if err != nil {
-		return 0, err
+func (bo *BufferedOutput) writeBatch(data []byte, entries int) error {
+	bo.mu.Lock()
+	defer bo.mu.Unlock()
+
+	if bo.closed {
+		bo.stats.errors.Add(1)
+		return fmt.Errorf("buffered output is closed")
+	}
+
+	// Flush first rather than split data across writes, so entries reach
+	// the output whole and are counted when they do.
+	if len(bo.buffer)+len(data) > bo.bufferSize && len(bo.buffer) > 0 {
+		if err := bo.flushLocked(); err != nil {
+			return err
+		}
+	}
+	if len(data) > bo.bufferSize {
+		err := writeBatch(bo.output, data, entries)
+		bo.stats.record(entries, len(data), err)
+		return err
 	}
-	return len(p), nil
+	bo.buffer = append(bo.buffer, data...)
+	bo.pending += entries
+	bo.pendingBytes += len(data)
+	return nil
 }
 
-// Write writes data to the buffer.
-func (bo *BufferedOutput) Write(data []byte) error {
+// Stats returns the output's counters. QueueDepth is the number of entries
+// in the buffer.
+func (bo *BufferedOutput) Stats() OutputStats {
 	bo.mu.Lock()
 	defer bo.mu.Unlock()
+	return bo.stats.snapshot(bo.pending)
+}
 
-	if bo.closed {
-		return fmt.Errorf("buffered output is closed")
+// flushLocked writes the buffer to the output. bo.mu must be held.
+func (bo *BufferedOutput) flushLocked() error {
+	if len(bo.buffer) == 0 {
+		return nil
 	}
+	err := writeBatch(bo.output, bo.buffer, bo.pending)
+	bo.buffer = bo.buffer[:0]
+	if err != nil {
+		bo.dropPendingLocked(err)
+		return err
+	}
+	bo.stats.record(bo.pending, bo.pendingBytes, nil)
+	bo.pending, bo.pendingBytes = 0, 0
+	return nil
+}
 
-	_, err := bo.buffer.Write(data)
-	return err
+// dropPendingLocked counts a failed flush, after which the buffered entries
+// are lost. bo.mu must be held.
+func (bo *BufferedOutput) dropPendingLocked(err error) {
+	bo.stats.record(0, 0, err)
+	bo.stats.dropped.Add(int64(bo.pending))
+	bo.pending, bo.pendingBytes = 0, 0
 }
 
 // Flush forces all buffered data to be written to the underlying output.
@@ -159,7 +461,7 @@ func (bo *BufferedOutput) Flush() error {
 		return fmt.Errorf("buffered output is closed")
 	}
 
-	return bo.buffer.Flush()
+	return bo.flushLocked()
 }
 
 // periodicFlush is called by the timer to flush buffered data.
@@ -191,7 +493,7 @@ func (bo *BufferedOutput) Close() error {
 	}
 
 	// Flush any remaining data
-	if err := bo.buffer.Flush(); err != nil {
+	if err := bo.flushLocked(); err != nil {
 		return err
 	}
 
@@ -224,6 +526,21 @@ func (mo *MultiOutput) Write(data []byte) error {
 	return firstErr
 }
 
+// WriteEntry writes data to all outputs, passing entry to those that
+// implement EntryOutput.
+func (mo *MultiOutput) WriteEntry(entry LogEntry, data []byte) error {
+	mo.mu.RLock()
+	defer mo.mu.RUnlock()
+
+	var firstErr error
+	for _, output := range mo.outputs {
+		if err := writeEntry(output, entry, data); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
 // Close closes all outputs.
 func (mo *MultiOutput) Close() error {
 	mo.mu.Lock()
@@ -259,40 +576,119 @@ func (mo *MultiOutput) RemoveOutput(output Output) {
 }
 
 // AsyncOutput processes writes asynchronously in a background goroutine.
+//
+// Writes queued while the output is busy are coalesced: a WriterOutput,
+// FileOutput or BufferedOutput receives them in a single Write of up to
+// asyncCoalesceBytes, saving a syscall, and with SyncAlways an fsync, per
+// entry. Other outputs receive every entry separately.
 type AsyncOutput struct {
 	output Output
-	worker *AsyncWorker[[]byte]
+	worker *AsyncWorker[asyncWrite]
+	stats  outputCounters
+	// coalesced is the worker's buffer for coalescing writes.
+	coalesced []byte
+}
+
+// Limits on the writes an AsyncOutput coalesces into one.
+const (
+	asyncCoalesceEntries = 256
+	asyncCoalesceBytes   = 64 << 10
+)
+
+// asyncWrite is a queued write. entry is nil for plain Write calls.
+type asyncWrite struct {
+	entry *LogEntry
+	data  []byte
 }
 
 // NewAsyncOutput creates a new AsyncOutput with the specified queue size.
 func NewAsyncOutput(output Output, queueSize int) *AsyncOutput {
 	ao := &AsyncOutput{output: output}
 
-	ao.worker = NewAsyncWorker(AsyncWorkerConfig[[]byte]{
+	config := AsyncWorkerConfig[asyncWrite]{
 		QueueSize: queueSize,
-		Processor: func(data []byte) error {
-			return ao.output.Write(data)
-		},
-	})
+		Processor: ao.write,
+	}
+	if _, ok := output.(batchWriter); ok {
+		config.BatchProcessor = ao.writeBatch
+		config.MaxBatch = asyncCoalesceEntries
+	}
+	ao.worker = NewAsyncWorker(config)
 
 	return ao
 }
 
+// writeBatch writes the queued writes in batch to the underlying byte
+// stream, coalesced.
+func (ao *AsyncOutput) writeBatch(batch []asyncWrite) error {
+	if len(batch) == 1 {
+		return ao.write(batch[0])
+	}
+
+	buf, entries := ao.coalesced[:0], 0
+	flush := func() {
+		if entries > 0 {
+			err := writeBatch(ao.output, buf, entries)
+			ao.stats.record(entries, len(buf), err)
+			buf, entries = buf[:0], 0
+		}
+	}
+	for _, w := range batch {
+		buf = append(buf, w.data...)
+		entries++
+		if len(buf) >= asyncCoalesceBytes {
+			flush()
+		}
+	}
+	flush()
+	ao.coalesced = buf
+	return nil
+}
+
+// write writes w to the underlying output.
+func (ao *AsyncOutput) write(w asyncWrite) error {
+	var err error
+	if w.entry != nil {
+		err = writeEntry(ao.output, *w.entry, w.data)
+	} else {
+		err = ao.output.Write(w.data)
+	}
+	ao.stats.record(1, len(w.data), err)
+	return err
+}
+
 // Write queues data for asynchronous writing.
 func (ao *AsyncOutput) Write(data []byte) error {
+	return ao.submit(asyncWrite{data: data})
+}
+
+// WriteEntry queues data and its entry for asynchronous writing.
+func (ao *AsyncOutput) WriteEntry(entry LogEntry, data []byte) error {
+	return ao.submit(asyncWrite{entry: &entry, data: data})
+}
+
+func (ao *AsyncOutput) submit(w asyncWrite) error {
 	if ao.worker.IsClosed() {
+		ao.stats.errors.Add(1)
 		return fmt.Errorf("async output is closed")
 	}
 
 	// Make a copy of the data since it might be modified by the caller
-	dataCopy := make([]byte, len(data))
-	copy(dataCopy, data)
+	data := w.data
+	w.data = make([]byte, len(data))
+	copy(w.data, data)
 
-	if ao.worker.Submit(dataCopy) {
+	if ao.worker.Submit(w) {
 		return nil
 	}
 	// Queue is full, write synchronously as fallback
-	return ao.output.Write(data)
+	return ao.write(w)
+}
+
+// Stats returns the output's counters. QueueDepth is the number of queued
+// writes, and Dropped counts those discarded by StopWithTimeout.
+func (ao *AsyncOutput) Stats() OutputStats {
+	return ao.stats.snapshot(ao.worker.QueueSize())
 }
 
 // Stop gracefully shuts down the async processing.
@@ -300,6 +696,15 @@ func (ao *AsyncOutput) Stop() error {
 	return ao.worker.Stop()
 }
 
+// StopWithTimeout shuts down the async processing, writing queued entries
+// until ctx is done and dropping the rest. It returns the number of dropped
+// entries; see AsyncWorker.StopWithTimeout.
+func (ao *AsyncOutput) StopWithTimeout(ctx context.Context) (int, error) {
+	dropped, err := ao.worker.StopWithTimeout(ctx)
+	ao.stats.dropped.Add(int64(dropped))
+	return dropped, err
+}
+
 // Close stops async processing and closes the underlying output.
 func (ao *AsyncOutput) Close() error {
 	if err := ao.Stop(); err != nil {
@@ -308,23 +713,174 @@ func (ao *AsyncOutput) Close() error {
 	return ao.output.Close()
 }
 
+// SamplingOutput forwards one in every rate writes to the underlying output
+// and drops the rest. Entries with a trace ID, from a trace_id field or the
+// context, are kept or dropped together: the decision is a hash of the trace
+// ID, so a kept trace has all of its entries, in every process sampling at
+// the same rate.
+type SamplingOutput struct {
+	output  Output
+	rate    uint64
+	counter atomic.Uint64
+	// keepWarnings forwards every entry at WarnLevel and above.
+	keepWarnings bool
+}
+
+// NewSamplingOutput creates a SamplingOutput. A rate of 1 or less forwards
+// every write.
+func NewSamplingOutput(output Output, rate int) *SamplingOutput {
+	if rate < 1 {
+		rate = 1
+	}
+	return &SamplingOutput{output: output, rate: uint64(rate)}
+}
+
+// Write forwards data if it falls on the sampling interval.
+func (so *SamplingOutput) Write(data []byte) error {
+	if so.counter.Add(1)%so.rate != 0 {
+		return nil
+	}
+	return so.output.Write(data)
+}
+
+// WriteEntry forwards data and entry if their trace is sampled or, without
+// a trace ID, if they fall on the sampling interval.
+func (so *SamplingOutput) WriteEntry(entry LogEntry, data []byte) error {
+	if so.keepWarnings && entry.Level >= WarnLevel {
+		return writeEntry(so.output, entry, data)
+	}
+	if traceID, ok := entryFieldString(entry, "trace_id"); ok && traceID != "" {
+		if !traceSampled(traceID, so.rate) {
+			return nil
+		}
+	} else if so.counter.Add(1)%so.rate != 0 {
+		return nil
+	}
+	return writeEntry(so.output, entry, data)
+}
+
+// traceSampled reports whether the trace with traceID is kept when sampling
+// one in rate traces. It depends only on its arguments.
+func traceSampled(traceID string, rate uint64) bool {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(traceID))
+	return h.Sum64()%rate == 0
+}
+
+// Close closes the underlying output.
+func (so *SamplingOutput) Close() error {
+	return so.output.Close()
+}
+
+// RotationInterval starts new files at fixed clock boundaries.
+type RotationInterval int
+
+const (
+	// RotateNone rotates only on MaxSize and MaxAge.
+	RotateNone RotationInterval = iota
+	// RotateHourly starts a new file at the top of every hour.
+	RotateHourly
+	// RotateDaily starts a new file at midnight.
+	RotateDaily
+)
+
+// next returns the first boundary after t, in t's location, or the zero
+// time for RotateNone.
+func (ri RotationInterval) next(t time.Time) time.Time {
+	switch ri {
+	case RotateHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+	case RotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// RotatingFileConfig configures a RotatingFileOutput.
+type RotatingFileConfig struct {
+	// Pattern is the file name, expanded each time a file is opened. It
+	// supports the time verbs %Y, %m, %d, %H, %M and %S, %s for the full
+	// time as 2006-01-02-15-04-05, %% for a percent sign, and the
+	// {hostname} and {pid} tokens, e.g. "/var/log/app-{hostname}-%Y-%m-%d.log".
+	// Each file rotated out is renamed to its name plus the rotation time,
+	// e.g. app.log.2024-05-01-00-00-00, so with a pattern without time verbs
+	// the file being written keeps one path.
+	Pattern string
+	// MaxSize and MaxAge start a new file once the current one would exceed
+	// that many bytes or was opened longer ago; zero disables the limit.
+	MaxSize int64
+	MaxAge  time.Duration
+	// Interval starts a new file at every hour or day boundary of Clock.
+	Interval RotationInterval
+	// Compress gzips each file in the background once it has been rotated
+	// out, replacing it with the file name plus .gz, e.g.
+	// app.log.2024-05-01-00-00-00.gz. The file open at Close is left as is.
+	Compress bool
+	// MaxBackups keeps at most this many rotated files, deleting the oldest;
+	// zero keeps them all.
+	MaxBackups int
+	// MaxBackupAge deletes rotated files last written longer ago; zero keeps
+	// them. Unlike MaxAge, it never affects the file being written. Old
+	// files are also removed when the output is created, so retention
+	// applies to files left by earlier runs.
+	MaxBackupAge time.Duration
+	// Symlink, if set, is a symbolic link kept pointing at the file being
+	// written, e.g. "/var/log/app.log" for app-%s.log segments, so tail -F
+	// and collectors configured with a fixed path follow rotations. It is
+	// replaced atomically; an existing regular file at that path is left
+	// alone and reported to OnError.
+	Symlink string
+	// OnError is called with the name of a rotated file that could not be
+	// compressed or deleted, or of a Symlink that could not be updated; the
+	// file is kept.
+	OnError func(filename string, err error)
+	// Clock supplies the time used in file names, rotation boundaries and
+	// ages. Defaults to SystemClock.
+	Clock Clock
+}
+
 // RotatingFileOutput writes to files with automatic rotation based on size or time.
+//
+// Example:
+//
+//	output := logging.NewRotatingFileOutputWithConfig(logging.RotatingFileConfig{
+//		Pattern:    "/var/log/myapp/app-%Y-%m-%d.log",
+//		Interval:   logging.RotateDaily,
+//		Compress:   true,
+//		MaxBackups: 10,
+//	})
+//	defer output.Close()
 type RotatingFileOutput struct {
-	pattern     string        // File pattern with placeholders
-	maxSize     int64         // Maximum size in bytes
-	maxAge      time.Duration // Maximum age
+	config      RotatingFileConfig
+	name        fileNamePattern
 	current     *os.File
 	currentSize int64
+	filename    string // name of the last file opened
+	opened      time.Time
+	nextRotate  time.Time // next Interval boundary
+	rotated     string    // file closed by rotate, compressed once the next one is open
+	background  sync.WaitGroup
+	cleanupMu   sync.Mutex // serializes compression and retention
 	mu          sync.Mutex
 }
 
 // NewRotatingFileOutput creates a new rotating file output.
 func NewRotatingFileOutput(pattern string, maxSize int64, maxAge time.Duration) *RotatingFileOutput {
-	return &RotatingFileOutput{
-		pattern: pattern,
-		maxSize: maxSize,
-		maxAge:  maxAge,
+	return NewRotatingFileOutputWithConfig(RotatingFileConfig{Pattern: pattern, MaxSize: maxSize, MaxAge: maxAge})
+}
+
+// NewRotatingFileOutputWithConfig creates a rotating file output from config.
+func NewRotatingFileOutputWithConfig(config RotatingFileConfig) *RotatingFileOutput {
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	rfo := &RotatingFileOutput{config: config, name: newFileNamePattern(config.Pattern)}
+	if rfo.hasRetention() {
+		rfo.background.Add(1)
+		go rfo.afterRotate("")
 	}
+	return rfo
 }
 
 // Write writes data to the current file, rotating if necessary.
@@ -365,23 +921,19 @@ func (rfo *RotatingFileOutput) shouldRotate(dataSize int64) bool {
 	}
 
 	// Check size limit
-	if rfo.maxSize > 0 && rfo.currentSize+dataSize > rfo.maxSize {
+	if rfo.config.MaxSize > 0 && rfo.currentSize+dataSize > rfo.config.MaxSize {
 		return true
 	}
 
-	// Check age limit (simplified - would need to track file creation time)
-	if rfo.maxAge > 0 {
-		if stat, err := rfo.current.Stat(); err == nil {
-			if time.Since(stat.ModTime()) > rfo.maxAge {
-				return true
-			}
-		}
+	now := rfo.config.Clock.Now()
+	if !rfo.nextRotate.IsZero() && !now.Before(rfo.nextRotate) {
+		return true
 	}
-
-	return false
+	return rfo.config.MaxAge > 0 && now.Sub(rfo.opened) > rfo.config.MaxAge
 }
 
-// rotate closes the current file and prepares for a new one.
+// rotate closes the current file and renames it to its archive name,
+// preparing for a new one.
 func (rfo *RotatingFileOutput) rotate() error {
 	if rfo.current != nil {
 		if err := rfo.current.Close(); err != nil {
@@ -389,14 +941,35 @@ func (rfo *RotatingFileOutput) rotate() error {
 		}
 		rfo.current = nil
 		rfo.currentSize = 0
+
+		archive := archiveName(rfo.filename, rfo.config.Clock.Now())
+		if err := os.Rename(rfo.filename, archive); err != nil {
+			return fmt.Errorf("failed to rename rotated file: %w", err)
+		}
+		rfo.rotated = archive
 	}
 	return nil
 }
 
+// archiveName returns the name a rotated file is renamed to: filename plus
+// t and, if that name or its compressed form is taken, a sequence number.
+func archiveName(filename string, t time.Time) string {
+	base := filename + "." + t.Format(rotatingTimeFormat)
+	archive := base
+	for seq := 1; ; seq++ {
+		_, err := os.Lstat(archive)
+		_, gzErr := os.Lstat(archive + ".gz")
+		if os.IsNotExist(err) && os.IsNotExist(gzErr) {
+			return archive
+		}
+		archive = base + "." + strconv.Itoa(seq)
+	}
+}
+
 // openNew opens a new log file.
 func (rfo *RotatingFileOutput) openNew() error {
-	// Generate filename from pattern (simplified)
-	filename := rfo.generateFilename()
+	now := rfo.config.Clock.Now()
+	filename := rfo.name.expand(now)
 
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(filename)
@@ -410,6 +983,20 @@ func (rfo *RotatingFileOutput) openNew() error {
 	}
 
 	rfo.current = file
+	rfo.filename = filename
+	rfo.opened = now
+	rfo.nextRotate = rfo.config.Interval.next(now)
+	if rfo.config.Symlink != "" {
+		if err := updateSymlink(rfo.config.Symlink, filename); err != nil {
+			rfo.reportError(rfo.config.Symlink, err)
+		}
+	}
+
+	if rfo.rotated != "" && rfo.rotated != filename && (rfo.config.Compress || rfo.hasRetention()) {
+		rfo.background.Add(1)
+		go rfo.afterRotate(rfo.rotated)
+	}
+	rfo.rotated = ""
 
 	// Get current size if file already exists
 	if stat, err := file.Stat(); err == nil {
@@ -419,27 +1006,655 @@ func (rfo *RotatingFileOutput) openNew() error {
 	return nil
 }
 
-// generateFilename generates a filename from the pattern.
-func (rfo *RotatingFileOutput) generateFilename() string {
-	// Simple implementation - replace placeholders with current timestamp
-	now := time.Now()
-	filename := rfo.pattern
+// updateSymlink points link at target by renaming a new link over it, so
+// readers never find link missing. target is stored relative to link's
+// directory when both are in the same one.
+func updateSymlink(link, target string) error {
+	if info, err := os.Lstat(link); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("failed to update symlink: %s exists and is not a symlink", link)
+	}
 
-	// Replace common placeholders
-	filename = fmt.Sprintf(filename, now.Format("2006-01-02-15-04-05"))
+	if filepath.Dir(filepath.Clean(link)) == filepath.Dir(filepath.Clean(target)) {
+		target = filepath.Base(target)
+	} else if abs, err := filepath.Abs(target); err == nil {
+		target = abs
+	}
+	if current, err := os.Readlink(link); err == nil && current == target {
+		return nil
+	}
 
-	return filename
+	tmp := link + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return fmt.Errorf("failed to create symlink: %w", err)
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to update symlink: %w", err)
+	}
+	return nil
 }
 
-// Close closes the current file.
-func (rfo *RotatingFileOutput) Close() error {
+// rotatingTimeFormat is the layout of the %s verb in RotatingFileOutput
+// patterns.
+const rotatingTimeFormat = "2006-01-02-15-04-05"
+
+func (rfo *RotatingFileOutput) hasRetention() bool {
+	return rfo.config.MaxBackups > 0 || rfo.config.MaxBackupAge > 0
+}
+
+// afterRotate compresses a rotated file, if enabled, then applies the
+// retention limits. An empty filename only applies retention.
+func (rfo *RotatingFileOutput) afterRotate(filename string) {
+	defer rfo.background.Done()
+	rfo.cleanupMu.Lock()
+	defer rfo.cleanupMu.Unlock()
+
+	// Jobs may run out of order, so retention can already have deleted the
+	// file.
+	if filename != "" && rfo.config.Compress {
+		if err := gzipFile(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+			rfo.reportError(filename, err)
+		}
+	}
+	if rfo.hasRetention() {
+		rfo.removeOldBackups()
+	}
+}
+
+func (rfo *RotatingFileOutput) reportError(filename string, err error) {
+	if rfo.config.OnError != nil {
+		rfo.config.OnError(filename, err)
+	}
+}
+
+// removeOldBackups deletes rotated files beyond MaxBackups or older than
+// MaxBackupAge.
+func (rfo *RotatingFileOutput) removeOldBackups() {
 	rfo.mu.Lock()
-	defer rfo.mu.Unlock()
+	current := rfo.filename
+	rfo.mu.Unlock()
 
+	backups, err := rfo.backups(current)
+	if err != nil {
+		rfo.reportError(filepath.Dir(rfo.config.Pattern), err)
+		return
+	}
+
+	var remove, keep []string
+	now := rfo.config.Clock.Now()
+	for _, backup := range backups {
+		if rfo.config.MaxBackupAge > 0 {
+			if info, err := os.Stat(backup); err == nil && now.Sub(info.ModTime()) > rfo.config.MaxBackupAge {
+				remove = append(remove, backup)
+				continue
+			}
+		}
+		keep = append(keep, backup)
+	}
+	if rfo.config.MaxBackups > 0 && len(keep) > rfo.config.MaxBackups {
+		remove = append(remove, keep[:len(keep)-rfo.config.MaxBackups]...)
+	}
+
+	for _, backup := range remove {
+		if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+			rfo.reportError(backup, fmt.Errorf("failed to remove rotated file: %w", err))
+		}
+	}
+}
+
+// backups lists the files named by the pattern, compressed or not, oldest
+// first, excluding current. Only the directory the pattern currently
+// expands to is searched; files that don't match the pattern are left
+// alone.
+func (rfo *RotatingFileOutput) backups(current string) ([]string, error) {
+	dir := filepath.Dir(rfo.name.expand(rfo.config.Clock.Now()))
+	matcher := rfo.name.matcher()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rotated files: %w", err)
+	}
+
+	type backup struct{ stamp, path string }
+	var found []backup
+	for _, e := range entries {
+		if e.IsDir() || e.Type()&os.ModeSymlink != 0 {
+			continue
+		}
+		stamp, ok := matcher.key(e.Name())
+		if !ok {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if current != "" && path == filepath.Clean(current) {
+			continue
+		}
+		found = append(found, backup{stamp: stamp, path: path})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].stamp != found[j].stamp {
+			return found[i].stamp < found[j].stamp
+		}
+		return found[i].path < found[j].path
+	})
+
+	paths := make([]string, len(found))
+	for i, b := range found {
+		paths[i] = b.path
+	}
+	return paths, nil
+}
+
+// gzipFile replaces filename with a gzip-compressed filename.gz. The
+// compressed file is written under a temporary name first, so a reader never
+// sees a partial archive.
+func gzipFile(filename string) error {
+	src, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open rotated file: %w", err)
+	}
+	defer src.Close()
+
+	tmp := filename + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create compressed file: %w", err)
+	}
+	zw := gzip.NewWriter(dst)
+	zw.Name = filepath.Base(filename)
+	_, err = io.Copy(zw, src)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, filename+".gz")
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to compress rotated file: %w", err)
+	}
+	return os.Remove(filename)
+}
+
+// Close closes the current file and waits for rotated files to be
+// compressed and old ones deleted.
+func (rfo *RotatingFileOutput) Close() error {
+	rfo.mu.Lock()
+	var err error
 	if rfo.current != nil {
-		err := rfo.current.Close()
+		err = rfo.current.Close()
 		rfo.current = nil
 		rfo.currentSize = 0
+	}
+	rfo.mu.Unlock()
+
+	rfo.background.Wait()
+	return err
+}
+
+// SyslogFormat selects the syslog message format.
+type SyslogFormat int
+
+const (
+	// SyslogRFC3164 is the traditional BSD syslog format.
+	SyslogRFC3164 SyslogFormat = iota
+	// SyslogRFC5424 is the structured IETF syslog format.
+	SyslogRFC5424
+)
+
+// SyslogFacility is a syslog facility code.
+type SyslogFacility int
+
+// Syslog facilities as defined in RFC 5424. The kernel facility (0) is
+// reserved for the kernel, so the zero value selects SyslogFacilityUser.
+const (
+	SyslogFacilityUser     SyslogFacility = 1
+	SyslogFacilityMail     SyslogFacility = 2
+	SyslogFacilityDaemon   SyslogFacility = 3
+	SyslogFacilityAuth     SyslogFacility = 4
+	SyslogFacilitySyslog   SyslogFacility = 5
+	SyslogFacilityLPR      SyslogFacility = 6
+	SyslogFacilityNews     SyslogFacility = 7
+	SyslogFacilityUUCP     SyslogFacility = 8
+	SyslogFacilityCron     SyslogFacility = 9
+	SyslogFacilityAuthPriv SyslogFacility = 10
+	SyslogFacilityFTP      SyslogFacility = 11
+	SyslogFacilityLocal0   SyslogFacility = 16
+	SyslogFacilityLocal1   SyslogFacility = 17
+	SyslogFacilityLocal2   SyslogFacility = 18
+	SyslogFacilityLocal3   SyslogFacility = 19
+	SyslogFacilityLocal4   SyslogFacility = 20
+	SyslogFacilityLocal5   SyslogFacility = 21
+	SyslogFacilityLocal6   SyslogFacility = 22
+	SyslogFacilityLocal7   SyslogFacility = 23
+)
+
+// syslogSeverity maps a level to a syslog severity code.
+func syslogSeverity(level Level) int {
+	switch {
+	case level >= CriticalLevel:
+		return 2 // crit
+	case level >= ErrorLevel:
+		return 3 // err
+	case level >= WarnLevel:
+		return 4 // warning
+	case level >= InfoLevel:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// SyslogConfig configures a SyslogOutput.
+type SyslogConfig struct {
+	// Network is "udp", "tcp", "unixgram" or "unix". Empty connects to the
+	// local syslog daemon's unix socket.
+	Network string
+	// Address is the collector address, e.g. "logs.example.com:514", or the
+	// socket path for unix networks.
+	Address string
+	// TLSConfig enables TLS for "tcp" connections.
+	TLSConfig *tls.Config
+	// Format is the message format. Defaults to SyslogRFC3164.
+	Format SyslogFormat
+	// Facility defaults to SyslogFacilityUser.
+	Facility SyslogFacility
+	// AppName defaults to the program name.
+	AppName string
+	// Hostname defaults to os.Hostname.
+	Hostname string
+}
+
+// SyslogOutput sends log entries to a syslog daemon or remote collector.
+// Entry levels map to syslog severities; entries written with plain Write
+// are sent at INFO severity. Stream connections are re-established once on
+// write failure.
+//
+// Example:
+//
+//	syslog, err := logging.NewSyslogOutput(logging.SyslogConfig{
+//		Network:   "tcp",
+//		Address:   "logs.example.com:6514",
+//		TLSConfig: &tls.Config{},
+//		Format:    logging.SyslogRFC5424,
+//		Facility:  logging.SyslogFacilityLocal0,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	output := logging.NewMultiOutput(logging.NewWriterOutput(os.Stdout), syslog)
+type SyslogOutput struct {
+	config   SyslogConfig
+	network  string
+	address  string
+	hostname string
+	pid      int
+	conn     net.Conn
+	mu       sync.Mutex
+}
+
+// syslogSocketPaths are the usual locations of the local syslog socket.
+var syslogSocketPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// NewSyslogOutput connects to the configured syslog endpoint.
+func NewSyslogOutput(config SyslogConfig) (*SyslogOutput, error) {
+	if config.Facility == 0 {
+		config.Facility = SyslogFacilityUser
+	}
+	if config.Facility < 0 || config.Facility > SyslogFacilityLocal7 {
+		return nil, fmt.Errorf("invalid syslog facility: %d", config.Facility)
+	}
+	if config.AppName == "" {
+		config.AppName = filepath.Base(os.Args[0])
+	}
+
+	so := &SyslogOutput{
+		config:   config,
+		network:  config.Network,
+		address:  config.Address,
+		hostname: config.Hostname,
+		pid:      os.Getpid(),
+	}
+	if so.hostname == "" {
+		so.hostname, _ = os.Hostname()
+	}
+
+	if err := so.connect(); err != nil {
+		return nil, err
+	}
+	return so, nil
+}
+
+// connect dials the endpoint. It must be called with mu held or before the
+// output is shared.
+func (so *SyslogOutput) connect() error {
+	if so.network == "" {
+		return so.connectLocal()
+	}
+
+	var conn net.Conn
+	var err error
+	if so.config.TLSConfig != nil && so.network == "tcp" {
+		conn, err = tls.Dial(so.network, so.address, so.config.TLSConfig)
+	} else {
+		conn, err = net.Dial(so.network, so.address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	so.conn = conn
+	return nil
+}
+
+// connectLocal finds the local syslog socket and remembers it for
+// reconnects.
+func (so *SyslogOutput) connectLocal() error {
+	paths := syslogSocketPaths
+	if so.address != "" {
+		paths = []string{so.address}
+	}
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range paths {
+			if conn, err := net.Dial(network, path); err == nil {
+				so.conn, so.network, so.address = conn, network, path
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("failed to connect to syslog: no local syslog socket found")
+}
+
+// isStream reports whether the transport needs message framing.
+func (so *SyslogOutput) isStream() bool {
+	return so.network == "tcp" || so.network == "unix"
+}
+
+// isLocal reports whether messages go to the local daemon, which adds the
+// hostname itself.
+func (so *SyslogOutput) isLocal() bool {
+	return so.network == "unix" || so.network == "unixgram"
+}
+
+// Write sends data at INFO severity.
+func (so *SyslogOutput) Write(data []byte) error {
+	return so.send(InfoLevel, time.Now(), data)
+}
+
+// WriteEntry sends data at the severity of entry's level.
+func (so *SyslogOutput) WriteEntry(entry LogEntry, data []byte) error {
+	timestamp := entry.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	return so.send(entry.Level, timestamp, data)
+}
+
+func (so *SyslogOutput) send(level Level, timestamp time.Time, data []byte) error {
+	msg := so.format(level, timestamp, data)
+
+	so.mu.Lock()
+	defer so.mu.Unlock()
+
+	if so.conn == nil {
+		return fmt.Errorf("syslog output is closed")
+	}
+
+	err := so.writeFramed(msg)
+	if err != nil && so.isStream() {
+		_ = so.conn.Close()
+		if err = so.connect(); err == nil {
+			err = so.writeFramed(msg)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write to syslog: %w", err)
+	}
+	return nil
+}
+
+// writeFramed writes msg, using octet counting (RFC 6587) for RFC5424 and
+// newline termination for RFC3164 on stream transports.
+func (so *SyslogOutput) writeFramed(msg []byte) error {
+	if so.isStream() {
+		if so.config.Format == SyslogRFC5424 {
+			msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+		} else {
+			msg = append(msg, '\n')
+		}
+	}
+	_, err := so.conn.Write(msg)
+	return err
+}
+
+// format renders a syslog message with the header for the configured format.
+func (so *SyslogOutput) format(level Level, timestamp time.Time, data []byte) []byte {
+	pri := int(so.config.Facility)*8 + syslogSeverity(level)
+	msg := bytes.TrimRight(data, "\r\n")
+
+	var header string
+	switch {
+	case so.config.Format == SyslogRFC5424:
+		header = fmt.Sprintf("<%d>1 %s %s %s %d - - ",
+			pri, timestamp.Format("2006-01-02T15:04:05.000000Z07:00"),
+			syslogHeaderField(so.hostname, 255), syslogHeaderField(so.config.AppName, 48), so.pid)
+	case so.isLocal():
+		header = fmt.Sprintf("<%d>%s %s[%d]: ",
+			pri, timestamp.Format(time.Stamp), so.config.AppName, so.pid)
+	default:
+		header = fmt.Sprintf("<%d>%s %s %s[%d]: ",
+			pri, timestamp.Format(time.Stamp), syslogHeaderField(so.hostname, 255), so.config.AppName, so.pid)
+	}
+
+	return append([]byte(header), msg...)
+}
+
+// syslogHeaderField makes s a valid header field: printable ASCII without
+// spaces, at most maxLen bytes, or "-" when empty.
+func syslogHeaderField(s string, maxLen int) string {
+	if s == "" {
+		return "-"
+	}
+	b := []byte(s)
+	for i, c := range b {
+		if c <= ' ' || c > '~' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > maxLen {
+		b = b[:maxLen]
+	}
+	return string(b)
+}
+
+// Close closes the connection.
+func (so *SyslogOutput) Close() error {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+
+	if so.conn != nil {
+		err := so.conn.Close()
+		so.conn = nil
+		return err
+	}
+	return nil
+}
+
+// DefaultJournaldSocket is the socket of the systemd journal's native
+// protocol.
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldOutput writes entries to the systemd journal using its native
+// protocol. The entry message becomes MESSAGE, its level becomes PRIORITY,
+// and fields from WithFields become journal fields with uppercase names
+// (request_id becomes REQUEST_ID). Entries written with plain Write are sent
+// at INFO priority with the formatted data as MESSAGE.
+//
+// Example:
+//
+//	journal, err := logging.NewJournaldOutput("")
+//	if err != nil {
+//		return err
+//	}
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithCustomOutput(journal).
+//		Build())
+type JournaldOutput struct {
+	conn       *net.UnixConn
+	identifier string
+	mu         sync.Mutex
+}
+
+// NewJournaldOutput connects to the journal socket at path, or to
+// DefaultJournaldSocket when path is empty.
+func NewJournaldOutput(path string) (*JournaldOutput, error) {
+	if path == "" {
+		path = DefaultJournaldSocket
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to journald: %w", err)
+	}
+
+	return &JournaldOutput{
+		conn:       conn,
+		identifier: filepath.Base(os.Args[0]),
+	}, nil
+}
+
+// Write sends data as the MESSAGE of an INFO entry.
+func (jo *JournaldOutput) Write(data []byte) error {
+	return jo.send(LogEntry{Level: InfoLevel, Message: string(bytes.TrimRight(data, "\r\n"))})
+}
+
+// WriteEntry sends entry with its fields. The formatted data is ignored
+// because the journal stores fields natively.
+func (jo *JournaldOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	return jo.send(entry)
+}
+
+func (jo *JournaldOutput) send(entry LogEntry) error {
+	data := jo.encode(entry)
+
+	jo.mu.Lock()
+	defer jo.mu.Unlock()
+
+	if jo.conn == nil {
+		return fmt.Errorf("journald output is closed")
+	}
+
+	_, err := jo.conn.Write(data)
+	if isMessageTooLong(err) {
+		err = sendJournalFD(jo.conn, data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write to journald: %w", err)
+	}
+	return nil
+}
+
+// encode serializes entry in the journal's native format. Fields are sorted
+// so the encoding is deterministic.
+func (jo *JournaldOutput) encode(entry LogEntry) []byte {
+	buf := &bytes.Buffer{}
+	writeJournalField(buf, "MESSAGE", entry.Message)
+	writeJournalField(buf, "PRIORITY", strconv.Itoa(syslogSeverity(entry.Level)))
+	writeJournalField(buf, "SYSLOG_IDENTIFIER", jo.identifier)
+	if entry.File != "" {
+		writeJournalField(buf, "CODE_FILE", entry.File)
+		writeJournalField(buf, "CODE_LINE", strconv.Itoa(entry.Line))
+	}
+
+	fields := make(map[string]string, len(entry.Fields)+3)
+	for k, v := range entry.Fields {
+		if name := journalFieldName(k); name != "" {
+			fields[name] = fmt.Sprint(v)
+		}
+	}
+
+	contextFields := contextFieldsFrom(entry.Context).Omit(entry.Fields)
+	for name, value := range map[string]string{
+		"TRACE_ID":       contextFields.TraceID,
+		"REQUEST_ID":     contextFields.RequestID,
+		"CORRELATION_ID": contextFields.CorrelationID,
+	} {
+		if value != "" {
+			fields[name] = value
+		}
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeJournalField(buf, name, fields[name])
+	}
+
+	return buf.Bytes()
+}
+
+// writeJournalField appends one field. Values containing newlines use the
+// binary form: name, newline, little-endian length, value.
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// reservedJournalFields are set by the output itself and can't be
+// overridden by entry fields.
+var reservedJournalFields = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"CODE_FILE":         true,
+	"CODE_LINE":         true,
+}
+
+// journalFieldName converts a field key to a journal field name: uppercase
+// letters, digits and underscores, not starting with a digit or underscore,
+// at most 64 characters. It returns "" for keys that can't be converted or
+// would collide with fields the output sets.
+func journalFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+
+	s := strings.TrimLeft(string(name), "_0123456789")
+	if len(s) > 64 {
+		s = s[:64]
+	}
+	if reservedJournalFields[s] {
+		return ""
+	}
+	return s
+}
+
+// Close closes the journal socket.
+func (jo *JournaldOutput) Close() error {
+	jo.mu.Lock()
+	defer jo.mu.Unlock()
+
+	if jo.conn != nil {
+		err := jo.conn.Close()
+		jo.conn = nil
 		return err
 	}
 	return nil
@@ -2,8 +2,14 @@ package logging
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
@@ -193,6 +199,84 @@ func TestFileOutput_Close(t *testing.T) {
 	}
 }
 
+func TestFileOutput_SyncInterval(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	output, err := NewFileOutputWithConfig(FileOutputConfig{
+		Filename:     filename,
+		Sync:         SyncInterval,
+		SyncInterval: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer output.Close()
+
+	if err := output.Write([]byte("batched\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content, _ := os.ReadFile(filename); len(content) != 0 {
+		t.Errorf("expected the entry to be buffered, got %q", content)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if content, _ := os.ReadFile(filename); string(content) == "batched\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the entry to be written after the sync interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFileOutput_SyncIntervalFlushAndClose(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	output, err := NewFileOutputWithConfig(FileOutputConfig{
+		Filename:     filename,
+		Sync:         SyncInterval,
+		SyncInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_ = output.Write([]byte("first\n"))
+	if err := output.Flush(); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if content, _ := os.ReadFile(filename); string(content) != "first\n" {
+		t.Errorf("expected Flush to write the buffer, got %q", content)
+	}
+
+	_ = output.Write([]byte("second\n"))
+	if err := output.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if content, _ := os.ReadFile(filename); string(content) != "first\nsecond\n" {
+		t.Errorf("expected Close to write the buffer, got %q", content)
+	}
+	if err := output.Flush(); err == nil {
+		t.Error("expected an error flushing a closed output")
+	}
+}
+
+func TestFileOutput_SyncNever(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.log")
+	output, err := NewFileOutputWithConfig(FileOutputConfig{Filename: filename, Sync: SyncNever})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer output.Close()
+
+	if err := output.Write([]byte("unsynced\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content, _ := os.ReadFile(filename); string(content) != "unsynced\n" {
+		t.Errorf("expected the entry to be written immediately, got %q", content)
+	}
+}
+
 func TestNewBufferedOutput(t *testing.T) {
 	buf := &bytes.Buffer{}
 	underlying := NewWriterOutput(buf)
@@ -389,3 +473,738 @@ func TestAsyncOutput_Stop(t *testing.T) {
 		t.Error("expected message to be written before stop")
 	}
 }
+
+func TestSamplingOutput_Write(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sampled := NewSamplingOutput(NewWriterOutput(buf), 3)
+
+	for i := 0; i < 9; i++ {
+		if err := sampled.Write([]byte("x\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := strings.Count(buf.String(), "x"); got != 3 {
+		t.Errorf("expected 3 sampled writes, got %d", got)
+	}
+}
+
+func TestSamplingOutput_TraceConsistent(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sampled := NewSamplingOutput(NewWriterOutput(buf), 4)
+
+	kept := map[string]int{}
+	for i := 0; i < 40; i++ {
+		traceID := fmt.Sprintf("trace-%d", i)
+		for j := 0; j < 3; j++ {
+			entry := LogEntry{Level: InfoLevel, Message: traceID, Context: WithTraceID(context.Background(), traceID)}
+			if j == 2 {
+				entry = LogEntry{Level: InfoLevel, Message: traceID, Fields: map[string]interface{}{"trace_id": traceID}}
+			}
+			before := buf.Len()
+			_ = sampled.WriteEntry(entry, []byte(traceID+"\n"))
+			if buf.Len() > before {
+				kept[traceID]++
+			}
+		}
+	}
+
+	if len(kept) == 0 || len(kept) == 40 {
+		t.Fatalf("expected some traces kept and some dropped, kept %d of 40", len(kept))
+	}
+	for traceID, n := range kept {
+		if n != 3 {
+			t.Errorf("expected all 3 entries of %s, got %d", traceID, n)
+		}
+		if !traceSampled(traceID, 4) {
+			t.Errorf("kept %s, which is not sampled", traceID)
+		}
+	}
+}
+
+func TestRotatingFileOutput_Compress(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern:  filepath.Join(dir, "app.log"),
+		MaxSize:  10,
+		Compress: true,
+		OnError:  func(filename string, err error) { t.Errorf("failed to compress %s: %v", filename, err) },
+		Clock:    ClockFunc(func() time.Time { return now }),
+	})
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if err := output.Write([]byte(line)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"app.log.2024-05-01-00-00-01.gz": "first\n",
+		"app.log.2024-05-01-00-00-02.gz": "second\n",
+	} {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("expected compressed file %s: %v", name, err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("expected a gzip file: %v", err)
+		}
+		data, _ := io.ReadAll(zr)
+		f.Close()
+		if string(data) != want {
+			t.Errorf("%s = %q, want %q", name, data, want)
+		}
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "app.log*"))
+	if len(matches) != 3 || filepath.Base(matches[0]) != "app.log" {
+		t.Errorf("expected only the last file left uncompressed, got %v", matches)
+	}
+}
+
+func TestRotatingFileOutput_RotateWithinSecond(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern:  filepath.Join(dir, "app-%s.log"),
+		MaxSize:  10,
+		Compress: true,
+		Clock:    ClockFunc(func() time.Time { return now }),
+	})
+	defer output.Close()
+
+	// Both writes expand to the same file name, so the full file is renamed
+	// aside and compressed rather than reopened.
+	for _, line := range []string{"first\n", "second\n"} {
+		if err := output.Write([]byte(line)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	output.background.Wait()
+
+	data, err := os.ReadFile(filepath.Join(dir, "app-2024-05-01-00-00-00.log"))
+	if err != nil || string(data) != "second\n" {
+		t.Errorf("expected only the second line in the open file, got %q, %v", data, err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.gz"))
+	if len(matches) != 1 || filepath.Base(matches[0]) != "app-2024-05-01-00-00-00.log.2024-05-01-00-00-00.gz" {
+		t.Errorf("expected the first file compressed under its rotation time, got %v", matches)
+	}
+}
+
+func TestRotatingFileOutput_StaticPattern(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern:    filepath.Join(dir, "app.log"),
+		MaxSize:    10,
+		MaxBackups: 2,
+		Clock:      ClockFunc(func() time.Time { return now }),
+	})
+
+	for i, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if err := output.Write([]byte(line)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i != 1 {
+			now = now.Add(time.Second)
+		}
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The oldest file is beyond MaxBackups; two rotations in one second
+	// are told apart by a sequence number.
+	want := map[string]string{
+		"app.log":                       "fourth\n",
+		"app.log.2024-05-01-00-00-01.1": "second\n",
+		"app.log.2024-05-01-00-00-02":   "third\n",
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != len(want) {
+		t.Errorf("expected %d files, got %v", len(want), entries)
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, content)
+		}
+	}
+}
+
+func TestRotatingFileOutput_MaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	// Files left by an earlier run, and one the output must not touch.
+	for _, name := range []string{
+		"app-2024-04-30-00-00-00.log.gz",
+		"app-2024-04-30-00-00-01.log",
+		"app-notes.log",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("old\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern:    filepath.Join(dir, "app-%s.log"),
+		MaxSize:    10,
+		MaxBackups: 2,
+		Clock:      ClockFunc(func() time.Time { return now }),
+	})
+	output.background.Wait()
+
+	if matches, _ := filepath.Glob(filepath.Join(dir, "app-2024-04-30-*")); len(matches) != 2 {
+		t.Errorf("expected the start pass to keep 2 backups, got %v", matches)
+	}
+
+	for i := 0; i < 5; i++ {
+		if err := output.Write([]byte("entry\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-2*"))
+	want := []string{
+		"app-2024-05-01-00-00-02.log.2024-05-01-00-00-03",
+		"app-2024-05-01-00-00-03.log.2024-05-01-00-00-04",
+		"app-2024-05-01-00-00-04.log",
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("expected %v, got %v", want, matches)
+	}
+	for i, m := range matches {
+		if filepath.Base(m) != want[i] {
+			t.Errorf("expected %v, got %v", want, matches)
+			break
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app-notes.log")); err != nil {
+		t.Errorf("expected unrelated files to be kept: %v", err)
+	}
+}
+
+func TestRotatingFileOutput_MaxBackupAge(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	for name, age := range map[string]time.Duration{
+		"app-2024-05-01-00-00-00.log.gz": 9 * 24 * time.Hour,
+		"app-2024-05-08-00-00-00.log.gz": 2 * 24 * time.Hour,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern:      filepath.Join(dir, "app-%s.log"),
+		MaxBackupAge: 7 * 24 * time.Hour,
+		Clock:        ClockFunc(func() time.Time { return now }),
+	})
+	if err := output.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "app-2024-05-01-00-00-00.log.gz")); !os.IsNotExist(err) {
+		t.Error("expected the expired backup to be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app-2024-05-08-00-00-00.log.gz")); err != nil {
+		t.Errorf("expected the recent backup to be kept: %v", err)
+	}
+}
+
+func TestRotatingFileOutput_ConcurrentRotationAndCleanup(t *testing.T) {
+	dir := t.TempDir()
+	var mu sync.Mutex
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern:    filepath.Join(dir, "app-%s.log"),
+		MaxSize:    64,
+		MaxBackups: 3,
+		Compress:   true,
+		OnError:    func(filename string, err error) { t.Errorf("%s: %v", filename, err) },
+		Clock: ClockFunc(func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			now = now.Add(time.Second)
+			return now
+		}),
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if err := output.Write([]byte("concurrent entry\n")); err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := output.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "*.gz"))
+	current, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(backups) != 3 || len(current) != 1 {
+		t.Errorf("expected 3 backups and the current file, got %v and %v", backups, current)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmp) != 0 {
+		t.Errorf("expected no temporary files, got %v", tmp)
+	}
+}
+
+func TestRotatingFileOutput_Interval(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 1, 22, 30, 0, 0, time.UTC)
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern:    filepath.Join(dir, "app-%Y-%m-%d.log"),
+		Interval:   RotateDaily,
+		MaxBackups: 1,
+		Clock:      ClockFunc(func() time.Time { return now }),
+	})
+
+	for _, step := range []time.Duration{0, time.Hour, 30 * time.Minute, 24 * time.Hour} {
+		now = now.Add(step)
+		if err := output.Write([]byte(now.Format(time.RFC3339) + "\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first day's file is beyond MaxBackups.
+	for name, want := range map[string]string{
+		"app-2024-05-02.log.2024-05-03-00-00-00": "2024-05-02T00:00:00Z\n",
+		"app-2024-05-03.log":                     "2024-05-03T00:00:00Z\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "app-2024-05-01.log*")); len(matches) != 0 {
+		t.Error("expected the oldest file to be deleted")
+	}
+}
+
+func TestRotationInterval_Next(t *testing.T) {
+	loc := time.FixedZone("IST", 5*3600+1800)
+	at := time.Date(2024, 12, 31, 23, 15, 0, 0, loc)
+
+	if got, want := RotateHourly.next(at), time.Date(2025, 1, 1, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("hourly: got %v, want %v", got, want)
+	}
+	if got, want := RotateDaily.next(at), time.Date(2025, 1, 1, 0, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("daily: got %v, want %v", got, want)
+	}
+	if got := RotateHourly.next(at.Add(-30 * time.Minute)); got.Minute() != 0 || got.Hour() != 23 {
+		t.Errorf("expected the boundary in the clock's zone, got %v", got)
+	}
+	if !RotateNone.next(at).IsZero() {
+		t.Error("expected no boundary")
+	}
+}
+
+func TestRotatingFileOutput_MaxAgeFromOpenTime(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern: filepath.Join(dir, "app-%s.log"),
+		MaxAge:  time.Minute,
+		Clock:   ClockFunc(func() time.Time { return now }),
+	})
+	defer output.Close()
+
+	// Frequent writes keep the modification time fresh; the age counts
+	// from when the file was opened.
+	for i := 0; i < 4; i++ {
+		if err := output.Write([]byte("entry\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		now = now.Add(30 * time.Second)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-*.log*"))
+	if len(matches) != 2 {
+		t.Errorf("expected a second file after MaxAge, got %v", matches)
+	}
+}
+
+func TestRotatingFileOutput_Symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks needs extra privileges on Windows")
+	}
+
+	dir := t.TempDir()
+	link := filepath.Join(dir, "app.log")
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern:    filepath.Join(dir, "app-%s.log"),
+		MaxSize:    10,
+		MaxBackups: 5,
+		Symlink:    link,
+		Clock:      ClockFunc(func() time.Time { return now }),
+	})
+	defer output.Close()
+
+	for i, entry := range []string{"first\n", "second\n"} {
+		if err := output.Write([]byte(entry)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		target, err := os.Readlink(link)
+		if want := "app-" + now.Format(rotatingTimeFormat) + ".log"; err != nil || target != want {
+			t.Errorf("write %d: link points at %q, %v; want %q", i, target, err, want)
+		}
+		if data, _ := os.ReadFile(link); string(data) != entry {
+			t.Errorf("write %d: expected the active file through the link, got %q", i, data)
+		}
+		now = now.Add(time.Second)
+	}
+
+	// The link is not a backup.
+	backups, err := output.backups("")
+	if err != nil || len(backups) != 2 {
+		t.Errorf("expected 2 backups, got %v, %v", backups, err)
+	}
+}
+
+func TestRotatingFileOutput_SymlinkOverRegularFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks needs extra privileges on Windows")
+	}
+
+	dir := t.TempDir()
+	link := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(link, []byte("old logs\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var reported []string
+	output := NewRotatingFileOutputWithConfig(RotatingFileConfig{
+		Pattern: filepath.Join(dir, "app-%s.log"),
+		Symlink: link,
+		OnError: func(filename string, err error) { reported = append(reported, filename) },
+	})
+	defer output.Close()
+
+	if err := output.Write([]byte("entry\n")); err != nil {
+		t.Fatalf("expected writes to continue, got %v", err)
+	}
+	if data, _ := os.ReadFile(link); string(data) != "old logs\n" {
+		t.Errorf("expected the regular file to be kept, got %q", data)
+	}
+	if len(reported) != 1 || reported[0] != link {
+		t.Errorf("expected the link to be reported, got %v", reported)
+	}
+}
+
+func TestSyslogOutput_UDPRFC3164(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	output, err := NewSyslogOutput(SyslogConfig{
+		Network:  "udp",
+		Address:  conn.LocalAddr().String(),
+		Facility: SyslogFacilityLocal0,
+		AppName:  "myapp",
+		Hostname: "host1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create syslog output: %v", err)
+	}
+	defer output.Close()
+
+	timestamp := time.Date(2024, 3, 5, 14, 2, 3, 0, time.UTC)
+	if err := output.WriteEntry(LogEntry{Timestamp: timestamp, Level: ErrorLevel}, []byte("boom\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+
+	// local0 (16) * 8 + err (3) = 131
+	want := fmt.Sprintf("<131>Mar  5 14:02:03 host1 myapp[%d]: boom", os.Getpid())
+	if got := string(buf[:n]); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSyslogOutput_TCPRFC5424(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- string(data)
+	}()
+
+	output, err := NewSyslogOutput(SyslogConfig{
+		Network:  "tcp",
+		Address:  listener.Addr().String(),
+		Format:   SyslogRFC5424,
+		AppName:  "my app",
+		Hostname: "host1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create syslog output: %v", err)
+	}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(NewMultiOutput(output)).Build())
+	logger.WithLevel(TraceLevel).Debug("hello")
+	_ = output.Close()
+
+	select {
+	case got := <-received:
+		// Octet-counted frame: "<len> <15>1 ..." where user (1) * 8 + debug (7) = 15
+		frame := strings.SplitN(got, " ", 2)
+		if len(frame) != 2 || frame[0] != fmt.Sprint(len(frame[1])) {
+			t.Fatalf("expected octet-counted frame, got %q", got)
+		}
+		if !strings.HasPrefix(frame[1], "<15>1 ") || !strings.Contains(frame[1], " host1 my_app ") || !strings.Contains(frame[1], "hello") {
+			t.Errorf("unexpected message: %q", frame[1])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for syslog message")
+	}
+}
+
+func TestSyslogOutput_InvalidFacility(t *testing.T) {
+	if _, err := NewSyslogOutput(SyslogConfig{Network: "udp", Address: "127.0.0.1:514", Facility: 99}); err == nil {
+		t.Error("expected error for invalid facility")
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	tests := map[Level]int{
+		TraceLevel:    7,
+		DebugLevel:    7,
+		InfoLevel:     6,
+		WarnLevel:     4,
+		ErrorLevel:    3,
+		CriticalLevel: 2,
+	}
+	for level, want := range tests {
+		if got := syslogSeverity(level); got != want {
+			t.Errorf("syslogSeverity(%s) = %d, want %d", level, got, want)
+		}
+	}
+}
+
+// listenJournal starts a fake journald socket and returns its path and the
+// received datagrams.
+func listenJournal(t *testing.T) (string, <-chan []byte) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	received := make(chan []byte, 10)
+	go func() {
+		buf := make([]byte, 65536)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			received <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+	return path, received
+}
+
+func receiveJournal(t *testing.T, received <-chan []byte) string {
+	t.Helper()
+	select {
+	case data := <-received:
+		return string(data)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for journal entry")
+		return ""
+	}
+}
+
+func TestJournaldOutput_WriteEntry(t *testing.T) {
+	path, received := listenJournal(t)
+
+	output, err := NewJournaldOutput(path)
+	if err != nil {
+		t.Fatalf("failed to create journald output: %v", err)
+	}
+	defer output.Close()
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(output).Build())
+	ctx := WithTraceID(context.Background(), "trace-1")
+	logger.WithFields(map[string]interface{}{
+		"user-id": 42,
+		"message": "ignored",
+		"note":    "line1\nline2",
+	}).WarnContext(ctx, "disk %s", "full")
+
+	got := receiveJournal(t, received)
+	for _, want := range []string{
+		"MESSAGE=disk full\n",
+		"PRIORITY=4\n",
+		"USER_ID=42\n",
+		"TRACE_ID=trace-1\n",
+		"NOTE\n\x0b\x00\x00\x00\x00\x00\x00\x00line1\nline2\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in journal entry %q", want, got)
+		}
+	}
+	if strings.Count(got, "MESSAGE") != 1 {
+		t.Errorf("expected entry fields not to override MESSAGE, got %q", got)
+	}
+}
+
+func TestJournaldOutput_Write(t *testing.T) {
+	path, received := listenJournal(t)
+
+	output, err := NewJournaldOutput(path)
+	if err != nil {
+		t.Fatalf("failed to create journald output: %v", err)
+	}
+	defer output.Close()
+
+	if err := output.Write([]byte("plain\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if got := receiveJournal(t, received); !strings.HasPrefix(got, "MESSAGE=plain\nPRIORITY=6\n") {
+		t.Errorf("unexpected journal entry %q", got)
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	tests := map[string]string{
+		"request_id": "REQUEST_ID",
+		"http.path":  "HTTP_PATH",
+		"_private":   "PRIVATE",
+		"2fa":        "FA",
+		"priority":   "",
+		"___":        "",
+	}
+	for key, want := range tests {
+		if got := journalFieldName(key); got != want {
+			t.Errorf("journalFieldName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+// gatedWriter blocks its first write until gate is closed, signalling
+// entered once that write has started, and records every write.
+type gatedWriter struct {
+	gate    chan struct{}
+	entered chan struct{}
+	mu      sync.Mutex
+	writes  []string
+}
+
+func (gw *gatedWriter) Write(p []byte) (int, error) {
+	gw.mu.Lock()
+	first := len(gw.writes) == 0
+	gw.writes = append(gw.writes, string(p))
+	gw.mu.Unlock()
+	if first {
+		close(gw.entered)
+		<-gw.gate
+	}
+	return len(p), nil
+}
+
+func TestAsyncOutput_CoalescesWrites(t *testing.T) {
+	writer := &gatedWriter{gate: make(chan struct{}), entered: make(chan struct{})}
+	sink := NewWriterOutput(writer)
+	output := NewAsyncOutput(sink, 100)
+
+	_ = output.Write([]byte("0\n"))
+	<-writer.entered
+	for i := 1; i < 10; i++ {
+		_ = output.WriteEntry(LogEntry{Level: InfoLevel}, []byte(fmt.Sprintf("%d\n", i)))
+	}
+	close(writer.gate)
+	if err := output.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if len(writer.writes) != 2 || writer.writes[1] != "1\n2\n3\n4\n5\n6\n7\n8\n9\n" {
+		t.Errorf("expected the queued entries in one write, got %q", writer.writes)
+	}
+	if got := sink.Stats(); got.Entries != 10 || got.Bytes != 20 {
+		t.Errorf("expected every entry counted by the sink, got %+v", got)
+	}
+	if got := output.Stats(); got.Entries != 10 {
+		t.Errorf("expected every entry counted by the async output, got %+v", got)
+	}
+}
+
+func TestAsyncOutput_DoesNotCoalesceMessageOutputs(t *testing.T) {
+	sink := &closeRecorder{}
+	output := NewAsyncOutput(sink, 100)
+	for i := 0; i < 5; i++ {
+		_ = output.Write([]byte("entry\n"))
+	}
+	_ = output.Stop()
+	if got := sink.String(); got != strings.Repeat("entry\n", 5) {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestBufferedOutput_FlushesInOneWrite(t *testing.T) {
+	writer := &gatedWriter{gate: make(chan struct{}), entered: make(chan struct{})}
+	close(writer.gate)
+	sink := NewWriterOutput(writer)
+	output := NewBufferedOutput(sink, 1024, 0)
+	for i := 0; i < 5; i++ {
+		_ = output.Write([]byte("entry\n"))
+	}
+	if err := output.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if len(writer.writes) != 1 {
+		t.Errorf("expected one write, got %d", len(writer.writes))
+	}
+	if got := sink.Stats(); got.Entries != 5 {
+		t.Errorf("expected the sink to count 5 entries, got %+v", got)
+	}
+}
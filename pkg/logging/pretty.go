@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ocrosby/go-logging/pkg/logging/internal"
+)
+
+// prettyIndent indents the lines under an entry's message in PrettyFormatter
+// output.
+const prettyIndent = "    "
+
+// PrettyFormatter writes multi-line, human-readable entries for local
+// development, in the style of pino-pretty. The level, time and message
+// share one line, and each field follows on its own indented line in the
+// configured FieldOrder. Errors, stack traces and structured values that span several lines
+// are indented beneath their key.
+//
+// Example:
+//
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithPrettyFormat().
+//		Build())
+//	logger.WithFields(map[string]interface{}{"order_id": 42, "error": err}).
+//		Error("payment failed")
+//	// [10:30:00.000] ERROR: payment failed
+//	//     error: card declined
+//	//     order_id: 42
+type PrettyFormatter struct {
+	config      *FormatterConfig
+	useColors   bool
+	levelColors map[Level]string
+}
+
+// NewPrettyFormatter creates a formatter for development console output,
+// colored with ANSI escapes when useColors is set.
+func NewPrettyFormatter(config *FormatterConfig, useColors bool) *PrettyFormatter {
+	if config == nil {
+		config = NewFormatterConfig().Build()
+	}
+	return &PrettyFormatter{
+		config:      config,
+		useColors:   useColors,
+		levelColors: config.levelColors(),
+	}
+}
+
+// Format formats a log entry as a message line followed by indented fields.
+func (f *PrettyFormatter) Format(entry LogEntry) ([]byte, error) {
+	var b strings.Builder
+
+	if f.config.IncludeTime {
+		b.WriteString(f.colorize("\033[90m", "["+f.config.formatTime(entry.Timestamp, "15:04:05.000", nil)+"]"))
+		b.WriteByte(' ')
+	}
+	b.WriteString(f.colorize(f.levelColors[entry.Level], f.config.levelName(entry.Level)+":"))
+	b.WriteByte(' ')
+
+	message := internal.ApplyRedactionPatterns(entry.Message, f.config.RedactPatterns)
+	b.WriteString(strings.ReplaceAll(message, "\n", "\n"+prettyIndent))
+
+	if f.config.IncludeFile && entry.File != "" {
+		b.WriteByte(' ')
+		b.WriteString(f.colorize("\033[90m", "("+internal.FormatFilename(entry.File, entry.Line, f.config.UseShortFile)+")"))
+	}
+	b.WriteByte('\n')
+
+	fields := make(map[string]interface{}, len(entry.Fields)+3)
+	contextFieldsFrom(entry.Context).AddToMap(fields)
+	for k, v := range entry.Fields {
+		fields[k] = v
+	}
+	for _, k := range f.config.orderFieldKeys(fields, entry.fieldOrder) {
+		value, isError := prettyValue(fields[k])
+		color := "\033[90m"
+		if isError {
+			color = "\033[31m"
+		}
+
+		b.WriteString(prettyIndent)
+		b.WriteString(f.colorize(color, k+":"))
+		if !strings.Contains(value, "\n") {
+			b.WriteByte(' ')
+			b.WriteString(value)
+			b.WriteByte('\n')
+			continue
+		}
+		// Stack traces and structured values go beneath their key.
+		b.WriteByte('\n')
+		for _, line := range strings.Split(strings.TrimRight(value, "\n"), "\n") {
+			b.WriteString(prettyIndent + prettyIndent)
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+func (f *PrettyFormatter) colorize(color, text string) string {
+	if !f.useColors || color == "" {
+		return text
+	}
+	return color + text + "\033[0m"
+}
+
+// prettyValue returns the text of a field value and whether it is an
+// error. Errors use %+v so that errors carrying a stack trace print it;
+// maps, slices and structs are shown as indented JSON.
+func prettyValue(v interface{}) (string, bool) {
+	switch value := v.(type) {
+	case nil:
+		return "null", false
+	case error:
+		return fmt.Sprintf("%+v", value), true
+	case string:
+		return value, false
+	case fmt.Stringer:
+		return value.String(), false
+	}
+
+	kind := reflect.Indirect(reflect.ValueOf(v)).Kind()
+	if kind == reflect.Map || kind == reflect.Slice || kind == reflect.Array || kind == reflect.Struct {
+		if data, err := json.MarshalIndent(v, "", "  "); err == nil {
+			return string(data), false
+		}
+	}
+	return fmt.Sprint(v), false
+}
+
+// WithPrettyFormat formats entries as multi-line development output.
+func (b *FormatterConfigBuilder) WithPrettyFormat() *FormatterConfigBuilder {
+	b.config.Format = PrettyFormat
+	return b
+}
+
+// WithPrettyFormat formats entries as multi-line development output with
+// PrettyFormatter, colored when the logger writes to a terminal.
+func (b *LoggerConfigBuilder) WithPrettyFormat() *LoggerConfigBuilder {
+	b.config.Formatter.Format = PrettyFormat
+	return b
+}
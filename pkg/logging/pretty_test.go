@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrettyFormatter_Format(t *testing.T) {
+	formatter := NewPrettyFormatter(NewFormatterConfig().UseShortFile(true).Build(), false)
+	data, err := formatter.Format(LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 250e6, time.UTC),
+		Level:     ErrorLevel,
+		Message:   "payment failed",
+		Context:   WithRequestID(context.Background(), "req-1"),
+		File:      "/src/app/orders.go",
+		Line:      17,
+		Fields: map[string]interface{}{
+			"order_id": 42,
+			"error":    errors.New("card declined"),
+			"stack":    "main.pay()\n\t/src/app/orders.go:17",
+			"items":    []string{"a"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	want := "[10:30:00.250] ERROR: payment failed (orders.go:17)\n" +
+		"    error: card declined\n" +
+		"    items:\n" +
+		"        [\n" +
+		"          \"a\"\n" +
+		"        ]\n" +
+		"    order_id: 42\n" +
+		"    request_id: req-1\n" +
+		"    stack:\n" +
+		"        main.pay()\n" +
+		"        \t/src/app/orders.go:17\n"
+	if string(data) != want {
+		t.Errorf("unexpected output\n got: %q\nwant: %q", data, want)
+	}
+}
+
+func TestPrettyFormatter_Colors(t *testing.T) {
+	formatter := NewPrettyFormatter(NewFormatterConfig().IncludeTime(false).IncludeFile(false).Build(), true)
+	data, _ := formatter.Format(LogEntry{Level: WarnLevel, Message: "slow", Fields: map[string]interface{}{"error": errors.New("timeout")}})
+	want := "\033[33mWARN:\033[0m slow\n    \033[31merror:\033[0m timeout\n"
+	if string(data) != want {
+		t.Errorf("unexpected output\n got: %q\nwant: %q", data, want)
+	}
+}
+
+func TestPrettyFormat_DevelopmentPreset(t *testing.T) {
+	builder := NewLoggerConfig()
+	yamlConfig := &YAMLConfig{}
+	if err := applyPreset(yamlConfig, "development"); err != nil {
+		t.Fatalf("applyPreset returned error: %v", err)
+	}
+	if err := configureFormatterFromYAML(builder, yamlConfig); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	if format := builder.Build().Formatter.Format; format != PrettyFormat {
+		t.Errorf("expected the development preset to use PrettyFormat, got %v", format)
+	}
+
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithWriter(buf).WithPrettyFormat().Build())
+	logger.WithField("port", 8080).Info("started")
+	output := buf.String()
+	if !strings.Contains(output, " INFO: started") || !strings.HasSuffix(output, "\n    port: 8080\n") || strings.Contains(output, "\033[") {
+		t.Errorf("unexpected uncolored output %q", output)
+	}
+}
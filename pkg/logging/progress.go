@@ -0,0 +1,212 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressConfig configures a Progress.
+type ProgressConfig struct {
+	// Interval is the minimum time between progress reports. Defaults to 5s
+	// for log entries and 200ms for a console line.
+	Interval time.Duration
+	// Console, when set, receives a single line that is redrawn in place
+	// instead of progress entries. NewProgress sets it to the logger's
+	// writer when that is a terminal and the format is not JSON.
+	Console io.Writer
+}
+
+// Progress reports the progress of a long-running operation, such as an
+// import or migration, with throttled entries carrying operation, done,
+// total, percent, rate (items per second), eta_ms and elapsed_ms fields. In
+// console mode it redraws one status line instead. A total of zero or less
+// means unknown, and percent and eta are omitted. Progress is safe for
+// concurrent use.
+//
+// Example:
+//
+//	progress := logging.NewProgress(logger, "import", int64(len(rows)))
+//	for _, row := range rows {
+//		importRow(row)
+//		progress.Advance(1)
+//	}
+//	progress.Done()
+type Progress struct {
+	logger    Logger
+	operation string
+	total     int64
+	config    ProgressConfig
+	now       func() time.Time
+
+	mu       sync.Mutex
+	level    Level
+	done     int64
+	start    time.Time
+	reported time.Time
+	width    int
+	finished bool
+}
+
+// NewProgress starts tracking operation with total items.
+func NewProgress(logger Logger, operation string, total int64) *Progress {
+	config := ProgressConfig{}
+	if ul, ok := logger.(*unifiedLogger); ok {
+		config.Console = consoleWriter(ul.config)
+	}
+	return NewProgressWithConfig(logger, operation, total, config)
+}
+
+// NewProgressWithConfig is NewProgress with explicit settings.
+func NewProgressWithConfig(logger Logger, operation string, total int64, config ProgressConfig) *Progress {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Second
+		if config.Console != nil {
+			config.Interval = 200 * time.Millisecond
+		}
+	}
+	p := &Progress{
+		logger:    logger,
+		operation: operation,
+		total:     total,
+		config:    config,
+		level:     InfoLevel,
+		now:       time.Now,
+	}
+	p.start = p.now()
+	p.reported = p.start
+	return p
+}
+
+// consoleWriter returns the writer a logger with config prints to if it is a
+// terminal showing human-readable output, or nil.
+func consoleWriter(config *LoggerConfig) io.Writer {
+	if config == nil || config.Output == nil || config.Output.Custom != nil || config.Formatter == nil {
+		return nil
+	}
+	if config.Formatter.Format == JSONFormat || config.Formatter.Custom != nil {
+		return nil
+	}
+	if !isTerminal(config.Output.Writer) {
+		return nil
+	}
+	return config.Output.Writer
+}
+
+// SetLevel changes the level of progress entries. The default is INFO.
+func (p *Progress) SetLevel(level Level) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.level = level
+}
+
+// Advance records n more completed items and reports progress if Interval
+// has passed since the last report or the total has been reached.
+func (p *Progress) Advance(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	p.maybeReport()
+}
+
+// Set records done as the number of completed items.
+func (p *Progress) Set(done int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = done
+	p.maybeReport()
+}
+
+// maybeReport reports unless throttled. It must be called with mu held.
+func (p *Progress) maybeReport() {
+	if p.finished {
+		return
+	}
+	now := p.now()
+	if now.Sub(p.reported) < p.config.Interval && (p.total <= 0 || p.done < p.total) {
+		return
+	}
+	p.reported = now
+	p.report(now, false)
+}
+
+// Done reports the final progress and ends the console line. Later calls
+// to Advance, Set and Done do nothing.
+func (p *Progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return
+	}
+	p.finished = true
+	p.report(p.now(), true)
+}
+
+// report logs an entry or redraws the console line. It must be called with
+// mu held.
+func (p *Progress) report(now time.Time, final bool) {
+	elapsed := now.Sub(p.start)
+	fields := map[string]interface{}{
+		"operation":  p.operation,
+		"done":       p.done,
+		"elapsed_ms": elapsed.Milliseconds(),
+	}
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(p.done) / elapsed.Seconds()
+	}
+	fields["rate"] = roundTo(rate, 2)
+
+	var eta time.Duration
+	status := fmt.Sprintf("%d", p.done)
+	if p.total > 0 {
+		percent := float64(p.done) / float64(p.total) * 100
+		fields["total"] = p.total
+		fields["percent"] = roundTo(percent, 1)
+		if remaining := p.total - p.done; remaining > 0 && rate > 0 {
+			eta = time.Duration(float64(remaining) / rate * float64(time.Second)).Round(time.Second)
+		}
+		fields["eta_ms"] = eta.Milliseconds()
+		status = fmt.Sprintf("%.1f%% (%d/%d)", percent, p.done, p.total)
+	}
+
+	if p.config.Console != nil {
+		p.drawLine(status, rate, eta, elapsed, final)
+		return
+	}
+	message := "%s progress: %s"
+	if final {
+		message = "%s finished: %s"
+	}
+	p.logger.WithFields(fields).Log(p.level, message, p.operation, status)
+}
+
+// drawLine redraws the console status line, padding it to erase the
+// previous one, and ends it with a newline when final.
+func (p *Progress) drawLine(status string, rate float64, eta, elapsed time.Duration, final bool) {
+	line := fmt.Sprintf("%s %s %.1f/s", p.operation, status, rate)
+	if final {
+		line += " in " + elapsed.Round(time.Millisecond).String()
+	} else if eta > 0 {
+		line += " ETA " + eta.String()
+	}
+	padding := ""
+	if p.width > len(line) {
+		padding = strings.Repeat(" ", p.width-len(line))
+	}
+	p.width = len(line)
+	end := ""
+	if final {
+		end = "\n"
+	}
+	_, _ = fmt.Fprintf(p.config.Console, "\r%s%s%s", line, padding, end)
+}
+
+// roundTo rounds v to the given number of decimal places.
+func roundTo(v float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(v*scale) / scale
+}
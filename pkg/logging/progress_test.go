@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeProgressClock returns a now function that advances by step on each call.
+func fakeProgressClock(step time.Duration) func() time.Time {
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		current = current.Add(step)
+		return current
+	}
+}
+
+func progressEntries(t *testing.T, buf *bytes.Buffer) []LogEntry {
+	t.Helper()
+	var entries []LogEntry
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		entry, ok := ParseJSONEntry(line)
+		if !ok {
+			t.Fatalf("expected JSON entry, got: %s", line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestProgress_ThrottledEntries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+
+	p := NewProgressWithConfig(logger, "import", 100, ProgressConfig{Interval: 10 * time.Second})
+	p.now = fakeProgressClock(time.Second)
+	p.start = p.now()
+	p.reported = p.start
+
+	for i := 0; i < 25; i++ {
+		p.Advance(1)
+	}
+
+	entries := progressEntries(t, buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 throttled entries, got %d: %s", len(entries), buf.String())
+	}
+	entry := entries[0]
+	if entry.Message != "import progress: 10.0% (10/100)" {
+		t.Errorf("unexpected message %q", entry.Message)
+	}
+	if entry.Fields["operation"] != "import" || entry.Fields["done"] != float64(10) || entry.Fields["total"] != float64(100) {
+		t.Errorf("unexpected fields: %v", entry.Fields)
+	}
+	if entry.Fields["percent"] != 10.0 || entry.Fields["rate"] != 1.0 {
+		t.Errorf("expected percent 10 and rate 1/s, got %v", entry.Fields)
+	}
+	if entry.Fields["eta_ms"] != float64(90000) || entry.Fields["elapsed_ms"] != float64(10000) {
+		t.Errorf("expected 90s ETA after 10s, got %v", entry.Fields)
+	}
+}
+
+func TestProgress_DoneAndTotalReached(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+
+	p := NewProgressWithConfig(logger, "migrate", 3, ProgressConfig{Interval: time.Hour})
+	p.Advance(3)
+	p.Done()
+	p.Done()
+	p.Advance(1)
+
+	entries := progressEntries(t, buf)
+	if len(entries) != 2 {
+		t.Fatalf("expected an entry on completion and one from Done, got %d", len(entries))
+	}
+	if entries[1].Message != "migrate finished: 100.0% (3/3)" {
+		t.Errorf("unexpected final message %q", entries[1].Message)
+	}
+}
+
+func TestProgress_UnknownTotal(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+
+	p := NewProgressWithConfig(logger, "scan", 0, ProgressConfig{Interval: time.Hour})
+	p.Set(42)
+	p.Done()
+
+	entries := progressEntries(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("expected only the final entry, got %d", len(entries))
+	}
+	for _, name := range []string{"total", "percent", "eta_ms"} {
+		if _, ok := entries[0].Fields[name]; ok {
+			t.Errorf("expected no %s field for an unknown total", name)
+		}
+	}
+	if entries[0].Message != "scan finished: 42" {
+		t.Errorf("unexpected message %q", entries[0].Message)
+	}
+}
+
+func TestProgress_ConsoleLine(t *testing.T) {
+	console := &bytes.Buffer{}
+	logBuf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithTextFormat().WithWriter(logBuf).Build())
+
+	p := NewProgressWithConfig(logger, "upload", 200, ProgressConfig{Console: console, Interval: time.Second})
+	p.now = fakeProgressClock(time.Second)
+	p.start = p.now()
+	p.reported = p.start
+
+	p.Advance(100)
+	p.Advance(10)
+	p.Done()
+
+	if logBuf.Len() != 0 {
+		t.Errorf("expected no log entries in console mode, got: %s", logBuf.String())
+	}
+	out := console.String()
+	lines := strings.Split(strings.TrimPrefix(out, "\r"), "\r")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 redraws, got %q", out)
+	}
+	if lines[0] != "upload 50.0% (100/200) 100.0/s ETA 1s" {
+		t.Errorf("unexpected first line %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[2], "upload 55.0% (110/200)") || !strings.HasSuffix(out, "\n") {
+		t.Errorf("expected final line ending in a newline, got %q", lines[2])
+	}
+}
+
+func TestNewProgress_EntriesForNonTerminal(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithTextFormat().WithWriter(buf).Build())
+
+	if p := NewProgress(logger, "import", 10); p.config.Console != nil {
+		t.Error("expected entries when the logger does not write to a terminal")
+	}
+}
@@ -0,0 +1,216 @@
+package logging
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter selects the entries returned by Query.
+type Filter struct {
+	// Since and Until bound the entry timestamp; zero values are unbounded.
+	Since time.Time
+	Until time.Time
+
+	// Level is the minimum level to return.
+	Level Level
+
+	// FieldEquals requires each key to be present with an equal value.
+	// Values are compared by their string representation so that numbers
+	// decoded from JSON match the integers they were logged as.
+	FieldEquals map[string]interface{}
+}
+
+// Matches reports whether entry satisfies the filter.
+func (f Filter) Matches(entry LogEntry) bool {
+	if entry.Level < f.Level {
+		return false
+	}
+	if !entry.Timestamp.IsZero() && !inTimeRange(entry.Timestamp, f.Since, f.Until) {
+		return false
+	}
+	for k, want := range f.FieldEquals {
+		got, ok := entry.Fields[k]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// errStopQuery aborts a file scan when the consumer stops iterating.
+var errStopQuery = errors.New("query stopped")
+
+// Query returns an iterator over the JSON entries stored in the files of dir
+// that match filter. Files are read in name order and use their JSONL side
+// index, when present, to skip blocks outside the requested time range.
+// Gzip-compressed rotated files (.gz) are decompressed and scanned;
+// symlinks, such as the one RotatingFileConfig.Symlink maintains, are
+// skipped so entries are not returned twice. Lines that are not valid JSON
+// entries are skipped.
+//
+// Example:
+//
+//	since := time.Now().Add(-time.Hour)
+//	for entry, err := range logging.Query("/var/log/app", logging.Filter{
+//		Since:       since,
+//		Level:       logging.ErrorLevel,
+//		FieldEquals: map[string]interface{}{"trace_id": traceID},
+//	}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(entry.Message)
+//	}
+func Query(dir string, filter Filter) iter.Seq2[LogEntry, error] {
+	return QueryWithConfig(dir, filter, JSONLConfig{})
+}
+
+// QueryWithConfig is Query for entries written with the field keys set in
+// config.
+func QueryWithConfig(dir string, filter Filter, config JSONLConfig) iter.Seq2[LogEntry, error] {
+	return func(yield func(LogEntry, error) bool) {
+		files, err := queryFiles(dir)
+		if err != nil {
+			yield(LogEntry{}, err)
+			return
+		}
+
+		for _, filename := range files {
+			if !queryFile(filename, filter, config, yield) {
+				return
+			}
+		}
+	}
+}
+
+// queryFile yields matching entries from one file. It returns false when the
+// consumer has stopped iterating.
+func queryFile(filename string, filter Filter, config JSONLConfig, yield func(LogEntry, error) bool) bool {
+	match := func(line []byte) error {
+		entry, ok := ParseJSONEntryWithKeys(line, config.Keys)
+		if !ok || !filter.Matches(entry) {
+			return nil
+		}
+		if !yield(entry, nil) {
+			return errStopQuery
+		}
+		return nil
+	}
+
+	var err error
+	if strings.HasSuffix(filename, ".gz") {
+		err = scanGzipFile(filename, filter, config.Keys.jsonKeys().Timestamp, match)
+	} else {
+		var reader *JSONLReader
+		if reader, err = OpenJSONLReaderWithConfig(filename, config); err != nil {
+			return yield(LogEntry{}, err)
+		}
+		err = reader.ReadRange(filter.Since, filter.Until, match)
+	}
+
+	if errors.Is(err, errStopQuery) {
+		return false
+	}
+	if err != nil {
+		return yield(LogEntry{}, fmt.Errorf("failed to query %s: %w", filename, err))
+	}
+	return true
+}
+
+// scanGzipFile passes the entries of a compressed file within the filter's
+// time range to fn.
+func scanGzipFile(filename string, filter Filter, timestampKey string, fn func([]byte) error) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to decompress log file: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+	return scanJSONLRange(zr, filter.Since, filter.Until, timestampKey, fn)
+}
+
+// queryFiles lists the log files in dir in name order, excluding side
+// indexes, temporary files and symlinks.
+func queryFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasSuffix(e.Name(), jsonlIndexSuffix) || strings.HasSuffix(e.Name(), ".tmp") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// parseTimestamp parses an RFC 3339 timestamp. A leap second (23:59:60),
+// which time.Parse rejects, is read as the first second of the next minute,
+// so entries written by systems that record leap seconds stay readable.
+func parseTimestamp(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err == nil {
+		return t, nil
+	}
+	if len(s) > 19 && s[16:19] == ":60" {
+		if t, leapErr := time.Parse(time.RFC3339Nano, s[:16]+":59"+s[19:]); leapErr == nil {
+			return t.Add(time.Second), nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// ParseJSONEntry decodes a JSON log line produced by the JSON formatters back
+// into a LogEntry. The timestamp, level, message and file keys populate the
+// corresponding LogEntry fields; every other key is placed in Fields.
+func ParseJSONEntry(line []byte) (LogEntry, bool) {
+	return ParseJSONEntryWithKeys(line, FieldKeys{})
+}
+
+// ParseJSONEntryWithKeys is ParseJSONEntry for entries written with the
+// field keys set by WithFieldKeys.
+func ParseJSONEntryWithKeys(line []byte, keys FieldKeys) (LogEntry, bool) {
+	keys = keys.jsonKeys()
+	var data map[string]interface{}
+	if err := json.Unmarshal(line, &data); err != nil {
+		return LogEntry{}, false
+	}
+
+	entry := LogEntry{Fields: make(map[string]interface{}, len(data))}
+	for k, v := range data {
+		switch k {
+		case keys.Timestamp:
+			if s, ok := v.(string); ok {
+				entry.Timestamp, _ = parseTimestamp(s)
+			}
+		case keys.Level:
+			if s, ok := v.(string); ok {
+				entry.Level, _ = ParseLevel(s)
+			}
+		case keys.Message:
+			entry.Message, _ = v.(string)
+		case "file":
+			entry.File, _ = v.(string)
+		default:
+			entry.Fields[k] = v
+		}
+	}
+	return entry, true
+}
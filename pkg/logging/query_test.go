@@ -0,0 +1,187 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeQueryFixture(t *testing.T, dir string, base time.Time) {
+	t.Helper()
+
+	lines := []string{
+		`{"timestamp":%q,"level":"INFO","message":"request served","trace_id":"abc"}`,
+		`{"timestamp":%q,"level":"ERROR","message":"db failed","trace_id":"abc","attempt":2}`,
+		`{"timestamp":%q,"level":"ERROR","message":"other failure","trace_id":"xyz"}`,
+		`{"timestamp":%q,"level":"CRITICAL","message":"late failure","trace_id":"abc"}`,
+	}
+
+	for i, name := range []string{"app-1.jsonl", "app-2.jsonl"} {
+		w, err := NewJSONLWriter(filepath.Join(dir, name), 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for j, line := range lines {
+			ts := base.Add(time.Duration(i*len(lines)+j) * time.Minute).Format(time.RFC3339)
+			if err := w.Write([]byte(fmt.Sprintf(line, ts) + "\n")); err != nil {
+				t.Fatalf("unexpected write error: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected close error: %v", err)
+		}
+	}
+}
+
+func TestQuery_FiltersByLevelAndField(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	writeQueryFixture(t, dir, base)
+
+	var messages []string
+	for entry, err := range Query(dir, Filter{
+		Level:       ErrorLevel,
+		FieldEquals: map[string]interface{}{"trace_id": "abc"},
+	}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		messages = append(messages, entry.Message)
+	}
+
+	if len(messages) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %v", len(messages), messages)
+	}
+	if messages[0] != "db failed" || messages[1] != "late failure" {
+		t.Errorf("unexpected order: %v", messages)
+	}
+}
+
+func TestQuery_FiltersByTimeRange(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	writeQueryFixture(t, dir, base)
+
+	count := 0
+	for entry, err := range Query(dir, Filter{Since: base.Add(3 * time.Minute), Until: base.Add(5 * time.Minute)}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entry.Timestamp.Before(base.Add(3*time.Minute)) || entry.Timestamp.After(base.Add(5*time.Minute)) {
+			t.Errorf("entry outside range: %v", entry.Timestamp)
+		}
+		count++
+	}
+
+	if count != 3 {
+		t.Errorf("expected 3 entries, got %d", count)
+	}
+}
+
+func TestQuery_NumericFieldEquals(t *testing.T) {
+	dir := t.TempDir()
+	writeQueryFixture(t, dir, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	count := 0
+	for _, err := range Query(dir, Filter{FieldEquals: map[string]interface{}{"attempt": 2}}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		count++
+	}
+
+	if count != 2 {
+		t.Errorf("expected 2 entries, got %d", count)
+	}
+}
+
+func TestQuery_StopEarly(t *testing.T) {
+	dir := t.TempDir()
+	writeQueryFixture(t, dir, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	count := 0
+	for range Query(dir, Filter{}) {
+		count++
+		if count == 1 {
+			break
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("expected iteration to stop after 1 entry, got %d", count)
+	}
+}
+
+func TestQuery_SkipsSymlinksAndReadsGzip(t *testing.T) {
+	dir := t.TempDir()
+	writeQueryFixture(t, dir, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	if err := os.Symlink(filepath.Join(dir, "app-2.jsonl"), filepath.Join(dir, "current.jsonl")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := gzipFile(filepath.Join(dir, "app-1.jsonl")); err != nil {
+		t.Fatalf("gzip failed: %v", err)
+	}
+
+	count := 0
+	for entry, err := range Query(dir, Filter{}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entry.Message == "" {
+			t.Errorf("unexpected entry: %+v", entry)
+		}
+		count++
+	}
+	if count != 8 {
+		t.Errorf("expected 8 entries, got %d", count)
+	}
+}
+
+func TestQueryWithConfig_FieldKeys(t *testing.T) {
+	dir := t.TempDir()
+	keys := FieldKeys{Timestamp: "@timestamp", Level: "severity", Message: "msg"}
+	w, err := NewJSONLWriterWithConfig(filepath.Join(dir, "app.jsonl"), JSONLConfig{Keys: keys})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = w.Write([]byte(`{"@timestamp":"2024-05-01T12:00:00Z","severity":"ERROR","msg":"failed"}` + "\n"))
+	_ = w.Close()
+
+	var got []LogEntry
+	for entry, err := range QueryWithConfig(dir, Filter{Level: ErrorLevel}, JSONLConfig{Keys: keys}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, entry)
+	}
+	if len(got) != 1 || got[0].Message != "failed" || got[0].Timestamp.IsZero() || len(got[0].Fields) != 0 {
+		t.Errorf("unexpected entries: %+v", got)
+	}
+}
+
+func TestQuery_MissingDirectory(t *testing.T) {
+	for _, err := range Query(filepath.Join(t.TempDir(), "missing"), Filter{}) {
+		if err == nil {
+			t.Error("expected error for missing directory")
+		}
+	}
+}
+
+func TestParseJSONEntry(t *testing.T) {
+	entry, ok := ParseJSONEntry([]byte(`{"timestamp":"2024-05-01T12:00:00Z","level":"WARN","message":"hi","file":"a.go:1","k":"v"}`))
+	if !ok {
+		t.Fatal("expected entry to parse")
+	}
+	if entry.Level != WarnLevel || entry.Message != "hi" || entry.File != "a.go:1" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Fields["k"] != "v" {
+		t.Errorf("expected field k=v, got %v", entry.Fields)
+	}
+
+	if _, ok := ParseJSONEntry([]byte("not json")); ok {
+		t.Error("expected invalid JSON to be rejected")
+	}
+}
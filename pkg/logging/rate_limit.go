@@ -0,0 +1,25 @@
+package logging
+
+import "time"
+
+// windowLimiter allows up to limit events per fixed window of time. It is
+// not safe for concurrent use.
+type windowLimiter struct {
+	limit  int
+	window time.Duration
+	start  time.Time
+	count  int
+}
+
+// allow reports whether another event fits in the window containing now.
+func (l *windowLimiter) allow(now time.Time) bool {
+	if now.Sub(l.start) >= l.window {
+		l.start = now
+		l.count = 0
+	}
+	if l.count >= l.limit {
+		return false
+	}
+	l.count++
+	return true
+}
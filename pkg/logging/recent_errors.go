@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"sync"
+)
+
+// RecentErrorsLogger is implemented by loggers that keep their most recent
+// ERROR and CRITICAL entries in memory. Loggers built with
+// NewWithLoggerConfig implement it.
+type RecentErrorsLogger interface {
+	Logger
+
+	// RecentErrors returns up to n of the most recent ERROR and CRITICAL
+	// entries, newest first. n <= 0 returns every kept entry.
+	RecentErrors(n int) []LogEntry
+}
+
+// RecentErrors returns up to n of the default logger's most recent ERROR
+// and CRITICAL entries, newest first. It returns nil unless the default
+// logger keeps them; see WithRecentErrors.
+//
+// Example:
+//
+//	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+//		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+//			"status":      "ok",
+//			"last_errors": logging.RecentErrors(5),
+//		})
+//	})
+func RecentErrors(n int) []LogEntry {
+	if logger, ok := GetDefaultLogger().(RecentErrorsLogger); ok {
+		return logger.RecentErrors(n)
+	}
+	return nil
+}
+
+// WithRecentErrors keeps the last size ERROR and CRITICAL entries in memory
+// for RecentErrors, so health endpoints and admin UIs can show them without
+// querying a log backend.
+func (b *CoreConfigBuilder) WithRecentErrors(size int) *CoreConfigBuilder {
+	if size < 0 {
+		reportInvalidConfig("WithRecentErrors", "ignoring negative size", nil)
+		return b
+	}
+	b.config.RecentErrors = size
+	return b
+}
+
+// WithRecentErrors keeps the last size ERROR and CRITICAL entries in memory
+// for RecentErrors.
+//
+// Example:
+//
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithRecentErrors(50).
+//		Build())
+//	...
+//	for _, entry := range logger.(logging.RecentErrorsLogger).RecentErrors(10) {
+//		fmt.Println(entry.Timestamp, entry.Message)
+//	}
+func (b *LoggerConfigBuilder) WithRecentErrors(size int) *LoggerConfigBuilder {
+	if size < 0 {
+		reportInvalidConfig("WithRecentErrors", "ignoring negative size", nil)
+		return b
+	}
+	b.config.Core.RecentErrors = size
+	return b
+}
+
+// errorRing keeps the most recent error entries of a logger and its
+// children.
+type errorRing struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int
+	full    bool
+}
+
+func newErrorRing(size int) *errorRing {
+	if size <= 0 {
+		return nil
+	}
+	return &errorRing{entries: make([]LogEntry, size)}
+}
+
+// add keeps entry, replacing the oldest one when the ring is full. Trace,
+// request and correlation IDs from the entry's context are kept as fields;
+// the context itself is not retained.
+func (r *errorRing) add(entry LogEntry) {
+	fields := copyFields(entry.Fields, 3)
+	contextFieldsFrom(entry.Context).Omit(fields).AddToMap(fields)
+	entry.Fields = fields
+	entry.Context = nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// last returns up to n entries, newest first.
+func (r *errorRing) last(n int) []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+	entries := make([]LogEntry, 0, n)
+	for i := 1; i <= n; i++ {
+		entries = append(entries, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return entries
+}
+
+// RecentErrors returns up to n of the most recent ERROR and CRITICAL
+// entries logged through this logger, its parent or its children, newest
+// first. It returns nil unless WithRecentErrors is configured.
+func (ul *unifiedLogger) RecentErrors(n int) []LogEntry {
+	if ul.recentErrors == nil {
+		return nil
+	}
+	return ul.recentErrors.last(n)
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestRecentErrors(t *testing.T) {
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithWriter(io.Discard).
+		WithRecentErrors(3).
+		Build())
+	recent := logger.(RecentErrorsLogger)
+
+	if got := recent.RecentErrors(10); len(got) != 0 {
+		t.Fatalf("expected no entries yet, got %v", got)
+	}
+
+	ctx := WithTraceID(context.Background(), "trace-1")
+	logger.Info("not an error")
+	logger.Error("first")
+	logger.WithField("component", "db").ErrorContext(ctx, "second")
+	logger.Warn("not an error either")
+	logger.Critical("third")
+	logger.Error("fourth")
+
+	got := recent.RecentErrors(0)
+	want := []string{"fourth", "third", "second"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), got)
+	}
+	for i, message := range want {
+		if got[i].Message != message {
+			t.Errorf("entry %d = %q, want %q", i, got[i].Message, message)
+		}
+	}
+	if got[1].Level != CriticalLevel {
+		t.Errorf("expected CRITICAL, got %s", got[1].Level)
+	}
+	if got[2].Fields["component"] != "db" || got[2].Fields["trace_id"] != "trace-1" || got[2].Context != nil {
+		t.Errorf("expected fields and context IDs without the context, got %+v", got[2])
+	}
+
+	if got := recent.RecentErrors(1); len(got) != 1 || got[0].Message != "fourth" {
+		t.Errorf("expected only the newest entry, got %+v", got)
+	}
+}
+
+func TestRecentErrors_Slog(t *testing.T) {
+	config := NewLoggerConfig().WithWriter(io.Discard).WithRecentErrors(5).Build()
+	config.UseSlog = true
+	logger := NewWithLoggerConfig(config)
+
+	logger.WithField("attempt", 3).Error("payment failed")
+
+	got := logger.(RecentErrorsLogger).RecentErrors(5)
+	if len(got) != 1 || got[0].Message != "payment failed" || got[0].Fields["attempt"] != 3 || got[0].Timestamp.IsZero() {
+		t.Errorf("unexpected entries %+v", got)
+	}
+}
+
+func TestRecentErrors_Disabled(t *testing.T) {
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithWriter(io.Discard).Build())
+	logger.Error("failure")
+	if got := logger.(RecentErrorsLogger).RecentErrors(5); got != nil {
+		t.Errorf("expected nil without WithRecentErrors, got %v", got)
+	}
+
+	previous := GetDefaultLogger()
+	defer SetDefaultLogger(previous)
+	SetDefaultLogger(NewWithLoggerConfig(NewLoggerConfig().WithWriter(io.Discard).WithRecentErrors(2).Build()))
+	GetDefaultLogger().Error("default failure")
+	if got := RecentErrors(1); len(got) != 1 || got[0].Message != "default failure" {
+		t.Errorf("expected the default logger's error, got %+v", got)
+	}
+}
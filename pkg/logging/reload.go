@@ -0,0 +1,466 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReloadDedupField is the field carrying the marker of entries written to
+// both pipelines during a reload overlap. Copies with the same marker are
+// the same entry.
+const ReloadDedupField = "reload_dedup_id"
+
+// Pipeline is a formatter and the output it writes to, the part of a
+// logger a reload replaces.
+type Pipeline struct {
+	// Formatter formats entries for Output. It is used by the Formatter of
+	// the ReloadableOutput and to re-format entries whose data it did not
+	// format, such as those marked during an overlap; if nil, Output
+	// receives the logger's data as is.
+	Formatter Formatter
+	// Output receives the entries.
+	Output Output
+	// Fields are added to every entry, under the entry's own fields.
+	Fields map[string]interface{}
+}
+
+// ReloadConfig configures a ReloadableOutput.
+type ReloadConfig struct {
+	// Overlap is how long entries are written to both the new and the old
+	// pipeline after a swap, so nothing is missed while the new one warms
+	// up, e.g. connects. Defaults to one second.
+	Overlap time.Duration
+}
+
+// ReloadableOutput writes to a Pipeline that can be replaced while the
+// logger is running without losing entries. Swap takes a pipeline that is
+// already built, switches to it atomically, writes to both pipelines for
+// the Overlap and only then closes the old one, which drains its queues.
+// Entries written during the overlap carry a ReloadDedupField marker, so
+// a destination receiving both copies can drop one.
+//
+// Example:
+//
+//	output := logging.NewReloadableOutput(logging.Pipeline{
+//		Formatter: logging.NewJSONFormatter(logging.NewFormatterConfig().Build()),
+//		Output:    logging.NewWriterOutput(os.Stdout),
+//	}, logging.ReloadConfig{})
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithCustomFormatter(output.Formatter()).
+//		WithCustomOutput(output).
+//		Build())
+//	// Later, e.g. on SIGHUP:
+//	err := output.Swap(logging.Pipeline{Formatter: formatter, Output: fileOutput})
+type ReloadableOutput struct {
+	overlap time.Duration
+	prefix  string
+	seq     atomic.Uint64
+	swapMu  sync.Mutex
+
+	// formatted maps the first byte of data returned by Formatter to the
+	// generation of the pipeline that formatted it, so WriteEntry can tell
+	// whether data suits the pipeline it is written to.
+	formatted sync.Map
+	tags      atomic.Int64
+
+	mu          sync.RWMutex
+	current     Pipeline
+	previous    *Pipeline
+	currentGen  uint64
+	previousGen uint64
+	closed      bool
+}
+
+// maxReloadTags bounds the formatted data a ReloadableOutput remembers.
+// Data it has forgotten is re-formatted.
+const maxReloadTags = 4096
+
+// tag records that data was formatted by the pipeline of generation gen.
+func (ro *ReloadableOutput) tag(data []byte, gen uint64) {
+	if len(data) == 0 {
+		return
+	}
+	if ro.tags.Add(1) > maxReloadTags {
+		ro.formatted.Clear()
+		ro.tags.Store(0)
+	}
+	ro.formatted.Store(&data[0], gen)
+}
+
+// formattedBy returns the generation of the pipeline that formatted data,
+// or false if data did not come from Formatter.
+func (ro *ReloadableOutput) formattedBy(data []byte) (uint64, bool) {
+	if len(data) == 0 {
+		return 0, false
+	}
+	gen, ok := ro.formatted.LoadAndDelete(&data[0])
+	if !ok {
+		return 0, false
+	}
+	ro.tags.Add(-1)
+	return gen.(uint64), true
+}
+
+// NewReloadableOutput creates a ReloadableOutput writing to initial.
+func NewReloadableOutput(initial Pipeline, config ReloadConfig) *ReloadableOutput {
+	if config.Overlap <= 0 {
+		config.Overlap = time.Second
+	}
+	prefix := make([]byte, 6)
+	_, _ = rand.Read(prefix)
+	return &ReloadableOutput{
+		overlap: config.Overlap,
+		prefix:  hex.EncodeToString(prefix),
+		current: initial,
+	}
+}
+
+// Formatter returns a Formatter that formats entries with the current
+// pipeline's Formatter and Fields. Loggers writing to the output should use
+// it, so each entry is formatted once for the pipeline it is written to.
+func (ro *ReloadableOutput) Formatter() Formatter {
+	return reloadFormatter{ro}
+}
+
+// reloadFormatter formats entries for the current pipeline.
+type reloadFormatter struct {
+	ro *ReloadableOutput
+}
+
+func (f reloadFormatter) Format(entry LogEntry) ([]byte, error) {
+	f.ro.mu.RLock()
+	p, gen := f.ro.current, f.ro.currentGen
+	f.ro.mu.RUnlock()
+
+	if p.Formatter == nil {
+		return nil, fmt.Errorf("pipeline has no formatter")
+	}
+	data, err := p.Formatter.Format(withPipelineFields(entry, p.Fields))
+	if err == nil {
+		f.ro.tag(data, gen)
+	}
+	return data, err
+}
+
+// withPipelineFields returns entry with fields added under its own.
+func withPipelineFields(entry LogEntry, fields map[string]interface{}) LogEntry {
+	if len(fields) == 0 {
+		return entry
+	}
+	merged := make(map[string]interface{}, len(fields)+len(entry.Fields))
+	for k, v := range fields {
+		merged[k] = v
+	}
+	for k, v := range entry.Fields {
+		merged[k] = v
+	}
+	entry.Fields = merged
+	return entry
+}
+
+// Write writes data to the current pipeline, and to the old one during an
+// overlap. Data formatted by Formatter for one pipeline is not written to
+// the other, as it cannot be re-formatted; data of a pipeline already
+// replaced goes to the current one.
+func (ro *ReloadableOutput) Write(data []byte) error {
+	gen, tagged := ro.formattedBy(data)
+
+	ro.mu.RLock()
+	defer ro.mu.RUnlock()
+
+	if ro.closed {
+		return fmt.Errorf("reloadable output is closed")
+	}
+	suits := func(p Pipeline, pipelineGen uint64) bool {
+		return p.Formatter == nil || !tagged || gen == pipelineGen
+	}
+	var err error
+	toPrevious := ro.previous != nil && suits(*ro.previous, ro.previousGen)
+	if toPrevious {
+		err = ro.previous.Output.Write(data)
+	}
+	if !toPrevious || suits(ro.current, ro.currentGen) {
+		if curErr := ro.current.Output.Write(data); err == nil {
+			err = curErr
+		}
+	}
+	return err
+}
+
+// WriteEntry writes entry to the current pipeline, re-formatting it unless
+// data was formatted for that pipeline. During an overlap it is marked and
+// written, re-formatted, to both pipelines.
+func (ro *ReloadableOutput) WriteEntry(entry LogEntry, data []byte) error {
+	gen, tagged := ro.formattedBy(data)
+
+	ro.mu.RLock()
+	defer ro.mu.RUnlock()
+
+	if ro.closed {
+		return fmt.Errorf("reloadable output is closed")
+	}
+	if ro.previous == nil {
+		return writePipelineEntry(ro.current, entry, data, tagged && gen == ro.currentGen)
+	}
+
+	marker := ro.prefix + "-" + strconv.FormatUint(ro.seq.Add(1), 10)
+	entry = withPipelineFields(entry, map[string]interface{}{ReloadDedupField: marker})
+	err := writePipelineEntry(ro.current, entry, data, false)
+	if prevErr := writePipelineEntry(*ro.previous, entry, data, false); err == nil {
+		err = prevErr
+	}
+	return err
+}
+
+// writePipelineEntry writes entry to p, formatting it with p's Formatter
+// unless data is already formatted for p.
+func writePipelineEntry(p Pipeline, entry LogEntry, data []byte, formatted bool) error {
+	entry = withPipelineFields(entry, p.Fields)
+	if p.Formatter != nil && !formatted {
+		formatted, err := p.Formatter.Format(entry)
+		if err != nil {
+			return fmt.Errorf("failed to format entry: %w", err)
+		}
+		data = formatted
+	}
+	return writeEntry(p.Output, entry, data)
+}
+
+// Swap switches to next, which must be ready to write, writes to both
+// pipelines for the Overlap and then closes the old pipeline's output. It
+// returns once the old output is closed, with its error.
+func (ro *ReloadableOutput) Swap(next Pipeline) error {
+	if next.Output == nil {
+		return fmt.Errorf("pipeline has no output")
+	}
+	ro.swapMu.Lock()
+	defer ro.swapMu.Unlock()
+
+	ro.mu.Lock()
+	if ro.closed {
+		ro.mu.Unlock()
+		return fmt.Errorf("reloadable output is closed")
+	}
+	old := ro.current
+	ro.current = next
+	ro.previous = &old
+	ro.previousGen = ro.currentGen
+	ro.currentGen++
+	ro.mu.Unlock()
+
+	time.Sleep(ro.overlap)
+
+	// Detaching waits for writes in flight, so none reach a closed output.
+	ro.mu.Lock()
+	ro.previous = nil
+	ro.mu.Unlock()
+
+	if err := old.Output.Close(); err != nil {
+		return fmt.Errorf("failed to close previous output: %w", err)
+	}
+	return nil
+}
+
+// Close closes the current pipeline's output, after any swap in progress.
+func (ro *ReloadableOutput) Close() error {
+	ro.swapMu.Lock()
+	defer ro.swapMu.Unlock()
+
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	if ro.closed {
+		return nil
+	}
+	ro.closed = true
+	return ro.current.Output.Close()
+}
+
+// reloadableSettings are the YAML settings a YAMLReloader can change; the
+// rest are fixed when the logger is built.
+var reloadableSettings = []string{"level", "format", "template", "field_keys.", "time_format", "time_zone", "time_precision", "field_order", "priority_fields", "group_style", "theme.", "include_time", "use_short_file", "collapse_multiline", "preset", "static_fields.", "output.type", "output.target"}
+
+// YAMLReloader owns a logger built from YAML configuration whose level,
+// format, static fields and output can be replaced by Reload without losing
+// entries, through a ReloadableOutput. Other settings, and use_slog, which
+// bypasses outputs, cannot be reloaded.
+//
+// Example:
+//
+//	reloader, err := logging.NewYAMLReloader(data, logging.ReloadConfig{})
+//	if err != nil {
+//		return err
+//	}
+//	logger := reloader.Logger()
+//	// On SIGHUP:
+//	data, _ = os.ReadFile(path)
+//	if err := reloader.Reload(data, logging.ConfigChangeFromFile(path)); err != nil {
+//		logger.Error("reload failed", "error", err)
+//	}
+type YAMLReloader struct {
+	logger Logger
+	output *ReloadableOutput
+
+	mu     sync.Mutex
+	config *YAMLConfig
+}
+
+// NewYAMLReloader builds a logger from YAML data.
+func NewYAMLReloader(data []byte, config ReloadConfig) (*YAMLReloader, error) {
+	yamlConfig, err := parseReloadableYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	if yamlConfig.UseSlog {
+		return nil, fmt.Errorf("use_slog cannot be used with a reloadable logger")
+	}
+	pipeline, err := pipelineFromYAML(yamlConfig)
+	if err != nil {
+		return nil, err
+	}
+	output := NewReloadableOutput(pipeline, config)
+
+	// Static fields belong to the pipeline so a reload can change them.
+	core := *yamlConfig
+	core.StaticFields = nil
+	builder := NewLoggerConfig()
+	if err := configureCoreFromYAML(builder, &core); err != nil {
+		return nil, fmt.Errorf("failed to configure core: %w", err)
+	}
+	if err := configureFormatterFromYAML(builder, yamlConfig); err != nil {
+		return nil, fmt.Errorf("failed to configure formatter: %w", err)
+	}
+	if yamlConfig.Output.DeadLetter != "" {
+		dlq, err := NewDeadLetterFile(yamlConfig.Output.DeadLetter)
+		if err != nil {
+			return nil, err
+		}
+		builder.WithWriteErrorHandler(dlq.Handle)
+	}
+	builder.WithCustomFormatter(output.Formatter()).WithCustomOutput(output)
+
+	loggerConfig := builder.Build()
+	return &YAMLReloader{
+		logger: NewUnifiedLogger(loggerConfig, ProvideRedactorChainFromLoggerConfig(loggerConfig)),
+		output: output,
+		config: yamlConfig,
+	}, nil
+}
+
+// parseReloadableYAML parses data and applies its preset.
+func parseReloadableYAML(data []byte) (*YAMLConfig, error) {
+	var yamlConfig YAMLConfig
+	if err := unmarshalYAMLConfig(data, &yamlConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML configuration: %w", err)
+	}
+	if yamlConfig.Preset != "" {
+		if err := applyPreset(&yamlConfig, yamlConfig.Preset); err != nil {
+			return nil, fmt.Errorf("failed to apply preset '%s': %w", yamlConfig.Preset, err)
+		}
+	}
+	return &yamlConfig, nil
+}
+
+// pipelineFromYAML builds the formatter, output and static fields of
+// yamlConfig.
+func pipelineFromYAML(yamlConfig *YAMLConfig) (Pipeline, error) {
+	fields, err := expandStaticFields(yamlConfig.StaticFields)
+	if err != nil {
+		return Pipeline{}, fmt.Errorf("failed to configure core: %w", err)
+	}
+
+	// The dead letter file is the logger's, not the pipeline's.
+	outputConfig := *yamlConfig
+	outputConfig.Output.DeadLetter = ""
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, yamlConfig); err != nil {
+		return Pipeline{}, fmt.Errorf("failed to configure formatter: %w", err)
+	}
+	if err := configureOutputFromYAML(builder, &outputConfig); err != nil {
+		return Pipeline{}, fmt.Errorf("failed to configure output: %w", err)
+	}
+	config := builder.Build()
+	if w := config.Output.Writer; w == os.Stdout || w == os.Stderr {
+		// Closing the old pipeline must not close the standard streams.
+		config.Output.Writer = struct{ io.Writer }{w}
+	}
+	return Pipeline{
+		Formatter: newFormatterFromConfig(config),
+		Output:    newOutputFromConfig(config),
+		Fields:    fields,
+	}, nil
+}
+
+// Logger returns the logger.
+func (r *YAMLReloader) Logger() Logger {
+	return r.logger
+}
+
+// Reload applies the YAML configuration in data. The new pipeline is built
+// before anything changes, so an invalid configuration leaves the logger as
+// it was; the old pipeline is drained and closed before Reload returns.
+// The changes are audited with AuditConfigChange.
+func (r *YAMLReloader) Reload(data []byte, source ConfigChangeSource) error {
+	next, err := parseReloadableYAML(data)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changes := DiffYAMLConfig(r.config, next)
+	for _, change := range changes {
+		if !isReloadableSetting(change.Setting) {
+			return fmt.Errorf("%s cannot be changed by a reload", change.Setting)
+		}
+	}
+	if !reflect.DeepEqual(r.config.FieldMigrations, next.FieldMigrations) {
+		return fmt.Errorf("field_migrations cannot be changed by a reload")
+	}
+	if !reflect.DeepEqual(r.config.EncryptFields, next.EncryptFields) {
+		return fmt.Errorf("encrypt_fields cannot be changed by a reload")
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	level := InfoLevel
+	if next.Level != "" {
+		var ok bool
+		if level, ok = ParseLevel(next.Level); !ok {
+			return fmt.Errorf("invalid log level: %s", next.Level)
+		}
+	}
+	pipeline, err := pipelineFromYAML(next)
+	if err != nil {
+		return err
+	}
+
+	swapErr := r.output.Swap(pipeline)
+	r.config = next
+	setLevelWithAudit(r.logger, level, source, changes)
+	return swapErr
+}
+
+func isReloadableSetting(setting string) bool {
+	for _, s := range reloadableSettings {
+		if setting == s || (strings.HasSuffix(s, ".") && strings.HasPrefix(setting, s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close closes the logger's output.
+func (r *YAMLReloader) Close() error {
+	return r.output.Close()
+}
@@ -0,0 +1,216 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// closeRecorder is a WriterOutput over a buffer that records Close.
+type closeRecorder struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (cr *closeRecorder) Write(data []byte) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if cr.closed {
+		return os.ErrClosed
+	}
+	cr.buf.Write(data)
+	return nil
+}
+
+func (cr *closeRecorder) Close() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.closed = true
+	return nil
+}
+
+func (cr *closeRecorder) lines(t *testing.T) []map[string]interface{} {
+	t.Helper()
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(cr.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestReloadableOutput_SwapOverlap(t *testing.T) {
+	jsonFormatter := NewJSONFormatter(NewFormatterConfig().Build())
+	oldOutput, newOutput := &closeRecorder{}, &closeRecorder{}
+	output := NewReloadableOutput(Pipeline{
+		Formatter: jsonFormatter,
+		Output:    oldOutput,
+		Fields:    map[string]interface{}{"pipeline": "old"},
+	}, ReloadConfig{Overlap: 50 * time.Millisecond})
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithCustomFormatter(output.Formatter()).
+		WithCustomOutput(output).
+		Build())
+
+	logger.Info("before")
+
+	swapped := make(chan error, 1)
+	go func() {
+		swapped <- output.Swap(Pipeline{
+			Formatter: jsonFormatter,
+			Output:    newOutput,
+			Fields:    map[string]interface{}{"pipeline": "new"},
+		})
+	}()
+	// Wait for the swap, then log during the overlap.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		output.mu.RLock()
+		swapping := output.previous != nil
+		output.mu.RUnlock()
+		if swapping || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	logger.Info("during")
+	if err := <-swapped; err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	logger.Info("after")
+
+	if !oldOutput.closed {
+		t.Error("expected the old output to be closed")
+	}
+	old, current := oldOutput.lines(t), newOutput.lines(t)
+	if len(old) != 2 || old[0]["message"] != "before" || old[1]["message"] != "during" {
+		t.Fatalf("unexpected old pipeline entries %v", old)
+	}
+	if len(current) != 2 || current[0]["message"] != "during" || current[1]["message"] != "after" {
+		t.Fatalf("unexpected new pipeline entries %v", current)
+	}
+	if _, ok := old[0][ReloadDedupField]; ok {
+		t.Error("expected no marker outside the overlap")
+	}
+	marker := old[1][ReloadDedupField]
+	if marker == nil || current[0][ReloadDedupField] != marker {
+		t.Errorf("expected matching markers, got %v and %v", marker, current[0][ReloadDedupField])
+	}
+	if old[1]["pipeline"] != "old" || current[0]["pipeline"] != "new" || current[1]["pipeline"] != "new" {
+		t.Errorf("expected pipeline fields, got %v and %v", old, current)
+	}
+}
+
+func TestReloadableOutput_FormatsForTheWrittenPipeline(t *testing.T) {
+	jsonFormatter := NewJSONFormatter(NewFormatterConfig().Build())
+	oldOutput, newOutput := &closeRecorder{}, &closeRecorder{}
+	output := NewReloadableOutput(Pipeline{
+		Formatter: jsonFormatter,
+		Output:    oldOutput,
+		Fields:    map[string]interface{}{"pipeline": "old"},
+	}, ReloadConfig{Overlap: time.Millisecond})
+	entry := LogEntry{Level: InfoLevel, Message: "raced"}
+
+	// Formatted for the old pipeline, written after the swap.
+	data, err := output.Formatter().Format(entry)
+	if err != nil {
+		t.Fatalf("format failed: %v", err)
+	}
+	if err := output.Swap(Pipeline{
+		Formatter: jsonFormatter,
+		Output:    newOutput,
+		Fields:    map[string]interface{}{"pipeline": "new"},
+	}); err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if err := output.WriteEntry(entry, data); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	lines := newOutput.lines(t)
+	if len(lines) != 1 || lines[0]["pipeline"] != "new" {
+		t.Errorf("expected the entry re-formatted for the new pipeline, got %v", lines)
+	}
+	if len(oldOutput.lines(t)) != 0 {
+		t.Errorf("expected nothing written to the old pipeline, got %v", oldOutput.lines(t))
+	}
+}
+
+func TestYAMLReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	reloader, err := NewYAMLReloader([]byte(`
+level: info
+format: json
+static_fields:
+  service: api
+output:
+  type: file
+  target: `+first), ReloadConfig{Overlap: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewYAMLReloader() error = %v", err)
+	}
+	logger := reloader.Logger()
+	logger.Info("one")
+
+	err = reloader.Reload([]byte(`
+level: debug
+format: json
+static_fields:
+  service: api
+  region: eu
+output:
+  type: file
+  target: `+second), ConfigChangeFromFile("logging.yaml"))
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	logger.Debug("two")
+	_ = reloader.Close()
+
+	firstData, _ := os.ReadFile(first)
+	secondData, _ := os.ReadFile(second)
+	if !strings.Contains(string(firstData), `"message":"one"`) || strings.Contains(string(firstData), `"message":"two"`) {
+		t.Errorf("unexpected first file %s", firstData)
+	}
+	if !strings.Contains(string(secondData), ConfigAuditMessage) || !strings.Contains(string(secondData), `"region":"eu"`) ||
+		!strings.Contains(string(secondData), `"message":"two"`) {
+		t.Errorf("unexpected second file %s", secondData)
+	}
+
+	if err := reloader.Reload([]byte("level: debug\nformat: json\nuse_short_file: true\nutc: true\n"), ConfigChangeSource{}); err == nil ||
+		!strings.Contains(err.Error(), "utc") {
+		t.Errorf("expected utc to be rejected, got %v", err)
+	}
+}
+
+func TestYAMLReloader_InvalidConfigKeepsPipeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	reloader, err := NewYAMLReloader([]byte("format: json\noutput:\n  type: file\n  target: "+path), ReloadConfig{})
+	if err != nil {
+		t.Fatalf("NewYAMLReloader() error = %v", err)
+	}
+	defer reloader.Close()
+
+	if err := reloader.Reload([]byte("format: xml\n"), ConfigChangeSource{}); err == nil {
+		t.Fatal("expected error for invalid format")
+	}
+	reloader.Logger().Info("still here")
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "still here") {
+		t.Errorf("expected entry in original output, got %s", data)
+	}
+}
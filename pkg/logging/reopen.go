@@ -0,0 +1,190 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// Reopener is implemented by outputs that can reopen the files they write
+// to, such as ReopenableOutput.
+type Reopener interface {
+	Reopen() error
+}
+
+// reopenables holds the open ReopenableOutputs reopened by
+// HandleRotationSignals when it is given no outputs.
+var reopenables struct {
+	mu      sync.Mutex
+	outputs []*ReopenableOutput
+}
+
+// ReopenableOutput writes to a file like FileOutput, and can reopen it by
+// name. Use it when an external tool such as logrotate moves the file
+// aside: after Reopen, entries go to a new file at the original path.
+// Writes wait while the file is reopened, so no entry is dropped or written
+// to a closed file.
+//
+// Example:
+//
+//	output, err := logging.NewReopenableOutput("/var/log/app.log")
+//	if err != nil {
+//		return err
+//	}
+//	stop := logging.HandleRotationSignals(output)
+//	defer stop()
+//
+// with a logrotate postrotate script running "kill -HUP <pid>".
+type ReopenableOutput struct {
+	config FileOutputConfig
+	mu     sync.RWMutex
+	file   *FileOutput
+}
+
+// NewReopenableOutput creates a ReopenableOutput writing to filename.
+func NewReopenableOutput(filename string) (*ReopenableOutput, error) {
+	return NewReopenableOutputWithConfig(FileOutputConfig{Filename: filename})
+}
+
+// NewReopenableOutputWithConfig creates a ReopenableOutput whose files use
+// the given sync policy.
+func NewReopenableOutputWithConfig(config FileOutputConfig) (*ReopenableOutput, error) {
+	file, err := NewFileOutputWithConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	ro := &ReopenableOutput{config: config, file: file}
+
+	reopenables.mu.Lock()
+	reopenables.outputs = append(reopenables.outputs, ro)
+	reopenables.mu.Unlock()
+	return ro, nil
+}
+
+// Filename returns the path the output writes to.
+func (ro *ReopenableOutput) Filename() string {
+	return ro.config.Filename
+}
+
+// Write writes data to the current file.
+func (ro *ReopenableOutput) Write(data []byte) error {
+	ro.mu.RLock()
+	defer ro.mu.RUnlock()
+
+	if ro.file == nil {
+		return fmt.Errorf("reopenable output is closed")
+	}
+	return ro.file.Write(data)
+}
+
+// Reopen opens the file name again and switches writes to it, then closes
+// the previous file, writing out anything it buffered. If the file cannot be
+// opened, writes continue to the previous file and the error is returned.
+func (ro *ReopenableOutput) Reopen() error {
+	file, err := NewFileOutputWithConfig(ro.config)
+	if err != nil {
+		return fmt.Errorf("failed to reopen log file: %w", err)
+	}
+
+	ro.mu.Lock()
+	previous := ro.file
+	if previous == nil {
+		ro.mu.Unlock()
+		_ = file.Close()
+		return fmt.Errorf("reopenable output is closed")
+	}
+	ro.file = file
+	ro.mu.Unlock()
+
+	return previous.Close()
+}
+
+// Close closes the file.
+func (ro *ReopenableOutput) Close() error {
+	reopenables.mu.Lock()
+	for i, output := range reopenables.outputs {
+		if output == ro {
+			reopenables.outputs = append(reopenables.outputs[:i], reopenables.outputs[i+1:]...)
+			break
+		}
+	}
+	reopenables.mu.Unlock()
+
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+
+	if ro.file == nil {
+		return nil
+	}
+	err := ro.file.Close()
+	ro.file = nil
+	return err
+}
+
+// HandleRotationSignals reopens outputs whenever the process receives
+// SIGHUP, the signal logrotate's postrotate scripts conventionally send.
+// Without outputs, every open ReopenableOutput is reopened. Failures are
+// reported as diagnostics and writes continue to the previous file. Call
+// the returned function to stop handling the signal. On platforms without
+// SIGHUP it does nothing.
+//
+// Example:
+//
+//	stop := logging.HandleRotationSignals()
+//	defer stop()
+func HandleRotationSignals(outputs ...Reopener) (stop func()) {
+	if len(rotationSignals) == 0 {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, rotationSignals...)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				reopenAll(outputs)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// reopenAll reopens outputs, or every open ReopenableOutput when there are
+// none, reporting failures as diagnostics.
+func reopenAll(outputs []Reopener) {
+	if len(outputs) == 0 {
+		reopenables.mu.Lock()
+		for _, output := range reopenables.outputs {
+			outputs = append(outputs, output)
+		}
+		reopenables.mu.Unlock()
+	}
+
+	for _, output := range outputs {
+		if err := output.Reopen(); err != nil {
+			// Not reportDiagnostic: a strict-mode panic here would crash the
+			// process from the signal goroutine.
+			if handler := diagnosticsHandler.Load(); handler != nil {
+				(*handler)(Diagnostic{
+					Kind:    DiagnosticFallback,
+					Source:  "HandleRotationSignals",
+					Message: "kept writing to the previous file",
+					Err:     err,
+				})
+			}
+		}
+	}
+}
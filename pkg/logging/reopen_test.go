@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReopenableOutput_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	output, err := NewReopenableOutput(filename)
+	if err != nil {
+		t.Fatalf("failed to create output: %v", err)
+	}
+	defer output.Close()
+
+	if err := output.Write([]byte("before\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := os.Rename(filename, filename+".1"); err != nil {
+		t.Fatal(err)
+	}
+	// Until reopened, entries follow the moved file.
+	if err := output.Write([]byte("moved\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := output.Reopen(); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if err := output.Write([]byte("after\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	rotated, _ := os.ReadFile(filename + ".1")
+	current, _ := os.ReadFile(filename)
+	if string(rotated) != "before\nmoved\n" || string(current) != "after\n" {
+		t.Errorf("unexpected contents: rotated %q, current %q", rotated, current)
+	}
+
+	if err := output.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if err := output.Write([]byte("closed\n")); err == nil {
+		t.Error("expected an error writing after Close")
+	}
+	if err := output.Reopen(); err == nil {
+		t.Error("expected an error reopening after Close")
+	}
+}
+
+func TestReopenableOutput_NoEntriesDropped(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	output, err := NewReopenableOutput(filename)
+	if err != nil {
+		t.Fatalf("failed to create output: %v", err)
+	}
+	defer output.Close()
+
+	const writers, perWriter = 4, 50
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				if err := output.Write([]byte(fmt.Sprintf("%d-%d\n", w, i))); err != nil {
+					t.Errorf("write failed: %v", err)
+				}
+			}
+		}(w)
+	}
+	for i := 1; i <= 3; i++ {
+		_ = os.Rename(filename, fmt.Sprintf("%s.%d", filename, i))
+		if err := output.Reopen(); err != nil {
+			t.Errorf("reopen failed: %v", err)
+		}
+	}
+	wg.Wait()
+
+	files, _ := filepath.Glob(filename + "*")
+	lines := 0
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		lines += strings.Count(string(data), "\n")
+	}
+	if lines != writers*perWriter {
+		t.Errorf("expected %d lines across %d files, got %d", writers*perWriter, len(files), lines)
+	}
+}
+
+func TestHandleRotationSignals(t *testing.T) {
+	if runtime.GOOS == "windows" || len(rotationSignals) == 0 {
+		t.Skip("SIGHUP cannot be sent on this platform")
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	output, err := NewReopenableOutput(filename)
+	if err != nil {
+		t.Fatalf("failed to create output: %v", err)
+	}
+	defer output.Close()
+
+	stop := HandleRotationSignals()
+	defer stop()
+
+	if err := os.Rename(filename, filename+".1"); err != nil {
+		t.Fatal(err)
+	}
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(rotationSignals[0]); err != nil {
+		t.Fatalf("failed to send SIGHUP: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(filename); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the file to be reopened after SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := output.Write([]byte("after\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if data, _ := os.ReadFile(filename); string(data) != "after\n" {
+		t.Errorf("expected the entry in the reopened file, got %q", data)
+	}
+}
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// RetryConfig controls how network outputs retry failed deliveries. Waits
+// grow exponentially from InitialBackoff up to MaxBackoff with full jitter.
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt. Defaults
+	// to 3; negative disables retries.
+	MaxRetries int
+	// InitialBackoff defaults to 500ms.
+	InitialBackoff time.Duration
+	// MaxBackoff defaults to 30s.
+	MaxBackoff time.Duration
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.MaxRetries == 0 {
+		c.MaxRetries = 3
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = 500 * time.Millisecond
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 30 * time.Second
+	}
+	return c
+}
+
+// permanentError marks an error that retrying won't fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent wraps err so retry returns it without further attempts.
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// retry calls fn until it succeeds, returns a permanent error, ctx is done
+// or the retries are exhausted. It returns the last error.
+func retry(ctx context.Context, config RetryConfig, fn func() error) error {
+	config = config.withDefaults()
+	backoff := config.InitialBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if attempt >= config.MaxRetries {
+			return err
+		}
+
+		wait := time.Duration(rand.Int64N(int64(backoff)) + 1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > config.MaxBackoff {
+			backoff = config.MaxBackoff
+		}
+	}
+}
+
+// checkHTTPResponse consumes resp's body and returns nil for 2xx statuses.
+// Other statuses produce an error that is permanent unless the status is
+// 429 or 5xx.
+func checkHTTPResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err := fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return err
+	}
+	return permanent(err)
+}
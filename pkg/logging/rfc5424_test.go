@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRFC5424Formatter_Format(t *testing.T) {
+	formatter := NewRFC5424Formatter(NewFormatterConfig().IncludeFile(false).Build(), RFC5424Config{
+		Facility:   SyslogFacilityLocal0,
+		AppName:    "orders",
+		Hostname:   "web 1",
+		MsgIDField: "event",
+		Elements:   map[string][]string{"http@32473": {"method", "status"}},
+	})
+	data, err := formatter.Format(LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 0, time.UTC),
+		Level:     WarnLevel,
+		Message:   "slow request\n",
+		Context:   WithRequestID(context.Background(), "req-1"),
+		Fields: map[string]interface{}{
+			"event":     "slow",
+			"method":    "GET",
+			"status":    200,
+			"query":     `name="a]b\c"`,
+			"user name": "alice",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	want := `<132>1 2025-11-24T10:30:00.000000Z web_1 orders ` + formatter.pid + ` slow ` +
+		`[fields@32473 query="name=\"a\]b\\c\"" request_id="req-1" user_name="alice"]` +
+		`[http@32473 method="GET" status="200"] slow request` + "\n"
+	if string(data) != want {
+		t.Errorf("unexpected message\n got: %q\nwant: %q", data, want)
+	}
+}
+
+func TestRFC5424Formatter_NilValuesAndOctetCounting(t *testing.T) {
+	formatter := NewRFC5424Formatter(nil, RFC5424Config{AppName: "app", Hostname: "h", OctetCounting: true})
+	data, err := formatter.Format(LogEntry{Level: ErrorLevel, Message: "boom"})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	length, msg, ok := strings.Cut(string(data), " ")
+	if !ok || length != strconv.Itoa(len(msg)) {
+		t.Errorf("expected an octet count prefix, got %q", data)
+	}
+	if want := "<11>1 - h app " + formatter.pid + " - - boom"; msg != want {
+		t.Errorf("got %q, want %q", msg, want)
+	}
+}
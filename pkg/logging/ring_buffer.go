@@ -0,0 +1,285 @@
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// ringRecord is one entry kept by a RingBufferOutput.
+type ringRecord struct {
+	entry LogEntry
+	data  []byte
+}
+
+// ringBuffers holds the open RingBufferOutputs served by DebugHandler.
+var ringBuffers struct {
+	mu      sync.Mutex
+	outputs []*RingBufferOutput
+}
+
+// RingBufferOutput is a flight recorder: it keeps the most recent entries in
+// memory and discards older ones, so verbose logs can be inspected after an
+// incident without being written anywhere during normal operation. Pair it
+// with LevelFilterOutput to keep DEBUG entries only in memory, and serve it
+// with DebugHandler.
+//
+// Example:
+//
+//	recorder := logging.NewRingBufferOutput(5000)
+//	output := logging.NewMultiOutput(
+//		logging.NewLevelFilterOutput(logging.NewWriterOutput(os.Stdout), logging.InfoLevel),
+//		recorder,
+//	)
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithLevel(logging.DebugLevel).WithCustomOutput(output).Build())
+//
+//	http.Handle("/debug/logs", logging.DebugHandler())
+type RingBufferOutput struct {
+	mu      sync.Mutex
+	records []ringRecord
+	next    int
+	full    bool
+	closed  bool
+}
+
+// NewRingBufferOutput creates a RingBufferOutput holding up to size entries.
+// A size of zero or less defaults to 1000.
+func NewRingBufferOutput(size int) *RingBufferOutput {
+	if size <= 0 {
+		size = 1000
+	}
+	rb := &RingBufferOutput{records: make([]ringRecord, size)}
+
+	ringBuffers.mu.Lock()
+	ringBuffers.outputs = append(ringBuffers.outputs, rb)
+	ringBuffers.mu.Unlock()
+	return rb
+}
+
+// Write keeps data as an INFO entry.
+func (rb *RingBufferOutput) Write(data []byte) error {
+	return rb.WriteEntry(LogEntry{Level: InfoLevel, Message: string(bytes.TrimRight(data, "\r\n"))}, data)
+}
+
+// WriteEntry keeps entry and its formatted data, replacing the oldest entry
+// when the buffer is full. Trace, request and correlation IDs from the
+// entry's context are kept as fields; the context itself is not retained.
+func (rb *RingBufferOutput) WriteEntry(entry LogEntry, data []byte) error {
+	fields := copyFields(entry.Fields, 3)
+	contextFieldsFrom(entry.Context).Omit(fields).AddToMap(fields)
+	entry.Fields = fields
+	entry.Context = nil
+
+	record := ringRecord{entry: entry, data: append([]byte(nil), data...)}
+
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if rb.closed {
+		return fmt.Errorf("ring buffer output is closed")
+	}
+	rb.records[rb.next] = record
+	rb.next = (rb.next + 1) % len(rb.records)
+	if rb.next == 0 {
+		rb.full = true
+	}
+	return nil
+}
+
+// snapshot returns the kept records, oldest first.
+func (rb *RingBufferOutput) snapshot() []ringRecord {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if !rb.full {
+		return append([]ringRecord(nil), rb.records[:rb.next]...)
+	}
+	records := make([]ringRecord, 0, len(rb.records))
+	records = append(records, rb.records[rb.next:]...)
+	return append(records, rb.records[:rb.next]...)
+}
+
+// Entries returns the kept entries that match filter, oldest first.
+func (rb *RingBufferOutput) Entries(filter Filter) []LogEntry {
+	var entries []LogEntry
+	for _, record := range rb.snapshot() {
+		if filter.Matches(record.entry) {
+			entries = append(entries, record.entry)
+		}
+	}
+	return entries
+}
+
+// Dump writes the formatted entries that match filter to w, oldest first.
+// A positive limit keeps only the newest limit entries.
+func (rb *RingBufferOutput) Dump(w io.Writer, filter Filter, limit int) error {
+	return dumpRecords(w, rb.snapshot(), filter, limit)
+}
+
+func dumpRecords(w io.Writer, records []ringRecord, filter Filter, limit int) error {
+	matched := records[:0:0]
+	for _, record := range records {
+		if filter.Matches(record.entry) {
+			matched = append(matched, record)
+		}
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	for _, record := range matched {
+		data := record.data
+		if len(data) == 0 || data[len(data)-1] != '\n' {
+			data = append(data[:len(data):len(data)], '\n')
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write ring buffer dump: %w", err)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries currently kept.
+func (rb *RingBufferOutput) Len() int {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	if rb.full {
+		return len(rb.records)
+	}
+	return rb.next
+}
+
+// Reset discards every kept entry.
+func (rb *RingBufferOutput) Reset() {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	clear(rb.records)
+	rb.next = 0
+	rb.full = false
+}
+
+// Handler returns an HTTP handler that dumps this buffer. See DebugHandler
+// for the supported query parameters.
+func (rb *RingBufferOutput) Handler() http.Handler {
+	return ringBufferHandler(func() []*RingBufferOutput { return []*RingBufferOutput{rb} })
+}
+
+// Close discards the kept entries and removes the buffer from DebugHandler.
+func (rb *RingBufferOutput) Close() error {
+	rb.mu.Lock()
+	rb.closed = true
+	rb.records = make([]ringRecord, len(rb.records))
+	rb.next = 0
+	rb.full = false
+	rb.mu.Unlock()
+
+	ringBuffers.mu.Lock()
+	defer ringBuffers.mu.Unlock()
+	for i, output := range ringBuffers.outputs {
+		if output == rb {
+			ringBuffers.outputs = append(ringBuffers.outputs[:i], ringBuffers.outputs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// DebugHandler returns an HTTP handler that dumps the entries of every open
+// RingBufferOutput as formatted lines, oldest first. The level query
+// parameter sets the minimum level and limit keeps only the newest entries
+// of each buffer, e.g. GET /debug/logs?level=warn&limit=100. Mount it on an
+// internal or authenticated listener; the dump includes DEBUG entries.
+func DebugHandler() http.Handler {
+	return ringBufferHandler(func() []*RingBufferOutput {
+		ringBuffers.mu.Lock()
+		defer ringBuffers.mu.Unlock()
+		return append([]*RingBufferOutput(nil), ringBuffers.outputs...)
+	})
+}
+
+func ringBufferHandler(buffers func() []*RingBufferOutput) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var filter Filter
+		if s := r.URL.Query().Get("level"); s != "" {
+			level, ok := ParseLevel(s)
+			if !ok {
+				http.Error(w, fmt.Sprintf("invalid level %q", s), http.StatusBadRequest)
+				return
+			}
+			filter.Level = level
+		}
+		limit := 0
+		if s := r.URL.Query().Get("limit"); s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 {
+				http.Error(w, fmt.Sprintf("invalid limit %q", s), http.StatusBadRequest)
+				return
+			}
+			limit = n
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, rb := range buffers() {
+			if err := rb.Dump(w, filter, limit); err != nil {
+				return
+			}
+		}
+	})
+}
+
+// LevelFilterOutput passes entries at or above a minimum level to another
+// output. Use it to send only some levels to an output while a more verbose
+// logger feeds others, such as a RingBufferOutput. Plain writes, which carry
+// no level, always pass.
+type LevelFilterOutput struct {
+	output Output
+	level  Level
+}
+
+// NewLevelFilterOutput creates a LevelFilterOutput.
+func NewLevelFilterOutput(output Output, level Level) *LevelFilterOutput {
+	return &LevelFilterOutput{output: output, level: level}
+}
+
+// Write passes data to the wrapped output.
+func (lf *LevelFilterOutput) Write(data []byte) error {
+	return lf.output.Write(data)
+}
+
+// WriteEntry passes entry to the wrapped output if it is at or above the
+// minimum level.
+func (lf *LevelFilterOutput) WriteEntry(entry LogEntry, data []byte) error {
+	if entry.Level < lf.level {
+		return nil
+	}
+	return writeEntry(lf.output, entry, data)
+}
+
+// ExplainEntry reports whether entry passes the level filter.
+func (lf *LevelFilterOutput) ExplainEntry(entry LogEntry) []ExplainStep {
+	if entry.Level < lf.level {
+		return []ExplainStep{{
+			Stage:   "LevelFilterOutput",
+			Outcome: ExplainDrop,
+			Detail:  fmt.Sprintf("%s is below the output's minimum level %s", entry.Level, lf.level),
+		}}
+	}
+	steps := []ExplainStep{{
+		Stage:   "LevelFilterOutput",
+		Outcome: ExplainPass,
+		Detail:  fmt.Sprintf("%s is at or above %s", entry.Level, lf.level),
+	}}
+	return append(steps, nestSteps(ExplainOutput(lf.output, entry))...)
+}
+
+// Close closes the wrapped output.
+func (lf *LevelFilterOutput) Close() error {
+	return lf.output.Close()
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRingBufferOutput_KeepsNewest(t *testing.T) {
+	rb := NewRingBufferOutput(3)
+	defer rb.Close()
+
+	for _, line := range []string{"one", "two", "three", "four"} {
+		if err := rb.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("Write returned error: %v", err)
+		}
+	}
+	if rb.Len() != 3 {
+		t.Errorf("expected 3 entries, got %d", rb.Len())
+	}
+
+	var buf bytes.Buffer
+	if err := rb.Dump(&buf, Filter{}, 0); err != nil {
+		t.Fatalf("Dump returned error: %v", err)
+	}
+	if buf.String() != "two\nthree\nfour\n" {
+		t.Errorf("unexpected dump %q", buf.String())
+	}
+
+	buf.Reset()
+	_ = rb.Dump(&buf, Filter{}, 1)
+	if buf.String() != "four\n" {
+		t.Errorf("expected only the newest entry, got %q", buf.String())
+	}
+
+	rb.Reset()
+	if rb.Len() != 0 {
+		t.Errorf("expected Reset to empty the buffer, got %d", rb.Len())
+	}
+}
+
+func TestRingBufferOutput_WithLevelFilter(t *testing.T) {
+	rb := NewRingBufferOutput(10)
+	defer rb.Close()
+	live := &bytes.Buffer{}
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(DebugLevel).
+		WithJSONFormat().
+		WithCustomOutput(NewMultiOutput(NewLevelFilterOutput(NewWriterOutput(live), InfoLevel), rb)).
+		Build())
+
+	ctx := WithTraceID(context.Background(), "trace-ring")
+	logger.DebugContext(ctx, "cache miss")
+	logger.Info("request served")
+
+	if strings.Contains(live.String(), "cache miss") || !strings.Contains(live.String(), "request served") {
+		t.Errorf("expected only INFO in the live output, got: %s", live.String())
+	}
+
+	entries := rb.Entries(Filter{})
+	if len(entries) != 2 {
+		t.Fatalf("expected both entries in the ring buffer, got %d", len(entries))
+	}
+	if entries[0].Level != DebugLevel || entries[0].Fields["trace_id"] != "trace-ring" || entries[0].Context != nil {
+		t.Errorf("unexpected debug entry: %+v", entries[0])
+	}
+	if got := rb.Entries(Filter{Level: InfoLevel}); len(got) != 1 || got[0].Message != "request served" {
+		t.Errorf("unexpected filtered entries: %+v", got)
+	}
+}
+
+func TestLevelFilterOutput_Explain(t *testing.T) {
+	output := NewLevelFilterOutput(NewWriterOutput(&bytes.Buffer{}), WarnLevel)
+
+	steps := ExplainOutput(output, LogEntry{Level: InfoLevel})
+	if len(steps) != 1 || steps[0].Outcome != ExplainDrop {
+		t.Errorf("expected INFO to be dropped, got %+v", steps)
+	}
+	steps = ExplainOutput(output, LogEntry{Level: ErrorLevel})
+	if len(steps) != 2 || steps[0].Outcome != ExplainPass || steps[1].Depth != 1 {
+		t.Errorf("expected ERROR to pass to the nested output, got %+v", steps)
+	}
+}
+
+func TestDebugHandler(t *testing.T) {
+	rb := NewRingBufferOutput(10)
+	_ = rb.WriteEntry(LogEntry{Level: DebugLevel, Message: "debug"}, []byte("debug line\n"))
+	_ = rb.WriteEntry(LogEntry{Level: ErrorLevel, Message: "error"}, []byte("error line\n"))
+
+	handler := DebugHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "debug line\nerror line\n") {
+		t.Errorf("unexpected response %d: %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs?level=error", nil))
+	if strings.Contains(rec.Body.String(), "debug line") || !strings.Contains(rec.Body.String(), "error line") {
+		t.Errorf("expected only the error entry, got %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs?limit=x", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid limit, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/logs", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, got %d", rec.Code)
+	}
+
+	_ = rb.Close()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs", nil))
+	if strings.Contains(rec.Body.String(), "error line") {
+		t.Errorf("expected a closed buffer to be removed, got %q", rec.Body.String())
+	}
+	if err := rb.Write([]byte("late")); err == nil {
+		t.Error("expected an error writing after Close")
+	}
+}
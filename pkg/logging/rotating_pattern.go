@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fileNamePattern expands RotatingFileConfig.Pattern. It supports the time
+// verbs %Y, %m, %d, %H, %M and %S, %s for the whole rotation time
+// (2006-01-02-15-04-05), %% for a literal percent sign, and the {hostname}
+// and {pid} tokens. Anything else is copied as is.
+type fileNamePattern struct {
+	pattern string
+	tokens  map[string]string
+}
+
+// patternVerbs maps time verbs to their time.Format layouts.
+var patternVerbs = map[byte]string{
+	'Y': "2006",
+	'm': "01",
+	'd': "02",
+	'H': "15",
+	'M': "04",
+	'S': "05",
+	's': rotatingTimeFormat,
+}
+
+func newFileNamePattern(pattern string) fileNamePattern {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+	return fileNamePattern{
+		pattern: pattern,
+		tokens: map[string]string{
+			"{hostname}": hostname,
+			"{pid}":      strconv.Itoa(os.Getpid()),
+		},
+	}
+}
+
+// walk splits s into literal text, time verbs and token values.
+func (p fileNamePattern) walk(s string, literal func(string), verb func(byte)) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '%':
+			if i+1 < len(s) {
+				if _, ok := patternVerbs[s[i+1]]; ok {
+					verb(s[i+1])
+					i++
+					continue
+				}
+				if s[i+1] == '%' {
+					literal("%")
+					i++
+					continue
+				}
+			}
+		case '{':
+			if end := strings.IndexByte(s[i:], '}'); end > 0 {
+				if value, ok := p.tokens[s[i:i+end+1]]; ok {
+					literal(value)
+					i += end
+					continue
+				}
+			}
+		}
+		literal(s[i : i+1])
+	}
+}
+
+// expand returns the file name for t.
+func (p fileNamePattern) expand(t time.Time) string {
+	var b strings.Builder
+	p.walk(p.pattern,
+		func(s string) { b.WriteString(s) },
+		func(v byte) { b.WriteString(t.Format(patternVerbs[v])) })
+	return b.String()
+}
+
+// fileNameMatcher recognizes the names a pattern's last element expands to,
+// and the archive names rotated files are renamed to, compressed or not.
+type fileNameMatcher struct {
+	re    *regexp.Regexp
+	verbs []byte
+}
+
+// matcher returns a fileNameMatcher for the last element of the pattern.
+func (p fileNamePattern) matcher() fileNameMatcher {
+	var b strings.Builder
+	var verbs []byte
+	b.WriteString("^")
+	p.walk(lastPathElement(p.pattern),
+		func(s string) { b.WriteString(regexp.QuoteMeta(s)) },
+		func(v byte) {
+			verbs = append(verbs, v)
+			switch v {
+			case 'Y':
+				b.WriteString(`(\d{4})`)
+			case 's':
+				b.WriteString(`(\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2})`)
+			default:
+				b.WriteString(`(\d{2})`)
+			}
+		})
+	b.WriteString(`(?:\.(\d{4}-\d{2}-\d{2}-\d{2}-\d{2}-\d{2})(?:\.(\d+))?)?(?:\.gz)?$`)
+	return fileNameMatcher{re: regexp.MustCompile(b.String()), verbs: verbs}
+}
+
+// key returns a sort key ordering name by the time in it, and whether name
+// matches the pattern.
+func (m fileNameMatcher) key(name string) (string, bool) {
+	match := m.re.FindStringSubmatch(name)
+	if match == nil {
+		return "", false
+	}
+
+	parts := make(map[byte]string, 6)
+	for i, v := range m.verbs {
+		value := match[i+1]
+		if v == 's' {
+			fields := strings.Split(value, "-")
+			for j, f := range []byte("YmdHMS") {
+				parts[f] = fields[j]
+			}
+			continue
+		}
+		if _, seen := parts[v]; !seen {
+			parts[v] = value
+		}
+	}
+	// An archive sorts by the time it was rotated out.
+	archived, seq := match[len(m.verbs)+1], match[len(m.verbs)+2]
+	if archived != "" {
+		fields := strings.Split(archived, "-")
+		for j, f := range []byte("YmdHMS") {
+			parts[f] = fields[j]
+		}
+	}
+
+	var key strings.Builder
+	for _, v := range []byte("YmdHMS") {
+		value, ok := parts[v]
+		if !ok {
+			value = "00"
+			if v == 'Y' {
+				value = "0000"
+			}
+		}
+		key.WriteString(value)
+	}
+	n, _ := strconv.Atoi(seq)
+	fmt.Fprintf(&key, "%09d", n)
+	return key.String(), true
+}
+
+// lastPathElement returns the part of a pattern after its last separator.
+func lastPathElement(pattern string) string {
+	if i := strings.LastIndexAny(pattern, `/`+string(os.PathSeparator)); i >= 0 {
+		return pattern[i+1:]
+	}
+	return pattern
+}
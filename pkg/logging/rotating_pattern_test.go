@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFileNamePattern_Expand(t *testing.T) {
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "localhost"
+	}
+	at := time.Date(2024, 5, 1, 13, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"app-%Y-%m-%d.log", "app-2024-05-01.log"},
+		{"app-%Y%m%dT%H%M%S.log", "app-20240501T130405.log"},
+		{"app-%s.log", "app-2024-05-01-13-04-05.log"},
+		{"logs/%Y/app-{hostname}-{pid}.log", "logs/2024/app-" + hostname + "-" + strconv.Itoa(os.Getpid()) + ".log"},
+		{"100%%-{unknown}-%q.log", "100%-{unknown}-%q.log"},
+		{"trailing%", "trailing%"},
+	}
+	for _, tt := range tests {
+		if got := newFileNamePattern(tt.pattern).expand(at); got != tt.want {
+			t.Errorf("expand(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestFileNamePattern_Matcher(t *testing.T) {
+	matcher := newFileNamePattern("/var/log/app-%d-%m-%Y.log").matcher()
+
+	older, ok := matcher.key("app-31-12-2023.log.gz")
+	if !ok {
+		t.Fatal("expected a compressed file to match")
+	}
+	newer, ok := matcher.key("app-01-01-2024.log")
+	if !ok {
+		t.Fatal("expected a file to match")
+	}
+	if older >= newer {
+		t.Errorf("expected keys to order by time, got %q and %q", older, newer)
+	}
+
+	for _, name := range []string{"app-1-1-2024.log", "app-01-01-2024.log.bak", "other-01-01-2024.log"} {
+		if _, ok := matcher.key(name); ok {
+			t.Errorf("expected %q not to match", name)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !js
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+// rotationSignals are the signals HandleRotationSignals listens for.
+var rotationSignals = []os.Signal{syscall.SIGHUP}
+
+// shutdownSignals are the signals RegisterShutdown flushes logs on.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
@@ -0,0 +1,11 @@
+//go:build js
+
+package logging
+
+import "os"
+
+// rotationSignals is empty; there are no signals under js/wasm.
+var rotationSignals []os.Signal
+
+// shutdownSignals is empty for the same reason.
+var shutdownSignals []os.Signal
@@ -0,0 +1,186 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+)
+
+// EntryFilter reports whether a route accepts an entry.
+type EntryFilter func(entry LogEntry) bool
+
+// FieldFilter returns an EntryFilter accepting entries whose field name,
+// or trace, request or correlation ID of that name in their context, is
+// one of values.
+func FieldFilter(name string, values ...string) EntryFilter {
+	return func(entry LogEntry) bool {
+		value, ok := entryFieldString(entry, name)
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Route is one destination of a RoutingOutput.
+type Route struct {
+	// Name identifies the route in Explain. Defaults to the output's type.
+	Name string
+	// Output receives the entries the route accepts.
+	Output Output
+	// MinLevel is the lowest level the route accepts.
+	MinLevel Level
+	// Formatter formats entries for this route. Without one, the route
+	// writes the logger's own formatting.
+	Formatter Formatter
+	// Filter, if set, must accept an entry for the route to write it.
+	Filter EntryFilter
+}
+
+// name returns the route's name for Explain.
+func (r Route) name() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%T", r.Output)
+}
+
+// RoutingOutput writes each entry to every route whose level and filter
+// accept it, formatted by that route's Formatter. One logger can then feed
+// several destinations with different levels and formats, without
+// building a logger per destination. The logger's own level still applies
+// first, so set it to the lowest MinLevel of the routes.
+//
+// Example:
+//
+//	errorsFile, err := logging.NewFileOutput("/var/log/myapp/errors.log")
+//	if err != nil {
+//		return err
+//	}
+//	output := logging.NewRoutingOutput(
+//		logging.Route{Output: logging.NewWriterOutput(os.Stdout), MinLevel: logging.InfoLevel,
+//			Formatter: logging.NewJSONFormatter(nil)},
+//		logging.Route{Output: errorsFile, MinLevel: logging.ErrorLevel,
+//			Formatter: logging.NewTextFormatter(nil)},
+//		logging.Route{Name: "audit", Output: auditOutput,
+//			Filter: logging.FieldFilter("component", "auth", "billing")},
+//	)
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithCustomOutput(output).Build())
+type RoutingOutput struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// NewRoutingOutput creates a RoutingOutput with routes. Routes without an
+// Output are reported and ignored.
+func NewRoutingOutput(routes ...Route) *RoutingOutput {
+	ro := &RoutingOutput{}
+	for _, route := range routes {
+		ro.AddRoute(route)
+	}
+	return ro
+}
+
+// AddRoute adds a route.
+func (ro *RoutingOutput) AddRoute(route Route) {
+	if route.Output == nil {
+		reportInvalidConfig("RoutingOutput", "ignoring route without an output", nil)
+		return
+	}
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.routes = append(ro.routes, route)
+}
+
+// Write writes data, which carries no level or fields, to every route.
+func (ro *RoutingOutput) Write(data []byte) error {
+	ro.mu.RLock()
+	defer ro.mu.RUnlock()
+
+	var firstErr error
+	for _, route := range ro.routes {
+		if err := route.Output.Write(data); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// WriteEntry writes entry to every route that accepts it.
+func (ro *RoutingOutput) WriteEntry(entry LogEntry, data []byte) error {
+	ro.mu.RLock()
+	defer ro.mu.RUnlock()
+
+	var firstErr error
+	for _, route := range ro.routes {
+		if !route.accepts(entry) {
+			continue
+		}
+		if err := route.write(entry, data); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// accepts reports whether the route's level and filter accept entry.
+func (r Route) accepts(entry LogEntry) bool {
+	return entry.Level >= r.MinLevel && (r.Filter == nil || r.Filter(entry))
+}
+
+// write formats entry with the route's Formatter, if any, and writes it.
+func (r Route) write(entry LogEntry, data []byte) error {
+	if r.Formatter != nil {
+		formatted, err := r.Formatter.Format(entry)
+		if err != nil {
+			return fmt.Errorf("failed to format entry for route %s: %w", r.name(), err)
+		}
+		data = formatted
+	}
+	return writeEntry(r.Output, entry, data)
+}
+
+// ExplainEntry reports which routes accept entry.
+func (ro *RoutingOutput) ExplainEntry(entry LogEntry) []ExplainStep {
+	ro.mu.RLock()
+	defer ro.mu.RUnlock()
+
+	steps := []ExplainStep{{Stage: "RoutingOutput", Outcome: ExplainRoute, Detail: fmt.Sprintf("to %d routes", len(ro.routes))}}
+	for _, route := range ro.routes {
+		step := ExplainStep{Stage: "route " + route.name(), Outcome: ExplainPass, Depth: 1}
+		switch {
+		case entry.Level < route.MinLevel:
+			step.Outcome = ExplainDrop
+			step.Detail = fmt.Sprintf("%s is below the route's minimum level %s", entry.Level, route.MinLevel)
+		case route.Filter != nil && !route.Filter(entry):
+			step.Outcome = ExplainDrop
+			step.Detail = "rejected by the route's filter"
+		case route.Formatter != nil:
+			step.Detail = fmt.Sprintf("formatted by %T", route.Formatter)
+		}
+		steps = append(steps, step)
+		if step.Outcome == ExplainPass {
+			steps = append(steps, nestSteps(nestSteps(ExplainOutput(route.Output, entry)))...)
+		}
+	}
+	return steps
+}
+
+// Close closes every route's output.
+func (ro *RoutingOutput) Close() error {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+
+	var firstErr error
+	for _, route := range ro.routes {
+		if err := route.Output.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
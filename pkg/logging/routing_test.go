@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRoutingOutput(t *testing.T) {
+	stdout, errorsFile, audit := &bytes.Buffer{}, &bytes.Buffer{}, &bytes.Buffer{}
+	output := NewRoutingOutput(
+		Route{Output: NewWriterOutput(stdout), MinLevel: InfoLevel, Formatter: NewJSONFormatter(nil)},
+		Route{Output: NewWriterOutput(errorsFile), MinLevel: ErrorLevel, Formatter: NewTextFormatter(NewFormatterConfig().WithTextFormat().IncludeFile(false).Build())},
+		Route{Name: "audit", Output: NewWriterOutput(audit), Filter: FieldFilter("component", "auth", "billing")},
+	)
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithLevel(DebugLevel).WithCustomOutput(output).Build())
+
+	logger.Debug("cache miss")
+	logger.Info("request served")
+	logger.WithField("component", "auth").Error("login failed")
+
+	if strings.Count(stdout.String(), "\n") != 2 || !strings.Contains(stdout.String(), `"message":"request served"`) {
+		t.Errorf("expected INFO and above as JSON, got:\n%s", stdout.String())
+	}
+	if got := errorsFile.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, "login failed") || strings.HasPrefix(got, "{") {
+		t.Errorf("expected only the error as text, got:\n%s", got)
+	}
+	if got := audit.String(); strings.Count(got, "\n") != 1 || !strings.Contains(got, "login failed") {
+		t.Errorf("expected only the auth entry, got:\n%s", got)
+	}
+
+	if err := output.Write([]byte("raw\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !strings.HasSuffix(audit.String(), "raw\n") || !strings.HasSuffix(errorsFile.String(), "raw\n") {
+		t.Error("expected plain writes to reach every route")
+	}
+}
+
+func TestRoutingOutput_Errors(t *testing.T) {
+	diagnostics := captureDiagnostics(t)
+	good := &bytes.Buffer{}
+	output := NewRoutingOutput(
+		Route{},
+		Route{Output: &failingOutput{failing: true}},
+		Route{Output: NewWriterOutput(good)},
+	)
+	if got := diagnostics(); len(got) != 1 || got[0].Source != "RoutingOutput" {
+		t.Errorf("expected a route without an output to be reported, got %v", got)
+	}
+
+	if err := output.WriteEntry(LogEntry{Level: InfoLevel}, []byte("entry\n")); err == nil {
+		t.Error("expected the failing route's error")
+	}
+	if good.String() != "entry\n" {
+		t.Errorf("expected other routes to be written, got %q", good.String())
+	}
+
+	formatErr := errors.New("bad entry")
+	output = NewRoutingOutput(Route{Name: "broken", Output: NewWriterOutput(good), Formatter: formatterFunc(func(LogEntry) ([]byte, error) {
+		return nil, formatErr
+	})})
+	if err := output.WriteEntry(LogEntry{}, nil); !errors.Is(err, formatErr) || !strings.Contains(err.Error(), "route broken") {
+		t.Errorf("expected the formatter error, got %v", err)
+	}
+}
+
+// formatterFunc adapts a function to Formatter.
+type formatterFunc func(LogEntry) ([]byte, error)
+
+func (f formatterFunc) Format(entry LogEntry) ([]byte, error) { return f(entry) }
+
+func TestRoutingOutput_Explain(t *testing.T) {
+	output := NewRoutingOutput(
+		Route{Output: NewWriterOutput(&bytes.Buffer{}), MinLevel: InfoLevel, Formatter: NewJSONFormatter(nil)},
+		Route{Name: "errors", Output: NewWriterOutput(&bytes.Buffer{}), MinLevel: ErrorLevel},
+		Route{Name: "audit", Output: NewWriterOutput(&bytes.Buffer{}), Filter: FieldFilter("component", "auth")},
+	)
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(output).Build())
+
+	e := ExplainLogger(logger, context.Background(), WarnLevel, map[string]interface{}{"component": "cache"})
+	var got []string
+	for _, step := range e.Steps {
+		got = append(got, strings.Repeat(">", step.Depth)+step.Stage+":"+string(step.Outcome))
+	}
+	want := []string{
+		"level:pass",
+		"RoutingOutput:route",
+		">route *logging.WriterOutput:pass",
+		">>*logging.WriterOutput:pass",
+		">route errors:drop",
+		">route audit:drop",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("unexpected steps %v, want %v", got, want)
+	}
+	if !strings.Contains(e.String(), "formatted by *logging.JSONFormatter") {
+		t.Errorf("expected the route's formatter in:\n%s", e)
+	}
+}
@@ -0,0 +1,83 @@
+package logging
+
+// SchemaVersionField is the field that carries CoreConfig.SchemaVersion on
+// every entry.
+const SchemaVersionField = "log_schema_version"
+
+// FieldMigration renames a field. Set KeepOld during a migration window so
+// entries carry both names while dashboards and alerts move to the new one,
+// then clear it to emit only New.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithSchemaVersion("2").
+//		WithFieldMigration(logging.FieldMigration{Old: "userId", New: "user_id", KeepOld: true}).
+//		Build()
+type FieldMigration struct {
+	Old     string
+	New     string
+	KeepOld bool
+}
+
+// ApplyFieldMigrations returns fields with each migration applied: a value
+// under either name is emitted under New, and also under Old when KeepOld
+// is set. When both names are present, New wins. fields is not modified; it
+// is returned as-is when no migration applies.
+func ApplyFieldMigrations(fields map[string]interface{}, migrations []FieldMigration) map[string]interface{} {
+	var migrated map[string]interface{}
+	for _, m := range migrations {
+		value, ok := fields[m.New]
+		if !ok {
+			value, ok = fields[m.Old]
+		}
+		if !ok {
+			continue
+		}
+		if migrated == nil {
+			migrated = copyFields(fields, 1)
+		}
+		migrated[m.New] = value
+		if m.KeepOld {
+			migrated[m.Old] = value
+		} else {
+			delete(migrated, m.Old)
+		}
+	}
+	if migrated == nil {
+		return fields
+	}
+	return migrated
+}
+
+// hasSchema reports whether entries need a schema version or migrations.
+func (c *CoreConfig) hasSchema() bool {
+	return c.SchemaVersion != "" || len(c.FieldMigrations) > 0
+}
+
+// rewritesFields reports whether entries' fields are transformed before
+// formatting, by the schema or field encryption.
+func (c *CoreConfig) rewritesFields() bool {
+	return c.hasSchema() || c.FieldEncryptor != nil
+}
+
+// applySchema applies the configured migrations to fields and adds the
+// schema version. fields is treated as read-only.
+func (c *CoreConfig) applySchema(fields map[string]interface{}) map[string]interface{} {
+	fields = ApplyFieldMigrations(fields, c.FieldMigrations)
+	if c.SchemaVersion == "" {
+		return fields
+	}
+	versioned := copyFields(fields, 1)
+	versioned[SchemaVersionField] = c.SchemaVersion
+	return versioned
+}
+
+// copyFields returns a copy of fields with room for extra more entries.
+func copyFields(fields map[string]interface{}, extra int) map[string]interface{} {
+	c := make(map[string]interface{}, len(fields)+extra)
+	for k, v := range fields {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,490 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OutputContractVersion is the version of the machine-readable output
+// contract described by OutputSchema. It changes only when the meaning of
+// existing descriptor properties changes, so consumers can refuse
+// descriptors they don't understand.
+const OutputContractVersion = "1"
+
+// Field types used in OutputSchema, named after their JSON Schema
+// equivalents. SchemaTypeAny matches every value.
+const (
+	SchemaTypeString  = "string"
+	SchemaTypeNumber  = "number"
+	SchemaTypeBoolean = "boolean"
+	SchemaTypeObject  = "object"
+	SchemaTypeArray   = "array"
+	SchemaTypeNull    = "null"
+	SchemaTypeAny     = "any"
+)
+
+// SchemaField describes one key of a structured entry.
+type SchemaField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Format refines Type, e.g. "date-time" for timestamps or "encrypted"
+	// for values produced by FieldEncryptor.
+	Format string `json:"format,omitempty"`
+	// Enum lists the allowed values, e.g. the level names.
+	Enum []string `json:"enum,omitempty"`
+	// Required fields appear on every entry.
+	Required bool `json:"required"`
+	// Deprecated fields are old names kept during a FieldMigration window.
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// OutputSchema describes the entries a logger currently produces: the keys,
+// their types and which are always present. It is derived from the running
+// configuration, including static fields, fields added with WithFields,
+// the schema version, field migrations and encryption. Publish it, e.g. on
+// an internal endpoint, so consumers can generate parsers against it.
+type OutputSchema struct {
+	ContractVersion string `json:"contract_version"`
+	// SchemaVersion is CoreConfig.SchemaVersion, if set.
+	SchemaVersion string `json:"schema_version,omitempty"`
+	// Format is "json", "text", "common", "combined", "gelf", "pretty",
+	// "stackdriver", "slog-json", "slog-text", "elasticsearch", "datadog" or
+	// "custom". Fields describe the keys of structured formats; a custom
+	// formatter or slog handler only guarantees the configured fields.
+	Format string        `json:"format"`
+	Fields []SchemaField `json:"fields"`
+	// AdditionalFields reports whether entries may carry keys not listed
+	// in Fields, such as fields passed at the call site.
+	AdditionalFields bool `json:"additional_fields"`
+}
+
+// Field returns the field called name.
+func (s OutputSchema) Field(name string) (SchemaField, bool) {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return SchemaField{}, false
+}
+
+// schemaDescriber is implemented by loggers that can describe their output.
+type schemaDescriber interface {
+	describeSchema() OutputSchema
+}
+
+// SchemaDescriptor describes the output of the default logger.
+//
+// Example:
+//
+//	http.HandleFunc("/debug/log-schema", func(w http.ResponseWriter, r *http.Request) {
+//		_ = json.NewEncoder(w).Encode(logging.SchemaDescriptor())
+//	})
+func SchemaDescriptor() OutputSchema {
+	return SchemaDescriptorLogger(GetDefaultLogger())
+}
+
+// SchemaDescriptorLogger is SchemaDescriptor for a specific logger. Loggers
+// from other packages are described by the JSON formatter's core fields.
+func SchemaDescriptorLogger(logger Logger) OutputSchema {
+	if describer, ok := logger.(schemaDescriber); ok {
+		return describer.describeSchema()
+	}
+	return OutputSchema{
+		ContractVersion:  OutputContractVersion,
+		Format:           jsonFormatString,
+		Fields:           coreSchemaFields("timestamp", "level", "message", schemaLevelNames(Level.String), true),
+		AdditionalFields: true,
+	}
+}
+
+func (ul *unifiedLogger) describeSchema() OutputSchema {
+	config := ul.config
+	schema := OutputSchema{
+		ContractVersion:  OutputContractVersion,
+		SchemaVersion:    config.Core.SchemaVersion,
+		AdditionalFields: true,
+	}
+
+	switch {
+	case config.UseSlog && config.Handler != nil:
+		schema.Format = "custom"
+	case config.UseSlog:
+		schema.Format = "slog-text"
+		keys := FieldKeys{}.orDefaults(slog.TimeKey, slog.LevelKey, slog.MessageKey)
+		if config.Formatter.Format == JSONFormat {
+			schema.Format = "slog-json"
+			keys = config.Formatter.Keys.orDefaults(slog.TimeKey, slog.LevelKey, slog.MessageKey)
+		}
+		schema.Fields = coreSchemaFields(keys.Timestamp, keys.Level, keys.Message, schemaLevelNames(func(l Level) string {
+			return ul.levelToSlog(l).String()
+		}), true)
+		describeTimeFormat(schema.Fields, config.Formatter)
+	case isOutput[*ElasticsearchOutput](config.Output.Custom):
+		// Elasticsearch and Datadog documents are built from the entry,
+		// whatever the formatter.
+		schema.Format = "elasticsearch"
+		schema.Fields = coreSchemaFields("@timestamp", "level", "message", schemaLevelNames(Level.String), true)
+	case isOutput[*DatadogOutput](config.Output.Custom):
+		schema.Format = "datadog"
+		schema.Fields = datadogSchemaFields(config.Output.Custom.(*DatadogOutput))
+	case isOutput[*GELFOutput](config.Output.Custom):
+		schema.Format = gelfFormatString
+		schema.Fields = gelfSchemaFields(config.Output.Custom.(*GELFOutput).formatter.config)
+	case config.Formatter.Custom != nil, config.Formatter.Format == TemplateFormat, config.Formatter.Format == RegisteredFormat:
+		schema.Format = "custom"
+	case config.Formatter.Format == GELFFormat:
+		schema.Format = gelfFormatString
+		schema.Fields = gelfSchemaFields(config.Formatter)
+	case config.Formatter.Format == StackdriverFormat:
+		schema.Format = stackdriverFormatString
+		schema.Fields = stackdriverSchemaFields(config.Formatter)
+	default:
+		switch config.Formatter.Format {
+		case JSONFormat:
+			schema.Format = jsonFormatString
+		case CommonLogFormat:
+			schema.Format = "common"
+		case CombinedLogFormat:
+			schema.Format = "combined"
+		case PrettyFormat:
+			schema.Format = prettyFormatString
+		default:
+			schema.Format = textFormatString
+		}
+		keys := FieldKeys{}.jsonKeys()
+		if config.Formatter.Format == JSONFormat {
+			keys = config.Formatter.Keys.jsonKeys()
+		}
+		schema.Fields = coreSchemaFields(keys.Timestamp, keys.Level, keys.Message, schemaLevelNames(config.Formatter.levelName), config.Formatter.IncludeTime)
+		if config.Formatter.IncludeTime {
+			describeTimeFormat(schema.Fields, config.Formatter)
+		}
+		if config.Formatter.IncludeFile {
+			schema.Fields = append(schema.Fields, SchemaField{
+				Name: "file", Type: SchemaTypeString, Description: "source file and line of the call site",
+			})
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range schema.Fields {
+		seen[f.Name] = true
+	}
+	add := func(f SchemaField) {
+		if schema.Format == gelfFormatString {
+			// GELF sends fields as additional fields, with strings or
+			// numbers as values.
+			f.Name = gelfFieldName(f.Name)
+			if f.Type != SchemaTypeNumber && f.Type != SchemaTypeString {
+				f.Type = SchemaTypeString
+			}
+		}
+		if !seen[f.Name] {
+			seen[f.Name] = true
+			schema.Fields = append(schema.Fields, f)
+		}
+	}
+
+	// Fields on every entry: static fields, WithFields and the schema
+	// version, after migration and encryption.
+	ul.mu.RLock()
+	fixed := ul.buildCommonLogFields()
+	ul.mu.RUnlock()
+	names := make([]string, 0, len(fixed))
+	for name := range fixed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := SchemaField{Name: name, Type: schemaType(fixed[name]), Required: true}
+		if name == SchemaVersionField {
+			f.Enum = []string{config.Core.SchemaVersion}
+			f.Description = "output schema version"
+		}
+		if s, ok := fixed[name].(string); ok && IsEncryptedValue(s) {
+			f.Format = "encrypted"
+		}
+		add(f)
+	}
+
+	for _, m := range config.Core.FieldMigrations {
+		add(SchemaField{Name: m.New, Type: SchemaTypeAny, Description: "renamed from " + m.Old})
+		if m.KeepOld {
+			add(SchemaField{Name: m.Old, Type: SchemaTypeAny, Deprecated: true, Description: "old name of " + m.New})
+		}
+	}
+	if fe := config.Core.FieldEncryptor; fe != nil {
+		encrypted := make([]string, 0, len(fe.fields))
+		for name := range fe.fields {
+			encrypted = append(encrypted, name)
+		}
+		sort.Strings(encrypted)
+		for _, name := range encrypted {
+			add(SchemaField{Name: name, Type: SchemaTypeString, Format: "encrypted"})
+		}
+	}
+
+	contextIDs := []string{"trace_id", "request_id", "correlation_id"}
+	if schema.Format == "datadog" {
+		contextIDs = []string{"dd.trace_id", "dd.span_id", "request_id", "correlation_id"}
+	}
+	for _, name := range contextIDs {
+		add(SchemaField{Name: name, Type: SchemaTypeString, Description: "from the entry's context"})
+	}
+	return schema
+}
+
+// isOutput reports whether output is a T.
+func isOutput[T Output](output Output) bool {
+	_, ok := output.(T)
+	return ok
+}
+
+// datadogSchemaFields describes the reserved attributes of DatadogOutput
+// logs.
+func datadogSchemaFields(do *DatadogOutput) []SchemaField {
+	var statuses []string
+	for _, status := range schemaLevelNames(datadogStatus) {
+		if !containsString(statuses, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	fields := []SchemaField{
+		{Name: "timestamp", Type: SchemaTypeNumber, Required: true, Description: "Unix time in milliseconds"},
+		{Name: "status", Type: SchemaTypeString, Enum: statuses, Required: true},
+		{Name: "message", Type: SchemaTypeString, Required: true},
+		{Name: "ddsource", Type: SchemaTypeString, Required: true},
+		{Name: "hostname", Type: SchemaTypeString, Required: true},
+		{Name: "service", Type: SchemaTypeString, Required: do.config.Service != ""},
+	}
+	if do.tags != "" {
+		fields = append(fields, SchemaField{Name: "ddtags", Type: SchemaTypeString, Required: true})
+	}
+	return fields
+}
+
+// gelfSchemaFields describes the GELF 1.1 keys written by GELFFormatter.
+func gelfSchemaFields(config *FormatterConfig) []SchemaField {
+	fields := []SchemaField{
+		{Name: "version", Type: SchemaTypeString, Enum: []string{"1.1"}, Required: true},
+		{Name: "host", Type: SchemaTypeString, Required: true},
+		{Name: "short_message", Type: SchemaTypeString, Required: true},
+		{Name: "full_message", Type: SchemaTypeString, Description: "the whole message, when it has several lines"},
+		{Name: "level", Type: SchemaTypeNumber, Required: true, Description: "syslog severity"},
+		{Name: "timestamp", Type: SchemaTypeNumber, Description: "Unix time in seconds, with milliseconds"},
+	}
+	if config.IncludeFile {
+		fields = append(fields,
+			SchemaField{Name: "_file", Type: SchemaTypeString, Description: "source file of the call site"},
+			SchemaField{Name: "_line", Type: SchemaTypeNumber, Description: "source line of the call site"},
+		)
+	}
+	return fields
+}
+
+// coreSchemaFields describes the timestamp, level and message keys.
+func coreSchemaFields(timeKey, levelKey, messageKey string, levels []string, includeTime bool) []SchemaField {
+	var fields []SchemaField
+	if includeTime {
+		fields = append(fields, SchemaField{Name: timeKey, Type: SchemaTypeString, Format: "date-time", Required: true})
+	}
+	return append(fields,
+		SchemaField{Name: levelKey, Type: SchemaTypeString, Enum: levels, Required: true},
+		SchemaField{Name: messageKey, Type: SchemaTypeString, Required: true},
+	)
+}
+
+// describeTimeFormat replaces the date-time format of the timestamp, the
+// first of fields, with a description of a custom TimeFormat layout.
+func describeTimeFormat(fields []SchemaField, config *FormatterConfig) {
+	if config.TimeFormat == "" || config.TimeFormat == time.RFC3339 {
+		return
+	}
+	fields[0].Format = ""
+	fields[0].Description = "time in the Go layout " + config.TimeFormat
+}
+
+// schemaLevelNames returns the name of every level, as rendered by name.
+func schemaLevelNames(name func(Level) string) []string {
+	var names []string
+	for l := TraceLevel; l <= CriticalLevel; l++ {
+		names = append(names, name(l))
+	}
+	return names
+}
+
+// schemaType returns the JSON type v is encoded as.
+func schemaType(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil || len(data) == 0 {
+		return SchemaTypeAny
+	}
+	switch data[0] {
+	case '"':
+		return SchemaTypeString
+	case '{':
+		return SchemaTypeObject
+	case '[':
+		return SchemaTypeArray
+	case 't', 'f':
+		return SchemaTypeBoolean
+	case 'n':
+		return SchemaTypeNull
+	default:
+		return SchemaTypeNumber
+	}
+}
+
+// JSONSchemaDraft is the JSON Schema dialect of the documents returned by
+// JSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns the schema as a JSON Schema document describing one
+// entry, for downstream teams to pin parsers and validators to. Formats
+// without keys, such as "text" and "common", produce a document that only
+// requires an object.
+func (s OutputSchema) JSONSchema() map[string]interface{} {
+	properties := make(map[string]interface{}, len(s.Fields))
+	required := []string{}
+	for _, f := range s.Fields {
+		property := map[string]interface{}{}
+		if f.Type != SchemaTypeAny {
+			property["type"] = f.Type
+		}
+		switch f.Format {
+		case "date-time":
+			property["format"] = "date-time"
+		case "encrypted":
+			property["pattern"] = "^" + regexp.QuoteMeta(encryptedPrefix)
+		}
+		if len(f.Enum) > 0 {
+			property["enum"] = f.Enum
+		}
+		if f.Deprecated {
+			property["deprecated"] = true
+		}
+		if f.Description != "" {
+			property["description"] = f.Description
+		}
+		properties[f.Name] = property
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+
+	title := "go-logging " + s.Format + " entry"
+	if s.SchemaVersion != "" {
+		title += ", schema version " + s.SchemaVersion
+	}
+	return map[string]interface{}{
+		"$schema":              JSONSchemaDraft,
+		"$comment":             "go-logging output contract version " + s.ContractVersion,
+		"title":                title,
+		"type":                 SchemaTypeObject,
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": s.AdditionalFields,
+	}
+}
+
+// JSONSchema returns a JSON Schema document describing the entries of the
+// default logger; see OutputSchema.JSONSchema.
+//
+// Example:
+//
+//	data, _ := json.MarshalIndent(logging.JSONSchema(), "", "  ")
+//	_ = os.WriteFile("log-entry.schema.json", data, 0o644)
+func JSONSchema() map[string]interface{} {
+	return SchemaDescriptor().JSONSchema()
+}
+
+// JSONSchemaLogger is JSONSchema for a specific logger.
+func JSONSchemaLogger(logger Logger) map[string]interface{} {
+	return SchemaDescriptorLogger(logger).JSONSchema()
+}
+
+// Validate checks a JSON entry against the schema: required fields must be
+// present, known fields must have the described type, format and allowed
+// values, and unknown fields are rejected unless AdditionalFields is set.
+// All problems are reported together.
+func (s OutputSchema) Validate(line []byte) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal(line, &data); err != nil {
+		return fmt.Errorf("entry is not a JSON object: %w", err)
+	}
+
+	var problems []error
+	for _, f := range s.Fields {
+		v, ok := data[f.Name]
+		if !ok {
+			if f.Required {
+				problems = append(problems, fmt.Errorf("missing required field %q", f.Name))
+			}
+			continue
+		}
+		if got := schemaType(v); f.Type != SchemaTypeAny && got != f.Type {
+			problems = append(problems, fmt.Errorf("field %q is %s, want %s", f.Name, got, f.Type))
+			continue
+		}
+		if len(f.Enum) > 0 && !containsString(f.Enum, fmt.Sprint(v)) {
+			problems = append(problems, fmt.Errorf("field %q is %q, want one of %s", f.Name, v, strings.Join(f.Enum, ", ")))
+		}
+		switch f.Format {
+		case "date-time":
+			if _, err := parseTimestamp(fmt.Sprint(v)); err != nil {
+				problems = append(problems, fmt.Errorf("field %q is not an RFC 3339 timestamp: %q", f.Name, v))
+			}
+		case "encrypted":
+			if !IsEncryptedValue(fmt.Sprint(v)) {
+				problems = append(problems, fmt.Errorf("field %q is not encrypted", f.Name))
+			}
+		}
+	}
+
+	if !s.AdditionalFields {
+		var unknown []string
+		for name := range data {
+			if _, ok := s.Field(name); !ok {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		for _, name := range unknown {
+			problems = append(problems, fmt.Errorf("unexpected field %q", name))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// ValidateAgainstSchema checks a JSON entry against the default logger's
+// schema. Use it in tests to catch output changes that would break
+// consumers.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	logging.SetDefaultLogger(logging.NewWithLoggerConfig(config.WithWriter(&buf).Build()))
+//	logging.Info("order placed")
+//	if err := logging.ValidateAgainstSchema(buf.Bytes()); err != nil {
+//		t.Fatal(err)
+//	}
+func ValidateAgainstSchema(entry []byte) error {
+	return SchemaDescriptor().Validate(entry)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
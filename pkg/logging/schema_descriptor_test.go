@@ -0,0 +1,203 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSchemaDescriptorLogger_JSON(t *testing.T) {
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithSchemaVersion("2").
+		WithFieldMigration(FieldMigration{Old: "userId", New: "user_id", KeepOld: true}).
+		Build()).
+		WithFields(map[string]interface{}{"service": "api", "replicas": 3, "canary": false})
+
+	schema := SchemaDescriptorLogger(logger)
+	if schema.ContractVersion != OutputContractVersion || schema.Format != "json" || schema.SchemaVersion != "2" {
+		t.Errorf("unexpected schema header: %+v", schema)
+	}
+
+	tests := []struct {
+		name     string
+		typ      string
+		required bool
+	}{
+		{"timestamp", SchemaTypeString, true},
+		{"level", SchemaTypeString, true},
+		{"message", SchemaTypeString, true},
+		{"service", SchemaTypeString, true},
+		{"replicas", SchemaTypeNumber, true},
+		{"canary", SchemaTypeBoolean, true},
+		{SchemaVersionField, SchemaTypeString, true},
+		{"user_id", SchemaTypeAny, false},
+		{"trace_id", SchemaTypeString, false},
+	}
+	for _, tt := range tests {
+		f, ok := schema.Field(tt.name)
+		if !ok {
+			t.Errorf("missing field %q", tt.name)
+			continue
+		}
+		if f.Type != tt.typ || f.Required != tt.required {
+			t.Errorf("field %q = %+v, want type %s required %v", tt.name, f, tt.typ, tt.required)
+		}
+	}
+	if f, _ := schema.Field("userId"); !f.Deprecated {
+		t.Error("expected the old migration name to be deprecated")
+	}
+	if f, _ := schema.Field("level"); len(f.Enum) != 6 || f.Enum[0] != "TRACE" {
+		t.Errorf("unexpected level enum %v", f.Enum)
+	}
+
+	if _, err := json.Marshal(schema); err != nil {
+		t.Errorf("expected the schema to marshal: %v", err)
+	}
+}
+
+func TestSchemaDescriptorLogger_Slog(t *testing.T) {
+	config := NewLoggerConfig().WithJSONFormat().Build()
+	config.UseSlog = true
+
+	schema := SchemaDescriptorLogger(NewWithLoggerConfig(config))
+	if schema.Format != "slog-json" {
+		t.Errorf("unexpected format %q", schema.Format)
+	}
+	for _, name := range []string{"time", "level", "msg"} {
+		if _, ok := schema.Field(name); !ok {
+			t.Errorf("expected slog key %q", name)
+		}
+	}
+}
+
+func TestOutputSchema_ValidatesOwnOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithWriter(buf).
+		WithSchemaVersion("3").
+		Build()).
+		WithField("service", "api")
+
+	logger.WithField("order_id", 42).Info("order placed")
+
+	schema := SchemaDescriptorLogger(logger)
+	if err := schema.Validate(buf.Bytes()); err != nil {
+		t.Errorf("expected the logger's own output to validate: %v", err)
+	}
+
+	schema.AdditionalFields = false
+	if err := schema.Validate(buf.Bytes()); err == nil || !strings.Contains(err.Error(), `unexpected field "order_id"`) {
+		t.Errorf("expected the call-site field to be rejected, got %v", err)
+	}
+}
+
+func TestOutputSchema_ValidateReportsProblems(t *testing.T) {
+	schema := SchemaDescriptorLogger(NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().Build()).
+		WithField("service", "api"))
+
+	err := schema.Validate([]byte(`{"timestamp":"yesterday","level":"LOUD","service":7}`))
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		`missing required field "message"`,
+		`field "timestamp" is not an RFC 3339 timestamp`,
+		`field "level" is "LOUD"`,
+		`field "service" is number, want string`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+
+	if err := schema.Validate([]byte("not json")); err == nil {
+		t.Error("expected an error for a non-JSON entry")
+	}
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	previous := GetDefaultLogger()
+	defer SetDefaultLogger(previous)
+
+	buf := &bytes.Buffer{}
+	SetDefaultLogger(NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build()))
+	GetDefaultLogger().Warn("disk almost full")
+
+	if err := ValidateAgainstSchema(buf.Bytes()); err != nil {
+		t.Errorf("expected the default logger's output to validate: %v", err)
+	}
+	if err := ValidateAgainstSchema([]byte(`{"level":"INFO"}`)); err == nil {
+		t.Error("expected an entry without a message to fail")
+	}
+}
+
+func TestOutputSchema_JSONSchema(t *testing.T) {
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithSchemaVersion("2").
+		WithFieldMigration(FieldMigration{Old: "userId", New: "user_id", KeepOld: true}).
+		Build())
+
+	document := JSONSchemaLogger(logger)
+	if document["$schema"] != JSONSchemaDraft || document["title"] != "go-logging json entry, schema version 2" {
+		t.Errorf("unexpected document header: %v", document)
+	}
+	properties := document["properties"].(map[string]interface{})
+	timestamp := properties["timestamp"].(map[string]interface{})
+	if timestamp["type"] != SchemaTypeString || timestamp["format"] != "date-time" {
+		t.Errorf("unexpected timestamp property %v", timestamp)
+	}
+	if _, ok := properties["user_id"].(map[string]interface{})["type"]; ok {
+		t.Error("expected a field of any type to have no type keyword")
+	}
+	if properties["userId"].(map[string]interface{})["deprecated"] != true {
+		t.Error("expected the old migration name to be deprecated")
+	}
+	required := strings.Join(document["required"].([]string), ",")
+	if required != "timestamp,level,message,"+SchemaVersionField {
+		t.Errorf("unexpected required properties %s", required)
+	}
+	if _, err := json.Marshal(document); err != nil {
+		t.Errorf("expected the document to marshal: %v", err)
+	}
+}
+
+func TestSchemaDescriptorLogger_ElasticsearchAndDatadog(t *testing.T) {
+	es, err := NewElasticsearchOutput(ElasticsearchConfig{URL: "http://127.0.0.1:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer es.Close()
+	schema := SchemaDescriptorLogger(NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(es).Build()))
+	if f, ok := schema.Field("@timestamp"); schema.Format != "elasticsearch" || !ok || !f.Required {
+		t.Errorf("unexpected elasticsearch schema %+v", schema)
+	}
+
+	dd, err := NewDatadogOutput(DatadogConfig{APIKey: "key", Service: "checkout", URL: "http://127.0.0.1:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dd.Close()
+	schema = SchemaDescriptorLogger(NewWithLoggerConfig(NewLoggerConfig().WithCustomOutput(dd).Build()))
+	if schema.Format != "datadog" {
+		t.Fatalf("expected the datadog format, got %q", schema.Format)
+	}
+	if f, _ := schema.Field("timestamp"); f.Type != SchemaTypeNumber {
+		t.Errorf("expected a numeric timestamp, got %+v", f)
+	}
+	if f, _ := schema.Field("status"); strings.Join(f.Enum, ",") != "debug,info,warn,error,critical" {
+		t.Errorf("unexpected status enum %v", f.Enum)
+	}
+	if f, _ := schema.Field("service"); !f.Required {
+		t.Error("expected service to be required when configured")
+	}
+	if _, ok := schema.Field("dd.trace_id"); !ok {
+		t.Error("expected dd.trace_id")
+	}
+	if _, ok := schema.Field("trace_id"); ok {
+		t.Error("expected no trace_id, which Datadog logs carry as dd.trace_id")
+	}
+}
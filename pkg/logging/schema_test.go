@@ -0,0 +1,122 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyFieldMigrations(t *testing.T) {
+	tests := []struct {
+		name       string
+		fields     map[string]interface{}
+		migrations []FieldMigration
+		want       map[string]interface{}
+	}{
+		{
+			name:       "rename",
+			fields:     map[string]interface{}{"userId": 1, "a": 2},
+			migrations: []FieldMigration{{Old: "userId", New: "user_id"}},
+			want:       map[string]interface{}{"user_id": 1, "a": 2},
+		},
+		{
+			name:       "keep old during window",
+			fields:     map[string]interface{}{"userId": 1},
+			migrations: []FieldMigration{{Old: "userId", New: "user_id", KeepOld: true}},
+			want:       map[string]interface{}{"user_id": 1, "userId": 1},
+		},
+		{
+			name:       "new name already used",
+			fields:     map[string]interface{}{"user_id": 1},
+			migrations: []FieldMigration{{Old: "userId", New: "user_id", KeepOld: true}},
+			want:       map[string]interface{}{"user_id": 1, "userId": 1},
+		},
+		{
+			name:       "new wins over old",
+			fields:     map[string]interface{}{"userId": 1, "user_id": 2},
+			migrations: []FieldMigration{{Old: "userId", New: "user_id"}},
+			want:       map[string]interface{}{"user_id": 2},
+		},
+		{
+			name:       "not present",
+			fields:     map[string]interface{}{"a": 1},
+			migrations: []FieldMigration{{Old: "userId", New: "user_id"}},
+			want:       map[string]interface{}{"a": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := copyFields(tt.fields, 0)
+			got := ApplyFieldMigrations(tt.fields, tt.migrations)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyFieldMigrations() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.fields, original) {
+				t.Errorf("input fields modified: %v", tt.fields)
+			}
+		})
+	}
+}
+
+func TestLogger_SchemaVersionAndMigrations(t *testing.T) {
+	for _, useSlog := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		logger := NewWithLoggerConfig(NewLoggerConfig().
+			WithJSONFormat().
+			WithWriter(buf).
+			UseSlog(useSlog).
+			WithSchemaVersion("2").
+			WithFieldMigration(FieldMigration{Old: "userId", New: "user_id", KeepOld: true}).
+			WithFieldMigration(FieldMigration{Old: "svc", New: "service"}).
+			Build())
+
+		logger.WithFields(map[string]interface{}{"userId": 7, "svc": "api"}).Info("migrated")
+
+		var got map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("slog=%v: invalid JSON %q: %v", useSlog, buf.String(), err)
+		}
+		if got[SchemaVersionField] != "2" {
+			t.Errorf("slog=%v: expected %s=2, got %v", useSlog, SchemaVersionField, got)
+		}
+		if got["user_id"] != float64(7) || got["userId"] != float64(7) {
+			t.Errorf("slog=%v: expected both user_id and userId, got %v", useSlog, got)
+		}
+		if got["service"] != "api" || got["svc"] != nil {
+			t.Errorf("slog=%v: expected svc renamed to service, got %v", useSlog, got)
+		}
+	}
+}
+
+func TestYAMLSchemaVersion(t *testing.T) {
+	var yamlConfig YAMLConfig
+	err := unmarshalYAMLConfig([]byte(`
+schema_version: "3"
+field_migrations:
+  - old: userId
+    new: user_id
+    keep_old: true
+`), &yamlConfig)
+	if err != nil {
+		t.Fatalf("unmarshalYAMLConfig() error = %v", err)
+	}
+
+	builder := NewLoggerConfig()
+	if err := configureCoreFromYAML(builder, &yamlConfig); err != nil {
+		t.Fatalf("configureCoreFromYAML() error = %v", err)
+	}
+	core := builder.Build().Core
+	if core.SchemaVersion != "3" {
+		t.Errorf("expected schema version 3, got %q", core.SchemaVersion)
+	}
+	want := []FieldMigration{{Old: "userId", New: "user_id", KeepOld: true}}
+	if !reflect.DeepEqual(core.FieldMigrations, want) {
+		t.Errorf("expected migrations %v, got %v", want, core.FieldMigrations)
+	}
+
+	if _, err := LoadFromYAMLString("field_migrations:\n  - old: a\n"); err == nil {
+		t.Error("expected error for a migration without a new name")
+	}
+}
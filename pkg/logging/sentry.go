@@ -0,0 +1,439 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sentryMaxTagLength is the longest tag value Sentry accepts.
+const sentryMaxTagLength = 200
+
+// Fields with special meaning to SentryOutput.
+const (
+	// SentryFingerprintField overrides the fingerprint derived from the
+	// message. Separate several parts with "|".
+	SentryFingerprintField = "fingerprint"
+	// SentryStackField holds a stack trace, in the format of
+	// runtime/debug.Stack, reported with the event's exception.
+	SentryStackField = "stack"
+)
+
+// SentryConfig configures a SentryOutput.
+type SentryConfig struct {
+	// DSN is the project's client key, e.g.
+	// "https://<key>@o0.ingest.sentry.io/<project>".
+	DSN string
+	// Environment is sent with every event, e.g. "production".
+	Environment string
+	// Release is sent with every event, e.g. "checkout@1.4.2".
+	Release string
+	// ServerName defaults to the machine's hostname.
+	ServerName string
+	// TagFields lists the fields sent as tags. Without it, every string,
+	// number or bool field of at most 200 characters is a tag. Other fields
+	// are sent as extra data.
+	TagFields []string
+	// QueueSize bounds the events waiting to be sent. Defaults to 1000.
+	QueueSize int
+	// Retry controls retries of events that fail with network errors, 429
+	// or 5xx responses.
+	Retry RetryConfig
+	// Client defaults to an http.Client with a 30s timeout.
+	Client *http.Client
+	// OnError is called with the number of events that could not be sent.
+	OnError func(events int, err error)
+}
+
+// SentryOutput sends ERROR and CRITICAL entries to Sentry as events and
+// ignores the rest. An error field, such as one set by the fluent Err,
+// becomes the event's exception, with the stack trace from a stack field or
+// else the entry's caller. Events are grouped by a fingerprint of the
+// message with numbers, IDs and quoted values replaced by placeholders, so
+// "user 42 not found" and "user 7 not found" form one issue. Fields become
+// tags, and the context's trace ID links events to their traces.
+//
+// Example:
+//
+//	sentry, err := logging.NewSentryOutput(logging.SentryConfig{
+//		DSN:         os.Getenv("SENTRY_DSN"),
+//		Environment: "production",
+//		TagFields:   []string{"service", "component"},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	output := logging.NewRoutingOutput(
+//		logging.Route{Output: logging.NewWriterOutput(os.Stdout)},
+//		logging.Route{Output: sentry, MinLevel: logging.ErrorLevel},
+//	)
+type SentryOutput struct {
+	config   SentryConfig
+	endpoint string
+	auth     string
+	tagNames map[string]bool
+	worker   *AsyncWorker[json.RawMessage]
+}
+
+// NewSentryOutput creates a SentryOutput.
+func NewSentryOutput(config SentryConfig) (*SentryOutput, error) {
+	endpoint, key, err := parseSentryDSN(config.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if config.ServerName == "" {
+		config.ServerName, _ = os.Hostname()
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	so := &SentryOutput{
+		config:   config,
+		endpoint: endpoint,
+		auth:     "Sentry sentry_version=7, sentry_client=go-logging/1.0, sentry_key=" + key,
+	}
+	if len(config.TagFields) > 0 {
+		so.tagNames = make(map[string]bool, len(config.TagFields))
+		for _, name := range config.TagFields {
+			so.tagNames[name] = true
+		}
+	}
+	so.worker = NewAsyncWorker(AsyncWorkerConfig[json.RawMessage]{
+		QueueSize: config.QueueSize,
+		Processor: func(event json.RawMessage) error {
+			if err := so.send(event); err != nil {
+				so.reportError(1, err)
+			}
+			return nil
+		},
+	})
+	return so, nil
+}
+
+// parseSentryDSN returns the envelope endpoint and public key of dsn.
+func parseSentryDSN(dsn string) (endpoint, key string, err error) {
+	if dsn == "" {
+		return "", "", fmt.Errorf("sentry output requires a DSN")
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse sentry DSN: %w", err)
+	}
+	key = u.User.Username()
+	path := strings.Trim(u.Path, "/")
+	slash := strings.LastIndexByte(path, '/')
+	project := path[slash+1:]
+	if key == "" || project == "" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid sentry DSN %q: expected scheme://key@host/project", u.Redacted())
+	}
+	prefix := ""
+	if slash >= 0 {
+		prefix = "/" + path[:slash]
+	}
+	return u.Scheme + "://" + u.Host + prefix + "/api/" + project + "/envelope/", key, nil
+}
+
+// sentryLevel maps a level to a Sentry event level.
+func sentryLevel(level Level) string {
+	if level >= CriticalLevel {
+		return "fatal"
+	}
+	return "error"
+}
+
+// sentryEvent is a LogEntry in Sentry's event payload form.
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   float64                `json:"timestamp"`
+	Level       string                 `json:"level"`
+	Platform    string                 `json:"platform"`
+	Logger      string                 `json:"logger"`
+	Message     *sentryMessage         `json:"logentry,omitempty"`
+	Exception   *sentryExceptions      `json:"exception,omitempty"`
+	Fingerprint []string               `json:"fingerprint"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Contexts    map[string]interface{} `json:"contexts,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+}
+
+type sentryMessage struct {
+	Formatted string `json:"formatted"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+// Write sends data as the message of an ERROR entry.
+func (so *SentryOutput) Write(data []byte) error {
+	return so.WriteEntry(LogEntry{
+		Timestamp: time.Now(),
+		Level:     ErrorLevel,
+		Message:   string(bytes.TrimRight(data, "\r\n")),
+	}, data)
+}
+
+// WriteEntry queues entry as an event if it is ERROR or CRITICAL.
+func (so *SentryOutput) WriteEntry(entry LogEntry, _ []byte) error {
+	if entry.Level < ErrorLevel {
+		return nil
+	}
+
+	data, err := json.Marshal(so.event(entry))
+	if err != nil {
+		return fmt.Errorf("failed to encode sentry event: %w", err)
+	}
+	if so.worker.IsClosed() {
+		return fmt.Errorf("output is closed")
+	}
+	if !so.worker.Submit(data) {
+		so.reportError(1, ErrQueueFull)
+		return ErrQueueFull
+	}
+	return nil
+}
+
+// event converts entry to a Sentry event.
+func (so *SentryOutput) event(entry LogEntry) sentryEvent {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	e := sentryEvent{
+		EventID:     newSentryEventID(),
+		Timestamp:   float64(entry.Timestamp.UnixNano()) / 1e9,
+		Level:       sentryLevel(entry.Level),
+		Platform:    "go",
+		Logger:      "go-logging",
+		Message:     &sentryMessage{Formatted: entry.Message},
+		Fingerprint: []string{messageTemplate(entry.Message)},
+		Environment: so.config.Environment,
+		Release:     so.config.Release,
+		ServerName:  so.config.ServerName,
+	}
+
+	var errText, stack string
+	for k, v := range entry.Fields {
+		switch k {
+		case "error":
+			errText = fmt.Sprint(v)
+			continue
+		case SentryStackField:
+			stack = fmt.Sprint(v)
+			continue
+		case SentryFingerprintField:
+			e.Fingerprint = strings.Split(fmt.Sprint(v), "|")
+			continue
+		case "trace_id", "span_id":
+			continue
+		}
+		if tag, ok := so.tagValue(k, v); ok {
+			if e.Tags == nil {
+				e.Tags = make(map[string]string)
+			}
+			e.Tags[k] = tag
+			continue
+		}
+		if e.Extra == nil {
+			e.Extra = make(map[string]interface{})
+		}
+		e.Extra[k] = v
+	}
+	for _, name := range []string{"request_id", "correlation_id"} {
+		if _, ok := entry.Fields[name]; ok {
+			continue
+		}
+		if value, ok := entryFieldString(entry, name); ok {
+			if e.Tags == nil {
+				e.Tags = make(map[string]string)
+			}
+			e.Tags[name] = value
+		}
+	}
+	if traceID, ok := entryFieldString(entry, "trace_id"); ok && traceID != "" {
+		trace := map[string]string{"trace_id": traceID}
+		if spanID, ok := entryFieldString(entry, "span_id"); ok {
+			trace["span_id"] = spanID
+		}
+		e.Contexts = map[string]interface{}{"trace": trace}
+	}
+
+	if errText != "" || stack != "" {
+		exception := sentryException{Type: entry.Message, Value: errText}
+		frames := parseGoStack(stack)
+		if len(frames) == 0 && entry.File != "" {
+			frames = []sentryFrame{{Filename: entry.File, AbsPath: entry.File, Lineno: entry.Line, InApp: true}}
+		}
+		if len(frames) > 0 {
+			exception.Stacktrace = &sentryStacktrace{Frames: frames}
+		}
+		e.Exception = &sentryExceptions{Values: []sentryException{exception}}
+	}
+	return e
+}
+
+// tagValue returns v as a tag if the field name is sent as a tag.
+func (so *SentryOutput) tagValue(name string, v interface{}) (string, bool) {
+	if so.tagNames != nil {
+		if !so.tagNames[name] {
+			return "", false
+		}
+		tag := fmt.Sprint(v)
+		return tag, len(tag) <= sentryMaxTagLength
+	}
+	switch v.(type) {
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		tag := fmt.Sprint(v)
+		return tag, len(tag) <= sentryMaxTagLength
+	}
+	return "", false
+}
+
+// Patterns matching the parts of a message that vary between occurrences
+// of the same event.
+var (
+	messageQuoted = regexp.MustCompile(`"[^"]*"|'[^']*'`)
+	messageUUID   = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	messageHex    = regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9a-fA-F]{8,})\b`)
+	messageNumber = regexp.MustCompile(`\b\d+(?:\.\d+)?`)
+)
+
+// messageTemplate replaces quoted values, UUIDs, hex IDs and numbers in
+// message with placeholders.
+func messageTemplate(message string) string {
+	message = messageQuoted.ReplaceAllString(message, "<str>")
+	message = messageUUID.ReplaceAllString(message, "<uuid>")
+	message = messageHex.ReplaceAllStringFunc(message, func(match string) string {
+		if strings.HasPrefix(match, "0x") || strings.ContainsAny(match, "abcdefABCDEF") {
+			return "<hex>"
+		}
+		return match
+	})
+	return messageNumber.ReplaceAllString(message, "<num>")
+}
+
+// parseGoStack converts a stack trace in the format of runtime/debug.Stack
+// to Sentry frames, outermost call first.
+func parseGoStack(stack string) []sentryFrame {
+	lines := strings.Split(strings.TrimSpace(stack), "\n")
+	var frames []sentryFrame
+	for i := 0; i+1 < len(lines); i++ {
+		location := strings.TrimSpace(lines[i+1])
+		if strings.HasPrefix(lines[i], "\t") || !strings.HasPrefix(lines[i+1], "\t") {
+			continue
+		}
+		function := lines[i]
+		if paren := strings.LastIndexByte(function, '('); paren > 0 {
+			function = function[:paren]
+		}
+		if space := strings.LastIndexByte(location, ' '); space >= 0 {
+			location = location[:space]
+		}
+		colon := strings.LastIndexByte(location, ':')
+		if colon < 0 {
+			continue
+		}
+		line, _ := strconv.Atoi(location[colon+1:])
+		module := packageOfFunction(function)
+		frames = append(frames, sentryFrame{
+			Function: strings.TrimPrefix(function, module+"."),
+			Module:   module,
+			Filename: location[:colon],
+			AbsPath:  location[:colon],
+			Lineno:   line,
+			InApp:    !strings.HasPrefix(module, "runtime") && strings.Contains(module, "."),
+		})
+		i++
+	}
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+// newSentryEventID returns a random event ID: 32 hex characters.
+func newSentryEventID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// send posts event in an envelope.
+func (so *SentryOutput) send(event json.RawMessage) error {
+	var header struct {
+		EventID string `json:"event_id"`
+	}
+	_ = json.Unmarshal(event, &header)
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "{\"event_id\":%q,\"sent_at\":%q}\n", header.EventID, time.Now().UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&body, "{\"type\":\"event\",\"length\":%d}\n", len(event))
+	body.Write(event)
+	body.WriteByte('\n')
+
+	err := retry(context.Background(), so.config.Retry, func() error {
+		req, err := http.NewRequest(http.MethodPost, so.endpoint, bytes.NewReader(body.Bytes()))
+		if err != nil {
+			return permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/x-sentry-envelope")
+		req.Header.Set("X-Sentry-Auth", so.auth)
+
+		resp, err := so.config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		return checkHTTPResponse(resp)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send event to sentry: %w", err)
+	}
+	return nil
+}
+
+func (so *SentryOutput) reportError(events int, err error) {
+	if so.config.OnError != nil {
+		so.config.OnError(events, err)
+	}
+}
+
+// Close sends queued events.
+func (so *SentryOutput) Close() error {
+	return so.worker.Stop()
+}
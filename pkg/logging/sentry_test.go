@@ -0,0 +1,219 @@
+package logging
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSentryDSN(t *testing.T) {
+	endpoint, key, err := parseSentryDSN("https://abc123@o1.ingest.sentry.io/42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if endpoint != "https://o1.ingest.sentry.io/api/42/envelope/" || key != "abc123" {
+		t.Errorf("got endpoint %q, key %q", endpoint, key)
+	}
+
+	endpoint, _, err = parseSentryDSN("http://key@sentry.internal/prefix/7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if endpoint != "http://sentry.internal/prefix/api/7/envelope/" {
+		t.Errorf("got endpoint %q", endpoint)
+	}
+
+	for _, dsn := range []string{"", "https://sentry.io/42", "https://key@sentry.io/"} {
+		if _, _, err := parseSentryDSN(dsn); err == nil {
+			t.Errorf("expected error for DSN %q", dsn)
+		}
+	}
+}
+
+func TestMessageTemplate(t *testing.T) {
+	tests := map[string]string{
+		"user 42 not found":                                   "user <num> not found",
+		`failed to open "/tmp/a.log"`:                         "failed to open <str>",
+		"order 4bf92f35-77b3-4da6-a3ce-929d0e0e4736 declined": "order <uuid> declined",
+		"object deadbeef01 at 0x1f missing after 1.5s":        "object <hex> at <hex> missing after <num>s",
+		"connection refused":                                  "connection refused",
+	}
+	for in, want := range tests {
+		if got := messageTemplate(in); got != want {
+			t.Errorf("messageTemplate(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseGoStack(t *testing.T) {
+	stack := `goroutine 1 [running]:
+runtime/debug.Stack()
+	/usr/local/go/src/runtime/debug/stack.go:26 +0x5e
+example.com/app/billing.(*Service).Charge(0xc000010000)
+	/src/app/billing/service.go:88 +0x1d
+main.main()
+	/src/app/main.go:12 +0x25
+`
+	frames := parseGoStack(stack)
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d: %+v", len(frames), frames)
+	}
+	if frames[0].Function != "main" || frames[0].Module != "main" || frames[0].Lineno != 12 {
+		t.Errorf("unexpected outermost frame: %+v", frames[0])
+	}
+	billing := frames[1]
+	if billing.Module != "example.com/app/billing" || billing.Function != "(*Service).Charge" ||
+		billing.Filename != "/src/app/billing/service.go" || billing.Lineno != 88 || !billing.InApp {
+		t.Errorf("unexpected frame: %+v", billing)
+	}
+	if frames[2].InApp {
+		t.Errorf("expected runtime frame not in app: %+v", frames[2])
+	}
+}
+
+func TestSentryOutput_Send(t *testing.T) {
+	var mu sync.Mutex
+	var events []map[string]interface{}
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scanner := bufio.NewScanner(r.Body)
+		var lines []string
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if len(lines) != 3 || !strings.Contains(lines[1], `"type":"event"`) {
+			t.Errorf("unexpected envelope: %q", lines)
+			return
+		}
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[2]), &event); err != nil {
+			t.Errorf("invalid event: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		auth = r.Header.Get("X-Sentry-Auth")
+	}))
+	defer server.Close()
+
+	output, err := NewSentryOutput(SentryConfig{
+		DSN:         strings.Replace(server.URL, "://", "://pubkey@", 1) + "/5",
+		Environment: "production",
+		ServerName:  "web-1",
+	})
+	if err != nil {
+		t.Fatalf("failed to create sentry output: %v", err)
+	}
+
+	ctx := WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	_ = output.WriteEntry(LogEntry{Level: InfoLevel, Message: "ignored"}, nil)
+	_ = output.WriteEntry(LogEntry{
+		Timestamp: time.Unix(1700000000, 0),
+		Level:     ErrorLevel,
+		Message:   "charge 1234 failed",
+		Fields: map[string]interface{}{
+			"error":   "card expired",
+			"service": "billing",
+			"cart":    []string{"a", "b"},
+		},
+		Context: ctx,
+		File:    "billing.go",
+		Line:    88,
+	}, nil)
+	_ = output.WriteEntry(LogEntry{
+		Level:   CriticalLevel,
+		Message: "out of disk",
+		Fields:  map[string]interface{}{SentryFingerprintField: "disk|full"},
+	}, nil)
+	if err := output.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if !strings.Contains(auth, "sentry_key=pubkey") {
+		t.Errorf("unexpected auth header %q", auth)
+	}
+
+	event := events[0]
+	if event["level"] != "error" || event["environment"] != "production" || event["server_name"] != "web-1" {
+		t.Errorf("unexpected event: %v", event)
+	}
+	if fp := event["fingerprint"].([]interface{}); len(fp) != 1 || fp[0] != "charge <num> failed" {
+		t.Errorf("unexpected fingerprint: %v", fp)
+	}
+	tags := event["tags"].(map[string]interface{})
+	if tags["service"] != "billing" {
+		t.Errorf("expected service tag, got %v", tags)
+	}
+	if _, ok := event["extra"].(map[string]interface{})["cart"]; !ok {
+		t.Errorf("expected cart in extra, got %v", event["extra"])
+	}
+	trace := event["contexts"].(map[string]interface{})["trace"].(map[string]interface{})
+	if trace["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("unexpected trace context: %v", trace)
+	}
+	exception := event["exception"].(map[string]interface{})["values"].([]interface{})[0].(map[string]interface{})
+	if exception["value"] != "card expired" {
+		t.Errorf("unexpected exception: %v", exception)
+	}
+	frames := exception["stacktrace"].(map[string]interface{})["frames"].([]interface{})
+	if frame := frames[0].(map[string]interface{}); frame["filename"] != "billing.go" || frame["lineno"] != float64(88) {
+		t.Errorf("unexpected frame: %v", frame)
+	}
+
+	event = events[1]
+	if event["level"] != "fatal" {
+		t.Errorf("expected fatal level, got %v", event["level"])
+	}
+	if fp := event["fingerprint"].([]interface{}); len(fp) != 2 || fp[0] != "disk" || fp[1] != "full" {
+		t.Errorf("unexpected fingerprint: %v", fp)
+	}
+}
+
+func TestSentryOutput_OnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var failed int
+	var lastErr error
+	output, err := NewSentryOutput(SentryConfig{
+		DSN: strings.Replace(server.URL, "://", "://key@", 1) + "/1",
+		OnError: func(events int, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed += events
+			lastErr = err
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create sentry output: %v", err)
+	}
+	_ = output.Write([]byte("boom\n"))
+	_ = output.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if failed != 1 || lastErr == nil {
+		t.Errorf("expected one failed event, got %d (%v)", failed, lastErr)
+	}
+	if errors.Is(lastErr, ErrQueueFull) {
+		t.Errorf("unexpected queue full error")
+	}
+	if err := output.Write([]byte("after close")); err == nil {
+		t.Error("expected error writing to closed output")
+	}
+}
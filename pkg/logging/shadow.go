@@ -0,0 +1,203 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ShadowConfig configures a ShadowOutput.
+type ShadowConfig struct {
+	// Primary is the current pipeline. Its errors are returned to the
+	// logger as before.
+	Primary Output
+	// Candidate is the pipeline being evaluated.
+	Candidate Output
+	// Formatter, when set, re-formats entries for the candidate, e.g. to try
+	// a new format. Nil sends the candidate the primary's bytes.
+	Formatter Formatter
+	// Field tags shadow copies with Field=true. Defaults to "shadow".
+	Field string
+	// QueueSize bounds the copies waiting for the candidate; copies beyond
+	// it are dropped and counted. Defaults to 1000.
+	QueueSize int
+}
+
+// ShadowReport counts how the candidate pipeline diverged from the primary.
+type ShadowReport struct {
+	// Entries is the number of entries written to the primary.
+	Entries uint64
+	// PrimaryErrors and CandidateErrors count failed writes.
+	PrimaryErrors   uint64
+	CandidateErrors uint64
+	// CandidateDrops counts copies dropped because the queue was full.
+	CandidateDrops uint64
+	// CandidateOnlyFailures counts entries the primary accepted but the
+	// candidate lost, through an error or a drop; PrimaryOnlyFailures the
+	// reverse.
+	CandidateOnlyFailures uint64
+	PrimaryOnlyFailures   uint64
+	// LastCandidateError is the most recent candidate error, if any.
+	LastCandidateError error
+}
+
+// String summarizes the report on one line.
+func (r ShadowReport) String() string {
+	s := fmt.Sprintf("entries=%d primary_errors=%d candidate_errors=%d candidate_drops=%d candidate_only_failures=%d primary_only_failures=%d",
+		r.Entries, r.PrimaryErrors, r.CandidateErrors, r.CandidateDrops, r.CandidateOnlyFailures, r.PrimaryOnlyFailures)
+	if r.LastCandidateError != nil {
+		s += fmt.Sprintf(" last_candidate_error=%q", r.LastCandidateError.Error())
+	}
+	return s
+}
+
+// shadowCopy is an entry queued for the candidate.
+type shadowCopy struct {
+	entry         *LogEntry
+	data          []byte
+	primaryFailed bool
+}
+
+// ShadowOutput mirrors every entry to a primary and a candidate pipeline so
+// a new log platform or format can be validated with production traffic.
+// The primary is written synchronously and stays authoritative; the
+// candidate receives tagged copies from a bounded queue on its own
+// goroutine, so its failures and slowness never affect the primary. Report
+// shows where the two diverged.
+//
+// Example:
+//
+//	shadow, err := logging.NewShadowOutput(logging.ShadowConfig{
+//		Primary:   current,
+//		Candidate: lokiOutput,
+//	})
+//	...
+//	log.Printf("shadow: %s", shadow.Report())
+type ShadowOutput struct {
+	config ShadowConfig
+	worker *AsyncWorker[shadowCopy]
+
+	entries               atomic.Uint64
+	primaryErrors         atomic.Uint64
+	candidateErrors       atomic.Uint64
+	candidateDrops        atomic.Uint64
+	candidateOnlyFailures atomic.Uint64
+	primaryOnlyFailures   atomic.Uint64
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+// NewShadowOutput creates a ShadowOutput.
+func NewShadowOutput(config ShadowConfig) (*ShadowOutput, error) {
+	if config.Primary == nil || config.Candidate == nil {
+		return nil, fmt.Errorf("shadow output requires primary and candidate outputs")
+	}
+	if config.Field == "" {
+		config.Field = "shadow"
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+
+	so := &ShadowOutput{config: config}
+	so.worker = NewAsyncWorker(AsyncWorkerConfig[shadowCopy]{
+		QueueSize: config.QueueSize,
+		Processor: so.writeCandidate,
+	})
+	return so, nil
+}
+
+// Write writes data to the primary and queues a copy for the candidate.
+func (so *ShadowOutput) Write(data []byte) error {
+	err := so.config.Primary.Write(data)
+	so.mirror(shadowCopy{data: append([]byte(nil), data...)}, err)
+	return err
+}
+
+// WriteEntry writes to the primary and queues a tagged copy of entry for
+// the candidate.
+func (so *ShadowOutput) WriteEntry(entry LogEntry, data []byte) error {
+	err := writeEntry(so.config.Primary, entry, data)
+
+	tagged := entry
+	tagged.Fields = copyFields(entry.Fields, 1)
+	tagged.Fields[so.config.Field] = true
+	so.mirror(shadowCopy{entry: &tagged, data: append([]byte(nil), data...)}, err)
+	return err
+}
+
+// mirror records the primary's result and queues c for the candidate.
+func (so *ShadowOutput) mirror(c shadowCopy, primaryErr error) {
+	so.entries.Add(1)
+	c.primaryFailed = primaryErr != nil
+	if c.primaryFailed {
+		so.primaryErrors.Add(1)
+	}
+	if !so.worker.Submit(c) {
+		so.candidateDrops.Add(1)
+		so.recordCandidateLoss(c)
+	}
+}
+
+// writeCandidate writes one copy to the candidate.
+func (so *ShadowOutput) writeCandidate(c shadowCopy) error {
+	var err error
+	switch {
+	case c.entry == nil:
+		err = so.config.Candidate.Write(c.data)
+	case so.config.Formatter != nil:
+		var data []byte
+		if data, err = so.config.Formatter.Format(*c.entry); err == nil {
+			err = writeEntry(so.config.Candidate, *c.entry, data)
+		}
+	default:
+		err = writeEntry(so.config.Candidate, *c.entry, c.data)
+	}
+
+	if err != nil {
+		so.candidateErrors.Add(1)
+		so.mu.Lock()
+		so.lastErr = err
+		so.mu.Unlock()
+		so.recordCandidateLoss(c)
+	} else if c.primaryFailed {
+		so.primaryOnlyFailures.Add(1)
+	}
+	return nil
+}
+
+// recordCandidateLoss counts a copy the candidate lost.
+func (so *ShadowOutput) recordCandidateLoss(c shadowCopy) {
+	if !c.primaryFailed {
+		so.candidateOnlyFailures.Add(1)
+	}
+}
+
+// Report returns the divergence counted so far. Copies still queued for
+// the candidate are not yet reflected.
+func (so *ShadowOutput) Report() ShadowReport {
+	so.mu.Lock()
+	lastErr := so.lastErr
+	so.mu.Unlock()
+
+	return ShadowReport{
+		Entries:               so.entries.Load(),
+		PrimaryErrors:         so.primaryErrors.Load(),
+		CandidateErrors:       so.candidateErrors.Load(),
+		CandidateDrops:        so.candidateDrops.Load(),
+		CandidateOnlyFailures: so.candidateOnlyFailures.Load(),
+		PrimaryOnlyFailures:   so.primaryOnlyFailures.Load(),
+		LastCandidateError:    lastErr,
+	}
+}
+
+// Close drains the candidate queue and closes both outputs.
+func (so *ShadowOutput) Close() error {
+	so.worker.Stop()
+	candidateErr := so.config.Candidate.Close()
+	if err := so.config.Primary.Close(); err != nil {
+		return err
+	}
+	return candidateErr
+}
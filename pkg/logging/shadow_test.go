@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// shadowRecorder records entries and optionally fails every write.
+type shadowRecorder struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	data    []string
+	err     error
+	block   chan struct{}
+}
+
+func (r *shadowRecorder) Write(data []byte) error {
+	return r.WriteEntry(LogEntry{}, data)
+}
+
+func (r *shadowRecorder) WriteEntry(entry LogEntry, data []byte) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entry)
+	r.data = append(r.data, string(data))
+	return r.err
+}
+
+func (r *shadowRecorder) Close() error { return nil }
+
+func TestNewShadowOutput_Validation(t *testing.T) {
+	if _, err := NewShadowOutput(ShadowConfig{Primary: &shadowRecorder{}}); err == nil {
+		t.Error("expected error without a candidate")
+	}
+}
+
+func TestShadowOutput_Mirrors(t *testing.T) {
+	primary, candidate := &shadowRecorder{}, &shadowRecorder{}
+	output, err := NewShadowOutput(ShadowConfig{
+		Primary:   primary,
+		Candidate: candidate,
+		Formatter: NewTextFormatter(&FormatterConfig{}),
+	})
+	if err != nil {
+		t.Fatalf("failed to create shadow output: %v", err)
+	}
+
+	fields := map[string]interface{}{"user": "ada"}
+	if err := output.WriteEntry(LogEntry{Level: InfoLevel, Message: "hello", Fields: fields}, []byte("primary\n")); err != nil {
+		t.Fatalf("WriteEntry returned error: %v", err)
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+
+	if len(primary.entries) != 1 || primary.data[0] != "primary\n" {
+		t.Fatalf("unexpected primary writes: %v", primary.data)
+	}
+	if _, ok := primary.entries[0].Fields["shadow"]; ok {
+		t.Error("expected the primary entry to be untagged")
+	}
+	if _, ok := fields["shadow"]; ok {
+		t.Error("expected the caller's fields to be unmodified")
+	}
+	if len(candidate.entries) != 1 || candidate.entries[0].Fields["shadow"] != true {
+		t.Fatalf("expected a tagged candidate entry, got %v", candidate.entries)
+	}
+	if !strings.Contains(candidate.data[0], "hello") || !strings.Contains(candidate.data[0], "shadow=true") {
+		t.Errorf("expected the candidate formatter output, got %q", candidate.data[0])
+	}
+	if report := output.Report(); report.Entries != 1 || report.CandidateErrors != 0 {
+		t.Errorf("unexpected report: %s", report)
+	}
+}
+
+func TestShadowOutput_CandidateFailure(t *testing.T) {
+	primary := &shadowRecorder{}
+	candidate := &shadowRecorder{err: errors.New("candidate down")}
+	output, _ := NewShadowOutput(ShadowConfig{Primary: primary, Candidate: candidate})
+
+	for i := 0; i < 3; i++ {
+		if err := output.Write([]byte("line\n")); err != nil {
+			t.Fatalf("expected candidate errors not to reach the caller: %v", err)
+		}
+	}
+	_ = output.Close()
+
+	report := output.Report()
+	if report.Entries != 3 || report.CandidateErrors != 3 || report.CandidateOnlyFailures != 3 {
+		t.Errorf("unexpected report: %s", report)
+	}
+	if report.LastCandidateError == nil || !strings.Contains(report.String(), "candidate down") {
+		t.Errorf("expected the last candidate error in the report: %s", report)
+	}
+}
+
+func TestShadowOutput_PrimaryFailure(t *testing.T) {
+	primary := &shadowRecorder{err: errors.New("primary down")}
+	output, _ := NewShadowOutput(ShadowConfig{Primary: primary, Candidate: &shadowRecorder{}})
+
+	if err := output.Write([]byte("line\n")); err == nil {
+		t.Error("expected the primary error to be returned")
+	}
+	_ = output.Close()
+
+	if report := output.Report(); report.PrimaryErrors != 1 || report.PrimaryOnlyFailures != 1 {
+		t.Errorf("unexpected report: %s", report)
+	}
+}
+
+func TestShadowOutput_Drops(t *testing.T) {
+	candidate := &shadowRecorder{block: make(chan struct{})}
+	output, _ := NewShadowOutput(ShadowConfig{
+		Primary:   &shadowRecorder{},
+		Candidate: candidate,
+		QueueSize: 1,
+	})
+
+	for i := 0; i < 10; i++ {
+		_ = output.Write([]byte("line\n"))
+	}
+	close(candidate.block)
+	_ = output.Close()
+
+	report := output.Report()
+	if report.CandidateDrops == 0 || report.CandidateDrops != report.CandidateOnlyFailures {
+		t.Errorf("expected drops to be counted, got %s", report)
+	}
+	if uint64(len(candidate.data))+report.CandidateDrops != 10 {
+		t.Errorf("expected every entry to be written or dropped: %d written, %s", len(candidate.data), report)
+	}
+}
@@ -0,0 +1,212 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// DefaultShutdownTimeout bounds the flush RegisterShutdown performs when the
+// process receives a shutdown signal.
+const DefaultShutdownTimeout = 5 * time.Second
+
+// ClosableLogger is implemented by loggers that can flush and close their
+// outputs. Loggers built with NewWithLoggerConfig implement it.
+type ClosableLogger interface {
+	Logger
+
+	// Close flushes and closes the logger's custom output or slog handler.
+	// Children share them, so it must be called once, on the root logger.
+	Close() error
+}
+
+// defaultLoggerTarget stands for whatever the default logger is when
+// Shutdown runs.
+type defaultLoggerTarget struct{}
+
+// shutdownTargets are closed by Shutdown, most recently registered first.
+// closing is held while they are, so a concurrent Shutdown waits for them.
+var shutdownTargets struct {
+	mu      sync.Mutex
+	closing sync.Mutex
+	targets []interface{}
+}
+
+var _ ClosableLogger = (*unifiedLogger)(nil)
+
+// exitOnSignal ends the process after a shutdown signal has been handled;
+// tests replace it.
+var exitOnSignal = reraiseSignal
+
+// RegisterShutdown arranges for targets to be flushed and closed when the
+// process receives SIGTERM or SIGINT, so the tail of the logs is not lost
+// when a container stops. Targets are Outputs such as BufferedOutput and
+// AsyncOutput, BufferedHandler, AsyncHandler and ClosableLoggers; without
+// targets, the default logger is closed. Once flushed, within
+// DefaultShutdownTimeout, the signal is raised again so the process exits
+// as it otherwise would have. Programs that handle these signals themselves,
+// or that exit normally, should call Shutdown instead. Call the returned
+// function to stop handling the signals.
+//
+// Example:
+//
+//	output := logging.NewAsyncOutput(logging.NewWriterOutput(os.Stdout), 1000)
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().WithCustomOutput(output).Build())
+//	logging.SetDefaultLogger(logger)
+//	stop := logging.RegisterShutdown()
+//	defer stop()
+//	defer logging.Shutdown(context.Background())
+func RegisterShutdown(targets ...interface{}) (stop func()) {
+	if len(targets) == 0 {
+		targets = []interface{}{defaultLoggerTarget{}}
+	}
+	addShutdownTargets(targets)
+	if len(shutdownSignals) == 0 {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, shutdownSignals...)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultShutdownTimeout)
+			if err := Shutdown(ctx); err != nil {
+				// Strict mode must not turn a signal into a panic
+				// before the process exits.
+				notifyDiagnostic(Diagnostic{
+					Kind:    DiagnosticFallback,
+					Source:  "RegisterShutdown",
+					Message: "exiting with entries that may not have been written",
+					Err:     err,
+				})
+			}
+			cancel()
+			signal.Stop(signals)
+			exitOnSignal(sig)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// addShutdownTargets registers the supported targets, reporting the rest.
+func addShutdownTargets(targets []interface{}) {
+	shutdownTargets.mu.Lock()
+	defer shutdownTargets.mu.Unlock()
+	for _, target := range targets {
+		if !canShutDown(target) {
+			reportInvalidConfig("RegisterShutdown", fmt.Sprintf("ignoring %T, which cannot be flushed or closed", target), nil)
+			continue
+		}
+		shutdownTargets.targets = append(shutdownTargets.targets, target)
+	}
+}
+
+// Shutdown flushes and closes everything registered with RegisterShutdown,
+// most recently registered first, giving up once ctx is done. Each target
+// is closed once; later calls do nothing.
+func Shutdown(ctx context.Context) error {
+	shutdownTargets.mu.Lock()
+	targets := shutdownTargets.targets
+	shutdownTargets.targets = nil
+	shutdownTargets.mu.Unlock()
+
+	result := make(chan error, 1)
+	go func() {
+		shutdownTargets.closing.Lock()
+		defer shutdownTargets.closing.Unlock()
+		var errs []error
+		for i := len(targets) - 1; i >= 0; i-- {
+			if err := shutDown(ctx, targets[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		result <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("failed to flush logs before shutdown: %w", ctx.Err())
+	}
+}
+
+// canShutDown reports whether shutDown supports target.
+func canShutDown(target interface{}) bool {
+	switch target.(type) {
+	case defaultLoggerTarget, *BufferedHandler, *AsyncHandler, *AsyncOutput, io.Closer:
+		return true
+	}
+	return false
+}
+
+// shutDown flushes and closes target, within ctx where it can.
+func shutDown(ctx context.Context, target interface{}) error {
+	switch t := target.(type) {
+	case defaultLoggerTarget:
+		if logger, ok := GetDefaultLogger().(ClosableLogger); ok {
+			return shutDown(ctx, logger)
+		}
+		return nil
+	case *unifiedLogger:
+		return t.shutdown(ctx)
+	case *BufferedHandler:
+		return t.Flush(ctx)
+	case *AsyncHandler:
+		_, err := t.StopWithTimeout(ctx)
+		return err
+	case *AsyncOutput:
+		if _, err := t.StopWithTimeout(ctx); err != nil {
+			return err
+		}
+		return t.output.Close()
+	case io.Closer:
+		return t.Close()
+	}
+	return fmt.Errorf("cannot shut down %T", target)
+}
+
+// Close flushes and closes the logger's custom output or slog handler.
+// Writers passed with WithWriter belong to the caller and are left open.
+func (ul *unifiedLogger) Close() error {
+	return ul.shutdown(context.Background())
+}
+
+func (ul *unifiedLogger) shutdown(ctx context.Context) error {
+	if ul.config.UseSlog {
+		if ul.config.Handler != nil && canShutDown(ul.config.Handler) {
+			return shutDown(ctx, ul.config.Handler)
+		}
+		return nil
+	}
+	if ul.config.Output.Custom != nil {
+		return shutDown(ctx, ul.config.Output.Custom)
+	}
+	return nil
+}
+
+// reraiseSignal restores the default handling of sig and raises it again,
+// exiting if that is not possible.
+func reraiseSignal(sig os.Signal) {
+	signal.Reset(sig)
+	if process, err := os.FindProcess(os.Getpid()); err == nil && process.Signal(sig) == nil {
+		time.Sleep(time.Second)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func (cr *closeRecorder) String() string {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.buf.String()
+}
+
+func TestShutdown(t *testing.T) {
+	buffered, async := &closeRecorder{}, &closeRecorder{}
+	bufferedOutput := NewBufferedOutput(buffered, 4096, 0)
+	asyncOutput := NewAsyncOutput(async, 100)
+	var handled bytes.Buffer
+	handler := NewBufferedHandler(slog.NewTextHandler(&handled, nil), 100)
+
+	RegisterShutdown(bufferedOutput, asyncOutput, handler)()
+
+	_ = bufferedOutput.Write([]byte("buffered\n"))
+	_ = asyncOutput.Write([]byte("async\n"))
+	_ = handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "handled", 0))
+	if buffered.String() != "" || handled.Len() != 0 {
+		t.Fatal("expected entries to be buffered before shutdown")
+	}
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if buffered.String() != "buffered\n" || async.String() != "async\n" || !strings.Contains(handled.String(), "handled") {
+		t.Errorf("expected every entry flushed, got %q, %q and %q", buffered.String(), async.String(), handled.String())
+	}
+	if !buffered.closed || !async.closed {
+		t.Error("expected the outputs to be closed")
+	}
+	if err := Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown() error = %v", err)
+	}
+}
+
+func TestRegisterShutdown_InvalidTarget(t *testing.T) {
+	diagnostics := captureDiagnostics(t)
+	RegisterShutdown("not an output")()
+	if got := diagnostics(); len(got) != 1 || got[0].Source != "RegisterShutdown" {
+		t.Errorf("expected a RegisterShutdown diagnostic, got %+v", got)
+	}
+	_ = Shutdown(context.Background())
+}
+
+func TestUnifiedLogger_Close(t *testing.T) {
+	recorder := &closeRecorder{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithCustomOutput(NewBufferedOutput(recorder, 4096, 0)).
+		Build())
+	logger.Info("tail entry")
+
+	closable, ok := logger.(ClosableLogger)
+	if !ok {
+		t.Fatal("expected logger to implement ClosableLogger")
+	}
+	if err := closable.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !strings.Contains(recorder.String(), "tail entry") || !recorder.closed {
+		t.Errorf("expected the entry flushed and output closed, got %q", recorder.String())
+	}
+}
+
+func TestUnifiedLogger_CloseLeavesWriterOpen(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithWriter(file).Build())
+	if err := logger.(ClosableLogger).Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := file.WriteString("still open\n"); err != nil {
+		t.Errorf("expected the writer to stay open, got %v", err)
+	}
+}
+
+func TestRegisterShutdown_Signal(t *testing.T) {
+	if runtime.GOOS == "windows" || len(shutdownSignals) == 0 {
+		t.Skip("SIGTERM cannot be sent on this platform")
+	}
+
+	received := make(chan os.Signal, 1)
+	exitOnSignal = func(sig os.Signal) { received <- sig }
+	defer func() { exitOnSignal = reraiseSignal }()
+
+	recorder := &closeRecorder{}
+	output := NewBufferedOutput(recorder, 4096, 0)
+	stop := RegisterShutdown(output)
+	defer stop()
+	_ = output.Write([]byte("last words\n"))
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send SIGTERM: %v", err)
+	}
+
+	select {
+	case sig := <-received:
+		if sig != syscall.SIGTERM {
+			t.Errorf("expected SIGTERM, got %v", sig)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the signal to be handled")
+	}
+	if recorder.String() != "last words\n" {
+		t.Errorf("expected the buffered entry flushed, got %q", recorder.String())
+	}
+}
+
+// closeErrorOutput fails to close.
+type closeErrorOutput struct{}
+
+func (closeErrorOutput) Write([]byte) error { return nil }
+func (closeErrorOutput) Close() error       { return errors.New("close failed") }
+
+func TestRegisterShutdown_SignalInStrictMode(t *testing.T) {
+	if runtime.GOOS == "windows" || len(shutdownSignals) == 0 {
+		t.Skip("SIGTERM cannot be sent on this platform")
+	}
+
+	received := make(chan os.Signal, 1)
+	exitOnSignal = func(sig os.Signal) { received <- sig }
+	defer func() { exitOnSignal = reraiseSignal }()
+	defer SetStrict(SetStrict(true))
+	diagnostics := make(chan Diagnostic, 1)
+	defer SetDiagnosticsHandler(SetDiagnosticsHandler(func(d Diagnostic) { diagnostics <- d }))
+
+	stop := RegisterShutdown(closeErrorOutput{})
+	defer stop()
+
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send SIGTERM: %v", err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the signal to be handled")
+	}
+	if d := <-diagnostics; d.Kind != DiagnosticFallback || d.Err == nil {
+		t.Errorf("expected the failed shutdown reported, got %+v", d)
+	}
+}
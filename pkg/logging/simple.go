@@ -1,8 +1,13 @@
 package logging
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 )
 
 // NewSimple creates a logger with sensible defaults:
@@ -34,6 +39,7 @@ func NewEasyJSONWithLevel(level Level) Logger {
 // - LOG_FORMAT: text, json (default: text)
 // - LOG_INCLUDE_FILE: true, false (default: false)
 // - LOG_INCLUDE_TIME: true, false (default: true)
+// - LOG_UTC: true, false (default: false)
 func NewFromEnvSimple() Logger {
 	level := getEnvLevel()
 	format := getEnvFormat()
@@ -48,6 +54,7 @@ func NewFromEnvSimple() Logger {
 			IncludeTime(includeTime).
 			Build()).
 		Build()
+	config.Core.UTC = getEnvBool("LOG_UTC", false)
 
 	return NewWithLoggerConfig(config)
 }
@@ -61,6 +68,9 @@ func NewFromEnvSimple() Logger {
 //	    Level(logging.DebugLevel).
 //	    JSON().
 //	    Field("service", "my-app").
+//	    File("/var/log/my-app.log").
+//	    Rotate(100<<20, 24*time.Hour).
+//	    Redact(`password=\S+`).
 //	    Build()
 func NewEasyBuilder() *EasyLoggerBuilder {
 	return &EasyLoggerBuilder{
@@ -79,6 +89,16 @@ type EasyLoggerBuilder struct {
 	includeFile bool
 	includeTime bool
 	fields      map[string]interface{}
+	formatter   Formatter
+	output      Output
+	filePath    string
+	stderr      bool
+	maxSize     int64
+	maxAge      time.Duration
+	redact      []*regexp.Regexp
+	sampleRate  int
+	asyncQueue  int
+	errs        []error
 }
 
 // Level sets the minimum logging level.
@@ -155,22 +175,159 @@ func (b *EasyLoggerBuilder) Fields(fields map[string]interface{}) *EasyLoggerBui
 	return b
 }
 
-// Build creates the logger with the configured options.
+// Formatter sets a custom Formatter, overriding JSON() and Text().
+func (b *EasyLoggerBuilder) Formatter(formatter Formatter) *EasyLoggerBuilder {
+	b.formatter = formatter
+	return b
+}
+
+// Output sets the Output that receives formatted entries (default: stdout).
+// It replaces any destination chosen with File or Stderr.
+func (b *EasyLoggerBuilder) Output(output Output) *EasyLoggerBuilder {
+	b.output = output
+	b.filePath = ""
+	b.stderr = false
+	return b
+}
+
+// File writes entries to the file at path, creating it if necessary.
+// It replaces any destination chosen with Output or Stderr.
+func (b *EasyLoggerBuilder) File(path string) *EasyLoggerBuilder {
+	b.filePath = path
+	b.output = nil
+	b.stderr = false
+	return b
+}
+
+// Stderr writes entries to standard error.
+// It replaces any destination chosen with Output or File.
+func (b *EasyLoggerBuilder) Stderr() *EasyLoggerBuilder {
+	b.stderr = true
+	b.output = nil
+	b.filePath = ""
+	return b
+}
+
+// Rotate enables rotation of the File destination once it exceeds maxSize
+// bytes or maxAge; a zero value disables that limit. With rotation enabled,
+// each file is named after the path with a timestamp before the extension,
+// e.g. app-2006-01-02-15-04-05.log.
+func (b *EasyLoggerBuilder) Rotate(maxSize int64, maxAge time.Duration) *EasyLoggerBuilder {
+	b.maxSize = maxSize
+	b.maxAge = maxAge
+	return b
+}
+
+// Redact masks text matching any of the regular expression patterns.
+// Invalid patterns are skipped by Build and reported by BuildWithError.
+func (b *EasyLoggerBuilder) Redact(patterns ...string) *EasyLoggerBuilder {
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			b.errs = append(b.errs, fmt.Errorf("invalid redact pattern %q: %w", pattern, err))
+			continue
+		}
+		b.redact = append(b.redact, re)
+	}
+	return b
+}
+
+// Sample keeps one in every rate entries below WarnLevel and drops the
+// rest; warnings, errors and critical entries are always kept. A rate of 1
+// or less disables sampling.
+func (b *EasyLoggerBuilder) Sample(rate int) *EasyLoggerBuilder {
+	b.sampleRate = rate
+	return b
+}
+
+// Async writes entries from a background goroutine through a queue of the
+// given size. Entries still queued when the process exits are lost.
+func (b *EasyLoggerBuilder) Async(queueSize int) *EasyLoggerBuilder {
+	b.asyncQueue = queueSize
+	return b
+}
+
+// Build creates the logger with the configured options. If the File
+// destination cannot be opened, the logger falls back to standard error;
+// use BuildWithError to detect this.
 func (b *EasyLoggerBuilder) Build() Logger {
+	logger, err := b.BuildWithError()
+	if err != nil {
+		reportDiagnostic(Diagnostic{
+			Kind:    DiagnosticFallback,
+			Source:  "EasyLoggerBuilder.Build",
+			Message: "some options were not applied",
+			Err:     err,
+		})
+	}
+	return logger
+}
+
+// BuildWithError creates the logger like Build and also reports invalid
+// redact patterns and failures to open the File destination. The returned
+// logger is always usable.
+func (b *EasyLoggerBuilder) BuildWithError() (Logger, error) {
+	errs := append([]error(nil), b.errs...)
+
+	output, err := b.buildOutput()
+	if err != nil {
+		errs = append(errs, err)
+		output = NewWriterOutput(os.Stderr)
+	}
+	if b.sampleRate > 1 {
+		sampling := NewSamplingOutput(output, b.sampleRate)
+		sampling.keepWarnings = true
+		output = sampling
+	}
+	if b.asyncQueue > 0 {
+		output = NewAsyncOutput(output, b.asyncQueue)
+	}
+
+	formatterBuilder := NewFormatterConfig().
+		WithFormat(b.format).
+		IncludeFile(b.includeFile).
+		IncludeTime(b.includeTime).
+		WithCustom(b.formatter)
+	for _, re := range b.redact {
+		formatterBuilder.AddRedactRegex(re)
+	}
+
 	config := NewLoggerConfig().
 		WithLevel(b.level).
-		WithFormatter(NewFormatterConfig().
-			WithFormat(b.format).
-			IncludeFile(b.includeFile).
-			IncludeTime(b.includeTime).
-			Build()).
+		WithFormatter(formatterBuilder.Build()).
 		WithCore(NewCoreConfig().
 			WithLevel(b.level).
 			WithStaticFields(b.fields).
 			Build()).
+		WithCustomOutput(output).
 		Build()
 
-	return NewWithLoggerConfig(config)
+	return NewWithLoggerConfig(config), errors.Join(errs...)
+}
+
+// buildOutput resolves the destination chosen with Output, File or Stderr.
+func (b *EasyLoggerBuilder) buildOutput() (Output, error) {
+	switch {
+	case b.output != nil:
+		return b.output, nil
+	case b.filePath != "" && (b.maxSize > 0 || b.maxAge > 0):
+		return NewRotatingFileOutput(rotatingPattern(b.filePath), b.maxSize, b.maxAge), nil
+	case b.filePath != "":
+		return NewFileOutput(b.filePath)
+	case b.stderr:
+		return NewWriterOutput(os.Stderr), nil
+	default:
+		return NewWriterOutput(os.Stdout), nil
+	}
+}
+
+// rotatingPattern turns a file path into a RotatingFileOutput pattern by
+// inserting a timestamp placeholder before the extension.
+func rotatingPattern(path string) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	escape := func(s string) string { return strings.ReplaceAll(s, "%", "%%") }
+	return escape(base) + "-%s" + escape(ext)
 }
 
 // Helper functions for environment variable parsing
@@ -183,6 +340,7 @@ func getEnvLevel() Level {
 
 	level, ok := ParseLevel(levelStr)
 	if !ok {
+		reportInvalidEnv("LOG_LEVEL", levelStr)
 		return InfoLevel
 	}
 	return level
@@ -193,9 +351,18 @@ func getEnvFormat() OutputFormat {
 	switch formatStr {
 	case "json":
 		return JSONFormat
+	case "auto":
+		return AutoFormat
+	case "gelf":
+		return GELFFormat
+	case "pretty":
+		return PrettyFormat
+	case "stackdriver":
+		return StackdriverFormat
 	case "text", "":
 		return TextFormat
 	default:
+		reportInvalidEnv("LOG_FORMAT", formatStr)
 		return TextFormat
 	}
 }
@@ -207,7 +374,10 @@ func getEnvBool(key string, defaultValue bool) bool {
 		return true
 	case "false", "0", "no":
 		return false
+	case "":
+		return defaultValue
 	default:
+		reportInvalidEnv(key, value)
 		return defaultValue
 	}
 }
@@ -1,8 +1,13 @@
 package logging
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewSimple(t *testing.T) {
@@ -192,3 +197,112 @@ func TestGetEnvBool(t *testing.T) {
 		os.Unsetenv("TEST_BOOL")
 	}
 }
+
+func TestEasyLoggerBuilder_OutputAndFormatter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewEasyBuilder().
+		Formatter(NewJSONFormatter(nil)).
+		Output(NewWriterOutput(buf)).
+		Build()
+
+	logger.Info("easy output")
+
+	if !strings.Contains(buf.String(), `"message":"easy output"`) {
+		t.Errorf("expected JSON entry in custom output, got: %s", buf.String())
+	}
+}
+
+func TestEasyLoggerBuilder_FileAndRedact(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.log")
+	logger, err := NewEasyBuilder().
+		File(filename).
+		Redact(`password=\S+`).
+		BuildWithError()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logger.Info("login password=hunter2")
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "login") || strings.Contains(string(data), "hunter2") {
+		t.Errorf("expected redacted entry in file, got: %s", data)
+	}
+}
+
+func TestEasyLoggerBuilder_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	logger := NewEasyBuilder().
+		File(filepath.Join(dir, "app.log")).
+		Rotate(1024, 0).
+		Build()
+
+	logger.Info("rotating entry")
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if len(matches) != 1 {
+		t.Errorf("expected one timestamped log file, got %v", matches)
+	}
+}
+
+// countingOutput counts writes and is safe for use from the async worker.
+type countingOutput struct {
+	mu     sync.Mutex
+	writes int
+}
+
+func (o *countingOutput) Write([]byte) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.writes++
+	return nil
+}
+
+func (o *countingOutput) Close() error { return nil }
+
+func (o *countingOutput) count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.writes
+}
+
+func TestEasyLoggerBuilder_SampleAndAsync(t *testing.T) {
+	output := &countingOutput{}
+	logger := NewEasyBuilder().
+		Output(output).
+		Sample(2).
+		Async(16).
+		Build()
+
+	for i := 0; i < 10; i++ {
+		logger.Info("sampled")
+	}
+	for i := 0; i < 3; i++ {
+		logger.Error("never sampled")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for output.count() < 8 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if got := output.count(); got != 8 {
+		t.Errorf("expected 5 sampled entries and 3 errors, got %d", got)
+	}
+}
+
+func TestEasyLoggerBuilder_BuildWithErrorFallsBack(t *testing.T) {
+	logger, err := NewEasyBuilder().
+		File(filepath.Join(t.TempDir(), "missing", "\x00", "app.log")).
+		Redact("(").
+		BuildWithError()
+	if err == nil {
+		t.Fatal("expected error for bad file path and invalid pattern")
+	}
+	if logger == nil {
+		t.Fatal("expected a usable fallback logger")
+	}
+}
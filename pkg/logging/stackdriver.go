@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ocrosby/go-logging/pkg/logging/internal"
+)
+
+// Special fields of Google Cloud structured logging, which the logging
+// agents of Cloud Run, GKE and Cloud Functions move from stdout JSON to the
+// LogEntry.
+const (
+	StackdriverSourceLocationKey = "logging.googleapis.com/sourceLocation"
+	StackdriverTraceKey          = "logging.googleapis.com/trace"
+	StackdriverSpanIDKey         = "logging.googleapis.com/spanId"
+)
+
+// StackdriverFormatter writes JSON lines in the structured logging format
+// Google Cloud parses from stdout. Level becomes severity, the timestamp
+// time, the call site logging.googleapis.com/sourceLocation and the trace
+// ID from the context (or a trace_id field) logging.googleapis.com/trace,
+// with a span_id field as logging.googleapis.com/spanId. Use
+// GCPLoggingOutput to write through the Cloud Logging API instead.
+//
+// Example:
+//
+//	formatter := logging.NewStackdriverFormatter(nil, "my-project")
+//	// {"logging.googleapis.com/trace":"projects/my-project/traces/4bf9...","message":"started","severity":"INFO","time":"2025-11-24T10:30:00Z"}
+type StackdriverFormatter struct {
+	config    *FormatterConfig
+	projectID string
+}
+
+// NewStackdriverFormatter creates a formatter for Google Cloud structured
+// logging. Trace IDs are qualified with projectID as
+// projects/PROJECT/traces/TRACE, which Cloud Logging needs to link entries
+// to Cloud Trace; with no projectID they are written as they are.
+func NewStackdriverFormatter(config *FormatterConfig, projectID string) *StackdriverFormatter {
+	if config == nil {
+		config = NewFormatterConfig().Build()
+	}
+	return &StackdriverFormatter{config: config, projectID: projectID}
+}
+
+// Format formats a log entry as a newline-terminated JSON object.
+func (f *StackdriverFormatter) Format(entry LogEntry) ([]byte, error) {
+	data := make(map[string]interface{}, len(entry.Fields)+6)
+	contextFieldsFrom(entry.Context).AddToMap(data)
+	for k, v := range entry.Fields {
+		data[k] = v
+	}
+
+	data["severity"] = gcpSeverity(entry.Level)
+	data["message"] = internal.ApplyRedactionPatterns(entry.Message, f.config.RedactPatterns)
+	if f.config.IncludeTime && !entry.Timestamp.IsZero() {
+		data["time"] = entry.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+	if f.config.IncludeFile && entry.File != "" {
+		file := entry.File
+		if f.config.UseShortFile {
+			file = filepath.Base(file)
+		}
+		data[StackdriverSourceLocationKey] = map[string]string{"file": file, "line": strconv.Itoa(entry.Line)}
+	}
+	if traceID, ok := entryFieldString(entry, "trace_id"); ok && traceID != "" {
+		if f.projectID != "" {
+			traceID = "projects/" + f.projectID + "/traces/" + traceID
+		}
+		data[StackdriverTraceKey] = traceID
+		delete(data, "trace_id")
+	}
+	if spanID, ok := data["span_id"]; ok {
+		data[StackdriverSpanIDKey] = fmt.Sprint(spanID)
+		delete(data, "span_id")
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode structured log entry: %w", err)
+	}
+	return append(payload, '\n'), nil
+}
+
+// WithStackdriverFormat formats entries for Google Cloud structured
+// logging.
+func (b *FormatterConfigBuilder) WithStackdriverFormat() *FormatterConfigBuilder {
+	b.config.Format = StackdriverFormat
+	return b
+}
+
+// WithStackdriverFormat formats entries as Google Cloud structured logging
+// JSON, so Cloud Run and GKE parse the severity, source location and trace
+// of stdout logs. Trace IDs are qualified with the GOOGLE_CLOUD_PROJECT
+// environment variable; use WithCustomFormatter(NewStackdriverFormatter(
+// config, project)) to set another project.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithWriter(os.Stdout).
+//		WithStackdriverFormat().
+//		Build()
+func (b *LoggerConfigBuilder) WithStackdriverFormat() *LoggerConfigBuilder {
+	b.config.Formatter.Format = StackdriverFormat
+	return b
+}
+
+// stackdriverProjectID returns the project trace IDs are qualified with by
+// StackdriverFormat.
+func stackdriverProjectID() string {
+	return os.Getenv("GOOGLE_CLOUD_PROJECT")
+}
+
+// stackdriverSchemaFields describes the keys written by
+// StackdriverFormatter.
+func stackdriverSchemaFields(config *FormatterConfig) []SchemaField {
+	var fields []SchemaField
+	if config.IncludeTime {
+		fields = append(fields, SchemaField{Name: "time", Type: SchemaTypeString, Format: "date-time", Required: true})
+	}
+	var severities []string
+	for _, severity := range schemaLevelNames(gcpSeverity) {
+		if !containsString(severities, severity) {
+			severities = append(severities, severity)
+		}
+	}
+	fields = append(fields,
+		SchemaField{Name: "severity", Type: SchemaTypeString, Enum: severities, Required: true},
+		SchemaField{Name: "message", Type: SchemaTypeString, Required: true},
+		SchemaField{Name: StackdriverTraceKey, Type: SchemaTypeString, Description: "trace ID, as projects/PROJECT/traces/TRACE when the project is known"},
+		SchemaField{Name: StackdriverSpanIDKey, Type: SchemaTypeString, Description: "span ID from the span_id field"},
+	)
+	if config.IncludeFile {
+		fields = append(fields, SchemaField{Name: StackdriverSourceLocationKey, Type: SchemaTypeObject, Description: "file and line of the call site"})
+	}
+	return fields
+}
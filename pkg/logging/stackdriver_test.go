@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStackdriverFormatter_Format(t *testing.T) {
+	formatter := NewStackdriverFormatter(NewFormatterConfig().UseShortFile(true).Build(), "my-project")
+	data, err := formatter.Format(LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 5, time.UTC),
+		Level:     WarnLevel,
+		Message:   "disk almost full",
+		Context:   WithTraceID(context.Background(), "4bf92f3577b34da6a3ce929d0e0e4736"),
+		Fields:    map[string]interface{}{"span_id": "00f067aa0ba902b7", "disk": "sda1"},
+		File:      "/src/app/disk.go",
+		Line:      42,
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, data)
+	}
+	want := map[string]interface{}{
+		"severity":                   "WARNING",
+		"message":                    "disk almost full",
+		"time":                       "2025-11-24T10:30:00.000000005Z",
+		"disk":                       "sda1",
+		StackdriverTraceKey:          "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+		StackdriverSpanIDKey:         "00f067aa0ba902b7",
+		StackdriverSourceLocationKey: map[string]interface{}{"file": "disk.go", "line": "42"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected entry\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestStackdriverFormatter_WithoutProject(t *testing.T) {
+	data, _ := NewStackdriverFormatter(nil, "").Format(LogEntry{
+		Level:   CriticalLevel,
+		Message: "down",
+		Fields:  map[string]interface{}{"trace_id": "abc"},
+	})
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if got["severity"] != "CRITICAL" || got[StackdriverTraceKey] != "abc" || got["trace_id"] != nil {
+		t.Errorf("unexpected entry %s", data)
+	}
+}
+
+func TestStackdriverFormat_YAMLAndSchema(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "env-project")
+
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "stackdriver", IncludeTime: true}); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(builder.WithWriter(buf).Build())
+	logger.ErrorContext(WithTraceID(context.Background(), "t1"), "failed")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.Bytes())
+	}
+	if got["severity"] != "ERROR" || got[StackdriverTraceKey] != "projects/env-project/traces/t1" {
+		t.Errorf("unexpected entry %s", buf.Bytes())
+	}
+
+	schema := SchemaDescriptorLogger(logger)
+	if schema.Format != "stackdriver" || len(schema.Fields) == 0 || schema.Fields[0].Name != "time" {
+		t.Errorf("unexpected schema %+v", schema)
+	}
+}
@@ -0,0 +1,216 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ErrStdCaptureUnsupported is returned by NewStdCapture on platforms where
+// the standard streams cannot be redirected.
+var ErrStdCaptureUnsupported = errors.New("capturing stdout and stderr is not supported on this platform")
+
+// Field values of the stream field on captured entries.
+const (
+	StdoutCaptureStream = "stdout_capture"
+	StderrCaptureStream = "stderr_capture"
+)
+
+// StdCaptureConfig configures a StdCapture.
+type StdCaptureConfig struct {
+	// Stdout captures file descriptor 1, logging each line at INFO.
+	Stdout bool
+	// Stderr captures file descriptor 2, logging each line at WARN.
+	Stderr bool
+	// MaxLineLength splits longer lines into several entries. Defaults to
+	// 64 KiB.
+	MaxLineLength int
+}
+
+// StdCapture redirects the process's standard output and error to pipes
+// and logs each line written to them as an entry with a stream field of
+// "stdout_capture" or "stderr_capture". Unlike reassigning os.Stdout, this
+// also catches writes made directly to the file descriptors, e.g. by C
+// libraries or dependencies that print instead of logging.
+//
+// The logger given to Start must not write to a captured stream, or each
+// entry would be captured again. Write to Stdout or Stderr instead, which
+// keep the original destinations.
+//
+// Example:
+//
+//	capture, err := logging.NewStdCapture(logging.StdCaptureConfig{Stdout: true, Stderr: true})
+//	if err != nil {
+//		return err
+//	}
+//	logger := logging.NewWithLoggerConfig(logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithWriter(capture.Stdout()).
+//		Build())
+//	if err := capture.Start(logger); err != nil {
+//		return err
+//	}
+//	defer capture.Stop()
+type StdCapture struct {
+	config  StdCaptureConfig
+	stdout  *os.File
+	stderr  *os.File
+	mu      sync.Mutex
+	streams []*capturedStream
+	started bool
+}
+
+// capturedStream is one redirected file descriptor.
+type capturedStream struct {
+	fd      int
+	name    string
+	level   Level
+	reader  *os.File
+	writer  *os.File
+	restore func() error
+	done    chan struct{}
+}
+
+// NewStdCapture prepares a capture of the streams selected by config and
+// keeps duplicates of the originals for Stdout and Stderr. Nothing is
+// redirected until Start.
+func NewStdCapture(config StdCaptureConfig) (*StdCapture, error) {
+	if config.MaxLineLength <= 0 {
+		config.MaxLineLength = 64 << 10
+	}
+
+	c := &StdCapture{config: config, stdout: os.Stdout, stderr: os.Stderr}
+	if config.Stdout {
+		stdout, err := dupStd(1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to duplicate stdout: %w", err)
+		}
+		c.stdout = stdout
+	}
+	if config.Stderr {
+		stderr, err := dupStd(2)
+		if err != nil {
+			if config.Stdout {
+				_ = c.stdout.Close()
+			}
+			return nil, fmt.Errorf("failed to duplicate stderr: %w", err)
+		}
+		c.stderr = stderr
+	}
+	return c, nil
+}
+
+// Stdout returns a file writing to the original standard output, which
+// stays valid after Stop.
+func (c *StdCapture) Stdout() *os.File {
+	return c.stdout
+}
+
+// Stderr returns a file writing to the original standard error, which
+// stays valid after Stop.
+func (c *StdCapture) Stderr() *os.File {
+	return c.stderr
+}
+
+// Start redirects the selected streams and logs what is written to them
+// through logger until Stop.
+func (c *StdCapture) Start(logger Logger) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.started {
+		return fmt.Errorf("std capture already started")
+	}
+
+	var streams []*capturedStream
+	if c.config.Stdout {
+		streams = append(streams, &capturedStream{fd: 1, name: StdoutCaptureStream, level: InfoLevel})
+	}
+	if c.config.Stderr {
+		streams = append(streams, &capturedStream{fd: 2, name: StderrCaptureStream, level: WarnLevel})
+	}
+
+	for i, s := range streams {
+		if err := s.redirect(); err != nil {
+			for _, started := range streams[:i] {
+				_ = started.stop()
+			}
+			return fmt.Errorf("failed to capture %s: %w", s.name, err)
+		}
+		go s.forward(logger.WithField("stream", s.name), c.config.MaxLineLength)
+	}
+	c.streams = streams
+	c.started = true
+	return nil
+}
+
+// Stop restores the original streams and waits until everything captured
+// has been logged. It is safe to call more than once.
+func (c *StdCapture) Stop() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+	for _, s := range c.streams {
+		errs = append(errs, s.stop())
+	}
+	c.streams = nil
+	return errors.Join(errs...)
+}
+
+// redirect points the file descriptor at a new pipe.
+func (s *capturedStream) redirect() error {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	restore, err := redirectStd(s.fd, writer)
+	if err != nil {
+		_ = reader.Close()
+		_ = writer.Close()
+		return err
+	}
+	s.reader, s.writer, s.restore = reader, writer, restore
+	s.done = make(chan struct{})
+	return nil
+}
+
+// forward logs each line read from the pipe until it is closed.
+func (s *capturedStream) forward(logger Logger, maxLineLength int) {
+	defer close(s.done)
+	defer s.reader.Close()
+
+	r := bufio.NewReaderSize(s.reader, maxLineLength)
+	for {
+		line, err := r.ReadSlice('\n')
+		if line = bytes.TrimRight(line, "\r\n"); len(line) > 0 {
+			logger.Log(s.level, "%s", line)
+		}
+		if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, os.ErrClosed) {
+				logger.Log(ErrorLevel, "failed to read captured stream: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// stop restores the file descriptor and waits for forward to drain the
+// pipe.
+func (s *capturedStream) stop() error {
+	err := s.restore()
+	// The pipe reaches EOF once the last write end is closed.
+	if closeErr := s.writer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// The descriptor may still hold the pipe open; stop reading.
+		_ = s.reader.Close()
+	}
+	<-s.done
+	return err
+}
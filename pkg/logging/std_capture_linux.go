@@ -0,0 +1,8 @@
+package logging
+
+import "syscall"
+
+// dup2 makes newfd a copy of oldfd. Some Linux architectures only have dup3.
+func dup2(oldfd, newfd int) error {
+	return syscall.Dup3(oldfd, newfd, 0)
+}
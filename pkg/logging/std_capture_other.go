@@ -0,0 +1,15 @@
+//go:build !(unix && !solaris) && !windows
+
+package logging
+
+import "os"
+
+// dupStd is unsupported on this platform.
+func dupStd(int) (*os.File, error) {
+	return nil, ErrStdCaptureUnsupported
+}
+
+// redirectStd is unsupported on this platform.
+func redirectStd(int, *os.File) (func() error, error) {
+	return nil, ErrStdCaptureUnsupported
+}
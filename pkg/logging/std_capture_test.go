@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestStdCapture(t *testing.T) {
+	capture, err := NewStdCapture(StdCaptureConfig{Stdout: true, Stderr: true, MaxLineLength: 16})
+	if errors.Is(err, ErrStdCaptureUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("failed to create capture: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+	if err := capture.Start(logger); err != nil {
+		t.Fatalf("failed to start capture: %v", err)
+	}
+	if err := capture.Start(logger); err == nil {
+		t.Error("expected an error starting twice")
+	}
+
+	fmt.Fprintln(os.Stderr, "libfoo: warning")
+	fmt.Fprintln(os.Stdout, "progress 50%")
+	fmt.Fprint(os.Stderr, strings.Repeat("x", 20)+"\n")
+	fmt.Fprint(os.Stderr, "no newline")
+
+	if err := capture.Stop(); err != nil {
+		t.Fatalf("failed to stop capture: %v", err)
+	}
+	if err := capture.Stop(); err != nil {
+		t.Errorf("expected a second Stop to do nothing, got %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		`"level":"WARN","message":"libfoo: warning"`,
+		`"message":"progress 50%"`,
+		`"stream":"stdout_capture"`,
+		`"message":"xxxxxxxxxxxxxxxx"`,
+		`"message":"xxxx"`,
+		`"message":"no newline"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %s in:\n%s", want, output)
+		}
+	}
+	if got := strings.Count(output, `"stream":"stderr_capture"`); got != 4 {
+		t.Errorf("expected 4 stderr entries, got %d:\n%s", got, output)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build unix && !solaris
+
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+// dupStd returns a new file for the stream open on fd.
+func dupStd(fd int) (*os.File, error) {
+	dup, err := syscall.Dup(fd)
+	if err != nil {
+		return nil, err
+	}
+	syscall.CloseOnExec(dup)
+	name := "/dev/stdout"
+	if fd == 2 {
+		name = "/dev/stderr"
+	}
+	return os.NewFile(uintptr(dup), name), nil
+}
+
+// redirectStd points fd at w and returns a function pointing it back.
+func redirectStd(fd int, w *os.File) (restore func() error, err error) {
+	saved, err := syscall.Dup(fd)
+	if err != nil {
+		return nil, err
+	}
+	syscall.CloseOnExec(saved)
+	if err := dup2(int(w.Fd()), fd); err != nil {
+		_ = syscall.Close(saved)
+		return nil, err
+	}
+	return func() error {
+		defer syscall.Close(saved)
+		return dup2(saved, fd)
+	}, nil
+}
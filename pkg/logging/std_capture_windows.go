@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"os"
+	"syscall"
+)
+
+var procSetStdHandle = syscall.NewLazyDLL("kernel32.dll").NewProc("SetStdHandle")
+
+// stdStream returns the os variable and standard handle ID for fd.
+func stdStream(fd int) (*(*os.File), int32) {
+	if fd == 2 {
+		return &os.Stderr, syscall.STD_ERROR_HANDLE
+	}
+	return &os.Stdout, syscall.STD_OUTPUT_HANDLE
+}
+
+// setStdHandle replaces the process's standard handle id.
+func setStdHandle(id int32, handle uintptr) error {
+	if r, _, err := procSetStdHandle.Call(uintptr(uint32(id)), handle); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// dupStd returns a new file for the stream open on fd.
+func dupStd(fd int) (*os.File, error) {
+	stream, _ := stdStream(fd)
+	process, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return nil, err
+	}
+	var dup syscall.Handle
+	if err := syscall.DuplicateHandle(process, syscall.Handle((*stream).Fd()), process, &dup, 0, false, syscall.DUPLICATE_SAME_ACCESS); err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(dup), (*stream).Name()), nil
+}
+
+// redirectStd points the standard handle for fd, and os.Stdout or
+// os.Stderr, at w, and returns a function pointing them back. Code that
+// cached the handle before, such as an initialized C runtime, keeps
+// writing to the original.
+func redirectStd(fd int, w *os.File) (restore func() error, err error) {
+	stream, id := stdStream(fd)
+	previous := *stream
+	if err := setStdHandle(id, w.Fd()); err != nil {
+		return nil, err
+	}
+	*stream = w
+	return func() error {
+		*stream = previous
+		return setStdHandle(id, previous.Fd())
+	}, nil
+}
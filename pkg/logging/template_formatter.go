@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ocrosby/go-logging/pkg/logging/internal"
+)
+
+// TemplateEntry is the data a TemplateFormatter's template is executed
+// with. Level renders as its name and Fields ranges in key order. File is
+// empty unless file information is enabled.
+type TemplateEntry struct {
+	Timestamp     time.Time
+	Level         Level
+	Message       string
+	Fields        map[string]interface{}
+	File          string
+	Line          int
+	TraceID       string
+	RequestID     string
+	CorrelationID string
+}
+
+// templateFuncs are the functions available to formatter templates.
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// TemplateFormatter renders each entry through a text/template, so log
+// lines can match a legacy format exactly. The template is executed with a
+// TemplateEntry and may call json, upper and lower; a newline is added to
+// output that does not end with one.
+//
+// Example:
+//
+//	formatter, err := logging.NewTemplateFormatter(nil,
+//		`{{.Timestamp.Format "2006-01-02 15:04:05"}} [{{.Level}}] {{.Message}}{{range $k, $v := .Fields}} {{$k}}={{$v}}{{end}}`)
+//	if err != nil {
+//		return err
+//	}
+//	// 2025-11-24 10:30:00 [INFO] order placed order_id=42 user=alice
+type TemplateFormatter struct {
+	config   *FormatterConfig
+	template *template.Template
+}
+
+// NewTemplateFormatter creates a formatter that renders entries through
+// text, which is parsed as a text/template.
+func NewTemplateFormatter(config *FormatterConfig, text string) (*TemplateFormatter, error) {
+	if config == nil {
+		config = NewFormatterConfig().Build()
+	}
+	tmpl, err := template.New("entry").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse log template: %w", err)
+	}
+	return &TemplateFormatter{config: config, template: tmpl}, nil
+}
+
+// Format renders a log entry through the template.
+func (f *TemplateFormatter) Format(entry LogEntry) ([]byte, error) {
+	contextFields := contextFieldsFrom(entry.Context)
+	data := TemplateEntry{
+		Timestamp:     entry.Timestamp,
+		Level:         entry.Level,
+		Message:       internal.ApplyRedactionPatterns(entry.Message, f.config.RedactPatterns),
+		Fields:        entry.Fields,
+		TraceID:       contextFields.TraceID,
+		RequestID:     contextFields.RequestID,
+		CorrelationID: contextFields.CorrelationID,
+	}
+	if f.config.IncludeFile {
+		data.File, data.Line = entry.File, entry.Line
+		if f.config.UseShortFile {
+			data.File = filepath.Base(data.File)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.template.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute log template: %w", err)
+	}
+	if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// WithTemplateFormat renders entries through the text/template text. An
+// invalid template is reported and leaves the format unchanged.
+func (b *FormatterConfigBuilder) WithTemplateFormat(text string) *FormatterConfigBuilder {
+	if _, err := NewTemplateFormatter(nil, text); err != nil {
+		reportInvalidConfig("WithTemplateFormat", "ignoring invalid template", err)
+		return b
+	}
+	b.config.Format = TemplateFormat
+	b.config.Template = text
+	return b
+}
+
+// WithTemplateFormat renders entries through the text/template text, with
+// a TemplateEntry as data. An invalid template is reported and leaves the
+// format unchanged.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithTemplateFormat(`{{.Level}}|{{.Message}}{{with .RequestID}}|{{.}}{{end}}`).
+//		Build()
+func (b *LoggerConfigBuilder) WithTemplateFormat(text string) *LoggerConfigBuilder {
+	if _, err := NewTemplateFormatter(nil, text); err != nil {
+		reportInvalidConfig("WithTemplateFormat", "ignoring invalid template", err)
+		return b
+	}
+	b.config.Formatter.Format = TemplateFormat
+	b.config.Formatter.Template = text
+	return b
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateFormatter_Format(t *testing.T) {
+	formatter, err := NewTemplateFormatter(NewFormatterConfig().AddRedactPattern(`\d{4}-\d{4}`).Build(),
+		`{{.Timestamp.Format "2006-01-02 15:04:05"}} [{{.Level}}] {{.Message}}{{range $k, $v := .Fields}} {{$k}}={{$v}}{{end}} file={{.File}}:{{.Line}} req={{.RequestID}} {{json .Fields.tags}} {{lower "ABC"}}`)
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter returned error: %v", err)
+	}
+	data, err := formatter.Format(LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 0, time.UTC),
+		Level:     WarnLevel,
+		Message:   "card 1234-5678 declined",
+		Fields:    map[string]interface{}{"user": "alice", "order_id": 42, "tags": []string{"a"}},
+		Context:   WithRequestID(context.Background(), "req-1"),
+		File:      "/src/app/orders.go",
+		Line:      17,
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	want := `2025-11-24 10:30:00 [WARN] card [REDACTED] declined order_id=42 tags=[a] user=alice file=orders.go:17 req=req-1 ["a"] abc` + "\n"
+	if string(data) != want {
+		t.Errorf("unexpected line\n got: %q\nwant: %q", data, want)
+	}
+}
+
+func TestTemplateFormatter_Errors(t *testing.T) {
+	if _, err := NewTemplateFormatter(nil, "{{.Message"); err == nil {
+		t.Error("expected a parse error")
+	}
+
+	formatter, err := NewTemplateFormatter(nil, "{{.Missing}}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter returned error: %v", err)
+	}
+	if _, err := formatter.Format(LogEntry{}); err == nil {
+		t.Error("expected an execution error for an unknown field")
+	}
+}
+
+func TestWithTemplateFormat(t *testing.T) {
+	diagnostics := captureDiagnostics(t)
+
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithWriter(buf).
+		WithTemplateFormat("{{.Level}}|{{.Message}}").
+		Build())
+	logger.Info("hello")
+	if buf.String() != "INFO|hello\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+
+	config := NewLoggerConfig().WithJSONFormat().WithTemplateFormat("{{").Build()
+	if config.Formatter.Format != JSONFormat {
+		t.Errorf("expected an invalid template to leave the format unchanged, got %v", config.Formatter.Format)
+	}
+	if got := diagnostics(); len(got) != 1 || got[0].Source != "WithTemplateFormat" {
+		t.Errorf("expected one WithTemplateFormat diagnostic, got %+v", got)
+	}
+}
+
+func TestTemplateFormat_YAML(t *testing.T) {
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "template", Template: "{{.Level}} {{.Message}}"}); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	config := builder.Build()
+	if config.Formatter.Format != TemplateFormat || config.Formatter.Template != "{{.Level}} {{.Message}}" {
+		t.Errorf("unexpected formatter config %+v", config.Formatter)
+	}
+
+	for _, yamlConfig := range []*YAMLConfig{
+		{Format: "template", Template: "{{.Level"},
+		{Format: "json", Template: "{{.Level}}"},
+	} {
+		err := configureFormatterFromYAML(NewLoggerConfig(), yamlConfig)
+		if err == nil || !strings.Contains(err.Error(), "template") {
+			t.Errorf("expected a template error for %+v, got %v", yamlConfig, err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"io"
+	"os"
+)
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ColorEnabled reports whether output to w should be colored. A non-empty
+// FORCE_COLOR other than "0" or "false" forces colors and a non-empty
+// NO_COLOR (https://no-color.org) disables them; otherwise w must be a
+// terminal other than TERM=dumb. On Windows, ANSI escape processing is
+// enabled on the console when possible.
+func ColorEnabled(w io.Writer) bool {
+	if force := os.Getenv("FORCE_COLOR"); force != "" {
+		return force != "0" && force != "false"
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	if !isTerminal(w) {
+		return false
+	}
+	return enableVirtualTerminal(w.(*os.File))
+}
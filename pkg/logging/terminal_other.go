@@ -0,0 +1,11 @@
+//go:build !windows
+
+package logging
+
+import "os"
+
+// enableVirtualTerminal reports whether the terminal f writes to
+// interprets ANSI escape sequences, which terminals outside Windows do.
+func enableVirtualTerminal(*os.File) bool {
+	return true
+}
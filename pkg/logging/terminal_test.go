@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestColorEnabled(t *testing.T) {
+	tests := []struct {
+		name       string
+		forceColor string
+		noColor    string
+		want       bool
+	}{
+		{name: "not a terminal", want: false},
+		{name: "force color", forceColor: "1", want: true},
+		{name: "force color disabled", forceColor: "0", want: false},
+		{name: "force color false", forceColor: "false", want: false},
+		{name: "no color", noColor: "1", want: false},
+		{name: "force color wins over no color", forceColor: "1", noColor: "1", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+			t.Setenv("NO_COLOR", tt.noColor)
+			if got := ColorEnabled(&bytes.Buffer{}); got != tt.want {
+				t.Errorf("ColorEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewConsoleFormatterFor(t *testing.T) {
+	entry := LogEntry{Level: ErrorLevel, Message: "failed"}
+
+	t.Setenv("FORCE_COLOR", "1")
+	data, _ := NewConsoleFormatterFor(nil, &bytes.Buffer{}).Format(entry)
+	if !strings.Contains(string(data), "\033[") {
+		t.Errorf("expected colored output with FORCE_COLOR, got %q", data)
+	}
+
+	t.Setenv("FORCE_COLOR", "")
+	data, _ = NewConsoleFormatterFor(nil, &bytes.Buffer{}).Format(entry)
+	if strings.Contains(string(data), "\033[") {
+		t.Errorf("expected plain output to a buffer, got %q", data)
+	}
+}
+
+func TestPrettyFormat_ForceColor(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "1")
+	buf := &bytes.Buffer{}
+	NewWithLoggerConfig(NewLoggerConfig().WithWriter(buf).WithPrettyFormat().Build()).Error("failed")
+	if !strings.Contains(buf.String(), "\033[") {
+		t.Errorf("expected colored pretty output with FORCE_COLOR, got %q", buf.String())
+	}
+}
@@ -0,0 +1,39 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTestFormatter_Format(t *testing.T) {
+	formatter := NewTestFormatter(nil, false)
+	ctx := WithTraceID(WithTestName(context.Background(), "TestCheckout/declined"), "t-1")
+
+	data, err := formatter.Format(LogEntry{
+		Level:   WarnLevel,
+		Message: "payment declined",
+		Fields:  map[string]interface{}{"order": "o-1", "amount": 42},
+		Context: ctx,
+	})
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	want := "[TestCheckout/declined] WARN     " + "payment declined" + strings.Repeat(" ", testMessageWidth-len("payment declined")) +
+		" amount=42 order=o-1 trace_id=t-1\n"
+	if string(data) != want {
+		t.Errorf("Format() = %q, want %q", data, want)
+	}
+
+	data, _ = formatter.Format(LogEntry{Level: InfoLevel, Message: "no fields"})
+	if string(data) != "INFO     no fields\n" {
+		t.Errorf("Format() = %q", data)
+	}
+}
+
+func TestTestFormatter_Colors(t *testing.T) {
+	data, _ := NewTestFormatter(nil, true).Format(LogEntry{Level: ErrorLevel, Message: "boom"})
+	if !strings.HasPrefix(string(data), "\033[31mERROR") {
+		t.Errorf("expected a colored level, got %q", data)
+	}
+}
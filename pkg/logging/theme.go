@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+)
+
+// consoleColors are the ANSI escapes of the color names a theme accepts.
+var consoleColors = map[string]string{
+	"black":        "\033[30m",
+	"red":          "\033[31m",
+	"green":        "\033[32m",
+	"yellow":       "\033[33m",
+	"blue":         "\033[34m",
+	"magenta":      "\033[35m",
+	"cyan":         "\033[36m",
+	"white":        "\033[37m",
+	"gray":         "\033[90m",
+	"bold-red":     "\033[1;31m",
+	"bold-green":   "\033[1;32m",
+	"bold-yellow":  "\033[1;33m",
+	"bold-blue":    "\033[1;34m",
+	"bold-magenta": "\033[1;35m",
+	"bold-cyan":    "\033[1;36m",
+	"bold-white":   "\033[1;37m",
+	"none":         "",
+}
+
+// ParseConsoleColor returns the ANSI escape for a color name, such as
+// "yellow", "gray" or "bold-red", or "none" for no color. An ANSI escape
+// sequence is returned as it is.
+func ParseConsoleColor(name string) (string, error) {
+	if strings.HasPrefix(name, "\033[") {
+		return name, nil
+	}
+	color, ok := consoleColors[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("unknown color %q", name)
+	}
+	return color, nil
+}
+
+// levelName returns the display name of l: its LevelNames entry, or its
+// standard name.
+func (c *FormatterConfig) levelName(l Level) string {
+	if name, ok := c.LevelNames[l]; ok {
+		return name
+	}
+	return l.String()
+}
+
+// levelColors returns the console colors of each level, with LevelColors
+// replacing the defaults.
+func (c *FormatterConfig) levelColors() map[Level]string {
+	if len(c.LevelColors) == 0 {
+		return consoleLevelColors
+	}
+	colors := make(map[Level]string, len(consoleLevelColors))
+	for l, color := range consoleLevelColors {
+		colors[l] = color
+	}
+	for l, color := range c.LevelColors {
+		colors[l] = color
+	}
+	return colors
+}
+
+// WithLevelNames replaces the displayed names of levels.
+func (b *FormatterConfigBuilder) WithLevelNames(names map[Level]string) *FormatterConfigBuilder {
+	b.config.LevelNames = names
+	return b
+}
+
+// WithLevelColors replaces the ANSI escapes levels are colored with.
+func (b *FormatterConfigBuilder) WithLevelColors(colors map[Level]string) *FormatterConfigBuilder {
+	b.config.LevelColors = colors
+	return b
+}
+
+// WithLevelNames replaces the displayed names of levels in JSON, text,
+// console and pretty output, such as "WARNING" for WarnLevel. Levels
+// without a name keep their own.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithLevelNames(map[logging.Level]string{
+//			logging.WarnLevel:     "WARNING",
+//			logging.CriticalLevel: "FATAL",
+//		}).
+//		Build()
+func (b *LoggerConfigBuilder) WithLevelNames(names map[Level]string) *LoggerConfigBuilder {
+	b.config.Formatter.LevelNames = names
+	return b
+}
+
+// WithLevelColors replaces the ANSI escapes levels are colored with in
+// console and pretty output. ParseConsoleColor turns color names into
+// escapes.
+func (b *LoggerConfigBuilder) WithLevelColors(colors map[Level]string) *LoggerConfigBuilder {
+	b.config.Formatter.LevelColors = colors
+	return b
+}
+
+// configureThemeFromYAML sets the level names and colors of a YAML theme,
+// keyed by level name.
+func configureThemeFromYAML(builder *LoggerConfigBuilder, theme map[string]YAMLLevelTheme) error {
+	if len(theme) == 0 {
+		return nil
+	}
+	names := make(map[Level]string)
+	colors := make(map[Level]string)
+	for levelName, levelTheme := range theme {
+		level, ok := ParseLevel(levelName)
+		if !ok {
+			return fmt.Errorf("invalid theme level: %s", levelName)
+		}
+		if levelTheme.Name != "" {
+			names[level] = levelTheme.Name
+		}
+		if levelTheme.Color != "" {
+			color, err := ParseConsoleColor(levelTheme.Color)
+			if err != nil {
+				return fmt.Errorf("invalid theme color for %s: %w", levelName, err)
+			}
+			colors[level] = color
+		}
+	}
+	builder.WithLevelNames(names).WithLevelColors(colors)
+	return nil
+}
+
+// themeToYAML returns the YAML theme of config's level names and colors.
+func themeToYAML(config *FormatterConfig) map[string]YAMLLevelTheme {
+	if len(config.LevelNames) == 0 && len(config.LevelColors) == 0 {
+		return nil
+	}
+	theme := make(map[string]YAMLLevelTheme)
+	for l := TraceLevel; l <= CriticalLevel; l++ {
+		name, hasName := config.LevelNames[l]
+		color, hasColor := config.LevelColors[l]
+		if hasColor && color == "" {
+			color = "none"
+		}
+		if hasName || hasColor {
+			theme[strings.ToLower(l.String())] = YAMLLevelTheme{Name: name, Color: color}
+		}
+	}
+	return theme
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLevelNames(t *testing.T) {
+	config := NewFormatterConfig().
+		IncludeTime(false).
+		IncludeFile(false).
+		WithLevelNames(map[Level]string{WarnLevel: "WARNING", CriticalLevel: "FATAL"}).
+		Build()
+	entry := LogEntry{Level: WarnLevel, Message: "slow"}
+
+	tests := []struct {
+		name      string
+		formatter Formatter
+		want      string
+	}{
+		{"json", NewJSONFormatter(config), `"level":"WARNING"`},
+		{"text", NewTextFormatter(config), "[WARNING] slow"},
+		{"console", NewConsoleFormatter(config, false), "[WARNING] slow"},
+		{"pretty", NewPrettyFormatter(config, false), "WARNING: slow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := tt.formatter.Format(entry)
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("expected %q in %q", tt.want, data)
+			}
+		})
+	}
+
+	data, _ := NewTextFormatter(config).Format(LogEntry{Level: InfoLevel, Message: "ok"})
+	if !strings.HasPrefix(string(data), "[INFO] ok") {
+		t.Errorf("expected levels without a name to keep theirs, got %q", data)
+	}
+}
+
+func TestLevelColors(t *testing.T) {
+	config := NewFormatterConfig().
+		IncludeTime(false).
+		WithLevelColors(map[Level]string{ErrorLevel: "\033[1;31m", InfoLevel: ""}).
+		Build()
+	formatter := NewConsoleFormatter(config, true)
+
+	data, _ := formatter.Format(LogEntry{Level: ErrorLevel, Message: "failed"})
+	if want := "[\033[1;31mERROR\033[0m] failed\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+	data, _ = formatter.Format(LogEntry{Level: InfoLevel, Message: "ok"})
+	if want := "[INFO] ok\n"; string(data) != want {
+		t.Errorf("got %q, want %q", data, want)
+	}
+	data, _ = formatter.Format(LogEntry{Level: WarnLevel, Message: "slow"})
+	if !strings.Contains(string(data), consoleLevelColors[WarnLevel]) {
+		t.Errorf("expected the default warn color, got %q", data)
+	}
+}
+
+func TestTheme_YAML(t *testing.T) {
+	builder := NewLoggerConfig()
+	err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "text", Theme: map[string]YAMLLevelTheme{
+		"warn":     {Name: "WARNING", Color: "bold-yellow"},
+		"critical": {Name: "FATAL"},
+		"info":     {Color: "none"},
+	}})
+	if err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	config := builder.Build().Formatter
+	if config.LevelNames[WarnLevel] != "WARNING" || config.LevelNames[CriticalLevel] != "FATAL" {
+		t.Errorf("unexpected level names %v", config.LevelNames)
+	}
+	if config.LevelColors[WarnLevel] != "\033[1;33m" || config.LevelColors[InfoLevel] != "" {
+		t.Errorf("unexpected level colors %q", config.LevelColors)
+	}
+	if theme := themeToYAML(config); theme["info"].Color != "none" || theme["warn"].Name != "WARNING" {
+		t.Errorf("unexpected saved theme %+v", theme)
+	}
+
+	for _, theme := range []map[string]YAMLLevelTheme{
+		{"loud": {Name: "LOUD"}},
+		{"warn": {Color: "chartreuse"}},
+	} {
+		err := configureFormatterFromYAML(NewLoggerConfig(), &YAMLConfig{Format: "text", Theme: theme})
+		if err == nil || !strings.Contains(err.Error(), "theme") {
+			t.Errorf("expected a theme error for %v, got %v", theme, err)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// TimePrecision is the number of sub-second digits in the default
+// timestamp layouts of JSON and text output.
+type TimePrecision int
+
+const (
+	// SecondPrecision writes whole seconds, the default.
+	SecondPrecision TimePrecision = iota
+	// MillisecondPrecision writes three sub-second digits.
+	MillisecondPrecision
+	// MicrosecondPrecision writes six sub-second digits.
+	MicrosecondPrecision
+	// NanosecondPrecision writes nine sub-second digits.
+	NanosecondPrecision
+)
+
+// timePrecisionNames are the names of each TimePrecision in configuration.
+var timePrecisionNames = map[TimePrecision]string{
+	SecondPrecision:      "second",
+	MillisecondPrecision: "millisecond",
+	MicrosecondPrecision: "microsecond",
+	NanosecondPrecision:  "nanosecond",
+}
+
+// String returns the name of p, such as "millisecond".
+func (p TimePrecision) String() string {
+	if name, ok := timePrecisionNames[p]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseTimePrecision returns the precision named by name: "second",
+// "millisecond", "microsecond" or "nanosecond", or "s", "ms", "us" or "ns".
+func ParseTimePrecision(name string) (TimePrecision, bool) {
+	switch strings.ToLower(name) {
+	case "second", "s":
+		return SecondPrecision, true
+	case "millisecond", "ms":
+		return MillisecondPrecision, true
+	case "microsecond", "us":
+		return MicrosecondPrecision, true
+	case "nanosecond", "ns":
+		return NanosecondPrecision, true
+	}
+	return SecondPrecision, false
+}
+
+// layout returns layout with p's sub-second digits after its seconds.
+// Trailing zeros are kept, so timestamps sort as text.
+func (p TimePrecision) layout(layout string) string {
+	digits := [...]string{"", ".000", ".000000", ".000000000"}
+	if p <= SecondPrecision || int(p) >= len(digits) {
+		return layout
+	}
+	return strings.Replace(layout, "05", "05"+digits[p], 1)
+}
+
+// formatTime formats t with the configured TimeFormat and TimeZone. A
+// formatter passes its own layout, and the zone it converts to or nil for
+// the entry's own, as the defaults.
+func (c *FormatterConfig) formatTime(t time.Time, layout string, zone *time.Location) string {
+	return string(c.appendTime(nil, t, layout, zone))
+}
+
+// appendTime is formatTime appending to buf.
+func (c *FormatterConfig) appendTime(buf []byte, t time.Time, layout string, zone *time.Location) []byte {
+	if c.TimeFormat != "" {
+		layout = c.TimeFormat
+	}
+	if c.TimeZone != nil {
+		zone = c.TimeZone
+	}
+	if zone != nil {
+		t = t.In(zone)
+	}
+	return t.AppendFormat(buf, layout)
+}
+
+// replaceSlogTime returns a slog.HandlerOptions.ReplaceAttr function that
+// applies TimeFormat, TimeZone and TimePrecision to the handler's time, or
+// nil when none is set.
+func (c *FormatterConfig) replaceSlogTime() func([]string, slog.Attr) slog.Attr {
+	if c.TimeFormat == "" && c.TimeZone == nil && c.TimePrecision == SecondPrecision {
+		return nil
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 || a.Key != slog.TimeKey || a.Value.Kind() != slog.KindTime {
+			return a
+		}
+		t := a.Value.Time()
+		if c.TimeZone != nil {
+			t = t.In(c.TimeZone)
+		}
+		switch {
+		case c.TimeFormat != "":
+			return slog.String(a.Key, t.Format(c.TimeFormat))
+		case c.TimePrecision != SecondPrecision:
+			return slog.String(a.Key, t.Format(c.TimePrecision.layout(time.RFC3339)))
+		}
+		return slog.Time(a.Key, t)
+	}
+}
+
+// chainReplaceAttr returns a ReplaceAttr function applying each non-nil
+// function in order, or nil when all are nil.
+func chainReplaceAttr(fns ...func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	var chain []func([]string, slog.Attr) slog.Attr
+	for _, fn := range fns {
+		if fn != nil {
+			chain = append(chain, fn)
+		}
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, fn := range chain {
+			a = fn(groups, a)
+		}
+		return a
+	}
+}
+
+// parseTimeZone returns the location named by name: "UTC", "Local" or an
+// IANA zone such as "America/New_York".
+func parseTimeZone(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "utc":
+		return time.UTC, nil
+	case "local":
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// WithTimeFormat sets the Go time layout of timestamps, replacing each
+// formatter's default.
+func (b *FormatterConfigBuilder) WithTimeFormat(layout string) *FormatterConfigBuilder {
+	b.config.TimeFormat = layout
+	return b
+}
+
+// WithTimePrecision sets the sub-second digits of JSON and text
+// timestamps.
+func (b *FormatterConfigBuilder) WithTimePrecision(precision TimePrecision) *FormatterConfigBuilder {
+	b.config.TimePrecision = precision
+	return b
+}
+
+// WithTimeZone converts timestamps to loc before formatting.
+func (b *FormatterConfigBuilder) WithTimeZone(loc *time.Location) *FormatterConfigBuilder {
+	b.config.TimeZone = loc
+	return b
+}
+
+// WithTimeFormat sets the Go time layout of timestamps in JSON, text,
+// console, pretty, Common Log Format and CSV output and, with UseSlog, of
+// the slog handler's time.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithTimeFormat("2006-01-02 15:04:05.000").
+//		WithTimeZone(time.Local).
+//		Build()
+func (b *LoggerConfigBuilder) WithTimeFormat(layout string) *LoggerConfigBuilder {
+	b.config.Formatter.TimeFormat = layout
+	return b
+}
+
+// WithTimeZone converts timestamps to loc before formatting. JSON output
+// is otherwise in UTC and other formats use the entry's own zone.
+func (b *LoggerConfigBuilder) WithTimeZone(loc *time.Location) *LoggerConfigBuilder {
+	b.config.Formatter.TimeZone = loc
+	return b
+}
+
+// WithTimePrecision sets the sub-second digits of timestamps in JSON and
+// text output and, with UseSlog, of the slog handler's time, so that
+// high-frequency entries keep their order. A TimeFormat layout takes
+// precedence.
+//
+// Example:
+//
+//	config := logging.NewLoggerConfig().
+//		WithJSONFormat().
+//		WithTimePrecision(logging.MicrosecondPrecision).
+//		Build()
+//	// {"timestamp":"2025-11-24T10:30:00.123456Z",...}
+func (b *LoggerConfigBuilder) WithTimePrecision(precision TimePrecision) *LoggerConfigBuilder {
+	b.config.Formatter.TimePrecision = precision
+	return b
+}
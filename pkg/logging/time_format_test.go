@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeFormat_Formatters(t *testing.T) {
+	zone := time.FixedZone("EST", -5*3600)
+	entry := LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 0, time.UTC),
+		Level:     InfoLevel,
+		Message:   "started",
+	}
+
+	tests := []struct {
+		name      string
+		formatter Formatter
+		want      string
+	}{
+		{
+			name:      "json default",
+			formatter: NewJSONFormatter(NewFormatterConfig().IncludeFile(false).Build()),
+			want:      `"timestamp":"2025-11-24T10:30:00Z"`,
+		},
+		{
+			name:      "json layout and zone",
+			formatter: NewJSONFormatter(NewFormatterConfig().IncludeFile(false).WithTimeFormat("2006-01-02 15:04:05 MST").WithTimeZone(zone).Build()),
+			want:      `"timestamp":"2025-11-24 05:30:00 EST"`,
+		},
+		{
+			name:      "text zone",
+			formatter: NewTextFormatter(NewFormatterConfig().IncludeFile(false).WithTimeZone(zone).Build()),
+			want:      "2025/11/24 05:30:00 [INFO] started",
+		},
+		{
+			name:      "console layout",
+			formatter: NewConsoleFormatter(NewFormatterConfig().WithTimeFormat(time.Kitchen).Build(), false),
+			want:      "10:30AM [INFO] started",
+		},
+		{
+			name:      "common log zone",
+			formatter: NewCommonLogFormatter(NewFormatterConfig().WithTimeZone(zone).Build()),
+			want:      "[24/Nov/2025:05:30:00 -0500]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.formatter.Format(entry)
+			if err != nil {
+				t.Fatalf("Format returned error: %v", err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("expected %q in %q", tt.want, data)
+			}
+		})
+	}
+}
+
+func TestTimeFormat_Slog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithWriter(buf).
+		WithJSONFormat().
+		WithTimeFormat("2006-01-02").
+		WithTimeZone(time.UTC).
+		WithFieldKeys(FieldKeys{Timestamp: "ts"}).
+		UseSlog(true).
+		Build())
+	logger.Info("started")
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.Bytes())
+	}
+	if ts, _ := got["ts"].(string); len(ts) != len("2006-01-02") {
+		t.Errorf("expected a date under ts, got %s", buf.Bytes())
+	}
+}
+
+func TestTimeFormat_YAML(t *testing.T) {
+	builder := NewLoggerConfig()
+	if err := configureFormatterFromYAML(builder, &YAMLConfig{Format: "json", TimeFormat: time.RFC3339Nano, TimeZone: "utc"}); err != nil {
+		t.Fatalf("failed to configure formatter: %v", err)
+	}
+	config := builder.Build()
+	if config.Formatter.TimeFormat != time.RFC3339Nano || config.Formatter.TimeZone != time.UTC {
+		t.Errorf("unexpected formatter config %+v", config.Formatter)
+	}
+
+	err := configureFormatterFromYAML(NewLoggerConfig(), &YAMLConfig{Format: "json", TimeZone: "Mars/Olympus_Mons"})
+	if err == nil || !strings.Contains(err.Error(), "time_zone") {
+		t.Errorf("expected a time_zone error, got %v", err)
+	}
+}
+
+func TestTimePrecision(t *testing.T) {
+	entry := LogEntry{
+		Timestamp: time.Date(2025, 11, 24, 10, 30, 0, 120_000_000, time.UTC),
+		Level:     InfoLevel,
+		Message:   "tick",
+	}
+	tests := []struct {
+		precision TimePrecision
+		json      string
+		text      string
+	}{
+		{SecondPrecision, "2025-11-24T10:30:00Z", "2025/11/24 10:30:00 "},
+		{MillisecondPrecision, "2025-11-24T10:30:00.120Z", "2025/11/24 10:30:00.120 "},
+		{MicrosecondPrecision, "2025-11-24T10:30:00.120000Z", "2025/11/24 10:30:00.120000 "},
+		{NanosecondPrecision, "2025-11-24T10:30:00.120000000Z", "2025/11/24 10:30:00.120000000 "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.precision.String(), func(t *testing.T) {
+			config := NewFormatterConfig().IncludeFile(false).WithTimePrecision(tt.precision).Build()
+			data, _ := NewJSONFormatter(config).Format(entry)
+			if !strings.Contains(string(data), `"timestamp":"`+tt.json+`"`) {
+				t.Errorf("expected JSON timestamp %s in %s", tt.json, data)
+			}
+			data, _ = NewTextFormatter(config).Format(entry)
+			if !strings.HasPrefix(string(data), tt.text) {
+				t.Errorf("expected text timestamp %q in %q", tt.text, data)
+			}
+		})
+	}
+
+	for _, name := range []string{"ms", "Millisecond"} {
+		if p, ok := ParseTimePrecision(name); !ok || p != MillisecondPrecision {
+			t.Errorf("ParseTimePrecision(%q) = %v, %v", name, p, ok)
+		}
+	}
+	err := configureFormatterFromYAML(NewLoggerConfig(), &YAMLConfig{Format: "json", TimePrecision: "minute"})
+	if err == nil || !strings.Contains(err.Error(), "time_precision") {
+		t.Errorf("expected a time_precision error, got %v", err)
+	}
+}
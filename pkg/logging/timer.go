@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"context"
+	"runtime/trace"
+	"time"
+)
+
+// Timer measures the duration of an operation using the monotonic clock, so
+// results are unaffected by wall-clock adjustments. It deliberately ignores
+// the configured Clock.
+//
+// Example:
+//
+//	timer := logging.StartTimer(ctx)
+//	rows, err := db.QueryContext(ctx, query)
+//	timer.Stop(logger, "db_query")
+type Timer struct {
+	ctx   context.Context
+	start time.Time
+	// end finishes the runtime/trace task or region, if any.
+	end func()
+}
+
+// StartTimer starts a Timer. ctx is used when the duration is logged, so trace
+// and request IDs are attached to the entry.
+func StartTimer(ctx context.Context) *Timer {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Timer{ctx: ctx, start: time.Now()}
+}
+
+// StartTaskTimer starts a Timer together with a runtime/trace task named
+// operation, so execution traces viewed with `go tool trace` group the work
+// under the same name its log entry uses. The returned context carries the
+// task; pass it on so regions and checkpoints nest under it.
+//
+// Example:
+//
+//	ctx, timer := logging.StartTaskTimer(ctx, "checkout")
+//	defer timer.Stop(logger, "checkout")
+func StartTaskTimer(ctx context.Context, operation string) (context.Context, *Timer) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, task := trace.NewTask(ctx, operation)
+	return ctx, &Timer{ctx: ctx, start: time.Now(), end: task.End}
+}
+
+// StartRegionTimer starts a Timer together with a runtime/trace region named
+// operation within ctx's task. The Timer must be stopped on the goroutine
+// that started it.
+func StartRegionTimer(ctx context.Context, operation string) *Timer {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	region := trace.StartRegion(ctx, operation)
+	return &Timer{ctx: ctx, start: time.Now(), end: region.End}
+}
+
+// Timed runs fn in a runtime/trace task named operation and logs its
+// duration like Stop, or at ERROR with an error field when fn fails. It
+// returns fn's error.
+//
+// Example:
+//
+//	err := logging.Timed(ctx, logger, "sync_inventory", func(ctx context.Context) error {
+//		return inventory.Sync(ctx)
+//	})
+func Timed(ctx context.Context, logger Logger, operation string, fn func(ctx context.Context) error) error {
+	ctx, timer := StartTaskTimer(ctx, operation)
+	err := fn(ctx)
+	if err != nil {
+		timer.stop(logger.WithField("error", err.Error()), ErrorLevel, operation, "failed")
+		return err
+	}
+	timer.Stop(logger, operation)
+	return nil
+}
+
+// Elapsed returns the time since the Timer was started.
+func (t *Timer) Elapsed() time.Duration {
+	return time.Since(t.start)
+}
+
+// Stop logs the elapsed time at INFO level with operation, duration_ms and a
+// human-readable duration field, and returns the elapsed time.
+func (t *Timer) Stop(logger Logger, operation string) time.Duration {
+	return t.StopLevel(logger, InfoLevel, operation)
+}
+
+// StopLevel is like Stop but logs at level.
+func (t *Timer) StopLevel(logger Logger, level Level, operation string) time.Duration {
+	return t.stop(logger, level, operation, "completed")
+}
+
+func (t *Timer) stop(logger Logger, level Level, operation, outcome string) time.Duration {
+	elapsed := t.Elapsed()
+	t.endTrace()
+	logger.WithFields(map[string]interface{}{
+		"operation":   operation,
+		"duration_ms": elapsed.Milliseconds(),
+		"duration":    elapsed.String(),
+	}).LogContext(t.ctx, level, "%s %s", operation, outcome)
+	return elapsed
+}
+
+// Checkpoint logs the time elapsed so far at DEBUG level with checkpoint,
+// elapsed_ms and elapsed fields, without stopping the Timer. The checkpoint
+// is also recorded as a log event in the execution trace.
+func (t *Timer) Checkpoint(logger Logger, name string) time.Duration {
+	elapsed := t.Elapsed()
+	trace.Log(t.ctx, "checkpoint", name)
+	logger.WithFields(map[string]interface{}{
+		"checkpoint": name,
+		"elapsed_ms": elapsed.Milliseconds(),
+		"elapsed":    elapsed.String(),
+	}).LogContext(t.ctx, DebugLevel, "checkpoint %s", name)
+	return elapsed
+}
+
+// endTrace ends the Timer's task or region the first time it is called.
+func (t *Timer) endTrace() {
+	if t.end != nil {
+		t.end()
+		t.end = nil
+	}
+}
+
+// Record adds the elapsed time to aggregator under key instead of logging it,
+// and returns the elapsed time. Use it on hot paths where a periodic summary
+// is preferable to one entry per operation.
+func (t *Timer) Record(aggregator *DurationAggregator, key string) time.Duration {
+	elapsed := t.Elapsed()
+	t.endTrace()
+	aggregator.Observe(key, elapsed)
+	return elapsed
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime/trace"
+	"testing"
+	"time"
+)
+
+func TestTimer_Stop(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+
+	ctx := WithTraceID(context.Background(), "trace-timer")
+	timer := StartTimer(ctx)
+	time.Sleep(2 * time.Millisecond)
+	elapsed := timer.Stop(logger, "db_query")
+
+	if elapsed < 2*time.Millisecond {
+		t.Errorf("expected at least 2ms elapsed, got %v", elapsed)
+	}
+
+	entry, ok := ParseJSONEntry(bytes.TrimSpace(buf.Bytes()))
+	if !ok {
+		t.Fatalf("expected JSON entry, got: %s", buf.String())
+	}
+	if entry.Message != "db_query completed" || entry.Level != InfoLevel {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Fields["operation"] != "db_query" || entry.Fields["trace_id"] != "trace-timer" {
+		t.Errorf("unexpected fields: %v", entry.Fields)
+	}
+	if ms, ok := entry.Fields["duration_ms"].(float64); !ok || ms < 2 {
+		t.Errorf("expected duration_ms >= 2, got %v", entry.Fields["duration_ms"])
+	}
+	if d, err := time.ParseDuration(entry.Fields["duration"].(string)); err != nil || d != elapsed {
+		t.Errorf("expected human-readable duration %v, got %v", elapsed, entry.Fields["duration"])
+	}
+}
+
+func TestTimer_IgnoresConfiguredClock(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(DebugLevel).
+		WithWriter(buf).
+		WithClock(ClockFunc(func() time.Time { return time.Time{} })).
+		Build())
+
+	timer := StartTimer(context.Background())
+	time.Sleep(time.Millisecond)
+	if elapsed := timer.StopLevel(logger, DebugLevel, "op"); elapsed <= 0 {
+		t.Errorf("expected positive elapsed time, got %v", elapsed)
+	}
+	if buf.Len() == 0 {
+		t.Error("expected DEBUG entry to be written")
+	}
+}
+
+func TestTimer_Checkpoint(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).WithLevel(DebugLevel).Build())
+
+	timer := StartTimer(context.Background())
+	timer.Checkpoint(logger, "parsed")
+
+	entry, ok := ParseJSONEntry(bytes.TrimSpace(buf.Bytes()))
+	if !ok {
+		t.Fatalf("expected JSON entry, got: %s", buf.String())
+	}
+	if entry.Message != "checkpoint parsed" || entry.Level != DebugLevel || entry.Fields["checkpoint"] != "parsed" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestTimed(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithJSONFormat().WithWriter(buf).Build())
+
+	var traceBuf bytes.Buffer
+	if err := trace.Start(&traceBuf); err != nil {
+		t.Skipf("execution tracing unavailable: %v", err)
+	}
+	err := Timed(context.Background(), logger, "sync_inventory", func(ctx context.Context) error {
+		region := StartRegionTimer(ctx, "fetch_page")
+		region.StopLevel(logger, DebugLevel, "fetch_page")
+		return errors.New("upstream unavailable")
+	})
+	trace.Stop()
+
+	if err == nil || err.Error() != "upstream unavailable" {
+		t.Errorf("expected fn's error, got %v", err)
+	}
+	entry, ok := ParseJSONEntry(bytes.TrimSpace(buf.Bytes()))
+	if !ok {
+		t.Fatalf("expected JSON entry, got: %s", buf.String())
+	}
+	if entry.Message != "sync_inventory failed" || entry.Level != ErrorLevel || entry.Fields["error"] != "upstream unavailable" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	for _, name := range []string{"sync_inventory", "fetch_page"} {
+		if !bytes.Contains(traceBuf.Bytes(), []byte(name)) {
+			t.Errorf("expected execution trace to name %q", name)
+		}
+	}
+}
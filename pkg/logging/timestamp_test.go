@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// renderedTimestamps logs one entry at ts through each built-in format and
+// returns the timestamp text each one printed.
+func renderedTimestamps(t *testing.T, ts time.Time, utc bool) map[string]string {
+	t.Helper()
+	patterns := map[OutputFormat]*regexp.Regexp{
+		JSONFormat:      regexp.MustCompile(`"timestamp":"([^"]+)"`),
+		TextFormat:      regexp.MustCompile(`^(\d{4,}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}) `),
+		CommonLogFormat: regexp.MustCompile(`\[([^\]]+)\]`),
+	}
+	names := map[OutputFormat]string{JSONFormat: "json", TextFormat: "text", CommonLogFormat: "common"}
+
+	rendered := make(map[string]string)
+	ctx := WithEntryTime(context.Background(), ts)
+	for format, pattern := range patterns {
+		buf := &bytes.Buffer{}
+		builder := NewLoggerConfig().WithFormat(format).WithWriter(buf)
+		if utc {
+			builder.WithUTC()
+		}
+		NewWithLoggerConfig(builder.Build()).InfoContext(ctx, "tick")
+
+		match := pattern.FindStringSubmatch(buf.String())
+		if match == nil {
+			t.Fatalf("no timestamp in %s output: %q", names[format], buf.String())
+		}
+		rendered[names[format]] = match[1]
+	}
+	return rendered
+}
+
+func TestWithUTC_FormattersAgree(t *testing.T) {
+	rng := rand.New(rand.NewSource(4521))
+	start := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	end := time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	for i := 0; i < 200; i++ {
+		offset := (rng.Intn(27*4) - 12*4) * 15 * 60
+		ts := time.Unix(start+rng.Int63n(end-start), rng.Int63n(int64(time.Second))).
+			In(time.FixedZone("random", offset))
+		want := ts.UTC()
+
+		rendered := renderedTimestamps(t, ts, true)
+		if got := rendered["json"]; got != want.Format(time.RFC3339) {
+			t.Fatalf("%v: json timestamp %q, want %q", ts, got, want.Format(time.RFC3339))
+		}
+		if got := rendered["text"]; got != want.Format("2006/01/02 15:04:05") {
+			t.Fatalf("%v: text timestamp %q, want %q", ts, got, want.Format("2006/01/02 15:04:05"))
+		}
+		if got := rendered["common"]; got != want.Format("02/Jan/2006:15:04:05 -0700") {
+			t.Fatalf("%v: common log timestamp %q, want %q", ts, got, want.Format("02/Jan/2006:15:04:05 -0700"))
+		}
+	}
+}
+
+func TestWithoutUTC_TextKeepsZone(t *testing.T) {
+	ts := time.Date(2024, 3, 10, 23, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	rendered := renderedTimestamps(t, ts, false)
+
+	if rendered["text"] != "2024/03/10 23:30:00" {
+		t.Errorf("expected local wall time in text output, got %q", rendered["text"])
+	}
+	if rendered["json"] != "2024-03-11T04:30:00Z" {
+		t.Errorf("expected JSON to stay UTC, got %q", rendered["json"])
+	}
+}
+
+func TestTimestamps_Y2K38(t *testing.T) {
+	for _, ts := range []time.Time{
+		time.Unix(1<<31-1, 0).UTC(),
+		time.Unix(1<<31, 0).UTC(),
+		time.Unix(1<<32, 0).UTC(),
+		time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC),
+	} {
+		rendered := renderedTimestamps(t, ts, true)
+		if rendered["json"] != ts.Format(time.RFC3339) {
+			t.Errorf("json timestamp %q, want %q", rendered["json"], ts.Format(time.RFC3339))
+		}
+
+		entry, ok := ParseJSONEntry([]byte(`{"timestamp":"` + rendered["json"] + `","level":"INFO","message":"tick"}`))
+		if !ok || !entry.Timestamp.Equal(ts) {
+			t.Errorf("round trip of %v gave %v", ts, entry.Timestamp)
+		}
+	}
+}
+
+func TestParseTimestamp_LeapSecond(t *testing.T) {
+	tests := map[string]time.Time{
+		"2016-12-31T23:59:60Z":        time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		"2016-12-31T23:59:60.25Z":     time.Date(2017, 1, 1, 0, 0, 0, 250e6, time.UTC),
+		"2015-06-30T19:59:60-04:00":   time.Date(2015, 7, 1, 0, 0, 0, 0, time.UTC),
+		"2024-05-01T12:00:00.123456Z": time.Date(2024, 5, 1, 12, 0, 0, 123456000, time.UTC),
+	}
+	for s, want := range tests {
+		got, err := parseTimestamp(s)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseTimestamp(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := parseTimestamp("2016-12-31T23:59:61Z"); err == nil {
+		t.Error("expected an error for second 61")
+	}
+
+	entry, ok := ParseJSONEntry([]byte(`{"timestamp":"2016-12-31T23:59:60Z","message":"leap"}`))
+	if !ok || entry.Timestamp.IsZero() {
+		t.Errorf("expected ParseJSONEntry to accept a leap second, got %v", entry.Timestamp)
+	}
+}
+
+func TestWithUTC_FromEnvironmentAndYAML(t *testing.T) {
+	t.Setenv("LOG_UTC", "true")
+	if config := NewLoggerConfig().FromEnvironment().Build(); !config.Core.UTC {
+		t.Error("expected LOG_UTC=true to enable UTC")
+	}
+
+	yamlConfig := &YAMLConfig{Level: "info", Format: "text", UTC: true, Output: YAMLOutputConfig{Type: "stdout"}}
+	builder := NewLoggerConfig()
+	if err := configureCoreFromYAML(builder, yamlConfig); err != nil {
+		t.Fatalf("failed to apply YAML config: %v", err)
+	}
+	if !builder.Build().Core.UTC {
+		t.Error("expected utc: true to enable UTC")
+	}
+
+	changes := DiffYAMLConfig(&YAMLConfig{}, yamlConfig)
+	found := false
+	for _, change := range changes {
+		found = found || change.Setting == "utc"
+	}
+	if !found {
+		t.Errorf("expected a utc change, got %v", changes)
+	}
+}
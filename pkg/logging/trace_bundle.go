@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// TraceBundleVersion is the format version of TraceBundle.
+const TraceBundleVersion = "1"
+
+// TraceExportConfig selects where ExportTrace looks for entries.
+type TraceExportConfig struct {
+	// RingBuffers are searched first. When nil, every open
+	// RingBufferOutput is searched; use an empty slice to skip them.
+	RingBuffers []*RingBufferOutput
+	// Dirs are directories of JSON log files, searched with Query. A
+	// LocalStore directory can be used as is.
+	Dirs []string
+	// Clock stamps the bundle. Defaults to the system clock.
+	Clock Clock
+}
+
+// TraceBundle is every locally available entry of one trace, in a single
+// JSON document that can be attached to a ticket.
+type TraceBundle struct {
+	Version     string    `json:"version"`
+	TraceID     string    `json:"trace_id"`
+	Since       time.Time `json:"since,omitzero"`
+	Until       time.Time `json:"until,omitzero"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// Sources lists every place searched, whether or not it held entries.
+	Sources []string           `json:"sources"`
+	Entries []TraceBundleEntry `json:"entries"`
+}
+
+// TraceBundleEntry is one entry of a TraceBundle.
+type TraceBundleEntry struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message"`
+	File      string                 `json:"file,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	// Source is "ring buffer" or the file the entry was read from.
+	Source string `json:"source"`
+}
+
+// ringBufferSource is the TraceBundleEntry source of ring buffer entries.
+const ringBufferSource = "ring buffer"
+
+// ExportTrace gathers the entries carrying traceID in their trace_id field
+// from ring buffers and log directories into a bundle, oldest first. Zero
+// since and until leave the window unbounded. An entry found in both a ring
+// buffer and a file, matched by second, level and message, is included once,
+// from the ring buffer.
+//
+// Example:
+//
+//	bundle, err := logging.ExportTrace(traceID, time.Now().Add(-time.Hour), time.Time{},
+//		logging.TraceExportConfig{Dirs: []string{"/var/log/myapp"}})
+//	if err != nil {
+//		return err
+//	}
+//	_, err = bundle.WriteTo(file)
+func ExportTrace(traceID string, since, until time.Time, config TraceExportConfig) (*TraceBundle, error) {
+	if traceID == "" {
+		return nil, fmt.Errorf("trace ID is required")
+	}
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	buffers := config.RingBuffers
+	if buffers == nil {
+		ringBuffers.mu.Lock()
+		buffers = append([]*RingBufferOutput(nil), ringBuffers.outputs...)
+		ringBuffers.mu.Unlock()
+	}
+
+	bundle := &TraceBundle{
+		Version:     TraceBundleVersion,
+		TraceID:     traceID,
+		Since:       since,
+		Until:       until,
+		GeneratedAt: config.Clock.Now(),
+		Sources:     []string{},
+		Entries:     []TraceBundleEntry{},
+	}
+	filter := Filter{
+		Since:       since,
+		Until:       until,
+		FieldEquals: map[string]interface{}{"trace_id": traceID},
+	}
+	seen := make(map[string]bool)
+	add := func(entry LogEntry, source string) {
+		key := fmt.Sprintf("%d|%s|%s", entry.Timestamp.Unix(), entry.Level, entry.Message)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		file := entry.File
+		if file != "" && entry.Line > 0 {
+			file = fmt.Sprintf("%s:%d", file, entry.Line)
+		}
+		bundle.Entries = append(bundle.Entries, TraceBundleEntry{
+			Timestamp: entry.Timestamp,
+			Level:     entry.Level.String(),
+			Message:   entry.Message,
+			File:      file,
+			Fields:    entry.Fields,
+			Source:    source,
+		})
+	}
+
+	if len(buffers) > 0 {
+		bundle.Sources = append(bundle.Sources, ringBufferSource)
+	}
+	for _, rb := range buffers {
+		for _, entry := range rb.Entries(filter) {
+			add(entry, ringBufferSource)
+		}
+	}
+
+	for _, dir := range config.Dirs {
+		files, err := queryFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, filename := range files {
+			bundle.Sources = append(bundle.Sources, filename)
+			var queryErr error
+			queryFile(filename, filter, JSONLConfig{}, func(entry LogEntry, err error) bool {
+				if err != nil {
+					queryErr = err
+					return false
+				}
+				add(entry, filename)
+				return true
+			})
+			if queryErr != nil {
+				return nil, queryErr
+			}
+		}
+	}
+
+	sort.SliceStable(bundle.Entries, func(i, j int) bool {
+		return bundle.Entries[i].Timestamp.Before(bundle.Entries[j].Timestamp)
+	})
+	return bundle, nil
+}
+
+// WriteTo writes the bundle as indented JSON.
+func (b *TraceBundle) WriteTo(w io.Writer) (int64, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode trace bundle: %w", err)
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportTrace(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	clock := ClockFunc(func() time.Time { return now })
+
+	file, err := NewJSONLWriter(filepath.Join(dir, "app.jsonl"), 0)
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	recorder := NewRingBufferOutput(100)
+	defer recorder.Close()
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithLevel(DebugLevel).
+		WithJSONFormat().
+		WithClock(clock).
+		WithCustomOutput(NewMultiOutput(NewLevelFilterOutput(file, InfoLevel), recorder)).
+		Build())
+
+	ctx := WithTraceID(context.Background(), "trace-1")
+	other := WithTraceID(context.Background(), "trace-2")
+
+	logger.InfoContext(ctx, "request received")
+	now = start.Add(time.Second)
+	logger.DebugContext(ctx, "cache miss")
+	logger.InfoContext(other, "unrelated request")
+	now = start.Add(2 * time.Second)
+	logger.ErrorContext(ctx, "upstream failed")
+	if err := file.Close(); err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+
+	// Earlier entries are only left in the file, e.g. after a restart.
+	recorder.Reset()
+	now = start.Add(3 * time.Second)
+	logger.WithField("attempt", 2).InfoContext(ctx, "retrying")
+
+	bundle, err := ExportTrace("trace-1", time.Time{}, time.Time{}, TraceExportConfig{
+		RingBuffers: []*RingBufferOutput{recorder},
+		Dirs:        []string{dir},
+		Clock:       clock,
+	})
+	if err != nil {
+		t.Fatalf("ExportTrace failed: %v", err)
+	}
+
+	want := []struct{ message, source string }{
+		{"request received", filepath.Join(dir, "app.jsonl")},
+		{"upstream failed", filepath.Join(dir, "app.jsonl")},
+		{"retrying", ringBufferSource},
+	}
+	if len(bundle.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), bundle.Entries)
+	}
+	for i, w := range want {
+		if got := bundle.Entries[i]; got.Message != w.message || got.Source != w.source {
+			t.Errorf("entry %d = %q from %q, want %q from %q", i, got.Message, got.Source, w.message, w.source)
+		}
+	}
+	if bundle.TraceID != "trace-1" || !bundle.GeneratedAt.Equal(now) || len(bundle.Sources) != 2 {
+		t.Errorf("unexpected bundle header: %+v", bundle)
+	}
+}
+
+func TestExportTrace_DeduplicatesAndWindows(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+
+	file, err := NewJSONLWriter(filepath.Join(dir, "app.jsonl"), 0)
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	defer file.Close()
+	recorder := NewRingBufferOutput(100)
+	defer recorder.Close()
+
+	logger := NewWithLoggerConfig(NewLoggerConfig().
+		WithJSONFormat().
+		WithClock(ClockFunc(func() time.Time { return now })).
+		WithCustomOutput(NewMultiOutput(file, recorder)).
+		Build())
+	ctx := WithTraceID(context.Background(), "trace-1")
+	for i := 0; i < 5; i++ {
+		now = start.Add(time.Duration(i) * time.Minute)
+		logger.WithField("step", i).InfoContext(ctx, "step done")
+	}
+
+	bundle, err := ExportTrace("trace-1", start.Add(time.Minute), start.Add(3*time.Minute), TraceExportConfig{
+		RingBuffers: []*RingBufferOutput{recorder},
+		Dirs:        []string{dir},
+	})
+	if err != nil {
+		t.Fatalf("ExportTrace failed: %v", err)
+	}
+	if len(bundle.Entries) != 3 {
+		t.Fatalf("expected the 3 entries in the window once each, got %+v", bundle.Entries)
+	}
+	for i, entry := range bundle.Entries {
+		if entry.Source != ringBufferSource || entry.Fields["step"] != i+1 {
+			t.Errorf("entry %d = %+v, want step %d from the ring buffer", i, entry, i+1)
+		}
+	}
+}
+
+func TestTraceBundle_WriteTo(t *testing.T) {
+	bundle, err := ExportTrace("trace-1", time.Time{}, time.Time{}, TraceExportConfig{
+		RingBuffers: []*RingBufferOutput{},
+	})
+	if err != nil {
+		t.Fatalf("ExportTrace failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := bundle.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo failed: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected a JSON document: %v", err)
+	}
+	if decoded["version"] != TraceBundleVersion || decoded["entries"] == nil {
+		t.Errorf("unexpected bundle %s", buf.String())
+	}
+	if _, ok := decoded["since"]; ok {
+		t.Error("expected an unbounded window to be omitted")
+	}
+
+	if _, err := ExportTrace("", time.Time{}, time.Time{}, TraceExportConfig{}); err == nil {
+		t.Error("expected an error without a trace ID")
+	}
+}
@@ -2,16 +2,13 @@ package logging
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"log/slog"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
-
-	"github.com/ocrosby/go-logging/pkg/logging/internal"
 )
 
 // unifiedLogger is a single implementation that provides all logger interfaces
@@ -20,12 +17,22 @@ type unifiedLogger struct {
 	mu            sync.RWMutex
 	config        *LoggerConfig
 	fields        map[string]interface{}
-	textLoggers   map[Level]*log.Logger
+	fieldOrder    []string
+	group         []string
+	level         *levelVar
+	formatter     Formatter
+	output        Output
 	slogLogger    *slog.Logger
-	discard       *log.Logger
 	redactorChain RedactorChainInterface
+	recentErrors  *errorRing
 }
 
+var (
+	_ ConfigurableLogger = (*unifiedLogger)(nil)
+	_ RecentErrorsLogger = (*unifiedLogger)(nil)
+	_ GroupLogger        = (*unifiedLogger)(nil)
+)
+
 // NewUnifiedLogger creates a new unified logger implementation.
 func NewUnifiedLogger(config *LoggerConfig, redactorChain RedactorChainInterface) Logger {
 	if config == nil {
@@ -34,54 +41,132 @@ func NewUnifiedLogger(config *LoggerConfig, redactorChain RedactorChainInterface
 	if redactorChain == nil {
 		redactorChain = NewRedactorChain()
 	}
+	config = resolveAutoFormat(config)
+	config = resolveAutoQuiet(config)
+
+	// A custom slog handler applies its own threshold, so the logger starts
+	// out passing every level through to it.
+	level := config.Core.Level
+	if config.UseSlog && config.Handler != nil {
+		level = TraceLevel
+	}
 
 	ul := &unifiedLogger{
 		config:        config,
 		fields:        make(map[string]interface{}),
-		textLoggers:   make(map[Level]*log.Logger),
-		discard:       log.New(io.Discard, "", 0),
+		level:         newLevelVar(level),
 		redactorChain: redactorChain,
+		recentErrors:  newErrorRing(config.Core.RecentErrors),
 	}
 
 	// Initialize based on configuration
 	if config.UseSlog {
 		handler := config.Handler
 		if handler == nil {
-			if config.Formatter.Format == JSONFormat {
-				handler = slog.NewJSONHandler(config.Output.Writer, &slog.HandlerOptions{
-					Level: ul.levelToSlog(config.Core.Level),
-				})
-			} else {
-				handler = slog.NewTextHandler(config.Output.Writer, &slog.HandlerOptions{
-					Level: ul.levelToSlog(config.Core.Level),
-				})
+			// The logger applies its own level so SetLevel and WithLevel
+			// take effect; the default handlers accept every level.
+			handler = ul.newSlogHandler(config.Output.Writer)
+			if len(config.Output.LevelWriters) > 0 {
+				lh := &levelWriterHandler{fallback: handler}
+				for _, lw := range sortedLevelWriters(config.Output.LevelWriters) {
+					lh.levels = append(lh.levels, ul.levelToSlog(lw.Level))
+					lh.handlers = append(lh.handlers, ul.newSlogHandler(lw.Writer))
+				}
+				handler = lh
 			}
 		}
 		ul.slogLogger = slog.New(handler)
-	} else if config.Formatter.Format == TextFormat {
-		ul.initTextLoggers()
+	} else {
+		ul.formatter = newFormatterFromConfig(config)
+		ul.output = newOutputFromConfig(config)
 	}
 
 	return ul
 }
 
-func (ul *unifiedLogger) initTextLoggers() {
-	flags := 0
-	if ul.config.Formatter.IncludeTime {
-		flags |= log.LstdFlags | log.Lmsgprefix
+// newSlogHandler returns the default slog handler for the configured
+// format, writing to w.
+func (ul *unifiedLogger) newSlogHandler(w io.Writer) slog.Handler {
+	if ul.config.Output.OnWriteError != nil {
+		w = &writeErrorWriter{w: w, onError: ul.config.Output.OnWriteError}
 	}
-	if ul.config.Formatter.IncludeFile {
-		if ul.config.Formatter.UseShortFile {
-			flags |= log.Lshortfile
-		} else {
-			flags |= log.Llongfile
+	options := &slog.HandlerOptions{
+		Level:       ul.levelToSlog(TraceLevel),
+		ReplaceAttr: ul.config.Formatter.replaceSlogTime(),
+	}
+	if ul.config.Formatter.Format == JSONFormat {
+		options.ReplaceAttr = chainReplaceAttr(options.ReplaceAttr, ul.config.Formatter.Keys.replaceSlogKeys())
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// resolveAutoFormat returns config with AutoFormat replaced by console
+// output if the logger writes to a terminal and by JSON otherwise. Console
+// output is colored unless ColorEnabled reports otherwise.
+func resolveAutoFormat(config *LoggerConfig) *LoggerConfig {
+	if config.Formatter == nil || config.Formatter.Format != AutoFormat {
+		return config
+	}
+	resolved := *config
+	formatter := *config.Formatter
+	resolved.Formatter = &formatter
+
+	formatter.Format = JSONFormat
+	if config.Output != nil && config.Output.Custom == nil && isTerminal(config.Output.Writer) {
+		formatter.Format = TextFormat
+		if formatter.Custom == nil {
+			formatter.Custom = NewConsoleFormatterFor(&formatter, config.Output.Writer)
+		}
+	}
+	return &resolved
+}
+
+// newFormatterFromConfig returns the custom formatter when one is configured,
+// otherwise the built-in formatter for the configured format.
+func newFormatterFromConfig(config *LoggerConfig) Formatter {
+	if config.Formatter.Custom != nil {
+		return config.Formatter.Custom
+	}
+
+	switch config.Formatter.Format {
+	case JSONFormat:
+		return NewJSONFormatter(config.Formatter)
+	case CommonLogFormat:
+		return NewCommonLogFormatter(config.Formatter)
+	case CombinedLogFormat:
+		return NewCombinedLogFormatter(config.Formatter)
+	case GELFFormat:
+		return NewGELFFormatter(config.Formatter, "")
+	case StackdriverFormat:
+		return NewStackdriverFormatter(config.Formatter, stackdriverProjectID())
+	case RegisteredFormat:
+		return newRegisteredFormatter(config.Formatter)
+	case PrettyFormat:
+		useColors := config.Output != nil && config.Output.Custom == nil && ColorEnabled(config.Output.Writer)
+		return NewPrettyFormatter(config.Formatter, useColors)
+	case TemplateFormat:
+		formatter, err := NewTemplateFormatter(config.Formatter, config.Formatter.Template)
+		if err != nil {
+			reportInvalidConfig("TemplateFormat", "using the text format", err)
+			return NewTextFormatter(config.Formatter)
 		}
+		return formatter
+	default:
+		return NewTextFormatter(config.Formatter)
 	}
+}
 
-	for level := range levelNames {
-		prefix := fmt.Sprintf("[%s] ", level)
-		ul.textLoggers[level] = log.New(ul.config.Output.Writer, prefix, flags)
+// newOutputFromConfig returns the custom output when one is configured,
+// otherwise an output wrapping the configured writer and level writers.
+func newOutputFromConfig(config *LoggerConfig) Output {
+	if config.Output.Custom != nil {
+		return config.Output.Custom
+	}
+	if len(config.Output.LevelWriters) > 0 {
+		return newLevelWriterOutput(config.Output.Writer, config.Output.LevelWriters)
 	}
+	return NewWriterOutput(config.Output.Writer)
 }
 
 func (ul *unifiedLogger) levelToSlog(level Level) slog.Level {
@@ -102,10 +187,41 @@ func (ul *unifiedLogger) levelToSlog(level Level) slog.Level {
 
 // Core Logger interface implementation
 func (ul *unifiedLogger) Log(level Level, msg string, args ...interface{}) {
-	ul.LogContext(context.Background(), level, msg, args...)
+	ul.log(context.Background(), level, msg, args...)
 }
 
 func (ul *unifiedLogger) LogContext(ctx context.Context, level Level, msg string, args ...interface{}) {
+	ul.log(ctx, level, msg, args...)
+}
+
+// callerSkip is the number of frames between log and the user's call site
+// for the direct logging methods. Paths with more frames, such as the fluent
+// API, are resolved by callerPC.
+const callerSkip = 2
+
+// libraryPackage is the import path of this package, whose non-test frames
+// callerPC skips.
+const libraryPackage = "github.com/ocrosby/go-logging/pkg/logging"
+
+// callerPC returns the program counter of the user's call site: the first
+// frame, starting skip frames above callerPC's caller as with
+// runtime.Caller, that is not in this package's non-test code.
+func callerPC(skip int) (uintptr, bool) {
+	var pcs [32]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	for _, pc := range pcs[:n] {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		if packageOfFunction(frame.Function) != libraryPackage || strings.HasSuffix(frame.File, "_test.go") {
+			return pc, true
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return pcs[0], true
+}
+
+func (ul *unifiedLogger) log(ctx context.Context, level Level, msg string, args ...interface{}) {
 	ul.mu.RLock()
 	defer ul.mu.RUnlock()
 
@@ -116,14 +232,43 @@ func (ul *unifiedLogger) LogContext(ctx context.Context, level Level, msg string
 	message := fmt.Sprintf(msg, args...)
 	message = ul.redactorChain.Redact(message)
 
+	if level == InfoLevel && ul.config.Core.NoiseDemoter != nil {
+		demote, notice := ul.config.Core.NoiseDemoter.observe(message, ul.now(ctx))
+		if notice != nil {
+			ul.emit(ctx, InfoLevel, noiseNoticeMessage, notice)
+		}
+		if demote {
+			level = DebugLevel
+			if !ul.isLevelEnabledInternal(level) {
+				return
+			}
+		}
+	}
+
+	if level == InfoLevel && ul.config.Core.infoSampler != nil && !ul.config.Core.infoSampler.keep(ul.traceID(ctx)) {
+		return
+	}
+
+	ul.emit(ctx, level, message, nil)
+}
+
+// traceID returns the trace ID of the context or, failing that, of the
+// logger's trace_id field. It must be called with mu held.
+func (ul *unifiedLogger) traceID(ctx context.Context) string {
+	if traceID, ok := GetTraceID(ctx); ok && traceID != "" {
+		return traceID
+	}
+	traceID, _ := ul.fields["trace_id"].(string)
+	return traceID
+}
+
+// emit passes the entry to the slog or Formatter backend, with extra fields
+// in addition to the logger's own. It must be called directly from log.
+func (ul *unifiedLogger) emit(ctx context.Context, level Level, message string, extra map[string]interface{}) {
 	if ul.config.UseSlog {
-		ul.logSlog(ctx, level, message)
-	} else if ul.config.Formatter.Format == JSONFormat {
-		ul.logJSON(level, message, ctx)
-	} else if ul.config.Formatter.Format == CommonLogFormat {
-		ul.logCommonLog(level, message, ctx)
+		ul.logSlog(ctx, level, message, extra)
 	} else {
-		ul.logText(level, message)
+		ul.logEntry(ctx, level, message, extra)
 	}
 }
 
@@ -135,15 +280,23 @@ func (ul *unifiedLogger) WithField(key string, value interface{}) Logger {
 	}
 	ul.mu.RUnlock()
 
-	newFields[key] = value
+	added := nestFields(ul.group, map[string]interface{}{key: value})
+	for k, v := range added {
+		mergeGroupField(newFields, k, v)
+	}
+	newOrder := appendFieldOrder(ul.fieldOrder, ul.fields, added)
 
 	return &unifiedLogger{
 		config:        ul.config,
 		fields:        newFields,
-		textLoggers:   ul.textLoggers,
+		fieldOrder:    newOrder,
+		group:         ul.group,
+		level:         ul.level,
+		formatter:     ul.formatter,
+		output:        ul.output,
 		slogLogger:    ul.slogLogger,
-		discard:       ul.discard,
 		redactorChain: ul.redactorChain,
+		recentErrors:  ul.recentErrors,
 	}
 }
 
@@ -155,17 +308,45 @@ func (ul *unifiedLogger) WithFields(fields map[string]interface{}) Logger {
 	}
 	ul.mu.RUnlock()
 
-	for k, v := range fields {
-		newFields[k] = v
+	added := nestFields(ul.group, fields)
+	for k, v := range added {
+		mergeGroupField(newFields, k, v)
 	}
+	newOrder := appendFieldOrder(ul.fieldOrder, ul.fields, added)
 
 	return &unifiedLogger{
 		config:        ul.config,
 		fields:        newFields,
-		textLoggers:   ul.textLoggers,
+		fieldOrder:    newOrder,
+		group:         ul.group,
+		level:         ul.level,
+		formatter:     ul.formatter,
+		output:        ul.output,
+		slogLogger:    ul.slogLogger,
+		redactorChain: ul.redactorChain,
+		recentErrors:  ul.recentErrors,
+	}
+}
+
+// WithLevel returns a child logger with its own minimum level. The child and
+// its descendants ignore SetLevel calls on the parent; children created with
+// WithField or WithFields keep following their parent's level.
+//
+// Example:
+//
+//	cacheLogger := logger.WithField("component", "cache").WithLevel(logging.WarnLevel)
+func (ul *unifiedLogger) WithLevel(level Level) Logger {
+	return &unifiedLogger{
+		config:        ul.config,
+		fields:        ul.fields,
+		fieldOrder:    ul.fieldOrder,
+		group:         ul.group,
+		level:         newLevelVar(level),
+		formatter:     ul.formatter,
+		output:        ul.output,
 		slogLogger:    ul.slogLogger,
-		discard:       ul.discard,
 		redactorChain: ul.redactorChain,
+		recentErrors:  ul.recentErrors,
 	}
 }
 
@@ -176,75 +357,73 @@ func (ul *unifiedLogger) IsLevelEnabled(level Level) bool {
 }
 
 func (ul *unifiedLogger) isLevelEnabledInternal(level Level) bool {
-	// When using slog, delegate to the slog handler for level checking
+	if level < ul.level.Load() {
+		return false
+	}
+	// A custom slog handler may apply its own threshold as well
 	if ul.config.UseSlog && ul.slogLogger != nil {
 		return ul.slogLogger.Enabled(context.Background(), ul.levelToSlog(level))
 	}
-	// For standard logging, use config level
-	return level >= ul.config.Core.Level
+	return true
 }
 
 // LevelLogger interface implementation
 func (ul *unifiedLogger) Trace(msg string, args ...interface{}) {
-	ul.Log(TraceLevel, msg, args...)
+	ul.log(context.Background(), TraceLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) Debug(msg string, args ...interface{}) {
-	ul.Log(DebugLevel, msg, args...)
+	ul.log(context.Background(), DebugLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) Info(msg string, args ...interface{}) {
-	ul.Log(InfoLevel, msg, args...)
+	ul.log(context.Background(), InfoLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) Warn(msg string, args ...interface{}) {
-	ul.Log(WarnLevel, msg, args...)
+	ul.log(context.Background(), WarnLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) Error(msg string, args ...interface{}) {
-	ul.Log(ErrorLevel, msg, args...)
+	ul.log(context.Background(), ErrorLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) Critical(msg string, args ...interface{}) {
-	ul.Log(CriticalLevel, msg, args...)
+	ul.log(context.Background(), CriticalLevel, msg, args...)
 }
 
 // ContextLogger interface implementation
 func (ul *unifiedLogger) TraceContext(ctx context.Context, msg string, args ...interface{}) {
-	ul.LogContext(ctx, TraceLevel, msg, args...)
+	ul.log(ctx, TraceLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) DebugContext(ctx context.Context, msg string, args ...interface{}) {
-	ul.LogContext(ctx, DebugLevel, msg, args...)
+	ul.log(ctx, DebugLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) InfoContext(ctx context.Context, msg string, args ...interface{}) {
-	ul.LogContext(ctx, InfoLevel, msg, args...)
+	ul.log(ctx, InfoLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) WarnContext(ctx context.Context, msg string, args ...interface{}) {
-	ul.LogContext(ctx, WarnLevel, msg, args...)
+	ul.log(ctx, WarnLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) ErrorContext(ctx context.Context, msg string, args ...interface{}) {
-	ul.LogContext(ctx, ErrorLevel, msg, args...)
+	ul.log(ctx, ErrorLevel, msg, args...)
 }
 
 func (ul *unifiedLogger) CriticalContext(ctx context.Context, msg string, args ...interface{}) {
-	ul.LogContext(ctx, CriticalLevel, msg, args...)
+	ul.log(ctx, CriticalLevel, msg, args...)
 }
 
 // ConfigurableLogger interface implementation
 func (ul *unifiedLogger) SetLevel(level Level) {
-	ul.mu.Lock()
-	defer ul.mu.Unlock()
-	ul.config.Core.Level = level
+	ul.level.Store(level)
 }
 
 func (ul *unifiedLogger) GetLevel() Level {
-	ul.mu.RLock()
-	defer ul.mu.RUnlock()
-	return ul.config.Core.Level
+	return ul.level.Load()
 }
 
 // FluentCapable interface implementation
@@ -253,147 +432,228 @@ func (ul *unifiedLogger) Fluent() FluentLogger {
 }
 
 // Internal logging methods
-func (ul *unifiedLogger) logSlog(ctx context.Context, level Level, message string) {
+func (ul *unifiedLogger) logSlog(ctx context.Context, level Level, message string, extra map[string]interface{}) {
 	if ul.slogLogger == nil {
 		return
 	}
 
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	slogLevel := ul.levelToSlog(level)
-	logAttrs := ul.buildSlogAttrs(ctx)
-	ul.slogLogger.LogAttrs(ctx, slogLevel, message, logAttrs...)
+	handler := ul.slogLogger.Handler()
+	if !handler.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	// Build the record directly so it carries the configured clock's time
+	// and the user's call site rather than this function's.
+	pc, _ := callerPC(callerSkip + 2)
+	record := slog.NewRecord(ul.now(ctx), slogLevel, message, pc)
+	record.AddAttrs(ul.buildSlogAttrs(ctx)...)
+	for _, k := range sortedKeys(extra) {
+		record.AddAttrs(slog.Any(k, extra[k]))
+	}
+	if componentsRegistered.Load() && !ul.hasField(ComponentField, extra) {
+		if component, ok := componentAt(pc); ok {
+			record.AddAttrs(slog.String(ComponentField, component))
+		}
+	}
+	if ul.config.Core.Origin && !ul.hasField(OriginField, extra) {
+		record.AddAttrs(slog.Any(OriginField, originAt(pc)))
+	}
+	if level >= ErrorLevel && ul.recentErrors != nil {
+		ul.recentErrors.add(LogEntry{
+			Timestamp: record.Time,
+			Level:     level,
+			Message:   message,
+			Fields:    ul.buildCommonLogFields(),
+			Context:   ctx,
+		})
+	}
+	// The default handlers report failed writes through writeErrorWriter,
+	// with the entry's bytes.
+	if err := handler.Handle(ctx, record); err != nil && ul.config.Handler != nil {
+		ul.reportWriteError(nil, err)
+	}
+}
+
+// now returns the timestamp for an entry logged with ctx: a time set with
+// WithEntryTime, otherwise the configured Clock.
+func (ul *unifiedLogger) now(ctx context.Context) time.Time {
+	t, ok := GetEntryTime(ctx)
+	if !ok {
+		if ul.config.Core.Clock != nil {
+			t = ul.config.Core.Clock.Now()
+		} else {
+			t = SystemClock.Now()
+		}
+	}
+	if ul.config.Core.UTC {
+		return t.UTC()
+	}
+	return t
 }
 
 func (ul *unifiedLogger) buildSlogAttrs(ctx context.Context) []slog.Attr {
-	logAttrs := make([]slog.Attr, 0, len(ul.fields)+len(ul.config.Core.StaticFields)+3)
+	logAttrs := make([]slog.Attr, 0, len(ul.fields)+len(ul.config.Core.StaticFields)+4)
 
-	ul.addStaticFieldAttrs(&logAttrs)
-	ul.addInstanceFieldAttrs(&logAttrs)
+	fields := ul.mergeFields()
+	if ul.config.Core.rewritesFields() {
+		fields = ul.buildCommonLogFields()
+	}
+	var inserted []string
+	if ul.config.Formatter.FieldOrder == InsertionFieldOrder {
+		inserted = ul.insertionOrder(nil)
+	}
+	for _, k := range ul.config.Formatter.orderFieldKeys(fields, inserted) {
+		logAttrs = append(logAttrs, slogAttr(k, fields[k]))
+	}
 	ul.addContextFieldAttrs(ctx, &logAttrs)
 
 	return logAttrs
 }
 
-func (ul *unifiedLogger) addStaticFieldAttrs(logAttrs *[]slog.Attr) {
-	for k, v := range ul.config.Core.StaticFields {
-		*logAttrs = append(*logAttrs, slog.Any(k, v))
-	}
-}
+func (ul *unifiedLogger) addContextFieldAttrs(ctx context.Context, logAttrs *[]slog.Attr) {
+	contextFields := contextFieldsFrom(ctx).
+		Omit(ul.fields).
+		Omit(ul.config.Core.StaticFields)
 
-func (ul *unifiedLogger) addInstanceFieldAttrs(logAttrs *[]slog.Attr) {
-	for k, v := range ul.fields {
-		*logAttrs = append(*logAttrs, slog.Any(k, v))
+	if contextFields.RequestID != "" {
+		*logAttrs = append(*logAttrs, slog.String("request_id", contextFields.RequestID))
+	}
+	if contextFields.TraceID != "" {
+		*logAttrs = append(*logAttrs, slog.String("trace_id", contextFields.TraceID))
+	}
+	if contextFields.CorrelationID != "" {
+		*logAttrs = append(*logAttrs, slog.String("correlation_id", contextFields.CorrelationID))
 	}
 }
 
-func (ul *unifiedLogger) addContextFieldAttrs(ctx context.Context, logAttrs *[]slog.Attr) {
-	if requestID, ok := GetRequestID(ctx); ok && requestID != "" {
-		*logAttrs = append(*logAttrs, slog.String("request_id", requestID))
+// logEntry builds a LogEntry and passes it through the configured Formatter
+// and Output.
+func (ul *unifiedLogger) logEntry(ctx context.Context, level Level, message string, extra map[string]interface{}) {
+	fields := ul.buildCommonLogFields()
+	if len(extra) > 0 {
+		fields = copyFields(fields, len(extra))
+		for k, v := range extra {
+			fields[k] = v
+		}
 	}
-	if traceID, ok := GetTraceID(ctx); ok && traceID != "" {
-		*logAttrs = append(*logAttrs, slog.String("trace_id", traceID))
+	entry := LogEntry{
+		Timestamp: ul.now(ctx),
+		Level:     level,
+		Message:   message,
+		Fields:    fields,
+		Context:   ctx,
 	}
-	if correlationID, ok := GetCorrelationID(ctx); ok && correlationID != "" {
-		*logAttrs = append(*logAttrs, slog.String("correlation_id", correlationID))
+	if ul.config.Formatter.FieldOrder == InsertionFieldOrder {
+		entry.fieldOrder = ul.insertionOrder(extra)
 	}
-}
-
-func (ul *unifiedLogger) logText(level Level, message string) {
-	logger := ul.textLoggers[level]
-	if logger == nil {
-		logger = ul.discard
+	ul.addCallerInfo(&entry)
+	ul.addComponent(&entry)
+	ul.addOrigin(&entry)
+	if level >= ErrorLevel && ul.recentErrors != nil {
+		ul.recentErrors.add(entry)
 	}
-	_ = logger.Output(3, message)
-}
 
-func (ul *unifiedLogger) logJSON(level Level, message string, ctx context.Context) {
-	entry := ul.createBaseEntry(level, message)
-	ul.addFileInfo(entry)
-	ul.addStaticFields(entry)
-	ul.addInstanceFields(entry)
-	ul.addContextFields(entry, ctx)
-	ul.writeJSON(entry)
+	data, err := ul.formatter.Format(entry)
+	if err != nil {
+		ul.reportWriteError(nil, fmt.Errorf("failed to format entry: %w", err))
+		return
+	}
+	if err := writeEntry(ul.output, entry, data); err != nil {
+		ul.reportWriteError(data, err)
+	}
 }
 
-func (ul *unifiedLogger) createBaseEntry(level Level, message string) map[string]interface{} {
-	entry := make(map[string]interface{})
-
-	if ul.config.Formatter.IncludeTime {
-		entry["timestamp"] = time.Now().UTC().Format(time.RFC3339)
+// reportWriteError passes a failed entry to the configured OnWriteError.
+func (ul *unifiedLogger) reportWriteError(data []byte, err error) {
+	if ul.config.Output.OnWriteError != nil {
+		ul.config.Output.OnWriteError(data, err)
 	}
-
-	entry["level"] = level.String()
-	entry["message"] = message
-
-	return entry
 }
 
-func (ul *unifiedLogger) addFileInfo(entry map[string]interface{}) {
+// addCallerInfo records the user's call site on the entry when file
+// information is enabled. It must be called directly from logEntry.
+func (ul *unifiedLogger) addCallerInfo(entry *LogEntry) {
 	if !ul.config.Formatter.IncludeFile {
 		return
 	}
-
-	if _, file, line, ok := runtime.Caller(4); ok {
-		entry["file"] = ul.formatFilename(file, line)
+	if pc, ok := callerPC(callerSkip + 3); ok {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		entry.File = frame.File
+		entry.Line = frame.Line
 	}
 }
 
-func (ul *unifiedLogger) formatFilename(file string, line int) string {
-	return internal.FormatFilename(file, line, ul.config.Formatter.UseShortFile)
-}
-
-func (ul *unifiedLogger) addStaticFields(entry map[string]interface{}) {
-	for k, v := range ul.config.Core.StaticFields {
-		entry[k] = v
+// addComponent sets the component registered for the user's package,
+// unless the entry already has one. It must be called directly from
+// logEntry.
+func (ul *unifiedLogger) addComponent(entry *LogEntry) {
+	if _, ok := entry.Fields[ComponentField]; ok {
+		return
+	}
+	component, ok := callerComponent(callerSkip + 3)
+	if !ok {
+		return
 	}
+	entry.Fields = copyFields(entry.Fields, 1)
+	entry.Fields[ComponentField] = component
 }
 
-func (ul *unifiedLogger) addInstanceFields(entry map[string]interface{}) {
-	for k, v := range ul.fields {
-		entry[k] = v
+// addOrigin sets the origin field when the logger adds origins and the
+// entry has none. It must be called directly from logEntry.
+func (ul *unifiedLogger) addOrigin(entry *LogEntry) {
+	if !ul.config.Core.Origin {
+		return
+	}
+	if _, ok := entry.Fields[OriginField]; ok {
+		return
 	}
+	entry.Fields = copyFields(entry.Fields, 1)
+	entry.Fields[OriginField] = callerOrigin(callerSkip + 3)
 }
 
-func (ul *unifiedLogger) addContextFields(entry map[string]interface{}, ctx context.Context) {
-	if requestID, ok := GetRequestID(ctx); ok && requestID != "" {
-		entry["request_id"] = requestID
+// hasField reports whether key is set by the logger's static or instance
+// fields, or by extra.
+func (ul *unifiedLogger) hasField(key string, extra map[string]interface{}) bool {
+	if _, ok := extra[key]; ok {
+		return true
 	}
-	if traceID, ok := GetTraceID(ctx); ok && traceID != "" {
-		entry["trace_id"] = traceID
-	}
-	if correlationID, ok := GetCorrelationID(ctx); ok && correlationID != "" {
-		entry["correlation_id"] = correlationID
+	if _, ok := ul.fields[key]; ok {
+		return true
 	}
+	_, ok := ul.config.Core.StaticFields[key]
+	return ok
 }
 
-func (ul *unifiedLogger) writeJSON(entry map[string]interface{}) {
-	jsonBytes, err := json.Marshal(entry)
-	if err != nil {
-		return
+// buildCommonLogFields merges static and instance fields, then applies the
+// configured schema and field encryption. When only one set is present and
+// nothing rewrites it, it is returned as-is; callers must treat the result
+// as read-only.
+func (ul *unifiedLogger) buildCommonLogFields() map[string]interface{} {
+	fields := ul.mergeFields()
+	if ul.config.Core.hasSchema() {
+		fields = ul.config.Core.applySchema(fields)
 	}
-
-	fmt.Fprintln(ul.config.Output.Writer, string(jsonBytes))
+	if ul.config.Core.FieldEncryptor != nil {
+		fields = ul.config.Core.FieldEncryptor.apply(fields)
+	}
+	return fields
 }
 
-func (ul *unifiedLogger) logCommonLog(level Level, message string, ctx context.Context) {
-	entry := LogEntry{
-		Level:     level,
-		Message:   message,
-		Timestamp: time.Now(),
-		Fields:    ul.buildCommonLogFields(),
-		Context:   ctx,
+// mergeFields merges static and instance fields, with instance fields
+// taking precedence.
+func (ul *unifiedLogger) mergeFields() map[string]interface{} {
+	if len(ul.config.Core.StaticFields) == 0 {
+		return ul.fields
 	}
-
-	formatter := NewCommonLogFormatter(ul.config.Formatter)
-	output, err := formatter.Format(entry)
-	if err != nil {
-		return
+	if len(ul.fields) == 0 {
+		return ul.config.Core.StaticFields
 	}
 
-	fmt.Fprint(ul.config.Output.Writer, string(output))
-}
-
-func (ul *unifiedLogger) buildCommonLogFields() map[string]interface{} {
-	fields := make(map[string]interface{})
+	fields := make(map[string]interface{}, len(ul.config.Core.StaticFields)+len(ul.fields))
 
 	for k, v := range ul.config.Core.StaticFields {
 		fields[k] = v
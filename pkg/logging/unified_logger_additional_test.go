@@ -3,7 +3,9 @@ package logging
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"log/slog"
+	"strings"
 	"testing"
 )
 
@@ -254,3 +256,141 @@ func TestUnifiedLogger_ThreadSafety(t *testing.T) {
 		t.Error("expected some output from concurrent logging")
 	}
 }
+
+func TestUnifiedLogger_TextIncludesFieldsAndCaller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	config := NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithWriter(buf).
+		WithTextFormat().
+		Build()
+	config.Formatter.IncludeTime = false
+	logger := NewUnifiedLogger(config, nil).WithField("user_id", 42)
+
+	ctx := WithTraceID(context.Background(), "trace-abc")
+	logger.InfoContext(ctx, "text message")
+
+	output := buf.String()
+	if !contains(output, "[INFO] unified_logger_additional_test.go:") {
+		t.Errorf("expected level and caller file in output, got: %s", output)
+	}
+	if !contains(output, "{user_id=42}") {
+		t.Errorf("expected fields in output, got: %s", output)
+	}
+	if !contains(output, "[trace_id=trace-abc]") {
+		t.Errorf("expected trace ID in output, got: %s", output)
+	}
+}
+
+type upperFormatter struct{}
+
+func (upperFormatter) Format(entry LogEntry) ([]byte, error) {
+	return []byte(strings.ToUpper(entry.Message) + "\n"), nil
+}
+
+func TestUnifiedLogger_CustomOutputAndFormatter(t *testing.T) {
+	first := &bytes.Buffer{}
+	second := &bytes.Buffer{}
+
+	config := NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithCustomFormatter(upperFormatter{}).
+		WithCustomOutput(NewMultiOutput(NewWriterOutput(first), NewWriterOutput(second))).
+		Build()
+	logger := NewWithLoggerConfig(config)
+
+	logger.Info("fan out")
+
+	for i, buf := range []*bytes.Buffer{first, second} {
+		if buf.String() != "FAN OUT\n" {
+			t.Errorf("output %d: expected formatted entry, got %q", i, buf.String())
+		}
+	}
+}
+
+func TestUnifiedLogger_AsyncOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	async := NewAsyncOutput(NewWriterOutput(buf), 10)
+
+	config := NewLoggerConfig().
+		WithLevel(InfoLevel).
+		WithJSONFormat().
+		WithCustomOutput(async).
+		Build()
+	logger := NewWithLoggerConfig(config)
+
+	logger.WithField("k", "v").Info("async entry")
+	if err := async.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON entry, got %q: %v", buf.String(), err)
+	}
+	if entry["message"] != "async entry" || entry["k"] != "v" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+}
+
+func TestUnifiedLogger_SatisfiesNarrowInterfaces(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithWriter(buf).WithJSONFormat().Build())
+
+	var info InfoLogger = logger
+	var errs ErrorLogger = logger
+	var ctxLogger ContextAwareLogger = logger
+
+	info.Info("info entry")
+	errs.Error("error entry")
+	ctxLogger.InfoContext(WithTraceID(context.Background(), "trace-1"), "context entry")
+
+	out := buf.String()
+	for _, want := range []string{"info entry", "error entry", `"trace_id":"trace-1"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output, got: %s", want, out)
+		}
+	}
+}
+
+func TestUnifiedLogger_WithLevelOverride(t *testing.T) {
+	buf := &bytes.Buffer{}
+	root := NewWithLoggerConfig(NewLoggerConfig().WithLevel(InfoLevel).WithWriter(buf).Build())
+
+	inherited := root.WithField("component", "api")
+	chatty := root.WithField("component", "cache").WithLevel(WarnLevel)
+	chattyChild := chatty.WithField("op", "get")
+
+	root.SetLevel(DebugLevel)
+
+	inherited.Debug("inherited debug")
+	chatty.Info("chatty info")
+	chattyChild.Warn("chatty child warn")
+
+	out := buf.String()
+	if !strings.Contains(out, "inherited debug") {
+		t.Error("expected child without override to follow root SetLevel")
+	}
+	if strings.Contains(out, "chatty info") {
+		t.Error("expected override to suppress INFO")
+	}
+	if !strings.Contains(out, "chatty child warn") {
+		t.Error("expected descendants of override to inherit its level")
+	}
+	if chatty.GetLevel() != WarnLevel || root.GetLevel() != DebugLevel {
+		t.Errorf("unexpected levels: chatty=%s root=%s", chatty.GetLevel(), root.GetLevel())
+	}
+}
+
+func TestUnifiedLogger_SetLevelWithSlog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewWithLoggerConfig(NewLoggerConfig().WithLevel(InfoLevel).WithWriter(buf).UseSlog(true).Build())
+
+	logger.Debug("hidden")
+	logger.SetLevel(DebugLevel)
+	logger.Debug("shown")
+
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Errorf("expected SetLevel to govern slog output, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestW3CExtendedFormatter_Format(t *testing.T) {
+	formatter := NewW3CExtendedFormatter(nil, W3CConfig{
+		Fields: []string{"date", "time", "c-ip", "cs-method", "cs-uri-stem", "sc-status", "cs(User-Agent)", "x-request", "request_id", "x-message"},
+	})
+	at := time.Date(2025, 11, 24, 5, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	entry := LogEntry{
+		Timestamp: at,
+		Level:     InfoLevel,
+		Message:   "Request completed\twith tab",
+		Context:   WithRequestID(context.Background(), "req-1"),
+		Fields: map[string]interface{}{
+			"remote_addr": "203.0.113.9:51234",
+			"method":      "GET",
+			"path":        `/files\report`,
+			"status":      200,
+			"user_agent":  "Mozilla/5.0 (X11; Linux)",
+		},
+	}
+
+	first, err := formatter.Format(entry)
+	if err != nil {
+		t.Fatalf("Format returned error: %v", err)
+	}
+	want := "#Software: go-logging\n" +
+		"#Version: 1.0\n" +
+		"#Date: 2025-11-24 10:30:00\n" +
+		"#Fields: date time c-ip cs-method cs-uri-stem sc-status cs(User-Agent) x-request request_id x-message\n" +
+		"2025-11-24\t10:30:00\t203.0.113.9\tGET\t/files\\\\report\t200\tMozilla/5.0 (X11; Linux)\t-\treq-1\tRequest completed\\twith tab\n"
+	if string(first) != want {
+		t.Errorf("unexpected first entry\n got: %q\nwant: %q", first, want)
+	}
+
+	second, _ := formatter.Format(entry)
+	if strings.HasPrefix(string(second), "#") {
+		t.Errorf("expected directives only before the first entry, got %q", second)
+	}
+
+	formatter.Reset()
+	third, _ := formatter.Format(entry)
+	if !strings.HasPrefix(string(third), "#Software: go-logging\n") {
+		t.Errorf("expected directives after Reset, got %q", third)
+	}
+}
+
+func TestW3CExtendedFormatter_Defaults(t *testing.T) {
+	formatter := NewW3CExtendedFormatter(nil, W3CConfig{})
+	if got := formatter.Directives(time.Time{}); !strings.HasSuffix(got, "#Fields: date time x-level x-message\n") {
+		t.Errorf("unexpected default directives %q", got)
+	}
+}